@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -86,6 +87,7 @@ type InstanceServer interface {
 	GetMetrics() (metrics string, err error)
 	GetServer() (server *api.Server, ETag string, err error)
 	GetServerResources() (resources *api.Resources, err error)
+	GetAuditRecords(args GetAuditRecordsArgs) (records []api.AuditRecord, err error)
 	UpdateServer(server api.ServerPut, ETag string) (err error)
 	HasExtension(extension string) (exists bool)
 	CheckExtension(extension string) (err error)
@@ -302,6 +304,7 @@ type InstanceServer interface {
 	CreateStoragePool(pool api.StoragePoolsPost) (op Operation, err error)
 	UpdateStoragePool(name string, pool api.StoragePoolPut, ETag string) (op Operation, err error)
 	DeleteStoragePool(name string) (op Operation, err error)
+	ScrubStoragePool(name string) (op Operation, err error)
 
 	// Storage bucket functions ("storage_buckets" API extension)
 	GetStoragePoolBucketNames(poolName string) ([]string, error)
@@ -318,6 +321,12 @@ type InstanceServer interface {
 	UpdateStoragePoolBucketKey(poolName string, bucketName string, keyName string, key api.StorageBucketKeyPut, ETag string) (op Operation, err error)
 	DeleteStoragePoolBucketKey(poolName string, bucketName string, keyName string) (op Operation, err error)
 
+	// Storage bucket object functions ("storage_bucket_objects" API extension)
+	GetStoragePoolBucketObjects(poolName string, bucketName string, prefix string) (objects []api.StorageBucketObject, err error)
+	GetStoragePoolBucketObject(poolName string, bucketName string, objectName string) (content io.ReadCloser, size int64, err error)
+	CreateStoragePoolBucketObject(poolName string, bucketName string, objectName string, content io.Reader, size int64) (err error)
+	DeleteStoragePoolBucketObject(poolName string, bucketName string, objectName string) (err error)
+
 	// List all volumes functions ("storage_volumes_all" API extension)
 	GetVolumesWithFilter(filters []string) (volumes []api.StorageVolume, err error)
 	GetVolumesWithFilterAllProjects(filters []string) (volumes []api.StorageVolume, err error)
@@ -331,6 +340,7 @@ type InstanceServer interface {
 	GetStoragePoolVolumesWithFilterAllProjects(pool string, filters []string) (volumes []api.StorageVolume, err error)
 	GetStoragePoolVolume(pool string, volType string, name string) (volume *api.StorageVolume, ETag string, err error)
 	GetStoragePoolVolumeState(pool string, volType string, name string) (state *api.StorageVolumeState, err error)
+	CheckStoragePoolVolume(pool string, volType string, name string) (op Operation, err error)
 	CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) (op Operation, err error)
 	UpdateStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePut, ETag string) (op Operation, err error)
 	RenameStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePost) (op Operation, err error)
@@ -347,6 +357,8 @@ type InstanceServer interface {
 	GetStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string) (snapshot *api.StorageVolumeSnapshot, ETag string, err error)
 	RenameStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, snapshot api.StorageVolumeSnapshotPost) (op Operation, err error)
 	UpdateStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, volume api.StorageVolumeSnapshotPut, ETag string) (op Operation, err error)
+	GetStoragePoolVolumeSnapshotDiff(pool string, volumeType string, volumeName string, snapshotName string, fromSnapshotName string) (diff *api.StorageVolumeSnapshotDiff, err error)
+	GetStoragePoolVolumeSnapshotDiffFile(pool string, volumeType string, volumeName string, snapshotName string, fromSnapshotName string, req *StorageVolumeSnapshotDiffFileRequest) (resp *StorageVolumeSnapshotDiffFileResponse, err error)
 
 	// Storage volume backup functions ("custom_volume_backup" API extension)
 	GetStoragePoolVolumeBackupNames(pool string, volName string) (names []string, err error)
@@ -376,6 +388,11 @@ type InstanceServer interface {
 	UpdateClusterCertificate(certs api.ClusterCertificatePut, ETag string) (err error)
 	GetClusterMemberState(name string) (*api.ClusterMemberState, string, error)
 	UpdateClusterMemberState(name string, state api.ClusterMemberStatePost) (op Operation, err error)
+	GetClusterInstanceRebalancePlan() (plan *api.ClusterRebalancePlan, err error)
+	RebalanceClusterInstances() (op Operation, err error)
+	GetClusterMaintenance() (maintenance *api.ClusterMaintenance, err error)
+	StartClusterMaintenance(maintenance api.ClusterMaintenancePost) (op Operation, err error)
+	UpdateClusterMaintenanceState(state api.ClusterMaintenanceStatePost) (err error)
 	GetClusterGroups() ([]api.ClusterGroup, error)
 	GetClusterGroupNames() ([]string, error)
 	RenameClusterGroup(name string, group api.ClusterGroupPost) error
@@ -404,6 +421,13 @@ type InstanceServer interface {
 	RunReplicator(project string, name string, req api.ReplicatorStatePut) (op Operation, err error)
 	RenameReplicator(project string, name string, replicator api.ReplicatorPost) (err error)
 
+	// Trash functions
+	GetTrashEntries() (entries []api.TrashEntry, err error)
+	GetTrashEntriesAllProjects() (entries []api.TrashEntry, err error)
+	GetTrashEntry(UUID string) (entry *api.TrashEntry, err error)
+	RestoreTrashEntry(UUID string, entry api.TrashEntryPost) (op Operation, err error)
+	DeleteTrashEntry(UUID string) (op Operation, err error)
+
 	// Warning functions
 	GetWarningUUIDs() (uuids []string, err error)
 	GetWarnings() (warnings []api.Warning, err error)
@@ -425,6 +449,8 @@ type InstanceServer interface {
 	GetIdentitiesByAuthenticationMethod(authenticationMethod string) (identities []api.Identity, err error)
 	GetIdentity(authenticationMethod string, nameOrIdentifier string) (identity *api.Identity, ETag string, err error)
 	GetCurrentIdentityInfo() (identityInfo *api.IdentityInfo, ETag string, err error)
+	GetCurrentIdentityCertificateNonce() (nonce *api.IdentityCertificateNonce, err error)
+	RenewCurrentIdentityCertificate(certificate api.IdentityCertificatePost) (err error)
 	UpdateIdentity(authenticationMethod string, nameOrIdentifier string, identityPut api.IdentityPut, ETag string) error
 	DeleteIdentity(authenticationMethod string, nameOrIdentifier string) error
 	CreateIdentityTLS(identitiesTLSPost api.IdentitiesTLSPost) error
@@ -441,6 +467,7 @@ type InstanceServer interface {
 	DeleteIdentityProviderGroup(identityProviderGroupName string) error
 	GetPermissions(args GetPermissionsArgs) (permissions []api.Permission, err error)
 	GetPermissionsInfo(args GetPermissionsArgs) (permissions []api.PermissionInfo, err error)
+	GetAuthCheck(identityURL string, entityURL string, entitlement string) (check *api.AuthCheck, err error)
 	GetOIDCSessionUUIDs() (uuids []string, err error)
 	GetOIDCSessionUUIDsByEmail(email string) (uuids []string, err error)
 	GetOIDCSessions() (sessions []api.OIDCSession, err error)
@@ -555,6 +582,24 @@ type BackupFileResponse struct {
 	Size int64
 }
 
+// The StorageVolumeSnapshotDiffFileRequest struct is used for a storage volume snapshot diff download request.
+type StorageVolumeSnapshotDiffFileRequest struct {
+	// Writer for the diff data
+	DiffFile io.Writer
+
+	// Progress handler (called whenever some progress is made)
+	ProgressHandler func(progress ioprogress.ProgressData)
+
+	// A canceler that can be used to interrupt some part of the download request
+	Canceler *cancel.HTTPRequestCanceller
+}
+
+// The StorageVolumeSnapshotDiffFileResponse struct is used as the response for storage volume snapshot diff downloads.
+type StorageVolumeSnapshotDiffFileResponse struct {
+	// Size of the diff data
+	Size int64
+}
+
 // The ImageCreateArgs struct is used for direct image upload.
 type ImageCreateArgs struct {
 	// Reader for the meta file
@@ -812,6 +857,22 @@ type InstanceFileResponse struct {
 	Entries []string
 }
 
+// GetAuditRecordsArgs is used in the call to GetAuditRecords to specify filtering behaviour.
+// Fields that are left unspecified do not filter the audit records.
+type GetAuditRecordsArgs struct {
+	// Identity is the username of the caller.
+	Identity string
+
+	// Entity is the URL of the entity targeted by the API call.
+	Entity string
+
+	// Since is the time from which to return audit records.
+	Since time.Time
+
+	// Until is the time until which to return audit records.
+	Until time.Time
+}
+
 // GetPermissionsArgs is used in the call to GetPermissions to specify filtering behaviour.
 type GetPermissionsArgs struct {
 	// EntityType is the type of entity to filter against.
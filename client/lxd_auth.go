@@ -247,6 +247,40 @@ func (r *ProtocolLXD) GetCurrentIdentityInfo() (*api.IdentityInfo, string, error
 	return &identityInfo, etag, nil
 }
 
+// GetCurrentIdentityCertificateNonce returns a nonce that must be signed with the private key of the new certificate
+// when renewing the TLS client certificate of the requestor.
+func (r *ProtocolLXD) GetCurrentIdentityCertificateNonce() (*api.IdentityCertificateNonce, error) {
+	err := r.CheckExtension("certificate_lifecycle")
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := api.IdentityCertificateNonce{}
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("auth", "identities", "current", "certificate").String(), nil, "", &nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nonce, nil
+}
+
+// RenewCurrentIdentityCertificate replaces the TLS client certificate of the requestor with the given certificate.
+// The request must be made with the certificate that is being replaced, and must contain a nonce from
+// [ProtocolLXD.GetCurrentIdentityCertificateNonce] signed with the private key of the new certificate.
+func (r *ProtocolLXD) RenewCurrentIdentityCertificate(certificate api.IdentityCertificatePost) error {
+	err := r.CheckExtension("certificate_lifecycle")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPost, api.NewURL().Path("auth", "identities", "current", "certificate").String(), certificate, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateIdentity replaces the editable fields of an identity with the given input.
 func (r *ProtocolLXD) UpdateIdentity(authenticationMethod string, nameOrIdentifer string, identityPut api.IdentityPut, ETag string) error {
 	err := r.CheckExtension("access_management")
@@ -334,6 +368,13 @@ func (r *ProtocolLXD) IssueBearerIdentityToken(nameOrIdentifier string, identity
 		return nil, err
 	}
 
+	if len(identityBearerTokenPost.Permissions) > 0 {
+		err := r.CheckExtension("auth_bearer_scoped_tokens")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var token api.IdentityBearerToken
 	_, err = r.queryStruct(http.MethodPost, api.NewURL().Path("auth", "identities", api.AuthenticationMethodBearer, nameOrIdentifier, "token").String(), identityBearerTokenPost, "", &token)
 	if err != nil {
@@ -518,6 +559,25 @@ func (r *ProtocolLXD) GetPermissionsInfo(args GetPermissionsArgs) ([]api.Permiss
 	return permissions, nil
 }
 
+// GetAuthCheck checks whether the identity with the given URL has the given entitlement on the entity with the given
+// URL, and explains why.
+func (r *ProtocolLXD) GetAuthCheck(identityURL string, entityURL string, entitlement string) (*api.AuthCheck, error) {
+	err := r.CheckExtension("auth_check")
+	if err != nil {
+		return nil, err
+	}
+
+	u := api.NewURL().Path("auth", "check").WithQuery("identity", identityURL).WithQuery("entity", entityURL).WithQuery("entitlement", entitlement)
+
+	var check api.AuthCheck
+	_, err = r.UseProject("").(*ProtocolLXD).queryStruct(http.MethodGet, u.String(), nil, "", &check)
+	if err != nil {
+		return nil, err
+	}
+
+	return &check, nil
+}
+
 // GetOIDCSessionUUIDs gets all OIDC session UUIDs.
 func (r *ProtocolLXD) GetOIDCSessionUUIDs() ([]string, error) {
 	err := r.CheckExtension("auth_oidc_sessions")
@@ -246,6 +246,85 @@ func (r *ProtocolLXD) UpdateClusterMemberState(name string, state api.ClusterMem
 	return op, nil
 }
 
+// GetClusterInstanceRebalancePlan returns the load of the cluster members and the instance moves that a
+// rebalancing run would perform.
+func (r *ProtocolLXD) GetClusterInstanceRebalancePlan() (*api.ClusterRebalancePlan, error) {
+	err := r.CheckExtension("cluster_instance_rebalance")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := api.ClusterRebalancePlan{}
+	_, err = r.queryStruct(http.MethodGet, "/cluster/instance-rebalance", nil, "", &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// RebalanceClusterInstances moves instances between cluster members to even out their load.
+func (r *ProtocolLXD) RebalanceClusterInstances() (Operation, error) {
+	err := r.CheckExtension("cluster_instance_rebalance")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, "/cluster/instance-rebalance", nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// GetClusterMaintenance returns the progress of the rolling cluster maintenance in progress.
+func (r *ProtocolLXD) GetClusterMaintenance() (*api.ClusterMaintenance, error) {
+	err := r.CheckExtension("cluster_maintenance")
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance := api.ClusterMaintenance{}
+	_, err = r.queryStruct(http.MethodGet, "/cluster/maintenance", nil, "", &maintenance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &maintenance, nil
+}
+
+// StartClusterMaintenance starts a rolling maintenance of the cluster members.
+func (r *ProtocolLXD) StartClusterMaintenance(maintenance api.ClusterMaintenancePost) (Operation, error) {
+	err := r.CheckExtension("cluster_maintenance")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, "/cluster/maintenance", maintenance, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// UpdateClusterMaintenanceState pauses, resumes or aborts the cluster maintenance in progress, or reports an
+// evacuated cluster member as ready to be restored.
+func (r *ProtocolLXD) UpdateClusterMaintenanceState(state api.ClusterMaintenanceStatePost) error {
+	err := r.CheckExtension("cluster_maintenance")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPost, "/cluster/maintenance/state", state, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetClusterGroups returns the cluster groups.
 func (r *ProtocolLXD) GetClusterGroups() ([]api.ClusterGroup, error) {
 	err := r.CheckExtension("clustering_groups")
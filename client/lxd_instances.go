@@ -296,6 +296,12 @@ type GetInstancesFullArgs struct {
 	// Examples: []string{"state.disk"}, []string{"state.network"}, []string{"state.disk", "state.network"}.
 	// If nil or empty, fetches all fields (default behavior).
 	Fields []string
+
+	// ClusterLink is the name of a cluster link to query instances from instead of the local cluster (requires cluster_link_views extension).
+	ClusterLink string
+
+	// AllLinks indicates whether to also query instances from all linked clusters (requires cluster_link_views extension).
+	AllLinks bool
 }
 
 // GetInstancesFull returns a list of instances including snapshots, backups and state.
@@ -330,6 +336,22 @@ func (r *ProtocolLXD) GetInstancesFull(args GetInstancesFullArgs) ([]api.Instanc
 		v.Set("all-projects", "true")
 	}
 
+	// Handle cluster links
+	if args.ClusterLink != "" || args.AllLinks {
+		err = r.CheckExtension("cluster_link_views")
+		if err != nil {
+			return nil, err
+		}
+
+		if args.ClusterLink != "" {
+			v.Set("cluster-link", args.ClusterLink)
+		}
+
+		if args.AllLinks {
+			v.Set("all-links", "true")
+		}
+	}
+
 	// Handle filters
 	if len(args.Filters) > 0 {
 		err = r.CheckExtension("api_filtering")
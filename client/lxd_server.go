@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"slices"
+	"time"
 
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -98,6 +99,40 @@ func (r *ProtocolLXD) GetServerResources() (*api.Resources, error) {
 	return &resources, nil
 }
 
+// GetAuditRecords returns the records of the audit log of the server that match the given filter.
+// In a cluster, use [ProtocolLXD.UseTarget] to get the audit log of a specific cluster member.
+func (r *ProtocolLXD) GetAuditRecords(args GetAuditRecordsArgs) ([]api.AuditRecord, error) {
+	err := r.CheckExtension("audit_log")
+	if err != nil {
+		return nil, err
+	}
+
+	u := api.NewURL().Path("audit")
+	if args.Identity != "" {
+		u = u.WithQuery("identity", args.Identity)
+	}
+
+	if args.Entity != "" {
+		u = u.WithQuery("entity", args.Entity)
+	}
+
+	if !args.Since.IsZero() {
+		u = u.WithQuery("since", args.Since.Format(time.RFC3339))
+	}
+
+	if !args.Until.IsZero() {
+		u = u.WithQuery("until", args.Until.Format(time.RFC3339))
+	}
+
+	records := []api.AuditRecord{}
+	_, err = r.UseProject("").(*ProtocolLXD).queryStruct(http.MethodGet, u.String(), nil, "", &records)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
 // UseProject returns a client that will use a specific project.
 func (r *ProtocolLXD) UseProject(name string) InstanceServer {
 	server := *r
@@ -1,6 +1,7 @@
 package lxd
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/canonical/lxd/shared/api"
@@ -328,3 +329,119 @@ func (r *ProtocolLXD) DeleteStoragePoolBucketKey(poolName string, bucketName str
 
 	return op, nil
 }
+
+// GetStoragePoolBucketObjects returns the objects of a storage bucket whose name starts with prefix.
+func (r *ProtocolLXD) GetStoragePoolBucketObjects(poolName string, bucketName string, prefix string) ([]api.StorageBucketObject, error) {
+	err := r.CheckExtension("storage_bucket_objects")
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []api.StorageBucketObject{}
+
+	// Fetch the raw value.
+	u := api.NewURL().Path("storage-pools", poolName, "buckets", bucketName, "objects").WithQuery("recursion", "1")
+	if prefix != "" {
+		u = u.WithQuery("prefix", prefix)
+	}
+
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &objects)
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// GetStoragePoolBucketObject returns the content and size of a storage bucket object.
+// The caller is responsible for closing the returned reader.
+func (r *ProtocolLXD) GetStoragePoolBucketObject(poolName string, bucketName string, objectName string) (io.ReadCloser, int64, error) {
+	err := r.CheckExtension("storage_bucket_objects")
+	if err != nil {
+		return nil, -1, err
+	}
+
+	u := api.NewURL().Path("storage-pools", poolName, "buckets", bucketName, "objects", objectName)
+	requestURL, err := r.setQueryAttributes(r.httpBaseURL.String() + "/1.0" + u.String())
+	if err != nil {
+		return nil, -1, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, -1, err
+		}
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// CreateStoragePoolBucketObject stores the content in a storage bucket object, replacing any existing content.
+// The size of the content must be known in advance.
+func (r *ProtocolLXD) CreateStoragePoolBucketObject(poolName string, bucketName string, objectName string, content io.Reader, size int64) error {
+	err := r.CheckExtension("storage_bucket_objects")
+	if err != nil {
+		return err
+	}
+
+	u := api.NewURL().Path("storage-pools", poolName, "buckets", bucketName, "objects", objectName)
+	requestURL, err := r.setQueryAttributes(r.httpBaseURL.String() + "/1.0" + u.String())
+	if err != nil {
+		return err
+	}
+
+	if size == 0 {
+		content = http.NoBody
+	}
+
+	req, err := http.NewRequest(http.MethodPut, requestURL, content)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+
+	// Check the return value for a cleaner error
+	_, _, err = lxdParseResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteStoragePoolBucketObject deletes a storage bucket object.
+func (r *ProtocolLXD) DeleteStoragePoolBucketObject(poolName string, bucketName string, objectName string) error {
+	err := r.CheckExtension("storage_bucket_objects")
+	if err != nil {
+		return err
+	}
+
+	u := api.NewURL().Path("storage-pools", poolName, "buckets", bucketName, "objects", objectName)
+	_, _, err = r.query(http.MethodDelete, u.String(), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
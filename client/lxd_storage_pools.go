@@ -174,3 +174,19 @@ func (r *ProtocolLXD) GetStoragePoolResources(name string) (*api.ResourcesStorag
 
 	return &res, nil
 }
+
+// ScrubStoragePool verifies the integrity of a storage pool and checks all its volumes.
+func (r *ProtocolLXD) ScrubStoragePool(name string) (Operation, error) {
+	err := r.CheckExtension("storage_volume_check")
+	if err != nil {
+		return nil, err
+	}
+
+	path := api.NewURL().Path("storage-pools", name, "scrub")
+	op, _, err := r.queryOperation(http.MethodPost, path.String(), nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -248,6 +248,23 @@ func (r *ProtocolLXD) GetStoragePoolVolumeState(pool string, volType string, nam
 	return &state, nil
 }
 
+// CheckStoragePoolVolume verifies the integrity of a storage volume.
+// The result is recorded in the volume state.
+func (r *ProtocolLXD) CheckStoragePoolVolume(pool string, volType string, name string) (Operation, error) {
+	err := r.CheckExtension("storage_volume_check")
+	if err != nil {
+		return nil, err
+	}
+
+	path := api.NewURL().Path("storage-pools", pool, "volumes", volType, name, "check")
+	op, _, err := r.queryOperation(http.MethodPost, path.String(), nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // CreateStoragePoolVolume defines a new storage volume.
 func (r *ProtocolLXD) CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) (Operation, error) {
 	err := r.CheckExtension("storage")
@@ -349,6 +366,75 @@ func (r *ProtocolLXD) GetStoragePoolVolumeSnapshot(pool string, volumeType strin
 	return &snapshot, etag, nil
 }
 
+// GetStoragePoolVolumeSnapshotDiff returns the changes between an older snapshot and the given snapshot.
+func (r *ProtocolLXD) GetStoragePoolVolumeSnapshotDiff(pool string, volumeType string, volumeName string, snapshotName string, fromSnapshotName string) (*api.StorageVolumeSnapshotDiff, error) {
+	err := r.CheckExtension("storage_volume_snapshot_diff")
+	if err != nil {
+		return nil, err
+	}
+
+	diff := api.StorageVolumeSnapshotDiff{}
+
+	u := api.NewURL().Path("storage-pools", pool, "volumes", volumeType, volumeName, "snapshots", snapshotName, "diff").WithQuery("from", fromSnapshotName)
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &diff)
+	if err != nil {
+		return nil, err
+	}
+
+	return &diff, nil
+}
+
+// GetStoragePoolVolumeSnapshotDiffFile requests the data that changed between an older snapshot and the given snapshot.
+func (r *ProtocolLXD) GetStoragePoolVolumeSnapshotDiffFile(pool string, volumeType string, volumeName string, snapshotName string, fromSnapshotName string, req *StorageVolumeSnapshotDiffFileRequest) (*StorageVolumeSnapshotDiffFileResponse, error) {
+	err := r.CheckExtension("storage_volume_snapshot_diff")
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the URL
+	uri := r.httpBaseURL.String() + "/1.0/storage-pools/" + url.PathEscape(pool) + "/volumes/" + url.PathEscape(volumeType) + "/" + url.PathEscape(volumeName) + "/snapshots/" + url.PathEscape(snapshotName) + "/diff/export?from=" + url.QueryEscape(fromSnapshotName)
+
+	uri, err = r.setQueryAttributes(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the download request
+	request, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.httpUserAgent != "" {
+		request.Header.Set("User-Agent", r.httpUserAgent)
+	}
+
+	// Start the request
+	response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = response.Body.Close() }()
+	defer close(doneCh)
+
+	if response.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(response)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle the data
+	body := ioprogress.NewProgressReader(response.Body, ioprogress.WithLength(response.ContentLength), ioprogress.WithProgressHandler(req.ProgressHandler))
+	size, err := io.Copy(req.DiffFile, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StorageVolumeSnapshotDiffFileResponse{Size: size}, nil
+}
+
 // RenameStoragePoolVolumeSnapshot renames a storage volume snapshot.
 func (r *ProtocolLXD) RenameStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, snapshot api.StorageVolumeSnapshotPost) (Operation, error) {
 	err := r.CheckExtension("storage_api_volume_snapshots")
@@ -0,0 +1,91 @@
+package lxd
+
+import (
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Trash handling functions
+
+// GetTrashEntries returns the trash entries of the current project.
+func (r *ProtocolLXD) GetTrashEntries() ([]api.TrashEntry, error) {
+	err := r.CheckExtension("trash")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []api.TrashEntry{}
+
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("trash").WithQuery("recursion", "1").String(), nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetTrashEntriesAllProjects returns the trash entries of all projects.
+func (r *ProtocolLXD) GetTrashEntriesAllProjects() ([]api.TrashEntry, error) {
+	err := r.CheckExtension("trash")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []api.TrashEntry{}
+
+	u := api.NewURL().Path("trash").WithQuery("recursion", "1").WithQuery("all-projects", "true")
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetTrashEntry returns the trash entry with the given UUID.
+func (r *ProtocolLXD) GetTrashEntry(UUID string) (*api.TrashEntry, error) {
+	err := r.CheckExtension("trash")
+	if err != nil {
+		return nil, err
+	}
+
+	entry := api.TrashEntry{}
+
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("trash", UUID).String(), nil, "", &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// RestoreTrashEntry restores the instance or custom volume held by the given trash entry.
+func (r *ProtocolLXD) RestoreTrashEntry(UUID string, entry api.TrashEntryPost) (Operation, error) {
+	err := r.CheckExtension("trash")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("trash", UUID).String(), entry, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteTrashEntry permanently deletes the instance or custom volume held by the given trash entry.
+func (r *ProtocolLXD) DeleteTrashEntry(UUID string) (Operation, error) {
+	err := r.CheckExtension("trash")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodDelete, api.NewURL().Path("trash", UUID).String(), nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -50,12 +50,79 @@ func (c *cmdAuth) command() *cobra.Command {
 	oidcSessionCmd := cmdOIDCSession{global: c.global}
 	cmd.AddCommand(oidcSessionCmd.command())
 
+	checkCmd := cmdAuthCheck{global: c.global}
+	cmd.AddCommand(checkCmd.command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
 	return cmd
 }
 
+type cmdAuthCheck struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuthCheck) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("check", "[<remote>:]<type>/<identity> <entity_type> [<entity_name>] <entitlement> [<key>=<value>...]")
+	cmd.Short = "Check whether an identity has an entitlement on an entity"
+	cmd.Long = cli.FormatSection("Description", `Check whether an identity has an entitlement on an entity
+
+This command explains the decision: which groups, identity provider group mappings,
+and permissions grant the entitlement, or why it is denied.
+The entity is specified in the same way as for "lxc auth group permission add".
+`)
+	cmd.Example = cli.FormatSection("", `lxc auth check oidc/jane.doe@example.com instance c1 can_exec project=default
+   Check whether OIDC identity "jane.doe@example.com" can execute commands in instance "c1" in project "default".`)
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuthCheck) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, -1)
+	if exit {
+		return err
+	}
+
+	remoteName, resourceName, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	method, _, nameOrID, err := resolveIdentityTypeShorthand(resourceName)
+	if err != nil {
+		return err
+	}
+
+	permission, err := parsePermissionArgs(args)
+	if err != nil {
+		return err
+	}
+
+	server, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	check, err := server.GetAuthCheck(entity.IdentityURL(method, nameOrID).String(), permission.EntityReference, permission.Entitlement)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&check)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
 type cmdGroup struct {
 	global *cmdGlobal
 }
@@ -754,6 +821,8 @@ func resolveIdentityTypeShorthand(identityArg string) (method string, identityTy
 		return api.AuthenticationMethodBearer, "", idName, nil
 	case "cluster-link":
 		return api.AuthenticationMethodTLS, api.IdentityTypeCertificateClusterLink, idName, nil
+	case "scim":
+		return api.AuthenticationMethodBearer, api.IdentityTypeBearerTokenSCIM, idName, nil
 	}
 
 	return "", "", "", fmt.Errorf("Unrecognized identity type shorthand %q", shorthandType)
@@ -1621,9 +1690,10 @@ func (c *cmdIdentityToken) command() *cobra.Command {
 }
 
 type cmdIdentityTokenIssue struct {
-	global     *cmdGlobal
-	identity   *cmdIdentity
-	flagExpiry string
+	global          *cmdGlobal
+	identity        *cmdIdentity
+	flagExpiry      string
+	flagPermissions []string
 }
 
 func (c *cmdIdentityTokenIssue) command() *cobra.Command {
@@ -1632,9 +1702,15 @@ func (c *cmdIdentityTokenIssue) command() *cobra.Command {
 	cmd.Short = "Issue a token for a bearer identity"
 	cmd.Long = cli.FormatSection("Description", cmd.Short+`
 
-Note that this revokes the current token if one is issued`)
+Note that this revokes the current token if one is issued
+
+The token can be narrowed to a subset of the permissions of the identity with one or more --permission flags.
+Each permission has the same form as the arguments of "lxc auth group permission add", without the group name`)
+	cmd.Example = cli.FormatSection("", `lxc auth identity token issue bearer/ci --expiry 1H --permission "instance c1 can_exec project=default"
+   Issue a token for bearer identity "ci" that is valid for an hour and may only be used to execute commands in instance "c1".`)
 
 	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "", `Token expiration as a space separated list of durations in the form (\d)+(S|M|H|d|w|m|y)`)
+	cmd.Flags().StringArrayVar(&c.flagPermissions, "permission", nil, cli.FormatStringFlagLabel("Permission to narrow the token to, in the form \"<entity_type> [<entity_name>] <entitlement> [<key>=<value>...]\""))
 	cmd.RunE = c.run
 
 	return cmd
@@ -1669,7 +1745,23 @@ func (c *cmdIdentityTokenIssue) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Expected identity of type %q but found identity with type %q", idType, identity.Type)
 	}
 
-	token, err := server.IssueBearerIdentityToken(name, api.IdentityBearerTokenPost{Expiry: c.flagExpiry})
+	permissions := make([]api.Permission, 0, len(c.flagPermissions))
+	for _, flagPermission := range c.flagPermissions {
+		fields := strings.Fields(flagPermission)
+		if len(fields) < 2 {
+			return fmt.Errorf("Invalid permission %q: Expected an entity type and an entitlement", flagPermission)
+		}
+
+		// Reuse the group permission argument parsing, where the first argument is the group.
+		permission, err := parsePermissionArgs(append([]string{""}, fields...))
+		if err != nil {
+			return fmt.Errorf("Invalid permission %q: %w", flagPermission, err)
+		}
+
+		permissions = append(permissions, *permission)
+	}
+
+	token, err := server.IssueBearerIdentityToken(name, api.IdentityBearerTokenPost{Expiry: c.flagExpiry, Permissions: permissions})
 	if err != nil {
 		return err
 	}
@@ -94,6 +94,14 @@ func (c *cmdCluster) command() *cobra.Command {
 	cmdClusterRestore := cmdClusterRestore{global: c.global, cluster: c}
 	cmd.AddCommand(cmdClusterRestore.command())
 
+	// Rebalance instances
+	cmdClusterRebalance := cmdClusterRebalance{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterRebalance.command())
+
+	// Rolling maintenance
+	cmdClusterMaintenance := cmdClusterMaintenance{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterMaintenance.command())
+
 	clusterGroupCmd := cmdClusterGroup{global: c.global, cluster: c}
 	cmd.AddCommand(clusterGroupCmd.command())
 
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	yaml "go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdClusterMaintenance struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+}
+
+func (c *cmdClusterMaintenance) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("maintenance")
+	cmd.Short = "Manage rolling cluster maintenance"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+A rolling maintenance evacuates the cluster members one after the other. Each evacuated
+member waits to be reported ready (or for the ready timeout) before being restored and
+checked for health, then the maintenance moves to the next member.
+
+Failures pause the maintenance. Resuming it retries the failed step.`)
+
+	// Start
+	clusterMaintenanceStartCmd := cmdClusterMaintenanceStart{global: c.global}
+	cmd.AddCommand(clusterMaintenanceStartCmd.command())
+
+	// Show
+	clusterMaintenanceShowCmd := cmdClusterMaintenanceShow{global: c.global}
+	cmd.AddCommand(clusterMaintenanceShowCmd.command())
+
+	// Pause
+	clusterMaintenancePauseCmd := cmdClusterMaintenanceAction{global: c.global, action: api.ClusterMaintenanceActionPause}
+	cmd.AddCommand(clusterMaintenancePauseCmd.command())
+
+	// Resume
+	clusterMaintenanceResumeCmd := cmdClusterMaintenanceAction{global: c.global, action: api.ClusterMaintenanceActionResume}
+	cmd.AddCommand(clusterMaintenanceResumeCmd.command())
+
+	// Abort
+	clusterMaintenanceAbortCmd := cmdClusterMaintenanceAction{global: c.global, action: api.ClusterMaintenanceActionAbort}
+	cmd.AddCommand(clusterMaintenanceAbortCmd.command())
+
+	// Ready
+	clusterMaintenanceReadyCmd := cmdClusterMaintenanceReady{global: c.global}
+	cmd.AddCommand(clusterMaintenanceReadyCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Start.
+type cmdClusterMaintenanceStart struct {
+	global *cmdGlobal
+
+	flagAll          bool
+	flagAction       string
+	flagReadyTimeout int64
+}
+
+func (c *cmdClusterMaintenanceStart) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("start", "[<remote>:]<member> [<member>...]")
+	cmd.Short = "Start a rolling maintenance of cluster members"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The members go through maintenance in the given order, with the member coordinating
+the maintenance last. Use --all to go through all cluster members.`)
+	cmd.Example = cli.FormatSection("", `lxc cluster maintenance start --all --ready-timeout 1800
+    Evacuate and restore all cluster members in turn, waiting up to 30 minutes for each one to be reported ready.
+
+lxc cluster maintenance start server01 server02 --action live-migrate
+    Evacuate and restore server01 then server02, live-migrating their instances.`)
+
+	cmd.Flags().BoolVar(&c.flagAll, "all", false, "Go through all cluster members")
+	cmd.Flags().StringVar(&c.flagAction, "action", "", cli.FormatStringFlagLabel("Force a particular instance evacuation action. One of stop, migrate or live-migrate"))
+	cmd.Flags().Int64Var(&c.flagReadyTimeout, "ready-timeout", 0, cli.FormatStringFlagLabel("How long to wait for an evacuated member to be reported ready before restoring it (in seconds)"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("cluster_member", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterMaintenanceStart) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	minArgs := 1
+	if c.flagAll {
+		minArgs = 0
+	}
+
+	exit, err := c.global.CheckArgs(cmd, args, minArgs, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	members := []string{}
+	if resource.name != "" {
+		members = append(members, resource.name)
+	}
+
+	if len(args) > 1 {
+		members = append(members, args[1:]...)
+	}
+
+	if c.flagAll && len(members) > 0 {
+		return errors.New("Cluster members cannot be listed with --all")
+	}
+
+	if !c.flagAll && len(members) == 0 {
+		return errors.New("Missing cluster member name")
+	}
+
+	req := api.ClusterMaintenancePost{
+		Members:      members,
+		Mode:         c.flagAction,
+		ReadyTimeout: c.flagReadyTimeout,
+	}
+
+	op, err := resource.server.StartClusterMaintenance(req)
+	if err != nil {
+		return fmt.Errorf("Failed starting cluster maintenance: %w", err)
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: "Cluster maintenance: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	// The cluster member coordinating the maintenance hands it over before going through maintenance itself.
+	opAPI := op.Get()
+	maintenance, ok := opAPI.Metadata["maintenance"].(map[string]any)
+	if ok && maintenance["status"] == api.ClusterMaintenanceStatusHandedOver && !c.global.flagQuiet {
+		fmt.Printf("Cluster maintenance handed over to %q, use \"lxc cluster maintenance show\" to follow its progress\n", maintenance["location"])
+	}
+
+	return nil
+}
+
+// Show.
+type cmdClusterMaintenanceShow struct {
+	global *cmdGlobal
+}
+
+func (c *cmdClusterMaintenanceShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", "[<remote>:]")
+	cmd.Short = "Show the progress of the cluster maintenance"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", false, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterMaintenanceShow) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name != "" {
+		return errors.New("Cluster member name isn't supported by this command")
+	}
+
+	maintenance, err := resource.server.GetClusterMaintenance()
+	if err != nil {
+		return err
+	}
+
+	// Render as YAML
+	data, err := yaml.Marshal(maintenance)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+	return nil
+}
+
+// Pause, resume and abort.
+type cmdClusterMaintenanceAction struct {
+	global *cmdGlobal
+	action string
+}
+
+func (c *cmdClusterMaintenanceAction) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage(c.action, "[<remote>:]")
+
+	switch c.action {
+	case api.ClusterMaintenanceActionPause:
+		cmd.Short = "Pause the cluster maintenance"
+		cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The maintenance is paused once the current step completes.`)
+	case api.ClusterMaintenanceActionResume:
+		cmd.Short = "Resume the cluster maintenance"
+		cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+If the maintenance was paused after a failure, the failed step is retried.`)
+	case api.ClusterMaintenanceActionAbort:
+		cmd.Short = "Abort the cluster maintenance"
+		cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+Cluster members that are evacuated when the maintenance is aborted stay evacuated
+and need to be restored with "lxc cluster restore".`)
+	}
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", false, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterMaintenanceAction) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name != "" {
+		return errors.New("Cluster member name isn't supported by this command")
+	}
+
+	return resource.server.UpdateClusterMaintenanceState(api.ClusterMaintenanceStatePost{Action: c.action})
+}
+
+// Ready.
+type cmdClusterMaintenanceReady struct {
+	global *cmdGlobal
+}
+
+func (c *cmdClusterMaintenanceReady) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("ready", "[<remote>:]<member>")
+	cmd.Short = "Report an evacuated cluster member as ready to be restored"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("cluster_member", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterMaintenanceReady) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing cluster member name")
+	}
+
+	return resource.server.UpdateClusterMaintenanceState(api.ClusterMaintenanceStatePost{Action: api.ClusterMaintenanceActionReady, Member: resource.name})
+}
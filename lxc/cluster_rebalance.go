@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdClusterRebalance struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+
+	flagDryRun bool
+	flagFormat string
+}
+
+func (c *cmdClusterRebalance) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rebalance", "[<remote>:]")
+	cmd.Short = "Rebalance instances across cluster members"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+Instances with "cluster.rebalance" set to true are moved from the most loaded
+cluster members to the least loaded ones, based on CPU, memory and storage usage.
+
+Use --dry-run to only show the load of the cluster members and the planned moves.`)
+	cmd.Example = cli.FormatSection("", `lxc cluster rebalance --dry-run
+    Show the instance moves the rebalancer would perform.
+
+lxc cluster rebalance
+    Rebalance instances across cluster members now.`)
+
+	cmd.Flags().BoolVar(&c.flagDryRun, "dry-run", false, "Only show the rebalancing plan")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", false, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterRebalance) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name != "" {
+		return errors.New("Cluster member name isn't supported by this command")
+	}
+
+	if c.flagDryRun {
+		plan, err := resource.server.GetClusterInstanceRebalancePlan()
+		if err != nil {
+			return err
+		}
+
+		return c.renderPlan(plan)
+	}
+
+	op, err := resource.server.RebalanceClusterInstances()
+	if err != nil {
+		return fmt.Errorf("Failed rebalancing cluster instances: %w", err)
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: "Rebalancing instances: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+	return nil
+}
+
+// renderPlan shows the member loads followed by the planned moves.
+// Structured formats get the whole plan in a single document.
+func (c *cmdClusterRebalance) renderPlan(plan *api.ClusterRebalancePlan) error {
+	if c.flagFormat == cli.TableFormatJSON || c.flagFormat == cli.TableFormatYAML {
+		return cli.RenderTable(c.flagFormat, nil, nil, plan)
+	}
+
+	formatPercent := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 1, 64) + "%"
+	}
+
+	members := [][]string{}
+	for _, member := range plan.Members {
+		members = append(members, []string{member.Name, formatPercent(member.Score), formatPercent(member.CPU), formatPercent(member.Memory), formatPercent(member.Storage), strconv.Itoa(member.Instances)})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(members))
+
+	err := cli.RenderTable(c.flagFormat, []string{"MEMBER", "SCORE", "CPU", "MEMORY", "STORAGE", "INSTANCES"}, members, plan.Members)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Moves) == 0 {
+		if c.flagFormat != cli.TableFormatCSV && !c.global.flagQuiet {
+			fmt.Println("No instance moves needed")
+		}
+
+		return nil
+	}
+
+	moves := [][]string{}
+	for _, move := range plan.Moves {
+		moves = append(moves, []string{move.Instance, move.Project, move.Source, move.Target, strconv.FormatBool(move.Live)})
+	}
+
+	if c.flagFormat != cli.TableFormatCSV {
+		fmt.Println()
+	}
+
+	return cli.RenderTable(c.flagFormat, []string{"INSTANCE", "PROJECT", "SOURCE", "TARGET", "LIVE"}, moves, plan.Moves)
+}
@@ -39,6 +39,8 @@ type cmdList struct {
 	flagFast        bool
 	flagFormat      string
 	flagAllProjects bool
+	flagClusterLink string
+	flagAllLinks    bool
 
 	shorthandFilters map[string]func(*api.Instance, *api.InstanceState, string) bool
 }
@@ -133,6 +135,8 @@ lxc list -c ns,user.comment:comment
 	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
 	cmd.Flags().BoolVar(&c.flagFast, "fast", false, "Fast mode (same as --columns=nsacPt)")
 	cmd.Flags().BoolVar(&c.flagAllProjects, "all-projects", false, "Display instances from all projects")
+	cmd.Flags().StringVar(&c.flagClusterLink, "cluster-link", "", cli.FormatStringFlagLabel("Display instances from a linked cluster"))
+	cmd.Flags().BoolVar(&c.flagAllLinks, "all-links", false, "Display instances from the cluster and all linked clusters")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -453,6 +457,13 @@ func (c *cmdList) run(cmd *cobra.Command, args []string) error {
 		return errors.New("Cannot specify --project with --all-projects")
 	}
 
+	if c.flagClusterLink != "" && c.flagAllLinks {
+		return errors.New("Cannot specify --cluster-link with --all-links")
+	}
+
+	// Instances of linked clusters are always listed with their location.
+	linkedClusters := c.flagClusterLink != "" || c.flagAllLinks
+
 	// Parse the remote
 	var remote string
 	var name string
@@ -489,14 +500,14 @@ func (c *cmdList) run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get the list of columns
-	columns, needsData, err := c.parseColumns(d.IsClustered())
+	columns, needsData, err := c.parseColumns(d.IsClustered() || linkedClusters)
 	if err != nil {
 		return err
 	}
 
 	// The ipv4 and ipv6 filters are applied client side against the instance's network state.
 	filtersNeedNetwork := c.filtersNeedNetwork(filters)
-	if filtersNeedNetwork {
+	if filtersNeedNetwork || linkedClusters {
 		needsData = true
 	}
 
@@ -535,6 +546,8 @@ func (c *cmdList) run(cmd *cobra.Command, args []string) error {
 			Filters:      serverFilters,
 			AllProjects:  c.flagAllProjects,
 			Fields:       recursionFields,
+			ClusterLink:  c.flagClusterLink,
+			AllLinks:     c.flagAllLinks,
 		})
 
 		if err != nil {
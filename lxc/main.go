@@ -288,6 +288,10 @@ For help with any of those, simply call them with --help.`)
 	versionCmd := cmdVersion{global: &globalCmd}
 	app.AddCommand(versionCmd.command())
 
+	// trash sub-command
+	trashCmd := cmdTrash{global: &globalCmd}
+	app.AddCommand(trashCmd.command())
+
 	// warning sub-command
 	warningCmd := cmdWarning{global: &globalCmd}
 	app.AddCommand(warningCmd.command())
@@ -27,6 +27,7 @@ type cmdMove struct {
 	flagTarget            string
 	flagTargetProject     string
 	flagAllowInconsistent bool
+	flagClusterLink       string
 }
 
 func (c *cmdMove) command() *cobra.Command {
@@ -46,6 +47,9 @@ The pull transfer mode is the default as it is compatible with all LXD versions.
 	cmd.Example = cli.FormatSection("", `lxc move [<remote>:]<source instance> [<remote>:][<destination instance>] [--instance-only]
     Move an instance between two hosts, renaming it if destination name differs.
 
+lxc move <instance> [<destination instance>] --cluster-link <cluster link> [--target-project <project>]
+    Move an instance to the cluster behind a cluster link, the server transfers it directly.
+
 lxc move <old name> <new name> [--instance-only]
     Rename a local instance.
 
@@ -64,6 +68,7 @@ lxc move <instance>/<old snapshot name> <instance>/<new snapshot name>
 	cmd.Flags().StringVar(&c.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
 	cmd.Flags().StringVar(&c.flagTargetProject, "target-project", "", cli.FormatStringFlagLabel("Copy to a project different from the source"))
 	cmd.Flags().BoolVar(&c.flagAllowInconsistent, "allow-inconsistent", false, "Ignore copy errors for volatile files")
+	cmd.Flags().StringVar(&c.flagClusterLink, "cluster-link", "", cli.FormatStringFlagLabel("Cluster link to move the instance to"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -89,7 +94,7 @@ func (c *cmdMove) run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
 
 	// Quick checks.
-	if c.flagTarget == "" && c.flagTargetProject == "" && c.flagStorage == "" {
+	if c.flagTarget == "" && c.flagTargetProject == "" && c.flagStorage == "" && c.flagClusterLink == "" {
 		exit, err := c.global.CheckArgs(cmd, args, 2, 2)
 		if exit {
 			return err
@@ -127,7 +132,7 @@ func (c *cmdMove) run(cmd *cobra.Command, args []string) error {
 	// running, instances that are running should be live migrated (of
 	// course, this changing of hostname isn't supported right now, so this
 	// simply won't work).
-	if sourceRemote == destRemote && c.flagTarget == "" && c.flagStorage == "" && c.flagTargetProject == "" {
+	if sourceRemote == destRemote && c.flagTarget == "" && c.flagStorage == "" && c.flagTargetProject == "" && c.flagClusterLink == "" {
 		if c.flagConfig != nil || c.flagDevice != nil || c.flagProfile != nil || c.flagNoProfiles {
 			return errors.New("Cannot override configuration or profiles in local rename")
 		}
@@ -176,6 +181,29 @@ func (c *cmdMove) run(cmd *cobra.Command, args []string) error {
 	stateful := !c.flagStateless
 
 	isServerSide, err := func() (bool, error) {
+		// Moves through a cluster link are always handled by the source server.
+		if c.flagClusterLink != "" {
+			if sourceRemote != destRemote {
+				return false, errors.New("Cannot specify a destination remote with --cluster-link")
+			}
+
+			if c.flagTarget != "" || c.flagMode != moveDefaultMode {
+				return false, errors.New("Cannot specify --target or --mode with --cluster-link")
+			}
+
+			source, err := conf.GetInstanceServer(sourceRemote)
+			if err != nil {
+				return false, err
+			}
+
+			err = source.CheckExtension("instance_move_cluster_link")
+			if err != nil {
+				return false, err
+			}
+
+			return true, nil
+		}
+
 		// Check if same source and destination.
 		if sourceRemote != destRemote {
 			return false, nil
@@ -306,6 +334,7 @@ func (c *cmdMove) moveInstance(sourceResource string, destResource string, state
 		Pool:         c.flagStorage,
 		Project:      c.flagTargetProject,
 		Live:         stateful,
+		ClusterLink:  c.flagClusterLink,
 	}
 
 	// Override profiles.
@@ -43,6 +43,14 @@ func (c *cmdReplicator) command() *cobra.Command {
 	replicatorEditCmd := cmdReplicatorEdit{global: c.global}
 	cmd.AddCommand(replicatorEditCmd.command())
 
+	// Failback.
+	replicatorFailbackCmd := cmdReplicatorFailback{global: c.global}
+	cmd.AddCommand(replicatorFailbackCmd.command())
+
+	// Failover.
+	replicatorFailoverCmd := cmdReplicatorFailover{global: c.global}
+	cmd.AddCommand(replicatorFailoverCmd.command())
+
 	// Get.
 	replicatorGetCmd := cmdReplicatorGet{global: c.global}
 	cmd.AddCommand(replicatorGetCmd.command())
@@ -71,6 +79,10 @@ func (c *cmdReplicator) command() *cobra.Command {
 	replicatorShowCmd := cmdReplicatorShow{global: c.global}
 	cmd.AddCommand(replicatorShowCmd.command())
 
+	// Test failover.
+	replicatorTestFailoverCmd := cmdReplicatorTestFailover{global: c.global}
+	cmd.AddCommand(replicatorTestFailoverCmd.command())
+
 	// Unset.
 	replicatorUnsetCmd := cmdReplicatorUnset{global: c.global, replicatorSet: &replicatorSetCmd}
 	cmd.AddCommand(replicatorUnsetCmd.command())
@@ -457,6 +469,149 @@ func (c *cmdReplicatorRun) run(cmd *cobra.Command, args []string) error {
 	return op.Wait()
 }
 
+// replicatorFailoverAction runs a failover related action of a replicator and waits for it to complete.
+func replicatorFailoverAction(global *cmdGlobal, cmd *cobra.Command, args []string, action string) error {
+	// Quick checks.
+	exit, err := global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing replicator name")
+	}
+
+	if !resource.server.HasExtension("replicator_failover") {
+		return errors.New("The server does not support replicator failovers")
+	}
+
+	op, err := resource.server.RunReplicator(global.flagProject, resource.name, api.ReplicatorStatePut{Action: action})
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// Failover.
+type cmdReplicatorFailover struct {
+	global *cmdGlobal
+}
+
+func (c *cmdReplicatorFailover) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("failover", "[<remote>:]<replicator>")
+	cmd.Short = "Fail over to the standby project of a replicator"
+	cmd.Long = cli.FormatSection("Description", `Fail over to the standby project of a replicator
+
+Run on the standby cluster. If the leader cluster is reachable, its instances are stopped
+and synced to the standby project, and its project is demoted. The local project is then
+promoted and its instances are started in boot order. From then on, the replicator
+replicates from the local project to the former leader.`)
+	cmd.Example = cli.FormatSection("", `lxc replicator failover my-replicator --project my-project
+    Make "my-project" on this cluster the leader of the replication.`)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return replicatorFailoverAction(c.global, cmd, args, "failover")
+	}
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("replicator", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Failback.
+type cmdReplicatorFailback struct {
+	global *cmdGlobal
+}
+
+func (c *cmdReplicatorFailback) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("failback", "[<remote>:]<replicator>")
+	cmd.Short = "Hand the leadership of a replication back to the target cluster"
+	cmd.Long = cli.FormatSection("Description", `Hand the leadership of a replication back to the target cluster
+
+Run on the leader cluster, typically after a failover. The local instances are stopped and
+synced to the target cluster, the local project is demoted and the project on the target
+cluster is promoted. The instances on the target cluster are then started in boot order.`)
+	cmd.Example = cli.FormatSection("", `lxc replicator failback my-replicator --project my-project
+    Make "my-project" on the target cluster of "my-replicator" the leader of the replication again.`)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return replicatorFailoverAction(c.global, cmd, args, "failback")
+	}
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("replicator", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Test failover.
+type cmdReplicatorTestFailover struct {
+	global *cmdGlobal
+
+	flagCleanup bool
+}
+
+func (c *cmdReplicatorTestFailover) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("test-failover", "[<remote>:]<replicator>")
+	cmd.Short = "Rehearse the failover of a replicator"
+	cmd.Long = cli.FormatSection("Description", `Rehearse the failover of a replicator
+
+Run on the standby cluster. The replicated instances are cloned into an isolated project
+(see the test_failover.project replicator configuration key) and started in boot order.
+The clones have no network interfaces, proxies or custom volumes. The replicas and the
+leader cluster are left untouched.
+
+Use --cleanup to delete the test failover project once done.`)
+	cmd.Example = cli.FormatSection("", `lxc replicator test-failover my-replicator --project my-project
+    Start clones of the replicas of "my-project" in the "my-project-test-failover" project.
+
+lxc replicator test-failover my-replicator --project my-project --cleanup
+    Delete the "my-project-test-failover" project and its instances.`)
+	cmd.Flags().BoolVar(&c.flagCleanup, "cleanup", false, "Delete the test failover project")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		action := "test-failover"
+		if c.flagCleanup {
+			action = "test-failover-cleanup"
+		}
+
+		return replicatorFailoverAction(c.global, cmd, args, action)
+	}
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("replicator", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
 // Set.
 type cmdReplicatorSet struct {
 	global *cmdGlobal
@@ -864,10 +1019,27 @@ func (c *cmdReplicatorInfo) run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Schedule: %s\n", schedule)
 	}
 
+	if shared.IsTrue(replicator.Config["continuous"]) {
+		fmt.Println("Continuous: yes")
+	}
+
 	if shared.TimeIsSet(replicator.LastRunAt) {
 		fmt.Printf("Last run: %s\n", replicator.LastRunAt.Local().Format(layout))
 	}
 
+	if state.Instances != nil {
+		fmt.Printf("Lag: %s\n", time.Duration(state.Lag)*time.Second)
+	}
+
+	if replicator.Config["rpo"] != "" {
+		rpoStatus := "met"
+		if state.RPOBreached {
+			rpoStatus = "breached"
+		}
+
+		fmt.Printf("RPO: %s (%s)\n", replicator.Config["rpo"], rpoStatus)
+	}
+
 	if schedule != "" {
 		now := time.Now()
 		var nextRun time.Time
@@ -892,10 +1064,32 @@ func (c *cmdReplicatorInfo) run(cmd *cobra.Command, args []string) error {
 	fmt.Println("Instances:")
 	instanceData := make([][]string, 0, len(instanceNames))
 	for _, name := range instanceNames {
-		instanceData = append(instanceData, []string{name})
+		// Servers without the replicator_continuous extension don't report per-instance state.
+		if state.Instances == nil {
+			instanceData = append(instanceData, []string{name})
+			continue
+		}
+
+		lastReplicated := ""
+		lag := ""
+		instState, ok := state.Instances[name]
+		if ok {
+			if shared.TimeIsSet(instState.LastReplicatedAt) {
+				lastReplicated = instState.LastReplicatedAt.Local().Format(layout)
+			}
+
+			lag = (time.Duration(instState.Lag) * time.Second).String()
+		}
+
+		instanceData = append(instanceData, []string{name, lastReplicated, lag})
+	}
+
+	header := []string{"NAME"}
+	if state.Instances != nil {
+		header = append(header, "LAST REPLICATED", "LAG")
 	}
 
-	err = cli.RenderTable(cli.TableFormatTable, []string{"NAME"}, instanceData, instanceNames)
+	err = cli.RenderTable(cli.TableFormatTable, header, instanceData, instanceNames)
 	if err != nil {
 		return err
 	}
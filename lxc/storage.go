@@ -58,6 +58,10 @@ func (c *cmdStorage) command() *cobra.Command {
 	storageListCmd := cmdStorageList{global: c.global, storage: c}
 	cmd.AddCommand(storageListCmd.command())
 
+	// Scrub
+	storageScrubCmd := cmdStorageScrub{global: c.global, storage: c}
+	cmd.AddCommand(storageScrubCmd.command())
+
 	// Set
 	storageSetCmd := cmdStorageSet{global: c.global, storage: c}
 	cmd.AddCommand(storageSetCmd.command())
@@ -775,6 +779,78 @@ func (c *cmdStorageList) stateColumnData(pool api.StoragePool) string {
 	return strings.ToUpper(pool.Status)
 }
 
+// Scrub.
+type cmdStorageScrub struct {
+	global  *cmdGlobal
+	storage *cmdStorage
+}
+
+func (c *cmdStorageScrub) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("scrub", "[<remote>:]<pool>")
+	cmd.Short = "Check the integrity of storage pools"
+	cmd.Long = cli.FormatSection("Description", `Check the integrity of storage pools
+
+The pool is scrubbed if supported by the storage driver, then all its volumes which aren't in use are checked.`)
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("storage_pool", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageScrub) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing pool name")
+	}
+
+	client := resource.server
+
+	// If a target was specified, scrub the pool on the given member.
+	if c.storage.flagTarget != "" {
+		client = client.UseTarget(c.storage.flagTarget)
+	}
+
+	// Scrub the pool
+	op, err := client.ScrubStoragePool(resource.name)
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Storage pool %s scrubbed\n", resource.name)
+	}
+
+	return nil
+}
+
 // Set.
 type cmdStorageSet struct {
 	global  *cmdGlobal
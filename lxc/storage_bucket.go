@@ -67,6 +67,10 @@ func (c *cmdStorageBucket) command() *cobra.Command {
 	storageBucketKeyCmd := cmdStorageBucketKey{global: c.global, storageBucket: c}
 	cmd.AddCommand(storageBucketKeyCmd.command())
 
+	// Object.
+	storageBucketObjectCmd := cmdStorageBucketObject{global: c.global, storageBucket: c}
+	cmd.AddCommand(storageBucketObjectCmd.command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
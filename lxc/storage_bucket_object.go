@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// Object commands.
+type cmdStorageBucketObject struct {
+	global        *cmdGlobal
+	storageBucket *cmdStorageBucket
+
+	flagTarget string
+}
+
+func (c *cmdStorageBucketObject) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("object")
+	cmd.Short = "Manage storage bucket objects"
+	cmd.Long = cli.FormatSection("Description", `Manage storage bucket objects
+
+Objects are transferred through LXD, so no S3 key is needed to access them.`)
+
+	// Delete.
+	storageBucketObjectDeleteCmd := cmdStorageBucketObjectDelete{global: c.global, storageBucketObject: c}
+	cmd.AddCommand(storageBucketObjectDeleteCmd.command())
+
+	// Get.
+	storageBucketObjectGetCmd := cmdStorageBucketObjectGet{global: c.global, storageBucketObject: c}
+	cmd.AddCommand(storageBucketObjectGetCmd.command())
+
+	// List.
+	storageBucketObjectListCmd := cmdStorageBucketObjectList{global: c.global, storageBucketObject: c}
+	cmd.AddCommand(storageBucketObjectListCmd.command())
+
+	// Put.
+	storageBucketObjectPutCmd := cmdStorageBucketObjectPut{global: c.global, storageBucketObject: c}
+	cmd.AddCommand(storageBucketObjectPutCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// parseBucketArgs parses the pool and bucket arguments and returns the resource and the client to use.
+func (c *cmdStorageBucketObject) parseBucketArgs(args []string) (*remoteResource, error) {
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return nil, errors.New("Missing pool name")
+	}
+
+	if args[1] == "" {
+		return nil, errors.New("Missing bucket name")
+	}
+
+	// If a target member was specified, use the bucket on that member.
+	if c.flagTarget != "" {
+		resource.server = resource.server.UseTarget(c.flagTarget)
+	}
+
+	return &resource, nil
+}
+
+// List Objects.
+// cmdStorageBucketObjectList implements the "lxc storage bucket object list" command.
+type cmdStorageBucketObjectList struct {
+	global              *cmdGlobal
+	storageBucketObject *cmdStorageBucketObject
+	flagFormat          string
+	flagColumns         string
+}
+
+// columns returns the ordered column definitions for storage bucket object list.
+func (c *cmdStorageBucketObjectList) columns() []cli.ShorthandColumn[api.StorageBucketObject] {
+	return []cli.ShorthandColumn[api.StorageBucketObject]{
+		{Shorthand: 'n', Name: "NAME", Data: c.nameColumnData},
+		{Shorthand: 's', Name: "SIZE", Data: c.sizeColumnData},
+		{Shorthand: 'm', Name: "LAST MODIFIED", Data: c.lastModifiedColumnData},
+	}
+}
+
+func (c *cmdStorageBucketObjectList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", "[<remote>:]<pool> <bucket> [<prefix>]")
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = "List storage bucket objects"
+	cmd.Long = cli.FormatSection("Description", `List storage bucket objects
+
+If a prefix is provided, only the objects whose name starts with it are listed.`)
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", cli.DefaultColumnString(c.columns()), cli.FormatStringFlagLabel("Columns"))
+	cmd.Flags().StringVar(&c.storageBucketObject.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdStorageBucketObjectList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 3)
+	if exit {
+		return err
+	}
+
+	resource, err := c.storageBucketObject.parseBucketArgs(args)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if len(args) > 2 {
+		prefix = args[2]
+	}
+
+	objects, err := resource.server.GetStoragePoolBucketObjects(resource.name, args[1], prefix)
+	if err != nil {
+		return err
+	}
+
+	// Parse column flags.
+	columns, err := cli.ParseShorthandColumns(c.flagColumns, c.columns())
+	if err != nil {
+		return err
+	}
+
+	data := cli.ColumnData(columns, objects)
+	sort.Sort(cli.SortColumnsNaturally(data))
+	header := cli.ColumnHeaders(columns)
+
+	return cli.RenderTable(c.flagFormat, header, data, objects)
+}
+
+func (c *cmdStorageBucketObjectList) nameColumnData(object api.StorageBucketObject) string {
+	return object.Name
+}
+
+func (c *cmdStorageBucketObjectList) sizeColumnData(object api.StorageBucketObject) string {
+	return units.GetByteSizeStringIEC(object.Size, 2)
+}
+
+func (c *cmdStorageBucketObjectList) lastModifiedColumnData(object api.StorageBucketObject) string {
+	return object.LastModified.Local().Format("2006/01/02 15:04 MST")
+}
+
+// Get Object.
+type cmdStorageBucketObjectGet struct {
+	global              *cmdGlobal
+	storageBucketObject *cmdStorageBucketObject
+}
+
+func (c *cmdStorageBucketObjectGet) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("get", "[<remote>:]<pool> <bucket> <object> [<target path>]")
+	cmd.Short = "Download storage bucket objects"
+	cmd.Long = cli.FormatSection("Description", `Download storage bucket objects
+
+The object is written to a file named after the last part of the object name, unless a target path is provided.
+Use "-" as the target path to write the object to the standard output.`)
+	cmd.Example = cli.FormatSection("", `lxc storage bucket object get p1 b01 backups/db.tar.gz
+	Download the object "backups/db.tar.gz" of bucket b01 to db.tar.gz
+
+lxc storage bucket object get p1 b01 config.yaml - | less
+	Display the content of the object "config.yaml" of bucket b01`)
+
+	cmd.Flags().StringVar(&c.storageBucketObject.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdStorageBucketObjectGet) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 4)
+	if exit {
+		return err
+	}
+
+	resource, err := c.storageBucketObject.parseBucketArgs(args)
+	if err != nil {
+		return err
+	}
+
+	objectName := args[2]
+	if objectName == "" {
+		return errors.New("Missing object name")
+	}
+
+	targetPath := path.Base(objectName)
+	if len(args) > 3 {
+		targetPath = args[3]
+	}
+
+	content, _, err := resource.server.GetStoragePoolBucketObject(resource.name, args[1], objectName)
+	if err != nil {
+		return err
+	}
+
+	defer content.Close()
+
+	if targetPath == "-" {
+		_, err = io.Copy(os.Stdout, content)
+		return err
+	}
+
+	target, err := os.Create(targetPath)
+	if err != nil {
+		return err
+	}
+
+	defer target.Close()
+
+	_, err = io.Copy(target, content)
+	if err != nil {
+		return err
+	}
+
+	return target.Close()
+}
+
+// Put Object.
+type cmdStorageBucketObjectPut struct {
+	global              *cmdGlobal
+	storageBucketObject *cmdStorageBucketObject
+}
+
+func (c *cmdStorageBucketObjectPut) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("put", "[<remote>:]<pool> <bucket> <source path> [<object>]")
+	cmd.Short = "Upload storage bucket objects"
+	cmd.Long = cli.FormatSection("Description", `Upload storage bucket objects
+
+The object is named after the source file, unless an object name is provided.
+Any existing object with the same name is replaced.
+Use "-" as the source path to read the object from the standard input.`)
+	cmd.Example = cli.FormatSection("", `lxc storage bucket object put p1 b01 db.tar.gz backups/db.tar.gz
+	Upload db.tar.gz to bucket b01 as "backups/db.tar.gz"`)
+
+	cmd.Flags().StringVar(&c.storageBucketObject.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdStorageBucketObjectPut) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 4)
+	if exit {
+		return err
+	}
+
+	resource, err := c.storageBucketObject.parseBucketArgs(args)
+	if err != nil {
+		return err
+	}
+
+	sourcePath := args[2]
+
+	objectName := filepath.Base(sourcePath)
+	if len(args) > 3 {
+		objectName = args[3]
+	} else if sourcePath == "-" {
+		return errors.New("An object name is required when reading from the standard input")
+	}
+
+	var content io.Reader
+	var size int64
+
+	if sourcePath == "-" {
+		// The size of the object must be known before uploading it.
+		buf, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		content = bytes.NewReader(buf)
+		size = int64(len(buf))
+	} else {
+		file, err := os.Open(sourcePath)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		fInfo, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		if !fInfo.Mode().IsRegular() {
+			return fmt.Errorf("%q is not a regular file", sourcePath)
+		}
+
+		content = file
+		size = fInfo.Size()
+	}
+
+	return resource.server.CreateStoragePoolBucketObject(resource.name, args[1], objectName, content, size)
+}
+
+// Delete Object.
+type cmdStorageBucketObjectDelete struct {
+	global              *cmdGlobal
+	storageBucketObject *cmdStorageBucketObject
+}
+
+func (c *cmdStorageBucketObjectDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", "[<remote>:]<pool> <bucket> <object>")
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = "Delete storage bucket objects"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.Flags().StringVar(&c.storageBucketObject.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdStorageBucketObjectDelete) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	resource, err := c.storageBucketObject.parseBucketArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if args[2] == "" {
+		return errors.New("Missing object name")
+	}
+
+	err = resource.server.DeleteStoragePoolBucketObject(resource.name, args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Storage bucket object %s deleted\n", args[2])
+	}
+
+	return nil
+}
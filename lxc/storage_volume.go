@@ -75,6 +75,10 @@ Unless specified through a prefix, all volume operations affect "custom" (user c
 	storageVolumeAttachProfileCmd := cmdStorageVolumeAttachProfile{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeAttachProfileCmd.command())
 
+	// Check
+	storageVolumeCheckCmd := cmdStorageVolumeCheck{global: c.global, storage: c.storage, storageVolume: c}
+	cmd.AddCommand(storageVolumeCheckCmd.command())
+
 	// Copy
 	storageVolumeCopyCmd := cmdStorageVolumeCopy{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeCopyCmd.command())
@@ -372,6 +376,96 @@ func (c *cmdStorageVolumeAttachProfile) run(cmd *cobra.Command, args []string) e
 	return nil
 }
 
+// Check.
+type cmdStorageVolumeCheck struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+}
+
+func (c *cmdStorageVolumeCheck) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("check", "[<remote>:]<pool> [<type>/]<volume>")
+	cmd.Short = "Check the integrity of storage volumes"
+	cmd.Long = cli.FormatSection("Description", `Check the integrity of storage volumes
+
+The volume must not be in use. Image volumes are also verified against their fingerprint.
+The result of the last check is shown by "lxc storage volume info".`)
+	cmd.Example = cli.FormatSection("", `Provide the type of the storage volume if it is not custom.
+Supported types are custom, image, container and virtual-machine.
+
+lxc storage volume check default data
+    Check the custom volume "data" in pool "default".
+
+lxc storage volume check default virtual-machine/v1
+    Check the volume of the stopped virtual machine "v1" in pool "default".`)
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("storage_pool", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpStoragePoolVolumes(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageVolumeCheck) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing pool name")
+	}
+
+	client := resource.server
+
+	// Parse the input
+	volName, volType := parseVolume("custom", args[1])
+
+	// If a target was specified, check the volume on the given member.
+	if c.storage.flagTarget != "" {
+		client = client.UseTarget(c.storage.flagTarget)
+	}
+
+	// Check the volume
+	op, err := client.CheckStoragePoolVolume(resource.name, volType, volName)
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		status, _ := op.Get().Metadata["status"].(string)
+		fmt.Printf("Storage volume %s checked: %s\n", args[1], status)
+	}
+
+	return nil
+}
+
 // Copy.
 type cmdStorageVolumeCopy struct {
 	global        *cmdGlobal
@@ -1506,6 +1600,15 @@ func (c *cmdStorageVolumeInfo) run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Created: %s\n", vol.CreatedAt.Local().Format(layout))
 	}
 
+	if volState != nil && volState.Check != nil {
+		check := volState.Check.Status
+		if volState.Check.Message != "" {
+			check += " (" + volState.Check.Message + ")"
+		}
+
+		fmt.Printf("Last check: %s, %s\n", volState.Check.CheckedAt.Local().Format(layout), check)
+	}
+
 	// List snapshots
 	firstSnapshot := true
 	if len(volSnapshots) > 0 {
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdTrash struct {
+	global *cmdGlobal
+}
+
+func (c *cmdTrash) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("trash")
+	cmd.Short = "Manage deleted instances and custom storage volumes"
+	cmd.Long = cli.FormatSection("Description", `Manage deleted instances and custom storage volumes
+
+Instances and custom storage volumes deleted from a project with "deletion.retention" set
+are kept in the project trash until the retention period expires.`)
+
+	// List
+	trashListCmd := cmdTrashList{global: c.global, trash: c}
+	cmd.AddCommand(trashListCmd.command())
+
+	// Restore
+	trashRestoreCmd := cmdTrashRestore{global: c.global, trash: c}
+	cmd.AddCommand(trashRestoreCmd.command())
+
+	// Delete
+	trashDeleteCmd := cmdTrashDelete{global: c.global, trash: c}
+	cmd.AddCommand(trashDeleteCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdTrashList struct {
+	global *cmdGlobal
+	trash  *cmdTrash
+
+	flagColumns     string
+	flagFormat      string
+	flagAllProjects bool
+}
+
+func (c *cmdTrashList) columns() []cli.ShorthandColumn[api.TrashEntry] {
+	return []cli.ShorthandColumn[api.TrashEntry]{
+		{Shorthand: 'u', Name: "UUID", Data: c.uuidColumnData},
+		{Shorthand: 'n', Name: "NAME", Data: c.nameColumnData},
+		{Shorthand: 't', Name: "TYPE", Data: c.typeColumnData},
+		{Shorthand: 'P', Name: "POOL", Data: c.poolColumnData},
+		{Shorthand: 'd', Name: "DELETED AT", Data: c.deletedAtColumnData},
+		{Shorthand: 'e', Name: "EXPIRES AT", Data: c.expiresAtColumnData},
+	}
+}
+
+func (c *cmdTrashList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", "[<remote>:]")
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = "List deleted instances and custom storage volumes"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The -c option takes a (optionally comma-separated) list of arguments
+that control which trash entry attributes to output when displaying in table
+or csv format.
+
+Default column layout is: untPde
+
+Column shorthand chars:
+
+    d - Deletion date
+    e - Expiry date
+    L - Location of the trashed volume (e.g. its cluster member)
+    n - Name
+    p - Project
+    P - Storage pool
+    t - Type
+    u - UUID`)
+
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", cli.DefaultColumnString(c.columns()), cli.FormatStringFlagLabel("Columns"))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+	cmd.Flags().BoolVar(&c.flagAllProjects, "all-projects", false, "Display trash entries from all projects")
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdTrashList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	remoteName, _, err := c.global.conf.ParseRemote(remote)
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	var entries []api.TrashEntry
+	if c.flagAllProjects {
+		entries, err = remoteServer.GetTrashEntriesAllProjects()
+	} else {
+		entries, err = remoteServer.GetTrashEntries()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Add non-default columns that are available for user selection.
+	cols := c.columns()
+	cols = append(cols,
+		cli.ShorthandColumn[api.TrashEntry]{Shorthand: 'p', Name: "PROJECT", Data: c.projectColumnData},
+		cli.ShorthandColumn[api.TrashEntry]{Shorthand: 'L', Name: "LOCATION", Data: c.locationColumnData},
+	)
+
+	// Show the project column by default when listing all projects.
+	if c.flagAllProjects && c.flagColumns == cli.DefaultColumnString(c.columns()) {
+		c.flagColumns = "p" + c.flagColumns
+	}
+
+	columns, err := cli.ParseShorthandColumns(c.flagColumns, cols)
+	if err != nil {
+		return err
+	}
+
+	// Render the table
+	data := cli.ColumnData(columns, entries)
+	sort.Sort(cli.StringList(data))
+
+	rawData := make([]*api.TrashEntry, len(entries))
+	for i := range entries {
+		rawData[i] = &entries[i]
+	}
+
+	headers := cli.ColumnHeaders(columns)
+
+	return cli.RenderTable(c.flagFormat, headers, data, rawData)
+}
+
+func (c *cmdTrashList) deletedAtColumnData(entry api.TrashEntry) string {
+	return entry.DeletedAt.UTC().Format("Jan 2, 2006 at 3:04pm (MST)")
+}
+
+func (c *cmdTrashList) expiresAtColumnData(entry api.TrashEntry) string {
+	return entry.ExpiresAt.UTC().Format("Jan 2, 2006 at 3:04pm (MST)")
+}
+
+func (c *cmdTrashList) locationColumnData(entry api.TrashEntry) string {
+	return entry.Location
+}
+
+func (c *cmdTrashList) nameColumnData(entry api.TrashEntry) string {
+	return entry.Name
+}
+
+func (c *cmdTrashList) poolColumnData(entry api.TrashEntry) string {
+	return entry.Pool
+}
+
+func (c *cmdTrashList) projectColumnData(entry api.TrashEntry) string {
+	return entry.Project
+}
+
+func (c *cmdTrashList) typeColumnData(entry api.TrashEntry) string {
+	return entry.Type
+}
+
+func (c *cmdTrashList) uuidColumnData(entry api.TrashEntry) string {
+	return entry.UUID
+}
+
+// Restore.
+type cmdTrashRestore struct {
+	global *cmdGlobal
+	trash  *cmdTrash
+
+	flagName string
+}
+
+func (c *cmdTrashRestore) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("restore", "[<remote>:]<uuid>")
+	cmd.Short = "Restore a deleted instance or custom storage volume"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+	cmd.Example = cli.FormatSection("", `lxc trash restore 5f0dbc3b-4e4f-4cdc-9f0f-17e1a9e3a5b1
+    Restores the trashed instance or volume under its original name.
+
+lxc trash restore 5f0dbc3b-4e4f-4cdc-9f0f-17e1a9e3a5b1 --name c1-restored
+    Restores the trashed instance or volume as "c1-restored".`)
+	cmd.Flags().StringVar(&c.flagName, "name", "", cli.FormatStringFlagLabel("Name to restore the instance or volume as"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdTrashRestore) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remoteName, UUID, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	if UUID == "" {
+		return errors.New("Missing trash entry UUID")
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	op, err := remoteServer.RestoreTrashEntry(UUID, api.TrashEntryPost{Name: c.flagName})
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Trash entry %s restored\n", UUID)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdTrashDelete struct {
+	global *cmdGlobal
+	trash  *cmdTrash
+}
+
+func (c *cmdTrashDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", "[<remote>:]<uuid>")
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = "Permanently delete a trashed instance or custom storage volume"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdTrashDelete) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remoteName, UUID, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	if UUID == "" {
+		return errors.New("Missing trash entry UUID")
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	op, err := remoteServer.DeleteTrashEntry(UUID)
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Trash entry %s deleted\n", UUID)
+	}
+
+	return nil
+}
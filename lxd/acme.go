@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
+	"github.com/go-acme/lego/v4/challenge"
+
 	"github.com/canonical/lxd/lxd/acme"
 	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
@@ -74,8 +77,19 @@ func autoRenewCertificate(ctx context.Context, d *Daemon, force bool) error {
 		return nil
 	}
 
+	challengeType, zoneName := s.GlobalConfig.ACMEChallenge()
+
+	var provider challenge.Provider = d.http01Provider
+	if challengeType == acme.ChallengeDNS01 {
+		if zoneName == "" {
+			return errors.New("The acme.dns.zone configuration key must be set to use the DNS-01 challenge")
+		}
+
+		provider = acme.NewDNS01Provider(s, zoneName)
+	}
+
 	opRun := func(ctx context.Context, op *operations.Operation) error {
-		newCert, err := acme.UpdateCertificate(s, d.http01Provider, s.ServerClustered, domain, email, caURL, force)
+		newCert, err := acme.UpdateCertificate(s, challengeType, provider, s.ServerClustered, domain, email, caURL, force)
 		if err != nil {
 			return err
 		}
@@ -14,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/registration"
 
@@ -39,8 +40,9 @@ func certificateNeedsUpdate(domain string, cert *x509.Certificate) bool {
 	return !slices.Contains(cert.DNSNames, domain) || time.Now().After(cert.NotAfter.Add(-30*24*time.Hour))
 }
 
-// UpdateCertificate updates the certificate.
-func UpdateCertificate(s *state.State, provider HTTP01Provider, clustered bool, domain string, email string, caURL string, force bool) (*certificate.Resource, error) {
+// UpdateCertificate updates the certificate. The challenge type selects whether the provider solves HTTP-01 or DNS-01
+// challenges.
+func UpdateCertificate(s *state.State, challengeType string, provider challenge.Provider, clustered bool, domain string, email string, caURL string, force bool) (*certificate.Resource, error) {
 	clusterCertFilename := shared.VarPath(ClusterCertFilename)
 
 	l := logger.AddContext(logger.Ctx{"domain": domain, "caURL": caURL})
@@ -129,9 +131,14 @@ func UpdateCertificate(s *state.State, provider HTTP01Provider, clustered bool,
 		return nil, fmt.Errorf("Failed creating new client: %w", err)
 	}
 
-	err = client.Challenge.SetHTTP01Provider(provider)
+	if challengeType == ChallengeDNS01 {
+		err = client.Challenge.SetDNS01Provider(provider)
+	} else {
+		err = client.Challenge.SetHTTP01Provider(provider)
+	}
+
 	if err != nil {
-		return nil, fmt.Errorf("Failed setting HTTP-01 provider: %w", err)
+		return nil, fmt.Errorf("Failed setting %s provider: %w", challengeType, err)
 	}
 
 	var reg *registration.Resource
@@ -0,0 +1,176 @@
+package acme
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+
+	"github.com/canonical/lxd/lxd/network/zone"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+)
+
+const (
+	// ChallengeHTTP01 is the HTTP-01 challenge type, where LXD serves the challenge on its HTTPS listener.
+	ChallengeHTTP01 = "HTTP-01"
+
+	// ChallengeDNS01 is the DNS-01 challenge type, where LXD publishes the challenge in a network zone.
+	ChallengeDNS01 = "DNS-01"
+)
+
+// dns01RecordTTL is the TTL of the challenge TXT records. It is kept low so that resolvers don't cache stale challenges.
+const dns01RecordTTL = 60
+
+// dns01PropagationTimeout is how long to wait for the challenge record to be visible on the authoritative nameservers
+// of the domain. Secondary DNS servers that transfer the network zone only pick up changes after the SOA refresh
+// interval, so this is longer than the default timeout.
+const dns01PropagationTimeout = 10 * time.Minute
+
+// dns01PollingInterval is how often to check whether the challenge record is visible.
+const dns01PollingInterval = 10 * time.Second
+
+// dns01Provider is a DNS-01 challenge provider that publishes the challenge as a custom record of a network zone. The
+// record is served by the LXD DNS server and transferred to any configured peers.
+type dns01Provider struct {
+	mu       sync.Mutex
+	s        *state.State
+	zoneName string
+}
+
+// NewDNS01Provider returns a DNS-01 challenge provider that publishes the challenge in the network zone with the given
+// name.
+func NewDNS01Provider(s *state.State, zoneName string) challenge.ProviderTimeout {
+	return &dns01Provider{
+		s:        s,
+		zoneName: zoneName,
+	}
+}
+
+// Present adds the challenge TXT entry to the network zone.
+func (p *dns01Provider) Present(domain string, token string, keyAuth string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return p.updateRecord(info.EffectiveFQDN, func(entries []api.NetworkZoneRecordEntry) []api.NetworkZoneRecordEntry {
+		return addChallengeEntry(entries, info.Value)
+	})
+}
+
+// CleanUp removes the challenge TXT entry from the network zone.
+func (p *dns01Provider) CleanUp(domain string, token string, keyAuth string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return p.updateRecord(info.EffectiveFQDN, func(entries []api.NetworkZoneRecordEntry) []api.NetworkZoneRecordEntry {
+		return removeChallengeEntry(entries, info.Value)
+	})
+}
+
+// Timeout returns the timeout and polling interval used to check that the challenge record has propagated.
+func (p *dns01Provider) Timeout() (timeout time.Duration, interval time.Duration) {
+	return dns01PropagationTimeout, dns01PollingInterval
+}
+
+// updateRecord applies the given change to the entries of the network zone record of the given FQDN. The record is
+// created if it doesn't exist, and deleted once it has no entries left.
+func (p *dns01Provider) updateRecord(fqdn string, change func([]api.NetworkZoneRecordEntry) []api.NetworkZoneRecordEntry) error {
+	ctx := context.Background()
+
+	netZone, err := zone.LoadByName(ctx, p.s, p.zoneName)
+	if err != nil {
+		return fmt.Errorf("Failed loading network zone %q: %w", p.zoneName, err)
+	}
+
+	recordName, err := challengeRecordName(fqdn, netZone.Info().Name)
+	if err != nil {
+		return err
+	}
+
+	record, err := netZone.GetRecord(ctx, recordName)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed loading network zone record %q: %w", recordName, err)
+	}
+
+	if record == nil {
+		entries := change(nil)
+		if len(entries) == 0 {
+			return nil
+		}
+
+		err = netZone.AddRecord(ctx, api.NetworkZoneRecordsPost{
+			Name: recordName,
+			NetworkZoneRecordPut: api.NetworkZoneRecordPut{
+				Description: "ACME DNS-01 challenge",
+				Entries:     entries,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("Failed adding network zone record %q: %w", recordName, err)
+		}
+
+		return nil
+	}
+
+	entries := change(record.Entries)
+	if len(entries) == 0 {
+		err = netZone.DeleteRecord(ctx, recordName)
+		if err != nil {
+			return fmt.Errorf("Failed deleting network zone record %q: %w", recordName, err)
+		}
+
+		return nil
+	}
+
+	req := record.Writable()
+	req.Entries = entries
+	err = netZone.UpdateRecord(ctx, recordName, req)
+	if err != nil {
+		return fmt.Errorf("Failed updating network zone record %q: %w", recordName, err)
+	}
+
+	return nil
+}
+
+// challengeRecordName returns the name of the challenge record relative to the network zone. It fails if the FQDN of
+// the challenge is not part of the zone.
+func challengeRecordName(fqdn string, zoneName string) (string, error) {
+	fqdn = strings.ToLower(dns01.UnFqdn(fqdn))
+	zoneName = strings.ToLower(dns01.UnFqdn(zoneName))
+
+	recordName, found := strings.CutSuffix(fqdn, "."+zoneName)
+	if !found || recordName == "" {
+		return "", fmt.Errorf("Challenge record %q is not part of network zone %q", fqdn, zoneName)
+	}
+
+	return recordName, nil
+}
+
+// addChallengeEntry returns the entries with a TXT entry for the given challenge value added.
+// Several challenges may be pending for the same record, for example when issuing a certificate for both a domain and
+// its wildcard.
+func addChallengeEntry(entries []api.NetworkZoneRecordEntry, value string) []api.NetworkZoneRecordEntry {
+	entry := api.NetworkZoneRecordEntry{Type: "TXT", TTL: dns01RecordTTL, Value: strconv.Quote(value)}
+	if slices.Contains(entries, entry) {
+		return entries
+	}
+
+	return append(slices.Clone(entries), entry)
+}
+
+// removeChallengeEntry returns the entries without the TXT entry for the given challenge value.
+func removeChallengeEntry(entries []api.NetworkZoneRecordEntry, value string) []api.NetworkZoneRecordEntry {
+	quoted := strconv.Quote(value)
+	return slices.DeleteFunc(slices.Clone(entries), func(entry api.NetworkZoneRecordEntry) bool {
+		return entry.Type == "TXT" && entry.Value == quoted
+	})
+}
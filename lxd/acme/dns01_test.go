@@ -0,0 +1,104 @@
+package acme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_challengeRecordName(t *testing.T) {
+	tests := []struct {
+		name     string
+		fqdn     string
+		zoneName string
+		want     string
+		wantErr  bool
+	}{
+		{
+			"Domain is the zone apex",
+			"_acme-challenge.example.net.",
+			"example.net",
+			"_acme-challenge",
+			false,
+		},
+		{
+			"Domain is a subdomain of the zone",
+			"_acme-challenge.lxd.example.net.",
+			"example.net",
+			"_acme-challenge.lxd",
+			false,
+		},
+		{
+			"Challenge delegated to a dedicated zone",
+			"lxd.acme.example.org.",
+			"acme.example.org.",
+			"lxd",
+			false,
+		},
+		{
+			"Names are case insensitive",
+			"_acme-challenge.LXD.Example.net.",
+			"example.NET",
+			"_acme-challenge.lxd",
+			false,
+		},
+		{
+			"Domain is not part of the zone",
+			"_acme-challenge.lxd.example.org.",
+			"example.net",
+			"",
+			true,
+		},
+		{
+			"Zone name is only a suffix of a label",
+			"_acme-challenge.lxdexample.net.",
+			"example.net",
+			"",
+			true,
+		},
+		{
+			"Challenge name is the zone itself",
+			"_acme-challenge.example.net.",
+			"_acme-challenge.example.net",
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := challengeRecordName(tt.fqdn, tt.zoneName)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_challengeEntries(t *testing.T) {
+	other := api.NetworkZoneRecordEntry{Type: "TXT", TTL: 300, Value: `"other"`}
+
+	// Adding a challenge keeps existing entries and is idempotent.
+	entries := addChallengeEntry([]api.NetworkZoneRecordEntry{other}, "foo")
+	entries = addChallengeEntry(entries, "foo")
+	require.Equal(t, []api.NetworkZoneRecordEntry{other, {Type: "TXT", TTL: dns01RecordTTL, Value: `"foo"`}}, entries)
+
+	// Several challenges can be pending for the same record.
+	entries = addChallengeEntry(entries, "bar")
+	require.Len(t, entries, 3)
+
+	// Removing a challenge only removes its own entry.
+	entries = removeChallengeEntry(entries, "foo")
+	require.Equal(t, []api.NetworkZoneRecordEntry{other, {Type: "TXT", TTL: dns01RecordTTL, Value: `"bar"`}}, entries)
+
+	entries = removeChallengeEntry(entries, "bar")
+	require.Equal(t, []api.NetworkZoneRecordEntry{other}, entries)
+
+	require.Empty(t, removeChallengeEntry(nil, "foo"))
+}
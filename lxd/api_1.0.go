@@ -58,6 +58,9 @@ var api10 = []APIEndpoint{
 	clusterCmd,
 	clusterGroupCmd,
 	clusterGroupsCmd,
+	clusterInstanceRebalanceCmd,
+	clusterMaintenanceCmd,
+	clusterMaintenanceStateCmd,
 	clusterMemberCmd,
 	clusterMemberStateCmd,
 	clusterMembersCmd,
@@ -125,25 +128,34 @@ var api10 = []APIEndpoint{
 	projectStateCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
+	storagePoolScrubCmd,
 	storagePoolsCmd,
 	storagePoolBucketsCmd,
 	storagePoolBucketCmd,
 	storagePoolBucketKeysCmd,
 	storagePoolBucketKeyCmd,
+	storagePoolBucketObjectsCmd,
+	storagePoolBucketObjectCmd,
 	storagePoolVolumesCmd,
 	storagePoolVolumeSnapshotsTypeCmd,
 	storagePoolVolumeSnapshotTypeCmd,
+	storagePoolVolumeSnapshotDiffCmd,
+	storagePoolVolumeSnapshotDiffExportCmd,
 	storagePoolVolumesTypeCmd,
 	storagePoolVolumeTypeCmd,
 	storagePoolVolumeTypeCustomBackupsCmd,
 	storagePoolVolumeTypeCustomBackupCmd,
 	storagePoolVolumeTypeCustomBackupExportCmd,
 	storagePoolVolumeTypeStateCmd,
+	storagePoolVolumeTypeCheckCmd,
+	trashCmd,
+	trashEntryCmd,
 	warningsCmd,
 	warningCmd,
 	metricsCmd,
 	identitiesCmd,
 	currentIdentityCmd,
+	currentIdentityCertificateCmd,
 	tlsIdentityCmd,
 	oidcIdentityCmd,
 	tlsIdentitiesCmd,
@@ -156,6 +168,8 @@ var api10 = []APIEndpoint{
 	identityProviderGroupsCmd,
 	identityProviderGroupCmd,
 	permissionsCmd,
+	authCheckCmd,
+	auditCmd,
 	storageVolumesCmd,
 	storageVolumesTypeCmd,
 	oidcSessionsCmd,
@@ -1112,6 +1126,11 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 
 		case "core.bgp_asn":
 			bgpChanged = true
+		case "core.audit_max_size", "core.audit_max_files":
+			if d.auditLog != nil {
+				d.auditLog.SetLimits(newClusterConfig.AuditLimits())
+			}
+
 		case "loki.api.url":
 			fallthrough
 		case "loki.auth.username":
@@ -1130,9 +1149,9 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			lokiChanged = true
 		case "acme.ca_url":
 			acmeCAURLChanged = true
-		case "acme.domain":
+		case "acme.domain", "acme.challenge", "acme.dns.zone":
 			acmeDomainChanged = true
-		case "oidc.issuer", "oidc.client.id", "oidc.client.secret", "oidc.scopes", "oidc.audience", "oidc.groups.claim", "oidc.device.client.id":
+		case "oidc.issuer", "oidc.client.id", "oidc.client.secret", "oidc.scopes", "oidc.audience", "oidc.groups.claim", "oidc.device.client.id", "oidc.additional_issuers", "oidc.groups.mappings":
 			oidcChanged = true
 		}
 	}
@@ -1303,7 +1322,7 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			}
 
 			sessionHandler := dbOIDC.NewSessionHandler(d.db.Cluster, d.events, expiryFunc)
-			oidcVerifier, err := oidc.NewVerifier(s.ShutdownCtx, oidcIssuer, oidcClientID, oidcClientSecret, oidcScopes, oidcAudience, oidcGroupsClaim, oidcDeviceClientID, newClusterConfig.ClusterUUID(), d.endpoints.NetworkAddress(), s.CoreAuthSecrets, httpClientFunc, sessionHandler)
+			oidcVerifier, err := oidc.NewVerifier(s.ShutdownCtx, oidcIssuer, oidcClientID, oidcClientSecret, oidcScopes, oidcAudience, oidcGroupsClaim, oidcDeviceClientID, newClusterConfig.OIDCAdditionalIssuers(), newClusterConfig.OIDCClaimMappings(), newClusterConfig.ClusterUUID(), d.endpoints.NetworkAddress(), s.CoreAuthSecrets, httpClientFunc, sessionHandler)
 			if err != nil {
 				return fmt.Errorf("Failed creating verifier: %w", err)
 			}
@@ -1724,7 +1724,20 @@ func autoHealCluster(ctx context.Context, s *state.State, gateway *cluster.Gatew
 	}
 
 	opRun := func(ctx context.Context, op *operations.Operation) error {
+		fencers, fencingTimeout := clusterFencers(s)
+
 		for _, member := range offlineMembers {
+			// Only start the instances of the member elsewhere once it's confirmed that it can't write to them anymore.
+			if len(fencers) > 0 {
+				err := cluster.FenceMember(ctx, member, fencingTimeout, fencers...)
+				if err != nil {
+					logger.Error("Failed fencing cluster member, not healing it", logger.Ctx{"member": member.Name, "err": err})
+					return err
+				}
+
+				s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberFenced.Event(member.Name, op.EventLifecycleRequestor(), nil))
+			}
+
 			err := healClusterMember(s, gateway, op, member.Name)
 			if err != nil {
 				logger.Error("Failed healing cluster instances", logger.Ctx{"err": err})
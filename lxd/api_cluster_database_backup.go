@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/node"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var internalClusterDatabaseBackupCmd = APIEndpoint{
+	Path:        "cluster/database-backup",
+	MetricsType: entity.TypeClusterMember,
+
+	Post: APIEndpointAction{Handler: internalClusterDatabaseBackupPost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+type internalClusterDatabaseBackup struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+// internalClusterDatabaseBackupPost takes a backup of the cluster database on this cluster member.
+func internalClusterDatabaseBackupPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	path, err := cluster.CreateDatabaseBackup(r.Context(), s.DB.Cluster, clusterDatabaseBackupsDir(s), s.ServerName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, internalClusterDatabaseBackup{Path: path})
+}
+
+// clusterDatabaseBackupsDir returns the directory holding the cluster database backups of this cluster member.
+func clusterDatabaseBackupsDir(s *state.State) string {
+	return filepath.Join(s.OS.VarDir, cluster.DatabaseBackupsDir)
+}
+
+// clusterDatabaseBackupTask takes the scheduled cluster database backups on database members.
+func clusterDatabaseBackupTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		spec, retention := s.GlobalConfig.ClusterDatabaseBackup()
+		if spec == "" {
+			return
+		}
+
+		err := clusterDatabaseBackupScheduled(ctx, s, spec, retention)
+		if err != nil {
+			logger.Error("Failed running scheduled cluster database backup task", logger.Ctx{"err": err})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// clusterDatabaseBackupScheduled takes a cluster database backup if scheduled now and this cluster member
+// is a voter or stand-by database member, then prunes the backups beyond retention.
+func clusterDatabaseBackupScheduled(ctx context.Context, s *state.State, spec string, retention int) error {
+	var info *db.RaftNode
+	err := s.DB.Node.Transaction(ctx, func(ctx context.Context, tx *db.NodeTx) error {
+		var err error
+		info, err = node.DetermineRaftNode(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed determining cluster member raft role: %w", err)
+	}
+
+	if info == nil || info.Role == db.RaftSpare {
+		return nil
+	}
+
+	// The raft node ID spreads the aliased schedules across database members.
+	if !snapshotIsScheduledNow(spec, int64(info.ID)) {
+		return nil
+	}
+
+	dir := clusterDatabaseBackupsDir(s)
+
+	path, err := cluster.CreateDatabaseBackup(ctx, s.DB.Cluster, dir, s.ServerName)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Created cluster database backup", logger.Ctx{"path": path})
+
+	return cluster.PruneDatabaseBackups(dir, retention)
+}
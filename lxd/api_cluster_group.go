@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db"
@@ -16,6 +17,7 @@ import (
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/scheduler"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
@@ -91,6 +93,11 @@ func clusterGroupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = clusterGroupValidateConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		obj := dbCluster.ClusterGroup{
 			Name:        req.Name,
@@ -98,7 +105,7 @@ func clusterGroupsPost(d *Daemon, r *http.Request) response.Response {
 			Nodes:       req.Members,
 		}
 
-		_, err := dbCluster.CreateClusterGroup(ctx, tx.Tx(), obj)
+		groupID, err := dbCluster.CreateClusterGroup(ctx, tx.Tx(), obj)
 		if err != nil {
 			if api.StatusErrorCheck(err, http.StatusConflict) {
 				return api.StatusErrorf(http.StatusConflict, "Cluster group %q already exists", req.Name)
@@ -107,6 +114,11 @@ func clusterGroupsPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = dbCluster.ClusterGroupsConfigStore().Set(ctx, tx.Tx(), groupID, req.Config)
+		if err != nil {
+			return err
+		}
+
 		for _, node := range obj.Nodes {
 			err = tx.AddNodeToClusterGroup(ctx, obj.Name, node)
 			if err != nil {
@@ -490,12 +502,22 @@ func clusterGroupPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = clusterGroupValidateConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		group, err := dbCluster.GetClusterGroup(ctx, tx.Tx(), name)
 		if err != nil {
 			return err
 		}
 
+		err = dbCluster.ClusterGroupsConfigStore().Set(ctx, tx.Tx(), int64(group.ID), req.Config)
+		if err != nil {
+			return err
+		}
+
 		members, err := tx.GetClusterGroupNodes(ctx, name)
 		if err != nil {
 			return err
@@ -626,6 +648,23 @@ func clusterGroupPatch(d *Daemon, r *http.Request) response.Response {
 		req.Members = clusterGroup.Members
 	}
 
+	// Keep the existing configuration keys which are not part of the request.
+	if req.Config == nil {
+		req.Config = map[string]string{}
+	}
+
+	for k, v := range clusterGroup.Config {
+		_, ok := req.Config[k]
+		if !ok {
+			req.Config[k] = v
+		}
+	}
+
+	err = clusterGroupValidateConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		obj := dbCluster.ClusterGroup{
 			Name:        dbClusterGroup.Name,
@@ -637,6 +676,11 @@ func clusterGroupPatch(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = dbCluster.ClusterGroupsConfigStore().Set(ctx, tx.Tx(), int64(dbClusterGroup.ID), req.Config)
+		if err != nil {
+			return err
+		}
+
 		members, err := tx.GetClusterGroupNodes(ctx, name)
 		if err != nil {
 			return err
@@ -838,3 +882,57 @@ func clusterGroupUsedBy(ctx context.Context, s *state.State, tx *db.ClusterTx, n
 
 	return usedBy, nil
 }
+
+// clusterGroupValidateConfig validates the configuration of a cluster group.
+func clusterGroupValidateConfig(config map[string]string) error {
+	isOvercommitRatio := func(value string) error {
+		_, err := scheduler.ParseOvercommit(value)
+		return err
+	}
+
+	clusterGroupConfigKeys := map[string]func(value string) error{
+		// lxdmeta:generate(entities=cluster; group=group-conf; key=scheduler.overcommit.cpu)
+		// Ratio between the number of CPUs that can be allocated to instances and the number of CPU threads
+		// of the members of the cluster group. For example, `2` allows allocating twice as many CPUs as available.
+		// If a cluster member belongs to several cluster groups, the lowest ratio applies.
+		// This is used by the `balanced` and `binpack` values of {config:option}`server-cluster:cluster.scheduler.strategy`.
+		// ---
+		//  type: string
+		//  defaultdesc: `1`
+		//  shortdesc: CPU overcommit ratio
+		"scheduler.overcommit.cpu": validate.Optional(isOvercommitRatio),
+
+		// lxdmeta:generate(entities=cluster; group=group-conf; key=scheduler.overcommit.memory)
+		// Ratio between the memory that can be allocated to instances and the memory of the members of the
+		// cluster group. If a cluster member belongs to several cluster groups, the lowest ratio applies.
+		// This is used by the `balanced` and `binpack` values of {config:option}`server-cluster:cluster.scheduler.strategy`.
+		// ---
+		//  type: string
+		//  defaultdesc: `1`
+		//  shortdesc: Memory overcommit ratio
+		"scheduler.overcommit.memory": validate.Optional(isOvercommitRatio),
+	}
+
+	for k, v := range config {
+		// lxdmeta:generate(entities=cluster; group=group-conf; key=user.*)
+		// User keys can be used in search.
+		// ---
+		//  type: string
+		//  shortdesc: Free form user key/value storage
+		if strings.HasPrefix(k, "user.") {
+			continue
+		}
+
+		validator, ok := clusterGroupConfigKeys[k]
+		if !ok {
+			return fmt.Errorf("Invalid cluster group key %q", k)
+		}
+
+		err := validator(v)
+		if err != nil {
+			return fmt.Errorf("Invalid cluster group key %q value: %w", k, err)
+		}
+	}
+
+	return nil
+}
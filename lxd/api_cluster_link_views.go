@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// clusterLinkViewRequested returns whether the request asks for results from linked clusters.
+func clusterLinkViewRequested(r *http.Request) bool {
+	return request.QueryParam(r, "cluster-link") != "" || shared.IsTrue(request.QueryParam(r, "all-links"))
+}
+
+// clusterLinkViewLocation returns the location reported for an entity of a linked cluster.
+// The cluster link name is prefixed to the cluster member name, if any.
+func clusterLinkViewLocation(linkName string, location string) string {
+	if location == "" || location == "none" {
+		return linkName
+	}
+
+	return linkName + ":" + location
+}
+
+// clusterLinkViewAnnotate records which cluster link each entry of a list response came from.
+// Entity URLs get a cluster-link query parameter, and entities get the cluster link prefixed to their location.
+func clusterLinkViewAnnotate(linkName string, entries []any) ([]any, error) {
+	for i, entry := range entries {
+		switch v := entry.(type) {
+		case string:
+			u, err := url.Parse(v)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid URL %q: %w", v, err)
+			}
+
+			values := u.Query()
+			values.Set("cluster-link", linkName)
+			u.RawQuery = values.Encode()
+			entries[i] = u.String()
+		case map[string]any:
+			_, hasLocations := v["locations"]
+			if hasLocations {
+				locations, _ := v["locations"].([]any)
+				newLocations := make([]any, 0, len(locations))
+				for _, location := range locations {
+					location, _ := location.(string)
+					newLocations = append(newLocations, clusterLinkViewLocation(linkName, location))
+				}
+
+				if len(newLocations) == 0 {
+					newLocations = append(newLocations, linkName)
+				}
+
+				v["locations"] = newLocations
+				continue
+			}
+
+			location, _ := v["location"].(string)
+			v["location"] = clusterLinkViewLocation(linkName, location)
+		default:
+			return nil, fmt.Errorf("Unexpected list entry type %T", entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// clusterLinkViewQuery returns the query string of the request without the cluster link parameters.
+func clusterLinkViewQuery(r *http.Request) string {
+	values := r.URL.Query()
+	values.Del("cluster-link")
+	values.Del("all-links")
+
+	return values.Encode()
+}
+
+// clusterLinkViewGet handles list requests using the cluster-link or all-links query parameters.
+// The request is sent to the linked clusters using the cluster link identity and the results are merged into a single list.
+// With all-links, the results of the local cluster are included and linked clusters which cannot be reached are skipped.
+// The caller must have the can_query entitlement on each cluster link that is queried. The access checks of the endpoint
+// have already been run against the local cluster by handleRequest.
+func clusterLinkViewGet(d *Daemon, r *http.Request, action APIEndpointAction, projectSpecific bool) response.Response {
+	s := d.State()
+
+	if r.Method != http.MethodGet {
+		return response.BadRequest(errors.New("Cluster link queries are only supported for GET requests"))
+	}
+
+	linkName := request.QueryParam(r, "cluster-link")
+	allLinks := shared.IsTrue(request.QueryParam(r, "all-links"))
+	if linkName != "" && allLinks {
+		return response.BadRequest(errors.New("Cannot specify a cluster link when requesting all cluster links"))
+	}
+
+	if linkName != "" {
+		err := s.Authorizer.CheckPermission(r.Context(), entity.ClusterLinkURL(linkName), auth.EntitlementCanQuery)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	type linkTarget struct {
+		clusterLink *api.ClusterLink
+		cert        *x509.Certificate
+	}
+
+	var targets []linkTarget
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		names := []string{linkName}
+		if allLinks {
+			userHasPermission, err := s.Authorizer.GetPermissionChecker(ctx, auth.EntitlementCanQuery, entity.TypeClusterLink)
+			if err != nil {
+				return err
+			}
+
+			links, _, err := dbCluster.GetClusterLinksAndURLs(ctx, tx.Tx(), func(link dbCluster.ClusterLinkRow) bool {
+				return userHasPermission(entity.ClusterLinkURL(link.Name))
+			})
+			if err != nil {
+				return err
+			}
+
+			names = make([]string, 0, len(links))
+			for _, link := range links {
+				names = append(names, link.Name)
+			}
+
+			slices.Sort(names)
+		}
+
+		for _, name := range names {
+			_, clusterLink, cert, err := cluster.LoadClusterLinkAndCert(ctx, tx.Tx(), name)
+			if err != nil {
+				return err
+			}
+
+			targets = append(targets, linkTarget{clusterLink: clusterLink, cert: cert})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	query := clusterLinkViewQuery(r)
+	path := r.URL.Path
+	if query != "" {
+		path += "?" + query
+	}
+
+	results := make([][]any, len(targets))
+	errs := make([]error, len(targets))
+	clusterCert := s.Endpoints.NetworkCert()
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Go(func() {
+			client, err := cluster.ConnectCluster(r.Context(), *target.clusterLink, cluster.GetClusterLinkConnectionArgs(clusterCert, target.cert))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			resp, _, err := client.RawQuery(http.MethodGet, path, nil, "")
+			if err != nil {
+				errs[i] = fmt.Errorf("Failed querying cluster link %q: %w", target.clusterLink.Name, err)
+				return
+			}
+
+			var entries []any
+			err = json.Unmarshal(resp.Metadata, &entries)
+			if err != nil {
+				errs[i] = fmt.Errorf("Failed parsing response from cluster link %q: %w", target.clusterLink.Name, err)
+				return
+			}
+
+			results[i], errs[i] = clusterLinkViewAnnotate(target.clusterLink.Name, entries)
+		})
+	}
+
+	merged := []any{}
+	if allLinks {
+		// Run the request against the local cluster with the usual checks.
+		localReq := r.Clone(r.Context())
+		localReq.URL.RawQuery = query
+		localReq.RequestURI = localReq.URL.RequestURI()
+
+		capture := response.NewResponseCapture(localReq)
+		err := handleRequest(d, localReq, action, projectSpecific).Render(capture, localReq)
+		if err != nil {
+			wg.Wait()
+			return response.SmartError(err)
+		}
+
+		localResp, _, err := capture.ToAPIResponse()
+		if err != nil {
+			wg.Wait()
+			return response.SmartError(err)
+		}
+
+		err = json.Unmarshal(localResp.Metadata, &merged)
+		if err != nil {
+			wg.Wait()
+			return response.InternalError(fmt.Errorf("Failed parsing local response: %w", err))
+		}
+	}
+
+	wg.Wait()
+
+	for i, target := range targets {
+		if errs[i] != nil {
+			if !allLinks {
+				return response.SmartError(errs[i])
+			}
+
+			logger.Warn("Skipping unavailable cluster link", logger.Ctx{"clusterLink": target.clusterLink.Name, "err": errs[i]})
+			continue
+		}
+
+		merged = append(merged, results[i]...)
+	}
+
+	return response.SyncResponse(true, merged)
+}
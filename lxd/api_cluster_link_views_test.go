@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterLinkViewLocation(t *testing.T) {
+	assert.Equal(t, "lxd02", clusterLinkViewLocation("lxd02", ""))
+	assert.Equal(t, "lxd02", clusterLinkViewLocation("lxd02", "none"))
+	assert.Equal(t, "lxd02:member1", clusterLinkViewLocation("lxd02", "member1"))
+}
+
+func TestClusterLinkViewAnnotate(t *testing.T) {
+	entries, err := clusterLinkViewAnnotate("lxd02", []any{
+		"/1.0/instances/c1?project=foo",
+		map[string]any{"name": "c1", "location": "member1"},
+		map[string]any{"name": "v1", "location": "none"},
+		map[string]any{"name": "net1", "locations": []any{"member1", "member2"}},
+		map[string]any{"name": "net2", "locations": nil},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/1.0/instances/c1?cluster-link=lxd02&project=foo", entries[0])
+	assert.Equal(t, "lxd02:member1", entries[1].(map[string]any)["location"])
+	assert.Equal(t, "lxd02", entries[2].(map[string]any)["location"])
+	assert.Equal(t, []any{"lxd02:member1", "lxd02:member2"}, entries[3].(map[string]any)["locations"])
+	assert.Equal(t, []any{"lxd02"}, entries[4].(map[string]any)["locations"])
+
+	_, err = clusterLinkViewAnnotate("lxd02", []any{42.0})
+	assert.Error(t, err)
+}
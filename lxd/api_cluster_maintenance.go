@@ -0,0 +1,778 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// clusterMaintenanceHealthTimeout is how long to wait for a cluster member to be online before restoring it and
+// healthy after restoring it.
+const clusterMaintenanceHealthTimeout = 10 * time.Minute
+
+// clusterMaintenancePollInterval is how often the state of a cluster member is checked while waiting for it.
+const clusterMaintenancePollInterval = 5 * time.Second
+
+var clusterMaintenanceCmd = APIEndpoint{
+	Path:        "cluster/maintenance",
+	MetricsType: entity.TypeClusterMember,
+
+	Get:  APIEndpointAction{Handler: clusterMaintenanceGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: clusterMaintenancePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+var clusterMaintenanceStateCmd = APIEndpoint{
+	Path:        "cluster/maintenance/state",
+	MetricsType: entity.TypeClusterMember,
+
+	Post: APIEndpointAction{Handler: clusterMaintenanceStatePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// clusterMaintenance holds the progress of a rolling cluster maintenance coordinated by this cluster member.
+type clusterMaintenance struct {
+	mu      sync.Mutex
+	state   api.ClusterMaintenance
+	aborted bool
+	ready   map[string]bool
+
+	// wake is signalled whenever the maintenance is paused, resumed, aborted or a member is reported ready.
+	wake chan struct{}
+}
+
+// clusterMaintenances holds the cluster maintenances coordinated by this cluster member, keyed by operation ID.
+var clusterMaintenances = map[string]*clusterMaintenance{}
+var clusterMaintenancesMu sync.Mutex
+
+// render returns a copy of the maintenance progress.
+func (m *clusterMaintenance) render() api.ClusterMaintenance {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.state
+	state.Members = slices.Clone(m.state.Members)
+
+	return state
+}
+
+// notify wakes up the maintenance run if it is waiting.
+func (m *clusterMaintenance) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// swagger:operation GET /1.0/cluster/maintenance cluster cluster_maintenance_get
+//
+//	Get the cluster maintenance progress
+//
+//	Returns the progress of the rolling cluster maintenance in progress.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Cluster maintenance
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterMaintenance"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterMaintenanceGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	m, resp := clusterMaintenanceLoad(r, s)
+	if resp != nil {
+		return resp
+	}
+
+	return response.SyncResponse(true, m.render())
+}
+
+// swagger:operation POST /1.0/cluster/maintenance cluster cluster_maintenance_post
+//
+//	Start a rolling cluster maintenance
+//
+//	Evacuates each cluster member in turn, waits for it to be reported ready (or for the ready timeout),
+//	restores it and checks that it is healthy before moving to the next cluster member.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: maintenance
+//	    description: Cluster maintenance request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterMaintenancePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterMaintenancePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	req := api.ClusterMaintenancePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !slices.Contains([]string{"", api.ClusterEvacuateModeStop, api.ClusterEvacuateModeMigrate, api.ClusterEvacuateModeLiveMigrate}, req.Mode) {
+		return response.BadRequest(fmt.Errorf("Invalid evacuation mode %q", req.Mode))
+	}
+
+	if req.ReadyTimeout < 0 {
+		return response.BadRequest(errors.New("Ready timeout cannot be negative"))
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// A cluster member handing the maintenance over is still running it.
+	handOver := requestor.IsClusterNotification()
+	if !handOver {
+		running, err := clusterMaintenanceOperation(r.Context(), s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if running != nil {
+			return response.Conflict(fmt.Errorf("A cluster maintenance is already in progress on %q", running.Location))
+		}
+	}
+
+	members, err := clusterMaintenanceMembers(r.Context(), s, req.Members)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	m := &clusterMaintenance{
+		state: api.ClusterMaintenance{
+			Status:       api.ClusterMaintenanceStatusRunning,
+			Location:     s.ServerName,
+			Mode:         req.Mode,
+			ReadyTimeout: req.ReadyTimeout,
+			Members:      make([]api.ClusterMaintenanceMember, 0, len(members)),
+		},
+		ready: map[string]bool{},
+		wake:  make(chan struct{}, 1),
+	}
+
+	for _, member := range members {
+		m.state.Members = append(m.state.Members, api.ClusterMaintenanceMember{Name: member, Status: api.ClusterMaintenanceMemberPending})
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		clusterMaintenancesMu.Lock()
+		clusterMaintenances[op.ID()] = m
+		clusterMaintenancesMu.Unlock()
+
+		defer func() {
+			clusterMaintenancesMu.Lock()
+			delete(clusterMaintenances, op.ID())
+			clusterMaintenancesMu.Unlock()
+		}()
+
+		return clusterMaintenanceRun(ctx, s, op, m)
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: "",
+		Type:        operationtype.ClusterMaintenance,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+		Metadata:    map[string]any{"maintenance": m.render()},
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !handOver {
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMaintenanceStarted.Event("maintenance", op.EventLifecycleRequestor(), map[string]any{"members": members}))
+	}
+
+	return response.OperationResponse(op)
+}
+
+// swagger:operation POST /1.0/cluster/maintenance/state cluster cluster_maintenance_state_post
+//
+//	Control the cluster maintenance
+//
+//	Pauses, resumes or aborts the cluster maintenance in progress, or reports an evacuated cluster member as ready
+//	to be restored.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: state
+//	    description: Cluster maintenance action
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterMaintenanceStatePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterMaintenanceStatePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	m, resp := clusterMaintenanceLoad(r, s)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.ClusterMaintenanceStatePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var action lifecycle.ClusterAction
+
+	m.mu.Lock()
+	switch req.Action {
+	case api.ClusterMaintenanceActionPause:
+		if m.state.Status != api.ClusterMaintenanceStatusRunning {
+			m.mu.Unlock()
+			return response.BadRequest(fmt.Errorf("Cannot pause a %s cluster maintenance", m.state.Status))
+		}
+
+		m.state.Status = api.ClusterMaintenanceStatusPaused
+		action = lifecycle.ClusterMaintenancePaused
+	case api.ClusterMaintenanceActionResume:
+		if m.state.Status != api.ClusterMaintenanceStatusPaused {
+			m.mu.Unlock()
+			return response.BadRequest(fmt.Errorf("Cannot resume a %s cluster maintenance", m.state.Status))
+		}
+
+		m.state.Status = api.ClusterMaintenanceStatusRunning
+		m.state.Error = ""
+		action = lifecycle.ClusterMaintenanceResumed
+	case api.ClusterMaintenanceActionAbort:
+		m.aborted = true
+		action = lifecycle.ClusterMaintenanceAborted
+	case api.ClusterMaintenanceActionReady:
+		waiting := slices.ContainsFunc(m.state.Members, func(member api.ClusterMaintenanceMember) bool {
+			return member.Name == req.Member && member.Status == api.ClusterMaintenanceMemberWaiting
+		})
+
+		if !waiting {
+			m.mu.Unlock()
+			return response.BadRequest(fmt.Errorf("Cluster member %q is not waiting to be restored", req.Member))
+		}
+
+		m.ready[req.Member] = true
+	default:
+		m.mu.Unlock()
+		return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
+	}
+
+	m.mu.Unlock()
+	m.notify()
+
+	if action != "" {
+		requestor := request.CreateRequestor(r.Context())
+		s.Events.SendLifecycle(api.ProjectDefaultName, action.Event("maintenance", requestor, nil))
+	}
+
+	return response.EmptySyncResponse
+}
+
+// clusterMaintenanceOperation returns the most recent cluster maintenance operation in progress, if any.
+func clusterMaintenanceOperation(ctx context.Context, s *state.State) (*api.Operation, error) {
+	ops, err := operationsGetByType(ctx, s, "", operationtype.ClusterMaintenance, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var running *api.Operation
+	for _, op := range ops {
+		if op.StatusCode.IsFinal() {
+			continue
+		}
+
+		if running == nil || op.CreatedAt.After(running.CreatedAt) {
+			running = op
+		}
+	}
+
+	return running, nil
+}
+
+// clusterMaintenanceLoad returns the cluster maintenance in progress.
+// A response is returned instead if the request must be forwarded to the coordinating cluster member or if there is no
+// cluster maintenance in progress.
+func clusterMaintenanceLoad(r *http.Request, s *state.State) (*clusterMaintenance, response.Response) {
+	op, err := clusterMaintenanceOperation(r.Context(), s)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	if op == nil {
+		return nil, response.NotFound(errors.New("No cluster maintenance in progress"))
+	}
+
+	resp := forwardedResponseToNode(r.Context(), s, op.Location)
+	if resp != nil {
+		return nil, resp
+	}
+
+	clusterMaintenancesMu.Lock()
+	m := clusterMaintenances[op.ID]
+	clusterMaintenancesMu.Unlock()
+
+	if m == nil {
+		return nil, response.NotFound(errors.New("No cluster maintenance in progress"))
+	}
+
+	return m, nil
+}
+
+// clusterMaintenanceMembers returns the names of the cluster members to go through maintenance, in order.
+// All cluster members are used if none are given. The local cluster member always comes last as the maintenance
+// needs to be handed over to another cluster member before it is evacuated.
+func clusterMaintenanceMembers(ctx context.Context, s *state.State, names []string) ([]string, error) {
+	var members []db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		members, err = tx.GetNodes(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	if len(names) == 0 {
+		for _, member := range members {
+			names = append(names, member.Name)
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if slices.Contains(result, name) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Cluster member %q is listed more than once", name)
+		}
+
+		idx := slices.IndexFunc(members, func(member db.NodeInfo) bool { return member.Name == name })
+		if idx < 0 {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Cluster member %q not found", name)
+		}
+
+		if members[idx].State == db.ClusterMemberStatePending {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Cluster member %q is pending", name)
+		}
+
+		result = append(result, name)
+	}
+
+	idx := slices.Index(result, s.ServerName)
+	if idx >= 0 {
+		result = append(slices.Delete(result, idx, idx+1), s.ServerName)
+	}
+
+	return result, nil
+}
+
+// clusterMaintenanceRun takes the cluster members through maintenance one after the other.
+// Failures pause the maintenance so that the failed step can be retried when the maintenance is resumed.
+func clusterMaintenanceRun(ctx context.Context, s *state.State, op *operations.Operation, m *clusterMaintenance) error {
+	for i := range m.state.Members {
+		for clusterMaintenanceMemberStatus(m, i) != api.ClusterMaintenanceMemberCompleted {
+			err := clusterMaintenanceWaitRunning(ctx, s, op, m)
+			if err != nil {
+				return err
+			}
+
+			name := m.state.Members[i].Name
+			if name == s.ServerName && clusterMaintenanceMemberStatus(m, i) == api.ClusterMaintenanceMemberPending {
+				var target string
+				target, err = clusterMaintenanceHandOver(ctx, s, m, i)
+				if err == nil {
+					clusterMaintenanceUpdate(op, m, func(state *api.ClusterMaintenance) {
+						state.Status = api.ClusterMaintenanceStatusHandedOver
+						state.Location = target
+					})
+
+					reportEvacuationProgress(op, fmt.Sprintf("Handed cluster maintenance over to %q", target))
+					return nil
+				}
+			} else {
+				err = clusterMaintenanceStep(ctx, s, op, m, i)
+			}
+
+			if err != nil {
+				if m.isAborted() {
+					clusterMaintenanceUpdate(op, m, func(state *api.ClusterMaintenance) {
+						state.Status = api.ClusterMaintenanceStatusAborted
+					})
+
+					return errors.New("Cluster maintenance aborted")
+				}
+
+				logger.Warn("Pausing cluster maintenance after failure", logger.Ctx{"member": name, "err": err})
+				clusterMaintenanceUpdate(op, m, func(state *api.ClusterMaintenance) {
+					state.Status = api.ClusterMaintenanceStatusPaused
+					state.Error = err.Error()
+				})
+
+				s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMaintenancePaused.Event("maintenance", op.EventLifecycleRequestor(), map[string]any{"member": name, "error": err.Error()}))
+			}
+		}
+	}
+
+	clusterMaintenanceUpdate(op, m, func(state *api.ClusterMaintenance) {
+		state.Status = api.ClusterMaintenanceStatusCompleted
+	})
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMaintenanceCompleted.Event("maintenance", op.EventLifecycleRequestor(), nil))
+
+	return nil
+}
+
+// clusterMaintenanceStep performs the next maintenance step of a cluster member.
+func clusterMaintenanceStep(ctx context.Context, s *state.State, op *operations.Operation, m *clusterMaintenance, i int) error {
+	name := m.state.Members[i].Name
+	setStatus := func(status string) {
+		clusterMaintenanceUpdate(op, m, func(state *api.ClusterMaintenance) {
+			state.Members[i].Status = status
+		})
+	}
+
+	switch clusterMaintenanceMemberStatus(m, i) {
+	case api.ClusterMaintenanceMemberPending, api.ClusterMaintenanceMemberEvacuating:
+		setStatus(api.ClusterMaintenanceMemberEvacuating)
+
+		member, err := clusterMaintenanceMember(ctx, s, name)
+		if err != nil {
+			return err
+		}
+
+		// A previous attempt may have evacuated the cluster member before failing.
+		if member.State != db.ClusterMemberStateEvacuated {
+			reportEvacuationProgress(op, fmt.Sprintf("Evacuating %q", name))
+			err = clusterMaintenanceMemberAction(ctx, s, member, api.ClusterMemberStatePost{Action: api.ClusterMemberActionEvacuate, Mode: m.state.Mode})
+			if err != nil {
+				return fmt.Errorf("Failed evacuating cluster member %q: %w", name, err)
+			}
+		}
+
+		m.mu.Lock()
+		delete(m.ready, name)
+		m.mu.Unlock()
+
+		setStatus(api.ClusterMaintenanceMemberWaiting)
+	case api.ClusterMaintenanceMemberWaiting:
+		reportEvacuationProgress(op, fmt.Sprintf("Waiting for %q to be ready", name))
+		err := clusterMaintenanceWaitReady(ctx, m, name)
+		if err != nil {
+			return err
+		}
+
+		setStatus(api.ClusterMaintenanceMemberRestoring)
+	case api.ClusterMaintenanceMemberRestoring:
+		reportEvacuationProgress(op, fmt.Sprintf("Waiting for %q to be online", name))
+		member, err := clusterMaintenanceWaitMember(ctx, s, m, name, func(member db.NodeInfo) bool {
+			return !member.IsOffline(s.GlobalConfig.OfflineThreshold())
+		})
+		if err != nil {
+			return err
+		}
+
+		if member.State == db.ClusterMemberStateEvacuated {
+			reportEvacuationProgress(op, fmt.Sprintf("Restoring %q", name))
+			err = clusterMaintenanceMemberAction(ctx, s, member, api.ClusterMemberStatePost{Action: api.ClusterMemberActionRestore})
+			if err != nil {
+				return fmt.Errorf("Failed restoring cluster member %q: %w", name, err)
+			}
+		}
+
+		reportEvacuationProgress(op, fmt.Sprintf("Checking health of %q", name))
+		_, err = clusterMaintenanceWaitMember(ctx, s, m, name, func(member db.NodeInfo) bool {
+			return member.State == db.ClusterMemberStateCreated && !member.IsOffline(s.GlobalConfig.OfflineThreshold())
+		})
+		if err != nil {
+			return fmt.Errorf("Cluster member %q is not healthy after restore: %w", name, err)
+		}
+
+		setStatus(api.ClusterMaintenanceMemberCompleted)
+	}
+
+	return nil
+}
+
+// clusterMaintenanceMemberStatus returns the maintenance status of the cluster member at the given index.
+func clusterMaintenanceMemberStatus(m *clusterMaintenance, i int) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.state.Members[i].Status
+}
+
+// isAborted returns whether the maintenance was aborted.
+func (m *clusterMaintenance) isAborted() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.aborted
+}
+
+// clusterMaintenanceUpdate applies a change to the maintenance progress and reports it in the operation metadata.
+func clusterMaintenanceUpdate(op *operations.Operation, m *clusterMaintenance, update func(state *api.ClusterMaintenance)) {
+	m.mu.Lock()
+	update(&m.state)
+	m.mu.Unlock()
+
+	err := op.ExtendMetadata(map[string]any{"maintenance": m.render()})
+	if err != nil {
+		logger.Warn("Failed updating cluster maintenance operation metadata", logger.Ctx{"err": err})
+	}
+}
+
+// clusterMaintenanceWaitRunning blocks while the maintenance is paused.
+// It returns an error if the maintenance is aborted.
+func clusterMaintenanceWaitRunning(ctx context.Context, s *state.State, op *operations.Operation, m *clusterMaintenance) error {
+	for {
+		m.mu.Lock()
+		aborted := m.aborted
+		status := m.state.Status
+		m.mu.Unlock()
+
+		if aborted {
+			clusterMaintenanceUpdate(op, m, func(state *api.ClusterMaintenance) {
+				state.Status = api.ClusterMaintenanceStatusAborted
+			})
+
+			return errors.New("Cluster maintenance aborted")
+		}
+
+		if status != api.ClusterMaintenanceStatusPaused {
+			return nil
+		}
+
+		reportEvacuationProgress(op, "Paused")
+		select {
+		case <-m.wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.ShutdownCtx.Done():
+			return s.ShutdownCtx.Err()
+		}
+	}
+}
+
+// clusterMaintenanceWaitReady blocks until the cluster member is reported ready or the ready timeout expires.
+// It returns an error if the maintenance is aborted.
+func clusterMaintenanceWaitReady(ctx context.Context, m *clusterMaintenance, name string) error {
+	timer := time.NewTimer(time.Duration(m.state.ReadyTimeout) * time.Second)
+	defer timer.Stop()
+
+	for {
+		m.mu.Lock()
+		ready := m.ready[name]
+		aborted := m.aborted
+		m.mu.Unlock()
+
+		if aborted {
+			return errors.New("Cluster maintenance aborted")
+		}
+
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-m.wake:
+		case <-timer.C:
+			logger.Info("Cluster member not reported ready before timeout, restoring it", logger.Ctx{"member": name})
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// clusterMaintenanceWaitMember polls the cluster member until the check passes or the health timeout expires.
+func clusterMaintenanceWaitMember(ctx context.Context, s *state.State, m *clusterMaintenance, name string, check func(member db.NodeInfo) bool) (*db.NodeInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, clusterMaintenanceHealthTimeout)
+	defer cancel()
+
+	for {
+		member, err := clusterMaintenanceMember(ctx, s, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if check(*member) {
+			return member, nil
+		}
+
+		if m.isAborted() {
+			return nil, errors.New("Cluster maintenance aborted")
+		}
+
+		select {
+		case <-time.After(clusterMaintenancePollInterval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Timed out waiting for cluster member %q: %w", name, ctx.Err())
+		}
+	}
+}
+
+// clusterMaintenanceMember returns the cluster member with the given name.
+func clusterMaintenanceMember(ctx context.Context, s *state.State, name string) (*db.NodeInfo, error) {
+	var member db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		member, err = tx.GetNodeByName(ctx, name)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting cluster member %q: %w", name, err)
+	}
+
+	return &member, nil
+}
+
+// clusterMaintenanceMemberAction evacuates or restores a cluster member through its API and waits for completion.
+func clusterMaintenanceMemberAction(ctx context.Context, s *state.State, member *db.NodeInfo, req api.ClusterMemberStatePost) error {
+	client, err := cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+	if err != nil {
+		return err
+	}
+
+	op, err := client.UpdateClusterMemberState(member.Name, req)
+	if err != nil {
+		return err
+	}
+
+	return op.WaitContext(ctx)
+}
+
+// clusterMaintenanceHandOver hands the remaining cluster members over to another online cluster member, which
+// continues the maintenance. It returns the name of the cluster member now coordinating the maintenance.
+func clusterMaintenanceHandOver(ctx context.Context, s *state.State, m *clusterMaintenance, i int) (string, error) {
+	m.mu.Lock()
+	remaining := make([]string, 0, len(m.state.Members)-i)
+	for _, member := range m.state.Members[i:] {
+		remaining = append(remaining, member.Name)
+	}
+
+	req := api.ClusterMaintenancePost{
+		Members:      remaining,
+		Mode:         m.state.Mode,
+		ReadyTimeout: m.state.ReadyTimeout,
+	}
+
+	m.mu.Unlock()
+
+	var target *db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		members, err := tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			if slices.Contains(remaining, member.Name) || member.State != db.ClusterMemberStateCreated || member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+				continue
+			}
+
+			target = &member
+			return nil
+		}
+
+		return errors.New("No online cluster member to hand the cluster maintenance over to")
+	})
+	if err != nil {
+		return "", err
+	}
+
+	client, err := cluster.Connect(ctx, target.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = client.StartClusterMaintenance(req)
+	if err != nil {
+		return "", fmt.Errorf("Failed handing cluster maintenance over to %q: %w", target.Name, err)
+	}
+
+	return target.Name, nil
+}
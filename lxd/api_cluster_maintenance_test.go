@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestClusterMaintenanceWaitReady(t *testing.T) {
+	t.Parallel()
+
+	newMaintenance := func(readyTimeout int64) *clusterMaintenance {
+		return &clusterMaintenance{
+			state: api.ClusterMaintenance{
+				ReadyTimeout: readyTimeout,
+				Members:      []api.ClusterMaintenanceMember{{Name: "m1", Status: api.ClusterMaintenanceMemberWaiting}},
+			},
+			ready: map[string]bool{},
+			wake:  make(chan struct{}, 1),
+		}
+	}
+
+	t.Run("No ready timeout", func(t *testing.T) {
+		m := newMaintenance(0)
+		assert.NoError(t, clusterMaintenanceWaitReady(context.Background(), m, "m1"))
+	})
+
+	t.Run("Reported ready", func(t *testing.T) {
+		m := newMaintenance(3600)
+		go func() {
+			m.mu.Lock()
+			m.ready["m1"] = true
+			m.mu.Unlock()
+			m.notify()
+		}()
+
+		assert.NoError(t, clusterMaintenanceWaitReady(context.Background(), m, "m1"))
+	})
+
+	t.Run("Other member reported ready", func(t *testing.T) {
+		m := newMaintenance(3600)
+		m.ready["m2"] = true
+		m.notify()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, clusterMaintenanceWaitReady(ctx, m, "m1"), context.DeadlineExceeded)
+	})
+
+	t.Run("Aborted", func(t *testing.T) {
+		m := newMaintenance(3600)
+		go func() {
+			m.mu.Lock()
+			m.aborted = true
+			m.mu.Unlock()
+			m.notify()
+		}()
+
+		assert.EqualError(t, clusterMaintenanceWaitReady(context.Background(), m, "m1"), "Cluster maintenance aborted")
+	})
+}
@@ -1922,76 +1922,89 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 
 func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst instance.Instance, pgCache *placement.Cache) (*db.NodeInfo, error) {
 	var targetMemberInfo *db.NodeInfo
-	var candidateMembers []db.NodeInfo
 
-	// Get candidate cluster members to move instances to.
 	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-		allMembers, err := tx.GetNodes(ctx)
+		candidateMembers, err := clusterInstanceCandidateMembers(ctx, tx, s, inst, pgCache, true)
 		if err != nil {
-			return fmt.Errorf("Failed getting cluster members: %w", err)
+			return err
 		}
 
-		// Placement groups and cluster group targets are mutually exclusive, with placement groups taking precedence.
-		_, clusterGroupName := limits.TargetDetect(inst.LocalConfig()["volatile.cluster.group"])
-		placementGroupName, ok := inst.ExpandedConfig()["placement.group"]
-
-		instProject := inst.Project()
-		clusterGroupsAllowed := limits.GetRestrictedClusterGroups(&instProject)
-
-		// Filter offline servers.
-		candidateMembers, err = tx.GetCandidateMembers(ctx, allMembers, []int{inst.Architecture()}, "", clusterGroupsAllowed, s.GlobalConfig.OfflineThreshold())
+		// Find the least loaded cluster member which supports the instance's architecture.
+		targetMemberInfo, err = tx.GetNodeWithLeastInstances(ctx, candidateMembers)
 		if err != nil {
 			return err
 		}
 
-		if ok {
-			// Filter candidates by placement group.
-			apiPlacementGroup, err := pgCache.Get(ctx, tx, placementGroupName, inst.Project().Name)
-			if err != nil {
-				return err
-			}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			filteredCandidates, err := placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, true)
-			if err != nil {
-				// If no candidates remain due to placement constraints, signal not found so caller can skip instance during evacuation.
-				if api.StatusErrorCheck(err, http.StatusConflict) {
-					return api.StatusErrorf(http.StatusNotFound, "No eligible target cluster members after applying placement group %q", apiPlacementGroup.Name)
-				}
+	return targetMemberInfo, nil
+}
 
-				return err
-			}
+// clusterInstanceCandidateMembers returns the online cluster members that an instance can be moved to, according to
+// its architecture, project restrictions, placement group and cluster group.
+// During evacuation, the instances of the local member are ignored when applying placement group policies.
+func clusterInstanceCandidateMembers(ctx context.Context, tx *db.ClusterTx, s *state.State, inst instance.Instance, pgCache *placement.Cache, evacuation bool) ([]db.NodeInfo, error) {
+	allMembers, err := tx.GetNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting cluster members: %w", err)
+	}
 
-			// If placement group filtering returns candidates, use them.
-			if len(filteredCandidates) > 0 {
-				candidateMembers = filteredCandidates
-			}
-		} else if clusterGroupName != "" {
-			// Filter candidates by cluster group.
-			newMembers := make([]db.NodeInfo, 0, len(candidateMembers))
-			for _, member := range candidateMembers {
-				if !slices.Contains(member.Groups, clusterGroupName) {
-					continue
-				}
+	// Placement groups and cluster group targets are mutually exclusive, with placement groups taking precedence.
+	_, clusterGroupName := limits.TargetDetect(inst.LocalConfig()["volatile.cluster.group"])
+	placementGroupName, err := pgCache.GroupName(ctx, tx, inst.Project().Name, inst.ExpandedConfig())
+	if err != nil {
+		return nil, err
+	}
 
-				newMembers = append(newMembers, member)
-			}
+	instProject := inst.Project()
+	clusterGroupsAllowed := limits.GetRestrictedClusterGroups(&instProject)
 
-			candidateMembers = newMembers
+	// Filter offline servers.
+	candidateMembers, err := tx.GetCandidateMembers(ctx, allMembers, []int{inst.Architecture()}, "", clusterGroupsAllowed, s.GlobalConfig.OfflineThreshold())
+	if err != nil {
+		return nil, err
+	}
+
+	if placementGroupName != "" {
+		// Filter candidates by placement group.
+		apiPlacementGroup, err := pgCache.Get(ctx, tx, placementGroupName, inst.Project().Name)
+		if err != nil {
+			return nil, err
 		}
 
-		// Find the least loaded cluster member which supports the instance's architecture.
-		targetMemberInfo, err = tx.GetNodeWithLeastInstances(ctx, candidateMembers)
+		filteredCandidates, err := placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, evacuation)
 		if err != nil {
-			return err
+			// If no candidates remain due to placement constraints, signal not found so caller can skip the instance.
+			if api.StatusErrorCheck(err, http.StatusConflict) {
+				return nil, api.StatusErrorf(http.StatusNotFound, "No eligible target cluster members after applying placement group %q", apiPlacementGroup.Name)
+			}
+
+			return nil, err
 		}
 
-		return nil
-	})
-	if err != nil {
-		return nil, err
+		// If placement group filtering returns candidates, use them.
+		if len(filteredCandidates) > 0 {
+			candidateMembers = filteredCandidates
+		}
+	} else if clusterGroupName != "" {
+		// Filter candidates by cluster group.
+		newMembers := make([]db.NodeInfo, 0, len(candidateMembers))
+		for _, member := range candidateMembers {
+			if !slices.Contains(member.Groups, clusterGroupName) {
+				continue
+			}
+
+			newMembers = append(newMembers, member)
+		}
+
+		candidateMembers = newMembers
 	}
 
-	return targetMemberInfo, nil
+	return candidateMembers, nil
 }
 
 func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Response {
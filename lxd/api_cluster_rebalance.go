@@ -0,0 +1,604 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/placement"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+)
+
+const clusterInstanceRebalanceConflictReference = "cluster-instance-rebalance"
+
+var clusterInstanceRebalanceCmd = APIEndpoint{
+	Path:        "cluster/instance-rebalance",
+	MetricsType: entity.TypeClusterMember,
+
+	Get:  APIEndpointAction{Handler: clusterInstanceRebalanceGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewResources)},
+	Post: APIEndpointAction{Handler: clusterInstanceRebalancePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// rebalanceCandidate is an instance that the rebalancer is allowed to move.
+type rebalanceCandidate struct {
+	project string
+	name    string
+	member  string
+	live    bool
+
+	// placementGroup is the project qualified placement group of the instance, if any.
+	placementGroup string
+
+	// targets are the cluster members the instance can be moved to.
+	targets []string
+}
+
+// swagger:operation GET /1.0/cluster/instance-rebalance cluster cluster_instance_rebalance_get
+//
+//	Get the instance rebalancing plan
+//
+//	Returns the current load of the cluster members and the instance moves that a rebalancing run would perform.
+//	No instance is moved.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Rebalancing plan
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterRebalancePlan"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterInstanceRebalanceGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	plan, _, err := clusterInstanceRebalancePlan(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, plan)
+}
+
+// swagger:operation POST /1.0/cluster/instance-rebalance cluster cluster_instance_rebalance_post
+//
+//	Rebalance the instances
+//
+//	Moves instances between cluster members to even out their load, as described by the rebalancing plan.
+//	Only instances with `cluster.rebalance` enabled are moved.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterInstanceRebalancePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		return clusterInstanceRebalance(ctx, s, op)
+	}
+
+	args := operations.OperationArgs{
+		ProjectName:       "",
+		Type:              operationtype.ClusterInstanceRebalance,
+		Class:             operationtype.OperationClassTask,
+		RunHook:           run,
+		ConflictReference: clusterInstanceRebalanceConflictReference,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// clusterInstanceRebalanceTask periodically rebalances instances across cluster members on the leader, based on
+// the cluster.rebalance.interval setting.
+func clusterInstanceRebalanceTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	var lastRun time.Time
+
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		interval, _, _, _ := s.GlobalConfig.ClusterRebalance()
+		if interval == 0 || time.Since(lastRun) < interval {
+			return
+		}
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil || !leaderInfo.Clustered || !leaderInfo.Leader {
+			return
+		}
+
+		lastRun = time.Now()
+
+		opRun := func(ctx context.Context, op *operations.Operation) error {
+			return clusterInstanceRebalance(ctx, s, op)
+		}
+
+		args := operations.OperationArgs{
+			Type:              operationtype.ClusterInstanceRebalance,
+			Class:             operationtype.OperationClassTask,
+			RunHook:           opRun,
+			ConflictReference: clusterInstanceRebalanceConflictReference,
+		}
+
+		op, err := operations.ScheduleServerOperation(s, args)
+		if err != nil {
+			logger.Warn("Failed creating cluster instance rebalance operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed rebalancing cluster instances", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute, task.SkipFirst)
+}
+
+// clusterInstanceRebalance moves the instances of the rebalancing plan to their new cluster member.
+func clusterInstanceRebalance(ctx context.Context, s *state.State, op *operations.Operation) error {
+	plan, candidates, err := clusterInstanceRebalancePlan(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	for _, move := range plan.Moves {
+		l := logger.AddContext(logger.Ctx{"project": move.Project, "instance": move.Instance, "source": move.Source, "target": move.Target})
+
+		inst := candidates[move.Project+"/"+move.Instance]
+		if inst == nil {
+			continue
+		}
+
+		reportEvacuationProgress(op, fmt.Sprintf("Moving %q in project %q to %q", move.Instance, move.Project, move.Target))
+
+		err := clusterInstanceRebalanceMove(ctx, s, inst, move)
+		if err != nil {
+			return err
+		}
+
+		l.Info("Moved instance to rebalance cluster load")
+	}
+
+	return nil
+}
+
+// clusterInstanceRebalanceMove moves an instance to the target member of the move.
+// Instances that can't be live-migrated are stopped during the move and started again on the target member, or on the
+// source member if the move fails.
+func clusterInstanceRebalanceMove(ctx context.Context, s *state.State, inst instance.Instance, move api.ClusterRebalanceMove) error {
+	var target db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		target, err = tx.GetNodeByName(ctx, move.Target)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading cluster member %q: %w", move.Target, err)
+	}
+
+	dest, err := cluster.Connect(ctx, target.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to destination %q: %w", target.Address, err)
+	}
+
+	dest = dest.UseProject(move.Project)
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	running := inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning
+	if running && !move.Live {
+		stopOp, err := dest.UpdateInstanceState(move.Instance, api.InstanceStatePut{Action: "stop", Timeout: -1}, "")
+		if err != nil {
+			return fmt.Errorf("Failed stopping instance %q in project %q: %w", move.Instance, move.Project, err)
+		}
+
+		err = stopOp.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed stopping instance %q in project %q: %w", move.Instance, move.Project, err)
+		}
+
+		// Start the instance again on the source member if it couldn't be moved.
+		// The request is forwarded to the member currently holding the instance.
+		reverter.Add(func() {
+			startOp, err := dest.UpdateInstanceState(move.Instance, api.InstanceStatePut{Action: "start"}, "")
+			if err == nil {
+				err = startOp.Wait()
+			}
+
+			if err != nil {
+				logger.Warn("Failed restarting instance after failed move", logger.Ctx{"project": move.Project, "instance": move.Instance, "err": err})
+			}
+		})
+	}
+
+	req := api.InstancePost{
+		Name:      move.Instance,
+		Migration: true,
+		Live:      move.Live,
+	}
+
+	migrateOp, err := dest.UseTarget(move.Target).MigrateInstance(move.Instance, req)
+	if err != nil {
+		return fmt.Errorf("Failed moving instance %q in project %q: %w", move.Instance, move.Project, err)
+	}
+
+	err = migrateOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed moving instance %q in project %q: %w", move.Instance, move.Project, err)
+	}
+
+	reverter.Success()
+
+	// Record the move only once the instance is on the target member so that failed moves don't start the cooldown.
+	// The instance record may have been recreated by the migration, so look it up again.
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err := tx.GetInstanceID(ctx, move.Project, move.Instance)
+		if err != nil {
+			return err
+		}
+
+		return tx.UpdateInstanceConfig(id, map[string]string{"volatile.rebalance.last_move": time.Now().UTC().Format(time.RFC3339)})
+	})
+	if err != nil {
+		return fmt.Errorf("Failed recording move of instance %q in project %q: %w", move.Instance, move.Project, err)
+	}
+
+	if !running || move.Live {
+		return nil
+	}
+
+	startOp, err := dest.UpdateInstanceState(move.Instance, api.InstanceStatePut{Action: "start"}, "")
+	if err != nil {
+		return fmt.Errorf("Failed starting instance %q in project %q: %w", move.Instance, move.Project, err)
+	}
+
+	return startOp.Wait()
+}
+
+// clusterInstanceRebalancePlan gathers the load of the cluster members and plans the instance moves needed to even
+// it out. It also returns the instances that can be moved, indexed by "<project>/<name>".
+func clusterInstanceRebalancePlan(ctx context.Context, s *state.State) (*api.ClusterRebalancePlan, map[string]instance.Instance, error) {
+	_, threshold, batch, cooldown := s.GlobalConfig.ClusterRebalance()
+
+	var members []db.NodeInfo
+	var remotePools []string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		allMembers, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		for _, member := range allMembers {
+			if member.State == db.ClusterMemberStateEvacuated || member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+				continue
+			}
+
+			members = append(members, member)
+		}
+
+		pools, _, err := tx.GetStoragePools(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("Failed getting storage pools: %w", err)
+		}
+
+		for _, pool := range pools {
+			if slices.Contains(storageDrivers.RemoteDriverNames(), pool.Driver) {
+				remotePools = append(remotePools, pool.Name)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memberStates, err := cluster.ClusterState(s, s.Endpoints.NetworkCert(), members...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed getting cluster member state: %w", err)
+	}
+
+	loads := make(map[string]*api.ClusterRebalanceMember, len(memberStates))
+	for name, memberState := range memberStates {
+		loads[name] = clusterMemberLoad(name, memberState, remotePools)
+	}
+
+	instances := map[string]instance.Instance{}
+	candidates := []rebalanceCandidate{}
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var movable []instance.Instance
+		err := tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			load, ok := loads[dbInst.Node]
+			if !ok {
+				return nil
+			}
+
+			load.Instances++
+
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+			}
+
+			if !shared.IsTrue(inst.ExpandedConfig()["cluster.rebalance"]) {
+				return nil
+			}
+
+			// Skip instances that were moved recently to avoid moving them back and forth.
+			lastMove, err := time.Parse(time.RFC3339, inst.LocalConfig()["volatile.rebalance.last_move"])
+			if err == nil {
+				cooldownEnd, err := shared.GetExpiry(lastMove, cooldown)
+				if err == nil && time.Now().Before(cooldownEnd) {
+					return nil
+				}
+			}
+
+			movable = append(movable, inst)
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Failed getting instances: %w", err)
+		}
+
+		pgCache := placement.NewCache()
+		for _, inst := range movable {
+			migrate, live := inst.CanMigrate()
+			if !migrate {
+				continue
+			}
+
+			targetMembers, err := clusterInstanceCandidateMembers(ctx, tx, s, inst, pgCache, false)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					continue
+				}
+
+				return err
+			}
+
+			candidate := rebalanceCandidate{
+				project: inst.Project().Name,
+				name:    inst.Name(),
+				member:  inst.Location(),
+				live:    live && inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning,
+			}
+
+			placementGroup, err := pgCache.GroupName(ctx, tx, candidate.project, inst.ExpandedConfig())
+			if err != nil {
+				return err
+			}
+
+			if placementGroup != "" {
+				candidate.placementGroup = candidate.project + "/" + placementGroup
+			}
+
+			for _, member := range targetMembers {
+				_, ok := loads[member.Name]
+				if ok && member.Name != candidate.member {
+					candidate.targets = append(candidate.targets, member.Name)
+				}
+			}
+
+			candidates = append(candidates, candidate)
+			instances[candidate.project+"/"+candidate.name] = inst
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan := &api.ClusterRebalancePlan{
+		Members: make([]api.ClusterRebalanceMember, 0, len(loads)),
+	}
+
+	for _, load := range loads {
+		plan.Members = append(plan.Members, *load)
+	}
+
+	sort.Slice(plan.Members, func(i, j int) bool { return plan.Members[i].Name < plan.Members[j].Name })
+
+	plan.Moves = planInstanceRebalance(plan.Members, candidates, float64(threshold), int(batch))
+
+	return plan, instances, nil
+}
+
+// clusterMemberLoad returns the load of a cluster member from its state.
+// Remote storage pools are ignored as their usage is the same on all members.
+func clusterMemberLoad(name string, memberState api.ClusterMemberState, remotePools []string) *api.ClusterRebalanceMember {
+	load := &api.ClusterRebalanceMember{Name: name}
+
+	sysInfo := memberState.SysInfo
+	if sysInfo.LogicalCPUs > 0 && len(sysInfo.LoadAverages) > 1 {
+		load.CPU = math.Min(100, sysInfo.LoadAverages[1]/float64(sysInfo.LogicalCPUs)*100)
+	}
+
+	if sysInfo.TotalRAM > 0 {
+		used := sysInfo.TotalRAM - min(sysInfo.TotalRAM, sysInfo.FreeRAM+sysInfo.BufferRAM)
+		load.Memory = float64(used) / float64(sysInfo.TotalRAM) * 100
+	}
+
+	for poolName, poolState := range memberState.StoragePools {
+		if slices.Contains(remotePools, poolName) || poolState.Space.Total == 0 {
+			continue
+		}
+
+		load.Storage = math.Max(load.Storage, float64(poolState.Space.Used)/float64(poolState.Space.Total)*100)
+	}
+
+	load.Score = (load.CPU + load.Memory + load.Storage) / 3
+
+	return load
+}
+
+// planInstanceRebalance plans up to batch instance moves from the most loaded to the least loaded cluster members,
+// as long as the difference between their load scores is at least threshold.
+// The load of an instance is estimated as an equal share of the load of its cluster member.
+// To respect the placement group policies, at most one instance of each placement group is moved per run.
+func planInstanceRebalance(members []api.ClusterRebalanceMember, candidates []rebalanceCandidate, threshold float64, batch int) []api.ClusterRebalanceMove {
+	moves := []api.ClusterRebalanceMove{}
+
+	scores := make(map[string]float64, len(members))
+	counts := make(map[string]int, len(members))
+	for _, member := range members {
+		scores[member.Name] = member.Score
+		counts[member.Name] = member.Instances
+	}
+
+	// Consider the candidates in a stable order.
+	candidates = slices.Clone(candidates)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].project != candidates[j].project {
+			return candidates[i].project < candidates[j].project
+		}
+
+		return candidates[i].name < candidates[j].name
+	})
+
+	moved := map[int]bool{}
+	movedGroups := map[string]bool{}
+
+	for len(moves) < batch {
+		// Order the members from the most to the least loaded.
+		sources := make([]string, 0, len(scores))
+		for name := range scores {
+			sources = append(sources, name)
+		}
+
+		sort.Slice(sources, func(i, j int) bool {
+			if scores[sources[i]] != scores[sources[j]] {
+				return scores[sources[i]] > scores[sources[j]]
+			}
+
+			return sources[i] < sources[j]
+		})
+
+		found := false
+		for _, source := range sources {
+			if counts[source] == 0 {
+				continue
+			}
+
+			weight := scores[source] / float64(counts[source])
+
+			for i, candidate := range candidates {
+				if moved[i] || candidate.member != source || (candidate.placementGroup != "" && movedGroups[candidate.placementGroup]) {
+					continue
+				}
+
+				// Pick the least loaded eligible target.
+				target := ""
+				for _, name := range candidate.targets {
+					_, ok := scores[name]
+					if !ok {
+						continue
+					}
+
+					if target == "" || scores[name] < scores[target] || (scores[name] == scores[target] && name < target) {
+						target = name
+					}
+				}
+
+				// Only move if the members are unbalanced enough and the move doesn't reverse the imbalance.
+				if target == "" || scores[source]-scores[target] < threshold || scores[target]+weight >= scores[source] {
+					continue
+				}
+
+				moves = append(moves, api.ClusterRebalanceMove{
+					Instance: candidate.name,
+					Project:  candidate.project,
+					Source:   source,
+					Target:   target,
+					Live:     candidate.live,
+				})
+
+				scores[source] -= weight
+				scores[target] += weight
+				counts[source]--
+				counts[target]++
+				moved[i] = true
+				if candidate.placementGroup != "" {
+					movedGroups[candidate.placementGroup] = true
+				}
+
+				found = true
+				break
+			}
+
+			if found {
+				break
+			}
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return moves
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestPlanInstanceRebalance(t *testing.T) {
+	t.Parallel()
+
+	members := []api.ClusterRebalanceMember{
+		{Name: "m1", Score: 80, Instances: 4},
+		{Name: "m2", Score: 20, Instances: 2},
+		{Name: "m3", Score: 40, Instances: 2},
+	}
+
+	tests := []struct {
+		name       string
+		candidates []rebalanceCandidate
+		threshold  float64
+		batch      int
+		want       []api.ClusterRebalanceMove
+	}{
+		{
+			name:       "No candidates",
+			candidates: nil,
+			threshold:  20,
+			batch:      5,
+			want:       []api.ClusterRebalanceMove{},
+		},
+		{
+			name: "Move to least loaded member",
+			candidates: []rebalanceCandidate{
+				{project: "default", name: "c1", member: "m1", live: true, targets: []string{"m2", "m3"}},
+			},
+			threshold: 20,
+			batch:     5,
+			want: []api.ClusterRebalanceMove{
+				{Instance: "c1", Project: "default", Source: "m1", Target: "m2", Live: true},
+			},
+		},
+		{
+			name: "Batch limit",
+			candidates: []rebalanceCandidate{
+				{project: "default", name: "c1", member: "m1", targets: []string{"m2", "m3"}},
+				{project: "default", name: "c2", member: "m1", targets: []string{"m2", "m3"}},
+			},
+			threshold: 10,
+			batch:     1,
+			want: []api.ClusterRebalanceMove{
+				{Instance: "c1", Project: "default", Source: "m1", Target: "m2"},
+			},
+		},
+		{
+			name: "Stop once balanced",
+			candidates: []rebalanceCandidate{
+				{project: "default", name: "c1", member: "m1", targets: []string{"m2", "m3"}},
+				{project: "default", name: "c2", member: "m1", targets: []string{"m2", "m3"}},
+				{project: "default", name: "c3", member: "m1", targets: []string{"m2", "m3"}},
+				{project: "default", name: "c4", member: "m1", targets: []string{"m2", "m3"}},
+			},
+			threshold: 20,
+			batch:     10,
+			// A second move would make m2 as loaded as m1 is after the first move.
+			want: []api.ClusterRebalanceMove{
+				{Instance: "c1", Project: "default", Source: "m1", Target: "m2"},
+			},
+		},
+		{
+			name: "Below threshold",
+			candidates: []rebalanceCandidate{
+				{project: "default", name: "c1", member: "m1", targets: []string{"m2", "m3"}},
+			},
+			threshold: 70,
+			batch:     5,
+			want:      []api.ClusterRebalanceMove{},
+		},
+		{
+			name: "Restricted targets",
+			candidates: []rebalanceCandidate{
+				{project: "default", name: "c1", member: "m1", targets: []string{"m3"}},
+			},
+			threshold: 20,
+			batch:     5,
+			want: []api.ClusterRebalanceMove{
+				{Instance: "c1", Project: "default", Source: "m1", Target: "m3"},
+			},
+		},
+		{
+			name: "One move per placement group",
+			candidates: []rebalanceCandidate{
+				{project: "default", name: "c1", member: "m1", placementGroup: "default/pg", targets: []string{"m2", "m3"}},
+				{project: "default", name: "c2", member: "m1", placementGroup: "default/pg", targets: []string{"m2", "m3"}},
+			},
+			threshold: 10,
+			batch:     5,
+			want: []api.ClusterRebalanceMove{
+				{Instance: "c1", Project: "default", Source: "m1", Target: "m2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := planInstanceRebalance(members, tt.candidates, tt.threshold, tt.batch)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClusterMemberLoad(t *testing.T) {
+	t.Parallel()
+
+	memberState := api.ClusterMemberState{
+		SysInfo: api.ClusterMemberSysInfo{
+			LoadAverages: []float64{1, 2, 3},
+			LogicalCPUs:  4,
+			TotalRAM:     1000,
+			FreeRAM:      200,
+			BufferRAM:    100,
+		},
+		StoragePools: map[string]api.StoragePoolState{
+			"local":  {ResourcesStoragePool: api.ResourcesStoragePool{Space: api.ResourcesStoragePoolSpace{Used: 60, Total: 100}}},
+			"remote": {ResourcesStoragePool: api.ResourcesStoragePool{Space: api.ResourcesStoragePoolSpace{Used: 90, Total: 100}}},
+		},
+	}
+
+	load := clusterMemberLoad("m1", memberState, []string{"remote"})
+	assert.Equal(t, "m1", load.Name)
+	assert.InDelta(t, 50, load.CPU, 0.001)
+	assert.InDelta(t, 70, load.Memory, 0.001)
+	assert.InDelta(t, 60, load.Storage, 0.001)
+	assert.InDelta(t, 60, load.Score, 0.001)
+}
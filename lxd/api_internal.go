@@ -46,6 +46,7 @@ var apiInternal = []APIEndpoint{
 	internalBGPStateCmd,
 	internalClusterAcceptCmd,
 	internalClusterAssignCmd,
+	internalClusterDatabaseBackupCmd,
 	internalClusterHandoverCmd,
 	internalClusterHealCmd,
 	internalClusterLinkRefreshVolatileAddressesCmd,
@@ -584,68 +584,88 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				}
 
 				// Recover instance volumes and any snapshots.
-				profiles := make([]api.Profile, 0, len(poolVol.Instance.Profiles))
-				for _, profileName := range poolVol.Instance.Profiles {
-					for i := range projectProfiles[profileProjectName] {
-						if projectProfiles[profileProjectName][i].Name == profileName {
-							profiles = append(profiles, *projectProfiles[profileProjectName][i])
-						}
-					}
-				}
-
-				inst, cleanup, err := internalRecoverImportInstance(ctx, s, pool, projectName, poolVol, profiles)
+				cleanup, err := internalRecoverInstance(ctx, s, pool, projectName, poolVol, projectProfiles[profileProjectName])
 				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed creating instance %q record in project %q: %w", poolVol.Instance.Name, projectName, err))
+					return response.SmartError(err)
 				}
 
 				revert.Add(cleanup)
+			}
+		}
+	}
 
-				// Recover instance volume snapshots.
-				for i, poolInstSnap := range poolVol.Snapshots {
-					if poolInstSnap == nil {
-						return response.SmartError(fmt.Errorf("Nil instance volume snapshot definition found at index %d for instance %q record in project %q", i, poolVol.Instance.Name, projectName))
-					}
+	revert.Success()
+	return response.EmptySyncResponse
+}
 
-					profiles := make([]api.Profile, 0, len(poolInstSnap.Profiles))
-					for _, profileName := range poolInstSnap.Profiles {
-						for i := range projectProfiles[profileProjectName] {
-							if projectProfiles[profileProjectName][i].Name == profileName {
-								profiles = append(profiles, *projectProfiles[profileProjectName][i])
-							}
-						}
-					}
+// internalRecoverInstance recreates the database records of an instance and its snapshots from the backup config
+// of its volume, then recreates its mount paths and reinitialises its root disk quota.
+// The profiles used by the instance and its snapshots are looked up in the provided project profiles.
+// Returns a revert fail function that can be used to undo this function if a subsequent step fails.
+func internalRecoverInstance(ctx context.Context, s *state.State, pool storagePools.Pool, projectName string, poolVol *backupConfig.Config, projectProfiles []*api.Profile) (revert.Hook, error) {
+	revert := revert.New()
+	defer revert.Fail()
 
-					cleanup, err := internalRecoverImportInstanceSnapshot(ctx, s, pool, projectName, poolVol, poolInstSnap, profiles)
-					if err != nil {
-						return response.SmartError(fmt.Errorf("Failed creating instance %q snapshot %q record in project %q: %w", poolVol.Instance.Name, poolInstSnap.Name, projectName, err))
-					}
+	profiles := make([]api.Profile, 0, len(poolVol.Instance.Profiles))
+	for _, profileName := range poolVol.Instance.Profiles {
+		for i := range projectProfiles {
+			if projectProfiles[i].Name == profileName {
+				profiles = append(profiles, *projectProfiles[i])
+			}
+		}
+	}
 
-					revert.Add(cleanup)
-				}
+	inst, cleanup, err := internalRecoverImportInstance(ctx, s, pool, projectName, poolVol, profiles)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating instance %q record in project %q: %w", poolVol.Instance.Name, projectName, err)
+	}
 
-				// Recreate instance mount path and symlinks (must come after snapshot recovery).
-				cleanup, err = pool.ImportInstance(inst, poolVol, nil)
-				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed importing instance %q in project %q: %w", poolVol.Instance.Name, projectName, err))
-				}
+	revert.Add(cleanup)
 
-				revert.Add(cleanup)
+	// Recover instance volume snapshots.
+	for i, poolInstSnap := range poolVol.Snapshots {
+		if poolInstSnap == nil {
+			return nil, fmt.Errorf("Nil instance volume snapshot definition found at index %d for instance %q record in project %q", i, poolVol.Instance.Name, projectName)
+		}
 
-				// Reinitialise the instance's root disk quota even if no size specified (allows the storage driver the
-				// opportunity to reinitialise the quota based on the new storage volume's DB ID).
-				_, rootConfig, err := api.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
-				if err == nil {
-					err = pool.SetInstanceQuota(inst, rootConfig["size"], rootConfig["size.state"], nil)
-					if err != nil {
-						return response.SmartError(fmt.Errorf("Failed reinitializing root disk quota %q for instance %q in project %q: %w", rootConfig["size"], poolVol.Instance.Name, projectName, err))
-					}
+		profiles := make([]api.Profile, 0, len(poolInstSnap.Profiles))
+		for _, profileName := range poolInstSnap.Profiles {
+			for i := range projectProfiles {
+				if projectProfiles[i].Name == profileName {
+					profiles = append(profiles, *projectProfiles[i])
 				}
 			}
 		}
+
+		cleanup, err := internalRecoverImportInstanceSnapshot(ctx, s, pool, projectName, poolVol, poolInstSnap, profiles)
+		if err != nil {
+			return nil, fmt.Errorf("Failed creating instance %q snapshot %q record in project %q: %w", poolVol.Instance.Name, poolInstSnap.Name, projectName, err)
+		}
+
+		revert.Add(cleanup)
 	}
 
+	// Recreate instance mount path and symlinks (must come after snapshot recovery).
+	cleanup, err = pool.ImportInstance(inst, poolVol, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed importing instance %q in project %q: %w", poolVol.Instance.Name, projectName, err)
+	}
+
+	revert.Add(cleanup)
+
+	// Reinitialise the instance's root disk quota even if no size specified (allows the storage driver the
+	// opportunity to reinitialise the quota based on the new storage volume's DB ID).
+	_, rootConfig, err := api.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
+	if err == nil {
+		err = pool.SetInstanceQuota(inst, rootConfig["size"], rootConfig["size.state"], nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed reinitializing root disk quota %q for instance %q in project %q: %w", rootConfig["size"], poolVol.Instance.Name, projectName, err)
+		}
+	}
+
+	cleanup = revert.Clone().Fail
 	revert.Success()
-	return response.EmptySyncResponse
+	return cleanup, nil
 }
 
 // internalRecoverImportInstance recreates the database records for an instance and returns the new instance.
@@ -37,6 +37,13 @@ type metricsCacheEntry struct {
 var metricsCache map[string]metricsCacheEntry
 var metricsCacheLock sync.Mutex
 
+// replicatorMetricsCache holds the replication lag metrics of this member.
+var replicatorMetricsCache metricsCacheEntry
+var replicatorMetricsCacheLock sync.Mutex
+
+// replicatorMetricsCacheDuration is how long the replication lag metrics are cached for.
+const replicatorMetricsCacheDuration = 8 * time.Second
+
 var metricsCmd = APIEndpoint{
 	Path:        "metrics",
 	MetricsType: entity.TypeServer,
@@ -430,6 +437,9 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 		out.AddSamples(metrics.OperationsTotal, metrics.Sample{Value: float64(len(operations))})
 	}
 
+	// Replication lag of the instances on this member
+	out.Merge(replicatorMetrics(ctx, s, tx))
+
 	// API request metrics
 	for _, entityType := range entity.APIMetricsEntityTypes() {
 		out.AddSamples(
@@ -487,3 +497,50 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 
 	return out
 }
+
+// replicatorMetrics returns the replication lag of the instances on this member for the replicators of leader projects.
+// Instances that were never replicated have no lag sample as they have no replica to compare against. The metrics are
+// cached for a short time to avoid loading the replication state from the database on every scrape.
+func replicatorMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *metrics.MetricSet {
+	replicatorMetricsCacheLock.Lock()
+	defer replicatorMetricsCacheLock.Unlock()
+
+	if replicatorMetricsCache.metrics != nil && replicatorMetricsCache.expiry.After(time.Now()) {
+		return replicatorMetricsCache.metrics
+	}
+
+	out := metrics.NewMetricSet(nil)
+
+	replicators, replicatorRows, projectModes, err := replicatorsLoad(ctx, tx)
+	if err != nil {
+		logger.Warn("Failed getting replicators", logger.Ctx{"err": err})
+		return out
+	}
+
+	for i, replicator := range replicators {
+		if projectModes[replicator.Project] != api.ReplicatorProjectModeLeader {
+			continue
+		}
+
+		state, err := replicatorLoadState(ctx, tx, replicator, replicatorRows[i].Row.ID, &s.ServerName)
+		if err != nil {
+			logger.Warn("Failed getting replicator state", logger.Ctx{"replicator": replicator.Name, "project": replicator.Project, "err": err})
+			continue
+		}
+
+		for instName, instState := range state.Instances {
+			if instState.LastReplicatedAt.IsZero() {
+				continue
+			}
+
+			out.AddSamples(metrics.ReplicatorLagSeconds, metrics.Sample{
+				Labels: map[string]string{"project": replicator.Project, "replicator": replicator.Name, "name": instName},
+				Value:  float64(instState.Lag),
+			})
+		}
+	}
+
+	replicatorMetricsCache = metricsCacheEntry{metrics: out, expiry: time.Now().Add(replicatorMetricsCacheDuration)}
+
+	return out
+}
@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/auth"
@@ -336,6 +337,20 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Volatile keys are only set by LXD itself.
+	if !requestor.IsClusterNotification() {
+		for key := range project.Config {
+			if strings.HasPrefix(key, "volatile.") {
+				return response.BadRequest(fmt.Errorf("Volatile project configuration key %q cannot be set", key))
+			}
+		}
+	}
+
 	// Validate the configuration.
 	err = projectValidateConfig(r.Context(), s, project.Config, project.Network, project.Name)
 	if err != nil {
@@ -344,37 +359,54 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 
 	// Extend the node config schema with the project-specific config keys.
 	// Otherwise the node config schema validation will not allow setting of these keys.
-	node.ConfigSchema.Lock()
-	node.ConfigSchema.Types["storage.project."+project.Name+".images_volume"] = config.Key{}
-	node.ConfigSchema.Types["storage.project."+project.Name+".backups_volume"] = config.Key{}
-	node.ConfigSchema.Unlock()
-
-	requestor, err := request.GetRequestor(r.Context())
-	if err != nil {
-		return response.SmartError(err)
-	}
+	projectExtendNodeConfigSchema(project.Name)
 
 	// On other cluster nodes, we're done.
 	if requestor.IsClusterNotification() {
 		return response.SyncResponse(true, nil)
 	}
 
+	err = projectCreate(r.Context(), s, project)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return response.Conflict(fmt.Errorf("Project %q already exists", project.Name))
+		}
+
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.ProjectCreated.Event(project.Name, requestor.EventLifecycleRequestor(), nil)
+	s.Events.SendLifecycle(project.Name, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// projectExtendNodeConfigSchema adds the project-specific keys to the node config schema.
+func projectExtendNodeConfigSchema(projectName string) {
+	node.ConfigSchema.Lock()
+	node.ConfigSchema.Types["storage.project."+projectName+".images_volume"] = config.Key{}
+	node.ConfigSchema.Types["storage.project."+projectName+".backups_volume"] = config.Key{}
+	node.ConfigSchema.Unlock()
+}
+
+// projectCreate notifies the other cluster members about the new project and creates its database records.
+// The project name and configuration must already be validated.
+func projectCreate(ctx context.Context, s *state.State, project api.ProjectsPost) error {
 	// Send notification to all cluster members to extend the node schema.
 	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
 		return client.CreateProject(project)
 	})
 	if err != nil {
-		return response.SmartError(fmt.Errorf("Failed notifying other cluster members: %w", err))
+		return fmt.Errorf("Failed notifying other cluster members: %w", err)
 	}
 
-	var id int64
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-		id, err = dbCluster.CreateProject(ctx, tx.Tx(), dbCluster.Project{Description: project.Description, Name: project.Name})
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err := dbCluster.CreateProject(ctx, tx.Tx(), dbCluster.Project{Description: project.Description, Name: project.Name})
 		if err != nil {
 			return fmt.Errorf("Failed adding database record: %w", err)
 		}
@@ -401,17 +433,10 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		return nil
 	})
 	if err != nil {
-		if api.StatusErrorCheck(err, http.StatusConflict) {
-			return response.Conflict(fmt.Errorf("Project %q already exists", project.Name))
-		}
-
-		return response.SmartError(fmt.Errorf("Failed creating project %q: %w", project.Name, err))
+		return fmt.Errorf("Failed creating project %q: %w", project.Name, err)
 	}
 
-	lc := lifecycle.ProjectCreated.Event(project.Name, requestor.EventLifecycleRequestor(), nil)
-	s.Events.SendLifecycle(project.Name, lc)
-
-	return response.SyncResponseLocation(true, nil, lc.Source)
+	return nil
 }
 
 // Create the default profile of a project.
@@ -755,6 +780,20 @@ func isProjectInUse(projectUsedBy []string, skipURLs ...string) bool {
 
 // Common logic between PUT and PATCH.
 func projectChange(ctx context.Context, s *state.State, project *api.Project, req api.ProjectPut) response.Response {
+	// Volatile keys are only set by LXD itself. Keep their current values if omitted from the request.
+	req.Config = util.CopyConfig(req.Config)
+	for key, value := range req.Config {
+		if strings.HasPrefix(key, "volatile.") && value != project.Config[key] {
+			return response.BadRequest(fmt.Errorf("Volatile project configuration key %q cannot be changed", key))
+		}
+	}
+
+	for key, value := range project.Config {
+		if strings.HasPrefix(key, "volatile.") {
+			req.Config[key] = value
+		}
+	}
+
 	// Make a list of config keys that have changed.
 	configChanged := []string{}
 	for key := range project.Config {
@@ -1266,6 +1305,16 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Failed loading project %q: %w", name, err)
 		}
 
+		// Trashed volumes are not tracked as project entities, so they must be purged explicitly.
+		trashEntries, err := dbCluster.GetTrashEntries(ctx, tx.Tx(), dbCluster.TrashEntryFilter{Project: &project.Name})
+		if err != nil {
+			return err
+		}
+
+		if len(trashEntries) > 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "Project %q still has %d entries in its trash", name, len(trashEntries))
+		}
+
 		projectEntities, err = projectUsedByMap(ctx, tx.Tx(), project.Name)
 		if err != nil {
 			return fmt.Errorf("Failed determining project usage: %w", err)
@@ -1361,36 +1410,9 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		// Clear the project-specific config keys from the local node config.
-		err = projectNodeConfigDelete(ctx, d, s, name)
-		if err != nil {
-			return fmt.Errorf("Failed deleting project specific information from local configuration: %w", err)
-		}
-
-		// Send notification to all cluster members to update the node schema and handle forced project deletion (if requested).
-		// Require all cluster members to process cluster notification to avoid leaving cluster members in an inconsistent state.
-		notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
-		if err != nil {
-			return fmt.Errorf("Failed getting a cluster notifier: %w", err)
-		}
-
-		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
-			op, err := client.DeleteProject(name, force)
-			if err != nil {
-				return err
-			}
-
-			return op.Wait()
-		})
+		err = projectDeleteAllMembers(ctx, d, s, name, force)
 		if err != nil {
-			return fmt.Errorf("Failed notifying other cluster members: %w", err)
-		}
-
-		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-			return dbCluster.DeleteProject(ctx, tx.Tx(), name)
-		})
-		if err != nil {
-			return fmt.Errorf("Failed deleting project: %w", err)
+			return err
 		}
 
 		s.Events.SendLifecycle(name, lifecycle.ProjectDeleted.Event(name, requestor.EventLifecycleRequestor(), nil))
@@ -1410,6 +1432,44 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 	return response.OperationResponse(op)
 }
 
+// projectDeleteAllMembers clears the project-specific configuration of all cluster members and removes the project.
+// If force is true, the other cluster members also delete the project entities they host.
+func projectDeleteAllMembers(ctx context.Context, d *Daemon, s *state.State, name string, force bool) error {
+	// Clear the project-specific config keys from the local node config.
+	err := projectNodeConfigDelete(ctx, d, s, name)
+	if err != nil {
+		return fmt.Errorf("Failed deleting project specific information from local configuration: %w", err)
+	}
+
+	// Send notification to all cluster members to update the node schema and handle forced project deletion (if requested).
+	// Require all cluster members to process cluster notification to avoid leaving cluster members in an inconsistent state.
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
+	if err != nil {
+		return fmt.Errorf("Failed getting a cluster notifier: %w", err)
+	}
+
+	err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+		op, err := client.DeleteProject(name, force)
+		if err != nil {
+			return err
+		}
+
+		return op.Wait()
+	})
+	if err != nil {
+		return fmt.Errorf("Failed notifying other cluster members: %w", err)
+	}
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteProject(ctx, tx.Tx(), name)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed deleting project: %w", err)
+	}
+
+	return nil
+}
+
 // swagger:operation GET /1.0/projects/{name}/state projects project_state_get
 //
 //	Get the project state
@@ -1804,6 +1864,22 @@ func projectValidateConfig(ctx context.Context, s *state.State, config map[strin
 		//  type: string
 		//  shortdesc: Compression algorithm to use for backups
 		"backups.compression_algorithm": validate.IsCompressionAlgorithm,
+		// lxdmeta:generate(entities=project; group=specific; key=deletion.retention)
+		// When set, deleted instances and custom storage volumes are moved into the project trash instead of being
+		// removed immediately. They can be restored with `lxc trash restore` until the retention period expires.
+		//
+		// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+		// ---
+		//  type: string
+		//  shortdesc: How long deleted instances and custom volumes are kept in the trash
+		"deletion.retention": func(value string) error {
+			if value == "" {
+				return nil
+			}
+
+			_, err := shared.GetExpiry(time.Time{}, value)
+			return err
+		},
 		// lxdmeta:generate(entities=project; group=features; key=features.profiles)
 		//
 		// ---
@@ -2179,6 +2255,14 @@ func projectValidateConfig(ctx context.Context, s *state.State, config map[strin
 		//  shortdesc: When set to `block`, creating instance or volume snapshots is prevented
 		"restricted.snapshots": isEitherAllowOrBlock,
 
+		// lxdmeta:generate(entities=project; group=specific; key=volatile.test_failover.source)
+		// This key is set by LXD on the projects created by the test failover of a replicator, to the URL of the replicator.
+		// It can't be set or changed by users.
+		// ---
+		//  type: string
+		//  shortdesc: Replicator that created the project for a test failover
+		"volatile.test_failover.source": validate.IsAny,
+
 		// lxdmeta:generate(entities=project; group=replica; key=replica.cluster)
 		// This setting is used on standby projects to identify which cluster link is allowed to replicate instances to this project.
 		// ---
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -19,18 +20,22 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/db/warningtype"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/operations"
+	projecthelpers "github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/lxd/warnings"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
 	"github.com/canonical/lxd/shared/version"
 )
@@ -369,6 +374,89 @@ func replicatorValidateConfig(ctx context.Context, s *state.State, config map[st
 		//  shortdesc: Cron expression for the replication schedule.
 		//  scope: global
 		"schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=continuous)
+		// When enabled, a new replication run starts as soon as the previous one finishes.
+		// Continuous runs only re-sync the instances that changed since they were last replicated, along with
+		// their attached custom volumes. Other custom volumes and buckets are left to manual and scheduled runs.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to replicate continuously
+		//  scope: global
+		"continuous": validate.Optional(validate.IsBool),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=rpo)
+		// Specify an expression like `15M`, `1H` or `1d`.
+		// A warning is raised when the replica of an instance is older than this.
+		// Custom volumes and buckets aren't covered.
+		// ---
+		//  type: string
+		//  shortdesc: Target recovery point objective
+		//  scope: global
+		"rpo": validate.Optional(func(value string) error {
+			_, err := shared.GetExpiry(time.Time{}, value)
+			return err
+		}),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=limits.bandwidth)
+		// Specify a bit rate like `100Mbit` or `1Gbit`.
+		// The transfers sent by the cluster member running the replicator share the bandwidth. Transfers sent by other
+		// cluster members or by the target cluster each get an even share of it.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum transfer rate of a replication run
+		//  scope: global
+		"limits.bandwidth": validate.Optional(func(value string) error {
+			_, err := units.ParseBitSizeString(value)
+			return err
+		}),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=limits.concurrency)
+		// By default, all instances, custom volumes and buckets are transferred at the same time.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of concurrent transfers
+		//  scope: global
+		"limits.concurrency": validate.Optional(validate.IsInRange(1, 1024)),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=filter.names)
+		// Specify a comma-separated list of name patterns like `web-*`.
+		// Only the instances, custom volumes and buckets whose name matches one of the patterns are replicated.
+		// ---
+		//  type: string
+		//  shortdesc: Name patterns of the entities to replicate
+		//  scope: global
+		"filter.names": validate.Optional(validate.IsListOf(replicatorValidateNamePattern)),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=filter.labels)
+		// Specify a comma-separated list of `user.<key>=<value>` pairs.
+		// Only the instances, custom volumes and buckets that have all of these user keys set to the given values are replicated.
+		// For instances, the keys inherited from profiles are taken into account.
+		// ---
+		//  type: string
+		//  shortdesc: User keys of the entities to replicate
+		//  scope: global
+		"filter.labels": validate.Optional(validate.IsListOf(replicatorValidateLabel)),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=snapshots.exclude)
+		// When enabled, only the current state of instances and custom volumes is replicated, without their snapshots.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to leave snapshots out of the replication
+		//  scope: global
+		"snapshots.exclude": validate.Optional(validate.IsBool),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=test_failover.project)
+		// Test failovers clone the replicated instances into this project, which is created by the test
+		// failover and deleted by its cleanup.
+		// ---
+		//  type: string
+		//  defaultdesc: `<project>-test-failover`
+		//  shortdesc: Project used for test failovers
+		//  scope: global
+		"test_failover.project": validate.Optional(projecthelpers.ValidName),
 	}
 
 	for k, v := range config {
@@ -592,6 +680,12 @@ func replicatorPost(d *Daemon, r *http.Request) response.Response {
 //	The "restore" action requires all local project instances to be stopped;
 //	it returns 400 if any instance is running to prevent partial restores.
 //
+//	The "failover" action promotes the local standby project and starts its instances in boot order,
+//	after stopping, syncing and demoting the leader project if the leader cluster is reachable.
+//	The "failback" action hands the leadership back to the target cluster in the same way.
+//	The "test-failover" action clones the instances of the local standby project into an isolated
+//	project and starts them, and "test-failover-cleanup" deletes that project.
+//
 //	---
 //	consumes:
 //	  - application/json
@@ -637,6 +731,8 @@ func replicatorStatePut(d *Daemon, r *http.Request) response.Response {
 
 	switch req.Action {
 	case "start", "restore":
+	case "failover", "failback", "test-failover", "test-failover-cleanup":
+		return replicatorFailoverStatePut(d, r, projectName, name, req.Action)
 	default:
 		return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
 	}
@@ -669,7 +765,7 @@ func replicatorStatePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Replicator %q has no cluster link configured", name))
 	}
 
-	opArgs, err := prepareReplicatorRunOperation(r.Context(), s, projectName, name, clusterLinkName, restore, dbReplicator.Row.ID)
+	opArgs, err := prepareReplicatorRunOperation(r.Context(), s, apiReplicator, restore, false, dbReplicator.Row.ID)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -958,25 +1054,104 @@ func replicatorStateGet(d *Daemon, r *http.Request) response.Response {
 	}
 
 	name := r.PathValue("name")
-	var status string
+	var state *api.ReplicatorState
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		dbReplicator, err := dbCluster.GetReplicator(ctx, tx.Tx(), name, projectName)
 		if err != nil {
 			return err
 		}
 
-		status = api.ReplicatorStatusPending
-		if dbReplicator.Row.LastRunStatus != "" {
-			status = dbReplicator.Row.LastRunStatus
+		config, err := dbCluster.ReplicatorsConfigStore().GetByEntityIDs(ctx, tx.Tx(), dbReplicator.Row.ID)
+		if err != nil {
+			return fmt.Errorf("Failed loading replicator config: %w", err)
 		}
 
-		return nil
+		state, err = replicatorLoadState(ctx, tx, dbReplicator.ToAPI(config), dbReplicator.Row.ID, nil)
+		return err
 	})
 	if err != nil {
 		return response.SmartError(fmt.Errorf("Failed loading replicator state for %q: %w", name, err))
 	}
 
-	return response.SyncResponse(true, api.ReplicatorState{Status: status})
+	return response.SyncResponse(true, state)
+}
+
+// replicatorLoadState computes the state of the replicator, including the replication lag of the instances of
+// its project. If node is set, only the instances located on that cluster member are included.
+func replicatorLoadState(ctx context.Context, tx *db.ClusterTx, replicator *api.Replicator, replicatorID int64, node *string) (*api.ReplicatorState, error) {
+	records, err := dbCluster.GetReplicatorInstances(ctx, tx.Tx(), replicatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	rpo, err := replicatorRPO(replicator.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &api.ReplicatorState{
+		Status:    replicator.LastRunStatus,
+		Instances: map[string]api.ReplicatorInstanceState{},
+	}
+
+	now := time.Now()
+	err = tx.InstanceList(ctx, func(dbInst db.InstanceArgs, _ api.Project) error {
+		var recordPtr *dbCluster.ReplicatorInstance
+		record, ok := records[dbInst.Name]
+		if ok {
+			recordPtr = &record
+		}
+
+		running := dbInst.Config["volatile.last_state.power"] == instance.PowerStateRunning
+		lag := replicatorInstanceLag(now, dbInst.CreationDate, dbInst.LastUsedDate, running, recordPtr)
+
+		state.Instances[dbInst.Name] = api.ReplicatorInstanceState{
+			LastReplicatedAt: record.LastReplicatedDate,
+			Lag:              int64(lag.Seconds()),
+		}
+
+		state.Lag = max(state.Lag, int64(lag.Seconds()))
+		return nil
+	}, dbCluster.InstanceFilter{Project: &replicator.Project, Node: node})
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing project instances: %w", err)
+	}
+
+	state.RPOBreached = rpo > 0 && time.Duration(state.Lag)*time.Second > rpo
+
+	return state, nil
+}
+
+// replicatorRPO returns the recovery point objective of the replicator, or zero if none is configured.
+func replicatorRPO(config map[string]string) (time.Duration, error) {
+	if config["rpo"] == "" {
+		return 0, nil
+	}
+
+	now := time.Now()
+	expiry, err := shared.GetExpiry(now, config["rpo"])
+	if err != nil {
+		return 0, fmt.Errorf("Invalid value for replicator configuration key %q: %w", "rpo", err)
+	}
+
+	return expiry.Sub(now), nil
+}
+
+// replicatorInstanceLag returns how far the replica of an instance is behind the instance, given the replication
+// record of the instance (nil if it was never replicated).
+// A replica taken while the instance was stopped stays up to date until the instance is started again.
+// Otherwise, the lag is the time since the last replication started, or since the instance was created if it was
+// never replicated.
+func replicatorInstanceLag(now time.Time, createdAt time.Time, lastUsedAt time.Time, running bool, record *dbCluster.ReplicatorInstance) time.Duration {
+	if record == nil {
+		return max(now.Sub(createdAt), 0)
+	}
+
+	if record.Stopped && !running && !lastUsedAt.After(record.LastReplicatedDate) {
+		return 0
+	}
+
+	return max(now.Sub(record.LastReplicatedDate), 0)
 }
 
 // runScheduledReplicatorsTask returns a background task that checks replicator schedules every minute
@@ -1004,15 +1179,193 @@ func runScheduledReplicatorsTask(stateFunc func() *state.State) (task.Func, task
 	return f, schedule
 }
 
+// runContinuousReplicatorsTask returns a background task that starts a new run of each continuous replicator
+// as soon as its previous run has finished. Changes are only detected on instances, see prepareReplicatorRunOperation.
+func runContinuousReplicatorsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := runContinuousReplicators(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed running continuous replicator task", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(10 * time.Second)
+}
+
+// runContinuousReplicators starts a run of each continuous replicator that has no run in progress.
+// Only the leader starts continuous runs so that cluster members don't compete for them.
+func runContinuousReplicators(ctx context.Context, s *state.State) error {
+	leaderInfo, err := s.LeaderInfo()
+	if err != nil {
+		return err
+	}
+
+	if !leaderInfo.Leader {
+		return nil
+	}
+
+	var apiReplicators []*api.Replicator
+	var replicatorRows []dbCluster.Replicator
+	var projectModes map[string]string
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		apiReplicators, replicatorRows, projectModes, err = replicatorsLoad(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, replicator := range apiReplicators {
+		if projectModes[replicator.Project] != api.ReplicatorProjectModeLeader || shared.IsFalseOrEmpty(replicator.Config["continuous"]) {
+			continue
+		}
+
+		// Check for a run in progress before connecting to the target cluster.
+		var running bool
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			ops, err := dbCluster.GetOperationsByProjectAndType(ctx, tx.Tx(), replicator.Project, operationtype.ReplicatorRun)
+			if err != nil {
+				return err
+			}
+
+			replicatorURL := entity.ReplicatorURL(replicator.Project, replicator.Name).String()
+			for _, op := range ops {
+				if op.Row.ConflictReference == replicatorURL {
+					running = true
+					break
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading replicator operations: %w", err)
+		}
+
+		if running {
+			continue
+		}
+
+		err = triggerScheduledReplicator(ctx, s, replicator, &replicatorRows[i], true)
+		if err != nil {
+			logger.Error("Failed running continuous replicator", logger.Ctx{
+				"replicator": replicator.Name,
+				"project":    replicator.Project,
+				"err":        err,
+			})
+		}
+	}
+
+	return nil
+}
+
+// replicatorRPOCheckTask returns a background task that raises a warning for each replicator whose
+// replication lag exceeds its recovery point objective.
+func replicatorRPOCheckTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := replicatorRPOCheck(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed checking replicator recovery point objectives", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
+
+// replicatorRPOCheck compares the replication lag of each replicator to its recovery point objective and
+// updates the related warnings. The check only runs on the leader.
+func replicatorRPOCheck(ctx context.Context, s *state.State) error {
+	leaderInfo, err := s.LeaderInfo()
+	if err != nil {
+		return err
+	}
+
+	if !leaderInfo.Leader {
+		// Clear any warning raised while this member was the leader.
+		return warnings.ResolveWarningsByLocalNodeAndType(s.DB.Cluster, warningtype.ReplicatorRPOBreached)
+	}
+
+	var apiReplicators []*api.Replicator
+	var replicatorRows []dbCluster.Replicator
+	var projectModes map[string]string
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		apiReplicators, replicatorRows, projectModes, err = replicatorsLoad(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, replicator := range apiReplicators {
+		replicatorID := replicatorRows[i].Row.ID
+
+		rpo, err := replicatorRPO(replicator.Config)
+		if err != nil {
+			logger.Warn("Failed checking replicator recovery point objective", logger.Ctx{"replicator": replicator.Name, "project": replicator.Project, "err": err})
+			continue
+		}
+
+		var state *api.ReplicatorState
+		if rpo > 0 && projectModes[replicator.Project] == api.ReplicatorProjectModeLeader {
+			err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+				state, err = replicatorLoadState(ctx, tx, replicator, replicatorID, nil)
+				return err
+			})
+			if err != nil {
+				logger.Warn("Failed checking replicator recovery point objective", logger.Ctx{"replicator": replicator.Name, "project": replicator.Project, "err": err})
+				continue
+			}
+		}
+
+		if state == nil || !state.RPOBreached {
+			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, replicator.Project, warningtype.ReplicatorRPOBreached, entity.TypeReplicator, int(replicatorID))
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		lag := time.Duration(state.Lag) * time.Second
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, replicator.Project, entity.TypeReplicator, int(replicatorID), warningtype.ReplicatorRPOBreached, fmt.Sprintf("Replication lag of replicator %q is %s (recovery point objective %s)", replicator.Name, lag, rpo))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // prepareReplicatorRunOperation builds the operation used to run a replicator.
-func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectName string, name string, clusterLinkName string, restore bool, replicatorID int64) (operations.OperationArgs, error) {
+// In continuous mode, only the instances whose replica is behind are replicated.
+func prepareReplicatorRunOperation(ctx context.Context, s *state.State, replicator *api.Replicator, restore bool, continuous bool, replicatorID int64) (operations.OperationArgs, error) {
+	projectName := replicator.Project
+	name := replicator.Name
+	clusterLinkName := replicator.Config["cluster"]
+
+	scope, err := replicatorLoadScope(replicator.Config)
+	if err != nil {
+		return operations.OperationArgs{}, api.StatusErrorf(http.StatusBadRequest, "%s", err)
+	}
+
+	// Take the recovery point time before loading the instances, so that any instance started after this
+	// point is detected as changed by the next continuous run.
+	recoveryPoint := time.Now()
+
 	// Load all DB state in a single transaction before any network I/O.
 	var clusterLink *api.ClusterLink
+	var records map[string]dbCluster.ReplicatorInstance
 	var targetCert *x509.Certificate
 	var sourceProject *api.Project
 	var allInsts []instance.Instance
+	var volumes []*db.StorageVolume
+	var buckets []*db.StorageBucket
+	var ownVolumes bool
+	var ownBuckets bool
 	var nodeAddressByName map[string]string
-	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 		_, clusterLink, targetCert, err = lxdCluster.LoadClusterLinkAndCert(ctx, tx.Tx(), clusterLinkName)
 		if err != nil {
@@ -1056,6 +1409,36 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			nodeAddressByName[node.Name] = node.Address
 		}
 
+		records, err = dbCluster.GetReplicatorInstances(ctx, tx.Tx(), replicatorID)
+		if err != nil {
+			return err
+		}
+
+		// Custom volumes and buckets are only replicated when the project has its own, rather than
+		// sharing those of the default project.
+		volumeType := dbCluster.StoragePoolVolumeTypeCustom
+		ownVolumes = projecthelpers.StorageVolumeProjectFromRecord(sourceProject, volumeType) == projectName
+		if ownVolumes {
+			projectVolumes, err := tx.GetStorageVolumes(ctx, false, db.StorageVolumeFilter{Type: &volumeType, Project: &projectName})
+			if err != nil {
+				return fmt.Errorf("Failed listing project custom volumes: %w", err)
+			}
+
+			for _, vol := range projectVolumes {
+				if !shared.IsSnapshot(vol.Name) {
+					volumes = append(volumes, vol)
+				}
+			}
+		}
+
+		ownBuckets = projecthelpers.StorageBucketProjectFromRecord(sourceProject) == projectName
+		if ownBuckets {
+			buckets, err = tx.GetStoragePoolBuckets(ctx, false, db.StorageBucketFilter{Project: &projectName})
+			if err != nil {
+				return fmt.Errorf("Failed listing project storage buckets: %w", err)
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -1096,10 +1479,12 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 		}
 	}
 
-	// In restore mode the current leader cluster is the source of truth: use its instance list so
-	// that instances created on the leader after failover are included. Restore is additive
-	// only: local instances that do not exist on the leader are left in place and not deleted.
+	// In restore mode the current leader cluster is the source of truth: use its instance, custom volume and
+	// bucket lists so that those created on the leader after failover are included. Restore is additive
+	// only: local instances, volumes and buckets that do not exist on the leader are left in place and not deleted.
 	var iterNames []string
+	var remoteVolumes []replicatorRemoteVolume
+	var remoteBuckets []replicatorRemoteBucket
 	if restore {
 		remoteInsts, err := targetClient.GetInstances(lxd.GetInstancesArgs{InstanceType: api.InstanceTypeAny})
 		if err != nil {
@@ -1108,21 +1493,79 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 
 		iterNames = make([]string, 0, len(remoteInsts))
 		for _, ri := range remoteInsts {
-			iterNames = append(iterNames, ri.Name)
+			if scope.selects(ri.Name, ri.ExpandedConfig) {
+				iterNames = append(iterNames, ri.Name)
+			}
+		}
+
+		remoteVolumes, remoteBuckets, err = replicatorListRemoteStorage(targetClient, scope, ownVolumes, ownBuckets)
+		if err != nil {
+			return operations.OperationArgs{}, err
 		}
 	}
 
 	replicatorURL := entity.ReplicatorURL(projectName, name)
 	projectURL := entity.ProjectURL(projectName)
 
+	// Custom volumes and buckets are transferred before the instances, so that the instances using them
+	// can be created. All child operations are started together, so the instance transfers wait for the
+	// volume and bucket transfers to finish.
+	var storageWG sync.WaitGroup
+	storageDone := sync.OnceValue(func() chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			storageWG.Wait()
+			close(done)
+		}()
+
+		return done
+	})
+
 	// Forward replication: iterate over all loaded instances directly.
 	if !restore {
-		childArgs := make([]*operations.OperationArgs, 0, len(allInsts))
+		childArgs := make([]*operations.OperationArgs, 0, len(allInsts)+len(volumes)+len(buckets))
+
+		// Custom volumes attached to the instances replicated by a continuous run.
+		changedVolumes := map[string]bool{}
 
 		for _, inst := range allInsts {
+			if !scope.selects(inst.Name(), inst.ExpandedConfig()) {
+				continue
+			}
+
 			memberAddress := nodeAddressByName[inst.Location()]
+			stopped := inst.LocalConfig()["volatile.last_state.power"] != instance.PowerStateRunning
+
+			if continuous {
+				var recordPtr *dbCluster.ReplicatorInstance
+				record, ok := records[inst.Name()]
+				if ok {
+					recordPtr = &record
+				}
+
+				if replicatorInstanceLag(recoveryPoint, inst.CreationDate(), inst.LastUsedDate(), !stopped, recordPtr) == 0 {
+					continue
+				}
+
+				for _, volume := range replicatorInstanceVolumes(inst) {
+					changedVolumes[volume] = true
+				}
+			}
 
 			copyFunc := func(ctx context.Context, op *operations.Operation) error {
+				select {
+				case <-storageDone():
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+
+				err := scope.acquire(ctx)
+				if err != nil {
+					return err
+				}
+
+				defer scope.release()
+
 				dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
 				if err != nil {
 					return fmt.Errorf("Failed connecting to target cluster: %w", err)
@@ -1130,7 +1573,20 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 
 				dstClient = dstClient.UseProject(projectName)
 
-				return replicateInstance(ctx, s, op, inst, memberAddress, dstClient, targetCertPEM)
+				err = replicateInstance(ctx, s, op, inst, memberAddress, dstClient, targetCertPEM, scope)
+				if err != nil {
+					return err
+				}
+
+				// Record the recovery point of the replica, used to compute the replication lag.
+				err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+					return dbCluster.UpsertReplicatorInstance(ctx, tx.Tx(), replicatorID, int64(inst.ID()), recoveryPoint, stopped)
+				})
+				if err != nil {
+					return fmt.Errorf("Failed recording replication of instance %q: %w", inst.Name(), err)
+				}
+
+				return nil
 			}
 
 			childArgs = append(childArgs, &operations.OperationArgs{
@@ -1145,6 +1601,87 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			})
 		}
 
+		for _, vol := range volumes {
+			// Continuous runs only refresh the volumes used by the instances they refresh.
+			if continuous && !changedVolumes[vol.Pool+"/"+vol.Name] {
+				continue
+			}
+
+			if !scope.selects(vol.Name, vol.Config) {
+				continue
+			}
+
+			memberAddress := nodeAddressByName[vol.Location]
+
+			storageWG.Add(1)
+			childArgs = append(childArgs, &operations.OperationArgs{
+				ProjectName: projectName,
+				EntityURL:   projectURL,
+				Type:        operationtype.ReplicatorRunVolume,
+				Class:       operationtype.OperationClassTask,
+				Metadata: map[string]any{
+					api.MetadataEntityURL: entity.StorageVolumeURL(projectName, vol.Location, vol.Pool, vol.Type, vol.Name).String(),
+				},
+				RunHook: func(ctx context.Context, op *operations.Operation) error {
+					defer storageWG.Done()
+
+					err := scope.acquire(ctx)
+					if err != nil {
+						return err
+					}
+
+					defer scope.release()
+
+					dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
+					if err != nil {
+						return fmt.Errorf("Failed connecting to target cluster: %w", err)
+					}
+
+					return replicateCustomVolume(ctx, s, op, vol, memberAddress, dstClient.UseProject(projectName), targetCertPEM, scope)
+				},
+			})
+		}
+
+		for _, bucket := range buckets {
+			// Continuous runs have no way to tell whether a bucket changed, so buckets are left to the
+			// manual and scheduled runs.
+			if continuous || !scope.selects(bucket.Name, bucket.Config) {
+				continue
+			}
+
+			memberAddress := nodeAddressByName[bucket.Location]
+
+			storageWG.Add(1)
+			childArgs = append(childArgs, &operations.OperationArgs{
+				ProjectName: projectName,
+				EntityURL:   projectURL,
+				Type:        operationtype.ReplicatorRunBucket,
+				Class:       operationtype.OperationClassTask,
+				Metadata: map[string]any{
+					api.MetadataEntityURL: entity.StorageBucketURL(projectName, bucket.Location, bucket.PoolName, bucket.Name).String(),
+				},
+				RunHook: func(ctx context.Context, op *operations.Operation) error {
+					defer storageWG.Done()
+
+					err := scope.acquire(ctx)
+					if err != nil {
+						return err
+					}
+
+					defer scope.release()
+
+					dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
+					if err != nil {
+						return fmt.Errorf("Failed connecting to target cluster: %w", err)
+					}
+
+					return replicateBucket(ctx, s, bucket, memberAddress, dstClient.UseProject(projectName), scope)
+				},
+			})
+		}
+
+		scope.setTransfers(len(childArgs))
+
 		return operations.OperationArgs{
 			ProjectName:       projectName,
 			EntityURL:         replicatorURL,
@@ -1170,15 +1707,117 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 		}, nil
 	}
 
-	// Restore mode: iterate over the current leader cluster's instance list.
-	childArgs := make([]*operations.OperationArgs, 0, len(iterNames))
+	// Restore mode: iterate over the current leader cluster's instance, custom volume and bucket lists.
+	childArgs := make([]*operations.OperationArgs, 0, len(iterNames)+len(remoteVolumes)+len(remoteBuckets))
 
 	// Use our cluster certificate so the leader can verify TLS when
 	// pushing data back to us.
 	localCertPEM := string(clusterCert.PublicKey())
 
+	localVolumes := make(map[string]*db.StorageVolume, len(volumes))
+	for _, vol := range volumes {
+		localVolumes[vol.Pool+"/"+vol.Name] = vol
+	}
+
+	for _, remote := range remoteVolumes {
+		// Volumes hosted by another cluster member are received by that member, others by this member.
+		var memberAddress string
+		localVol := localVolumes[remote.pool+"/"+remote.volume.Name]
+		if localVol != nil && localVol.Location != "" && localVol.Location != s.ServerName {
+			memberAddress = nodeAddressByName[localVol.Location]
+		}
+
+		storageWG.Add(1)
+		childArgs = append(childArgs, &operations.OperationArgs{
+			ProjectName: projectName,
+			EntityURL:   projectURL,
+			Type:        operationtype.ReplicatorRunVolume,
+			Class:       operationtype.OperationClassTask,
+			Metadata: map[string]any{
+				api.MetadataEntityURL: entity.StorageVolumeURL(projectName, remote.volume.Location, remote.pool, remote.volume.Type, remote.volume.Name).String(),
+			},
+			RunHook: func(ctx context.Context, op *operations.Operation) error {
+				defer storageWG.Done()
+
+				err := scope.acquire(ctx)
+				if err != nil {
+					return err
+				}
+
+				defer scope.release()
+
+				localAddress, err := replicatorLocalAddress(s, nodeAddressByName)
+				if err != nil {
+					return err
+				}
+
+				srcClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
+				if err != nil {
+					return fmt.Errorf("Failed connecting to target cluster: %w", err)
+				}
+
+				return restoreCustomVolume(ctx, s, op, projectName, remote.pool, remote.volume, memberAddress, localAddress, srcClient.UseProject(projectName), localCertPEM, scope)
+			},
+		})
+	}
+
+	localBuckets := make(map[string]*db.StorageBucket, len(buckets))
+	for _, bucket := range buckets {
+		localBuckets[bucket.PoolName+"/"+bucket.Name] = bucket
+	}
+
+	for _, remote := range remoteBuckets {
+		// Buckets hosted by another cluster member are written through that member.
+		var memberAddress string
+		localBucket := localBuckets[remote.pool+"/"+remote.bucket.Name]
+		if localBucket != nil && localBucket.Location != "" && localBucket.Location != s.ServerName {
+			memberAddress = nodeAddressByName[localBucket.Location]
+		}
+
+		storageWG.Add(1)
+		childArgs = append(childArgs, &operations.OperationArgs{
+			ProjectName: projectName,
+			EntityURL:   projectURL,
+			Type:        operationtype.ReplicatorRunBucket,
+			Class:       operationtype.OperationClassTask,
+			Metadata: map[string]any{
+				api.MetadataEntityURL: entity.StorageBucketURL(projectName, remote.bucket.Location, remote.pool, remote.bucket.Name).String(),
+			},
+			RunHook: func(ctx context.Context, op *operations.Operation) error {
+				defer storageWG.Done()
+
+				err := scope.acquire(ctx)
+				if err != nil {
+					return err
+				}
+
+				defer scope.release()
+
+				srcClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
+				if err != nil {
+					return fmt.Errorf("Failed connecting to target cluster: %w", err)
+				}
+
+				return restoreBucket(ctx, s, projectName, remote.pool, remote.bucket, localBucket, memberAddress, srcClient.UseProject(projectName), scope)
+			},
+		})
+	}
+
 	for _, instName := range iterNames {
 		copyFunc := func(ctx context.Context, op *operations.Operation) error {
+			select {
+			case <-storageDone():
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			err := scope.acquire(ctx)
+			if err != nil {
+				return err
+			}
+
+			defer scope.release()
+
 			dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
 			if err != nil {
 				return fmt.Errorf("Failed connecting to target cluster: %w", err)
@@ -1229,9 +1868,10 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 					InstancePut: freshInst.Writable(),
 					Type:        api.InstanceType(freshInst.Type),
 					Source: api.InstanceSource{
-						Type:    api.SourceTypeMigration,
-						Mode:    "push",
-						Refresh: true,
+						Type:         api.SourceTypeMigration,
+						Mode:         "push",
+						Refresh:      true,
+						InstanceOnly: !scope.snapshots,
 					},
 				})
 				if err != nil {
@@ -1253,7 +1893,9 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 
 				// Tell the current leader cluster to push-migrate the instance to the hosting cluster member's sink.
 				remoteMigrateOp, err := dstClient.MigrateInstance(instName, api.InstancePost{
-					Migration: true,
+					Migration:    true,
+					InstanceOnly: !scope.snapshots,
+					TransferRate: scope.transferRate,
 					Target: &api.InstancePostTarget{
 						Operation:   restoreOp.URL().String(),
 						Websockets:  restoreSecrets,
@@ -1298,9 +1940,10 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 				Name: instName,
 				Type: api.InstanceType(freshInst.Type),
 				Source: api.InstanceSource{
-					Type:    api.SourceTypeMigration,
-					Mode:    "push",
-					Refresh: true,
+					Type:         api.SourceTypeMigration,
+					Mode:         "push",
+					Refresh:      true,
+					InstanceOnly: !scope.snapshots,
 				},
 			}
 
@@ -1340,29 +1983,19 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			}
 
 			// Build the operation URL using a reachable address for this server.
-			// For clustered members the address from the nodes table is already a
-			// concrete, registered address. For unclustered servers the nodes table
-			// stores the sentinel "0.0.0.0", so fall back to the configured HTTPS
-			// address. Return an error if we still cannot determine a concrete address,
-			// because the leader would not be able to reach us.
-			localAddress := nodeAddressByName[s.ServerName]
-			if util.IsWildCardAddress(localAddress) {
-				localAddress = s.LocalConfig.ClusterAddress()
-				if localAddress == "" {
-					localAddress = s.LocalConfig.HTTPSAddress()
-				}
-
-				if util.IsWildCardAddress(localAddress) || localAddress == "" {
-					sinkOp.Cancel()
-					return errors.New("Cannot restore to this server: configure a concrete address using cluster.https_address or core.https_address")
-				}
+			localAddress, err := replicatorLocalAddress(s, nodeAddressByName)
+			if err != nil {
+				sinkOp.Cancel()
+				return err
 			}
 
 			sinkOpURL := "https://" + localAddress + sinkOp.URL()
 
 			// Tell the current leader cluster to push-migrate the instance to our local sink.
 			remoteMigrateOp, err := dstClient.MigrateInstance(instName, api.InstancePost{
-				Migration: true,
+				Migration:    true,
+				InstanceOnly: !scope.snapshots,
+				TransferRate: scope.transferRate,
 				Target: &api.InstancePostTarget{
 					Operation:   sinkOpURL,
 					Websockets:  sinkSecrets,
@@ -1401,6 +2034,8 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 		})
 	}
 
+	scope.setTransfers(len(childArgs))
+
 	return operations.OperationArgs{
 		ProjectName:       projectName,
 		EntityURL:         replicatorURL,
@@ -1442,13 +2077,13 @@ func replicatorCheckInstancesStopped(allInsts []instance.Instance) error {
 // replicateInstance handles forward replication of a single instance to the
 // destination cluster. It handles both instances on the local cluster member
 // and instances on other cluster members.
-func replicateInstance(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance, memberAddress string, dstClient lxd.InstanceServer, targetCertPEM string) error {
+func replicateInstance(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance, memberAddress string, dstClient lxd.InstanceServer, targetCertPEM string, scope *replicatorScope) error {
 	instName := inst.Name()
 	projectName := inst.Project().Name
-	// Snapshotting is unconditional; the only exception is when the instance already has a
+	// Snapshotting is unconditional; the exceptions are when the instance already has a
 	// snapshot schedule defined, since scheduled snapshots provide point-in-time history so
-	// an extra one here would be redundant.
-	createSnapshot := inst.ExpandedConfig()["snapshots.schedule"] == ""
+	// an extra one here would be redundant, and when snapshots aren't replicated.
+	createSnapshot := scope.snapshots && inst.ExpandedConfig()["snapshots.schedule"] == ""
 
 	// Instance on another cluster member: connect to the hosting cluster member and
 	// drive the snapshot (if needed) and push migration through its API so the
@@ -1491,9 +2126,10 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 			InstancePut: srcInstInfo.Writable(),
 			Type:        api.InstanceType(srcInstInfo.Type),
 			Source: api.InstanceSource{
-				Type:    api.SourceTypeMigration,
-				Mode:    "push",
-				Refresh: true,
+				Type:         api.SourceTypeMigration,
+				Mode:         "push",
+				Refresh:      true,
+				InstanceOnly: !scope.snapshots,
 			},
 		})
 		if err != nil {
@@ -1515,7 +2151,9 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 
 		// Tell the hosting cluster member to push-migrate the instance to the destination.
 		srcMigrateOp, err := memberClient.MigrateInstance(instName, api.InstancePost{
-			Migration: true,
+			Migration:    true,
+			InstanceOnly: !scope.snapshots,
+			TransferRate: scope.transferRate,
 			Target: &api.InstancePostTarget{
 				Operation:   destOp.URL().String(),
 				Websockets:  destSecrets,
@@ -1568,9 +2206,10 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 		InstancePut: srcInstInfo.Writable(),
 		Type:        api.InstanceType(srcInstInfo.Type),
 		Source: api.InstanceSource{
-			Type:    api.SourceTypeMigration,
-			Mode:    "push",
-			Refresh: true,
+			Type:         api.SourceTypeMigration,
+			Mode:         "push",
+			Refresh:      true,
+			InstanceOnly: !scope.snapshots,
 		},
 	})
 	if err != nil {
@@ -1598,11 +2237,13 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 		Certificate: targetCertPEM,
 	}
 
-	srcMigration, err := newMigrationSource(inst, false, false, false, "", pushTarget)
+	srcMigration, err := newMigrationSource(inst, false, !scope.snapshots, false, "", pushTarget)
 	if err != nil {
 		return fmt.Errorf("Failed setting up migration source for instance %q: %w", instName, err)
 	}
 
+	srcMigration.rateLimiter = scope.limiter
+
 	migrArgs := operations.OperationArgs{
 		ProjectName: projectName,
 		EntityURL:   entity.InstanceURL(projectName, instName),
@@ -1644,55 +2285,50 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 	return destOp.Wait()
 }
 
-// runScheduledReplicators loads all replicators, checks their schedule config key against the current
-// time, and triggers replication for those that are due.
-func runScheduledReplicators(ctx context.Context, s *state.State) error {
-	// Load all replicators across all projects.
-	var apiReplicators []*api.Replicator
-	var replicatorRows []dbCluster.Replicator
-	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-		replicators, _, err := dbCluster.GetReplicatorsAndURLs(ctx, tx.Tx(), nil, func(_ dbCluster.Replicator) bool { return true })
-		if err != nil {
-			return fmt.Errorf("Failed loading replicators: %w", err)
-		}
-
-		allConfigs, err := dbCluster.ReplicatorsConfigStore().GetAll(ctx, tx.Tx())
-		if err != nil {
-			return fmt.Errorf("Failed loading replicator configs: %w", err)
-		}
-
-		apiReplicatorsTx := make([]*api.Replicator, 0, len(replicators))
-		for _, replicator := range replicators {
-			apiReplicatorsTx = append(apiReplicatorsTx, replicator.ToAPI(allConfigs))
-		}
+// replicatorsLoad loads all replicators across all projects along with their database rows, and the replica
+// mode of their projects so that callers can skip standby projects without an extra DB round-trip per replicator.
+func replicatorsLoad(ctx context.Context, tx *db.ClusterTx) ([]*api.Replicator, []dbCluster.Replicator, map[string]string, error) {
+	replicators, _, err := dbCluster.GetReplicatorsAndURLs(ctx, tx.Tx(), nil, func(_ dbCluster.Replicator) bool { return true })
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed loading replicators: %w", err)
+	}
 
-		apiReplicators = apiReplicatorsTx
-		replicatorRows = replicators
-		return nil
-	})
+	allConfigs, err := dbCluster.ReplicatorsConfigStore().GetAll(ctx, tx.Tx())
 	if err != nil {
-		return err
+		return nil, nil, nil, fmt.Errorf("Failed loading replicator configs: %w", err)
 	}
 
-	// Build a per-project replica mode map so the loop can skip standby projects
-	// without an extra DB round-trip per replicator.
-	projectModes := make(map[string]string, len(apiReplicators))
-	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-		for _, replicator := range apiReplicators {
-			_, ok := projectModes[replicator.Project]
-			if ok {
-				continue
-			}
+	apiReplicators := make([]*api.Replicator, 0, len(replicators))
+	projectModes := make(map[string]string, len(replicators))
+	for _, replicator := range replicators {
+		apiReplicators = append(apiReplicators, replicator.ToAPI(allConfigs))
 
-			dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), replicator.Project)
-			if err != nil {
-				return fmt.Errorf("Failed loading project %q: %w", replicator.Project, err)
-			}
+		_, ok := projectModes[replicator.ProjectName]
+		if ok {
+			continue
+		}
 
-			projectModes[replicator.Project] = string(dbProject.ReplicaMode)
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), replicator.ProjectName)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Failed loading project %q: %w", replicator.ProjectName, err)
 		}
 
-		return nil
+		projectModes[replicator.ProjectName] = string(dbProject.ReplicaMode)
+	}
+
+	return apiReplicators, replicators, projectModes, nil
+}
+
+// runScheduledReplicators loads all replicators, checks their schedule config key against the current
+// time, and triggers replication for those that are due.
+func runScheduledReplicators(ctx context.Context, s *state.State) error {
+	var apiReplicators []*api.Replicator
+	var replicatorRows []dbCluster.Replicator
+	var projectModes map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		apiReplicators, replicatorRows, projectModes, err = replicatorsLoad(ctx, tx)
+		return err
 	})
 	if err != nil {
 		return err
@@ -1716,7 +2352,7 @@ func runScheduledReplicators(ctx context.Context, s *state.State) error {
 		row := &replicatorRows[i]
 		logger.Debug("Running scheduled replicator", logger.Ctx{"replicator": replicator.Name, "project": replicator.Project, "schedule": schedule})
 
-		err := triggerScheduledReplicator(ctx, s, replicator, row)
+		err := triggerScheduledReplicator(ctx, s, replicator, row, false)
 		if err != nil {
 			logger.Error("Failed running scheduled replicator", logger.Ctx{
 				"replicator": replicator.Name,
@@ -1751,19 +2387,24 @@ func replicatorIsScheduledNow(spec string, now time.Time) bool {
 }
 
 // triggerScheduledReplicator runs replication for a single replicator as a background server operation.
-// It blocks until the operation completes so that last_run_date is persisted before the next scheduler
-// tick and operation results are visible to callers.
-func triggerScheduledReplicator(ctx context.Context, s *state.State, replicator *api.Replicator, row *dbCluster.Replicator) error {
+// Scheduled runs block until the operation completes so that last_run_date is persisted before the next
+// scheduler tick and operation results are visible to callers. Continuous runs don't wait for the operation,
+// and are skipped if no instance changed since the previous run.
+func triggerScheduledReplicator(ctx context.Context, s *state.State, replicator *api.Replicator, row *dbCluster.Replicator, continuous bool) error {
 	clusterLinkName := replicator.Config["cluster"]
 	if clusterLinkName == "" {
 		return fmt.Errorf("Replicator %q has no cluster link configured", replicator.Name)
 	}
 
-	opArgs, err := prepareReplicatorRunOperation(ctx, s, replicator.Project, replicator.Name, clusterLinkName, false, row.Row.ID)
+	opArgs, err := prepareReplicatorRunOperation(ctx, s, replicator, false, continuous, row.Row.ID)
 	if err != nil {
 		return err
 	}
 
+	if continuous && len(opArgs.Children) == 0 {
+		return nil
+	}
+
 	// Set status to Running before scheduling the operation. The operation's RunHook writes
 	// the terminal status (Completed/Failed) when it finishes. If the project has no instances,
 	// the RunHook can complete synchronously inside ScheduleServerOperation before it returns,
@@ -1793,6 +2434,10 @@ func triggerScheduledReplicator(ctx context.Context, s *state.State, replicator
 		return fmt.Errorf("Failed scheduling replicator operation: %w", err)
 	}
 
+	if continuous {
+		return nil
+	}
+
 	return op.Wait(ctx)
 }
 
@@ -0,0 +1,821 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/auth"
+	lxdCluster "github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+)
+
+// replicatorTestFailoverSourceKey is set on the projects created by test failovers, to the URL of the replicator.
+// It prevents the cleanup of a test failover from deleting a project it didn't create. Users can't set volatile
+// project keys, so only a test failover can create a project that its cleanup deletes.
+const replicatorTestFailoverSourceKey = "volatile.test_failover.source"
+
+// replicatorInstanceStateFunc changes the state of an instance ("start" or "stop"). Stopping an instance
+// waits for it to shut down for up to timeout seconds before forcing it to stop.
+type replicatorInstanceStateFunc func(ctx context.Context, name string, action string, timeout int) error
+
+// replicatorFailoverStatePut schedules a failover, failback, test failover or test failover cleanup of a replicator.
+func replicatorFailoverStatePut(d *Daemon, r *http.Request, projectName string, name string, action string) response.Response {
+	s := d.State()
+
+	var replicatorID int64
+	var replicator *api.Replicator
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbReplicator, err := dbCluster.GetReplicator(ctx, tx.Tx(), name, projectName)
+		if err != nil {
+			return err
+		}
+
+		config, err := dbCluster.ReplicatorsConfigStore().GetByEntityIDs(ctx, tx.Tx(), dbReplicator.Row.ID)
+		if err != nil {
+			return fmt.Errorf("Failed loading replicator config: %w", err)
+		}
+
+		replicatorID = dbReplicator.Row.ID
+		replicator = dbReplicator.ToAPI(config)
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if replicator.Config["cluster"] == "" {
+		return response.BadRequest(fmt.Errorf("Replicator %q has no cluster link configured", name))
+	}
+
+	opType := operationtype.ReplicatorFailover
+	var run func(ctx context.Context, op *operations.Operation) error
+	switch action {
+	case "failover":
+		run = func(ctx context.Context, op *operations.Operation) error {
+			return replicatorFailover(ctx, s, op, replicator, replicatorID)
+		}
+
+	case "failback":
+		run = func(ctx context.Context, op *operations.Operation) error {
+			return replicatorFailback(ctx, s, op, replicator, replicatorID)
+		}
+
+	case "test-failover":
+		// Test failovers create a project, so require the same permission as creating one directly.
+		err = s.Authorizer.CheckPermission(r.Context(), entity.ServerURL(), auth.EntitlementCanCreateProjects)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		opType = operationtype.ReplicatorTestFailover
+		run = func(ctx context.Context, op *operations.Operation) error {
+			return replicatorTestFailover(ctx, s, op, replicator)
+		}
+
+	case "test-failover-cleanup":
+		// The cleanup deletes the test failover project, so require the same permission as deleting it directly.
+		err = s.Authorizer.CheckPermission(r.Context(), entity.ProjectURL(replicatorTestFailoverProject(replicator)), auth.EntitlementCanDelete)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		opType = operationtype.ReplicatorTestFailover
+		run = func(ctx context.Context, op *operations.Operation) error {
+			return replicatorTestFailoverCleanup(ctx, d, s, op, replicator)
+		}
+
+	default:
+		return response.BadRequest(fmt.Errorf("Unknown action %q", action))
+	}
+
+	replicatorURL := entity.ReplicatorURL(projectName, name)
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, operations.OperationArgs{
+		ProjectName:       projectName,
+		EntityURL:         replicatorURL,
+		Type:              opType,
+		Class:             operationtype.OperationClassTask,
+		ConflictReference: "failover:" + replicatorURL.String(), // Distinct from the run conflict reference, as failovers run the replicator themselves.
+		RunHook:           run,
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.ReplicatorFailover.Event(r.Context(), name, projectName, logger.Ctx{"action": action}))
+
+	return response.OperationResponse(op)
+}
+
+// replicatorFailover makes the local project of the replicator the leader of the replication.
+// If the leader cluster is reachable, its instances are stopped and synced back to the local project
+// before its project is demoted, so that no data is lost. The local instances are then started in boot
+// order, and the replicator replicates from the local project to the former leader from then on.
+func replicatorFailover(ctx context.Context, s *state.State, op *operations.Operation, replicator *api.Replicator, replicatorID int64) error {
+	projectName := replicator.Project
+
+	mode, err := replicatorProjectMode(ctx, s, projectName)
+	if err != nil {
+		return err
+	}
+
+	if mode != api.ReplicatorProjectModeStandby {
+		return api.StatusErrorf(http.StatusBadRequest, "Project %q must be in standby mode to fail over", projectName)
+	}
+
+	targetClient, err := replicatorConnectTarget(ctx, s, replicator)
+	if err != nil {
+		// The leader cluster is down, fail over without it.
+		logger.Warn("Failed connecting to leader cluster, failing over without it", logger.Ctx{"project": projectName, "replicator": replicator.Name, "err": err})
+	} else {
+		defer targetClient.Disconnect()
+	}
+
+	// Created after connecting so that the reverter runs before the target client is disconnected.
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if targetClient != nil {
+		targetProject, _, err := targetClient.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("Failed getting project %q from leader cluster: %w", projectName, err)
+		}
+
+		if targetProject.ReplicaMode == api.ReplicatorProjectModeLeader {
+			if targetProject.Config["replica.cluster"] == "" {
+				return api.StatusErrorf(http.StatusBadRequest, "Project %q on the leader cluster must have replica.cluster set to be demoted", projectName)
+			}
+
+			stopped, err := replicatorStopTargetInstances(ctx, targetClient)
+			reverter.Add(func() {
+				replicatorRevertStartInstances(projectName, stopped, replicatorClientInstanceState(targetClient))
+			})
+			if err != nil {
+				return err
+			}
+
+			// Sync the final state of the instances from the leader cluster.
+			err = replicatorRunAndWait(ctx, s, op, replicator, replicatorID, true)
+			if err != nil {
+				return err
+			}
+
+			err = replicatorSetTargetMode(ctx, targetClient, projectName, api.ReplicatorProjectModeStandby)
+			if err != nil {
+				return err
+			}
+
+			// Reverters run in reverse order, so the project is leader again before its instances are restarted.
+			reverter.Add(func() { replicatorRevertTargetMode(targetClient, projectName, api.ReplicatorProjectModeLeader) })
+		}
+	}
+
+	err = projectPromote(ctx, s, projectName, false)
+	if err != nil {
+		return fmt.Errorf("Failed promoting project %q: %w", projectName, err)
+	}
+
+	reverter.Success()
+
+	insts, err := replicatorProjectInstances(ctx, s, projectName)
+	if err != nil {
+		return err
+	}
+
+	configs := make(map[string]map[string]string, len(insts))
+	for _, inst := range insts {
+		configs[inst.Name()] = inst.ExpandedConfig()
+	}
+
+	return replicatorStartInstances(ctx, configs, replicatorLocalInstanceState(s, op, projectName))
+}
+
+// replicatorFailback hands the leadership of the replication back to the target cluster of the replicator,
+// typically the original leader after a failover. The local instances are stopped and synced to the target
+// cluster before the local project is demoted and the target project promoted. The target instances are
+// then started in boot order. If the target project came back in leader mode after an outage, its changes
+// are discarded in favour of the local ones.
+func replicatorFailback(ctx context.Context, s *state.State, op *operations.Operation, replicator *api.Replicator, replicatorID int64) error {
+	projectName := replicator.Project
+
+	mode, err := replicatorProjectMode(ctx, s, projectName)
+	if err != nil {
+		return err
+	}
+
+	if mode != api.ReplicatorProjectModeLeader {
+		return api.StatusErrorf(http.StatusBadRequest, "Project %q must be in leader mode to fail back", projectName)
+	}
+
+	targetClient, err := replicatorConnectTarget(ctx, s, replicator)
+	if err != nil {
+		return err
+	}
+
+	defer targetClient.Disconnect()
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	targetProject, _, err := targetClient.GetProject(projectName)
+	if err != nil {
+		return fmt.Errorf("Failed getting project %q from target cluster: %w", projectName, err)
+	}
+
+	if targetProject.ReplicaMode == api.ReplicatorProjectModeLeader {
+		if targetProject.Config["replica.cluster"] == "" {
+			return api.StatusErrorf(http.StatusBadRequest, "Project %q on the target cluster must have replica.cluster set to be demoted", projectName)
+		}
+
+		stopped, err := replicatorStopTargetInstances(ctx, targetClient)
+		reverter.Add(func() {
+			replicatorRevertStartInstances(projectName, stopped, replicatorClientInstanceState(targetClient))
+		})
+		if err != nil {
+			return err
+		}
+
+		err = replicatorSetTargetMode(ctx, targetClient, projectName, api.ReplicatorProjectModeStandby)
+		if err != nil {
+			return err
+		}
+
+		// Reverters run in reverse order, so the project is leader again before its instances are restarted.
+		reverter.Add(func() { replicatorRevertTargetMode(targetClient, projectName, api.ReplicatorProjectModeLeader) })
+	}
+
+	insts, err := replicatorProjectInstances(ctx, s, projectName)
+	if err != nil {
+		return err
+	}
+
+	configs := make(map[string]map[string]string, len(insts))
+	running := make([]string, 0, len(insts))
+	for _, inst := range insts {
+		configs[inst.Name()] = inst.ExpandedConfig()
+		if inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning {
+			running = append(running, inst.Name())
+		}
+	}
+
+	reverter.Add(func() {
+		runningConfigs := make(map[string]map[string]string, len(running))
+		for _, name := range running {
+			runningConfigs[name] = configs[name]
+		}
+
+		replicatorRevertStartInstances(projectName, runningConfigs, replicatorLocalInstanceState(s, op, projectName))
+	})
+
+	err = replicatorStopInstances(ctx, running, configs, replicatorLocalInstanceState(s, op, projectName))
+	if err != nil {
+		return err
+	}
+
+	// Sync the final state of the instances to the target cluster.
+	err = replicatorRunAndWait(ctx, s, op, replicator, replicatorID, false)
+	if err != nil {
+		return err
+	}
+
+	err = projectDemote(ctx, s, projectName, false)
+	if err != nil {
+		return fmt.Errorf("Failed demoting project %q: %w", projectName, err)
+	}
+
+	reverter.Add(func() {
+		err := projectPromote(context.Background(), s, projectName, false)
+		if err != nil {
+			logger.Warn("Failed promoting project back after failed failback", logger.Ctx{"project": projectName, "err": err})
+		}
+	})
+
+	err = replicatorSetTargetMode(ctx, targetClient, projectName, api.ReplicatorProjectModeLeader)
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	targetInsts, err := targetClient.GetInstances(lxd.GetInstancesArgs{InstanceType: api.InstanceTypeAny})
+	if err != nil {
+		return fmt.Errorf("Failed listing instances on target cluster: %w", err)
+	}
+
+	targetConfigs := make(map[string]map[string]string, len(targetInsts))
+	for _, inst := range targetInsts {
+		targetConfigs[inst.Name] = inst.ExpandedConfig
+	}
+
+	return replicatorStartInstances(ctx, targetConfigs, replicatorClientInstanceState(targetClient))
+}
+
+// replicatorTestFailover clones the instances of the local standby project into an isolated project and
+// starts the clones in boot order, so that failovers can be rehearsed without touching the replicas.
+func replicatorTestFailover(ctx context.Context, s *state.State, op *operations.Operation, replicator *api.Replicator) error {
+	projectName := replicator.Project
+	testProjectName := replicatorTestFailoverProject(replicator)
+
+	mode, err := replicatorProjectMode(ctx, s, projectName)
+	if err != nil {
+		return err
+	}
+
+	if mode != api.ReplicatorProjectModeStandby {
+		return api.StatusErrorf(http.StatusBadRequest, "Project %q must be in standby mode to test a failover", projectName)
+	}
+
+	insts, err := replicatorProjectInstances(ctx, s, projectName)
+	if err != nil {
+		return err
+	}
+
+	// The test project has its own networks, so that the clones can't use the production ones.
+	testProject := api.ProjectsPost{
+		Name: testProjectName,
+		ProjectPut: api.ProjectPut{
+			Description: fmt.Sprintf("Test failover of replicator %q", replicator.Name),
+			Config: map[string]string{
+				"features.networks":             "true",
+				replicatorTestFailoverSourceKey: entity.ReplicatorURL(projectName, replicator.Name).String(),
+			},
+		},
+	}
+
+	for featureName, featureInfo := range dbCluster.ProjectFeatures {
+		_, ok := testProject.Config[featureName]
+		if !ok && featureInfo.DefaultEnabled {
+			testProject.Config[featureName] = "true"
+		}
+	}
+
+	projectExtendNodeConfigSchema(testProjectName)
+
+	err = projectCreate(ctx, s, testProject)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return api.StatusErrorf(http.StatusConflict, "Test failover project %q already exists, clean up the previous test failover first", testProjectName)
+		}
+
+		return err
+	}
+
+	s.Events.SendLifecycle(testProjectName, lifecycle.ProjectCreated.Event(testProjectName, request.CreateRequestor(ctx), nil))
+
+	configs := make(map[string]map[string]string, len(insts))
+	for _, inst := range insts {
+		config, devices := replicatorTestFailoverInstance(inst.ExpandedConfig(), inst.ExpandedDevices().CloneNative())
+
+		err = replicatorTestFailoverClone(ctx, s, op, inst, testProjectName, config, devices)
+		if err != nil {
+			return fmt.Errorf("Failed cloning instance %q into project %q: %w", inst.Name(), testProjectName, err)
+		}
+
+		configs[inst.Name()] = config
+	}
+
+	return replicatorStartInstances(ctx, configs, replicatorLocalInstanceState(s, op, testProjectName))
+}
+
+// replicatorTestFailoverClone copies an instance into the test failover project on the cluster member
+// hosting it, with the given configuration and devices and without snapshots or profiles.
+func replicatorTestFailoverClone(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance, testProjectName string, config map[string]string, devices map[string]map[string]string) error {
+	baseImage := inst.LocalConfig()["volatile.base_image"]
+
+	client, err := lxdCluster.ConnectIfInstanceIsRemote(ctx, s, inst.Project().Name, inst.Name(), inst.Type())
+	if err != nil {
+		return err
+	}
+
+	if client != nil {
+		createOp, err := client.UseProject(testProjectName).UseTarget(inst.Location()).CreateInstance(api.InstancesPost{
+			Name: inst.Name(),
+			InstancePut: api.InstancePut{
+				Config:      config,
+				Devices:     devices,
+				Profiles:    []string{},
+				Description: inst.Description(),
+			},
+			Type: api.InstanceType(inst.Type().String()),
+			Source: api.InstanceSource{
+				Type:         api.SourceTypeCopy,
+				Source:       inst.Name(),
+				Project:      inst.Project().Name,
+				BaseImage:    baseImage,
+				InstanceOnly: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		return createOp.WaitContext(ctx)
+	}
+
+	_, err = instanceCreateAsCopy(ctx, s, instanceCreateAsCopyOpts{
+		sourceInstance: inst,
+		targetInstance: db.InstanceArgs{
+			Name:         inst.Name(),
+			Project:      testProjectName,
+			Type:         inst.Type(),
+			Architecture: inst.Architecture(),
+			BaseImage:    baseImage,
+			Config:       config,
+			Devices:      deviceConfig.NewDevices(devices),
+			Profiles:     []api.Profile{},
+			Description:  inst.Description(),
+		},
+		instanceOnly:         true,
+		applyTemplateTrigger: true,
+	}, op)
+
+	return err
+}
+
+// replicatorTestFailoverCleanup deletes the project created by the last test failover of the replicator,
+// along with the instance clones it contains.
+func replicatorTestFailoverCleanup(ctx context.Context, d *Daemon, s *state.State, op *operations.Operation, replicator *api.Replicator) error {
+	testProjectName := replicatorTestFailoverProject(replicator)
+
+	var testProject *api.Project
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), testProjectName)
+		if err != nil {
+			return err
+		}
+
+		testProject, err = dbProject.ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return api.StatusErrorf(http.StatusNotFound, "Test failover project %q not found", testProjectName)
+		}
+
+		return fmt.Errorf("Failed loading project %q: %w", testProjectName, err)
+	}
+
+	if testProject.Config[replicatorTestFailoverSourceKey] != entity.ReplicatorURL(replicator.Project, replicator.Name).String() {
+		return api.StatusErrorf(http.StatusBadRequest, "Project %q wasn't created by a test failover of replicator %q", testProjectName, replicator.Name)
+	}
+
+	// Delete the entities hosted on this cluster member, the other members delete their own.
+	err = doProjectForceDelete(ctx, request.ClientTypeNormal, op, s, testProjectName, nil)
+	if err != nil {
+		return fmt.Errorf("Failed deleting test failover project entities: %w", err)
+	}
+
+	err = projectDeleteAllMembers(ctx, d, s, testProjectName, true)
+	if err != nil {
+		return err
+	}
+
+	s.Events.SendLifecycle(testProjectName, lifecycle.ProjectDeleted.Event(testProjectName, request.CreateRequestor(ctx), nil))
+
+	return nil
+}
+
+// replicatorTestFailoverProject returns the name of the project used by the test failovers of a replicator.
+func replicatorTestFailoverProject(replicator *api.Replicator) string {
+	if replicator.Config["test_failover.project"] != "" {
+		return replicator.Config["test_failover.project"]
+	}
+
+	return replicator.Project + "-test-failover"
+}
+
+// replicatorTestFailoverInstance returns the configuration and devices of a test failover clone, based on
+// the expanded configuration and devices of the replicated instance. Volatile keys are dropped, as are the
+// devices that would connect the clone to production: network interfaces, proxies and disks other than the root disk.
+// Deletion protection is dropped so that the clones can be cleaned up.
+func replicatorTestFailoverInstance(config map[string]string, devices map[string]map[string]string) (map[string]string, map[string]map[string]string) {
+	cloneConfig := make(map[string]string, len(config))
+	for key, value := range config {
+		if strings.HasPrefix(key, "volatile.") || key == "security.protection.delete" {
+			continue
+		}
+
+		cloneConfig[key] = value
+	}
+
+	cloneDevices := make(map[string]map[string]string, len(devices))
+	for name, device := range devices {
+		switch device["type"] {
+		case "nic", "infiniband", "proxy":
+			continue
+		case "disk":
+			// Only keep the root disk and the disks generated by LXD. Custom volumes, host paths and
+			// remote file systems are shared with the production instances.
+			isRoot := device["path"] == "/" && device["pool"] != ""
+			if !isRoot && device["source"] != "agent:config" && device["source"] != "cloud-init:config" {
+				continue
+			}
+		}
+
+		cloneDevices[name] = device
+	}
+
+	return cloneConfig, cloneDevices
+}
+
+// replicatorRunAndWait runs the replicator as a child of the given operation and waits for it to complete.
+// It fails if the replication of any instance failed.
+func replicatorRunAndWait(ctx context.Context, s *state.State, op *operations.Operation, replicator *api.Replicator, replicatorID int64, restore bool) error {
+	opArgs, err := prepareReplicatorRunOperation(ctx, s, replicator, restore, false, replicatorID)
+	if err != nil {
+		return err
+	}
+
+	// Set status to Running before scheduling the operation, see replicatorStatePut.
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.UpdateReplicatorLastRun(ctx, tx.Tx(), replicatorID, time.Now(), api.ReplicatorStatusRunning)
+	})
+	if err != nil {
+		logger.Warn("Failed updating replicator last run status to running", logger.Ctx{"name": replicator.Name, "project": replicator.Project, "err": err})
+	}
+
+	runOp, err := operations.ScheduleUserOperationFromOperation(s, op, opArgs)
+	if err != nil {
+		// Revert Running to Failed so the status doesn't get stuck.
+		_ = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, api.ReplicatorStatusFailed)
+		})
+
+		return fmt.Errorf("Failed starting replicator run: %w", err)
+	}
+
+	err = runOp.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed running replicator: %w", err)
+	}
+
+	// The run itself succeeds even if some instances failed to replicate.
+	for _, child := range runOp.Children() {
+		err = child.Wait(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed running replicator: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// replicatorConnectTarget connects to the target cluster of the replicator, using the replicator project.
+func replicatorConnectTarget(ctx context.Context, s *state.State, replicator *api.Replicator) (lxd.InstanceServer, error) {
+	var clusterLink *api.ClusterLink
+	var targetCert *x509.Certificate
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		_, clusterLink, targetCert, err = lxdCluster.LoadClusterLinkAndCert(ctx, tx.Tx(), replicator.Config["cluster"])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	args := lxdCluster.GetClusterLinkConnectionArgs(s.Endpoints.NetworkCert(), targetCert)
+	targetClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, args)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to target cluster: %w", err)
+	}
+
+	return targetClient.UseProject(replicator.Project), nil
+}
+
+// replicatorSetTargetMode changes the replica mode of the project on the target cluster.
+func replicatorSetTargetMode(ctx context.Context, targetClient lxd.InstanceServer, projectName string, mode string) error {
+	modeOp, err := targetClient.UpdateProjectState(projectName, api.ProjectStatePut{ReplicaMode: mode}, false)
+	if err == nil {
+		err = modeOp.WaitContext(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed setting replica mode of project %q on target cluster to %q: %w", projectName, mode, err)
+	}
+
+	return nil
+}
+
+// replicatorRevertTargetMode changes the replica mode of the project on the target cluster back after a failure.
+func replicatorRevertTargetMode(targetClient lxd.InstanceServer, projectName string, mode string) {
+	err := replicatorSetTargetMode(context.Background(), targetClient, projectName, mode)
+	if err != nil {
+		logger.Warn("Failed reverting replica mode of project on target cluster", logger.Ctx{"project": projectName, "mode": mode, "err": err})
+	}
+}
+
+// replicatorRevertStartInstances starts the given instances again after a failure.
+func replicatorRevertStartInstances(projectName string, configs map[string]map[string]string, setState replicatorInstanceStateFunc) {
+	err := replicatorStartInstances(context.Background(), configs, setState)
+	if err != nil {
+		logger.Warn("Failed restarting instances after failure", logger.Ctx{"project": projectName, "err": err})
+	}
+}
+
+// replicatorStopTargetInstances stops the running instances of the project on the target cluster.
+// It returns the configuration of the instances it stopped, so that they can be started again on failure.
+func replicatorStopTargetInstances(ctx context.Context, targetClient lxd.InstanceServer) (map[string]map[string]string, error) {
+	targetInsts, err := targetClient.GetInstances(lxd.GetInstancesArgs{InstanceType: api.InstanceTypeAny})
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing instances on target cluster: %w", err)
+	}
+
+	configs := make(map[string]map[string]string, len(targetInsts))
+	running := make([]string, 0, len(targetInsts))
+	for _, inst := range targetInsts {
+		if inst.StatusCode == api.Running {
+			configs[inst.Name] = inst.ExpandedConfig
+			running = append(running, inst.Name)
+		}
+	}
+
+	return configs, replicatorStopInstances(ctx, running, configs, replicatorClientInstanceState(targetClient))
+}
+
+// replicatorProjectMode returns the replica mode of a local project.
+func replicatorProjectMode(ctx context.Context, s *state.State, projectName string) (string, error) {
+	var mode string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return fmt.Errorf("Failed loading project %q: %w", projectName, err)
+		}
+
+		mode = string(dbProject.ReplicaMode)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return mode, nil
+}
+
+// replicatorProjectInstances loads the instances of a local project across all cluster members.
+func replicatorProjectInstances(ctx context.Context, s *state.State, projectName string) ([]instance.Instance, error) {
+	var insts []instance.Instance
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q: %w", dbInst.Name, err)
+			}
+
+			insts = append(insts, inst)
+			return nil
+		}, dbCluster.InstanceFilter{Project: &projectName})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing project instances: %w", err)
+	}
+
+	return insts, nil
+}
+
+// replicatorLocalInstanceState returns a [replicatorInstanceStateFunc] for the instances of a local project.
+// Instances hosted on other cluster members are changed through the member hosting them.
+func replicatorLocalInstanceState(s *state.State, op *operations.Operation, projectName string) replicatorInstanceStateFunc {
+	return func(ctx context.Context, name string, action string, timeout int) error {
+		inst, err := instance.LoadByProjectAndName(s, projectName, name)
+		if err != nil {
+			return err
+		}
+
+		client, err := lxdCluster.ConnectIfInstanceIsRemote(ctx, s, projectName, name, inst.Type())
+		if err != nil {
+			return err
+		}
+
+		if client != nil {
+			return replicatorClientInstanceState(client)(ctx, name, action, timeout)
+		}
+
+		req := api.InstanceStatePut{Action: action, Timeout: timeout}
+		err = doInstanceStatePut(ctx, inst, req, op)
+		if err != nil && action == "stop" {
+			// Force stop instances that don't shut down in time.
+			req.Force = true
+			err = doInstanceStatePut(ctx, inst, req, op)
+		}
+
+		return err
+	}
+}
+
+// replicatorClientInstanceState returns a [replicatorInstanceStateFunc] for the instances of a remote project.
+func replicatorClientInstanceState(client lxd.InstanceServer) replicatorInstanceStateFunc {
+	return func(ctx context.Context, name string, action string, timeout int) error {
+		req := api.InstanceStatePut{Action: action, Timeout: timeout}
+		stateOp, err := client.UpdateInstanceState(name, req, "")
+		if err == nil {
+			err = stateOp.WaitContext(ctx)
+		}
+
+		if err != nil && action == "stop" {
+			// Force stop instances that don't shut down in time.
+			req.Force = true
+			stateOp, err = client.UpdateInstanceState(name, req, "")
+			if err == nil {
+				err = stateOp.WaitContext(ctx)
+			}
+		}
+
+		return err
+	}
+}
+
+// replicatorStartInstances starts the instances in boot order, waiting for their boot.autostart.delay
+// after each start. A failure to start an instance doesn't prevent the others from being started.
+func replicatorStartInstances(ctx context.Context, configs map[string]map[string]string, setState replicatorInstanceStateFunc) error {
+	var errs []error
+	for _, name := range replicatorBootOrder(configs) {
+		err := setState(ctx, name, "start", 0)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed starting instance %q: %w", name, err))
+			continue
+		}
+
+		delay, err := strconv.Atoi(configs[name]["boot.autostart.delay"])
+		if err == nil && delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(delay) * time.Second):
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// replicatorStopInstances stops the given instances in reverse boot order, waiting for each of them to shut
+// down for up to its boot.host_shutdown_timeout.
+func replicatorStopInstances(ctx context.Context, names []string, configs map[string]map[string]string, setState replicatorInstanceStateFunc) error {
+	replicatorSortByBootPriority(names, configs)
+
+	for i := len(names) - 1; i >= 0; i-- {
+		timeout, err := strconv.Atoi(configs[names[i]]["boot.host_shutdown_timeout"])
+		if err != nil {
+			timeout = 30
+		}
+
+		err = setState(ctx, names[i], "stop", timeout)
+		if err != nil {
+			return fmt.Errorf("Failed stopping instance %q: %w", names[i], err)
+		}
+	}
+
+	return nil
+}
+
+// replicatorBootOrder returns the names of the instances to start after a failover, by descending
+// boot.autostart.priority and then by name. Instances with boot.autostart set to false or with
+// security.protection.start enabled are left stopped.
+func replicatorBootOrder(configs map[string]map[string]string) []string {
+	names := make([]string, 0, len(configs))
+	for name, config := range configs {
+		if shared.IsFalse(config["boot.autostart"]) || shared.IsTrue(config["security.protection.start"]) {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	replicatorSortByBootPriority(names, configs)
+
+	return names
+}
+
+// replicatorSortByBootPriority sorts instance names by descending boot.autostart.priority and then by name.
+func replicatorSortByBootPriority(names []string, configs map[string]map[string]string) {
+	sort.SliceStable(names, func(i, j int) bool {
+		iPriority, _ := strconv.Atoi(configs[names[i]]["boot.autostart.priority"])
+		jPriority, _ := strconv.Atoi(configs[names[j]]["boot.autostart.priority"])
+		if iPriority != jPriority {
+			return iPriority > jPriority
+		}
+
+		return names[i] < names[j]
+	})
+}
@@ -0,0 +1,715 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/client"
+	lxdCluster "github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/units"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// replicatorScope holds the replicator settings selecting what a run transfers and how fast.
+type replicatorScope struct {
+	// Name patterns, an entity must match one of them when set.
+	names []string
+
+	// User keys and values, an entity must have all of them when set.
+	labels map[string]string
+
+	// Whether snapshots are transferred.
+	snapshots bool
+
+	// Maximum transfer rate of the whole run in bytes per second, zero for no limit.
+	bandwidth int64
+
+	// Slots limiting the number of concurrent transfers, nil for no limit.
+	slots chan struct{}
+
+	// Limiter shared by the transfers sending data from this server, nil for no limit.
+	limiter *util.RateLimiter
+
+	// Maximum rate in bytes per second of each transfer that another server sends, such as another cluster member
+	// or the target cluster. Those transfers cannot share the limiter, so they get an even share of the bandwidth
+	// instead. Set once the number of transfers is known.
+	transferRate int64
+}
+
+// replicatorLoadScope parses the filter, snapshot and limit settings of a replicator.
+func replicatorLoadScope(config map[string]string) (*replicatorScope, error) {
+	scope := &replicatorScope{
+		names:     shared.SplitNTrimSpace(config["filter.names"], ",", -1, true),
+		labels:    map[string]string{},
+		snapshots: shared.IsFalseOrEmpty(config["snapshots.exclude"]),
+	}
+
+	for _, label := range shared.SplitNTrimSpace(config["filter.labels"], ",", -1, true) {
+		key, value, _ := strings.Cut(label, "=")
+		scope.labels[key] = value
+	}
+
+	if config["limits.bandwidth"] != "" {
+		bandwidth, err := units.ParseBitSizeString(config["limits.bandwidth"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid replicator bandwidth limit: %w", err)
+		}
+
+		scope.bandwidth = bandwidth / 8
+		scope.limiter = util.NewRateLimiter(scope.bandwidth)
+	}
+
+	if config["limits.concurrency"] != "" {
+		concurrency, err := strconv.Atoi(config["limits.concurrency"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid replicator concurrency limit: %w", err)
+		}
+
+		scope.slots = make(chan struct{}, concurrency)
+	}
+
+	return scope, nil
+}
+
+// replicatorValidateLabel validates a "user.<key>=<value>" label filter.
+func replicatorValidateLabel(value string) error {
+	key, _, found := strings.Cut(value, "=")
+	if !found {
+		return errors.New(`Label filters must be in the form "user.<key>=<value>"`)
+	}
+
+	if !strings.HasPrefix(key, "user.") || key == "user." {
+		return errors.New("Label filters can only match user keys")
+	}
+
+	return nil
+}
+
+// replicatorValidateNamePattern validates a name pattern.
+func replicatorValidateNamePattern(value string) error {
+	_, err := path.Match(value, "")
+	if err != nil {
+		return fmt.Errorf("Invalid name pattern: %w", err)
+	}
+
+	return nil
+}
+
+// selects returns whether the entity with the given name and configuration is replicated.
+func (sc *replicatorScope) selects(name string, config map[string]string) bool {
+	if len(sc.names) > 0 {
+		matched := false
+		for _, pattern := range sc.names {
+			ok, _ := path.Match(pattern, name)
+			if ok {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	for key, value := range sc.labels {
+		if config[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setTransfers sets the share of the bandwidth of the transfers that another server sends, evenly split between the
+// transfers that can run at the same time.
+func (sc *replicatorScope) setTransfers(transfers int) {
+	sc.transferRate = replicatorTransferRate(sc.bandwidth, cap(sc.slots), transfers)
+}
+
+// replicatorTransferRate returns the rate of each transfer when the bandwidth is shared by up to concurrency
+// transfers at a time (zero for no concurrency limit) out of the given number of transfers.
+func replicatorTransferRate(bandwidth int64, concurrency int, transfers int) int64 {
+	if bandwidth <= 0 || transfers <= 0 {
+		return bandwidth
+	}
+
+	parallel := transfers
+	if concurrency > 0 && concurrency < transfers {
+		parallel = concurrency
+	}
+
+	return max(bandwidth/int64(parallel), 1)
+}
+
+// acquire waits for a free transfer slot.
+func (sc *replicatorScope) acquire(ctx context.Context) error {
+	if sc.slots == nil {
+		return nil
+	}
+
+	select {
+	case sc.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the transfer slot taken by acquire.
+func (sc *replicatorScope) release() {
+	if sc.slots != nil {
+		<-sc.slots
+	}
+}
+
+// replicatorInstanceVolumes returns the "<pool>/<volume>" keys of the custom volumes attached to an instance.
+func replicatorInstanceVolumes(inst instance.Instance) []string {
+	var volumes []string
+	for _, dev := range inst.ExpandedDevices() {
+		if dev["type"] != "disk" || dev["pool"] == "" || dev["path"] == "/" || dev["source"] == "" || shared.IsSnapshot(dev["source"]) {
+			continue
+		}
+
+		volumes = append(volumes, dev["pool"]+"/"+dev["source"])
+	}
+
+	return volumes
+}
+
+// replicateCustomVolume pushes a custom storage volume to the pool with the same name on the target cluster,
+// refreshing it if it already exists there.
+func replicateCustomVolume(ctx context.Context, s *state.State, op *operations.Operation, vol *db.StorageVolume, memberAddress string, dstClient lxd.InstanceServer, targetCertPEM string, scope *replicatorScope) error {
+	// Set up a push-mode migration sink on the destination.
+	destOp, err := dstClient.CreateStoragePoolVolume(vol.Pool, api.StorageVolumesPost{
+		Name:        vol.Name,
+		Type:        vol.Type,
+		ContentType: vol.ContentType,
+		StorageVolumePut: api.StorageVolumePut{
+			Config:      vol.Config,
+			Description: vol.Description,
+		},
+		Source: api.StorageVolumeSource{
+			Type:       api.SourceTypeMigration,
+			Mode:       "push",
+			Refresh:    true,
+			VolumeOnly: !scope.snapshots,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed requesting volume create on destination for %q: %w", vol.Name, err)
+	}
+
+	destOpCancelled := false
+	defer func() {
+		if !destOpCancelled {
+			_ = destOp.Cancel()
+		}
+	}()
+
+	destOpAPI := destOp.Get()
+	destSecrets, err := destOpAPI.WebsocketSecrets()
+	if err != nil {
+		return fmt.Errorf("Failed getting websocket secrets from destination for volume %q: %w", vol.Name, err)
+	}
+
+	pushTarget := &api.StorageVolumePostTarget{
+		Operation:   destOp.URL().String(),
+		Websockets:  destSecrets,
+		Certificate: targetCertPEM,
+	}
+
+	// Volume on a local pool of another cluster member: have the hosting member push the volume.
+	if vol.Location != "" && vol.Location != s.ServerName {
+		if memberAddress == "" {
+			return fmt.Errorf("Failed resolving cluster member address for volume %q", vol.Name)
+		}
+
+		memberClient, err := lxdCluster.Connect(ctx, memberAddress, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to hosting cluster member for volume %q: %w", vol.Name, err)
+		}
+
+		srcMigrateOp, err := memberClient.UseProject(vol.Project).MigrateStoragePoolVolume(vol.Pool, api.StorageVolumePost{
+			Name:         vol.Name,
+			Migration:    true,
+			Target:       pushTarget,
+			VolumeOnly:   !scope.snapshots,
+			TransferRate: scope.transferRate,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed starting push migration for volume %q: %w", vol.Name, err)
+		}
+
+		destOpCancelled = true
+
+		err = srcMigrateOp.Wait()
+		if err != nil {
+			return fmt.Errorf("Replication of volume %q failed on hosting cluster member: %w", vol.Name, err)
+		}
+
+		return destOp.Wait()
+	}
+
+	srcMigration, err := newStorageMigrationSource(!scope.snapshots, pushTarget)
+	if err != nil {
+		return fmt.Errorf("Failed setting up migration source for volume %q: %w", vol.Name, err)
+	}
+
+	srcMigration.rateLimiter = scope.limiter
+
+	migrArgs := operations.OperationArgs{
+		ProjectName: vol.Project,
+		EntityURL:   entity.StorageVolumeURL(vol.Project, vol.Location, vol.Pool, vol.Type, vol.Name),
+		Type:        operationtype.VolumeMigrate,
+		Class:       operationtype.OperationClassTask,
+		RunHook: func(ctx context.Context, innerOp *operations.Operation) error {
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				select {
+				case <-done:
+				case <-ctx.Done():
+					srcMigration.disconnect()
+				}
+			}()
+
+			return srcMigration.DoStorage(s, vol.Project, vol.Pool, vol.Name, innerOp)
+		},
+	}
+
+	var srcOp *operations.Operation
+	if op.Requestor() != nil {
+		srcOp, err = operations.ScheduleUserOperationFromOperation(s, op, migrArgs)
+	} else {
+		srcOp, err = operations.ScheduleServerOperation(s, migrArgs)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	destOpCancelled = true // source is now connected via websockets; cancel would interrupt an in-flight transfer
+
+	err = srcOp.Wait(context.Background())
+	if err != nil {
+		return fmt.Errorf("Replication of volume %q failed on source: %w", vol.Name, err)
+	}
+
+	return destOp.Wait()
+}
+
+// replicatorRemoteVolume is a custom volume of the current leader cluster restored by a replicator.
+type replicatorRemoteVolume struct {
+	pool   string
+	volume api.StorageVolume
+}
+
+// replicatorRemoteBucket is a storage bucket of the current leader cluster restored by a replicator.
+type replicatorRemoteBucket struct {
+	pool   string
+	bucket api.StorageBucket
+}
+
+// replicatorListRemoteStorage returns the custom volumes and buckets of the project on the current leader cluster
+// that are selected by the replicator. Volumes and buckets are only listed if the project has its own.
+func replicatorListRemoteStorage(client lxd.InstanceServer, scope *replicatorScope, volumes bool, buckets bool) ([]replicatorRemoteVolume, []replicatorRemoteBucket, error) {
+	if !volumes && !buckets {
+		return nil, nil, nil
+	}
+
+	pools, err := client.GetStoragePools()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed listing storage pools on target: %w", err)
+	}
+
+	var remoteVolumes []replicatorRemoteVolume
+	var remoteBuckets []replicatorRemoteBucket
+	for _, pool := range pools {
+		if volumes {
+			poolVolumes, err := client.GetStoragePoolVolumes(pool.Name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed listing volumes of storage pool %q on target: %w", pool.Name, err)
+			}
+
+			for _, vol := range poolVolumes {
+				if vol.Type != dbCluster.StoragePoolVolumeTypeNameCustom || shared.IsSnapshot(vol.Name) || !scope.selects(vol.Name, vol.Config) {
+					continue
+				}
+
+				remoteVolumes = append(remoteVolumes, replicatorRemoteVolume{pool: pool.Name, volume: vol})
+			}
+		}
+
+		if buckets {
+			poolBuckets, err := client.GetStoragePoolBuckets(pool.Name)
+			if err != nil {
+				// Skip storage pools whose driver doesn't support buckets.
+				if api.StatusErrorCheck(err, http.StatusBadRequest) {
+					continue
+				}
+
+				return nil, nil, fmt.Errorf("Failed listing buckets of storage pool %q on target: %w", pool.Name, err)
+			}
+
+			for _, bucket := range poolBuckets {
+				if scope.selects(bucket.Name, bucket.Config) {
+					remoteBuckets = append(remoteBuckets, replicatorRemoteBucket{pool: pool.Name, bucket: bucket})
+				}
+			}
+		}
+	}
+
+	return remoteVolumes, remoteBuckets, nil
+}
+
+// replicatorLocalAddress returns the address at which the target cluster can reach this server to push data to it.
+func replicatorLocalAddress(s *state.State, nodeAddressByName map[string]string) (string, error) {
+	// For clustered members the address from the nodes table is already a concrete, registered address. For
+	// unclustered servers the nodes table stores the sentinel "0.0.0.0", so fall back to the configured HTTPS
+	// address. Return an error if we still cannot determine a concrete address, because the leader would not be
+	// able to reach us.
+	localAddress := nodeAddressByName[s.ServerName]
+	if util.IsWildCardAddress(localAddress) {
+		localAddress = s.LocalConfig.ClusterAddress()
+		if localAddress == "" {
+			localAddress = s.LocalConfig.HTTPSAddress()
+		}
+
+		if util.IsWildCardAddress(localAddress) || localAddress == "" {
+			return "", errors.New("Cannot restore to this server: configure a concrete address using cluster.https_address or core.https_address")
+		}
+	}
+
+	return localAddress, nil
+}
+
+// restoreCustomVolume refreshes a custom storage volume from the volume with the same name in the pool with the same
+// name on the current leader cluster, creating it if needed. The volume is received by the cluster member at
+// memberAddress if it is hosted by another member, otherwise by this member, which the leader reaches at
+// localAddress.
+func restoreCustomVolume(ctx context.Context, s *state.State, op *operations.Operation, projectName string, poolName string, vol api.StorageVolume, memberAddress string, localAddress string, srcClient lxd.InstanceServer, localCertPEM string, scope *replicatorScope) error {
+	req := api.StorageVolumesPost{
+		Name:        vol.Name,
+		Type:        vol.Type,
+		ContentType: vol.ContentType,
+		StorageVolumePut: api.StorageVolumePut{
+			Config:      vol.Config,
+			Description: vol.Description,
+		},
+		Source: api.StorageVolumeSource{
+			Type:       api.SourceTypeMigration,
+			Mode:       "push",
+			Refresh:    true,
+			VolumeOnly: !scope.snapshots,
+		},
+	}
+
+	var sinkURL string
+	var sinkSecrets map[string]string
+	var sinkWait func() error
+	var sinkCancel func()
+
+	if memberAddress != "" {
+		// Set up a push-mode migration sink on the hosting cluster member.
+		memberClient, err := lxdCluster.Connect(ctx, memberAddress, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to hosting cluster member for volume %q: %w", vol.Name, err)
+		}
+
+		sinkOp, err := memberClient.UseProject(projectName).CreateStoragePoolVolume(poolName, req)
+		if err != nil {
+			return fmt.Errorf("Failed requesting restore on hosting cluster member for volume %q: %w", vol.Name, err)
+		}
+
+		sinkOpAPI := sinkOp.Get()
+		sinkSecrets, err = sinkOpAPI.WebsocketSecrets()
+		if err != nil {
+			_ = sinkOp.Cancel()
+			return fmt.Errorf("Failed getting websocket secrets from hosting cluster member for volume %q: %w", vol.Name, err)
+		}
+
+		sinkURL = sinkOp.URL().String()
+		sinkWait = sinkOp.Wait
+		sinkCancel = func() { _ = sinkOp.Cancel() }
+	} else {
+		// Set up a push-mode migration sink locally.
+		sink, err := newStorageMigrationSink(&migrationSinkArgs{
+			push:       true,
+			volumeOnly: !scope.snapshots,
+			refresh:    true,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed setting up migration sink for volume %q: %w", vol.Name, err)
+		}
+
+		sinkOpArgs := operations.OperationArgs{
+			ProjectName: projectName,
+			EntityURL:   api.NewURL().Path(version.APIVersion, "projects", projectName),
+			Type:        operationtype.VolumeCreate,
+			Class:       operationtype.OperationClassWebsocket,
+			Metadata:    sink.Metadata(),
+			ConnectHook: sink.Connect,
+			RunHook: func(ctx context.Context, op *operations.Operation) error {
+				return sink.DoStorage(ctx, s, projectName, poolName, &req, op)
+			},
+		}
+
+		var sinkOp *operations.Operation
+		if op.Requestor() != nil {
+			sinkOp, err = operations.ScheduleUserOperationFromOperation(s, op, sinkOpArgs)
+		} else {
+			sinkOp, err = operations.ScheduleServerOperation(s, sinkOpArgs)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed scheduling migration sink operation for volume %q: %w", vol.Name, err)
+		}
+
+		_, sinkOpAPI := sinkOp.Render()
+		sinkSecrets, err = sinkOpAPI.WebsocketSecrets()
+		if err != nil {
+			sinkOp.Cancel()
+			return fmt.Errorf("Failed getting websocket secrets from local sink for volume %q: %w", vol.Name, err)
+		}
+
+		sinkURL = "https://" + localAddress + sinkOp.URL()
+		sinkWait = func() error { return sinkOp.Wait(context.Background()) }
+		sinkCancel = sinkOp.Cancel
+	}
+
+	// Tell the current leader cluster to push the volume to the sink.
+	srcOp, err := srcClient.MigrateStoragePoolVolume(poolName, api.StorageVolumePost{
+		Name:         vol.Name,
+		Migration:    true,
+		VolumeOnly:   !scope.snapshots,
+		TransferRate: scope.transferRate,
+		Target: &api.StorageVolumePostTarget{
+			Operation:   sinkURL,
+			Websockets:  sinkSecrets,
+			Certificate: localCertPEM,
+		},
+	})
+	if err != nil {
+		sinkCancel()
+		return fmt.Errorf("Failed starting push migration on current leader cluster for volume %q: %w", vol.Name, err)
+	}
+
+	err = srcOp.Wait()
+	if err != nil {
+		sinkCancel()
+		return fmt.Errorf("Restore of volume %q failed on current leader cluster: %w", vol.Name, err)
+	}
+
+	err = sinkWait()
+	if err != nil {
+		return fmt.Errorf("Restore of volume %q failed: %w", vol.Name, err)
+	}
+
+	return nil
+}
+
+// replicatorBucket gives access to the objects of a storage bucket.
+type replicatorBucket struct {
+	list   func() ([]api.StorageBucketObject, error)
+	get    func(objectName string) (io.ReadCloser, int64, error)
+	put    func(objectName string, content io.Reader, size int64) error
+	delete func(objectName string) error
+}
+
+// replicatorLocalBucket returns access to a storage bucket of this cluster, through the cluster member at
+// memberAddress if it is hosted by another member.
+func replicatorLocalBucket(ctx context.Context, s *state.State, projectName string, poolName string, bucketName string, memberAddress string) (*replicatorBucket, error) {
+	if memberAddress != "" {
+		memberClient, err := lxdCluster.Connect(ctx, memberAddress, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed connecting to hosting cluster member for bucket %q: %w", bucketName, err)
+		}
+
+		return replicatorClientBucket(memberClient.UseProject(projectName), poolName, bucketName), nil
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
+	}
+
+	return &replicatorBucket{
+		list: func() ([]api.StorageBucketObject, error) {
+			return pool.ListBucketObjects(projectName, bucketName, "")
+		},
+		get: func(objectName string) (io.ReadCloser, int64, error) {
+			return pool.GetBucketObject(projectName, bucketName, objectName)
+		},
+		put: func(objectName string, content io.Reader, size int64) error {
+			return pool.PutBucketObject(projectName, bucketName, objectName, content, size)
+		},
+		delete: func(objectName string) error {
+			return pool.DeleteBucketObject(projectName, bucketName, objectName)
+		},
+	}, nil
+}
+
+// replicatorClientBucket returns access to a storage bucket through the given client.
+func replicatorClientBucket(client lxd.InstanceServer, poolName string, bucketName string) *replicatorBucket {
+	return &replicatorBucket{
+		list: func() ([]api.StorageBucketObject, error) {
+			return client.GetStoragePoolBucketObjects(poolName, bucketName, "")
+		},
+		get: func(objectName string) (io.ReadCloser, int64, error) {
+			return client.GetStoragePoolBucketObject(poolName, bucketName, objectName)
+		},
+		put: func(objectName string, content io.Reader, size int64) error {
+			return client.CreateStoragePoolBucketObject(poolName, bucketName, objectName, content, size)
+		},
+		delete: func(objectName string) error {
+			return client.DeleteStoragePoolBucketObject(poolName, bucketName, objectName)
+		},
+	}
+}
+
+// replicatorObjectUnchanged returns whether the destination object is an up to date copy of the source object.
+// Entity tags only match for objects uploaded in a single part, so objects uploaded in several parts are compared by
+// size and modification time instead: the copy is up to date if it was written after the source was last modified.
+func replicatorObjectUnchanged(src api.StorageBucketObject, dst api.StorageBucketObject) bool {
+	if src.Size != dst.Size {
+		return false
+	}
+
+	return src.ETag == dst.ETag || !dst.LastModified.Before(src.LastModified)
+}
+
+// syncBucket mirrors the objects of the source bucket to the destination bucket. Objects that are unchanged
+// according to replicatorObjectUnchanged are skipped and objects missing from the source are deleted from the
+// destination.
+func syncBucket(ctx context.Context, bucketName string, src *replicatorBucket, dst *replicatorBucket, limiter *util.RateLimiter) error {
+	srcObjects, err := src.list()
+	if err != nil {
+		return fmt.Errorf("Failed listing objects of bucket %q on source: %w", bucketName, err)
+	}
+
+	dstObjects, err := dst.list()
+	if err != nil {
+		return fmt.Errorf("Failed listing objects of bucket %q on destination: %w", bucketName, err)
+	}
+
+	dstByName := make(map[string]api.StorageBucketObject, len(dstObjects))
+	for _, object := range dstObjects {
+		dstByName[object.Name] = object
+	}
+
+	for _, object := range srcObjects {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		dstObject, ok := dstByName[object.Name]
+		delete(dstByName, object.Name)
+		if ok && replicatorObjectUnchanged(object, dstObject) {
+			continue
+		}
+
+		content, size, err := src.get(object.Name)
+		if err != nil {
+			return fmt.Errorf("Failed reading object %q of bucket %q: %w", object.Name, bucketName, err)
+		}
+
+		err = dst.put(object.Name, util.SharedRateLimitReader(content, limiter), size)
+		_ = content.Close()
+		if err != nil {
+			return fmt.Errorf("Failed replicating object %q of bucket %q: %w", object.Name, bucketName, err)
+		}
+	}
+
+	// Objects left over were deleted from the source.
+	for name := range dstByName {
+		err := dst.delete(name)
+		if err != nil {
+			return fmt.Errorf("Failed deleting object %q of bucket %q on destination: %w", name, bucketName, err)
+		}
+	}
+
+	return nil
+}
+
+// replicateBucket mirrors the objects of a storage bucket to the bucket with the same name in the pool with the
+// same name on the target cluster, creating it if needed.
+func replicateBucket(ctx context.Context, s *state.State, bucket *db.StorageBucket, memberAddress string, dstClient lxd.InstanceServer, scope *replicatorScope) error {
+	if bucket.Location == "" || bucket.Location == s.ServerName {
+		memberAddress = ""
+	} else if memberAddress == "" {
+		return fmt.Errorf("Failed resolving cluster member address for bucket %q", bucket.Name)
+	}
+
+	src, err := replicatorLocalBucket(ctx, s, bucket.Project, bucket.PoolName, bucket.Name, memberAddress)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = dstClient.GetStoragePoolBucket(bucket.PoolName, bucket.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("Failed getting bucket %q on destination: %w", bucket.Name, err)
+		}
+
+		createOp, err := dstClient.CreateStoragePoolBucket(bucket.PoolName, api.StorageBucketsPost{
+			Name:             bucket.Name,
+			StorageBucketPut: bucket.Writable(),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed creating bucket %q on destination: %w", bucket.Name, err)
+		}
+
+		err = createOp.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed creating bucket %q on destination: %w", bucket.Name, err)
+		}
+	}
+
+	return syncBucket(ctx, bucket.Name, src, replicatorClientBucket(dstClient, bucket.PoolName, bucket.Name), scope.limiter)
+}
+
+// restoreBucket mirrors the objects of a storage bucket of the current leader cluster to the bucket with the same
+// name in the pool with the same name on this cluster. The local bucket is localBucket, hosted by the cluster member
+// at memberAddress if that is not this member. If there is no local bucket, it is created on this member.
+func restoreBucket(ctx context.Context, s *state.State, projectName string, poolName string, bucket api.StorageBucket, localBucket *db.StorageBucket, memberAddress string, srcClient lxd.InstanceServer, scope *replicatorScope) error {
+	if localBucket == nil {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			return fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
+		}
+
+		err = pool.CreateBucket(projectName, api.StorageBucketsPost{
+			Name:             bucket.Name,
+			StorageBucketPut: bucket.Writable(),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed creating bucket %q: %w", bucket.Name, err)
+		}
+	}
+
+	dst, err := replicatorLocalBucket(ctx, s, projectName, poolName, bucket.Name, memberAddress)
+	if err != nil {
+		return err
+	}
+
+	return syncBucket(ctx, bucket.Name, replicatorClientBucket(srcClient, poolName, bucket.Name), dst, scope.limiter)
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared/api"
 )
 
 func TestReplicatorIsScheduledNow(t *testing.T) {
@@ -66,3 +69,201 @@ func TestReplicatorIsScheduledNow(t *testing.T) {
 		})
 	}
 }
+
+func TestReplicatorInstanceLag(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	createdAt := now.Add(-24 * time.Hour)
+	replicatedAt := now.Add(-10 * time.Minute)
+
+	tests := []struct {
+		name       string
+		lastUsedAt time.Time
+		running    bool
+		record     *dbCluster.ReplicatorInstance
+		want       time.Duration
+	}{
+		{name: "never replicated", running: false, record: nil, want: 24 * time.Hour},
+		{name: "running instance", lastUsedAt: createdAt, running: true, record: &dbCluster.ReplicatorInstance{LastReplicatedDate: replicatedAt, Stopped: true}, want: 10 * time.Minute},
+		{name: "stopped instance replicated while stopped", lastUsedAt: createdAt, running: false, record: &dbCluster.ReplicatorInstance{LastReplicatedDate: replicatedAt, Stopped: true}, want: 0},
+		{name: "stopped instance replicated while running", lastUsedAt: createdAt, running: false, record: &dbCluster.ReplicatorInstance{LastReplicatedDate: replicatedAt, Stopped: false}, want: 10 * time.Minute},
+		{name: "stopped instance started since replication", lastUsedAt: now.Add(-5 * time.Minute), running: false, record: &dbCluster.ReplicatorInstance{LastReplicatedDate: replicatedAt, Stopped: true}, want: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replicatorInstanceLag(now, createdAt, tt.lastUsedAt, tt.running, tt.record)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplicatorBootOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs map[string]map[string]string
+		want    []string
+	}{
+		{name: "no instances", configs: map[string]map[string]string{}, want: []string{}},
+		{
+			name: "sorted by name without priorities",
+			configs: map[string]map[string]string{
+				"c2": {},
+				"c1": {},
+			},
+			want: []string{"c1", "c2"},
+		},
+		{
+			name: "sorted by descending priority",
+			configs: map[string]map[string]string{
+				"db":    {"boot.autostart.priority": "10"},
+				"web":   {},
+				"cache": {"boot.autostart.priority": "5"},
+			},
+			want: []string{"db", "cache", "web"},
+		},
+		{
+			name: "autostart disabled and start protection",
+			configs: map[string]map[string]string{
+				"c1": {"boot.autostart": "false"},
+				"c2": {"security.protection.start": "true"},
+				"c3": {"boot.autostart": "true"},
+			},
+			want: []string{"c3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replicatorBootOrder(tt.configs))
+		})
+	}
+}
+
+func TestReplicatorTestFailoverInstance(t *testing.T) {
+	config := map[string]string{
+		"limits.cpu":                 "2",
+		"boot.autostart.priority":    "10",
+		"security.protection.delete": "true",
+		"volatile.uuid":              "bc3e7f02-3b64-4d5b-a2b6-c0d4d2b3a2a0",
+	}
+
+	devices := map[string]map[string]string{
+		"root":  {"type": "disk", "path": "/", "pool": "default"},
+		"data":  {"type": "disk", "path": "/data", "pool": "default", "source": "data"},
+		"host":  {"type": "disk", "path": "/mnt", "source": "/srv"},
+		"ceph":  {"type": "disk", "path": "/ceph", "source": "ceph:rbd/vol"},
+		"init":  {"type": "disk", "source": "cloud-init:config"},
+		"eth0":  {"type": "nic", "network": "lxdbr0"},
+		"ib0":   {"type": "infiniband", "nictype": "physical", "parent": "ib0"},
+		"http":  {"type": "proxy", "listen": "tcp:0.0.0.0:80", "connect": "tcp:127.0.0.1:80"},
+		"gpu":   {"type": "gpu"},
+		"agent": {"type": "disk", "source": "agent:config"},
+	}
+
+	cloneConfig, cloneDevices := replicatorTestFailoverInstance(config, devices)
+
+	assert.Equal(t, map[string]string{"limits.cpu": "2", "boot.autostart.priority": "10"}, cloneConfig)
+	assert.Equal(t, map[string]map[string]string{
+		"root":  devices["root"],
+		"gpu":   devices["gpu"],
+		"agent": devices["agent"],
+		"init":  devices["init"],
+	}, cloneDevices)
+}
+
+func TestReplicatorScopeSelects(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		entity string
+		labels map[string]string
+		want   bool
+	}{
+		{name: "no filters", config: map[string]string{}, entity: "c1", want: true},
+		{name: "name matches", config: map[string]string{"filter.names": "web-*, db"}, entity: "web-1", want: true},
+		{name: "exact name matches", config: map[string]string{"filter.names": "web-*, db"}, entity: "db", want: true},
+		{name: "name doesn't match", config: map[string]string{"filter.names": "web-*, db"}, entity: "db-1", want: false},
+		{name: "labels match", config: map[string]string{"filter.labels": "user.tier=gold,user.dr=true"}, entity: "c1", labels: map[string]string{"user.tier": "gold", "user.dr": "true", "user.other": "x"}, want: true},
+		{name: "label missing", config: map[string]string{"filter.labels": "user.tier=gold,user.dr=true"}, entity: "c1", labels: map[string]string{"user.tier": "gold"}, want: false},
+		{name: "label differs", config: map[string]string{"filter.labels": "user.tier=gold"}, entity: "c1", labels: map[string]string{"user.tier": "silver"}, want: false},
+		{name: "name and labels match", config: map[string]string{"filter.names": "web-*", "filter.labels": "user.tier=gold"}, entity: "web-1", labels: map[string]string{"user.tier": "gold"}, want: true},
+		{name: "labels match but name doesn't", config: map[string]string{"filter.names": "web-*", "filter.labels": "user.tier=gold"}, entity: "db", labels: map[string]string{"user.tier": "gold"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := replicatorLoadScope(tt.config)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, scope.selects(tt.entity, tt.labels))
+		})
+	}
+}
+
+func TestReplicatorLoadScope(t *testing.T) {
+	scope, err := replicatorLoadScope(map[string]string{"limits.bandwidth": "80Mbit", "limits.concurrency": "2", "snapshots.exclude": "true"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10_000_000), scope.bandwidth)
+	assert.Equal(t, 2, cap(scope.slots))
+	assert.NotNil(t, scope.limiter)
+	assert.False(t, scope.snapshots)
+
+	scope, err = replicatorLoadScope(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), scope.bandwidth)
+	assert.Nil(t, scope.slots)
+	assert.Nil(t, scope.limiter)
+	assert.True(t, scope.snapshots)
+}
+
+func TestReplicatorObjectUnchanged(t *testing.T) {
+	modified := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	src := api.StorageBucketObject{Name: "foo", Size: 10, ETag: "abc-2", LastModified: modified}
+
+	tests := []struct {
+		name string
+		dst  api.StorageBucketObject
+		want bool
+	}{
+		{name: "same entity tag", dst: api.StorageBucketObject{Size: 10, ETag: "abc-2", LastModified: modified.Add(-time.Hour)}, want: true},
+		{name: "copied after modification", dst: api.StorageBucketObject{Size: 10, ETag: "def", LastModified: modified.Add(time.Minute)}, want: true},
+		{name: "modified after copy", dst: api.StorageBucketObject{Size: 10, ETag: "def", LastModified: modified.Add(-time.Minute)}, want: false},
+		{name: "different size", dst: api.StorageBucketObject{Size: 11, ETag: "abc-2", LastModified: modified.Add(time.Minute)}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replicatorObjectUnchanged(src, tt.dst))
+		})
+	}
+}
+
+func TestReplicatorTransferRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		bandwidth   int64
+		concurrency int
+		transfers   int
+		want        int64
+	}{
+		{name: "no limit", bandwidth: 0, concurrency: 2, transfers: 10, want: 0},
+		{name: "no transfers", bandwidth: 1000, concurrency: 2, transfers: 0, want: 1000},
+		{name: "shared by all transfers", bandwidth: 1000, concurrency: 0, transfers: 4, want: 250},
+		{name: "shared by concurrent transfers", bandwidth: 1000, concurrency: 2, transfers: 10, want: 500},
+		{name: "fewer transfers than concurrency", bandwidth: 1000, concurrency: 8, transfers: 2, want: 500},
+		{name: "minimum rate", bandwidth: 1, concurrency: 0, transfers: 4, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replicatorTransferRate(tt.bandwidth, tt.concurrency, tt.transfers))
+		})
+	}
+}
+
+func TestReplicatorValidateLabel(t *testing.T) {
+	assert.NoError(t, replicatorValidateLabel("user.tier=gold"))
+	assert.NoError(t, replicatorValidateLabel("user.empty="))
+	assert.Error(t, replicatorValidateLabel("user.tier"))
+	assert.Error(t, replicatorValidateLabel("limits.cpu=2"))
+	assert.Error(t, replicatorValidateLabel("user.=x"))
+}
@@ -23,6 +23,11 @@ var apiRoot = []APIEndpoint{
 	oidcLoginCmd,
 	oidcLogoutCmd,
 	rootCmd,
+	scimGroupCmd,
+	scimGroupsCmd,
+	scimServiceProviderConfigCmd,
+	scimUserCmd,
+	scimUsersCmd,
 	uiCmd,
 	uiRedirectCmd,
 }
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/audit"
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var auditCmd = APIEndpoint{
+	Path:        "audit",
+	MetricsType: entity.TypeServer,
+
+	Get: APIEndpointAction{Handler: auditGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewEvents)},
+}
+
+// recordAPICall records a mutating API call in the audit log after it has been handled.
+// Requests that were forwarded by, or sent from, other cluster members are not recorded, as the API call is recorded
+// by the cluster member that received it. Failed API calls of untrusted callers are rate limited, so that they cannot
+// be used to rotate the records of trusted callers out of the audit log.
+func (d *Daemon) recordAPICall(r *http.Request, w *audit.ResponseWriter, body *audit.BodyDigest) {
+	if d.auditLog == nil {
+		return
+	}
+
+	record := api.AuditRecord{
+		Timestamp:     time.Now(),
+		SourceAddress: r.RemoteAddr,
+		Method:        r.Method,
+		URL:           auditRequestURI(r.URL),
+		Entity:        auditEntityURL(r.URL),
+		BodySHA256:    body.Sum(),
+		StatusCode:    w.StatusCode,
+	}
+
+	// The requestor is not set if authentication failed.
+	trusted := false
+	requestor, err := request.GetRequestor(r.Context())
+	if err == nil {
+		trusted = requestor.IsTrusted()
+		if requestor.IsForwarded() || requestor.Protocol == request.ProtocolCluster {
+			return
+		}
+
+		record.Username = requestor.Username
+		record.Protocol = requestor.Protocol
+		record.SourceAddress = requestor.OriginAddress
+	}
+
+	if w.StatusCode == http.StatusAccepted {
+		record.Operation = w.Header().Get("Location")
+	}
+
+	if !trusted && w.StatusCode >= http.StatusBadRequest {
+		_, err = d.auditLog.AppendUntrustedFailure(record)
+	} else {
+		_, err = d.auditLog.Append(record)
+	}
+
+	if err != nil {
+		logger.Error("Failed recording API call in audit log", logger.Ctx{"method": r.Method, "url": record.URL, "err": err})
+	}
+}
+
+// auditSensitiveQueryParams lists the query parameters carrying credentials (e.g. operation secrets or join tokens),
+// which are removed from the URLs recorded in the audit log.
+var auditSensitiveQueryParams = []string{"password", "secret", "token"}
+
+// auditRequestURI returns the request URI of an API call without its sensitive query parameters.
+func auditRequestURI(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+
+	params := strings.Split(u.RawQuery, "&")
+	params = slices.DeleteFunc(params, func(param string) bool {
+		key, _, _ := strings.Cut(param, "=")
+		key, err := url.QueryUnescape(key)
+		return err != nil || slices.Contains(auditSensitiveQueryParams, strings.ToLower(key))
+	})
+
+	stripped := *u
+	stripped.RawQuery = strings.Join(params, "&")
+	stripped.ForceQuery = false
+	return stripped.RequestURI()
+}
+
+// auditEntityURL returns the URL of the entity targeted by an API call to the given URL, or an empty string if the API
+// call does not target an entity. API calls to a sub-path of an entity (e.g. `/1.0/instances/c1/state`) target the
+// entity.
+func auditEntityURL(u *url.URL) string {
+	apiRoot := "/" + version.APIVersion
+	path := u.EscapedPath()
+	for strings.HasPrefix(path, apiRoot) {
+		// API calls to sub-paths of /1.0 that are not entities (e.g. POST /1.0/instances) do not target the server.
+		if path == apiRoot && u.Path != apiRoot {
+			return ""
+		}
+
+		entityType, projectName, _, pathArguments, err := entity.ParseURL(url.URL{Path: path, RawPath: path, RawQuery: u.RawQuery})
+		if err == nil {
+			entityURL, err := entityType.URL(projectName, "", pathArguments...)
+			if err != nil {
+				return ""
+			}
+
+			return entityURL.String()
+		}
+
+		path = path[:strings.LastIndex(path, "/")]
+	}
+
+	return ""
+}
+
+// swagger:operation GET /1.0/audit server audit_get
+//
+//	Get the audit log
+//
+//	Returns the records of the audit log of the cluster member, optionally filtered by identity, entity, or time.
+//	Each mutating API call is recorded by the cluster member that received it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: query
+//	    name: identity
+//	    description: Username of the caller
+//	    type: string
+//	    example: jane.doe@example.com
+//	  - in: query
+//	    name: entity
+//	    description: URL of the entity
+//	    type: string
+//	    example: /1.0/instances/c1?project=default
+//	  - in: query
+//	    name: since
+//	    description: Only return records at or after this time (RFC3339)
+//	    type: string
+//	    example: 2026-10-18T00:00:00Z
+//	  - in: query
+//	    name: until
+//	    description: Only return records at or before this time (RFC3339)
+//	    type: string
+//	    example: 2026-10-19T00:00:00Z
+//	responses:
+//	  "200":
+//	    description: Audit records
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of audit records
+//	          items:
+//	            $ref: "#/definitions/AuditRecord"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func auditGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	filter := audit.Filter{
+		Username: request.QueryParam(r, "identity"),
+	}
+
+	// Normalise the entity URL, so that the project query parameter may be omitted for the default project.
+	entityReference := request.QueryParam(r, "entity")
+	if entityReference != "" {
+		entityURL, err := url.Parse(entityReference)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Failed parsing entity URL %q: %w", entityReference, err))
+		}
+
+		filter.Entity = auditEntityURL(entityURL)
+		if filter.Entity == "" {
+			return response.BadRequest(fmt.Errorf("URL %q is not an entity URL", entityReference))
+		}
+	}
+
+	for name, value := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		param := request.QueryParam(r, name)
+		if param == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid %q query parameter: %w", name, err))
+		}
+
+		*value = t
+	}
+
+	records, err := audit.Query(filepath.Join(s.OS.VarDir, "audit"), filter)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, records)
+}
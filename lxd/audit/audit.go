@@ -0,0 +1,692 @@
+// Package audit implements the tamper-evident audit log of mutating API calls.
+//
+// The audit log is a sequence of JSON encoded [api.AuditRecord] entries, one per line. Each record contains the hash
+// of the previous record, so removing, reordering or modifying a record breaks the hash chain. The hashes are keyed
+// with a secret that is not stored with the log, so that the chain cannot be recomputed by someone who can only write
+// to the log. This is detected by [Verify]. When the log file exceeds its maximum size it is rotated, and the hash
+// chain continues in the new file. When rotated files are deleted, the range of removed records is recorded in the
+// chain.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// FileName is the name of the current audit log file. Rotated files have a numeric suffix, where `.1` is the most
+// recently rotated file.
+const FileName = "audit.log"
+
+// maxRecordSize is the maximum size of an encoded record that is read from the audit log.
+const maxRecordSize = 1024 * 1024
+
+// untrustedFailuresLimit is the maximum number of failed API calls of unauthenticated callers that are recorded per
+// untrustedFailuresInterval. This prevents unauthenticated callers from rotating the history out of the audit log.
+const untrustedFailuresLimit = 60
+
+// untrustedFailuresInterval is the interval over which untrustedFailuresLimit applies.
+const untrustedFailuresInterval = time.Minute
+
+// Log is an append-only, hash-chained audit log in a directory.
+type Log struct {
+	mu sync.Mutex
+
+	dir      string
+	key      []byte
+	file     *os.File
+	closed   bool
+	size     int64
+	maxSize  int64
+	maxFiles int
+
+	sequence int64
+	lastHash string
+
+	untrustedWindow     time.Time
+	untrustedFailures   int
+	untrustedSuppressed int
+}
+
+// Open opens the audit log in the given directory, creating it if needed. The records are hashed with the given key.
+// The log is rotated when the current file would exceed maxSize bytes, and at most maxFiles rotated files are kept.
+func Open(dir string, key []byte, maxSize int64, maxFiles int) (*Log, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating audit log directory: %w", err)
+	}
+
+	l := &Log{
+		dir:      dir,
+		key:      key,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+
+	// Continue the hash chain from the last record in the log.
+	files, err := Files(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(files) - 1; i >= 0; i-- {
+		last, err := lastRecord(files[i])
+		if err != nil {
+			// Don't prevent LXD from starting if the log was tampered with. The broken chain is reported by Verify.
+			logger.Warn("Failed reading last audit log record, starting a new hash chain", logger.Ctx{"file": files[i], "err": err})
+			break
+		}
+
+		if last != nil {
+			l.sequence = last.Sequence
+			l.lastHash = last.Hash
+			break
+		}
+	}
+
+	err = l.openFile()
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// openFile opens the current file of the audit log for appending.
+func (l *Log) openFile() error {
+	file, err := os.OpenFile(filepath.Join(l.dir, FileName), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed opening audit log: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("Failed getting audit log size: %w", err)
+	}
+
+	l.file = file
+	l.size = info.Size()
+
+	return nil
+}
+
+// SetLimits sets the maximum size of the current file and the maximum number of rotated files to keep.
+func (l *Log) SetLimits(maxSize int64, maxFiles int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxSize = maxSize
+	l.maxFiles = maxFiles
+}
+
+// Append sets the sequence number and hashes of the given record and writes it to the audit log.
+func (l *Log) Append(record api.AuditRecord) (*api.AuditRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.append(record)
+}
+
+// AppendUntrustedFailure appends the record of a failed API call of an unauthenticated caller. At most
+// untrustedFailuresLimit of these records are appended per untrustedFailuresInterval, and the others are dropped. It
+// returns nil if the record was dropped.
+func (l *Log) AppendUntrustedFailure(record api.AuditRecord) (*api.AuditRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.untrustedWindow) >= untrustedFailuresInterval {
+		if l.untrustedSuppressed > 0 {
+			logger.Warn("Dropped failed API calls of unauthenticated callers from the audit log", logger.Ctx{"count": l.untrustedSuppressed, "since": l.untrustedWindow})
+		}
+
+		l.untrustedWindow = now
+		l.untrustedFailures = 0
+		l.untrustedSuppressed = 0
+	}
+
+	if l.untrustedFailures >= untrustedFailuresLimit {
+		l.untrustedSuppressed++
+		return nil, nil
+	}
+
+	l.untrustedFailures++
+
+	return l.append(record)
+}
+
+// append writes the record to the audit log, rotating the log first if needed. The lock must be held.
+func (l *Log) append(record api.AuditRecord) (*api.AuditRecord, error) {
+	if l.closed {
+		return nil, errors.New("Audit log is closed")
+	}
+
+	// Reopen the current file if a previous rotation failed.
+	if l.file == nil {
+		err := l.openFile()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	record.Timestamp = record.Timestamp.UTC().Round(0)
+
+	line, err := l.encode(&record)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		removedThrough, err := l.rotate()
+		if err != nil {
+			return nil, err
+		}
+
+		// Record the range of removed records in the hash chain, so that it can be told apart from records that
+		// were removed otherwise.
+		if removedThrough > 0 {
+			_, err = l.write(api.AuditRecord{Timestamp: record.Timestamp, RemovedThrough: removedThrough})
+			if err != nil {
+				return nil, err
+			}
+
+			line, err = l.encode(&record)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return l.writeLine(record, line)
+}
+
+// encode sets the sequence number and hashes of the record, chaining it to the last record, and returns the encoded
+// line. The lock must be held.
+func (l *Log) encode(record *api.AuditRecord) ([]byte, error) {
+	record.Sequence = l.sequence + 1
+	record.PreviousHash = l.lastHash
+
+	var err error
+	record.Hash, err = Hash(l.key, *record)
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("Failed encoding audit record: %w", err)
+	}
+
+	return append(line, '\n'), nil
+}
+
+// write encodes the record and writes it to the current file. The lock must be held.
+func (l *Log) write(record api.AuditRecord) (*api.AuditRecord, error) {
+	line, err := l.encode(&record)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.writeLine(record, line)
+}
+
+// writeLine writes the encoded record to the current file. The lock must be held.
+func (l *Log) writeLine(record api.AuditRecord, line []byte) (*api.AuditRecord, error) {
+	_, err := l.file.Write(line)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing audit record: %w", err)
+	}
+
+	err = l.file.Sync()
+	if err != nil {
+		return nil, fmt.Errorf("Failed syncing audit log: %w", err)
+	}
+
+	l.size += int64(len(line))
+	l.sequence = record.Sequence
+	l.lastHash = record.Hash
+
+	return &record, nil
+}
+
+// rotate renames the current file to `audit.log.1`, shifting previously rotated files, and deletes rotated files
+// beyond the maximum number of files to keep. Rotated files are made read-only. It returns the sequence number of the
+// last deleted record, or zero if no records were deleted. The current file is reopened if the rotation fails.
+func (l *Log) rotate() (removedThrough int64, err error) {
+	err = l.file.Close()
+	l.file = nil
+	if err != nil {
+		return 0, fmt.Errorf("Failed closing audit log: %w", err)
+	}
+
+	defer func() {
+		if l.file != nil {
+			return
+		}
+
+		reopenErr := l.openFile()
+		if reopenErr != nil {
+			logger.Error("Failed reopening audit log after failed rotation", logger.Ctx{"err": reopenErr})
+		}
+	}()
+
+	files, err := Files(l.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	// Files are ordered from oldest to newest, and the newest is the current file.
+	for i, path := range files {
+		index := len(files) - i
+		if index > l.maxFiles {
+			last, err := lastRecord(path)
+			if err != nil {
+				logger.Warn("Failed reading last record of rotated audit log", logger.Ctx{"file": path, "err": err})
+			} else if last != nil {
+				removedThrough = last.Sequence
+			}
+
+			logger.Warn("Deleting rotated audit log", logger.Ctx{"file": path, "removedThrough": removedThrough, "maxFiles": l.maxFiles})
+			err = os.Remove(path)
+			if err != nil {
+				return 0, fmt.Errorf("Failed removing rotated audit log: %w", err)
+			}
+
+			continue
+		}
+
+		err = os.Rename(path, filepath.Join(l.dir, FileName+"."+strconv.Itoa(index)))
+		if err != nil {
+			return 0, fmt.Errorf("Failed rotating audit log: %w", err)
+		}
+	}
+
+	err = os.Chmod(filepath.Join(l.dir, FileName+".1"), 0400)
+	if err != nil {
+		return 0, fmt.Errorf("Failed making rotated audit log read-only: %w", err)
+	}
+
+	err = l.openFile()
+	if err != nil {
+		return 0, err
+	}
+
+	return removedThrough, nil
+}
+
+// Close closes the audit log.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	if l.file == nil {
+		return nil
+	}
+
+	err := l.file.Close()
+	l.file = nil
+
+	return err
+}
+
+// Hash returns the hex encoded HMAC-SHA256 of the given record, keyed with the given key. The hash field of the
+// record is ignored.
+func Hash(key []byte, record api.AuditRecord) (string, error) {
+	record.Hash = ""
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("Failed encoding audit record: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Files returns the paths of the audit log files in the given directory, ordered from oldest to newest.
+func Files(dir string) ([]string, error) {
+	var rotated []string
+	for i := 1; ; i++ {
+		path := filepath.Join(dir, FileName+"."+strconv.Itoa(i))
+		_, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed checking rotated audit log: %w", err)
+		}
+
+		rotated = append([]string{path}, rotated...)
+	}
+
+	path := filepath.Join(dir, FileName)
+	_, err := os.Stat(path)
+	if err == nil {
+		return append(rotated, path), nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("Failed checking audit log: %w", err)
+	}
+
+	return rotated, nil
+}
+
+// lastRecord returns the last record in the given file, or nil if the file is empty. It returns an error if the last
+// line of the file cannot be decoded.
+func lastRecord(path string) (*api.AuditRecord, error) {
+	var last *api.AuditRecord
+	var lastErr error
+	err := readFile(path, func(line int, record *api.AuditRecord, err error) error {
+		last = record
+		lastErr = err
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if lastErr != nil {
+		return nil, lastErr
+	}
+
+	return last, nil
+}
+
+// readFile calls the given function for each record in the given file, with the one-based line number of the record.
+// If a line cannot be decoded, the function is called with the decoding error instead.
+func readFile(path string, f func(line int, record *api.AuditRecord, err error) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Failed opening audit log: %w", err)
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		var record api.AuditRecord
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&record)
+		if err != nil {
+			err = f(line, nil, fmt.Errorf("Invalid record: %w", err))
+		} else {
+			err = f(line, &record, nil)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return fmt.Errorf("Failed reading audit log %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// VerifyError describes where the hash chain of the audit log is broken.
+type VerifyError struct {
+	// File is the path of the file containing the invalid record.
+	File string
+
+	// Line is the one-based line number of the invalid record.
+	Line int
+
+	// Err describes why the record is invalid.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyResult summarises a verified audit log.
+type VerifyResult struct {
+	// Files is the number of verified files.
+	Files int
+
+	// Records is the number of verified records.
+	Records int
+
+	// FirstSequence is the sequence number of the first verified record. If it is greater than one, earlier records
+	// have been removed by rotation. This is recorded in the hash chain.
+	FirstSequence int64
+
+	// LastSequence is the sequence number of the last verified record.
+	LastSequence int64
+
+	// LastHash is the hash of the last verified record. Comparing it to a previously recorded value detects if
+	// records were removed from the end of the log.
+	LastHash string
+}
+
+// Verify checks the hash chain of the audit log in the given directory using the key the records were hashed with.
+// It returns a [*VerifyError] for the first record that was modified, or that does not follow the previous record. It
+// also returns an error if records were removed from the start of the log without being recorded in the hash chain.
+func Verify(dir string, key []byte) (*VerifyResult, error) {
+	files, err := Files(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removedThrough int64
+	var firstPath string
+	result := &VerifyResult{}
+	for _, path := range files {
+		result.Files++
+		err = readFile(path, func(line int, record *api.AuditRecord, err error) error {
+			if err != nil {
+				return &VerifyError{File: path, Line: line, Err: err}
+			}
+
+			hash, err := Hash(key, *record)
+			if err != nil {
+				return err
+			}
+
+			if hash != record.Hash {
+				return &VerifyError{File: path, Line: line, Err: fmt.Errorf("Record %d was modified (expected hash %q, got %q)", record.Sequence, record.Hash, hash)}
+			}
+
+			// The first record is the anchor of the chain. Records before it may have been removed by rotation.
+			if result.Records == 0 {
+				if record.Sequence == 1 && record.PreviousHash != "" {
+					return &VerifyError{File: path, Line: line, Err: errors.New("The first record references a previous record")}
+				}
+
+				result.FirstSequence = record.Sequence
+				firstPath = path
+			} else {
+				if record.Sequence != result.LastSequence+1 {
+					return &VerifyError{File: path, Line: line, Err: fmt.Errorf("Expected record %d, got record %d", result.LastSequence+1, record.Sequence)}
+				}
+
+				if record.PreviousHash != result.LastHash {
+					return &VerifyError{File: path, Line: line, Err: fmt.Errorf("Record %d does not follow record %d", record.Sequence, result.LastSequence)}
+				}
+			}
+
+			removedThrough = max(removedThrough, record.RemovedThrough)
+			result.Records++
+			result.LastSequence = record.Sequence
+			result.LastHash = record.Hash
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// The latest rotation that deleted files must have deleted exactly the records before the first one.
+	if result.FirstSequence > 1 && removedThrough != result.FirstSequence-1 {
+		return result, &VerifyError{File: firstPath, Line: 1, Err: fmt.Errorf("Records before record %d were removed without being recorded by a rotation", result.FirstSequence)}
+	}
+
+	return result, nil
+}
+
+// Filter selects audit records. Empty fields match any record.
+type Filter struct {
+	// Username matches records of the caller with this username.
+	Username string
+
+	// Entity matches records targeting the entity with this URL.
+	Entity string
+
+	// Since matches records at or after this time.
+	Since time.Time
+
+	// Until matches records at or before this time.
+	Until time.Time
+}
+
+// Matches returns true if the record matches the filter.
+func (f Filter) Matches(record api.AuditRecord) bool {
+	if f.Username != "" && record.Username != f.Username {
+		return false
+	}
+
+	if f.Entity != "" && record.Entity != f.Entity {
+		return false
+	}
+
+	if !f.Since.IsZero() && record.Timestamp.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && record.Timestamp.After(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Query returns the records of the audit log in the given directory that match the filter, ordered from oldest to
+// newest. Records that cannot be decoded are skipped; use [Verify] to check the integrity of the log.
+func Query(dir string, filter Filter) ([]api.AuditRecord, error) {
+	files, err := Files(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []api.AuditRecord{}
+	for _, path := range files {
+		err = readFile(path, func(line int, record *api.AuditRecord, err error) error {
+			if err == nil && filter.Matches(*record) {
+				records = append(records, *record)
+			}
+
+			return nil
+		})
+		if err != nil {
+			// A file may be removed by a concurrent rotation.
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// ResponseWriter is an [http.ResponseWriter] that records the status code of the response.
+type ResponseWriter struct {
+	http.ResponseWriter
+
+	// StatusCode is the status code of the response, or zero if nothing was written yet.
+	StatusCode int
+}
+
+// WriteHeader records the status code and calls the underlying WriteHeader.
+func (w *ResponseWriter) WriteHeader(statusCode int) {
+	if w.StatusCode == 0 {
+		w.StatusCode = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the implicit status code and calls the underlying Write.
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if w.StatusCode == 0 {
+		w.StatusCode = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the underlying writer if supported.
+func (w *ResponseWriter) Flush() {
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for use with [http.ResponseController].
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// BodyDigest is an [io.ReadCloser] that computes the SHA-256 digest of the request body as it is read.
+type BodyDigest struct {
+	io.ReadCloser
+
+	hash hash.Hash
+	read int64
+}
+
+// NewBodyDigest returns a [BodyDigest] reading from the given body.
+func NewBodyDigest(body io.ReadCloser) *BodyDigest {
+	return &BodyDigest{ReadCloser: body, hash: sha256.New()}
+}
+
+// Read reads from the underlying body and adds the data to the digest.
+func (b *BodyDigest) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	b.read += int64(n)
+	return n, err
+}
+
+// Sum returns the hex encoded digest of the data that was read, or an empty string if nothing was read.
+func (b *BodyDigest) Sum() string {
+	if b.read == 0 {
+		return ""
+	}
+
+	return hex.EncodeToString(b.hash.Sum(nil))
+}
@@ -0,0 +1,221 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+var testKey = []byte("test-key")
+
+func appendRecords(t *testing.T, l *Log, usernames ...string) {
+	for _, username := range usernames {
+		_, err := l.Append(api.AuditRecord{
+			Timestamp: time.Now(),
+			Username:  username,
+			Method:    "POST",
+			URL:       "/1.0/instances",
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestLog(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := Open(dir, testKey, 1024*1024, 3)
+	require.NoError(t, err)
+	appendRecords(t, l, "alice", "bob")
+	require.NoError(t, l.Close())
+
+	// The hash chain continues after reopening the log.
+	l, err = Open(dir, testKey, 1024*1024, 3)
+	require.NoError(t, err)
+	record, err := l.Append(api.AuditRecord{Username: "alice", Entity: "/1.0/instances/c1?project=default"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), record.Sequence)
+	require.NoError(t, l.Close())
+
+	result, err := Verify(dir, testKey)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Records)
+	assert.Equal(t, int64(1), result.FirstSequence)
+	assert.Equal(t, record.Hash, result.LastHash)
+
+	records, err := Query(dir, Filter{Username: "alice"})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	records, err = Query(dir, Filter{Entity: "/1.0/instances/c1?project=default"})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, int64(3), records[0].Sequence)
+
+	records, err = Query(dir, Filter{Since: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestLogRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	// Each record exceeds the maximum size, so the log is rotated before each record is written.
+	l, err := Open(dir, testKey, 1, 2)
+	require.NoError(t, err)
+	appendRecords(t, l, "a", "b", "c", "d")
+	require.NoError(t, l.Close())
+
+	files, err := Files(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "audit.log.2"), filepath.Join(dir, "audit.log.1"), filepath.Join(dir, "audit.log")}, files)
+
+	// The oldest record was removed by rotation, which is recorded before the next record. The remaining records are
+	// still verified across files.
+	records, err := Query(dir, Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, int64(1), records[2].RemovedThrough)
+	assert.Equal(t, "d", records[3].Username)
+
+	result, err := Verify(dir, testKey)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Files)
+	assert.Equal(t, 4, result.Records)
+	assert.Equal(t, int64(2), result.FirstSequence)
+	assert.Equal(t, int64(5), result.LastSequence)
+
+	// Deleting a rotated file is detected.
+	require.NoError(t, os.Remove(filepath.Join(dir, "audit.log.2")))
+	_, err = Verify(dir, testKey)
+	verifyErr, ok := errors.AsType[*VerifyError](err)
+	require.True(t, ok, err)
+	assert.Equal(t, filepath.Join(dir, "audit.log.1"), verifyErr.File)
+}
+
+func TestLogRotationFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := Open(dir, testKey, 1, 1)
+	require.NoError(t, err)
+	appendRecords(t, l, "a")
+
+	// A rotated file that cannot be removed makes the rotation fail.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "audit.log.1", "busy"), 0700))
+	_, err = l.Append(api.AuditRecord{Username: "b"})
+	require.Error(t, err)
+
+	// The current file is reopened, so records are appended once the rotation succeeds.
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "audit.log.1")))
+	appendRecords(t, l, "c")
+	require.NoError(t, l.Close())
+
+	records, err := Query(dir, Filter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	assert.Equal(t, "c", records[len(records)-1].Username)
+}
+
+func TestLogUntrustedFailures(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := Open(dir, testKey, 1024*1024, 3)
+	require.NoError(t, err)
+
+	for range untrustedFailuresLimit {
+		record, err := l.AppendUntrustedFailure(api.AuditRecord{StatusCode: 403})
+		require.NoError(t, err)
+		require.NotNil(t, record)
+	}
+
+	// Further failures of untrusted callers are dropped, but other records are still appended.
+	record, err := l.AppendUntrustedFailure(api.AuditRecord{StatusCode: 403})
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	record, err = l.Append(api.AuditRecord{Username: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(untrustedFailuresLimit+1), record.Sequence)
+	require.NoError(t, l.Close())
+}
+
+func TestVerifyTampering(t *testing.T) {
+	newLog := func() string {
+		dir := t.TempDir()
+		l, err := Open(dir, testKey, 1024*1024, 3)
+		require.NoError(t, err)
+		appendRecords(t, l, "alice", "bob", "carol")
+		require.NoError(t, l.Close())
+		return dir
+	}
+
+	editLines := func(dir string, edit func(lines [][]byte) [][]byte) {
+		path := filepath.Join(dir, FileName)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		lines := bytes.Split(bytes.TrimSuffix(content, []byte("\n")), []byte("\n"))
+		lines = edit(lines)
+		require.NoError(t, os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0600))
+	}
+
+	// Modified record.
+	dir := newLog()
+	editLines(dir, func(lines [][]byte) [][]byte {
+		lines[1] = bytes.Replace(lines[1], []byte(`"bob"`), []byte(`"eve"`), 1)
+		return lines
+	})
+
+	_, err := Verify(dir, testKey)
+	verifyErr, ok := errors.AsType[*VerifyError](err)
+	require.True(t, ok, err)
+	assert.Equal(t, 2, verifyErr.Line)
+
+	// Removed record.
+	dir = newLog()
+	editLines(dir, func(lines [][]byte) [][]byte {
+		return append(lines[:1], lines[2:]...)
+	})
+
+	_, err = Verify(dir, testKey)
+	verifyErr, ok = errors.AsType[*VerifyError](err)
+	require.True(t, ok, err)
+	assert.Equal(t, 2, verifyErr.Line)
+
+	// Reordered records.
+	dir = newLog()
+	editLines(dir, func(lines [][]byte) [][]byte {
+		lines[1], lines[2] = lines[2], lines[1]
+		return lines
+	})
+
+	_, err = Verify(dir, testKey)
+	verifyErr, ok = errors.AsType[*VerifyError](err)
+	require.True(t, ok, err)
+	assert.Equal(t, 2, verifyErr.Line)
+
+	// Records hashed with another key.
+	dir = newLog()
+	_, err = Verify(dir, []byte("other-key"))
+	verifyErr, ok = errors.AsType[*VerifyError](err)
+	require.True(t, ok, err)
+	assert.Equal(t, 1, verifyErr.Line)
+
+	// Invalid record.
+	dir = newLog()
+	editLines(dir, func(lines [][]byte) [][]byte {
+		return append(lines, []byte("not json"))
+	})
+
+	_, err = Verify(dir, testKey)
+	verifyErr, ok = errors.AsType[*VerifyError](err)
+	require.True(t, ok, err)
+	assert.Equal(t, 4, verifyErr.Line)
+}
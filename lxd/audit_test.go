@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEntityURL(t *testing.T) {
+	tests := map[string]string{
+		"/1.0":                                      "/1.0",
+		"/1.0/instances":                            "",
+		"/1.0/instances/c1":                         "/1.0/instances/c1?project=default",
+		"/1.0/instances/c1/state?project=foo":       "/1.0/instances/c1?project=foo",
+		"/1.0/instances/c1/snapshots/snap0":         "/1.0/instances/c1/snapshots/snap0?project=default",
+		"/1.0/projects/foo?target=member1":          "/1.0/projects/foo",
+		"/1.0/storage-pools/default/volumes/custom": "/1.0/storage-pools/default",
+		"/scim/v2/Users/1":                          "",
+	}
+
+	for rawURL, expected := range tests {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, expected, auditEntityURL(u), rawURL)
+	}
+}
+
+func TestAuditRequestURI(t *testing.T) {
+	tests := map[string]string{
+		"/1.0/instances": "/1.0/instances",
+		"/1.0/instances?project=foo&target=member1":            "/1.0/instances?project=foo&target=member1",
+		"/1.0/operations/1234/websocket?secret=abc":            "/1.0/operations/1234/websocket",
+		"/1.0/operations/1234/websocket?project=foo&secret=ab": "/1.0/operations/1234/websocket?project=foo",
+		"/1.0/images/abc/export?Token=abc&project=foo":         "/1.0/images/abc/export?project=foo",
+		"/1.0/instances?sec%72et=abc&%zz=1":                    "/1.0/instances",
+	}
+
+	for rawURL, expected := range tests {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, expected, auditRequestURI(u), rawURL)
+	}
+}
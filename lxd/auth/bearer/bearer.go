@@ -28,6 +28,12 @@ func IsDevLXDRequest(r *http.Request, clusterUUID string) (isRequest bool, token
 	return isAuthorizationHeaderRequestFromAudience(r, clusterUUID, encryption.DevLXDAudience(clusterUUID))
 }
 
+// IsSCIMRequest returns true if the caller sent a bearer token in the Authorization header that is a JWT and appears to
+// have this LXD cluster as the issuer and the SCIM API as the audience. If true, it returns the raw token, and the subject.
+func IsSCIMRequest(r *http.Request, clusterUUID string) (isRequest bool, token string, subject string) {
+	return isAuthorizationHeaderRequestFromAudience(r, clusterUUID, encryption.SCIMAudience(clusterUUID))
+}
+
 // IsAPIRequest returns true if the caller sent a JWT that has this LXD cluster as the issuer.
 // If true, it returns the location that the token was found, the raw token, and the subject.
 // The JWT is not verified. The caller must call [Authenticate] to verify the returned raw token.
@@ -271,6 +277,26 @@ func VerifySessionToken(token string, clusterSecret []byte, sessionID uuid.UUID)
 	return err
 }
 
+// VerifyCertificateRenewalNonce verifies that the given certificate renewal nonce was issued by this cluster for the
+// certificate with the given fingerprint, that it was signed by a key derived from the given cluster secret, and that it
+// has not expired.
+func VerifyCertificateRenewalNonce(nonce string, clusterSecret []byte, clusterUUID string, fingerprint string) error {
+	subject, _, err := isLXDToken(nonce, clusterUUID, encryption.CertificateRenewalAudience(clusterUUID))
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "Invalid nonce: %w", err)
+	}
+
+	if subject != fingerprint {
+		return api.NewStatusError(http.StatusForbidden, "Nonce was not issued for the current certificate")
+	}
+
+	_, err = verifyToken(nonce, func() ([]byte, error) {
+		return encryption.TokenSigningKey(clusterSecret, []byte(fingerprint))
+	})
+
+	return err
+}
+
 // verifyToken verifies that the given token was signed by the key returned by the given key func.
 // For a valid token, an expiration time is returned.
 func verifyToken(token string, keyFunc func() ([]byte, error)) (expiresAt *time.Time, err error) {
@@ -7,6 +7,7 @@ import (
 	"errors"
 
 	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/request/security"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
@@ -48,6 +49,12 @@ func (c *commonAuthorizer) emitAuthzFail(ctx context.Context, entityURL *api.URL
 		return
 	}
 
+	// Denials evaluated to explain an authorization decision are not authorization failures.
+	isAuthorizationCheck, _ := ctx.Value(request.CtxAuthorizationCheck).(bool)
+	if isAuthorizationCheck {
+		return
+	}
+
 	if entitlement == auth.EntitlementCanEdit && (entityType == entity.TypeServer || entityType == entity.TypeStoragePool) {
 		return
 	}
@@ -190,6 +190,73 @@ func (e *embeddedOpenFGA) GetViewableProjects(ctx context.Context, permissions [
 	return projects, nil
 }
 
+// GetGrantingPermissions accepts a list of permissions and returns those that, on their own, grant the given entitlement
+// on the entity found at the given URL to a member of a group with the permission.
+func (e *embeddedOpenFGA) GetGrantingPermissions(ctx context.Context, entityURL *api.URL, entitlement auth.Entitlement, permissions []api.Permission) ([]api.Permission, error) {
+	entityType, projectName, location, pathArguments, err := entity.ParseURL(entityURL.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing entity URL: %w", err)
+	}
+
+	// Construct the URL in a standardised form (adding the project parameter if it was not present).
+	entityURL, err = entityType.URL(projectName, location, pathArguments...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed standardizing entity URL: %w", err)
+	}
+
+	// As with GetViewableProjects, the request cache is not useful when evaluating contextual tuples.
+	ctx = context.WithValue(ctx, request.CtxOpenFGARequestCache, nil)
+
+	// Check each permission in turn using a dummy identity as the only member of a dummy group with the permission.
+	// Neither the group nor the identity have to exist, all tuples are passed contextually. The group name contains a
+	// forward slash so that the permissions of an existing group are never considered.
+	userObject := string(entity.TypeIdentity) + ":" + entity.IdentityURL("foo", "bar").String()
+	groupObject := string(entity.TypeAuthGroup) + ":" + entity.AuthGroupURL("dummy/granting").String()
+
+	var granting []api.Permission
+	for _, permission := range permissions {
+		req := &openfgav1.CheckRequest{
+			StoreId: dummyDatastoreULID,
+			TupleKey: &openfgav1.CheckRequestTupleKey{
+				User:     userObject,
+				Relation: string(entitlement),
+				Object:   fmt.Sprintf("%s:%s", entityType, entityURL.String()),
+			},
+			ContextualTuples: &openfgav1.ContextualTupleKeys{
+				TupleKeys: []*openfgav1.TupleKey{
+					{
+						// The dummy identity is a member of the dummy group.
+						User:     userObject,
+						Relation: "member",
+						Object:   groupObject,
+					},
+					{
+						// Members of the dummy group have permission, not the group itself.
+						User:     groupObject + "#member",
+						Relation: permission.Entitlement,
+						Object:   permission.EntityType + ":" + permission.EntityReference,
+					},
+				},
+			},
+		}
+
+		resp, err := e.server.Check(ctx, req)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return nil, api.NewGenericStatusError(http.StatusNotFound)
+			}
+
+			return nil, fmt.Errorf("Failed checking OpenFGA relation: %w", err)
+		}
+
+		if resp.GetAllowed() {
+			granting = append(granting, permission)
+		}
+	}
+
+	return granting, nil
+}
+
 // CheckPermission checks if the current requestor has the given entitlement on the given entity URL.
 func (e *embeddedOpenFGA) CheckPermission(ctx context.Context, entityURL *api.URL, entitlement auth.Entitlement) error {
 	return e.checkPermission(ctx, entityURL, entitlement, true)
@@ -320,9 +387,12 @@ func (e *embeddedOpenFGA) checkPermission(ctx context.Context, entityURL *api.UR
 		})
 	}
 
+	// If the caller authenticated with a narrowed bearer token, the relation must also be allowed by the token permissions.
+	scopeTuples := tokenScopeTuples(requestor, userObject)
+
 	// Perform the check.
 	l.Debug("Checking OpenFGA relation")
-	resp, err := e.server.Check(ctx, req)
+	allowed, err := e.check(ctx, req, scopeTuples)
 	if err != nil {
 		// If we have a not found error from the underlying OpenFGADatastore we should mask it to make requests consistent.
 		// (all not found errors returned before an access control decision is made are masked to prevent discovery).
@@ -351,7 +421,7 @@ func (e *embeddedOpenFGA) checkPermission(ctx context.Context, entityURL *api.UR
 	}
 
 	// If not allowed, decide if the user can view the resource.
-	if !resp.GetAllowed() {
+	if !allowed {
 		err := auth.ValidateEntitlement(entityType, auth.EntitlementCanView)
 		doCheckCanView := err == nil
 
@@ -363,7 +433,7 @@ func (e *embeddedOpenFGA) checkPermission(ctx context.Context, entityURL *api.UR
 			req.TupleKey.Relation = string(auth.EntitlementCanView)
 
 			l.Debug("Checking OpenFGA relation")
-			resp, err := e.server.Check(ctx, req)
+			allowed, err := e.check(ctx, req, scopeTuples)
 			if err != nil {
 				// If we have a not found error from the underlying OpenFGADatastore we should mask it to make requests consistent.
 				// (all not found errors returned before an access control decision is made are masked to prevent discovery).
@@ -383,7 +453,7 @@ func (e *embeddedOpenFGA) checkPermission(ctx context.Context, entityURL *api.UR
 			}
 
 			// If we can't view the resource, return a generic not found error.
-			if !resp.GetAllowed() {
+			if !allowed {
 				responseCode = http.StatusNotFound
 			}
 		}
@@ -523,6 +593,23 @@ func (e *embeddedOpenFGA) getPermissionChecker(ctx context.Context, entitlement
 
 	objects := resp.GetObjects()
 
+	// If the caller authenticated with a narrowed bearer token, only keep the objects that the token permissions allow.
+	scopeTuples := tokenScopeTuples(requestor, userObject)
+	if scopeTuples != nil {
+		req.ContextualTuples = &openfgav1.ContextualTupleKeys{TupleKeys: scopeTuples}
+
+		l.Debug("Listing related objects for narrowed token")
+		resp, err := e.server.ListObjects(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("Failed listing OpenFGA objects of type %q with entitlement %q for narrowed token of user %q: %w", entityType.String(), entitlement, requestor.Username, err)
+		}
+
+		scopeObjects := resp.GetObjects()
+		objects = slices.DeleteFunc(objects, func(object string) bool {
+			return !slices.Contains(scopeObjects, object)
+		})
+	}
+
 	// Return a permission checker that constructs an OpenFGA object from the given URL and returns true if the object is
 	// found in the list of objects in the response.
 	return func(entityURL *api.URL) bool {
@@ -558,6 +645,75 @@ func (e *embeddedOpenFGA) getPermissionChecker(ctx context.Context, entitlement
 	}, nil
 }
 
+// check performs the given OpenFGA check request. If scopeTuples is not nil, the relation must additionally be allowed
+// when the contextual tuples of the request are replaced by scopeTuples.
+func (e *embeddedOpenFGA) check(ctx context.Context, req *openfgav1.CheckRequest, scopeTuples []*openfgav1.TupleKey) (bool, error) {
+	resp, err := e.server.Check(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	if !resp.GetAllowed() || scopeTuples == nil {
+		return resp.GetAllowed(), nil
+	}
+
+	resp, err = e.server.Check(ctx, &openfgav1.CheckRequest{
+		StoreId:          req.GetStoreId(),
+		TupleKey:         req.GetTupleKey(),
+		ContextualTuples: &openfgav1.ContextualTupleKeys{TupleKeys: scopeTuples},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return resp.GetAllowed(), nil
+}
+
+// tokenScopeGroupName is the name of the dummy group used to evaluate the permissions that a bearer token is narrowed to.
+// It cannot collide with an existing group because group names cannot contain a forward slash.
+const tokenScopeGroupName = "token/scope"
+
+// tokenScopeTuples returns contextual tuples relating the given user object to the permissions that the bearer token of
+// the requestor is narrowed to, via membership of a dummy group. These are used in place of the group memberships of
+// the requestor. It returns nil if the requestor did not authenticate with a narrowed bearer token.
+func tokenScopeTuples(requestor *request.Requestor, userObject string) []*openfgav1.TupleKey {
+	permissions := requestor.CallerTokenPermissions()
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	groupObject := fmt.Sprintf("%s:%s", entity.TypeAuthGroup, entity.AuthGroupURL(tokenScopeGroupName).String())
+	tuples := []*openfgav1.TupleKey{
+		{
+			// Users can always view (but not edit) themselves.
+			User:     userObject,
+			Relation: string(auth.EntitlementCanView),
+			Object:   userObject,
+		},
+		{
+			// Users can always delete (but not edit) themselves.
+			User:     userObject,
+			Relation: string(auth.EntitlementCanDelete),
+			Object:   userObject,
+		},
+		{
+			User:     userObject,
+			Relation: "member",
+			Object:   groupObject,
+		},
+	}
+
+	for _, permission := range permissions {
+		tuples = append(tuples, &openfgav1.TupleKey{
+			User:     groupObject + "#member", // Members of the dummy group have permission, not the group itself.
+			Relation: permission.Entitlement,
+			Object:   permission.EntityType + ":" + permission.EntityReference,
+		})
+	}
+
+	return tuples
+}
+
 // openfgaLogger implements OpenFGAs logger.Logger interface but delegates to our logger.
 type openfgaLogger struct {
 	l logger.Logger
@@ -0,0 +1,67 @@
+package drivers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/identity"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+func bearerRequestor(t *testing.T, permissions []api.Permission) *request.Requestor {
+	r := &http.Request{
+		RemoteAddr: "127.0.0.1:53423",
+	}
+
+	err := request.SetRequestor(r, func(ctx context.Context, authenticationMethod string, identifier string) (*request.RequestorHookResult, error) {
+		return &request.RequestorHookResult{
+			IdentityID:       1,
+			IdentityType:     identity.TokenBearerClient{},
+			AuthGroups:       []string{"ci"},
+			TokenPermissions: permissions,
+		}, nil
+	}, request.RequestorArgs{Trusted: true, Protocol: api.AuthenticationMethodBearer, Username: "ci"})
+	require.NoError(t, err)
+
+	requestor, err := request.GetRequestor(r.Context())
+	require.NoError(t, err)
+
+	return requestor
+}
+
+func TestTokenScopeTuples(t *testing.T) {
+	userObject := "identity:" + entity.IdentityURL(api.AuthenticationMethodBearer, "ci").String()
+
+	// Tokens that are not narrowed are evaluated using the groups of the identity only.
+	require.Nil(t, tokenScopeTuples(bearerRequestor(t, nil), userObject))
+
+	instanceURL := entity.InstanceURL("default", "c1").String()
+	tuples := tokenScopeTuples(bearerRequestor(t, []api.Permission{{
+		EntityType:      string(entity.TypeInstance),
+		EntityReference: instanceURL,
+		Entitlement:     "can_exec",
+	}}), userObject)
+
+	groupObject := "group:/1.0/auth/groups/token%2Fscope"
+	var keys []string
+	for _, tuple := range tuples {
+		keys = append(keys, tuple.GetUser()+" "+tuple.GetRelation()+" "+tuple.GetObject())
+	}
+
+	require.Equal(t, []string{
+		userObject + " can_view " + userObject,
+		userObject + " can_delete " + userObject,
+		userObject + " member " + groupObject,
+		groupObject + "#member can_exec instance:" + instanceURL,
+	}, keys)
+
+	// The groups of the identity are not used when evaluating the token permissions.
+	for _, tuple := range tuples {
+		require.NotContains(t, tuple.GetObject(), "/1.0/auth/groups/ci")
+	}
+}
@@ -13,7 +13,9 @@ import (
 )
 
 const (
-	audienceDevLXD = "devlxd"
+	audienceDevLXD             = "devlxd"
+	audienceSCIM               = "scim"
+	audienceCertificateRenewal = "certificate-renewal"
 )
 
 // DevLXDAudience returns the aud claim for all DevLXD tokens issued by this cluster.
@@ -21,6 +23,16 @@ func DevLXDAudience(clusterUUID string) string {
 	return strings.Join([]string{audienceDevLXD, clusterUUID}, ":")
 }
 
+// SCIMAudience returns the aud claim for all SCIM tokens issued by this cluster.
+func SCIMAudience(clusterUUID string) string {
+	return strings.Join([]string{audienceSCIM, clusterUUID}, ":")
+}
+
+// CertificateRenewalAudience returns the aud claim for all certificate renewal nonces issued by this cluster.
+func CertificateRenewalAudience(clusterUUID string) string {
+	return strings.Join([]string{audienceCertificateRenewal, clusterUUID}, ":")
+}
+
 // Issuer returns the iss claim for all tokens issued by this LXD cluster.
 func Issuer(clusterUUID string) string {
 	return strings.Join([]string{"lxd", clusterUUID}, ":")
@@ -43,6 +55,17 @@ func GetDevLXDBearerToken(secret []byte, identityIdentifier string, clusterUUID
 	return getToken(secret, nil, identityIdentifier, clusterUUID, DevLXDAudience, expiresAt, "")
 }
 
+// GetSCIMBearerToken generates and signs a token for use with the SCIM API. For claims it has:
+// - Subject (sub): Identity identifier (UUID)
+// - Issuer (iss): "lxd:{cluster_uuid}"
+// - Audience (aud): "scim:{cluster_uuid}"
+// - Not before (nbf): time now (UTC)
+// - Issued at (iat): time now (UTC)
+// - Expiry (exp): The given time (UTC).
+func GetSCIMBearerToken(secret []byte, identityIdentifier string, clusterUUID string, expiresAt time.Time) (string, error) {
+	return getToken(secret, nil, identityIdentifier, clusterUUID, SCIMAudience, expiresAt, "")
+}
+
 // GetClientBearerToken generates and signs a token for use with the main LXD API. For claims it has:
 // - Subject (sub): Identity identifier (UUID)
 // - Issuer (iss): "lxd:{cluster_uuid}"
@@ -70,6 +93,19 @@ func GetOIDCSessionToken(secret []byte, sessionID uuid.UUID, clusterUUID string,
 	return getToken(secret, sessionID[:], sessionID.String(), clusterUUID, LXDAudience, expiresAt, "")
 }
 
+// GetCertificateRenewalNonce generates and signs a nonce that a TLS client must sign with the key of its new certificate
+// when renewing its certificate. The nonce is signed by a key derived from the given secret using the fingerprint of the
+// current certificate as a salt. For claims it has:
+// - Subject (sub): Fingerprint of the current certificate
+// - Issuer (iss): "lxd:{cluster_uuid}"
+// - Audience (aud): "certificate-renewal:{cluster_uuid}"
+// - Not before (nbf): time now (UTC)
+// - Issued at (iat): time now (UTC)
+// - Expiry (exp): The given time (UTC).
+func GetCertificateRenewalNonce(secret []byte, fingerprint string, clusterUUID string, expiresAt time.Time) (string, error) {
+	return getToken(secret, []byte(fingerprint), fingerprint, clusterUUID, CertificateRenewalAudience, expiresAt, "")
+}
+
 // getToken generates and signs a token for use with the LXD. If a salt is provided, a signing key will be generated
 // using [TokenSigningKey] with the secret, otherwise the given secret will be used directly.
 // For claims it has:
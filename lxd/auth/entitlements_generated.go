@@ -127,6 +127,9 @@ const (
 	// EntitlementCanDeleteClusterLinks is the "can_delete_cluster_links" entitlement. It applies to the following entities: entity.TypeServer.
 	EntitlementCanDeleteClusterLinks Entitlement = "can_delete_cluster_links"
 
+	// EntitlementCanQuery is the "can_query" entitlement. It applies to the following entities: entity.TypeClusterLink.
+	EntitlementCanQuery Entitlement = "can_query"
+
 	// EntitlementOperator is the "operator" entitlement. It applies to the following entities: entity.TypeInstance, entity.TypeProject.
 	EntitlementOperator Entitlement = "operator"
 
@@ -339,6 +342,8 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		EntitlementCanEdit,
 		// Grants permission to delete the cluster link.
 		EntitlementCanDelete,
+		// Grants permission to query the linked cluster with the identity of the cluster link. The caller must also be allowed to run the query on the local cluster.
+		EntitlementCanQuery,
 	},
 	entity.TypeAuthGroup: {
 		// Grants permission to view the group. Identities can always view groups that they are a member of.
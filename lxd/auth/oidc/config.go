@@ -0,0 +1,262 @@
+package oidc
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"go.yaml.in/yaml/v2"
+)
+
+// DefaultScopes are the scopes requested from an identity provider if none are configured.
+var DefaultScopes = []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeOfflineAccess, oidc.ScopeProfile}
+
+// ValidateScopes checks that the given scopes include the scopes that are required by LXD.
+func ValidateScopes(scopes []string) error {
+	if !slices.Contains(scopes, oidc.ScopeOpenID) || !slices.Contains(scopes, oidc.ScopeEmail) {
+		return fmt.Errorf("Scopes must include the %q and %q OpenID Connect scopes", oidc.ScopeOpenID, oidc.ScopeEmail)
+	}
+
+	return nil
+}
+
+// Issuer is the configuration of a trusted OpenID Connect issuer, in addition to the issuer set in "oidc.issuer".
+//
+// The groups from GroupsClaim are prefixed with GroupPrefix, so that the groups of the issuer cannot be confused with
+// the groups of the issuer set in "oidc.issuer", or with those of other issuers.
+type Issuer struct {
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty"`
+	Audience     string   `yaml:"audience,omitempty"`
+	GroupsClaim  string   `yaml:"groups_claim,omitempty"`
+	GroupPrefix  string   `yaml:"group_prefix,omitempty"`
+}
+
+// ParseIssuers parses and validates a YAML list of [Issuer].
+func ParseIssuers(value string) ([]Issuer, error) {
+	var issuers []Issuer
+	err := yaml.UnmarshalStrict([]byte(value), &issuers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing issuers: %w", err)
+	}
+
+	for i, issuer := range issuers {
+		if issuer.Issuer == "" {
+			return nil, fmt.Errorf("Issuer %d: Issuer URL is required", i)
+		}
+
+		u, err := url.Parse(issuer.Issuer)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("Issuer %d: Invalid issuer URL %q", i, issuer.Issuer)
+		}
+
+		if issuer.ClientID == "" {
+			return nil, fmt.Errorf("Issuer %q: Client ID is required", issuer.Issuer)
+		}
+
+		if len(issuer.Scopes) == 0 {
+			issuers[i].Scopes = DefaultScopes
+		} else {
+			err = ValidateScopes(issuer.Scopes)
+			if err != nil {
+				return nil, fmt.Errorf("Issuer %q: %w", issuer.Issuer, err)
+			}
+		}
+
+		if issuer.GroupsClaim != "" && issuer.GroupPrefix == "" {
+			return nil, fmt.Errorf("Issuer %q: A group prefix is required when a groups claim is set", issuer.Issuer)
+		}
+
+		for _, other := range issuers[:i] {
+			if sameIssuer(other.Issuer, issuer.Issuer) {
+				return nil, fmt.Errorf("Issuer %q is defined more than once", issuer.Issuer)
+			}
+
+			if issuer.GroupPrefix != "" && other.GroupPrefix != "" && (strings.HasPrefix(issuer.GroupPrefix, other.GroupPrefix) || strings.HasPrefix(other.GroupPrefix, issuer.GroupPrefix)) {
+				return nil, fmt.Errorf("Issuer %q: Group prefix %q overlaps with the group prefix of issuer %q", issuer.Issuer, issuer.GroupPrefix, other.Issuer)
+			}
+		}
+	}
+
+	return issuers, nil
+}
+
+// sameIssuer returns true if the given issuer URLs are equal, ignoring a trailing slash.
+func sameIssuer(a string, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+// ClaimMapping is a rule that maps the claims of an identity to identity provider groups.
+//
+// The claim is a path to a claim value, where nested claims are separated by a dot. Each value of the claim is matched
+// against the rule. Array values are matched element-wise, and numbers and booleans are matched by their string
+// representation:
+//   - If Value is set, the rule matches if any value of the claim is equal to it.
+//   - If Regex is set, the rule matches if any value of the claim fully matches it. The groups may refer to
+//     submatches of the regular expression (e.g. "$1").
+//   - Otherwise, the rule matches if the claim is present.
+//
+// If the rule matches, the identity is a member of the given groups. If no groups are given, each value of the claim is
+// used as a group name, prefixed with Prefix.
+type ClaimMapping struct {
+	Issuer string   `yaml:"issuer,omitempty"`
+	Claim  string   `yaml:"claim"`
+	Value  string   `yaml:"value,omitempty"`
+	Regex  string   `yaml:"regex,omitempty"`
+	Groups []string `yaml:"groups,omitempty"`
+	Prefix string   `yaml:"prefix,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// ParseClaimMappings parses and validates a YAML list of [ClaimMapping].
+func ParseClaimMappings(value string) ([]ClaimMapping, error) {
+	var mappings []ClaimMapping
+	err := yaml.UnmarshalStrict([]byte(value), &mappings)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing claim mappings: %w", err)
+	}
+
+	for i, mapping := range mappings {
+		if mapping.Claim == "" {
+			return nil, fmt.Errorf("Claim mapping %d: Claim is required", i)
+		}
+
+		if mapping.Value != "" && mapping.Regex != "" {
+			return nil, fmt.Errorf("Claim mapping %d: Value and regex are mutually exclusive", i)
+		}
+
+		if mapping.Prefix != "" && len(mapping.Groups) > 0 {
+			return nil, fmt.Errorf("Claim mapping %d: Prefix cannot be used with groups", i)
+		}
+
+		if (mapping.Value != "" || mapping.Regex != "") && len(mapping.Groups) == 0 {
+			return nil, fmt.Errorf("Claim mapping %d: Groups are required when matching a value or regex", i)
+		}
+
+		if slices.Contains(mapping.Groups, "") {
+			return nil, fmt.Errorf("Claim mapping %d: Group names cannot be empty", i)
+		}
+
+		if mapping.Regex != "" {
+			// Anchor the regular expression so that it must match the whole claim value.
+			mappings[i].regex, err = regexp.Compile("^(?:" + mapping.Regex + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("Claim mapping %d: Invalid regex: %w", i, err)
+			}
+		}
+	}
+
+	return mappings, nil
+}
+
+// groups returns the groups that the claim mapping grants for the given issuer and claims.
+func (m ClaimMapping) groups(issuer string, claims map[string]any) []string {
+	if m.Issuer != "" && !sameIssuer(m.Issuer, issuer) {
+		return nil
+	}
+
+	values, ok := claimValues(claims, m.Claim)
+	if !ok {
+		return nil
+	}
+
+	var groups []string
+	for _, value := range values {
+		switch {
+		case m.regex != nil:
+			match := m.regex.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+
+			for _, group := range m.Groups {
+				groups = append(groups, string(m.regex.ExpandString(nil, group, value, match)))
+			}
+
+		case m.Value != "":
+			if value == m.Value {
+				return m.Groups
+			}
+
+		case len(m.Groups) > 0:
+			return m.Groups
+
+		case value != "":
+			groups = append(groups, m.Prefix+value)
+		}
+	}
+
+	return groups
+}
+
+// mapClaims returns the identity provider groups granted by the given claim mappings for the given issuer and claims.
+func mapClaims(mappings []ClaimMapping, issuer string, claims map[string]any) []string {
+	var groups []string
+	for _, mapping := range mappings {
+		groups = append(groups, mapping.groups(issuer, claims)...)
+	}
+
+	return groups
+}
+
+// claimValues gets the values of the claim at the given path as strings. Nested claims are separated by a dot. Claim
+// names containing a dot (e.g. namespaced claims such as "https://example.com/groups") are matched before nested claims.
+func claimValues(claims map[string]any, path string) ([]string, bool) {
+	value, ok := claims[path]
+	if !ok {
+		for i := range len(path) {
+			if path[i] != '.' {
+				continue
+			}
+
+			nested, ok := claims[path[:i]].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			values, ok := claimValues(nested, path[i+1:])
+			if ok {
+				return values, true
+			}
+		}
+
+		return nil, false
+	}
+
+	var values []string
+	var appendValue func(value any) bool
+	appendValue = func(value any) bool {
+		switch v := value.(type) {
+		case string:
+			values = append(values, v)
+		case bool:
+			values = append(values, strconv.FormatBool(v))
+		case float64:
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		case []any:
+			for _, element := range v {
+				if !appendValue(element) {
+					return false
+				}
+			}
+
+		default:
+			return false
+		}
+
+		return true
+	}
+
+	if !appendValue(value) {
+		return nil, false
+	}
+
+	return values, true
+}
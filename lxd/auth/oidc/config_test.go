@@ -0,0 +1,160 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIssuers(t *testing.T) {
+	issuers, err := ParseIssuers(`
+- issuer: https://idp.example.com/
+  client_id: lxd
+  audience: https://lxd.example.com
+- issuer: https://login.example.org
+  client_id: lxd
+  scopes: [openid, email]
+  groups_claim: roles
+  group_prefix: example-org-
+`)
+	require.NoError(t, err)
+	require.Len(t, issuers, 2)
+	assert.Equal(t, DefaultScopes, issuers[0].Scopes)
+	assert.Equal(t, []string{"openid", "email"}, issuers[1].Scopes)
+
+	invalid := map[string]string{
+		"not a list":         `issuer: https://idp.example.com`,
+		"unknown field":      `[{issuer: "https://idp.example.com", client_id: lxd, foo: bar}]`,
+		"missing issuer":     `[{client_id: lxd}]`,
+		"invalid issuer":     `[{issuer: "idp.example.com", client_id: lxd}]`,
+		"missing client ID":  `[{issuer: "https://idp.example.com"}]`,
+		"missing scope":      `[{issuer: "https://idp.example.com", client_id: lxd, scopes: [openid]}]`,
+		"duplicate issuer":   `[{issuer: "https://idp.example.com", client_id: a}, {issuer: "https://idp.example.com/", client_id: b}]`,
+		"missing prefix":     `[{issuer: "https://idp.example.com", client_id: lxd, groups_claim: roles}]`,
+		"overlapping prefix": `[{issuer: "https://idp.example.com", client_id: a, group_prefix: idp-}, {issuer: "https://idp.example.org", client_id: b, group_prefix: idp-org-}]`,
+	}
+
+	for name, value := range invalid {
+		_, err := ParseIssuers(value)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseClaimMappings(t *testing.T) {
+	_, err := ParseClaimMappings(`
+- claim: email
+  regex: '.*@(example\.com)'
+  groups: [domain-$1]
+- claim: department
+  prefix: department-
+`)
+	require.NoError(t, err)
+
+	invalid := map[string]string{
+		"missing claim":         `[{groups: [foo]}]`,
+		"value and regex":       `[{claim: email, value: foo, regex: foo, groups: [foo]}]`,
+		"prefix and groups":     `[{claim: email, prefix: foo, groups: [foo]}]`,
+		"value without groups":  `[{claim: email, value: foo}]`,
+		"empty group":           `[{claim: email, groups: [""]}]`,
+		"invalid regex":         `[{claim: email, regex: "(", groups: [foo]}]`,
+		"unknown field":         `[{claim: email, group: foo}]`,
+		"not a list of mapping": `claim: email`,
+	}
+
+	for name, value := range invalid {
+		_, err := ParseClaimMappings(value)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestMapClaims(t *testing.T) {
+	mappings, err := ParseClaimMappings(`
+- claim: email
+  regex: '[^@]+@(example\.com|example\.org)'
+  groups: [domain-$1]
+- claim: email_verified
+  value: "true"
+  groups: [verified]
+- claim: https://example.com/roles
+  prefix: role-
+- claim: org.department
+  prefix: department-
+- claim: org.cost_center
+  value: "42"
+  groups: [finance]
+- claim: email
+  issuer: https://idp.example.org/
+  groups: [acquired]
+- claim: missing
+  groups: [never]
+`)
+	require.NoError(t, err)
+
+	claims := map[string]any{
+		"email":                     "jane@example.com",
+		"email_verified":            true,
+		"https://example.com/roles": []any{"admin", "operator"},
+		"org": map[string]any{
+			"department":  "engineering",
+			"cost_center": float64(42),
+		},
+	}
+
+	expected := []string{"domain-example.com", "verified", "role-admin", "role-operator", "department-engineering", "finance"}
+	assert.Equal(t, expected, mapClaims(mappings, "https://idp.example.com", claims))
+
+	// Rules for a specific issuer only apply to identities from that issuer.
+	assert.Equal(t, append(expected, "acquired"), mapClaims(mappings, "https://idp.example.org", claims))
+
+	// Regular expressions must match the whole claim value.
+	claims = map[string]any{"email": "jane@example.com.evil"}
+	assert.Empty(t, mapClaims(mappings, "https://idp.example.com", claims))
+}
+
+func TestProviderForToken(t *testing.T) {
+	verifier := &Verifier{
+		providers: []*provider{
+			{issuer: "https://idp.example.com"},
+			{issuer: "https://idp.example.org/", audience: "lxd", additional: true},
+		},
+	}
+
+	token := func(claims jwt.MapClaims) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		require.NoError(t, err)
+		return signed
+	}
+
+	// Opaque tokens are verified by the primary issuer.
+	p, err := verifier.providerForToken("opaque")
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com", p.issuer)
+
+	// Tokens of unknown issuers are verified by the primary issuer.
+	p, err = verifier.providerForToken(token(jwt.MapClaims{"iss": "https://idp.example.net", "aud": "lxd"}))
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com", p.issuer)
+
+	// Tokens of additional issuers are verified by that issuer.
+	p, err = verifier.providerForToken(token(jwt.MapClaims{"iss": "https://idp.example.org", "aud": []string{"lxd", "other"}}))
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.example.org/", p.issuer)
+	assert.Equal(t, "https://idp.example.org/", p.sessionIssuer())
+
+	// Tokens of additional issuers must have the configured audience.
+	_, err = verifier.providerForToken(token(jwt.MapClaims{"iss": "https://idp.example.org/", "aud": "other"}))
+	assert.Error(t, err)
+}
+
+func TestGetGroupsFromClaims(t *testing.T) {
+	claims := map[string]any{"roles": []any{"admins", "users"}}
+
+	primary := &provider{issuer: "https://idp.example.com", groupsClaim: "roles"}
+	assert.Equal(t, []string{"admins", "users"}, primary.getGroupsFromClaims(claims))
+
+	// The groups of additional issuers are prefixed, so that they never match the groups of the primary issuer.
+	additional := &provider{issuer: "https://idp.example.org", groupsClaim: "roles", groupPrefix: "example-org-", additional: true}
+	assert.Equal(t, []string{"example-org-admins", "example-org-users"}, additional.getGroupsFromClaims(claims))
+}
@@ -41,6 +41,9 @@ const (
 	// cookieNameLoginID is used to identify a single login flow.
 	cookieNameLoginID = "login_id"
 
+	// cookieNameLoginIssuer is used to identify the issuer of a single login flow.
+	cookieNameLoginIssuer = "login_issuer"
+
 	// cookieNameSession references a cookie that is a JWT containing the session information.
 	cookieNameSession = "session"
 
@@ -52,19 +55,34 @@ const (
 
 // Verifier holds all information needed to verify and manage OIDC logins and sessions.
 type Verifier struct {
-	relyingParty rp.RelyingParty
+	// providers contains the trusted issuers. The first provider is the issuer set in "oidc.issuer", the remaining
+	// providers are the issuers set in "oidc.additional_issuers".
+	providers []*provider
 
-	clientID       string
-	clientSecret   string
-	issuer         string
-	scopes         []string
-	audience       string
-	groupsClaim    string
 	deviceClientID string
+	claimMappings  []ClaimMapping
 	secretsFunc    func(ctx context.Context) (cluster.AuthSecrets, error)
 	httpClientFunc func() (*http.Client, error)
 	clusterUUID    string
 	sessionHandler SessionHandler
+}
+
+// provider holds the configuration and relying party of a trusted issuer.
+type provider struct {
+	relyingParty rp.RelyingParty
+
+	// additional is true if the issuer is not the issuer set in "oidc.issuer".
+	additional bool
+
+	issuer       string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	audience     string
+	groupsClaim  string
+
+	// groupPrefix is prepended to the groups from the groups claim.
+	groupPrefix string
 
 	// host is used for setting a valid callback URL when setting the relyingParty.
 	// When creating the relyingParty, the OIDC library performs discovery (e.g. it calls the /well-known/oidc-configuration endpoint).
@@ -76,11 +94,28 @@ type Verifier struct {
 	expireConfig bool
 }
 
+// sessionIssuer returns the issuer that is recorded for sessions started with the provider. This is empty for the
+// issuer set in "oidc.issuer", so that identities are not affected by changes to that issuer.
+func (p *provider) sessionIssuer() string {
+	if !p.additional {
+		return ""
+	}
+
+	return p.issuer
+}
+
 // AuthenticationResult represents an authenticated OIDC client.
 type AuthenticationResult struct {
-	IdentityType           string
-	Subject                string
-	Email                  string
+	IdentityType string
+	Subject      string
+
+	// Issuer is the issuer that authenticated the client if it is not the issuer set in "oidc.issuer".
+	Issuer string
+	Email  string
+
+	// EmailVerified is true if the issuer asserts that the email address has been verified (see the "email_verified"
+	// claim).
+	EmailVerified          bool
 	Name                   string
 	IdentityProviderGroups []string
 }
@@ -128,11 +163,11 @@ func (o *Verifier) Auth(w http.ResponseWriter, r *http.Request) (*Authentication
 	return nil, AuthError{Err: errors.New("No credentials found")}
 }
 
-// userInfo calls the /userinfo endpoint of the configured issuer with the given access token.
+// userInfo calls the /userinfo endpoint of the given provider with the given access token.
 // Note that this implementation is required because the zitadel library implementation asserts that the endpoint returns
 // a value with a specific subject, which we can't do for opaque tokens.
-func (o *Verifier) userInfo(ctx context.Context, token string) (*oidc.UserInfo, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.relyingParty.UserinfoEndpoint(), nil)
+func (o *Verifier) userInfo(ctx context.Context, p *provider, token string) (*oidc.UserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.relyingParty.UserinfoEndpoint(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +175,7 @@ func (o *Verifier) userInfo(ctx context.Context, token string) (*oidc.UserInfo,
 	// We only expect bearer tokens.
 	req.Header.Set("Authorization", "Bearer "+token)
 	var userinfo oidc.UserInfo
-	err = httphelper.HttpRequest(o.relyingParty.HttpClient(), req, &userinfo)
+	err = httphelper.HttpRequest(p.relyingParty.HttpClient(), req, &userinfo)
 	if err != nil {
 		return nil, fmt.Errorf("Failed getting user info: %w", err)
 	}
@@ -160,17 +195,22 @@ func (o *Verifier) authenticateBearerToken(r *http.Request, w http.ResponseWrite
 		return nil, api.StatusErrorf(http.StatusBadRequest, "OIDC authentication requires persistent cookie support. Please update your client")
 	}
 
-	err = o.ensureConfig(r.Context(), r.Host)
+	p, err := o.providerForToken(accessToken)
+	if err != nil {
+		return nil, AuthError{Err: err}
+	}
+
+	err = o.ensureConfig(r.Context(), p, r.Host)
 	if err != nil {
 		return nil, fmt.Errorf("Could not verify OIDC configuration: %w", err)
 	}
 
-	userInfo, err := o.userInfo(r.Context(), accessToken)
+	userInfo, err := o.userInfo(r.Context(), p, accessToken)
 	if err != nil {
 		return nil, AuthError{Err: fmt.Errorf("Failed calling user info endpoint with given access token: %w", err)}
 	}
 
-	res, err := o.getResultFromClaims(userInfo, userInfo.Claims)
+	res, err := o.getResultFromClaims(p, userInfo, userInfo.Claims)
 	if err != nil {
 		return nil, fmt.Errorf("Failed parsing user info response: %w", err)
 	}
@@ -248,7 +288,7 @@ func (o *Verifier) handleExpiredSession(r *http.Request, w http.ResponseWriter,
 		}
 	}()
 
-	_, tokens, _, err := o.sessionHandler.GetIdentityBySessionID(r.Context(), sessionID)
+	sessionRes, tokens, _, err := o.sessionHandler.GetIdentityBySessionID(r.Context(), sessionID)
 	if err != nil {
 		if !api.StatusErrorCheck(err, http.StatusNotFound) {
 			return nil, fmt.Errorf("Failed getting session information: %w", err)
@@ -272,15 +312,21 @@ func (o *Verifier) handleExpiredSession(r *http.Request, w http.ResponseWriter,
 		return nil, AuthError{Err: errors.New("Session expired, please log in again")}
 	}
 
-	err = o.ensureConfig(r.Context(), r.Host)
+	// The session must be reverified by the issuer that started it.
+	p := o.provider(sessionRes.Issuer)
+	if p == nil {
+		return nil, AuthError{Err: errors.New("Session expired, please log in again")}
+	}
+
+	err = o.ensureConfig(r.Context(), p, r.Host)
 	if err != nil {
 		return nil, fmt.Errorf("Failed verifying OIDC configuration: %w", err)
 	}
 
 	// Reverify access token.
-	userInfo, err := o.userInfo(r.Context(), tokens.AccessToken)
+	userInfo, err := o.userInfo(r.Context(), p, tokens.AccessToken)
 	if err == nil {
-		res, err := o.getResultFromClaims(userInfo, userInfo.Claims)
+		res, err := o.getResultFromClaims(p, userInfo, userInfo.Claims)
 		if err != nil {
 			return nil, err
 		}
@@ -298,18 +344,18 @@ func (o *Verifier) handleExpiredSession(r *http.Request, w http.ResponseWriter,
 		return nil, errors.New("Cannot refresh session")
 	}
 
-	refreshedTokens, err := rp.RefreshTokens[*oidc.IDTokenClaims](r.Context(), o.relyingParty, tokens.RefreshToken, "", "")
+	refreshedTokens, err := rp.RefreshTokens[*oidc.IDTokenClaims](r.Context(), p.relyingParty, tokens.RefreshToken, "", "")
 	if err != nil {
 		return nil, fmt.Errorf("Failed refreshing ID tokens: %w", err)
 	}
 
 	// Verify the refreshed access token.
-	userInfo, err = o.userInfo(r.Context(), refreshedTokens.AccessToken)
+	userInfo, err = o.userInfo(r.Context(), p, refreshedTokens.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("Failed verifying refreshed access token: %w", err)
 	}
 
-	res, err := o.getResultFromClaims(userInfo, userInfo.Claims)
+	res, err := o.getResultFromClaims(p, userInfo, userInfo.Claims)
 	if err != nil {
 		return nil, fmt.Errorf("Failed parsing user info response: %w", err)
 	}
@@ -347,9 +393,9 @@ func (o *Verifier) verifySessionToken(ctx context.Context, sessionToken string)
 	return sessionID, staleSigningKey, nil
 }
 
-// getResultFromClaims gets an AuthenticationResult from the given rp.SubjectGetter and claim map.
+// getResultFromClaims gets an AuthenticationResult from the given rp.SubjectGetter and claim map of the given provider.
 // It returns an error if any required values are not present or are invalid.
-func (o *Verifier) getResultFromClaims(sg rp.SubjectGetter, claims map[string]any) (*AuthenticationResult, error) {
+func (o *Verifier) getResultFromClaims(p *provider, sg rp.SubjectGetter, claims map[string]any) (*AuthenticationResult, error) {
 	email, err := o.getEmailFromClaims(claims)
 	if err != nil {
 		return nil, err
@@ -369,12 +415,18 @@ func (o *Verifier) getResultFromClaims(sg rp.SubjectGetter, claims map[string]an
 		}
 	}
 
+	// Combine the groups from the groups claim with the groups from the claim mappings.
+	groups := append(p.getGroupsFromClaims(claims), mapClaims(o.claimMappings, p.issuer, claims)...)
+	slices.Sort(groups)
+
 	return &AuthenticationResult{
 		IdentityType:           api.IdentityTypeOIDCClient,
 		Subject:                subject,
+		Issuer:                 p.sessionIssuer(),
 		Email:                  email,
+		EmailVerified:          getEmailVerifiedFromClaims(claims),
 		Name:                   name,
-		IdentityProviderGroups: o.getGroupsFromClaims(claims),
+		IdentityProviderGroups: slices.Compact(groups),
 	}, nil
 }
 
@@ -398,22 +450,35 @@ func (o *Verifier) getEmailFromClaims(claims map[string]any) (string, error) {
 	return email, nil
 }
 
+// getEmailVerifiedFromClaims returns whether the "email_verified" claim is set to true. Some identity providers set
+// the claim as a string rather than a boolean.
+func getEmailVerifiedFromClaims(claims map[string]any) bool {
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+
+	return false
+}
+
 // getGroupsFromClaims attempts to get the configured groups claim from the token claims and warns if it is not present
 // or is not a valid type. The custom claims are an unmarshalled JSON object.
-func (o *Verifier) getGroupsFromClaims(customClaims map[string]any) []string {
-	if o.groupsClaim == "" {
+func (p *provider) getGroupsFromClaims(customClaims map[string]any) []string {
+	if p.groupsClaim == "" {
 		return nil
 	}
 
-	groupsClaimAny, ok := customClaims[o.groupsClaim]
+	groupsClaimAny, ok := customClaims[p.groupsClaim]
 	if !ok {
-		logger.Warn("OIDC groups custom claim not found", logger.Ctx{"claim_name": o.groupsClaim})
+		logger.Warn("OIDC groups custom claim not found", logger.Ctx{"claim_name": p.groupsClaim, "issuer": p.issuer})
 		return nil
 	}
 
 	groupsArr, ok := groupsClaimAny.([]any)
 	if !ok {
-		logger.Warn("Unexpected type for OIDC groups custom claim", logger.Ctx{"claim_name": o.groupsClaim, "claim_value": groupsClaimAny})
+		logger.Warn("Unexpected type for OIDC groups custom claim", logger.Ctx{"claim_name": p.groupsClaim, "claim_value": groupsClaimAny})
 		return nil
 	}
 
@@ -421,19 +486,30 @@ func (o *Verifier) getGroupsFromClaims(customClaims map[string]any) []string {
 	for _, groupNameAny := range groupsArr {
 		groupName, ok := groupNameAny.(string)
 		if !ok {
-			logger.Warn("Unexpected type for OIDC groups custom claim", logger.Ctx{"claim_name": o.groupsClaim, "claim_value": groupsClaimAny})
+			logger.Warn("Unexpected type for OIDC groups custom claim", logger.Ctx{"claim_name": p.groupsClaim, "claim_value": groupsClaimAny})
 			return nil
 		}
 
-		groups = append(groups, groupName)
+		groups = append(groups, p.groupPrefix+groupName)
 	}
 
 	return groups
 }
 
-// Login is a http.Handler than initiates the login flow for the UI.
+// Login is a http.Handler than initiates the login flow for the UI. The issuer to log in with may be selected with the
+// "issuer" query parameter, otherwise the issuer set in "oidc.issuer" is used.
 func (o *Verifier) Login(w http.ResponseWriter, r *http.Request) {
-	err := o.ensureConfig(r.Context(), r.Host)
+	issuer := r.URL.Query().Get("issuer")
+	p := o.providers[0]
+	if issuer != "" {
+		p = o.providerByURL(issuer)
+		if p == nil {
+			_ = response.BadRequest(fmt.Errorf("Login failed: Issuer %q is not trusted", issuer)).Render(w, r)
+			return
+		}
+	}
+
+	err := o.ensureConfig(r.Context(), p, r.Host)
 	if err != nil {
 		_ = response.ErrorResponse(http.StatusInternalServerError, fmt.Errorf("Login failed: %w", err).Error()).Render(w, r)
 		return
@@ -470,7 +546,17 @@ func (o *Verifier) Login(w http.ResponseWriter, r *http.Request) {
 	// must set this on the response now, because the AuthURLHandler below will send a HTTP redirect.
 	http.SetCookie(w, loginIDCookie)
 
-	handler := rp.AuthURLHandler(func() string { return uuid.New().String() }, o.relyingParty, rp.WithURLParam("audience", o.audience))
+	// Record the issuer of the login flow, so that /oidc/callback exchanges the code with the same issuer.
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieNameLoginIssuer,
+		Path:     "/",
+		Value:    p.issuer,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	handler := rp.AuthURLHandler(func() string { return uuid.New().String() }, p.relyingParty, rp.WithURLParam("audience", p.audience))
 	handler(w, r)
 }
 
@@ -516,19 +602,38 @@ func (o *Verifier) Callback(w http.ResponseWriter, r *http.Request) {
 		Expires:  time.Unix(0, 0),
 	})
 
-	err := o.ensureConfig(r.Context(), r.Host)
+	p := o.providers[0]
+	issuerCookie, err := r.Cookie(cookieNameLoginIssuer)
+	if err == nil {
+		http.SetCookie(w, &http.Cookie{
+			Name:     cookieNameLoginIssuer,
+			Path:     "/",
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+			Expires:  time.Unix(0, 0),
+		})
+
+		p = o.providerByURL(issuerCookie.Value)
+		if p == nil {
+			_ = response.BadRequest(fmt.Errorf("OIDC callback failed: Issuer %q is not trusted", issuerCookie.Value)).Render(w, r)
+			return
+		}
+	}
+
+	err = o.ensureConfig(r.Context(), p, r.Host)
 	if err != nil {
 		_ = response.ErrorResponse(http.StatusInternalServerError, fmt.Errorf("OIDC callback failed: %w", err).Error()).Render(w, r)
 		return
 	}
 
 	callback := func(ctx context.Context, tokens *oidc.Tokens[*oidc.IDTokenClaims]) error {
-		userInfo, err := o.userInfo(r.Context(), tokens.AccessToken)
+		userInfo, err := o.userInfo(r.Context(), p, tokens.AccessToken)
 		if err != nil {
 			return fmt.Errorf("Failed getting caller identity: %w", err)
 		}
 
-		res, err := o.getResultFromClaims(userInfo, userInfo.Claims)
+		res, err := o.getResultFromClaims(p, userInfo, userInfo.Claims)
 		if err != nil {
 			return fmt.Errorf("Failed parsing user info response: %w", err)
 		}
@@ -551,21 +656,23 @@ func (o *Verifier) Callback(w http.ResponseWriter, r *http.Request) {
 		// Send to the UI.
 		// NOTE: Once the UI does the redirection on its own, we may be able to use the referer here instead.
 		http.Redirect(w, r, "/ui/", http.StatusMovedPermanently)
-	}, o.relyingParty)
+	}, p.relyingParty)
 
 	handler(w, r)
 }
 
 // WriteHeaders writes the OIDC configuration as HTTP headers so the client can initatiate the device code flow.
+// The device code flow always uses the issuer set in "oidc.issuer".
 func (o *Verifier) WriteHeaders(w http.ResponseWriter) error {
-	w.Header().Set("X-LXD-OIDC-issuer", o.issuer)
+	p := o.providers[0]
+	w.Header().Set("X-LXD-OIDC-issuer", p.issuer)
 	w.Header().Set("X-LXD-OIDC-clientid", o.deviceClientID)
-	w.Header().Set("X-LXD-OIDC-audience", o.audience)
+	w.Header().Set("X-LXD-OIDC-audience", p.audience)
 
 	// Continue to sent groups claim header for compatibility with older clients
-	w.Header().Set("X-LXD-OIDC-groups-claim", o.groupsClaim)
+	w.Header().Set("X-LXD-OIDC-groups-claim", p.groupsClaim)
 
-	scopesJSON, err := json.Marshal(o.scopes)
+	scopesJSON, err := json.Marshal(p.scopes)
 	if err != nil {
 		return fmt.Errorf("Failed marshaling OIDC scopes: %w", err)
 	}
@@ -589,28 +696,92 @@ func (*Verifier) IsRequest(r *http.Request) bool {
 // ExpireConfig sets the expiry time of the current configuration to zero. This forces the verifier to reconfigure the
 // relying party the next time a user authenticates.
 func (o *Verifier) ExpireConfig() {
-	o.expireConfig = true
+	for _, p := range o.providers {
+		p.expireConfig = true
+	}
+}
+
+// provider returns the provider of the given session issuer (see [provider.sessionIssuer]), or nil if the issuer is
+// no longer trusted.
+func (o *Verifier) provider(sessionIssuer string) *provider {
+	if sessionIssuer == "" {
+		return o.providers[0]
+	}
+
+	for _, p := range o.providers[1:] {
+		if sameIssuer(p.issuer, sessionIssuer) {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// providerByURL returns the provider with the given issuer URL, or nil if the issuer is not trusted.
+func (o *Verifier) providerByURL(issuer string) *provider {
+	for _, p := range o.providers {
+		if sameIssuer(p.issuer, issuer) {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// providerForToken returns the provider that must verify the given access token. Access tokens of additional issuers
+// must be JWTs so that their issuer can be determined from the "iss" claim. All other access tokens are verified by
+// the issuer set in "oidc.issuer".
+//
+// The claims of the token are not verified here, they are only used to select the issuer. The token is verified by
+// calling the user info endpoint of the selected issuer.
+func (o *Verifier) providerForToken(accessToken string) (*provider, error) {
+	claims := jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(accessToken, claims)
+	if err != nil {
+		// Not a JWT (e.g. an opaque access token).
+		return o.providers[0], nil
+	}
+
+	issuer, err := claims.GetIssuer()
+	if err != nil || issuer == "" {
+		return o.providers[0], nil
+	}
+
+	p := o.providerByURL(issuer)
+	if p == nil || !p.additional {
+		return o.providers[0], nil
+	}
+
+	// Check the audience of tokens issued by additional issuers, so that tokens that were issued by the same issuer
+	// for other applications are not accepted.
+	if p.audience != "" {
+		audience, err := claims.GetAudience()
+		if err != nil || !slices.Contains(audience, p.audience) {
+			return nil, fmt.Errorf("Access token issued by %q is not valid for audience %q", p.issuer, p.audience)
+		}
+	}
+
+	return p, nil
 }
 
-// ensureConfig ensures that the relyingParty and accessTokenVerifier fields of the Verifier are non-nil. Additionally,
-// if the given host is different from the Verifier host we reset the relyingParty to ensure the callback URL is set
-// correctly.
-func (o *Verifier) ensureConfig(ctx context.Context, host string) error {
-	if o.relyingParty == nil || host != o.host || o.expireConfig {
-		err := o.setRelyingParty(ctx, host)
+// ensureConfig ensures that the relyingParty field of the given provider is non-nil. Additionally, if the given host is
+// different from the provider host we reset the relyingParty to ensure the callback URL is set correctly.
+func (o *Verifier) ensureConfig(ctx context.Context, p *provider, host string) error {
+	if p.relyingParty == nil || host != p.host || p.expireConfig {
+		err := o.setRelyingParty(ctx, p, host)
 		if err != nil {
 			return err
 		}
 
-		o.host = host
-		o.expireConfig = false
+		p.host = host
+		p.expireConfig = false
 	}
 
 	return nil
 }
 
-// setRelyingParty sets the relyingParty on the Verifier. The host argument is used to set a valid callback URL.
-func (o *Verifier) setRelyingParty(ctx context.Context, host string) error {
+// setRelyingParty sets the relyingParty on the given provider. The host argument is used to set a valid callback URL.
+func (o *Verifier) setRelyingParty(ctx context.Context, p *provider, host string) error {
 	// The relying party sets cookies for the following values:
 	// - "state": Used to prevent CSRF attacks (https://datatracker.ietf.org/doc/html/rfc6749#section-10.12).
 	// - "pkce": Used to prevent authorization code interception attacks (https://datatracker.ietf.org/doc/html/rfc7636).
@@ -652,12 +823,12 @@ func (o *Verifier) setRelyingParty(ctx context.Context, host string) error {
 		rp.WithHTTPClient(httpClient),
 	}
 
-	relyingParty, err := rp.NewRelyingPartyOIDC(ctx, o.issuer, o.clientID, o.clientSecret, "https://"+host+"/oidc/callback", o.scopes, options...)
+	relyingParty, err := rp.NewRelyingPartyOIDC(ctx, p.issuer, p.clientID, p.clientSecret, "https://"+host+"/oidc/callback", p.scopes, options...)
 	if err != nil {
-		return fmt.Errorf("Failed getting OIDC relying party: %w", err)
+		return fmt.Errorf("Failed getting OIDC relying party for issuer %q: %w", p.issuer, err)
 	}
 
-	o.relyingParty = relyingParty
+	p.relyingParty = relyingParty
 	return nil
 }
 
@@ -775,26 +946,49 @@ func (o *Verifier) secureCookieFromV7UUID(ctx context.Context, sessionID uuid.UU
 	return securecookie.New(cookieHashKey, cookieBlockKey), nil
 }
 
-// NewVerifier returns a Verifier.
-func NewVerifier(ctx context.Context, issuer string, clientID string, clientSecret string, scopes []string, audience string, groupsClaim string, deviceClientID string, clusterUUID string, networkAddress string, secretsFunc func(ctx context.Context) (cluster.AuthSecrets, error), httpClientFunc func() (*http.Client, error), sessionHandler SessionHandler) (*Verifier, error) {
+// NewVerifier returns a Verifier. The additional issuers are trusted in addition to the given issuer, and the claim
+// mappings are applied to the claims of identities from all issuers.
+func NewVerifier(ctx context.Context, issuer string, clientID string, clientSecret string, scopes []string, audience string, groupsClaim string, deviceClientID string, additionalIssuers []Issuer, claimMappings []ClaimMapping, clusterUUID string, networkAddress string, secretsFunc func(ctx context.Context) (cluster.AuthSecrets, error), httpClientFunc func() (*http.Client, error), sessionHandler SessionHandler) (*Verifier, error) {
 	verifier := &Verifier{
-		issuer:         issuer,
-		clientID:       clientID,
-		clientSecret:   clientSecret,
-		scopes:         scopes,
-		audience:       audience,
-		groupsClaim:    groupsClaim,
+		providers: []*provider{{
+			issuer:       issuer,
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			scopes:       scopes,
+			audience:     audience,
+			groupsClaim:  groupsClaim,
+		}},
 		deviceClientID: deviceClientID,
+		claimMappings:  claimMappings,
 		clusterUUID:    clusterUUID,
 		secretsFunc:    secretsFunc,
 		httpClientFunc: httpClientFunc,
 		sessionHandler: sessionHandler,
 	}
 
+	for _, additionalIssuer := range additionalIssuers {
+		if verifier.providerByURL(additionalIssuer.Issuer) != nil {
+			return nil, fmt.Errorf("Issuer %q is already trusted", additionalIssuer.Issuer)
+		}
+
+		verifier.providers = append(verifier.providers, &provider{
+			additional:   true,
+			issuer:       additionalIssuer.Issuer,
+			clientID:     additionalIssuer.ClientID,
+			clientSecret: additionalIssuer.ClientSecret,
+			scopes:       additionalIssuer.Scopes,
+			audience:     additionalIssuer.Audience,
+			groupsClaim:  additionalIssuer.GroupsClaim,
+			groupPrefix:  additionalIssuer.GroupPrefix,
+		})
+	}
+
 	// Ensure configuration is valid with daemon's network address.
-	err := verifier.ensureConfig(ctx, networkAddress)
-	if err != nil {
-		return nil, fmt.Errorf("Failed ensuring new verifier's configuration: %w", err)
+	for _, p := range verifier.providers {
+		err := verifier.ensureConfig(ctx, p, networkAddress)
+		if err != nil {
+			return nil, fmt.Errorf("Failed ensuring new verifier's configuration: %w", err)
+		}
 	}
 
 	return verifier, nil
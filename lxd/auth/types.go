@@ -51,6 +51,10 @@ type Authorizer interface {
 
 	// GetViewableProjects accepts a list of permissions and returns a list of projects that a member of a group with these permissions is able to view.
 	GetViewableProjects(ctx context.Context, permissions []api.Permission) ([]string, error)
+
+	// GetGrantingPermissions accepts a list of permissions and returns those that, on their own, grant the given
+	// entitlement on the entity found at the given URL to a member of a group with the permission.
+	GetGrantingPermissions(ctx context.Context, entityURL *api.URL, entitlement Entitlement, permissions []api.Permission) ([]api.Permission, error)
 }
 
 // IsDeniedError returns true if the error is not found or forbidden. This is because the CheckPermission method on
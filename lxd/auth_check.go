@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/identity"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+var authCheckCmd = APIEndpoint{
+	Path:        "auth/check",
+	MetricsType: entity.TypeIdentity,
+	Get: APIEndpointAction{
+		Handler:       authCheckGet,
+		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewPermissions),
+	},
+}
+
+// swagger:operation GET /1.0/auth/check permissions auth_check_get
+//
+//	Check an authorization decision
+//
+//	Checks whether an identity has an entitlement on an entity, and explains which groups, identity provider group
+//	mappings, and permissions allow the access, or why it is denied.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: identity
+//	    description: URL of the identity
+//	    type: string
+//	    example: /1.0/auth/identities/oidc/jane.doe@example.com
+//	  - in: query
+//	    name: entity
+//	    description: URL of the entity
+//	    type: string
+//	    example: /1.0/instances/c1?project=default
+//	  - in: query
+//	    name: entitlement
+//	    description: Entitlement to check
+//	    type: string
+//	    example: can_exec
+//	responses:
+//	  "200":
+//	    description: Authorization check
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthCheck"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authCheckGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	identityReference := request.QueryParam(r, "identity")
+	entityReference := request.QueryParam(r, "entity")
+	entitlement := auth.Entitlement(request.QueryParam(r, "entitlement"))
+	if identityReference == "" || entityReference == "" || entitlement == "" {
+		return response.BadRequest(errors.New("The identity, entity and entitlement query parameters are required"))
+	}
+
+	identityURL, err := url.Parse(identityReference)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Failed parsing identity URL %q: %w", identityReference, err))
+	}
+
+	identityEntityType, _, _, identityPathArguments, err := entity.ParseURL(*identityURL)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Failed parsing identity URL %q: %w", identityReference, err))
+	}
+
+	if identityEntityType != entity.TypeIdentity || len(identityPathArguments) != 2 {
+		return response.BadRequest(fmt.Errorf("URL %q is not an identity URL", identityReference))
+	}
+
+	u, err := url.Parse(entityReference)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Failed parsing entity URL %q: %w", entityReference, err))
+	}
+
+	entityType, projectName, location, pathArguments, err := entity.ParseURL(*u)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Failed parsing entity URL %q: %w", entityReference, err))
+	}
+
+	err = auth.ValidateEntitlement(entityType, entitlement)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Construct the URL in a standardised form (adding the project parameter if it was not present).
+	entityURL, err := entityType.URL(projectName, location, pathArguments...)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed standardizing entity URL: %w", err))
+	}
+
+	var id *dbCluster.IdentitiesRow
+	var directGroups []string
+	mappedGroups := make(map[string][]string)
+	groupPermissions := make(map[string][]api.Permission)
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		id, err = dbCluster.GetIdentityByNameOrIdentifier(ctx, tx.Tx(), identityPathArguments[0], identityPathArguments[1])
+		if err != nil {
+			return err
+		}
+
+		groupIDs := make(map[string]int64)
+		dbGroups, err := dbCluster.GetAuthGroupsByIdentityID(ctx, tx.Tx(), id.ID)
+		if err != nil {
+			return fmt.Errorf("Failed getting groups for identity: %w", err)
+		}
+
+		for _, g := range dbGroups {
+			directGroups = append(directGroups, g.Name)
+			groupIDs[g.Name] = g.ID
+		}
+
+		if id.AuthMethod == api.AuthenticationMethodOIDC {
+			metadata, err := id.OIDCMetadata()
+			if err != nil {
+				return fmt.Errorf("Failed reading OIDC identity metadata: %w", err)
+			}
+
+			// Record the identity provider groups that each mapped group comes from.
+			for _, idpGroupName := range metadata.IdentityProviderGroups {
+				idpGroup, err := dbCluster.GetIdentityProviderGroup(ctx, tx.Tx(), idpGroupName)
+				if err != nil {
+					if api.StatusErrorCheck(err, http.StatusNotFound) {
+						continue
+					}
+
+					return fmt.Errorf("Failed getting identity provider group %q: %w", idpGroupName, err)
+				}
+
+				dbMappedGroups, err := dbCluster.GetAuthGroupsByIdentityProviderGroupID(ctx, tx.Tx(), idpGroup.ID)
+				if err != nil {
+					return fmt.Errorf("Failed getting groups mapped to identity provider group %q: %w", idpGroupName, err)
+				}
+
+				for _, g := range dbMappedGroups {
+					mappedGroups[g.Name] = append(mappedGroups[g.Name], idpGroupName)
+					groupIDs[g.Name] = g.ID
+				}
+			}
+		}
+
+		for groupName, groupID := range groupIDs {
+			groupPermissions[groupName], err = dbCluster.GetAPIPermissionsByAuthGroupID(ctx, tx.Tx(), groupID)
+			if err != nil {
+				return fmt.Errorf("Failed getting permissions of group %q: %w", groupName, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	identityType, err := identity.New(string(id.Type))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	result := api.AuthCheck{
+		Identity:        entity.IdentityURL(string(id.AuthMethod), id.Identifier).String(),
+		EntityReference: entityURL.String(),
+		Entitlement:     string(entitlement),
+		Grants:          []api.AuthCheckGrant{},
+	}
+
+	if identityType.IsPending() {
+		result.Reason = "The identity is pending and cannot authenticate"
+		return response.SyncResponse(true, result)
+	}
+
+	// Evaluate the permission through the authorizer as if the identity had made a request.
+	// Denials are not reported as security events because the identity did not make the request.
+	ctx := context.WithValue(r.Context(), request.CtxAuthorizationCheck, true)
+	ctx = context.WithValue(ctx, request.CtxOpenFGARequestCache, nil)
+	checkReq := (&http.Request{RemoteAddr: r.RemoteAddr, Header: http.Header{}}).WithContext(ctx)
+	err = request.SetRequestor(checkReq, d.requestorHook, request.RequestorArgs{
+		Trusted:  true,
+		Protocol: string(id.AuthMethod),
+		Username: id.Identifier,
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	checkErr := s.Authorizer.CheckPermissionWithoutEffectiveProject(checkReq.Context(), entityURL, entitlement)
+	if checkErr != nil && !auth.IsDeniedError(checkErr) {
+		return response.SmartError(checkErr)
+	}
+
+	result.Allowed = checkErr == nil
+
+	if identityType.IsAdmin() {
+		result.Reason = fmt.Sprintf("Identities of type %q have full access", id.Type)
+		return response.SyncResponse(true, result)
+	}
+
+	if !identityType.IsFineGrained() {
+		requestor, err := request.GetRequestor(checkReq.Context())
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		projects := requestor.CallerAllowedProjectNames()
+		if result.Allowed {
+			result.Reason = fmt.Sprintf("Identities of type %q are granted access within the projects that they are restricted to (%s)", id.Type, strings.Join(projects, ", "))
+		} else {
+			result.Reason = fmt.Sprintf("Identities of type %q are restricted to projects (%s): %v", id.Type, strings.Join(projects, ", "), checkErr)
+		}
+
+		return response.SyncResponse(true, result)
+	}
+
+	groupNames := make([]string, 0, len(groupPermissions))
+	for groupName := range groupPermissions {
+		groupNames = append(groupNames, groupName)
+	}
+
+	slices.Sort(groupNames)
+
+	grantingGroups := make([]string, 0, len(groupNames))
+	for _, groupName := range groupNames {
+		permissions, err := s.Authorizer.GetGrantingPermissions(r.Context(), entityURL, entitlement, groupPermissions[groupName])
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if len(permissions) == 0 {
+			continue
+		}
+
+		// Identity provider groups are only reported if the identity is not a direct member of the group.
+		idpGroups := []string{}
+		if !slices.Contains(directGroups, groupName) {
+			idpGroups = mappedGroups[groupName]
+		}
+
+		result.Grants = append(result.Grants, api.AuthCheckGrant{
+			Group:                  groupName,
+			IdentityProviderGroups: idpGroups,
+			Permissions:            permissions,
+		})
+
+		grantingGroups = append(grantingGroups, fmt.Sprintf("group %q", groupName))
+	}
+
+	switch {
+	case result.Allowed && len(grantingGroups) > 0:
+		result.Reason = "Granted by " + strings.Join(grantingGroups, ", ")
+	case result.Allowed:
+		result.Reason = "Not granted by any group, but identities are always granted this entitlement (for example, identities can always view themselves)"
+	case len(grantingGroups) > 0:
+		result.Reason = "Granted by " + strings.Join(grantingGroups, ", ") + ", but not by the permissions that the bearer token of the identity is narrowed to"
+	case api.StatusErrorCheck(checkErr, http.StatusForbidden):
+		result.Reason = "Not granted by any group of the identity, although the identity can view the entity"
+	default:
+		result.Reason = "Not granted by any group of the identity, and the identity cannot view the entity"
+	}
+
+	return response.SyncResponse(true, result)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/identity"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// certificateExpiryCheckTask runs daily and raises warnings for certificates that expire within the period set in
+// core.certificate_expiry_warning. Each member checks its own server certificate, and the leader checks the cluster
+// certificate and the certificates of TLS client identities.
+func certificateExpiryCheckTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := certificateExpiryCheck(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed checking certificate expiry", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Daily()
+}
+
+// certificateExpiryCheck updates the certificate expiry warnings.
+func certificateExpiryCheck(ctx context.Context, s *state.State) error {
+	threshold, err := shared.GetExpiry(time.Now(), s.GlobalConfig.CertificateExpiryWarning())
+	if err != nil {
+		return fmt.Errorf("Failed parsing certificate expiry warning period: %w", err)
+	}
+
+	// An empty period disables the warnings.
+	expiring := func(cert *x509.Certificate) bool {
+		return !threshold.IsZero() && cert.NotAfter.Before(threshold)
+	}
+
+	err = certificateExpiryUpdateWarning(ctx, s, warningtype.ServerCertificateExpiring, "", -1, s.ServerCert().PublicKeyX509, expiring, "Server certificate")
+	if err != nil {
+		return err
+	}
+
+	leaderInfo, err := s.LeaderInfo()
+	if err != nil {
+		return err
+	}
+
+	if !leaderInfo.Leader {
+		// Clear any warnings raised while this member was the leader.
+		for _, warningType := range []warningtype.Type{warningtype.ClusterCertificateExpiring, warningtype.ClientCertificateExpiring} {
+			err = warnings.ResolveWarningsByLocalNodeAndType(s.DB.Cluster, warningType)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if s.ServerClustered {
+		err = certificateExpiryUpdateWarning(ctx, s, warningtype.ClusterCertificateExpiring, "", -1, s.Endpoints.NetworkCert().PublicKeyX509, expiring, "Cluster certificate")
+		if err != nil {
+			return err
+		}
+	} else {
+		err = warnings.ResolveWarningsByLocalNodeAndType(s.DB.Cluster, warningtype.ClusterCertificateExpiring)
+		if err != nil {
+			return err
+		}
+	}
+
+	var identities []dbCluster.IdentitiesRow
+	var certificatesByIdentityID map[int64][]string
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		authMethod := api.AuthenticationMethodTLS
+		identities, _, err = dbCluster.GetIdentitiesAndURLs(ctx, tx.Tx(), &authMethod, func(row dbCluster.IdentitiesRow) bool {
+			identityType, err := identity.New(string(row.Type))
+			if err != nil {
+				return false
+			}
+
+			// Server certificates are checked by each member, and pending identities have no certificate yet.
+			return row.Type != api.IdentityTypeCertificateServer && !identityType.IsPending()
+		})
+		if err != nil {
+			return err
+		}
+
+		certificatesByIdentityID, err = dbCluster.GetIdentitiesPEMCertificates(ctx, tx.Tx(), nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range identities {
+		certs := certificatesByIdentityID[id.ID]
+		if len(certs) == 0 {
+			continue
+		}
+
+		certificate := func() (*x509.Certificate, error) {
+			return shared.ParseCert([]byte(certs[0]))
+		}
+
+		err = certificateExpiryUpdateWarning(ctx, s, warningtype.ClientCertificateExpiring, entity.TypeIdentity, int(id.ID), certificate, expiring, fmt.Sprintf("Certificate of identity %q", id.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// certificateExpiryUpdateWarning raises a warning of the given type if the given certificate is expiring, and resolves
+// it otherwise.
+func certificateExpiryUpdateWarning(ctx context.Context, s *state.State, warningType warningtype.Type, entityType entity.Type, entityID int, certificate func() (*x509.Certificate, error), expiring func(*x509.Certificate) bool, description string) error {
+	cert, err := certificate()
+	if err != nil {
+		logger.Warn("Failed parsing certificate for expiry check", logger.Ctx{"certificate": description, "err": err})
+		return nil
+	}
+
+	if !expiring(cert) {
+		if entityType == "" {
+			return warnings.ResolveWarningsByLocalNodeAndType(s.DB.Cluster, warningType)
+		}
+
+		return warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningType, entityType, entityID)
+	}
+
+	verb := "expires"
+	if cert.NotAfter.Before(time.Now()) {
+		verb = "expired"
+	}
+
+	message := fmt.Sprintf("%s (fingerprint %q) %s on %s", description, shared.CertFingerprint(cert), verb, cert.NotAfter.UTC().Format(time.RFC3339))
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, "", entityType, entityID, warningType, message)
+	})
+}
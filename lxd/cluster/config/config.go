@@ -13,10 +13,13 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/zitadel/oidc/v3/pkg/oidc"
 
+	lxdOIDC "github.com/canonical/lxd/lxd/auth/oidc"
 	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/scheduler"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
 )
 
@@ -93,6 +96,17 @@ func (c *Config) TrustCACertificates() bool {
 	return c.m.GetBool("core.trust_ca_certificates")
 }
 
+// TrustCARevocation returns how the revocation status of client certificates that are signed by the CA is checked
+// (empty if only the local certificate revocation list is used).
+func (c *Config) TrustCARevocation() string {
+	return c.m.GetString("core.trust_ca_revocation")
+}
+
+// CertificateExpiryWarning returns how long before a certificate expires a warning is raised.
+func (c *Config) CertificateExpiryWarning() string {
+	return c.m.GetString("core.certificate_expiry_warning")
+}
+
 // ProxyHTTPS returns the configured HTTPS proxy, if any.
 func (c *Config) ProxyHTTPS() string {
 	return c.m.GetString("core.proxy_https")
@@ -213,6 +227,12 @@ func (c *Config) ACME() (domain string, email string, caURL string, agreeTOS boo
 	return c.m.GetString("acme.domain"), c.m.GetString("acme.email"), c.m.GetString("acme.ca_url"), c.m.GetBool("acme.agree_tos")
 }
 
+// ACMEChallenge returns the ACME challenge type and, for the DNS-01 challenge, the network zone in which the challenge
+// is published.
+func (c *Config) ACMEChallenge() (challengeType string, zoneName string) {
+	return c.m.GetString("acme.challenge"), c.m.GetString("acme.dns.zone")
+}
+
 // ClusterJoinTokenExpiry returns the cluster join token expiry.
 func (c *Config) ClusterJoinTokenExpiry() string {
 	return c.m.GetString("cluster.join_token_expiry")
@@ -223,6 +243,13 @@ func (c *Config) RemoteTokenExpiry() string {
 	return c.m.GetString("core.remote_token_expiry")
 }
 
+// AuditLimits returns the maximum size in bytes of the audit log file, and the number of rotated audit log files to keep.
+func (c *Config) AuditLimits() (maxSize int64, maxFiles int) {
+	// Validated in the config schema.
+	maxSize, _ = units.ParseByteSizeString(c.m.GetString("core.audit_max_size"))
+	return maxSize, int(c.m.GetInt64("core.audit_max_files"))
+}
+
 // AuthSecretExpiry returns the time after which an core secret is invalid.
 func (c *Config) AuthSecretExpiry() string {
 	return c.m.GetString("core.auth_secret_expiry")
@@ -240,6 +267,20 @@ func (c *Config) OIDCServer() (issuer string, clientID string, clientSecret stri
 	return c.m.GetString("oidc.issuer"), clientID, c.m.GetString("oidc.client.secret"), strings.Fields(c.m.GetString("oidc.scopes")), c.m.GetString("oidc.audience"), c.m.GetString("oidc.groups.claim"), deviceClientID
 }
 
+// OIDCAdditionalIssuers returns the OpenID Connect issuers that are trusted in addition to "oidc.issuer".
+func (c *Config) OIDCAdditionalIssuers() []lxdOIDC.Issuer {
+	// Validated in the config schema.
+	issuers, _ := lxdOIDC.ParseIssuers(c.m.GetString("oidc.additional_issuers"))
+	return issuers
+}
+
+// OIDCClaimMappings returns the rules that map the claims of OpenID Connect identities to identity provider groups.
+func (c *Config) OIDCClaimMappings() []lxdOIDC.ClaimMapping {
+	// Validated in the config schema.
+	mappings, _ := lxdOIDC.ParseClaimMappings(c.m.GetString("oidc.groups.mappings"))
+	return mappings
+}
+
 // OIDCDeviceClientID returns the raw value of "oidc.device.client.id".
 // It is used to validate that unsetting "oidc.client.id" set will not leave the device client ID set.
 func (c *Config) OIDCDeviceClientID() string {
@@ -273,6 +314,30 @@ func (c *Config) ClusterHealingThreshold() time.Duration {
 	return healingThreshold
 }
 
+// ClusterFencing returns the fencing settings for offline cluster members: the fencing command, the power command,
+// whether to fence Ceph RBD volumes and the fencing timeout.
+func (c *Config) ClusterFencing() (command string, powerCommand string, cephRBD bool, timeout time.Duration) {
+	timeout = time.Duration(c.m.GetInt64("cluster.fencing.timeout")) * time.Second
+	return c.m.GetString("cluster.fencing.command"), c.m.GetString("cluster.fencing.power_command"), c.m.GetBool("cluster.fencing.ceph_rbd"), timeout
+}
+
+// ClusterRebalance returns the instance rebalancing settings: the interval between runs (0 if disabled),
+// the load difference threshold in percent, the maximum number of moves per run and the cooldown expression.
+func (c *Config) ClusterRebalance() (interval time.Duration, threshold int64, batch int64, cooldown string) {
+	interval = time.Duration(c.m.GetInt64("cluster.rebalance.interval")) * time.Minute
+	return interval, c.m.GetInt64("cluster.rebalance.threshold"), c.m.GetInt64("cluster.rebalance.batch"), c.m.GetString("cluster.rebalance.cooldown")
+}
+
+// ClusterSchedulerStrategy returns the strategy used to select cluster members for new instances.
+func (c *Config) ClusterSchedulerStrategy() string {
+	return c.m.GetString("cluster.scheduler.strategy")
+}
+
+// ClusterDatabaseBackup returns the schedule of the cluster database backups (empty if disabled) and the number of backups to keep.
+func (c *Config) ClusterDatabaseBackup() (schedule string, retention int) {
+	return c.m.GetString("cluster.database_backup.schedule"), int(c.m.GetInt64("cluster.database_backup.retention"))
+}
+
 // Dump current configuration keys and their values. Keys with values matching
 // their defaults are omitted.
 func (c *Config) Dump() map[string]string {
@@ -373,6 +438,29 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Agree to ACME terms of service
 		"acme.agree_tos": {Type: config.Bool, Default: "false"},
 
+		// lxdmeta:generate(entities=server; group=acme; key=acme.challenge)
+		// Possible values are `HTTP-01` and `DNS-01`.
+		//
+		// With `HTTP-01`, the ACME service validates the domain by connecting to LXD on port 80, which usually requires a
+		// reverse proxy. With `DNS-01`, LXD publishes the challenge as a TXT record in the network zone set in
+		// {config:option}`server-acme:acme.dns.zone`, so LXD does not need to be reachable by the ACME service.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `HTTP-01`
+		//  shortdesc: ACME challenge type to use
+		"acme.challenge": {Default: "HTTP-01", Validator: validate.IsOneOf("HTTP-01", "DNS-01")},
+
+		// lxdmeta:generate(entities=server; group=acme; key=acme.dns.zone)
+		// The `_acme-challenge` TXT records for the DNS-01 challenge are added to the custom records of this network zone.
+		// The zone must contain the `_acme-challenge` name of the domain, or the name that it is delegated to with a CNAME
+		// record, and must be served to the public DNS (see {ref}`network-dns-server`).
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Network zone in which DNS-01 challenges are published
+		"acme.dns.zone": {},
+
 		// lxdmeta:generate(entities=server; group=miscellaneous; key=backups.compression_algorithm)
 		// Possible values are `bzip2`, `gzip`, `lzma`, `xz`, or `none`.
 		// ---
@@ -410,6 +498,123 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Threshold when to evacuate an offline cluster member
 		"cluster.healing_threshold": {Type: config.Int64, Default: "0"},
 
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.fencing.command)
+		// Specify the absolute path to a command that fences an offline cluster member before automatic healing.
+		// The command is run on the leader with the name and address of the cluster member as arguments, and fencing is only confirmed if it exits successfully.
+		// See {ref}`cluster-fencing` for more information.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Command to fence offline cluster members
+		"cluster.fencing.command": {Type: config.String, Validator: validate.Optional(validate.IsAbsFilePath)},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.fencing.power_command)
+		// Specify the absolute path to an IPMI-like power command used to power off an offline cluster member before automatic healing.
+		// The command is run on the leader with an action (`off` or `status`) and the name and address of the cluster member as arguments.
+		// For the `status` action, it must print either `on` or `off`. Fencing is only confirmed once the cluster member is reported as `off`.
+		// See {ref}`cluster-fencing` for more information.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Power command to fence offline cluster members
+		"cluster.fencing.power_command": {Type: config.String, Validator: validate.Optional(validate.IsAbsFilePath)},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.fencing.ceph_rbd)
+		// Whether to fence the Ceph RBD volumes of the instances of an offline cluster member before automatic healing.
+		// The Ceph clients using the volumes are blocklisted and their locks on the volumes are released.
+		// See {ref}`cluster-fencing` for more information.
+		// ---
+		//  type: bool
+		//  scope: global
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to fence Ceph RBD volumes of offline cluster members
+		"cluster.fencing.ceph_rbd": {Type: config.Bool, Default: "false"},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.fencing.timeout)
+		// Specify the number of seconds after which fencing an offline cluster member is considered failed.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `120`
+		//  shortdesc: Timeout for fencing an offline cluster member
+		"cluster.fencing.timeout": {Type: config.Int64, Default: "120", Validator: validate.Optional(validate.IsInRange(1, 3600))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.interval)
+		// Specify the number of minutes between automatic instance rebalancing runs.
+		// To disable automatic rebalancing, set this option to `0`.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `0`
+		//  shortdesc: How often to rebalance instances across cluster members
+		"cluster.rebalance.interval": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 10080))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.threshold)
+		// Specify the minimum difference (in percent) between the load scores of the most and least loaded cluster members for instances to be moved.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `20`
+		//  shortdesc: Load difference that triggers instance rebalancing
+		"cluster.rebalance.threshold": {Type: config.Int64, Default: "20", Validator: validate.Optional(validate.IsInRange(1, 100))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.batch)
+		// Specify the maximum number of instances that are moved during one rebalancing run.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `1`
+		//  shortdesc: Maximum number of instances moved per rebalancing run
+		"cluster.rebalance.batch": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsInRange(1, 100))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.cooldown)
+		// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+		// An instance is not moved again by the rebalancer before this time has elapsed since its last move.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `6H`
+		//  shortdesc: Minimum time between two rebalancing moves of an instance
+		"cluster.rebalance.cooldown": {Type: config.String, Default: "6H", Validator: expiryValidator},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.scheduler.strategy)
+		// Specify how a cluster member is selected for new instances that are not targeted to a cluster member.
+		// Possible values are `instances` (the member with the fewest instances), `balanced` (the member with the most
+		// free CPU and memory once the instance is placed) and `binpack` (the member with the least free CPU and memory
+		// that can still host the instance).
+		//
+		// The `balanced` and `binpack` strategies only consider members with enough free capacity for the CPU, memory,
+		// root disk and GPUs requested by the instance.
+		// See {ref}`clustering-instance-placement-scheduler` for more information.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `instances`
+		//  shortdesc: Strategy used to place new instances
+		"cluster.scheduler.strategy": {Type: config.String, Default: scheduler.StrategyInstances, Validator: func(value string) error {
+			return validate.IsOneOf(scheduler.Strategies()...)(value)
+		}},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.database_backup.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable scheduled backups.
+		// Each database member (voter or stand-by) keeps its own backups of the cluster database.
+		// See {ref}`cluster-database-backups` for more information.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: empty
+		//  shortdesc: Schedule for cluster database backups
+		"cluster.database_backup.schedule": {Type: config.String, Validator: validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.database_backup.retention)
+		// Specify the number of scheduled cluster database backups that each database member keeps.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `7`
+		//  shortdesc: Number of cluster database backups to keep
+		"cluster.database_backup.retention": {Type: config.Int64, Default: "7", Validator: validate.Optional(validate.IsInRange(1, 1000))},
+
 		// lxdmeta:generate(entities=server; group=cluster; key=cluster.join_token_expiry)
 		//
 		// ---
@@ -448,6 +653,26 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Whether to enforce authentication on the metrics endpoint
 		"core.metrics_authentication": {Type: config.Bool, Default: "true"},
 
+		// lxdmeta:generate(entities=server; group=core; key=core.audit_max_size)
+		// Each cluster member records the mutating API calls it receives in a hash-chained audit log.
+		// When the audit log reaches this size, it is rotated.
+		// See {ref}`audit-log` for more information.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `100MiB`
+		//  shortdesc: Size at which the audit log is rotated
+		"core.audit_max_size": {Type: config.String, Default: "100MiB", Validator: validate.IsSize},
+
+		// lxdmeta:generate(entities=server; group=core; key=core.audit_max_files)
+		// Specify the number of rotated audit log files that each cluster member keeps.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `10`
+		//  shortdesc: Number of rotated audit log files to keep
+		"core.audit_max_files": {Type: config.Int64, Default: "10", Validator: validate.Optional(validate.IsInRange(1, 1000))},
+
 		// lxdmeta:generate(entities=server; group=core; key=core.bgp_asn)
 		//
 		// ---
@@ -551,6 +776,40 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Whether to automatically trust clients signed by the CA
 		"core.trust_ca_certificates": {Type: config.Bool, Default: "false"},
 
+		// lxdmeta:generate(entities=server; group=core; key=core.trust_ca_revocation)
+		// If a CA certificate is configured, the revocation status of client certificates that are signed by the CA is always
+		// checked against the local certificate revocation list (`ca.crl`).
+		// Set this option to additionally check the revocation status online:
+		//
+		// - `crl`: Check against the certificate revocation lists of the CRL distribution points of the client certificate.
+		// - `ocsp`: Check with the OCSP responder of the client certificate.
+		//
+		// Client certificates without a CRL distribution point or OCSP responder are then rejected, as are client
+		// certificates whose revocation status cannot be determined.
+		// See {ref}`authentication-revoke-certificates` for more information.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: How to check the revocation status of CA-signed client certificates
+		"core.trust_ca_revocation": {Validator: validate.Optional(validate.IsOneOf("crl", "ocsp"))},
+
+		// lxdmeta:generate(entities=server; group=core; key=core.certificate_expiry_warning)
+		// A warning is raised for trusted client certificates, the server certificate, and the cluster certificate that expire
+		// within this period. The certificates are checked daily. Set this option to an empty value to disable the warnings.
+		//
+		// This configuration option accepts multiple space-separated values of the form `[0-9]+(S|M|H|d|w|m|y)`,
+		// where `S` is seconds, `M` is minutes, `H` is hours, `d` is days, `w` is weeks, `m` is months, and `y` is years.
+		// For example, `1d 3H` is 1 day and 3 hours.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `30d`
+		//  shortdesc: How long before certificates expire to raise a warning
+		"core.certificate_expiry_warning": {Default: "30d", Validator: func(s string) error {
+			_, err := shared.GetExpiry(time.Now().UTC(), s)
+			return err
+		}},
+
 		// lxdmeta:generate(entities=server; group=core; key=core.auth_secret_expiry)
 		// The secret is used for various cryptographic purposes, such as cookie encryption.
 		// When a given secret is older than the configured expiry, a new secret is generated.
@@ -789,6 +1048,33 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: A claim used for mapping identity provider groups to LXD groups.
 		"oidc.groups.claim": {},
 
+		// lxdmeta:generate(entities=server; group=oidc; key=oidc.groups.mappings)
+		// Specify a YAML list of rules that map token claims to groups defined at the identity provider.
+		// The resulting groups are added to the groups from `oidc.groups.claim`, and can be mapped to LXD groups for managing access control.
+		// See {ref}`identity-provider-group-mappings` for the format of the rules.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Rules for mapping token claims to identity provider groups
+		"oidc.groups.mappings": {Validator: validate.Optional(func(value string) error {
+			_, err := lxdOIDC.ParseClaimMappings(value)
+			return err
+		})},
+
+		// lxdmeta:generate(entities=server; group=oidc; key=oidc.additional_issuers)
+		// Specify a YAML list of OpenID Connect issuers that are trusted in addition to `oidc.issuer`.
+		// Each issuer has its own client ID, and optionally a client secret, scopes, audience, and groups claim.
+		// The groups from the groups claim of an issuer are prefixed with the group prefix of that issuer.
+		// See {ref}`authentication-oidc-additional-issuers` for the format of the list.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Additional trusted OpenID Connect issuers
+		"oidc.additional_issuers": {Validator: validate.Optional(func(value string) error {
+			_, err := lxdOIDC.ParseIssuers(value)
+			return err
+		})},
+
 		// lxdmeta:generate(entities=server; group=oidc; key=oidc.session.expiry)
 		// The duration of an OIDC session.
 		//
@@ -0,0 +1,356 @@
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	dqlite "github.com/canonical/go-dqlite/v3"
+	"github.com/canonical/go-dqlite/v3/client"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// DatabaseBackupsDir is the directory, relative to the LXD var directory, holding the cluster database backups.
+const DatabaseBackupsDir = "database-backups"
+
+const databaseBackupPrefix = "global."
+
+const databaseBackupSuffix = ".tar.gz"
+
+const databaseBackupDumpFilename = "global.sql"
+
+const databaseBackupIndexFilename = "index.yaml"
+
+// maxDatabaseBackupDumpBytes is the maximum size of a database dump read from a backup.
+const maxDatabaseBackupDumpBytes = 1024 * 1024 * 1024
+
+// DatabaseBackupIndex describes a cluster database backup.
+type DatabaseBackupIndex struct {
+	CreatedAt     time.Time `yaml:"created_at"`
+	Member        string    `yaml:"member"`
+	Schema        int       `yaml:"schema"`
+	APIExtensions int       `yaml:"api_extensions"`
+}
+
+// CreateDatabaseBackup takes a consistent snapshot of the cluster database and writes it as a
+// tarball in the given directory. Returns the path to the tarball.
+func CreateDatabaseBackup(ctx context.Context, cluster *db.Cluster, dir string, member string) (string, error) {
+	var dump string
+	err := cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		dump, err = query.Dump(ctx, tx.Tx(), false)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed dumping cluster database: %w", err)
+	}
+
+	index := DatabaseBackupIndex{
+		CreatedAt:     time.Now().UTC(),
+		Member:        member,
+		Schema:        dbCluster.SchemaVersion,
+		APIExtensions: version.APIExtensionsCount(),
+	}
+
+	indexYaml, err := yaml.Marshal(index)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	// The timestamp sorts lexically and omits colons like createDatabaseBackup does.
+	tarballPath := filepath.Join(dir, databaseBackupPrefix+index.CreatedAt.Format("2006-01-02T150405.000Z")+databaseBackupSuffix)
+
+	err = writeDatabaseBackupTarball(tarballPath, map[string][]byte{
+		databaseBackupIndexFilename: indexYaml,
+		databaseBackupDumpFilename:  []byte(dump),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return tarballPath, nil
+}
+
+// writeDatabaseBackupTarball writes the given files to a new tarball at tarballPath.
+func writeDatabaseBackupTarball(tarballPath string, files map[string][]byte) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	tarball, err := os.OpenFile(tarballPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = os.Remove(tarballPath) })
+
+	gzWriter := gzip.NewWriter(tarball)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	// Write the index first so that it can be read without decompressing the dump.
+	for _, name := range []string{databaseBackupIndexFilename, databaseBackupDumpFilename} {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(files[name])),
+			ModTime: time.Now(),
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = tarWriter.Write(files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	err = gzWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	err = tarball.Close()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// ListDatabaseBackups returns the paths of the cluster database backups found in dir, oldest first.
+func ListDatabaseBackups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	backups := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !strings.HasPrefix(name, databaseBackupPrefix) || !strings.HasSuffix(name, databaseBackupSuffix) {
+			continue
+		}
+
+		backups = append(backups, filepath.Join(dir, name))
+	}
+
+	slices.Sort(backups)
+
+	return backups, nil
+}
+
+// PruneDatabaseBackups removes the oldest cluster database backups found in dir, keeping the last retention ones.
+func PruneDatabaseBackups(dir string, retention int) error {
+	backups, err := ListDatabaseBackups(dir)
+	if err != nil {
+		return err
+	}
+
+	if len(backups) <= retention {
+		return nil
+	}
+
+	for _, path := range backups[:len(backups)-retention] {
+		err := os.Remove(path)
+		if err != nil {
+			return fmt.Errorf("Failed removing cluster database backup %q: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// ReadDatabaseBackup returns the index and the database dump of the cluster database backup at tarballPath.
+func ReadDatabaseBackup(tarballPath string) (*DatabaseBackupIndex, string, error) {
+	tarball, err := os.Open(tarballPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer func() { _ = tarball.Close() }()
+
+	gzReader, err := gzip.NewReader(tarball)
+	if err != nil {
+		return nil, "", fmt.Errorf("Invalid cluster database backup %q: %w", tarballPath, err)
+	}
+
+	tarReader := tar.NewReader(gzReader)
+
+	var index *DatabaseBackupIndex
+	var dump []byte
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, "", fmt.Errorf("Invalid cluster database backup %q: %w", tarballPath, err)
+		}
+
+		switch header.Name {
+		case databaseBackupIndexFilename:
+			index = &DatabaseBackupIndex{}
+			err = yaml.NewDecoder(util.MaxBytesReader(tarReader, util.MaxYAMLFileBytes)).Decode(index)
+			if err != nil {
+				return nil, "", fmt.Errorf("Invalid %q in %q: %w", header.Name, tarballPath, err)
+			}
+
+		case databaseBackupDumpFilename:
+			if header.Size > maxDatabaseBackupDumpBytes {
+				return nil, "", fmt.Errorf("Database dump in %q is too large", tarballPath)
+			}
+
+			dump, err = io.ReadAll(tarReader)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	if index == nil || dump == nil {
+		return nil, "", fmt.Errorf("Invalid cluster database backup %q: missing %q or %q", tarballPath, databaseBackupIndexFilename, databaseBackupDumpFilename)
+	}
+
+	return index, string(dump), nil
+}
+
+// RestoreDatabase stages the cluster database backup at tarballPath to replace the content of the
+// cluster database when LXD next starts. If clustered, the dqlite raft configuration is rebuilt
+// leaving only the current member in the cluster, as done by Recover.
+func RestoreDatabase(database *db.Node, tarballPath string) error {
+	index, dump, err := ReadDatabaseBackup(tarballPath)
+	if err != nil {
+		return err
+	}
+
+	// The daemon would otherwise wait for the other cluster members in the backup to be upgraded.
+	if index.Schema != dbCluster.SchemaVersion || index.APIExtensions != version.APIExtensionsCount() {
+		return fmt.Errorf("Cluster database backup was created by a different LXD version (schema %d, API extensions %d) than this one (schema %d, API extensions %d)", index.Schema, index.APIExtensions, dbCluster.SchemaVersion, version.APIExtensionsCount())
+	}
+
+	restorePath := filepath.Join(database.Dir(), dbCluster.RestoreFilename)
+	_, err = os.Stat(restorePath)
+	if err == nil {
+		return fmt.Errorf("Found %s: a cluster database backup is already waiting to be restored", restorePath)
+	}
+
+	_, err = createDatabaseBackup(database.Dir())
+	if err != nil {
+		return fmt.Errorf("Failed creating backup: %w", err)
+	}
+
+	info, err := localRaftNode(database)
+	if err != nil {
+		return err
+	}
+
+	// Rebuild the raft configuration if clustered.
+	if info.Address != "" {
+		err = checkDatabaseBackupMember(dump, info)
+		if err != nil {
+			return err
+		}
+
+		cluster := []dqlite.NodeInfo{
+			{
+				ID:      uint64(info.ID),
+				Address: info.Address,
+				Role:    client.Voter,
+			},
+		}
+
+		err = dqlite.ReconfigureMembershipExt(database.DqliteDir(), cluster)
+		if err != nil {
+			return fmt.Errorf("Failed recovering database state: %w", err)
+		}
+
+		err = database.Transaction(context.TODO(), func(ctx context.Context, tx *db.NodeTx) error {
+			nodes := []db.RaftNode{
+				{
+					NodeInfo: client.NodeInfo{
+						ID:      info.ID,
+						Address: info.Address,
+					},
+					Name: info.Name,
+				},
+			}
+
+			return tx.ReplaceRaftNodes(nodes)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed updating database nodes: %w", err)
+		}
+	}
+
+	err = os.WriteFile(restorePath, []byte(dump), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed staging cluster database backup: %w", err)
+	}
+
+	logger.Info("Staged cluster database backup", logger.Ctx{"path": tarballPath, "created_at": index.CreatedAt})
+
+	return nil
+}
+
+// checkDatabaseBackupMember checks that the local cluster member is part of the cluster database dump.
+func checkDatabaseBackupMember(dump string, info *db.RaftNode) error {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = sqlDB.Close() }()
+
+	var count int
+	err = query.Transaction(context.TODO(), sqlDB, func(ctx context.Context, tx *sql.Tx) error {
+		err := query.Restore(ctx, tx, dump)
+		if err != nil {
+			return err
+		}
+
+		return tx.QueryRowContext(ctx, "SELECT count(*) FROM nodes WHERE address = ?", info.Address).Scan(&count)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading cluster database backup: %w", err)
+	}
+
+	if count != 1 {
+		return fmt.Errorf("Missing cluster member with address %q in cluster database backup", info.Address)
+	}
+
+	return nil
+}
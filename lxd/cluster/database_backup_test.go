@@ -0,0 +1,63 @@
+package cluster_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+)
+
+func TestCreateDatabaseBackup(t *testing.T) {
+	clusterDB, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+
+	dir := filepath.Join(t.TempDir(), cluster.DatabaseBackupsDir)
+
+	path, err := cluster.CreateDatabaseBackup(context.Background(), clusterDB, dir, "server01")
+	require.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+
+	index, dump, err := cluster.ReadDatabaseBackup(path)
+	require.NoError(t, err)
+	assert.Equal(t, "server01", index.Member)
+	assert.Equal(t, dbCluster.SchemaVersion, index.Schema)
+	assert.Contains(t, dump, "CREATE TABLE")
+	assert.Contains(t, dump, "INSERT INTO nodes VALUES(1,'none','','0.0.0.0',")
+
+	backups, err := cluster.ListDatabaseBackups(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{path}, backups)
+}
+
+func TestPruneDatabaseBackups(t *testing.T) {
+	dir := t.TempDir()
+
+	names := []string{
+		"global.2025-01-03T000000.000Z.tar.gz",
+		"global.2025-01-01T000000.000Z.tar.gz",
+		"global.2025-01-02T000000.000Z.tar.gz",
+		"unrelated.tar.gz",
+	}
+
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+
+	require.NoError(t, cluster.PruneDatabaseBackups(dir, 2))
+
+	backups, err := cluster.ListDatabaseBackups(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "global.2025-01-02T000000.000Z.tar.gz"),
+		filepath.Join(dir, "global.2025-01-03T000000.000Z.tar.gz"),
+	}, backups)
+
+	assert.FileExists(t, filepath.Join(dir, "unrelated.tar.gz"))
+}
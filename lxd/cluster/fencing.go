@@ -0,0 +1,162 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Fencer isolates an offline cluster member, so that its instances can safely be started on other members
+// without the offline member still writing to their shared storage.
+type Fencer interface {
+	// Name returns the name of the fencing method, used in logs and errors.
+	Name() string
+
+	// Fence isolates the given cluster member. It must only return nil once fencing is confirmed.
+	Fence(ctx context.Context, member db.NodeInfo) error
+}
+
+// PowerStateOn and PowerStateOff are the power states reported by a PowerController.
+const (
+	PowerStateOn  = "on"
+	PowerStateOff = "off"
+)
+
+// PowerController is an IPMI-like interface to the power of cluster members.
+type PowerController interface {
+	// PowerOff requests the given cluster member to be powered off.
+	PowerOff(ctx context.Context, member db.NodeInfo) error
+
+	// PowerStatus returns the power state of the given cluster member, either PowerStateOn or PowerStateOff.
+	PowerStatus(ctx context.Context, member db.NodeInfo) (string, error)
+}
+
+// FenceMember fences the given cluster member with each of the fencers in turn, giving up after timeout.
+// Fencing is only confirmed if all the fencers succeeded.
+func FenceMember(ctx context.Context, member db.NodeInfo, timeout time.Duration, fencers ...Fencer) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, fencer := range fencers {
+		logger.Info("Fencing cluster member", logger.Ctx{"member": member.Name, "method": fencer.Name()})
+
+		err := fencer.Fence(ctx, member)
+		if err != nil {
+			return fmt.Errorf("Failed fencing cluster member %q using %q: %w", member.Name, fencer.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// commandFencer fences cluster members by running a command.
+type commandFencer struct {
+	command string
+}
+
+// NewCommandFencer returns a Fencer running the given command with the name and address of the cluster
+// member as arguments. Fencing is confirmed when the command exits successfully.
+func NewCommandFencer(command string) Fencer {
+	return &commandFencer{command: command}
+}
+
+// Name returns the name of the fencing method.
+func (f *commandFencer) Name() string {
+	return "command"
+}
+
+// Fence runs the fencing command for the given cluster member.
+func (f *commandFencer) Fence(ctx context.Context, member db.NodeInfo) error {
+	_, err := shared.RunCommand(ctx, f.command, member.Name, member.Address)
+	return err
+}
+
+// powerFencer fences cluster members by powering them off.
+type powerFencer struct {
+	controller PowerController
+	interval   time.Duration
+}
+
+// NewPowerFencer returns a Fencer powering off cluster members using the given controller.
+// Fencing is confirmed once the controller reports the cluster member as powered off, checking every interval.
+func NewPowerFencer(controller PowerController, interval time.Duration) Fencer {
+	return &powerFencer{controller: controller, interval: interval}
+}
+
+// Name returns the name of the fencing method.
+func (f *powerFencer) Name() string {
+	return "power"
+}
+
+// Fence powers off the given cluster member and waits for it to be reported as powered off.
+func (f *powerFencer) Fence(ctx context.Context, member db.NodeInfo) error {
+	status, err := f.controller.PowerStatus(ctx, member)
+	if err != nil {
+		return fmt.Errorf("Failed getting power status: %w", err)
+	}
+
+	if status == PowerStateOff {
+		return nil
+	}
+
+	err = f.controller.PowerOff(ctx, member)
+	if err != nil {
+		return fmt.Errorf("Failed powering off: %w", err)
+	}
+
+	for {
+		status, err = f.controller.PowerStatus(ctx, member)
+		if err != nil {
+			return fmt.Errorf("Failed getting power status: %w", err)
+		}
+
+		if status == PowerStateOff {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.New("Timed out waiting for the cluster member to be powered off")
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+// commandPowerController is a local stand-in for an IPMI-like power controller.
+type commandPowerController struct {
+	command string
+}
+
+// NewCommandPowerController returns a PowerController running the given command with an action ("off" or
+// "status") and the name and address of the cluster member as arguments. For the "status" action, the command
+// must print either "on" or "off".
+func NewCommandPowerController(command string) PowerController {
+	return &commandPowerController{command: command}
+}
+
+// PowerOff runs the power command with the "off" action.
+func (c *commandPowerController) PowerOff(ctx context.Context, member db.NodeInfo) error {
+	_, err := shared.RunCommand(ctx, c.command, "off", member.Name, member.Address)
+	return err
+}
+
+// PowerStatus runs the power command with the "status" action.
+func (c *commandPowerController) PowerStatus(ctx context.Context, member db.NodeInfo) (string, error) {
+	out, err := shared.RunCommand(ctx, c.command, "status", member.Name, member.Address)
+	if err != nil {
+		return "", err
+	}
+
+	status := strings.TrimSpace(out)
+	if status != PowerStateOn && status != PowerStateOff {
+		return "", fmt.Errorf("Invalid power status %q", status)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,100 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+)
+
+// fakePowerController is a PowerController reporting a cluster member as powered off after a number of
+// status checks following the power off request.
+type fakePowerController struct {
+	poweredOff  bool
+	checksToOff int
+	powerOffErr error
+}
+
+func (c *fakePowerController) PowerOff(ctx context.Context, member db.NodeInfo) error {
+	if c.powerOffErr != nil {
+		return c.powerOffErr
+	}
+
+	c.poweredOff = true
+	return nil
+}
+
+func (c *fakePowerController) PowerStatus(ctx context.Context, member db.NodeInfo) (string, error) {
+	if !c.poweredOff {
+		return cluster.PowerStateOn, nil
+	}
+
+	if c.checksToOff > 0 {
+		c.checksToOff--
+		return cluster.PowerStateOn, nil
+	}
+
+	return cluster.PowerStateOff, nil
+}
+
+func TestFenceMember_Power(t *testing.T) {
+	member := db.NodeInfo{Name: "node2", Address: "10.0.0.2:8443"}
+
+	// Powered off after a few checks.
+	controller := &fakePowerController{checksToOff: 2}
+	fencer := cluster.NewPowerFencer(controller, time.Millisecond)
+	require.NoError(t, cluster.FenceMember(context.Background(), member, time.Second, fencer))
+	assert.True(t, controller.poweredOff)
+
+	// Never powered off.
+	controller = &fakePowerController{checksToOff: 1 << 30}
+	fencer = cluster.NewPowerFencer(controller, time.Millisecond)
+	err := cluster.FenceMember(context.Background(), member, 50*time.Millisecond, fencer)
+	assert.ErrorContains(t, err, `Failed fencing cluster member "node2" using "power": Timed out waiting for the cluster member to be powered off`)
+
+	// Power off failure.
+	controller = &fakePowerController{powerOffErr: errors.New("BMC unreachable")}
+	fencer = cluster.NewPowerFencer(controller, time.Millisecond)
+	err = cluster.FenceMember(context.Background(), member, time.Second, fencer)
+	assert.ErrorContains(t, err, "Failed powering off: BMC unreachable")
+}
+
+func TestFenceMember_Command(t *testing.T) {
+	dir := t.TempDir()
+	member := db.NodeInfo{Name: "node2", Address: "10.0.0.2:8443"}
+
+	// The command receives the name and address of the cluster member.
+	output := filepath.Join(dir, "fenced")
+	command := filepath.Join(dir, "fence")
+	require.NoError(t, os.WriteFile(command, []byte("#!/bin/sh\necho \"$@\" > "+output+"\n"), 0700))
+
+	err := cluster.FenceMember(context.Background(), member, time.Second, cluster.NewCommandFencer(command))
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "node2 10.0.0.2:8443\n", string(content))
+
+	// Failing command.
+	failing := filepath.Join(dir, "fail")
+	require.NoError(t, os.WriteFile(failing, []byte("#!/bin/sh\nexit 1\n"), 0700))
+
+	err = cluster.FenceMember(context.Background(), member, time.Second, cluster.NewCommandFencer(command), cluster.NewCommandFencer(failing))
+	assert.ErrorContains(t, err, `Failed fencing cluster member "node2" using "command"`)
+
+	// Power command stand-in.
+	power := filepath.Join(dir, "power")
+	require.NoError(t, os.WriteFile(power, []byte("#!/bin/sh\ncase \"$1\" in\n  off) touch "+output+".off ;;\n  status) [ -e "+output+".off ] && echo off || echo on ;;\nesac\n"), 0700))
+
+	err = cluster.FenceMember(context.Background(), member, time.Second, cluster.NewPowerFencer(cluster.NewCommandPowerController(power), time.Millisecond))
+	require.NoError(t, err)
+	assert.FileExists(t, output+".off")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// clusterFencingPowerInterval is how often the power status of a cluster member is checked while fencing it.
+const clusterFencingPowerInterval = 5 * time.Second
+
+// cephRBDFencer fences the Ceph RBD volumes of the instances located on a cluster member.
+type cephRBDFencer struct {
+	s *state.State
+}
+
+// Name returns the name of the fencing method.
+func (f *cephRBDFencer) Name() string {
+	return "ceph_rbd"
+}
+
+// Fence blocklists the Ceph clients using the RBD volumes of the instances located on the cluster member.
+func (f *cephRBDFencer) Fence(ctx context.Context, member db.NodeInfo) error {
+	var dbInstances []dbCluster.Instance
+	err := f.s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		dbInstances, err = dbCluster.GetInstances(ctx, tx.Tx(), dbCluster.InstanceFilter{Node: &member.Name})
+		if err != nil {
+			return fmt.Errorf("Failed getting instances: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, dbInst := range dbInstances {
+		inst, err := instance.LoadByProjectAndName(f.s, dbInst.Project, dbInst.Name)
+		if err != nil {
+			return fmt.Errorf("Failed loading instance: %w", err)
+		}
+
+		poolName, err := inst.StoragePool()
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				continue
+			}
+
+			return err
+		}
+
+		pool, err := storagePools.LoadByName(f.s, poolName)
+		if err != nil {
+			return fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
+		}
+
+		if pool.Driver().Info().Name != "ceph" {
+			continue
+		}
+
+		volType, err := storagePools.InstanceTypeToVolumeType(inst.Type())
+		if err != nil {
+			return err
+		}
+
+		// Virtual machines also have a filesystem volume next to their block volume.
+		contentTypes := []storageDrivers.ContentType{storageDrivers.ContentTypeFS}
+		if inst.Type() == instancetype.VM {
+			contentTypes = append(contentTypes, storageDrivers.ContentTypeBlock)
+		}
+
+		poolConfig := pool.Driver().Config()
+		for _, contentType := range contentTypes {
+			vol := pool.GetVolume(volType, contentType, project.Instance(inst.Project().Name, inst.Name()), nil)
+			imageName, _ := storageDrivers.CephGetRBDImageName(vol, false)
+
+			addresses, err := storageDrivers.CephRBDFenceImage(ctx, poolConfig["ceph.cluster_name"], poolConfig["ceph.user.name"], poolConfig["ceph.osd.pool_name"], imageName)
+			if err != nil {
+				return err
+			}
+
+			if len(addresses) > 0 {
+				logger.Info("Fenced Ceph RBD volume", logger.Ctx{"member": member.Name, "pool": poolName, "image": imageName, "clients": addresses})
+			}
+		}
+	}
+
+	return nil
+}
+
+// clusterFencers returns the configured fencers for offline cluster members, along with the fencing timeout.
+func clusterFencers(s *state.State) ([]cluster.Fencer, time.Duration) {
+	command, powerCommand, cephRBD, timeout := s.GlobalConfig.ClusterFencing()
+
+	fencers := []cluster.Fencer{}
+	if powerCommand != "" {
+		fencers = append(fencers, cluster.NewPowerFencer(cluster.NewCommandPowerController(powerCommand), clusterFencingPowerInterval))
+	}
+
+	if command != "" {
+		fencers = append(fencers, cluster.NewCommandFencer(command))
+	}
+
+	if cephRBD {
+		fencers = append(fencers, &cephRBDFencer{s: s})
+	}
+
+	return fencers, timeout
+}
@@ -31,6 +31,7 @@ import (
 
 	"github.com/canonical/lxd/lxd/acme"
 	"github.com/canonical/lxd/lxd/apparmor"
+	"github.com/canonical/lxd/lxd/audit"
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/auth/bearer"
 	authDrivers "github.com/canonical/lxd/lxd/auth/drivers"
@@ -64,6 +65,7 @@ import (
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/request/security"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/revocation"
 	"github.com/canonical/lxd/lxd/rsync"
 	"github.com/canonical/lxd/lxd/seccomp"
 	"github.com/canonical/lxd/lxd/state"
@@ -121,6 +123,9 @@ type Daemon struct {
 
 	oidcVerifier atomic.Pointer[oidc.Verifier]
 
+	// Checks the revocation status of CA signed client certificates (core.trust_ca_revocation).
+	revocationChecker *revocation.Checker
+
 	// Stores last heartbeat node information to detect node changes.
 	lastNodeList *cluster.APIHeartbeat
 
@@ -157,6 +162,9 @@ type Daemon struct {
 
 	lokiClient *loki.Client
 
+	// Tamper-evident log of mutating API calls.
+	auditLog *audit.Log
+
 	// HTTP-01 challenge provider for ACME
 	http01Provider acme.HTTP01Provider
 
@@ -203,6 +211,9 @@ func newDaemon(config *DaemonConfig, os *sys.OS) *Daemon {
 	}
 
 	d.serverCert = func() *shared.CertInfo { return d.serverCertInt }
+	d.revocationChecker = revocation.NewChecker(func() (*http.Client, error) {
+		return util.HTTPClient("", d.proxy)
+	})
 
 	return d
 }
@@ -251,6 +262,9 @@ type APIEndpointAction struct {
 	AllowUntrusted  bool
 	AllProjectsMode allProjectsMode
 	ContentTypes    []string // Client content types to allow.
+
+	// ClusterLinkViews allows the cluster-link and all-links query parameters to return results from linked clusters.
+	ClusterLinkViews bool
 }
 
 // allProjectsMode dictates how the all-projects query parameter is handled if present.
@@ -434,6 +448,23 @@ func extractEntitlementsFromQuery(r *http.Request, entityType entity.Type, allow
 	return validEntitlements, nil
 }
 
+// checkCARevocation checks the revocation status of a CA signed client certificate online, using the mode set in
+// core.trust_ca_revocation. It returns true if the certificate may be trusted. The check fails closed, so the
+// certificate is not trusted if its revocation status cannot be determined.
+func (d *Daemon) checkCARevocation(ctx context.Context, mode string, cert x509.Certificate) bool {
+	if mode == "" {
+		return true
+	}
+
+	err := d.revocationChecker.Check(ctx, mode, &cert, d.endpoints.NetworkCert().CA())
+	if err != nil {
+		logger.Warn("Rejecting CA signed client certificate", logger.Ctx{"fingerprint": shared.CertFingerprint(&cert), "mode": mode, "err": err})
+		return false
+	}
+
+	return true
+}
+
 // Authenticate validates an incoming http Request
 // It will check over what protocol it came, what type of request it is and
 // will validate the TLS certificate or OIDC token.
@@ -483,6 +514,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request, oidcVerifi
 
 	d.globalConfigMu.Lock()
 	trustCACertificates := d.globalConfig.TrustCACertificates()
+	trustCARevocation := d.globalConfig.TrustCARevocation()
 	clusterUUID := d.globalConfig.ClusterUUID()
 	d.globalConfigMu.Unlock()
 
@@ -522,7 +554,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request, oidcVerifi
 				// This does not apply to server certificates.
 				if protocol != request.ProtocolCluster && d.endpoints.NetworkCert().CA() != nil {
 					trusted, _, _ = util.CheckCASignature(peerCertificates[matchedFingerprint], d.endpoints.NetworkCert())
-					if !trusted {
+					if !trusted || !d.checkCARevocation(r.Context(), trustCARevocation, peerCertificates[matchedFingerprint]) {
 						return nil, nil
 					}
 				}
@@ -572,7 +604,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request, oidcVerifi
 		if d.endpoints.NetworkCert().CA() != nil && trustCACertificates {
 			for f, cert := range peerCertificates {
 				trusted, _, _ := util.CheckCASignature(cert, d.endpoints.NetworkCert())
-				if trusted {
+				if trusted && d.checkCARevocation(r.Context(), trustCARevocation, cert) {
 					return &request.RequestorArgs{
 						Trusted:  true,
 						Username: f,
@@ -583,6 +615,19 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request, oidcVerifi
 		}
 	}
 
+	// Check if the caller has a SCIM bearer token. These are only valid for the SCIM API.
+	if strings.HasPrefix(r.URL.Path, "/scim/") {
+		isSCIMRequest, token, subject := bearer.IsSCIMRequest(r, clusterUUID)
+		if isSCIMRequest {
+			scimRequestor, err := bearer.Authenticate(r.Context(), subject, token, auth.TokenLocationAuthorizationBearer, d.identityCache, d.events.SendSecurity)
+			if err != nil {
+				return nil, fmt.Errorf("Failed verifying SCIM bearer token: %w", err)
+			}
+
+			return scimRequestor, nil
+		}
+	}
+
 	// Check if the caller has a bearer token.
 	isBearerRequest, tokenLocation, token, subject := bearer.IsAPIRequest(r, clusterUUID)
 	if isBearerRequest {
@@ -862,6 +907,15 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, version string, c APIEndpoint
 			return
 		}
 
+		// Record mutating API calls in the audit log once they have been handled, including those that are denied.
+		if version != "internal" && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			auditWriter := &audit.ResponseWriter{ResponseWriter: w}
+			bodyDigest := audit.NewBodyDigest(r.Body)
+			w = auditWriter
+			r.Body = bodyDigest
+			defer d.recordAPICall(r, auditWriter, bodyDigest)
+		}
+
 		// Authentication
 		oidcVerifier := d.oidcVerifier.Load()
 		requestor, err := d.Authenticate(w, r, oidcVerifier, endpointAction.AllowUntrusted)
@@ -1063,6 +1117,16 @@ func handleRequest(d *Daemon, r *http.Request, action APIEndpointAction, project
 		}
 	}
 
+	// Requests for linked clusters are sent with the identity of the cluster links. The caller must pass the checks
+	// above for the local cluster, so that a cluster link never grants more than the caller could see locally.
+	if clusterLinkViewRequested(r) {
+		if !action.ClusterLinkViews {
+			return response.BadRequest(errors.New("Cluster link queries are not supported"))
+		}
+
+		return clusterLinkViewGet(d, r, action, projectSpecific)
+	}
+
 	return action.Handler(d, r)
 }
 
@@ -1794,6 +1858,7 @@ func (d *Daemon) init() error {
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := d.globalConfig.LokiServer()
+	auditMaxSize, auditMaxFiles := d.globalConfig.AuditLimits()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 
 	d.endpoints.NetworkUpdateTrustedProxy(d.globalConfig.HTTPSTrustedProxy())
@@ -1807,6 +1872,23 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Open the audit log. This must succeed, as mutating API calls must not be handled without being recorded.
+	// The records are hashed with a key from the cluster database, so that the hash chain cannot be recomputed by
+	// someone who can only write to the log.
+	var auditKey dbCluster.AuthSecretValue
+	err = d.db.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		auditKey, err = dbCluster.GetOrCreateAuditKey(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting audit log key: %w", err)
+	}
+
+	d.auditLog, err = audit.Open(filepath.Join(d.os.VarDir, "audit"), auditKey, auditMaxSize, auditMaxFiles)
+	if err != nil {
+		return err
+	}
+
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
 		if err != nil {
@@ -2026,11 +2108,29 @@ func (d *Daemon) init() error {
 		// Auto-renew server certificate (daily)
 		d.tasks.Add(autoRenewCertificateTask(d))
 
+		// Warn about expiring certificates (daily)
+		d.tasks.Add(certificateExpiryCheckTask(d.State))
+
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d.State))
 
 		// Run scheduled replicators (minutely check of configurable cron expression)
 		d.tasks.Add(runScheduledReplicatorsTask(d.State))
+
+		// Run continuous replicators (every 10 seconds)
+		d.tasks.Add(runContinuousReplicatorsTask(d.State))
+
+		// Check replicator recovery point objectives (minutely)
+		d.tasks.Add(replicatorRPOCheckTask(d.State))
+
+		// Check storage pool usage thresholds (every 5 minutes)
+		d.tasks.Add(storagePoolUsageCheckTask(d.State))
+
+		// Purge expired trash entries (hourly)
+		d.tasks.Add(pruneExpiredTrashTask(d.State))
+
+		// Run scheduled storage pool scrubs (minutely check of configurable cron expression)
+		d.tasks.Add(storagePoolScrubTask(d.State))
 	}
 
 	// Load Ubuntu Pro configuration before starting any instances.
@@ -2102,6 +2202,15 @@ func (d *Daemon) requestorHook(ctx context.Context, authenticationMethod string,
 			res.AuthGroups = append(res.AuthGroups, g.Name)
 		}
 
+		if id.AuthMethod == api.AuthenticationMethodBearer {
+			metadata, err := id.BearerMetadata()
+			if err != nil {
+				return fmt.Errorf("Failed reading bearer identity metadata: %w", err)
+			}
+
+			res.TokenPermissions = metadata.TokenPermissions
+		}
+
 		if idType.Name() == api.IdentityTypeOIDCClient {
 			metadata, err := id.OIDCMetadata()
 			if err != nil {
@@ -2155,6 +2264,12 @@ func (d *Daemon) startClusterTasks() {
 	// Refresh cluster link volatile addresses (daily).
 	d.clusterTasks.Add(autoRefreshClusterLinkVolatileAddressesTask(d.State))
 
+	// Rebalance instances across cluster members (minutely check of the configured interval)
+	d.clusterTasks.Add(clusterInstanceRebalanceTask(d.State))
+
+	// Back up the cluster database (minutely check of configurable cron expression)
+	d.clusterTasks.Add(clusterDatabaseBackupTask(d.State))
+
 	// Start all background tasks
 	d.clusterTasks.Start(d.shutdownCtx)
 }
@@ -2352,6 +2467,10 @@ func (d *Daemon) Stop(ctx context.Context, sig os.Signal) error {
 		trackError(d.endpoints.Down(), "Shutdown endpoints")
 	}
 
+	if d.auditLog != nil {
+		trackError(d.auditLog.Close(), "Close audit log")
+	}
+
 	if shouldUnmount {
 		logger.Info("Unmounting temporary filesystems")
 
@@ -2473,6 +2592,9 @@ func initializeOIDCVerifier(d *Daemon) {
 			return nil
 		}
 
+		additionalIssuers := d.globalConfig.OIDCAdditionalIssuers()
+		claimMappings := d.globalConfig.OIDCClaimMappings()
+
 		d.globalConfigMu.Unlock()
 
 		// Proxy set up (for LXD to reach the IdP).
@@ -2490,7 +2612,7 @@ func initializeOIDCVerifier(d *Daemon) {
 		sessionHandler := dbOIDC.NewSessionHandler(d.db.Cluster, d.events, expiryFunc)
 
 		var err error
-		verifier, err = oidc.NewVerifier(d.shutdownCtx, issuer, clientID, clientSecret, scopes, audience, groupsClaim, deviceClientID, d.globalConfig.ClusterUUID(), d.endpoints.NetworkAddress(), d.getCoreAuthSecrets, httpClientFunc, sessionHandler)
+		verifier, err = oidc.NewVerifier(d.shutdownCtx, issuer, clientID, clientSecret, scopes, audience, groupsClaim, deviceClientID, additionalIssuers, claimMappings, d.globalConfig.ClusterUUID(), d.endpoints.NetworkAddress(), d.getCoreAuthSecrets, httpClientFunc, sessionHandler)
 		if err != nil {
 			return err
 		}
@@ -79,35 +79,11 @@ func (g *AuthGroupsRow) ToAPI(ctx context.Context, tx *sql.Tx, canViewIdentity a
 		Description: g.Description,
 	}
 
-	permissions, err := GetPermissionsByAuthGroupID(ctx, tx, g.ID)
+	apiPermissions, err := GetAPIPermissionsByAuthGroupID(ctx, tx, g.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	permissions, entityURLs, err := GetPermissionEntityURLs(ctx, tx, permissions)
-	if err != nil {
-		return nil, err
-	}
-
-	apiPermissions := make([]api.Permission, 0, len(permissions))
-	for _, p := range permissions {
-		entityURLs, ok := entityURLs[entity.Type(p.EntityType)]
-		if !ok {
-			return nil, fmt.Errorf("Entity URLs missing for permissions with entity type %q", p.EntityType)
-		}
-
-		u, ok := entityURLs[p.EntityID]
-		if !ok {
-			return nil, fmt.Errorf("Entity URL missing for permission with entity type %q and entity ID %d", p.EntityType, p.EntityID)
-		}
-
-		apiPermissions = append(apiPermissions, api.Permission{
-			EntityType:      string(p.EntityType),
-			EntityReference: u.String(),
-			Entitlement:     string(p.Entitlement),
-		})
-	}
-
 	group.Permissions = apiPermissions
 
 	identities, err := GetIdentitiesByAuthGroupID(ctx, tx, g.ID)
@@ -137,6 +113,40 @@ func (g *AuthGroupsRow) ToAPI(ctx context.Context, tx *sql.Tx, canViewIdentity a
 	return group, nil
 }
 
+// GetAPIPermissionsByAuthGroupID returns the permissions of the group with the given ID as [api.Permission].
+func GetAPIPermissionsByAuthGroupID(ctx context.Context, tx *sql.Tx, groupID int64) ([]api.Permission, error) {
+	permissions, err := GetPermissionsByAuthGroupID(ctx, tx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, entityURLs, err := GetPermissionEntityURLs(ctx, tx, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	apiPermissions := make([]api.Permission, 0, len(permissions))
+	for _, p := range permissions {
+		entityURLs, ok := entityURLs[entity.Type(p.EntityType)]
+		if !ok {
+			return nil, fmt.Errorf("Entity URLs missing for permissions with entity type %q", p.EntityType)
+		}
+
+		u, ok := entityURLs[p.EntityID]
+		if !ok {
+			return nil, fmt.Errorf("Entity URL missing for permission with entity type %q and entity ID %d", p.EntityType, p.EntityID)
+		}
+
+		apiPermissions = append(apiPermissions, api.Permission{
+			EntityType:      string(p.EntityType),
+			EntityReference: u.String(),
+			Entitlement:     string(p.Entitlement),
+		})
+	}
+
+	return apiPermissions, nil
+}
+
 // GetIdentitiesByAuthGroupID returns the identities that are members of the group with the given ID.
 func GetIdentitiesByAuthGroupID(ctx context.Context, tx *sql.Tx, groupID int64) ([]IdentitiesRow, error) {
 	stmt := `
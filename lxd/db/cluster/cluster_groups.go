@@ -48,12 +48,27 @@ type ClusterGroupFilter struct {
 	Name *string
 }
 
+// ClusterGroupsConfigStore returns a [query.EntityConfigStore] for cluster groups.
+func ClusterGroupsConfigStore() *query.EntityConfigStore {
+	return &query.EntityConfigStore{
+		EntityTable:               "cluster_groups",
+		ConfigTable:               "cluster_groups_config",
+		ConfigTableEntityIDColumn: "cluster_group_id",
+	}
+}
+
 // ToAPI returns a LXD API entry.
 func (c *ClusterGroup) ToAPI(ctx context.Context, tx *sql.Tx) (*api.ClusterGroup, error) {
+	config, err := ClusterGroupsConfigStore().GetByEntityID(ctx, tx, int64(c.ID))
+	if err != nil {
+		return nil, err
+	}
+
 	result := api.ClusterGroup{
 		Name:        c.Name,
 		Description: c.Description,
 		Members:     c.Nodes,
+		Config:      config,
 	}
 
 	return &result, nil
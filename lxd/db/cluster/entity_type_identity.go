@@ -2,6 +2,7 @@ package cluster
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,7 +19,7 @@ type entityTypeIdentity struct {
 // identityTypes returns the list of identity type codes that are considered fine-grained.
 func (e entityTypeIdentity) identityTypes() (types []int64) {
 	for _, t := range identity.Types() {
-		if t.IsFineGrained() || t.Name() == api.IdentityTypeBearerTokenInitialUI {
+		if t.IsFineGrained() || slices.Contains([]string{api.IdentityTypeBearerTokenInitialUI, api.IdentityTypeBearerTokenSCIM}, t.Name()) {
 			types = append(types, t.Code())
 		}
 	}
@@ -1032,3 +1032,170 @@ func (r ReplicatorRow) CreateStmt() string {
 func (r ReplicatorRow) UpdateStmt() string {
 	return "UPDATE replicators SET name = ?, project_id = ?, description = ?, last_run_date = ?, last_run_status = ? "
 }
+
+// TableName returns the table name for [StorageVolumeChecksRow] entities.
+func (s StorageVolumeChecksRow) TableName() string {
+	return "storage_volumes_checks"
+}
+
+// SelectColumns returns a slice of column names for [StorageVolumeChecksRow] entities.
+func (s StorageVolumeChecksRow) SelectColumns() []string {
+	return []string{
+		"storage_volumes_checks.id",
+		"storage_volumes_checks.storage_volume_id",
+		"storage_volumes_checks.status",
+		"storage_volumes_checks.message",
+		"storage_volumes_checks.date",
+	}
+}
+
+// Joins returns a slice of join expressions for [StorageVolumeChecksRow].
+func (s StorageVolumeChecksRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [StorageVolumeChecksRow].
+// This returns references to struct fields in definition order.
+func (s *StorageVolumeChecksRow) ScanArgs() []any {
+	return []any{&s.ID, &s.StorageVolumeID, &s.Status, &s.Message, &s.Date}
+}
+
+// CreateValues returns a list of values from [StorageVolumeChecksRow] entities matching the bind arguments in [CreateStmt].
+func (s StorageVolumeChecksRow) CreateValues() []any {
+	return []any{s.StorageVolumeID, s.Status, s.Message, s.Date}
+}
+
+// UpdateValues returns a list of values from [StorageVolumeChecksRow] entities matching the columns in [UpdateStmt].
+func (s StorageVolumeChecksRow) UpdateValues() []any {
+	return []any{s.StorageVolumeID, s.Status, s.Message, s.Date}
+}
+
+// PKColumns returns the column names for the primary key of a [StorageVolumeChecksRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (s StorageVolumeChecksRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [StorageVolumeChecksRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (s StorageVolumeChecksRow) PKValues() []any {
+	return []any{s.ID}
+}
+
+// CreateStmt returns a query that creates a [StorageVolumeChecksRow] entity.
+func (s StorageVolumeChecksRow) CreateStmt() string {
+	return "INSERT INTO storage_volumes_checks (storage_volume_id, status, message, date) VALUES (?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [StorageVolumeChecksRow] by primary key.
+func (s StorageVolumeChecksRow) UpdateStmt() string {
+	return "UPDATE storage_volumes_checks SET storage_volume_id = ?, status = ?, message = ?, date = ? "
+}
+
+// TableName returns the table name for [TrashEntriesRow] entities.
+func (t TrashEntriesRow) TableName() string {
+	return "trash_entries"
+}
+
+// SelectColumns returns a slice of column names for [TrashEntriesRow] entities.
+func (t TrashEntriesRow) SelectColumns() []string {
+	return []string{
+		"trash_entries.id",
+		"trash_entries.uuid",
+		"trash_entries.project_id",
+		"trash_entries.storage_pool_id",
+		"trash_entries.node_id",
+		"trash_entries.type",
+		"trash_entries.name",
+		"trash_entries.backup_config",
+		"trash_entries.deletion_date",
+		"trash_entries.expiry_date",
+	}
+}
+
+// Joins returns a slice of join expressions for [TrashEntriesRow].
+func (t TrashEntriesRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [TrashEntriesRow].
+// This returns references to struct fields in definition order.
+func (t *TrashEntriesRow) ScanArgs() []any {
+	return []any{&t.ID, &t.UUID, &t.ProjectID, &t.StoragePoolID, &t.NodeID, &t.Type, &t.Name, &t.BackupConfig, &t.DeletionDate, &t.ExpiryDate}
+}
+
+// CreateValues returns a list of values from [TrashEntriesRow] entities matching the bind arguments in [CreateStmt].
+func (t TrashEntriesRow) CreateValues() []any {
+	return []any{t.UUID, t.ProjectID, t.StoragePoolID, t.NodeID, t.Type, t.Name, t.BackupConfig, t.DeletionDate, t.ExpiryDate}
+}
+
+// UpdateValues returns a list of values from [TrashEntriesRow] entities matching the columns in [UpdateStmt].
+func (t TrashEntriesRow) UpdateValues() []any {
+	return []any{t.UUID, t.ProjectID, t.StoragePoolID, t.NodeID, t.Type, t.Name, t.BackupConfig, t.DeletionDate, t.ExpiryDate}
+}
+
+// PKColumns returns the column names for the primary key of a [TrashEntriesRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (t TrashEntriesRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [TrashEntriesRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (t TrashEntriesRow) PKValues() []any {
+	return []any{t.ID}
+}
+
+// CreateStmt returns a query that creates a [TrashEntriesRow] entity.
+func (t TrashEntriesRow) CreateStmt() string {
+	return "INSERT INTO trash_entries (uuid, project_id, storage_pool_id, node_id, type, name, backup_config, deletion_date, expiry_date) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [TrashEntriesRow] by primary key.
+func (t TrashEntriesRow) UpdateStmt() string {
+	return "UPDATE trash_entries SET uuid = ?, project_id = ?, storage_pool_id = ?, node_id = ?, type = ?, name = ?, backup_config = ?, deletion_date = ?, expiry_date = ? "
+}
+
+// TableName returns the table name for [TrashEntry] entities.
+func (t TrashEntry) TableName() string {
+	return "trash_entries"
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (t TrashEntry) APIName() string {
+	return t.Row.APIName()
+}
+
+// SelectColumns returns a slice of column names for [TrashEntry] entities.
+func (t TrashEntry) SelectColumns() []string {
+	return []string{
+		"trash_entries.id",
+		"trash_entries.uuid",
+		"trash_entries.project_id",
+		"trash_entries.storage_pool_id",
+		"trash_entries.node_id",
+		"trash_entries.type",
+		"trash_entries.name",
+		"trash_entries.backup_config",
+		"trash_entries.deletion_date",
+		"trash_entries.expiry_date",
+		"projects.name",
+		"storage_pools.name",
+		"coalesce(nodes.name, '')",
+	}
+}
+
+// Joins returns a slice of join expressions for [TrashEntry].
+func (t TrashEntry) Joins() []string {
+	return []string{
+		"JOIN projects ON trash_entries.project_id = projects.id",
+		"JOIN storage_pools ON trash_entries.storage_pool_id = storage_pools.id",
+		"LEFT JOIN nodes ON trash_entries.node_id = nodes.id",
+	}
+}
+
+// ScanArgs implements [query.ScanArger] for [TrashEntry].
+// This returns references to struct fields in definition order.
+func (t *TrashEntry) ScanArgs() []any {
+	return []any{&t.Row.ID, &t.Row.UUID, &t.Row.ProjectID, &t.Row.StoragePoolID, &t.Row.NodeID, &t.Row.Type, &t.Row.Name, &t.Row.BackupConfig, &t.Row.DeletionDate, &t.Row.ExpiryDate, &t.ProjectName, &t.StoragePoolName, &t.NodeName}
+}
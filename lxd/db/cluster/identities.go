@@ -210,11 +210,26 @@ func (i IdentitiesRow) ToCertificate(idToCert map[int64][]string) (*CertificateL
 type OIDCMetadata struct {
 	Subject                string   `json:"subject"`
 	IdentityProviderGroups []string `json:"identity_provider_groups"`
+
+	// Issuer is the issuer of the identity if it is not the issuer set in "oidc.issuer" (see "oidc.additional_issuers").
+	// Identities that are provisioned via SCIM belong to the issuer set in "oidc.issuer", and can only be logged in to
+	// via that issuer.
+	Issuer string `json:"issuer,omitempty"`
+
+	// SCIM is true if the identity is provisioned via SCIM. The identity provider groups of the identity are then
+	// managed via SCIM, rather than extracted from the groups claim on login.
+	SCIM bool `json:"scim,omitempty"`
+
+	// ExternalID is the identifier of the identity in the identity provider, as set via SCIM.
+	ExternalID string `json:"external_id,omitempty"`
+
+	// Disabled is true if the identity was deactivated via SCIM. Disabled identities cannot log in.
+	Disabled bool `json:"disabled,omitempty"`
 }
 
 // Equals returns true if the given [OIDCMetadata] is equal to the receiver.
 func (o OIDCMetadata) Equals(m OIDCMetadata) bool {
-	if o.Subject != m.Subject {
+	if o.Subject != m.Subject || o.Issuer != m.Issuer || o.SCIM != m.SCIM || o.ExternalID != m.ExternalID || o.Disabled != m.Disabled {
 		return false
 	}
 
@@ -269,6 +284,10 @@ type BearerMetadata struct {
 	// TokenExpiry is the expiry of the issued token for the identity.
 	// It is nil when no token has been issued, or when the token has been revoked.
 	TokenExpiry *time.Time `json:"token_expiry,omitempty"`
+
+	// TokenPermissions are the permissions that the issued token for the identity is narrowed to.
+	// It is empty when the token is not narrowed, when no token has been issued, or when the token has been revoked.
+	TokenPermissions []api.Permission `json:"token_permissions,omitempty"`
 }
 
 // BearerMetadata returns the identity metadata as [BearerMetadata]. The [AuthMethod] of the
@@ -326,6 +345,41 @@ WHERE id = ? AND auth_method = ?`
 	return nil
 }
 
+// SetBearerIdentityTokenPermissions records the permissions that the issued token for a bearer identity is narrowed to.
+func SetBearerIdentityTokenPermissions(ctx context.Context, tx *sql.Tx, identityID int64, permissions []api.Permission) error {
+	// Permissions are stored as JSON null when the token is not narrowed, which unmarshals back to an empty list.
+	value := "null"
+	if len(permissions) > 0 {
+		b, err := json.Marshal(permissions)
+		if err != nil {
+			return fmt.Errorf("Failed marshaling bearer identity token permissions: %w", err)
+		}
+
+		value = string(b)
+	}
+
+	// Update token_permissions in place so any other metadata fields are preserved.
+	q := `
+UPDATE identities
+SET metadata = json_set(CASE WHEN metadata = '' THEN '{}' ELSE metadata END, '$.token_permissions', json(?))
+WHERE id = ? AND auth_method = ?`
+	res, err := tx.ExecContext(ctx, q, value, identityID, AuthMethod(api.AuthenticationMethodBearer))
+	if err != nil {
+		return fmt.Errorf("Failed setting bearer identity token permissions: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed checking bearer identity token permissions update: %w", err)
+	}
+
+	if rowsAffected != 1 {
+		return fmt.Errorf("Failed setting bearer identity token permissions: Expected to update 1 row, updated %d", rowsAffected)
+	}
+
+	return nil
+}
+
 // ToAPI converts an [IdentitiesRow] to an [api.Identity], executing database queries as necessary.
 func (i *IdentitiesRow) ToAPI(idToGroups map[int64][]string, idToCertificates map[int64][]string) (*api.Identity, error) {
 	if idToGroups == nil {
@@ -34,6 +34,11 @@ func GetIdentityProviderGroup(ctx context.Context, tx *sql.Tx, name string) (*Id
 	return query.SelectOne[IdentityProviderGroupsRow](ctx, tx, "WHERE name = ?", name)
 }
 
+// GetIdentityProviderGroupByID returns the identity provider group with the given ID.
+func GetIdentityProviderGroupByID(ctx context.Context, tx *sql.Tx, id int64) (*IdentityProviderGroupsRow, error) {
+	return query.SelectOne[IdentityProviderGroupsRow](ctx, tx, "WHERE id = ?", id)
+}
+
 // CreateIdentityProviderGroup adds a new identity provider group to the database.
 func CreateIdentityProviderGroup(ctx context.Context, tx *sql.Tx, object IdentityProviderGroupsRow) (int64, error) {
 	return query.Create(ctx, tx, object)
@@ -193,3 +193,13 @@ func DeleteOIDCSessionByUUID(ctx context.Context, tx *sql.Tx, sessionID uuid.UUI
 
 	return nil
 }
+
+// DeleteOIDCSessionsByIdentityID deletes all sessions of the identity with the given ID.
+func DeleteOIDCSessionsByIdentityID(ctx context.Context, tx *sql.Tx, identityID int64) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM oidc_sessions WHERE identity_id = ?`, identityID)
+	if err != nil {
+		return fmt.Errorf("Failed deleting sessions of identity: %w", err)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync/atomic"
@@ -54,6 +55,10 @@ func Open(name string, store driver.NodeStore, options ...driver.Option) (*sql.D
 	return db, nil
 }
 
+// RestoreFilename is the name of the file, in the database directory, holding a cluster
+// database dump to be restored the next time the cluster database is opened.
+const RestoreFilename = "restore.global.sql"
+
 // EnsureSchema applies all relevant schema updates to the cluster database.
 //
 // Before actually doing anything, this function will make sure that all members in the cluster have a schema
@@ -149,13 +154,19 @@ func EnsureSchema(db *sql.DB, address string, dir string, serverUUID string) err
 		return checkClusterIsUpgradable(ctx, tx, [2]int{len(updates), apiExtensions})
 	}
 
+	// Load the database backup staged by "lxd cluster restore-db", if any.
+	err := restoreFromFile(db, filepath.Join(dir, RestoreFilename))
+	if err != nil {
+		return err
+	}
+
 	schema := Schema()
 	schema.File(filepath.Join(dir, "patch.global.sql")) // Optional custom queries
 	schema.Check(check)
 	schema.Hook(hook)
 
 	var initial int
-	err := query.Retry(context.TODO(), func(ctx context.Context) error {
+	err = query.Retry(context.TODO(), func(ctx context.Context) error {
 		var err error
 		initial, err = schema.Ensure(db)
 		if err != nil {
@@ -255,6 +266,37 @@ INSERT INTO nodes_cluster_groups (node_id, group_id) VALUES(1, 1);
 	return nil
 }
 
+// restoreFromFile replaces the content of the cluster database with the dump found at path, if it exists.
+func restoreFromFile(db *sql.DB, path string) error {
+	dump, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed reading database backup: %w", err)
+	}
+
+	logger.Warn("Database backup located; restoring the global database", logger.Ctx{"path": path})
+
+	err = query.Retry(context.TODO(), func(ctx context.Context) error {
+		return query.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+			return query.Restore(ctx, tx, string(dump))
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Failed restoring global database: %w", err)
+	}
+
+	// Prevent the backup being restored again after subsequent restarts.
+	err = os.Remove(path)
+	if err != nil {
+		return fmt.Errorf("Failed removing restored database backup: %w", err)
+	}
+
+	return nil
+}
+
 // Generate a new name for the dqlite driver registration. We need it to be
 // unique for testing, see below.
 func dqliteDriverName() string {
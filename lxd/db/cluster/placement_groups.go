@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -210,19 +212,56 @@ WHERE profiles_config.key = 'placement.group'`)
 // GetInstancesInPlacementGroup returns a map of member (node) ID to a slice of instance IDs for all instances that reference the given placement group either directly or indirectly via a profile.
 // The target placement group is specified with the given name and project name. Instances located on the optional node ID are excluded if the node ID is not nil.
 func GetInstancesInPlacementGroup(ctx context.Context, tx *sql.Tx, name string, projectName string, nodeID *int64) (map[int64][]int64, error) {
-	args := []any{projectName, name}
+	return GetInstancesWithConfig(ctx, tx, projectName, map[string]string{"placement.group": name}, nodeID)
+}
+
+// GetInstancesWithConfig returns a map of member (node) ID to a slice of instance IDs for all instances of the given project whose
+// configuration contains all of the given key/value pairs, either directly or indirectly via a profile.
+// Instances located on the optional node ID are excluded if the node ID is not nil.
+func GetInstancesWithConfig(ctx context.Context, tx *sql.Tx, projectName string, config map[string]string, nodeID *int64) (map[int64][]int64, error) {
+	if len(config) == 0 {
+		return nil, errors.New("At least one config key is required")
+	}
+
+	args := []any{projectName}
+
+	var b strings.Builder
+	b.WriteString(`SELECT instances.id, instances.node_id
+FROM instances
+JOIN projects ON instances.project_id = projects.id
+WHERE projects.name = ?`)
 
-	// Compute the "placement.group" for each instance using COALESCE(instance-level-config, last-applied-profile-config) so that instance-level config overrides profile-level config.
+	for _, key := range slices.Sorted(maps.Keys(config)) {
+		b.WriteString("\nAND " + instanceConfigValueSQL + " = ?")
+		args = append(args, key, key, config[key])
+	}
+
+	return getInstancesByMember(ctx, tx, b.String(), args, nodeID)
+}
+
+// GetInstancesWithConfigKey returns a map of member (node) ID to a slice of instance IDs for all instances of the given project
+// with a non-empty value for the given configuration key, either directly or indirectly via a profile.
+// Instances located on the optional node ID are excluded if the node ID is not nil.
+func GetInstancesWithConfigKey(ctx context.Context, tx *sql.Tx, projectName string, key string, nodeID *int64) (map[int64][]int64, error) {
 	q := `SELECT instances.id, instances.node_id
 FROM instances
 JOIN projects ON instances.project_id = projects.id
 WHERE projects.name = ?
-AND COALESCE(
-  (SELECT value FROM instances_config WHERE instance_id = instances.id AND key = 'placement.group' LIMIT 1),
-  (SELECT profiles_config.value FROM instances_profiles JOIN profiles ON instances_profiles.profile_id = profiles.id JOIN profiles_config ON profiles.id = profiles_config.profile_id WHERE instances_profiles.instance_id = instances.id AND profiles_config.key = 'placement.group' ORDER BY instances_profiles.apply_order DESC LIMIT 1)
-) = ?`
+AND ` + instanceConfigValueSQL + ` != ''`
+
+	return getInstancesByMember(ctx, tx, q, []any{projectName, key, key}, nodeID)
+}
+
+// instanceConfigValueSQL computes the value of a configuration key of an instance using COALESCE(instance-level-config, last-applied-profile-config)
+// so that instance-level config overrides profile-level config. It takes the key twice as arguments.
+const instanceConfigValueSQL = `COALESCE(
+  (SELECT value FROM instances_config WHERE instance_id = instances.id AND key = ? LIMIT 1),
+  (SELECT profiles_config.value FROM instances_profiles JOIN profiles ON instances_profiles.profile_id = profiles.id JOIN profiles_config ON profiles.id = profiles_config.profile_id WHERE instances_profiles.instance_id = instances.id AND profiles_config.key = ? ORDER BY instances_profiles.apply_order DESC LIMIT 1)
+)`
 
-	// Exclude member ID if specified.
+// getInstancesByMember runs the given query selecting instance and member IDs and returns a map of member ID to instance IDs.
+// Instances located on the optional node ID are excluded if the node ID is not nil.
+func getInstancesByMember(ctx context.Context, tx *sql.Tx, q string, args []any, nodeID *int64) (map[int64][]int64, error) {
 	if nodeID != nil {
 		q += " AND instances.node_id != ?"
 		args = append(args, *nodeID)
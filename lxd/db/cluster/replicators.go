@@ -152,3 +152,51 @@ func UpdateReplicatorLastRunStatus(ctx context.Context, tx *sql.Tx, id int64, st
 	_, err := tx.ExecContext(ctx, `UPDATE replicators SET last_run_status=? WHERE id=?`, status, id)
 	return err
 }
+
+// ReplicatorInstance holds the replication state of an instance for a replicator.
+type ReplicatorInstance struct {
+	InstanceName       string
+	LastReplicatedDate time.Time
+
+	// Stopped indicates whether the instance was stopped when it was last replicated.
+	Stopped bool
+}
+
+// GetReplicatorInstances returns the replication state of the instances replicated by the replicator
+// with the given ID, keyed by instance name.
+func GetReplicatorInstances(ctx context.Context, tx *sql.Tx, replicatorID int64) (map[string]ReplicatorInstance, error) {
+	stmt := `
+SELECT instances.name, replicators_instances.last_replicated_date, replicators_instances.stopped
+FROM replicators_instances
+JOIN instances ON replicators_instances.instance_id = instances.id
+WHERE replicators_instances.replicator_id = ?`
+
+	result := map[string]ReplicatorInstance{}
+	dest := func(scan func(dest ...any) error) error {
+		r := ReplicatorInstance{}
+		err := scan(&r.InstanceName, &r.LastReplicatedDate, &r.Stopped)
+		if err != nil {
+			return err
+		}
+
+		result[r.InstanceName] = r
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, stmt, dest, replicatorID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting replicated instances for the replicator with ID %d: %w", replicatorID, err)
+	}
+
+	return result, nil
+}
+
+// UpsertReplicatorInstance records that the instance with the given ID was replicated by the replicator
+// with the given ID at the given date.
+func UpsertReplicatorInstance(ctx context.Context, tx *sql.Tx, replicatorID int64, instanceID int64, date time.Time, stopped bool) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO replicators_instances (replicator_id, instance_id, last_replicated_date, stopped) VALUES (?, ?, ?, ?)
+ON CONFLICT (replicator_id, instance_id) DO UPDATE SET last_replicated_date=excluded.last_replicated_date, stopped=excluded.stopped`, replicatorID, instanceID, date, stopped)
+	return err
+}
@@ -90,12 +90,16 @@ const (
 
 	// SecretTypeBearerSigningKey is the SecretType for bearer identity signing keys.
 	SecretTypeBearerSigningKey SecretType = "bearer_signing_key"
+
+	// SecretTypeAuditKey is the SecretType for the key that authenticates the records of the audit logs.
+	SecretTypeAuditKey SecretType = "audit_key"
 )
 
 const (
 	// secretTypeCodeCoreAuth is the database code for SecretTypeCoreAuth.
 	secretTypeCodeCoreAuth         int64 = 1
 	secretTypeCodeBearerSigningKey int64 = 2
+	secretTypeCodeAuditKey         int64 = 3
 )
 
 // Value implements [driver.Valuer] for SecretType.
@@ -105,6 +109,8 @@ func (s SecretType) Value() (driver.Value, error) {
 		return secretTypeCodeCoreAuth, nil
 	case SecretTypeBearerSigningKey:
 		return secretTypeCodeBearerSigningKey, nil
+	case SecretTypeAuditKey:
+		return secretTypeCodeAuditKey, nil
 	}
 
 	return nil, fmt.Errorf("Invalid secret type %q", s)
@@ -117,6 +123,8 @@ func (s *SecretType) ScanInteger(code int64) error {
 		*s = SecretTypeCoreAuth
 	case secretTypeCodeBearerSigningKey:
 		*s = SecretTypeBearerSigningKey
+	case secretTypeCodeAuditKey:
+		*s = SecretTypeAuditKey
 	default:
 		return fmt.Errorf("Invalid secret type code %d", code)
 	}
@@ -378,3 +386,42 @@ func RotateBearerIdentitySigningKey(ctx context.Context, tx *sql.Tx, identityID
 
 	return signingKey, nil
 }
+
+// GetOrCreateAuditKey returns the key that authenticates the records of the audit logs of the cluster members. The key
+// is created if it doesn't exist yet.
+func GetOrCreateAuditKey(ctx context.Context, tx *sql.Tx) (AuthSecretValue, error) {
+	q := `SELECT value FROM secrets WHERE entity_type = ? AND entity_id = ? AND type = ?`
+
+	var keys []AuthSecretValue
+	scanFunc := func(scan func(dest ...any) error) error {
+		var key AuthSecretValue
+		err := scan(&key)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, q, scanFunc, EntityType(entity.TypeServer), 0, SecretTypeAuditKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting audit key: %w", err)
+	}
+
+	switch len(keys) {
+	case 0:
+	case 1:
+		return keys[0], nil
+	default:
+		return nil, errors.New("Encountered more than one audit key")
+	}
+
+	key := newAuthSecretValue()
+	_, err = createSecret(ctx, tx, entity.TypeServer, 0, SecretTypeAuditKey, key, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating audit key: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// StorageVolumeChecksRow represents a single row of the storage_volumes_checks table.
+// db:model storage_volumes_checks
+type StorageVolumeChecksRow struct {
+	ID              int64     `db:"id"`
+	StorageVolumeID int64     `db:"storage_volume_id"`
+	Status          string    `db:"status"`
+	Message         string    `db:"message"`
+	Date            time.Time `db:"date"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (StorageVolumeChecksRow) APIName() string {
+	return "Storage volume check"
+}
+
+// ToAPI converts the [StorageVolumeChecksRow] to an [api.StorageVolumeStateCheck].
+func (c *StorageVolumeChecksRow) ToAPI() *api.StorageVolumeStateCheck {
+	return &api.StorageVolumeStateCheck{
+		Status:    c.Status,
+		Message:   c.Message,
+		CheckedAt: c.Date,
+	}
+}
+
+// GetStorageVolumeCheck returns the result of the last integrity check of the given storage volume.
+func GetStorageVolumeCheck(ctx context.Context, tx *sql.Tx, storageVolumeID int64) (*StorageVolumeChecksRow, error) {
+	check, err := query.SelectOne[StorageVolumeChecksRow](ctx, tx, "WHERE storage_volumes_checks.storage_volume_id = ?", storageVolumeID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading storage volume check: %w", err)
+	}
+
+	return check, nil
+}
+
+// UpsertStorageVolumeCheck records the result of an integrity check of a storage volume, replacing any previous one.
+func UpsertStorageVolumeCheck(ctx context.Context, tx *sql.Tx, check StorageVolumeChecksRow) error {
+	q := `
+INSERT INTO storage_volumes_checks (storage_volume_id, status, message, date) VALUES (?, ?, ?, ?)
+  ON CONFLICT (storage_volume_id) DO UPDATE SET status = excluded.status, message = excluded.message, date = excluded.date
+`
+	_, err := tx.ExecContext(ctx, q, check.StorageVolumeID, check.Status, check.Message, check.Date)
+	if err != nil {
+		return fmt.Errorf("Failed recording storage volume check: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// TrashEntriesRow represents a single row of the trash_entries table.
+// db:model trash_entries
+type TrashEntriesRow struct {
+	ID            int64     `db:"id"`
+	UUID          string    `db:"uuid"`
+	ProjectID     int64     `db:"project_id"`
+	StoragePoolID int64     `db:"storage_pool_id"`
+	NodeID        *int64    `db:"node_id"`
+	Type          string    `db:"type"`
+	Name          string    `db:"name"`
+	BackupConfig  string    `db:"backup_config"`
+	DeletionDate  time.Time `db:"deletion_date"`
+	ExpiryDate    time.Time `db:"expiry_date"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (TrashEntriesRow) APIName() string {
+	return "Trash entry"
+}
+
+// TrashEntry contains [TrashEntriesRow] with additional joins.
+// db:model trash_entries
+type TrashEntry struct {
+	Row TrashEntriesRow
+
+	// db:join JOIN projects ON trash_entries.project_id = projects.id
+	ProjectName string `db:"projects.name"`
+
+	// db:join JOIN storage_pools ON trash_entries.storage_pool_id = storage_pools.id
+	StoragePoolName string `db:"storage_pools.name"`
+
+	// db:join LEFT JOIN nodes ON trash_entries.node_id = nodes.id
+	NodeName string `db:"coalesce(nodes.name, '')"`
+}
+
+// TrashEntryFilter contains fields that can be used to filter results when getting trash entries.
+type TrashEntryFilter struct {
+	Project       *string
+	StoragePoolID *int64
+	ExpiredBefore *time.Time
+}
+
+// ToAPI converts the [TrashEntry] to an [api.TrashEntry].
+func (t *TrashEntry) ToAPI() *api.TrashEntry {
+	return &api.TrashEntry{
+		UUID:      t.Row.UUID,
+		Name:      t.Row.Name,
+		Type:      t.Row.Type,
+		Project:   t.ProjectName,
+		Pool:      t.StoragePoolName,
+		Location:  t.NodeName,
+		DeletedAt: t.Row.DeletionDate,
+		ExpiresAt: t.Row.ExpiryDate,
+	}
+}
+
+// GetTrashEntry returns the trash entry with the given UUID.
+func GetTrashEntry(ctx context.Context, tx *sql.Tx, uuid string) (*TrashEntry, error) {
+	entry, err := query.SelectOne[TrashEntry](ctx, tx, "WHERE trash_entries.uuid = ?", uuid)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading trash entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GetTrashEntries returns all trash entries matching the given filter, ordered by deletion date.
+func GetTrashEntries(ctx context.Context, tx *sql.Tx, filter TrashEntryFilter) ([]TrashEntry, error) {
+	var args []any
+	var where []string
+
+	if filter.Project != nil {
+		where = append(where, "projects.name = ?")
+		args = append(args, *filter.Project)
+	}
+
+	if filter.StoragePoolID != nil {
+		where = append(where, "trash_entries.storage_pool_id = ?")
+		args = append(args, *filter.StoragePoolID)
+	}
+
+	if filter.ExpiredBefore != nil {
+		where = append(where, "trash_entries.expiry_date <= ?")
+		args = append(args, *filter.ExpiredBefore)
+	}
+
+	var b strings.Builder
+	if len(where) > 0 {
+		b.WriteString("WHERE ")
+		b.WriteString(strings.Join(where, " AND "))
+		b.WriteString(" ")
+	}
+
+	b.WriteString("ORDER BY trash_entries.deletion_date")
+
+	entries, err := query.Select[TrashEntry](ctx, tx, b.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading trash entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CreateTrashEntry adds a new trash entry to the database.
+func CreateTrashEntry(ctx context.Context, tx *sql.Tx, object TrashEntriesRow) (int64, error) {
+	return query.Create(ctx, tx, object)
+}
+
+// DeleteTrashEntry deletes the trash entry with the given UUID.
+func DeleteTrashEntry(ctx context.Context, tx *sql.Tx, uuid string) error {
+	return query.DeleteOne[TrashEntriesRow, *TrashEntriesRow](ctx, tx, "WHERE trash_entries.uuid = ?", uuid)
+}
@@ -75,6 +75,7 @@ func (s *sessionHandler) StartSession(r *http.Request, res oidc.AuthenticationRe
 	// per-session.
 	newMetadata := cluster.OIDCMetadata{
 		Subject:                res.Subject,
+		Issuer:                 res.Issuer,
 		IdentityProviderGroups: res.IdentityProviderGroups,
 	}
 
@@ -99,7 +100,17 @@ func (s *sessionHandler) StartSession(r *http.Request, res oidc.AuthenticationRe
 				return fmt.Errorf("Failed getting OIDC metadata: %w", err)
 			}
 
-			if newMetadata.Subject != existingMetadata.Subject {
+			if existingMetadata.Disabled {
+				logger.Warn("Rejecting OIDC login of identity that was deactivated via SCIM", logger.Ctx{"email": res.Email})
+
+				// Return a generic error so as not to reveal that a user with this email exists.
+				return api.NewGenericStatusError(http.StatusForbidden)
+			}
+
+			// Identities that are provisioned via SCIM have no subject until their first login, but they do have an
+			// issuer. The issuer is always checked, as subjects are only unique for a given issuer and an identity must
+			// never be taken over by a login from another issuer with the same email address.
+			if newMetadata.Issuer != existingMetadata.Issuer || (existingMetadata.Subject != "" && newMetadata.Subject != existingMetadata.Subject) {
 				// We have historically allowed the IdP subject for a user with a given email address to change.
 				// This was with the view that the end user may authenticate to the IdP with a different mechanism (such
 				// as social login) and should still have the same permissions.
@@ -111,12 +122,28 @@ func (s *sessionHandler) StartSession(r *http.Request, res oidc.AuthenticationRe
 				// So we will instead restrict changes to the IdP subject. The consequence is that email address/subject
 				// conflicts will need to be resolved by an administrator. If there does turn out to be a problem with
 				// social login, we may need a mechanism to "join accounts" - but we can approach it from a secure position.
-				logger.Warn("Encountered new OIDC login unexpected subject. The existing identity with this email must be deleted to allow the new login", logger.Ctx{"email": res.Email, "subject": res.Subject})
+				logger.Warn("Encountered new OIDC login unexpected subject. The existing identity with this email must be deleted to allow the new login", logger.Ctx{"email": res.Email, "subject": res.Subject, "issuer": res.Issuer})
 
 				// Return a generic error so as not to reveal that a user with this email already exists.
 				return api.NewGenericStatusError(http.StatusInternalServerError)
 			}
 
+			// The first login of an identity that was provisioned via SCIM binds the identity to the subject. Only allow
+			// this if the issuer has verified that the user owns the email address.
+			if existingMetadata.Subject == "" && !res.EmailVerified {
+				logger.Warn("Rejecting first OIDC login of provisioned identity with unverified email address", logger.Ctx{"email": res.Email, "subject": res.Subject, "issuer": res.Issuer})
+
+				// Return a generic error so as not to reveal that a user with this email exists.
+				return api.NewGenericStatusError(http.StatusForbidden)
+			}
+
+			// The identity provider groups of identities that are provisioned via SCIM are managed via SCIM.
+			newMetadata.SCIM = existingMetadata.SCIM
+			newMetadata.ExternalID = existingMetadata.ExternalID
+			if existingMetadata.SCIM {
+				newMetadata.IdentityProviderGroups = existingMetadata.IdentityProviderGroups
+			}
+
 			// Allow updates to the identity name, or to the identity provider groups.
 			doUpdateIdentity = res.Name != identity.Name || !existingMetadata.Equals(newMetadata)
 		}
@@ -233,6 +260,7 @@ func (s *sessionHandler) GetIdentityBySessionID(ctx context.Context, sessionID u
 	return &oidc.AuthenticationResult{
 			IdentityType:           api.IdentityTypeOIDCClient,
 			Subject:                metadata.Subject,
+			Issuer:                 metadata.Issuer,
 			Email:                  identity.Identifier,
 			Name:                   identity.Name,
 			IdentityProviderGroups: metadata.IdentityProviderGroups,
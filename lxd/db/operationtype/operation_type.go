@@ -145,6 +145,17 @@ const (
 	ReplicatorRun
 	ReplicatorRunInstance
 	ProjectReplicaModeUpdate
+	TrashRestore
+	TrashPurge
+	TrashExpire
+	VolumeCheck
+	StoragePoolScrub
+	ClusterInstanceRebalance
+	ReplicatorFailover
+	ReplicatorTestFailover
+	ReplicatorRunVolume
+	ReplicatorRunBucket
+	ClusterMaintenance
 
 	// upperBound is used only to enforce consistency in the package on init.
 	// Make sure it's always the last item in this list.
@@ -396,6 +407,28 @@ func (t Type) Description() string {
 		return "Replicating instance"
 	case ProjectReplicaModeUpdate:
 		return "Updating project replica mode"
+	case TrashRestore:
+		return "Restoring from trash"
+	case TrashPurge:
+		return "Purging from trash"
+	case TrashExpire:
+		return "Purging expired trash"
+	case VolumeCheck:
+		return "Checking storage volume"
+	case StoragePoolScrub:
+		return "Scrubbing storage pool"
+	case ClusterInstanceRebalance:
+		return "Rebalancing cluster instances"
+	case ReplicatorFailover:
+		return "Failing over replicator"
+	case ReplicatorTestFailover:
+		return "Testing replicator failover"
+	case ReplicatorRunVolume:
+		return "Replicating storage volume"
+	case ReplicatorRunBucket:
+		return "Replicating storage bucket"
+	case ClusterMaintenance:
+		return "Running cluster maintenance"
 
 	// It should never be possible to reach the default clause.
 	// See the init function.
@@ -414,7 +447,7 @@ func (t Type) EntityType() entity.Type {
 		BackupsExpire, SnapshotsExpire, ClusterJoinToken, CertificateAddToken, RenewServerCertificate,
 		ClusterHeal, ImagesUpdate, VolumeSnapshotsCreateScheduled, SnapshotsCreateScheduled,
 		PruneExpiredOperations, RefreshClusterLinkVolatileAddresses,
-		StoragePoolCreate, Wait:
+		StoragePoolCreate, TrashExpire, ClusterInstanceRebalance, ClusterMaintenance, Wait:
 		return entity.TypeServer
 
 	// Project level operations.
@@ -422,7 +455,7 @@ func (t Type) EntityType() entity.Type {
 	// (the entity being created is not yet referenceable).
 	case VolumeCreate, ProjectRename, InstanceCreate, ImageDownload, ImageUploadToken, CustomVolumeBackupRestore,
 		InstanceStateUpdateBulk, BackupRestore, ProjectDelete, NetworkCreate, NetworkACLCreate, StorageBucketCreate,
-		NetworkZoneCreate, ReplicatorRunInstance, ProjectReplicaModeUpdate:
+		NetworkZoneCreate, ReplicatorRunInstance, ReplicatorRunVolume, ReplicatorRunBucket, ProjectReplicaModeUpdate, TrashRestore, TrashPurge:
 		return entity.TypeProject
 
 	// Storage bucket operations.
@@ -430,7 +463,7 @@ func (t Type) EntityType() entity.Type {
 		return entity.TypeStorageBucket
 
 	// Volume operations.
-	case VolumeMigrate, VolumeMove, VolumeSnapshotCreate, CustomVolumeBackupCreate, VolumeCopy, VolumeUpdate, VolumeDelete:
+	case VolumeMigrate, VolumeMove, VolumeSnapshotCreate, CustomVolumeBackupCreate, VolumeCopy, VolumeUpdate, VolumeDelete, VolumeCheck:
 		return entity.TypeStorageVolume
 
 	// Volume snapshot operations
@@ -460,7 +493,7 @@ func (t Type) EntityType() entity.Type {
 		return entity.TypeStorageVolumeBackup
 
 	// Storage pool operations.
-	case StoragePoolUpdate, StoragePoolDelete:
+	case StoragePoolUpdate, StoragePoolDelete, StoragePoolScrub:
 		return entity.TypeStoragePool
 
 	// Profile operations.
@@ -491,7 +524,7 @@ func (t Type) EntityType() entity.Type {
 	case NetworkZoneUpdate, NetworkZoneDelete, NetworkZoneRecordCreate, NetworkZoneRecordUpdate, NetworkZoneRecordDelete:
 		return entity.TypeNetworkZone
 	// Replicator operations.
-	case ReplicatorRun:
+	case ReplicatorRun, ReplicatorFailover, ReplicatorTestFailover:
 		return entity.TypeReplicator
 
 	// It should never be possible to reach the default clause.
@@ -530,6 +563,10 @@ func (t Type) ConflictAction() ConflictAction {
 		return ConflictActionFail // Enforces cluster-wide evacuation exclusivity when used with a shared ConflictReference; this prevents evacuation race conditions.
 	case ReplicatorRun:
 		return ConflictActionFail // Prevents concurrent runs of the same replicator; the replicator URL is used as the per-replicator conflict reference.
+	case ClusterInstanceRebalance:
+		return ConflictActionFail // Prevents concurrent rebalancing runs from moving the same instances.
+	case ReplicatorFailover, ReplicatorTestFailover:
+		return ConflictActionFail // Prevents concurrent failovers of the same replicator.
 	}
 
 	return ConflictActionNone
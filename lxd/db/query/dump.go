@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -61,6 +62,53 @@ func Dump(ctx context.Context, tx *sql.Tx, schemaOnly bool) (string, error) {
 	return builder.String(), nil
 }
 
+// Restore replaces all tables, views and triggers of the database with the ones found in
+// the given SQL text dump, as returned by Dump. It's meant to be run in a transaction.
+func Restore(ctx context.Context, tx *sql.Tx, dump string) error {
+	// Strip the statements wrapping the dump, since we're already in a transaction.
+	body, found := strings.CutPrefix(dump, "PRAGMA foreign_keys=OFF;\nBEGIN TRANSACTION;\n")
+	if found {
+		body, found = strings.CutSuffix(body, "COMMIT;\n")
+	}
+
+	if !found {
+		return errors.New("Invalid database dump")
+	}
+
+	entitiesSchemas, entityNames, err := getEntitiesSchemas(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Only check foreign keys when committing, as tables are dropped and re-created in dump order.
+	_, err = tx.ExecContext(ctx, "PRAGMA defer_foreign_keys=ON")
+	if err != nil {
+		return fmt.Errorf("Failed deferring foreign keys: %w", err)
+	}
+
+	// Drop triggers and views before tables so that nothing fires while tables are being dropped.
+	// Indexes are dropped along with their table.
+	for _, kind := range []string{"trigger", "view", "table"} {
+		for _, name := range entityNames {
+			if entitiesSchemas[name][0] != kind {
+				continue
+			}
+
+			_, err = tx.ExecContext(ctx, fmt.Sprintf("DROP %s %q", strings.ToUpper(kind), name))
+			if err != nil {
+				return fmt.Errorf("Failed dropping %s %q: %w", kind, name, err)
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, body)
+	if err != nil {
+		return fmt.Errorf("Failed loading database dump: %w", err)
+	}
+
+	return nil
+}
+
 // getEntitiesSchemas gets all the tables, their kind, and their schema, as well as a list of entity names in their default order from
 // the sqlite_master table. The returned map values are arrays of length 2 whose first element contains the entity type and the second
 // contains it's schema.
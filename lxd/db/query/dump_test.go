@@ -394,3 +394,24 @@ var data = map[string][]string{
 		"INSERT INTO storage_pools_config VALUES(1,1,NULL,'k','v')",
 	},
 }
+
+func TestRestore(t *testing.T) {
+	tx := newTxForDump(t, "global")
+	dump, err := query.Dump(context.Background(), tx, false)
+	require.NoError(t, err)
+
+	// Restore over a database with a different schema and content.
+	tx = newTxForDump(t, "local")
+	err = query.Restore(context.Background(), tx, dump)
+	require.NoError(t, err)
+
+	restored, err := query.Dump(context.Background(), tx, false)
+	require.NoError(t, err)
+	assert.Equal(t, dump, restored)
+}
+
+func TestRestoreInvalidDump(t *testing.T) {
+	tx := newTxForDump(t, "local")
+	err := query.Restore(context.Background(), tx, "DROP TABLE config;\n")
+	assert.EqualError(t, err, "Invalid database dump")
+}
@@ -61,6 +61,22 @@ const (
 	// OIDCAuthenticationUnavailable warnings are created when OIDC is configured on LXD but LXD is unable to use those
 	// settings to initialize the OIDC verifier.
 	OIDCAuthenticationUnavailable
+	// StoragePoolUsageWarning represents a storage pool whose usage is above its warning threshold.
+	StoragePoolUsageWarning
+	// StoragePoolUsageCritical represents a storage pool whose usage is above its critical threshold.
+	StoragePoolUsageCritical
+	// StoragePoolScrubFailed represents a storage pool whose scrub reported data integrity issues.
+	StoragePoolScrubFailed
+	// StorageVolumeCheckFailed represents a storage volume whose integrity check reported issues.
+	StorageVolumeCheckFailed
+	// ReplicatorRPOBreached represents a replicator whose replication lag exceeds its recovery point objective.
+	ReplicatorRPOBreached
+	// ClientCertificateExpiring represents a trusted client certificate that expires soon or has expired.
+	ClientCertificateExpiring
+	// ServerCertificateExpiring represents a server certificate that expires soon or has expired.
+	ServerCertificateExpiring
+	// ClusterCertificateExpiring represents a cluster certificate that expires soon or has expired.
+	ClusterCertificateExpiring
 )
 
 // TypeNames associates a warning code to its name.
@@ -92,6 +108,14 @@ var TypeNames = map[Type]string{
 	StoragePoolUnvailable:                  "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:       "Cannot update cluster certificate",
 	OIDCAuthenticationUnavailable:          "Failed applying OIDC settings",
+	StoragePoolUsageWarning:                "Storage pool usage above warning threshold",
+	StoragePoolUsageCritical:               "Storage pool usage above critical threshold",
+	StoragePoolScrubFailed:                 "Storage pool scrub failed",
+	StorageVolumeCheckFailed:               "Storage volume integrity check failed",
+	ReplicatorRPOBreached:                  "Replicator recovery point objective breached",
+	ClientCertificateExpiring:              "Client certificate expiring",
+	ServerCertificateExpiring:              "Server certificate expiring",
+	ClusterCertificateExpiring:             "Cluster certificate expiring",
 }
 
 // Severity returns the severity of the warning type.
@@ -151,6 +175,22 @@ func (t Type) Severity() Severity {
 		return SeverityLow
 	case OIDCAuthenticationUnavailable:
 		return SeverityModerate
+	case StoragePoolUsageWarning:
+		return SeverityModerate
+	case StoragePoolUsageCritical:
+		return SeverityHigh
+	case StoragePoolScrubFailed:
+		return SeverityHigh
+	case StorageVolumeCheckFailed:
+		return SeverityHigh
+	case ReplicatorRPOBreached:
+		return SeverityHigh
+	case ClientCertificateExpiring:
+		return SeverityModerate
+	case ServerCertificateExpiring:
+		return SeverityModerate
+	case ClusterCertificateExpiring:
+		return SeverityModerate
 	}
 
 	return SeverityLow
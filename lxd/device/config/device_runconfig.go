@@ -82,6 +82,15 @@ type DiskLimits struct {
 	ReadIOps   int64
 	WriteBytes int64
 	WriteIOps  int64
+
+	// Combined read and write limits, used by volume level limits.
+	Bytes      int64
+	BytesBurst int64
+	IOps       int64
+	IOpsBurst  int64
+
+	// Group is the name of the throttle group shared by all attachments of the same volume.
+	Group string
 }
 
 // RunConfig represents run-time config used for device setup/cleanup.
@@ -1142,6 +1142,7 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 			// Mount the pool volume and update srcPath to mount path so it can be recognised as dir
 			// if the volume is a filesystem volume type (if it is a block volume the srcPath will
 			// be returned as the path to the block device).
+			var volLimits *deviceConfig.DiskLimits
 			if d.config["pool"] != "" {
 				var revertFunc func()
 
@@ -1173,6 +1174,14 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 					mount.FSType = "iso9660"
 				}
 
+				// Apply the I/O limits of the volume itself.
+				volLimits, err = storagePools.VolumeDiskLimits(dbVolume.ID, dbVolume.Config)
+				if err != nil {
+					return nil, err
+				}
+
+				mount.Limits = diskMergeLimits(mount.Limits, volLimits)
+
 				revertFunc, mountedPath, _, err := d.mountPoolVolume()
 				if err != nil {
 					return nil, diskSourceNotFoundError{msg: "Failed mounting volume", err: err}
@@ -1233,6 +1242,11 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 					return nil, errors.New(`Missing mount "path" setting`)
 				}
 
+				// Directory sharing doesn't support I/O limits.
+				if volLimits != nil {
+					return nil, fmt.Errorf("I/O limits of custom volume %q cannot be applied to filesystem volumes attached to virtual machines", d.config["source"])
+				}
+
 				// Mount the source in the instance devices directory.
 				// This will ensure that if the exported directory configured as readonly that this
 				// takes effect event if using virtio-fs (which doesn't support read only mode) by
@@ -1426,13 +1440,18 @@ func (d *disk) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 				return err
 			}
 
+			volLimits, err := d.volumeLimits(d.config)
+			if err != nil {
+				return err
+			}
+
 			// Apply the limits to a minimal mount entry.
-			diskLimits := &deviceConfig.DiskLimits{
+			diskLimits := diskMergeLimits(&deviceConfig.DiskLimits{
 				ReadBytes:  readBps,
 				ReadIOps:   readIops,
 				WriteBytes: writeBps,
 				WriteIOps:  writeIops,
-			}
+			}, volLimits)
 
 			runConf.Mounts = []deviceConfig.MountEntryItem{
 				{
@@ -1524,6 +1543,16 @@ func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
 			hasDiskLimits = true
 			break
 		}
+
+		volLimits, err := d.volumeLimits(dev)
+		if err != nil {
+			return err
+		}
+
+		if volLimits != nil {
+			hasDiskLimits = true
+			break
+		}
 	}
 
 	if !hasDiskLimits {
@@ -2237,6 +2266,23 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 			return nil, err
 		}
 
+		// The limits of the volume take precedence over those of the device.
+		// As cgroups don't support combined limits, apply them to reads and writes separately.
+		volLimits, err := d.volumeLimits(dev)
+		if err != nil {
+			return nil, err
+		}
+
+		if volLimits != nil && volLimits.Bytes > 0 {
+			readBps = volLimits.Bytes
+			writeBps = volLimits.Bytes
+		}
+
+		if volLimits != nil && volLimits.IOps > 0 {
+			readIops = volLimits.IOps
+			writeIops = volLimits.IOps
+		}
+
 		// Set the source path
 		source := d.getDevicePath(devName, dev)
 		if dev["source"] == "" {
@@ -2395,6 +2441,63 @@ func (d *disk) parseLimit(dev deviceConfig.Device) (readBps int64, readIops int6
 	return readBps, readIops, writeBps, writeIops, nil
 }
 
+// volumeLimits returns the I/O limits of the custom volume attached by the given disk device.
+// Returns nil if the device doesn't attach a custom volume or if the volume has no limits configured.
+func (d *disk) volumeLimits(dev deviceConfig.Device) (*deviceConfig.DiskLimits, error) {
+	if dev["pool"] == "" || dev["source"] == "" || filters.IsRootDisk(dev) {
+		return nil, nil
+	}
+
+	if dev["source.type"] != "" && dev["source.type"] != cluster.StoragePoolVolumeTypeNameCustom {
+		return nil, nil
+	}
+
+	volumeName := dev["source"]
+	if dev["source.snapshot"] != "" {
+		volumeName = volumeName + shared.SnapshotDelimiter + dev["source.snapshot"]
+	}
+
+	instProj := d.inst.Project()
+	storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, cluster.StoragePoolVolumeTypeCustom)
+
+	var dbVolume *db.StorageVolume
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err := tx.GetStoragePoolID(ctx, dev["pool"])
+		if err != nil {
+			return err
+		}
+
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, poolID, storageProjectName, cluster.StoragePoolVolumeTypeCustom, volumeName, true)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading custom volume %q: %w", volumeName, err)
+	}
+
+	return storagePools.VolumeDiskLimits(dbVolume.ID, dbVolume.Config)
+}
+
+// diskMergeLimits combines the I/O limits of a disk device with those of the volume it attaches.
+// Either argument may be nil, in which case the other one is returned.
+func diskMergeLimits(deviceLimits *deviceConfig.DiskLimits, volLimits *deviceConfig.DiskLimits) *deviceConfig.DiskLimits {
+	if volLimits == nil {
+		return deviceLimits
+	}
+
+	if deviceLimits == nil {
+		return volLimits
+	}
+
+	limits := *deviceLimits
+	limits.Bytes = volLimits.Bytes
+	limits.BytesBurst = volLimits.BytesBurst
+	limits.IOps = volLimits.IOps
+	limits.IOpsBurst = volLimits.IOpsBurst
+	limits.Group = volLimits.Group
+
+	return &limits
+}
+
 func (d *disk) getParentBlocks(path string) ([]string, error) {
 	var devices []string
 	var dev []string
@@ -31,7 +31,7 @@ func (d instanceDeleter) Delete(ctx context.Context, clientType request.ClientTy
 		return operations.ScheduleUserOperationFromOperation(s, op, args)
 	}
 
-	instanceDeleteOp, err := doInstanceDelete(opScheduler, s, ref.Name(), ref.ProjectName, true)
+	instanceDeleteOp, err := doInstanceDelete(opScheduler, s, ref.Name(), ref.ProjectName, true, false)
 	if err != nil {
 		return fmt.Errorf("Failed deleting instance %q: %w", name, err)
 	}
@@ -172,7 +172,7 @@ func (d storageVolumeDeleter) Delete(ctx context.Context, clientType request.Cli
 		return operations.ScheduleUserOperationFromOperation(s, op, args)
 	}
 
-	volumeDeleteOp, err := doStoragePoolVolumeDelete(ctx, opScheduler, s, name, volTypeCode, pool, ref.ProjectName, ref.ProjectName)
+	volumeDeleteOp, err := doStoragePoolVolumeDelete(ctx, opScheduler, s, name, volTypeCode, pool, ref.ProjectName, ref.ProjectName, false)
 	if err != nil {
 		return fmt.Errorf("Failed deleting storage volume %q: %w", name, err)
 	}
@@ -3,11 +3,13 @@ package main
 import (
 	"context"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"slices"
 	"time"
 
@@ -15,12 +17,14 @@ import (
 
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/auth/bearer"
 	"github.com/canonical/lxd/lxd/auth/encryption"
 	"github.com/canonical/lxd/lxd/certificate"
 	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/db/warningtype"
 	"github.com/canonical/lxd/lxd/identity"
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/request"
@@ -39,6 +43,9 @@ const (
 	// defaultBearerTokenExpiry is used when issuing bearer tokens if no expiry is provided.
 	// The default value is 10 years (essentially no expiry).
 	defaultBearerTokenExpiry = "10y"
+
+	// certificateRenewalNonceExpiry is the validity of the nonces that identities sign to renew their certificate.
+	certificateRenewalNonceExpiry = 5 * time.Minute
 )
 
 var identitiesCmd = APIEndpoint{
@@ -62,6 +69,20 @@ var currentIdentityCmd = APIEndpoint{
 	},
 }
 
+var currentIdentityCertificateCmd = APIEndpoint{
+	Path:        "auth/identities/current/certificate",
+	MetricsType: entity.TypeIdentity,
+
+	Get: APIEndpointAction{
+		Handler:       identityCurrentCertificateGet,
+		AccessHandler: allowAuthenticated,
+	},
+	Post: APIEndpointAction{
+		Handler:       identityCurrentCertificatePost,
+		AccessHandler: allowAuthenticated,
+	},
+}
+
 var tlsIdentitiesCmd = APIEndpoint{
 	Path:        "auth/identities/tls",
 	MetricsType: entity.TypeIdentity,
@@ -416,6 +437,10 @@ func identitiesBearerPost(d *Daemon, r *http.Request) response.Response {
 		return response.Forbidden(errors.New("Initial UI identities may only be created via unix socket"))
 	}
 
+	if !idType.IsFineGrained() && len(req.Groups) > 0 {
+		return response.BadRequest(fmt.Errorf("Identities of type %q cannot be added to groups", req.Type))
+	}
+
 	newIdentityID := uuid.New()
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the identity.
@@ -458,6 +483,7 @@ func identitiesBearerPost(d *Daemon, r *http.Request) response.Response {
 //	Issue a token for a bearer identity.
 //
 //	Issues a new token for the bearer identity and revokes any existing token.
+//	The token can be narrowed to a subset of the permissions of the identity.
 //
 //	---
 //	consumes:
@@ -508,6 +534,10 @@ func identityBearerTokenPost(d *Daemon, r *http.Request) response.Response {
 			return response.BadRequest(errors.New("The initial UI token expiry cannot be set"))
 		}
 
+		if len(req.Permissions) > 0 {
+			return response.BadRequest(errors.New("The initial UI token cannot be narrowed"))
+		}
+
 		req.Expiry = "1d"
 	}
 
@@ -523,6 +553,11 @@ func identityBearerTokenPost(d *Daemon, r *http.Request) response.Response {
 
 	s := d.State()
 
+	permissions, err := bearerTokenPermissions(r.Context(), s, req.Permissions)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	var secret []byte
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		secret, err = dbCluster.RotateBearerIdentitySigningKey(ctx, tx.Tx(), id.ID)
@@ -530,6 +565,12 @@ func identityBearerTokenPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		// Record the permissions alongside the new signing key so that they are enforced for the new token only.
+		err = dbCluster.SetBearerIdentityTokenPermissions(ctx, tx.Tx(), id.ID, permissions)
+		if err != nil {
+			return err
+		}
+
 		// Record the expiry alongside the new signing key so that it can be reported when the identity is listed.
 		return dbCluster.SetBearerIdentityTokenExpiry(ctx, tx.Tx(), id.ID, &expiresAt)
 	})
@@ -551,6 +592,8 @@ func identityBearerTokenPost(d *Daemon, r *http.Request) response.Response {
 		token, err = encryption.GetClientBearerToken(secret, id.Identifier, s.GlobalConfig.ClusterUUID(), expiresAt, serverCertFingerprint)
 	case api.IdentityTypeBearerTokenDevLXD:
 		token, err = encryption.GetDevLXDBearerToken(secret, id.Identifier, s.GlobalConfig.ClusterUUID(), expiresAt)
+	case api.IdentityTypeBearerTokenSCIM:
+		token, err = encryption.GetSCIMBearerToken(secret, id.Identifier, s.GlobalConfig.ClusterUUID(), expiresAt)
 	default:
 		err = api.StatusErrorf(http.StatusBadRequest, "Token cannot be issued for identity of type %q", id.Type)
 	}
@@ -579,6 +622,49 @@ func identityBearerTokenPost(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, api.IdentityBearerToken{Token: token})
 }
 
+// bearerTokenPermissions validates the permissions that a bearer token is narrowed to. It returns them with standardised
+// entity URLs, so that they match the entity URLs used during permission checks.
+func bearerTokenPermissions(ctx context.Context, s *state.State, permissions []api.Permission) ([]api.Permission, error) {
+	if len(permissions) == 0 {
+		return nil, nil
+	}
+
+	_, err := validatePermissions(ctx, s, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	standardised := make([]api.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		u, err := url.Parse(permission.EntityReference)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Failed parsing permission with entity reference %q: %w", permission.EntityReference, err)
+		}
+
+		entityType, projectName, location, pathArguments, err := entity.ParseURL(*u)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Failed parsing permission with entity reference %q: %w", permission.EntityReference, err)
+		}
+
+		entityURL, err := entityType.URL(projectName, location, pathArguments...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed standardizing entity URL: %w", err)
+		}
+
+		standardisedPermission := api.Permission{
+			EntityType:      permission.EntityType,
+			EntityReference: entityURL.String(),
+			Entitlement:     permission.Entitlement,
+		}
+
+		if !slices.Contains(standardised, standardisedPermission) {
+			standardised = append(standardised, standardisedPermission)
+		}
+	}
+
+	return standardised, nil
+}
+
 // swagger:operation DELETE /1.0/auth/identities/bearer/{nameOrID}/token identities identity_delete_bearer_token
 //
 //	Revoke a bearer identity token.
@@ -611,6 +697,11 @@ func identityBearerTokenDelete(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Failed revoking token: %w", err)
 		}
 
+		err = dbCluster.SetBearerIdentityTokenPermissions(ctx, tx.Tx(), id.ID, nil)
+		if err != nil {
+			return err
+		}
+
 		// Clear the recorded expiry so that the revoked token is no longer reported when the identity is listed.
 		return dbCluster.SetBearerIdentityTokenExpiry(ctx, tx.Tx(), id.ID, nil)
 	})
@@ -1648,6 +1739,219 @@ func identityGetCurrent(d *Daemon, r *http.Request) response.Response {
 	})
 }
 
+// swagger:operation GET /1.0/auth/identities/current/certificate identities identity_current_certificate_get
+//
+//	Get a certificate renewal nonce
+//
+//	Returns a short-lived nonce that the requestor must sign with the private key of its new certificate to renew its
+//	TLS client certificate. The nonce is only valid for the certificate that the request is authenticated with.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Certificate renewal nonce
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/IdentityCertificateNonce"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func identityCurrentCertificateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if requestor.Protocol != api.AuthenticationMethodTLS {
+		return response.BadRequest(errors.New("Only identities that authenticate with a trusted TLS client certificate can renew their certificate"))
+	}
+
+	secrets, err := s.CoreAuthSecrets(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	expiresAt := time.Now().Add(certificateRenewalNonceExpiry)
+	nonce, err := encryption.GetCertificateRenewalNonce(secrets[0].Value, requestor.Username, s.GlobalConfig.ClusterUUID(), expiresAt)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, api.IdentityCertificateNonce{Nonce: nonce, ExpiresAt: expiresAt})
+}
+
+// swagger:operation POST /1.0/auth/identities/current/certificate identities identity_current_certificate_post
+//
+//	Renew the certificate of the current identity
+//
+//	Replaces the TLS client certificate of the requestor with the given certificate.
+//	The identity keeps its name, group memberships and project restrictions.
+//	The request must be authenticated with the certificate that is being replaced, and must contain a nonce from
+//	GET /1.0/auth/identities/current/certificate signed with the private key of the new certificate.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: certificate
+//	    description: The new certificate
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/IdentityCertificatePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func identityCurrentCertificatePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Only identities in the trust store can renew their certificate. Clients that are trusted because their
+	// certificate is signed by the CA (core.trust_ca_certificates) have no identity to update.
+	if requestor.Protocol != api.AuthenticationMethodTLS {
+		return response.BadRequest(errors.New("Only identities that authenticate with a trusted TLS client certificate can renew their certificate"))
+	}
+
+	var req api.IdentityCertificatePost
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	fingerprint, err := validateIdentityCert(s.Endpoints.NetworkCert(), req.Certificate)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if fingerprint == requestor.Username {
+		return response.BadRequest(errors.New("The new certificate must be different from the current certificate"))
+	}
+
+	// Check that the nonce was issued to the current certificate and that the requestor holds the new private key.
+	secrets, err := s.CoreAuthSecrets(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = api.NewStatusError(http.StatusForbidden, "No valid nonce was provided")
+	for _, secret := range secrets {
+		err = bearer.VerifyCertificateRenewalNonce(req.Nonce, secret.Value, s.GlobalConfig.ClusterUUID(), requestor.Username)
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	newCert, err := shared.ParseCert([]byte(req.Certificate))
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = verifyCertificateRenewalSignature(newCert, req.Nonce, req.Signature)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// CA signed certificates must not have been revoked.
+	if s.Endpoints.NetworkCert().CA() != nil && !d.checkCARevocation(r.Context(), s.GlobalConfig.TrustCARevocation(), *newCert) {
+		return response.Forbidden(errors.New("The new certificate has been revoked or its revocation status could not be determined"))
+	}
+
+	var id *dbCluster.IdentitiesRow
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err = dbCluster.GetIdentityByAuthenticationMethodAndIdentifier(ctx, tx.Tx(), api.AuthenticationMethodTLS, requestor.Username)
+		if err != nil {
+			return err
+		}
+
+		if id.Type == api.IdentityTypeCertificateServer {
+			return api.NewStatusError(http.StatusForbidden, "Server certificates cannot be renewed")
+		}
+
+		_, err = dbCluster.GetIdentityByAuthenticationMethodAndIdentifier(ctx, tx.Tx(), api.AuthenticationMethodTLS, fingerprint)
+		if err == nil {
+			return api.NewStatusError(http.StatusConflict, "The new certificate is already in use")
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		// The identity ID is unchanged, so group memberships and project restrictions are kept.
+		err = dbCluster.UpdateTLSIdentity(ctx, tx.Tx(), *id, fingerprint, req.Certificate)
+		if err != nil {
+			return err
+		}
+
+		// Resolve any expiry warning for the replaced certificate, regardless of which member raised it.
+		warningType := warningtype.ClientCertificateExpiring
+		entityType := dbCluster.EntityType(entity.TypeIdentity)
+		entityID := int(id.ID)
+		expiryWarnings, err := dbCluster.GetWarnings(ctx, tx.Tx(), dbCluster.WarningFilter{TypeCode: &warningType, EntityType: &entityType, EntityID: &entityID})
+		if err != nil {
+			return err
+		}
+
+		for _, w := range expiryWarnings {
+			err = tx.UpdateWarningStatus(w.UUID, warningtype.StatusResolved)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Notify other cluster members to update their identity cache.
+	notify := newIdentityNotificationFunc(s, r, s.Endpoints.NetworkCert(), s.ServerCert())
+	_, err = notify(lifecycle.IdentityUpdated, api.AuthenticationMethodTLS, fingerprint, true, true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendSecurity(security.AuthnCertificateChange.WithSuffix(requestor.Username).UserEvent(r.Context(), security.LevelInfo, "TLS client certificate renewed"))
+
+	return response.EmptySyncResponse
+}
+
 // swagger:operation PUT /1.0/auth/identities/bearer/{nameOrIdentifier} identities identity_put_bearer
 //
 //	Update the bearer identity
@@ -2293,7 +2597,7 @@ func identityDelete(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	if !identityType.IsFineGrained() && identityType.Name() != api.IdentityTypeBearerTokenInitialUI {
+	if !identityType.IsFineGrained() && !slices.Contains([]string{api.IdentityTypeBearerTokenInitialUI, api.IdentityTypeBearerTokenSCIM}, identityType.Name()) {
 		return response.NotImplemented(fmt.Errorf("Identities of type %q cannot be modified via this API", id.Type))
 	}
 
@@ -2412,6 +2716,38 @@ func validateIdentityCert(networkCert *shared.CertInfo, cert string) (fingerprin
 	return shared.CertFingerprint(x509Cert), nil
 }
 
+// verifyCertificateRenewalSignature checks that the given base64 encoded signature of the nonce was made with the
+// private key of the given certificate using SHA-256.
+func verifyCertificateRenewalSignature(cert *x509.Certificate, nonce string, signature string) error {
+	if signature == "" {
+		return api.NewStatusError(http.StatusBadRequest, "Must provide a signature of the nonce")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Failed decoding signature: %w", err)
+	}
+
+	var algorithm x509.SignatureAlgorithm
+	switch cert.PublicKeyAlgorithm {
+	case x509.ECDSA:
+		algorithm = x509.ECDSAWithSHA256
+	case x509.RSA:
+		algorithm = x509.SHA256WithRSA
+	case x509.Ed25519:
+		algorithm = x509.PureEd25519
+	default:
+		return api.StatusErrorf(http.StatusBadRequest, "Unsupported public key algorithm %q", cert.PublicKeyAlgorithm.String())
+	}
+
+	err = cert.CheckSignature(algorithm, []byte(nonce), sig)
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "The nonce was not signed with the private key of the new certificate: %w", err)
+	}
+
+	return nil
+}
+
 // updateIdentityCache reads all identities from the database and sets them in the identity.Cache.
 // The certificates in the local database are replaced with identities in the cluster database that
 // are of type api.IdentityTypeCertificateServer. This ensures that this cluster member is able to
@@ -0,0 +1,33 @@
+package identity
+
+import (
+	"github.com/canonical/lxd/shared/api"
+)
+
+// TokenBearerSCIM represents an identity that authenticates using a token issued by LXD.
+// The token is only valid for the SCIM API, which is used by an identity provider to provision OIDC identities and
+// identity provider groups. It does not have any permissions on the main API.
+type TokenBearerSCIM struct {
+	typeInfoCommon
+}
+
+// Name returns the name of the TokenBearerSCIM identity type.
+func (TokenBearerSCIM) Name() string {
+	return api.IdentityTypeBearerTokenSCIM
+}
+
+// Code returns the database code for TokenBearerSCIM.
+func (TokenBearerSCIM) Code() int64 {
+	return identityTypeBearerSCIM
+}
+
+// AuthenticationMethod indicates that identities of this type authenticate via bearer token.
+func (TokenBearerSCIM) AuthenticationMethod() string {
+	return api.AuthenticationMethodBearer
+}
+
+// IsCacheable returns true to indicate that this identity type requires some data to be stored in the cache.
+// In this case, the cache needs the identities' token secret.
+func (TokenBearerSCIM) IsCacheable() bool {
+	return true
+}
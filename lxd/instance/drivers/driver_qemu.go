@@ -4688,7 +4688,7 @@ func (d *qemu) addDriveConfig(busAllocate busAllocator, bootIndexes map[string]i
 				return errors.New("Failed getting QEMU device id")
 			}
 
-			err = m.SetBlockThrottle(qemuDevID, int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps))
+			err = m.SetBlockThrottle(qemuDevID, qemuBlockThrottle(driveConf.Limits))
 			if err != nil {
 				return fmt.Errorf("Failed applying limits for disk device %q: %w", driveConf.DevName, err)
 			}
@@ -4701,6 +4701,29 @@ func (d *qemu) addDriveConfig(busAllocate busAllocator, bootIndexes map[string]i
 	return monHook, nil
 }
 
+// qemuBlockThrottle converts disk limits into QEMU block throttling settings.
+// The limits of a throttle group are shared by all of its members, so when the limits of a volume place the disk in a
+// throttle group, only the volume limits are applied. This prevents the device limits of one disk from applying to the
+// other disks attaching the same volume.
+func qemuBlockThrottle(limits *deviceConfig.DiskLimits) qmp.BlockThrottle {
+	if limits.Group != "" {
+		return qmp.BlockThrottle{
+			Bytes:    int(limits.Bytes),
+			BytesMax: int(limits.BytesBurst),
+			IOPs:     int(limits.IOps),
+			IOPsMax:  int(limits.IOpsBurst),
+			Group:    limits.Group,
+		}
+	}
+
+	return qmp.BlockThrottle{
+		BytesRead:  int(limits.ReadBytes),
+		BytesWrite: int(limits.WriteBytes),
+		IOPsRead:   int(limits.ReadIOps),
+		IOPsWrite:  int(limits.WriteIOps),
+	}
+}
+
 // addNetDevConfig adds the qemu config required for adding a network device.
 // The qemuDev map is expected to be preconfigured with the settings for an existing port to use for the device.
 func (d *qemu) addNetDevConfig(busName string, busAllocate busAllocator, bootIndexes map[string]int, nicConfig []deviceConfig.RunConfigItem) (monitorHook, error) {
@@ -8726,7 +8749,7 @@ func (d *qemu) DeviceEventHandler(runConf *deviceConfig.RunConfig) error {
 		devID := qemuDeviceIDPrefix + filesystem.PathNameEncode(mount.DevName)
 
 		// Apply the limits.
-		err = m.SetBlockThrottle(devID, int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps))
+		err = m.SetBlockThrottle(devID, qemuBlockThrottle(mount.Limits))
 		if err != nil {
 			return fmt.Errorf("Failed applying limits for disk device %q: %w", mount.DevName, err)
 		}
@@ -1012,24 +1012,31 @@ func (m *Monitor) Eject(id string) error {
 	return nil
 }
 
+// BlockThrottle represents the I/O limits of a disk.
+// The combined Bytes and IOPs limits cannot be used together with their read/write counterparts.
+type BlockThrottle struct {
+	Bytes      int    `json:"bps"`
+	BytesRead  int    `json:"bps_rd"`
+	BytesWrite int    `json:"bps_wr"`
+	IOPs       int    `json:"iops"`
+	IOPsRead   int    `json:"iops_rd"`
+	IOPsWrite  int    `json:"iops_wr"`
+	BytesMax   int    `json:"bps_max,omitempty"`
+	IOPsMax    int    `json:"iops_max,omitempty"`
+	Group      string `json:"group,omitempty"`
+}
+
 // SetBlockThrottle applies an I/O limit on a disk.
-func (m *Monitor) SetBlockThrottle(id string, bytesRead int, bytesWrite int, iopsRead int, iopsWrite int) error {
+// Disks sharing the same throttle group have the limits applied to their combined I/O.
+func (m *Monitor) SetBlockThrottle(id string, limits BlockThrottle) error {
 	var args struct {
 		ID string `json:"id"`
 
-		Bytes      int `json:"bps"`
-		BytesRead  int `json:"bps_rd"`
-		BytesWrite int `json:"bps_wr"`
-		IOPs       int `json:"iops"`
-		IOPsRead   int `json:"iops_rd"`
-		IOPsWrite  int `json:"iops_wr"`
+		BlockThrottle
 	}
 
 	args.ID = id
-	args.BytesRead = bytesRead
-	args.BytesWrite = bytesWrite
-	args.IOPsRead = iopsRead
-	args.IOPsWrite = iopsWrite
+	args.BlockThrottle = limits
 
 	err := m.run("block_set_io_throttle", args, nil)
 	if err != nil {
@@ -218,6 +218,18 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf(api.ClusterEvacuateModeAuto, api.ClusterEvacuateModeMigrate, api.ClusterEvacuateModeLiveMigrate, api.ClusterEvacuateModeStop)),
 
+	// lxdmeta:generate(entities=instance; group=miscellaneous; key=cluster.rebalance)
+	// When enabled, the instance can be moved to another cluster member to even out the load of the cluster.
+	// Running instances are live-migrated if possible, otherwise they are stopped, moved and started again.
+	//
+	// See {ref}`cluster-rebalance` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether the instance can be moved by the cluster rebalancer
+	"cluster.rebalance": validate.Optional(validate.IsBool),
+
 	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	//
@@ -458,6 +470,13 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: The origin of the evacuated instance
 	"volatile.evacuate.origin": validate.IsAny,
 
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.rebalance.last_move)
+	// The time at which the instance was last moved by the cluster rebalancer.
+	// ---
+	//  type: string
+	//  shortdesc: When the instance was last moved by the rebalancer
+	"volatile.rebalance.last_move": validate.IsAny,
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.cluster.group)
 	// The target cluster group at instance creation or migration time. This is used during scheduling events such as evacuation to ensure the instance is placed correctly.
 	// ---
@@ -61,7 +61,7 @@ func instanceDelete(d *Daemon, r *http.Request) response.Response {
 	}
 
 	force := shared.IsTrue(r.FormValue("force"))
-	op, err := doInstanceDelete(opScheduler, s, name, projectName, force)
+	op, err := doInstanceDelete(opScheduler, s, name, projectName, force, true)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -73,7 +73,9 @@ func instanceDelete(d *Daemon, r *http.Request) response.Response {
 // If the instance is running and force is true, the instance is force stopped asynchronously
 // as part of the delete operation. If the instance is running and force is false, the request
 // fails before the operation is created.
-func doInstanceDelete(opScheduler operations.OperationScheduler, s *state.State, name string, projectName string, force bool) (*operations.Operation, error) {
+// If trash is true and the project has a deletion retention configured, the instance volume is moved
+// into the project trash rather than being deleted.
+func doInstanceDelete(opScheduler operations.OperationScheduler, s *state.State, name string, projectName string, force bool, trash bool) (*operations.Operation, error) {
 	inst, err := instance.LoadByProjectAndName(s, projectName, name)
 	if err != nil {
 		return nil, err
@@ -103,6 +105,18 @@ func doInstanceDelete(opScheduler operations.OperationScheduler, s *state.State,
 			}
 		}
 
+		// Ephemeral instances are never kept in the trash.
+		if trash && !inst.IsEphemeral() {
+			retention, err := projectDeletionRetention(ctx, s, projectName)
+			if err != nil {
+				return err
+			}
+
+			if retention != "" {
+				return instanceTrash(ctx, s, inst, retention, op)
+			}
+		}
+
 		return inst.Delete(ctx, false, "", op)
 	}
 
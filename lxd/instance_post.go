@@ -222,6 +222,19 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Moves to a linked cluster are driven by this member using the cluster link identity.
+	if req.ClusterLink != "" {
+		if !req.Migration {
+			return response.BadRequest(errors.New("A cluster link can only be set for migrations"))
+		}
+
+		if target != "" || req.Target != nil {
+			return response.BadRequest(errors.New("Cannot specify a target when migrating to a cluster link"))
+		}
+
+		return instancePostClusterLink(s, r, inst, req)
+	}
+
 	var targetGroupName string
 	after, ok := strings.CutPrefix(target, instancetype.TargetClusterGroupPrefix)
 	if ok {
@@ -454,6 +467,8 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			return response.InternalError(err)
 		}
 
+		ws.transferRate = req.TransferRate
+
 		run := func(ctx context.Context, op *operations.Operation) error {
 			// Migrations do not currently cancel via context.
 			// The only way to cancel them is by disconnecting the websocket.
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// instancePostClusterLink moves an instance to the cluster behind the cluster link named in the request.
+// The migration is negotiated directly with the linked cluster using the cluster link identity, so it doesn't
+// depend on the client staying connected. The instance is deleted locally once the linked cluster has it.
+func instancePostClusterLink(s *state.State, r *http.Request, inst instance.Instance, req api.InstancePost) response.Response {
+	// The instance is created on the linked cluster with the cluster link identity, so the caller must be allowed to
+	// edit the cluster link.
+	err := s.Authorizer.CheckPermission(r.Context(), entity.ClusterLinkURL(req.ClusterLink), auth.EntitlementCanEdit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The instance is deleted locally once moved.
+	err = s.Authorizer.CheckPermission(r.Context(), entity.InstanceURL(inst.Project().Name, inst.Name()), auth.EntitlementCanDelete)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Only allow moving to a project with the same name as a local project that the caller can create instances in, so
+	// that the cluster link identity is never used to write to projects that the caller has no access to.
+	targetProject := req.Project
+	if targetProject == "" {
+		targetProject = inst.Project().Name
+	}
+
+	err = s.Authorizer.CheckPermission(r.Context(), entity.ProjectURL(targetProject), auth.EntitlementCanCreateInstances)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.IsRunning() && !req.Live {
+		return response.BadRequest(errors.New("Instance must be stopped to move between clusters statelessly"))
+	}
+
+	if inst.IsRunning() && inst.Type() != instancetype.VM {
+		return response.BadRequest(errors.New("Live migration between clusters is only supported for virtual machines"))
+	}
+
+	var backups []string
+	var clusterLink *api.ClusterLink
+	var targetCert *x509.Certificate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		backups, err = tx.GetInstanceBackups(ctx, inst.Project().Name, inst.Name())
+		if err != nil {
+			return fmt.Errorf("Failed fetching instance's backups: %w", err)
+		}
+
+		_, clusterLink, targetCert, err = cluster.LoadClusterLinkAndCert(ctx, tx.Tx(), req.ClusterLink)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(backups) > 0 {
+		return response.BadRequest(errors.New("Instance has backups"))
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		return instanceMigrateClusterLink(ctx, s, op, inst, req, clusterLink, targetCert)
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: inst.Project().Name,
+		EntityURL:   api.NewURL().Path(version.APIVersion, "instances", inst.Name()).Project(inst.Project().Name),
+		Type:        operationtype.InstanceMigrate,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// instanceMigrateClusterLink push-migrates an instance to a linked cluster and deletes it locally on success.
+// The project, name, pool, profiles, config and devices of the new instance can be changed through the request.
+func instanceMigrateClusterLink(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance, req api.InstancePost, clusterLink *api.ClusterLink, targetCert *x509.Certificate) error {
+	targetProject := req.Project
+	if targetProject == "" {
+		targetProject = inst.Project().Name
+	}
+
+	client, err := cluster.ConnectCluster(ctx, *clusterLink, cluster.GetClusterLinkConnectionArgs(s.Endpoints.NetworkCert(), targetCert))
+	if err != nil {
+		return err
+	}
+
+	dstClient := client.UseProject(targetProject)
+
+	renderRes, _, err := inst.Render()
+	if err != nil {
+		return fmt.Errorf("Failed rendering instance %q: %w", inst.Name(), err)
+	}
+
+	apiInst, ok := renderRes.(*api.Instance)
+	if !ok {
+		return fmt.Errorf("Unexpected result from instance render for %q", inst.Name())
+	}
+
+	instancePut := apiInst.Writable()
+	if instancePut.Config == nil {
+		instancePut.Config = map[string]string{}
+	}
+
+	if instancePut.Devices == nil {
+		instancePut.Devices = map[string]map[string]string{}
+	}
+
+	maps.Copy(instancePut.Config, req.Config)
+	maps.Copy(instancePut.Devices, req.Devices)
+
+	if req.Profiles != nil {
+		instancePut.Profiles = req.Profiles
+	}
+
+	// Set the storage pool of the new instance, keeping the current root disk settings.
+	if req.Pool != "" {
+		rootDevKey, rootDev, err := api.GetRootDiskDevice(instancePut.Devices)
+		if errors.Is(err, api.ErrNoRootDisk) {
+			rootDevKey, rootDev, err = api.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
+		}
+
+		if err != nil {
+			return err
+		}
+
+		rootDev = maps.Clone(rootDev)
+		rootDev["pool"] = req.Pool
+		instancePut.Devices[rootDevKey] = rootDev
+	}
+
+	live := req.Live && inst.IsRunning()
+	instanceOnly := req.InstanceOnly || req.ContainerOnly //nolint:staticcheck,unused
+
+	// Set up a push-mode migration sink on the linked cluster, so that it doesn't need to reach back into this cluster.
+	destOp, err := dstClient.CreateInstance(api.InstancesPost{
+		Name:        req.Name,
+		InstancePut: instancePut,
+		Type:        api.InstanceType(apiInst.Type),
+		Source: api.InstanceSource{
+			Type:         api.SourceTypeMigration,
+			Mode:         "push",
+			Live:         live,
+			InstanceOnly: instanceOnly,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed creating instance on cluster link %q: %w", clusterLink.Name, err)
+	}
+
+	destOpAPI := destOp.Get()
+	destSecrets, err := destOpAPI.WebsocketSecrets()
+	if err != nil {
+		_ = destOp.Cancel()
+		return fmt.Errorf("Failed getting websocket secrets from cluster link %q: %w", clusterLink.Name, err)
+	}
+
+	ws, err := newMigrationSource(inst, live, instanceOnly, req.AllowInconsistent, "", &api.InstancePostTarget{
+		Operation:   destOp.URL().String(),
+		Websockets:  destSecrets,
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: targetCert.Raw})),
+	})
+	if err != nil {
+		_ = destOp.Cancel()
+		return err
+	}
+
+	ws.transferRate = req.TransferRate
+
+	// Migrations do not currently cancel via context, so disconnect the websocket if the context is cancelled.
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			ws.disconnect()
+		}
+	}()
+
+	err = ws.Do(ctx, s, op)
+	close(done)
+	if err != nil {
+		return fmt.Errorf("Failed migrating instance %q to cluster link %q: %w", inst.Name(), clusterLink.Name, err)
+	}
+
+	err = destOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed creating instance %q on cluster link %q: %w", req.Name, clusterLink.Name, err)
+	}
+
+	// The instance now runs on the linked cluster, remove it locally.
+	if inst.IsRunning() {
+		err = inst.Stop(ctx, false)
+		if err != nil {
+			return fmt.Errorf("Failed stopping instance %q after migration: %w", inst.Name(), err)
+		}
+	}
+
+	err = inst.Delete(ctx, true, "", nil)
+	if err != nil {
+		return fmt.Errorf("Failed deleting instance %q after migration: %w", inst.Name(), err)
+	}
+
+	logger.Info("Moved instance to linked cluster", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "clusterLink": clusterLink.Name, "targetProject": targetProject, "targetName": req.Name})
+
+	return nil
+}
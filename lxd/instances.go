@@ -31,7 +31,7 @@ var instancesCmd = APIEndpoint{
 	MetricsType:     entity.TypeInstance,
 	ProjectSpecific: true,
 
-	Get:  APIEndpointAction{Handler: instancesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+	Get:  APIEndpointAction{Handler: instancesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients, ClusterLinkViews: true},
 	Post: APIEndpointAction{Handler: instancesPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateInstances), ContentTypes: []string{"application/json", "application/octet-stream"}},
 	Put:  APIEndpointAction{Handler: instancesPut, AccessHandler: allowAuthenticated},
 }
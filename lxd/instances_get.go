@@ -68,6 +68,16 @@ func urlInstanceTypeDetect(r *http.Request) (instancetype.Type, error) {
 //      name: all-projects
 //      description: Retrieve instances from all projects
 //      type: boolean
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -126,6 +136,16 @@ func urlInstanceTypeDetect(r *http.Request) (instancetype.Type, error) {
 //      name: all-projects
 //      description: Retrieve instances from all projects
 //      type: boolean
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -188,6 +208,16 @@ func urlInstanceTypeDetect(r *http.Request) (instancetype.Type, error) {
 //      description: Recursion level (0, 1, 2)
 //      type: string
 //      example: 2
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -34,6 +34,7 @@ import (
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/scheduler"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/shared"
@@ -93,7 +94,7 @@ func ensureDownloadedImageFitWithinBudget(ctx context.Context, s *state.State, o
 	return imgDownloaded, nil
 }
 
-func createFromImage(r *http.Request, s *state.State, p api.Project, profiles []api.Profile, img *api.Image, imgAlias string, req *api.InstancesPost) response.Response {
+func createFromImage(r *http.Request, s *state.State, p api.Project, profiles []api.Profile, img *api.Image, imgAlias string, req *api.InstancesPost, schedulerReason string) response.Response {
 	if s.DB.Cluster.LocalNodeIsEvacuated() {
 		return response.Forbidden(errors.New("Cluster member is evacuated"))
 	}
@@ -156,6 +157,8 @@ func createFromImage(r *http.Request, s *state.State, p api.Project, profiles []
 		},
 	}
 
+	instancesPostSchedulerMetadata(args.Metadata, schedulerReason)
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
 	if err != nil {
 		return response.InternalError(err)
@@ -164,7 +167,7 @@ func createFromImage(r *http.Request, s *state.State, p api.Project, profiles []
 	return response.OperationResponse(op)
 }
 
-func createFromNone(r *http.Request, s *state.State, projectName string, profiles []api.Profile, req *api.InstancesPost) response.Response {
+func createFromNone(r *http.Request, s *state.State, projectName string, profiles []api.Profile, req *api.InstancesPost, schedulerReason string) response.Response {
 	if s.DB.Cluster.LocalNodeIsEvacuated() {
 		return response.Forbidden(errors.New("Cluster member is evacuated"))
 	}
@@ -217,6 +220,8 @@ func createFromNone(r *http.Request, s *state.State, projectName string, profile
 		},
 	}
 
+	instancesPostSchedulerMetadata(opArgs.Metadata, schedulerReason)
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, opArgs)
 	if err != nil {
 		return response.InternalError(err)
@@ -403,7 +408,7 @@ func prepareInstanceMigrationSink(ctx context.Context, s *state.State, projectNa
 	}, nil
 }
 
-func createFromMigration(r *http.Request, s *state.State, projectName string, profiles []api.Profile, req *api.InstancesPost, isClusterNotification bool) response.Response {
+func createFromMigration(r *http.Request, s *state.State, projectName string, profiles []api.Profile, req *api.InstancesPost, isClusterNotification bool, schedulerReason string) response.Response {
 	requestor, err := request.GetRequestor(r.Context())
 	if err == nil {
 		if requestor.Protocol == "" {
@@ -448,6 +453,8 @@ func createFromMigration(r *http.Request, s *state.State, projectName string, pr
 		opArgs.Class = operationtype.OperationClassTask
 	}
 
+	instancesPostSchedulerMetadata(opArgs.Metadata, schedulerReason)
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, opArgs)
 	if err != nil {
 		return response.InternalError(err)
@@ -459,7 +466,7 @@ func createFromMigration(r *http.Request, s *state.State, projectName string, pr
 
 // createFromConversion receives the root disk (container FS or VM block volume) from the client and creates an
 // instance from it. Conversion options also allow the uploaded image to be converted into a raw format.
-func createFromConversion(r *http.Request, s *state.State, projectName string, profiles []api.Profile, req *api.InstancesPost) response.Response {
+func createFromConversion(r *http.Request, s *state.State, projectName string, profiles []api.Profile, req *api.InstancesPost, schedulerReason string) response.Response {
 	if s.DB.Cluster.LocalNodeIsEvacuated() {
 		return response.Forbidden(errors.New("Cluster member is evacuated"))
 	}
@@ -554,6 +561,8 @@ func createFromConversion(r *http.Request, s *state.State, projectName string, p
 		ConnectHook: sink.Connect,
 	}
 
+	instancesPostSchedulerMetadata(opArgs.Metadata, schedulerReason)
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, opArgs)
 	if err != nil {
 		return response.InternalError(err)
@@ -563,7 +572,7 @@ func createFromConversion(r *http.Request, s *state.State, projectName string, p
 	return response.OperationResponse(op)
 }
 
-func createFromCopy(r *http.Request, s *state.State, projectName string, profiles []api.Profile, req *api.InstancesPost, targetMemberInfo *db.NodeInfo) response.Response {
+func createFromCopy(r *http.Request, s *state.State, projectName string, profiles []api.Profile, req *api.InstancesPost, targetMemberInfo *db.NodeInfo, schedulerReason string) response.Response {
 	if s.DB.Cluster.LocalNodeIsEvacuated() {
 		return response.Forbidden(errors.New("Cluster member is evacuated"))
 	}
@@ -649,7 +658,7 @@ func createFromCopy(r *http.Request, s *state.State, projectName string, profile
 
 			if sourcePoolName != destPoolName {
 				// Redirect to migration
-				return clusterCopyContainerInternal(r, s, source, projectName, profiles, req)
+				return clusterCopyContainerInternal(r, s, source, projectName, profiles, req, schedulerReason)
 			}
 
 			pool, err := storagePools.LoadByName(s, sourcePoolName)
@@ -666,7 +675,7 @@ func createFromCopy(r *http.Request, s *state.State, projectName string, profile
 			// as it's cheaper to perform the copy on the storage array directly without performing migration.
 			if !pool.Driver().Info().Remote {
 				// Redirect to migration
-				return clusterCopyContainerInternal(r, s, source, projectName, profiles, req)
+				return clusterCopyContainerInternal(r, s, source, projectName, profiles, req, schedulerReason)
 			}
 		}
 	}
@@ -818,6 +827,8 @@ func createFromCopy(r *http.Request, s *state.State, projectName string, profile
 		},
 	}
 
+	instancesPostSchedulerMetadata(opArgs.Metadata, schedulerReason)
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, opArgs)
 	if err != nil {
 		return response.InternalError(err)
@@ -1439,6 +1450,10 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 	var targetMemberInfo *db.NodeInfo
 	var targetGroupName string
 	var placementGroupName string
+	var schedulerStrategy string
+	var schedulerConfig map[string]string
+	var schedulerDevices deviceConfig.Devices
+	var schedulerReason string
 
 	// Set to true once we find that the request is currently handled on a member which isn't hosting the source instance.
 	sourceInstOnDifferentMember := false
@@ -1702,11 +1717,27 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 
 			expandedConfig := instancetype.ExpandInstanceConfig(s.GlobalConfig.Dump(), req.Config, profiles)
-			placementGroupName = expandedConfig["placement.group"]
-			targetMemberInfo, err = instancesPostSelectClusterMember(ctx, tx, placementGroupName, candidateMembers, targetProject.Name)
+			placementGroupName, err = placement.GroupName(ctx, tx, targetProject.Name, expandedConfig)
 			if err != nil {
 				return err
 			}
+
+			// Resource-aware strategies need the resources of the cluster members, which are retrieved outside of the transaction.
+			if s.GlobalConfig.ClusterSchedulerStrategy() != scheduler.StrategyInstances {
+				candidateMembers, err = instancesPostFilterClusterMembers(ctx, tx, placementGroupName, candidateMembers, targetProject.Name)
+				if err != nil {
+					return err
+				}
+
+				schedulerStrategy = s.GlobalConfig.ClusterSchedulerStrategy()
+				schedulerConfig = expandedConfig
+				schedulerDevices = instancetype.ExpandInstanceDevices(deviceConfig.NewDevices(req.Devices), profiles)
+			} else {
+				targetMemberInfo, err = instancesPostSelectClusterMember(ctx, tx, placementGroupName, candidateMembers, targetProject.Name)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		if !clusterNotification {
@@ -1732,6 +1763,21 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	if schedulerStrategy != "" {
+		instType, err := instancetype.New(string(req.Type))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		var decision *scheduler.Decision
+		targetMemberInfo, decision, err = instancesSchedulerSelect(r.Context(), s, schedulerStrategy, candidateMembers, instType, schedulerConfig, schedulerDevices)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		schedulerReason = decision.Reason
+	}
+
 	poolSupportsInternalCopy := false
 
 	if s.ServerClustered && req.Source.Type == api.SourceTypeCopy && sourceInstPoolName != "" {
@@ -1795,7 +1841,16 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
+		// The placement was decided by this cluster member, so report its reason in the forwarded operation.
 		opAPI := op.Get()
+		if schedulerReason != "" {
+			if opAPI.Metadata == nil {
+				opAPI.Metadata = map[string]any{}
+			}
+
+			instancesPostSchedulerMetadata(opAPI.Metadata, schedulerReason)
+		}
+
 		return response.ForwardedOperationResponse(&opAPI)
 	}
 
@@ -1804,30 +1859,53 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 
 	switch req.Source.Type {
 	case api.SourceTypeImage:
-		return createFromImage(r, s, *targetProject, profiles, sourceImage, sourceImageRef, &req)
+		return createFromImage(r, s, *targetProject, profiles, sourceImage, sourceImageRef, &req, schedulerReason)
 	case api.SourceTypeNone:
-		return createFromNone(r, s, targetProjectName, profiles, &req)
+		return createFromNone(r, s, targetProjectName, profiles, &req, schedulerReason)
 	case api.SourceTypeMigration:
-		return createFromMigration(r, s, targetProjectName, profiles, &req, clusterNotification)
+		return createFromMigration(r, s, targetProjectName, profiles, &req, clusterNotification, schedulerReason)
 	case api.SourceTypeConversion:
-		return createFromConversion(r, s, targetProjectName, profiles, &req)
+		return createFromConversion(r, s, targetProjectName, profiles, &req, schedulerReason)
 	case api.SourceTypeCopy:
 		// Inside the copy handler we perform additional checks whether or not we can actually do a copy or need to fall back to migration.
 		// This is the case when e.g. different pools are used for source and target instance.
-		return createFromCopy(r, s, targetProjectName, profiles, &req, targetMemberInfo)
+		return createFromCopy(r, s, targetProjectName, profiles, &req, targetMemberInfo, schedulerReason)
 	default:
 		return response.BadRequest(fmt.Errorf("Unknown source type %s", req.Source.Type))
 	}
 }
 
+// instancesPostSchedulerMetadata adds the reason given by the instance scheduler for the placement of the instance to the operation metadata.
+func instancesPostSchedulerMetadata(metadata map[string]any, reason string) {
+	if reason != "" {
+		metadata[api.MetadataScheduler] = reason
+	}
+}
+
 // instancesPostSelectClusterMember determines which cluster member to use for placing an instance during creation or migration.
 // It first checks whether the instance belongs to a placement group and, if so, applies the placement group’s policy and rigor to filter the available members.
 // Among the remaining candidates, the member with the fewest existing instances is selected.
 // If the instance does not belong to a placement group, the member with the fewest instances is chosen from all candidates.
 func instancesPostSelectClusterMember(ctx context.Context, tx *db.ClusterTx, placementGroupName string, candidateMembers []db.NodeInfo, projectName string) (*db.NodeInfo, error) {
+	filteredCandidates, err := instancesPostFilterClusterMembers(ctx, tx, placementGroupName, candidateMembers, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Early return if only a single candidate.
+	if len(filteredCandidates) == 1 {
+		return &filteredCandidates[0], nil
+	}
+
+	// Use filtered candidates to pick the node with least instances.
+	return tx.GetNodeWithLeastInstances(ctx, filteredCandidates)
+}
+
+// instancesPostFilterClusterMembers returns the candidate cluster members allowed by the placement group of the instance, if any.
+func instancesPostFilterClusterMembers(ctx context.Context, tx *db.ClusterTx, placementGroupName string, candidateMembers []db.NodeInfo, projectName string) ([]db.NodeInfo, error) {
 	// Check if instance is using a placement group.
 	if placementGroupName == "" {
-		return tx.GetNodeWithLeastInstances(ctx, candidateMembers)
+		return candidateMembers, nil
 	}
 
 	placementGroup, err := dbCluster.GetPlacementGroup(ctx, tx.Tx(), placementGroupName, projectName)
@@ -1842,18 +1920,7 @@ func instancesPostSelectClusterMember(ctx context.Context, tx *db.ClusterTx, pla
 
 	apiPlacementGroup := placementGroup.ToAPI(configs)
 
-	filteredCandidates, err := placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, false)
-	if err != nil {
-		return nil, err
-	}
-
-	// Early return if only a single candidate.
-	if len(filteredCandidates) == 1 {
-		return &filteredCandidates[0], nil
-	}
-
-	// Use filtered candidates to pick the node with least instances.
-	return tx.GetNodeWithLeastInstances(ctx, filteredCandidates)
+	return placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, false)
 }
 
 func instanceFindStoragePool(s *state.State, projectName string, req *api.InstancesPost) (storagePool string, storagePoolProfile string, localRootDiskDeviceKey string, localRootDiskDevice map[string]string, err error) {
@@ -1931,7 +1998,7 @@ func instanceFindStoragePool(s *state.State, projectName string, req *api.Instan
 	return storagePool, storagePoolProfile, localRootDiskDeviceKey, localRootDiskDevice, nil
 }
 
-func clusterCopyContainerInternal(r *http.Request, s *state.State, source instance.Instance, projectName string, profiles []api.Profile, req *api.InstancesPost) response.Response {
+func clusterCopyContainerInternal(r *http.Request, s *state.State, source instance.Instance, projectName string, profiles []api.Profile, req *api.InstancesPost, schedulerReason string) response.Response {
 	// Locate the source of the container
 	var nodeAddress string
 	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -2012,7 +2079,7 @@ func clusterCopyContainerInternal(r *http.Request, s *state.State, source instan
 	req.Source.Project = ""
 
 	// Run the migration
-	return createFromMigration(r, s, projectName, profiles, req, false)
+	return createFromMigration(r, s, projectName, profiles, req, false, schedulerReason)
 }
 
 // instanceCreateFinish finalizes the creation process of an instance by starting it based on
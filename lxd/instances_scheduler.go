@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance/drivers"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/resources"
+	"github.com/canonical/lxd/lxd/scheduler"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// instancesSchedulerRequest returns the resources requested by an instance from its expanded configuration and devices.
+// Virtual machines without CPU or memory limits request the default amount of CPUs and memory of virtual machines.
+func instancesSchedulerRequest(instType instancetype.Type, config map[string]string, devices deviceConfig.Devices) (scheduler.Request, error) {
+	req := scheduler.Request{}
+
+	limitsCPU := config["limits.cpu"]
+	if limitsCPU == "" && instType == instancetype.VM {
+		limitsCPU = strconv.Itoa(drivers.QEMUDefaultCPUCores)
+	}
+
+	if limitsCPU != "" {
+		if strings.ContainsAny(limitsCPU, ",-") {
+			cpus, err := resources.ParseCpuset(limitsCPU)
+			if err != nil {
+				return req, err
+			}
+
+			req.CPUs = float64(len(cpus))
+		} else {
+			cpus, err := strconv.ParseFloat(limitsCPU, 64)
+			if err != nil {
+				return req, fmt.Errorf("Invalid limits.cpu value %q: %w", limitsCPU, err)
+			}
+
+			req.CPUs = cpus
+		}
+	}
+
+	limitsMemory := config["limits.memory"]
+	if limitsMemory == "" && instType == instancetype.VM {
+		limitsMemory = drivers.QEMUDefaultMemSize
+	}
+
+	if strings.HasSuffix(limitsMemory, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(limitsMemory, "%"), 64)
+		if err != nil {
+			return req, fmt.Errorf("Invalid limits.memory value %q: %w", limitsMemory, err)
+		}
+
+		req.MemoryPercent = percent
+	} else if limitsMemory != "" {
+		memory, err := units.ParseByteSizeString(limitsMemory)
+		if err != nil {
+			return req, fmt.Errorf("Invalid limits.memory value %q: %w", limitsMemory, err)
+		}
+
+		req.Memory = memory
+	}
+
+	for _, device := range devices {
+		switch device["type"] {
+		case "gpu":
+			req.GPUs++
+		case "disk":
+			if device["path"] != "/" || device["size"] == "" {
+				continue
+			}
+
+			size, err := units.ParseByteSizeString(device["size"])
+			if err != nil {
+				return req, fmt.Errorf("Invalid root disk size %q: %w", device["size"], err)
+			}
+
+			req.Disk = size
+		}
+	}
+
+	return req, nil
+}
+
+// instancesSchedulerMembers returns the capacity of the candidate cluster members for the scheduler.
+// CPU and memory capacities are multiplied by the lowest overcommit ratio of the cluster groups of each member and
+// the resources allocated to existing instances are deducted. The disk capacity is the one of the given storage pool.
+// Cluster members whose resources cannot be retrieved are skipped.
+func instancesSchedulerMembers(ctx context.Context, s *state.State, candidates []db.NodeInfo, poolName string) ([]scheduler.Member, error) {
+	allocations := map[string][]scheduler.Request{}
+	groupConfigs := map[string]map[string]string{}
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		groups, err := dbCluster.GetClusterGroups(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster groups: %w", err)
+		}
+
+		configs, err := dbCluster.ClusterGroupsConfigStore().GetAll(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster group configuration: %w", err)
+		}
+
+		for _, group := range groups {
+			groupConfigs[group.Name] = configs[int64(group.ID)]
+		}
+
+		globalConfig := s.GlobalConfig.Dump()
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			config := instancetype.ExpandInstanceConfig(globalConfig, inst.Config, inst.Profiles)
+			devices := instancetype.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+			req, err := instancesSchedulerRequest(inst.Type, config, devices)
+			if err != nil {
+				logger.Warn("Failed getting instance resources for scheduling", logger.Ctx{"project": inst.Project, "instance": inst.Name, "err": err})
+				return nil
+			}
+
+			allocations[inst.Node] = append(allocations[inst.Node], req)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*scheduler.Member, len(candidates))
+
+	var wg sync.WaitGroup
+	for i, candidate := range candidates {
+		wg.Go(func() {
+			member, err := instancesSchedulerMember(ctx, s, candidate, poolName)
+			if err != nil {
+				logger.Warn("Skipping cluster member for scheduling", logger.Ctx{"member": candidate.Name, "err": err})
+				return
+			}
+
+			cpuRatio, memoryRatio := 1.0, 1.0
+			for j, groupName := range candidate.Groups {
+				groupCPURatio, groupMemoryRatio := 1.0, 1.0
+				value := groupConfigs[groupName]["scheduler.overcommit.cpu"]
+				if value != "" {
+					groupCPURatio, _ = scheduler.ParseOvercommit(value)
+				}
+
+				value = groupConfigs[groupName]["scheduler.overcommit.memory"]
+				if value != "" {
+					groupMemoryRatio, _ = scheduler.ParseOvercommit(value)
+				}
+
+				if j == 0 || groupCPURatio < cpuRatio {
+					cpuRatio = groupCPURatio
+				}
+
+				if j == 0 || groupMemoryRatio < memoryRatio {
+					memoryRatio = groupMemoryRatio
+				}
+			}
+
+			member.CPUs *= cpuRatio
+			member.Memory = int64(float64(member.Memory) * memoryRatio)
+			member.Instances = len(allocations[candidate.Name])
+
+			for _, req := range allocations[candidate.Name] {
+				member.UsedCPUs += req.CPUs
+				member.UsedGPUs += req.GPUs
+				if req.MemoryPercent > 0 {
+					member.UsedMemory += int64(float64(member.Memory) * req.MemoryPercent / 100)
+				} else {
+					member.UsedMemory += req.Memory
+				}
+			}
+
+			members[i] = member
+		})
+	}
+
+	wg.Wait()
+
+	result := make([]scheduler.Member, 0, len(members))
+	for _, member := range members {
+		if member != nil {
+			result = append(result, *member)
+		}
+	}
+
+	return result, nil
+}
+
+// instancesSchedulerMember returns the resources of a cluster member, using the local resources for the local member.
+func instancesSchedulerMember(ctx context.Context, s *state.State, candidate db.NodeInfo, poolName string) (*scheduler.Member, error) {
+	var res *api.Resources
+	var poolRes *api.ResourcesStoragePool
+	var err error
+
+	if candidate.Name == s.ServerName {
+		res, err = resources.GetResources()
+		if err != nil {
+			return nil, err
+		}
+
+		if poolName != "" {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				return nil, err
+			}
+
+			poolRes, err = pool.GetResources()
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		client, err := cluster.Connect(ctx, candidate.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err = client.GetServerResources()
+		if err != nil {
+			return nil, err
+		}
+
+		if poolName != "" {
+			poolRes, err = client.GetStoragePoolResources(poolName)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	member := &scheduler.Member{
+		Name:   candidate.Name,
+		CPUs:   float64(res.CPU.Total),
+		Memory: int64(res.Memory.Total),
+		GPUs:   int(res.GPU.Total),
+	}
+
+	if poolRes != nil {
+		member.Disk = int64(poolRes.Space.Total)
+		member.UsedDisk = int64(poolRes.Space.Used)
+	}
+
+	return member, nil
+}
+
+// instancesSchedulerSelect selects a cluster member for a new instance among the candidates using the given strategy.
+func instancesSchedulerSelect(ctx context.Context, s *state.State, strategy string, candidates []db.NodeInfo, instType instancetype.Type, config map[string]string, devices deviceConfig.Devices) (*db.NodeInfo, *scheduler.Decision, error) {
+	req, err := instancesSchedulerRequest(instType, config, devices)
+	if err != nil {
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Failed getting requested resources: %w", err)
+	}
+
+	poolName := ""
+	_, rootDisk, err := api.GetRootDiskDevice(devices.CloneNative())
+	if err == nil {
+		poolName = rootDisk["pool"]
+	}
+
+	members, err := instancesSchedulerMembers(ctx, s, candidates, poolName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decision, err := scheduler.Select(strategy, members, req)
+	if err != nil {
+		return nil, nil, api.StatusErrorf(http.StatusConflict, "Failed scheduling instance: %w", err)
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Name == decision.Member {
+			logger.Debug("Scheduled instance", logger.Ctx{"member": decision.Member, "strategy": strategy, "candidates": decision.Candidates})
+			return &candidate, decision, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("Selected cluster member %q is not a candidate", decision.Member)
+}
@@ -16,6 +16,15 @@ const (
 	ClusterTokenCreated       = ClusterAction(api.EventLifecycleClusterTokenCreated)
 )
 
+// All supported lifecycle events for rolling cluster maintenance.
+const (
+	ClusterMaintenanceStarted   = ClusterAction(api.EventLifecycleClusterMaintenanceStarted)
+	ClusterMaintenancePaused    = ClusterAction(api.EventLifecycleClusterMaintenancePaused)
+	ClusterMaintenanceResumed   = ClusterAction(api.EventLifecycleClusterMaintenanceResumed)
+	ClusterMaintenanceAborted   = ClusterAction(api.EventLifecycleClusterMaintenanceAborted)
+	ClusterMaintenanceCompleted = ClusterAction(api.EventLifecycleClusterMaintenanceCompleted)
+)
+
 // Event creates the lifecycle event for an action on a cluster.
 func (a ClusterAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
 	u := api.NewURL().Path(version.APIVersion, "cluster", name)
@@ -12,6 +12,7 @@ type ClusterMemberAction string
 const (
 	ClusterMemberAdded     = ClusterMemberAction(api.EventLifecycleClusterMemberAdded)
 	ClusterMemberEvacuated = ClusterMemberAction(api.EventLifecycleClusterMemberEvacuated)
+	ClusterMemberFenced    = ClusterMemberAction(api.EventLifecycleClusterMemberFenced)
 	ClusterMemberHealed    = ClusterMemberAction(api.EventLifecycleClusterMemberHealed)
 	ClusterMemberRemoved   = ClusterMemberAction(api.EventLifecycleClusterMemberRemoved)
 	ClusterMemberRenamed   = ClusterMemberAction(api.EventLifecycleClusterMemberRenamed)
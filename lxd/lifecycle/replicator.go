@@ -13,11 +13,12 @@ type ReplicatorAction string
 
 // All supported lifecycle events for replicators.
 const (
-	ReplicatorCreated = ReplicatorAction(api.EventLifecycleReplicatorCreated)
-	ReplicatorDeleted = ReplicatorAction(api.EventLifecycleReplicatorDeleted)
-	ReplicatorRenamed = ReplicatorAction(api.EventLifecycleReplicatorRenamed)
-	ReplicatorRun     = ReplicatorAction(api.EventLifecycleReplicatorRun)
-	ReplicatorUpdated = ReplicatorAction(api.EventLifecycleReplicatorUpdated)
+	ReplicatorCreated  = ReplicatorAction(api.EventLifecycleReplicatorCreated)
+	ReplicatorDeleted  = ReplicatorAction(api.EventLifecycleReplicatorDeleted)
+	ReplicatorFailover = ReplicatorAction(api.EventLifecycleReplicatorFailover)
+	ReplicatorRenamed  = ReplicatorAction(api.EventLifecycleReplicatorRenamed)
+	ReplicatorRun      = ReplicatorAction(api.EventLifecycleReplicatorRun)
+	ReplicatorUpdated  = ReplicatorAction(api.EventLifecycleReplicatorUpdated)
 )
 
 // Event creates the lifecycle event for an action on a replicator.
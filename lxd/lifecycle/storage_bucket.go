@@ -16,7 +16,10 @@ type StorageBucketAction string
 // StorageBucketKeyAction represents a lifecycle event action for storage bucket keys.
 type StorageBucketKeyAction string
 
-// All supported lifecycle events for storage buckets and keys.
+// StorageBucketObjectAction represents a lifecycle event action for storage bucket objects.
+type StorageBucketObjectAction string
+
+// All supported lifecycle events for storage buckets, keys and objects.
 const (
 	StorageBucketCreated    = StorageBucketAction(api.EventLifecycleStorageBucketCreated)
 	StorageBucketDeleted    = StorageBucketAction(api.EventLifecycleStorageBucketDeleted)
@@ -24,6 +27,9 @@ const (
 	StorageBucketKeyCreated = StorageBucketKeyAction(api.EventLifecycleStorageBucketKeyCreated)
 	StorageBucketKeyDeleted = StorageBucketKeyAction(api.EventLifecycleStorageBucketKeyDeleted)
 	StorageBucketKeyUpdated = StorageBucketKeyAction(api.EventLifecycleStorageBucketKeyUpdated)
+
+	StorageBucketObjectCreated = StorageBucketObjectAction(api.EventLifecycleStorageBucketObjectCreated)
+	StorageBucketObjectDeleted = StorageBucketObjectAction(api.EventLifecycleStorageBucketObjectDeleted)
 )
 
 // Event creates the lifecycle event for an action on a storage bucket.
@@ -49,3 +55,15 @@ func (a StorageBucketKeyAction) Event(pool pool, projectName string, bucketName
 		Requestor: requestor,
 	}
 }
+
+// Event creates the lifecycle event for an action on a storage bucket object.
+func (a StorageBucketObjectAction) Event(pool pool, projectName string, bucketName string, objectName string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "storage-pools", pool.Name(), "buckets", bucketName, "objects", objectName).Project(projectName)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
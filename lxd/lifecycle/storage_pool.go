@@ -10,9 +10,11 @@ type StoragePoolAction string
 
 // All supported lifecycle events for storage pools.
 const (
-	StoragePoolCreated = StoragePoolAction(api.EventLifecycleStoragePoolCreated)
-	StoragePoolDeleted = StoragePoolAction(api.EventLifecycleStoragePoolDeleted)
-	StoragePoolUpdated = StoragePoolAction(api.EventLifecycleStoragePoolUpdated)
+	StoragePoolCreated       = StoragePoolAction(api.EventLifecycleStoragePoolCreated)
+	StoragePoolDeleted       = StoragePoolAction(api.EventLifecycleStoragePoolDeleted)
+	StoragePoolUpdated       = StoragePoolAction(api.EventLifecycleStoragePoolUpdated)
+	StoragePoolUsageWarning  = StoragePoolAction(api.EventLifecycleStoragePoolUsageWarning)
+	StoragePoolUsageCritical = StoragePoolAction(api.EventLifecycleStoragePoolUsageCritical)
 )
 
 // Event creates the lifecycle event for an action on an storage pool.
@@ -110,6 +110,10 @@ func main() {
 	activateifneededCmd := cmdActivateifneeded{global: &globalCmd}
 	app.AddCommand(activateifneededCmd.command())
 
+	// audit sub-command
+	auditCmd := cmdAudit{global: &globalCmd}
+	app.AddCommand(auditCmd.Command())
+
 	// callhook sub-command
 	callhookCmd := cmdCallhook{global: &globalCmd}
 	app.AddCommand(callhookCmd.Command())
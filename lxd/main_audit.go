@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/audit"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/sys"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdAudit struct {
+	global *cmdGlobal
+}
+
+// Command returns a subcommand for inspecting the audit log.
+func (c *cmdAudit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "audit"
+	cmd.Short = "Audit log commands"
+	cmd.Long = `Description:
+  Tools for inspecting the audit log of mutating API calls.
+`
+	// Verify the audit log.
+	verify := cmdAuditVerify{global: c.global}
+	cmd.AddCommand(verify.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+type cmdAuditVerify struct {
+	global *cmdGlobal
+
+	flagDir      string
+	flagKey      string
+	flagLastHash string
+}
+
+// Command returns a command for verifying the audit log.
+func (c *cmdAuditVerify) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "verify"
+	cmd.Short = "Verify the integrity of the audit log"
+	cmd.Long = `Description:
+  Verify the integrity of the audit log
+
+  This checks the hash chain of the audit log of this server, including rotated files.
+  It reports the first record that was modified, removed, or reordered.
+
+  The records are authenticated with a key stored in the cluster database. Unless the key
+  is passed with --key, it is read from the running daemon.
+`
+	cmd.RunE = c.Run
+	cmd.Flags().StringVar(&c.flagDir, "dir", "", cli.FormatStringFlagLabel("Directory containing the audit log (defaults to the audit directory of LXD)"))
+	cmd.Flags().StringVar(&c.flagKey, "key", "", cli.FormatStringFlagLabel("Base64 encoded audit log key (defaults to the key of the running daemon)"))
+	cmd.Flags().StringVar(&c.flagLastHash, "last-hash", "", cli.FormatStringFlagLabel("Hash of a previously verified record that must still be present, to detect records removed from the end of the log"))
+
+	return cmd
+}
+
+// Run executes the command for verifying the audit log.
+func (c *cmdAuditVerify) Run(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errors.New("This command doesn't take any arguments")
+	}
+
+	dir := c.flagDir
+	if dir == "" {
+		dir = filepath.Join(sys.DefaultOS().VarDir, "audit")
+	}
+
+	files, err := audit.Files(dir)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("No audit log found in %q", dir)
+	}
+
+	key, err := c.key()
+	if err != nil {
+		return err
+	}
+
+	result, err := audit.Verify(dir, key)
+	if err != nil {
+		if result == nil {
+			return err
+		}
+
+		return fmt.Errorf("Audit log verification failed after %d valid records: %w", result.Records, err)
+	}
+
+	if c.flagLastHash != "" && c.flagLastHash != result.LastHash {
+		found := false
+		records, err := audit.Query(dir, audit.Filter{})
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			if record.Hash == c.flagLastHash {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("Audit log verification failed: No record with hash %q found, records may have been removed", c.flagLastHash)
+		}
+	}
+
+	fmt.Printf("Verified %d records in %d files (sequence %d to %d)\n", result.Records, result.Files, result.FirstSequence, result.LastSequence)
+	if result.FirstSequence > 1 {
+		fmt.Printf("Records before sequence %d were removed by rotation\n", result.FirstSequence)
+	}
+
+	fmt.Printf("Last hash: %s\n", result.LastHash)
+
+	return nil
+}
+
+// key returns the key of the audit log, either from the command line or from the cluster database of the running
+// daemon.
+func (c *cmdAuditVerify) key() ([]byte, error) {
+	encodedKey := c.flagKey
+	if encodedKey == "" {
+		secretType, err := dbCluster.SecretTypeAuditKey.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := lxd.ConnectLXDUnix("", &lxd.ConnectionArgs{SkipGetServer: true})
+		if err != nil {
+			return nil, fmt.Errorf("Failed connecting to LXD to get the audit log key (use --key if LXD is not running): %w", err)
+		}
+
+		data := internalSQLQuery{
+			Database: "global",
+			Query:    fmt.Sprintf("SELECT value FROM secrets WHERE type = %d", secretType),
+		}
+
+		response, _, err := d.RawQuery(http.MethodPost, "/internal/sql", data, "")
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting the audit log key: %w", err)
+		}
+
+		batch := internalSQLBatch{}
+		err = json.Unmarshal(response.Metadata, &batch)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing the audit log key: %w", err)
+		}
+
+		if len(batch.Results) != 1 || len(batch.Results[0].Rows) != 1 || len(batch.Results[0].Rows[0]) != 1 {
+			return nil, errors.New("Failed getting the audit log key: Expected exactly one key")
+		}
+
+		var ok bool
+		encodedKey, ok = batch.Results[0].Rows[0][0].(string)
+		if !ok {
+			return nil, errors.New("Failed getting the audit log key: Unexpected key type")
+		}
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid audit log key: %w", err)
+	}
+
+	return key, nil
+}
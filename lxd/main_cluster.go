@@ -73,6 +73,14 @@ func (c *cmdCluster) Command() *cobra.Command {
 	clusterShow := cmdClusterShow{global: c.global}
 	cmd.AddCommand(clusterShow.Command())
 
+	// Back up the cluster database.
+	backupDatabase := cmdClusterBackupDatabase{global: c.global}
+	cmd.AddCommand(backupDatabase.Command())
+
+	// Restore the cluster database.
+	restoreDatabase := cmdClusterRestoreDatabase{global: c.global}
+	cmd.AddCommand(restoreDatabase.Command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
@@ -540,3 +548,119 @@ func (c *cmdClusterRemoveRaftNode) Run(cmd *cobra.Command, args []string) error
 
 	return nil
 }
+
+type cmdClusterBackupDatabase struct {
+	global *cmdGlobal
+}
+
+// Command returns a command for taking a backup of the cluster database.
+func (c *cmdClusterBackupDatabase) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "backup-db"
+	cmd.Short = "Take a backup of the cluster database"
+	cmd.Long = `Description:
+  Take a consistent snapshot of the cluster database.
+
+  The backup is written to the database-backups directory of this cluster member
+  and can be restored with "lxd cluster restore-db".
+`
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run executes the command for taking a backup of the cluster database.
+func (c *cmdClusterBackupDatabase) Run(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		_ = cmd.Help()
+		return errors.New("Too many arguments")
+	}
+
+	client, err := lxd.ConnectLXDUnix("", nil)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to LXD daemon: %w", err)
+	}
+
+	resp, _, err := client.RawQuery(http.MethodPost, "/internal/cluster/database-backup", nil, "")
+	if err != nil {
+		return err
+	}
+
+	backup := internalClusterDatabaseBackup{}
+	err = resp.MetadataAsStruct(&backup)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(backup.Path)
+
+	return nil
+}
+
+const restoreDatabasePrompt = `You should run this command only if you are *absolutely* certain that this is
+the only database member left in your cluster AND that other database members will
+never come back (i.e. their LXD daemon will not ever be started again), or if the
+cluster database must be rolled back after a failed upgrade.
+
+This will replace the whole content of the cluster database with the backup, which is
+loaded the next time LXD starts. Any change made since the backup was taken is lost.
+
+If clustered, this will also make this LXD server the only member of the cluster, as
+done by "lxd cluster recover-from-quorum-loss".
+
+See https://canonical.com/lxd/docs/latest/howto/cluster_recover/#restore-the-cluster-database-from-a-backup
+for more info.`
+
+type cmdClusterRestoreDatabase struct {
+	global             *cmdGlobal
+	flagNonInteractive bool
+}
+
+// Command returns a command for restoring the cluster database from a backup.
+func (c *cmdClusterRestoreDatabase) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "restore-db <backup>"
+	cmd.Short = "Restore the cluster database from a backup"
+	cmd.Long = `Description:
+  Restore the cluster database from a backup taken by "lxd cluster backup-db" or
+  by the cluster.database_backup.schedule server configuration.
+
+  The LXD daemon must be stopped. The backup is loaded the next time it starts.
+`
+	cmd.RunE = c.Run
+
+	cmd.Flags().BoolVarP(&c.flagNonInteractive, "quiet", "q", false, "Do not require user confirmation")
+
+	return cmd
+}
+
+// Run executes the command for restoring the cluster database from a backup.
+func (c *cmdClusterRestoreDatabase) Run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		_ = cmd.Help()
+		return errors.New("Missing required arguments")
+	}
+
+	// Make sure that the daemon is not running.
+	_, err := lxd.ConnectLXDUnix("", nil)
+	if err == nil {
+		return errors.New("The LXD daemon is running, please stop it first.")
+	}
+
+	// Prompt for confirmation unless --quiet was passed.
+	if !c.flagNonInteractive {
+		err := promptConfirmation(restoreDatabasePrompt, "Restore")
+		if err != nil {
+			return err
+		}
+	}
+
+	os := sys.DefaultOS()
+
+	db, err := db.OpenNode(filepath.Join(os.VarDir, "database"), nil)
+	if err != nil {
+		return fmt.Errorf("Failed opening local database: %w", err)
+	}
+
+	return cluster.RestoreDatabase(db, args[0])
+}
@@ -94,6 +94,7 @@ func (m *MetricSet) String() string {
 		GoHeapObjects,
 		Instances,
 		APIOngoingRequests,
+		ReplicatorLagSeconds,
 	}
 
 	for _, metricType := range metricTypes {
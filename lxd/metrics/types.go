@@ -148,6 +148,8 @@ const (
 	OperationsTotal
 	// ProcsTotal represents the number of running processes.
 	ProcsTotal
+	// ReplicatorLagSeconds represents the replication lag of an instance in seconds.
+	ReplicatorLagSeconds
 	// UptimeSeconds represents the daemon uptime in seconds.
 	UptimeSeconds
 	// WarningsTotal represents the number of active warnings.
@@ -221,6 +223,7 @@ var MetricNames = map[MetricType]string{
 	NetworkTransmitPacketsTotal: "lxd_network_transmit_packets_total",
 	OperationsTotal:             "lxd_operations_total",
 	ProcsTotal:                  "lxd_procs_total",
+	ReplicatorLagSeconds:        "lxd_replicator_lag_seconds",
 	UptimeSeconds:               "lxd_uptime_seconds",
 	WarningsTotal:               "lxd_warnings_total",
 	Instances:                   "lxd_instances",
@@ -293,6 +296,7 @@ var MetricHeaders = map[MetricType]string{
 	NetworkTransmitPacketsTotal: "# HELP lxd_network_transmit_packets_total The amount of transmitted packets on a given interface.",
 	OperationsTotal:             "# HELP lxd_operations_total The number of running operations",
 	ProcsTotal:                  "# HELP lxd_procs_total The number of running processes.",
+	ReplicatorLagSeconds:        "# HELP lxd_replicator_lag_seconds The replication lag of an instance in seconds.",
 	UptimeSeconds:               "# HELP lxd_uptime_seconds The daemon uptime in seconds.",
 	WarningsTotal:               "# HELP lxd_warnings_total The number of active warnings.",
 	Instances:                   "# HELP lxd_instances The number of instances.",
@@ -9,6 +9,7 @@ import (
 	"context"
 	"crypto/subtle"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/migration"
 	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
 )
 
@@ -35,6 +37,21 @@ type migrationFields struct {
 	// storage specific fields
 	volumeOnly        bool
 	allowInconsistent bool
+
+	// Maximum rate in bytes per second at which data is sent, zero for no limit.
+	transferRate int64
+
+	// Limiter shared with other transfers, used instead of transferRate when set.
+	rateLimiter *util.RateLimiter
+}
+
+// rateLimit limits the rate at which data is written to the given connection.
+func (c *migrationFields) rateLimit(conn io.ReadWriteCloser) io.ReadWriteCloser {
+	if c.rateLimiter != nil {
+		return util.SharedRateLimitReadWriteCloser(conn, c.rateLimiter)
+	}
+
+	return util.RateLimitReadWriteCloser(conn, c.transferRate)
 }
 
 func (c *migrationFields) send(m proto.Message) error {
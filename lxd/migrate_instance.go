@@ -110,7 +110,7 @@ func (s *migrationSourceWs) Do(ctx context.Context, state *state.State, migrateO
 			return nil, fmt.Errorf("Failed getting migration source control connection: %w", err)
 		}
 
-		return wsConn, nil
+		return s.rateLimit(wsConn), nil
 	}
 
 	filesystemConnFunc := func(ctx context.Context) (io.ReadWriteCloser, error) {
@@ -124,7 +124,7 @@ func (s *migrationSourceWs) Do(ctx context.Context, state *state.State, migrateO
 			return nil, fmt.Errorf("Failed getting migration source filesystem connection: %w", err)
 		}
 
-		return wsConn, nil
+		return s.rateLimit(wsConn), nil
 	}
 
 	err = s.instance.MigrateSend(ctx, instance.MigrateSendArgs{
@@ -190,7 +190,7 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 		return err
 	}
 
-	err = pool.MigrateCustomVolume(projectName, fsConn, volSourceArgs, migrateOp)
+	err = pool.MigrateCustomVolume(projectName, s.rateLimit(fsConn), volSourceArgs, migrateOp)
 	if err != nil {
 		s.sendControl(err)
 		return err
@@ -47,7 +47,7 @@ var networksCmd = APIEndpoint{
 	MetricsType:     entity.TypeNetwork,
 	ProjectSpecific: true,
 
-	Get:  APIEndpointAction{Handler: networksGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+	Get:  APIEndpointAction{Handler: networksGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients, ClusterLinkViews: true},
 	Post: APIEndpointAction{Handler: networksPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateNetworks)},
 }
 
@@ -154,6 +154,16 @@ func networkAccessHandler(entitlement auth.Entitlement) func(d *Daemon, r *http.
 //      description: Retrieve networks from all projects
 //      type: boolean
 //      example: true
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -213,6 +223,16 @@ func networkAccessHandler(entitlement auth.Entitlement) func(d *Daemon, r *http.
 //	    description: Retrieve networks from all projects
 //	    type: boolean
 //	    example: true
+//	  - in: query
+//	    name: cluster-link
+//	    description: Retrieve entries from a linked cluster
+//	    type: string
+//	    example: lxd02
+//	  - in: query
+//	    name: all-links
+//	    description: Retrieve entries from the local cluster and all linked clusters
+//	    type: boolean
+//	    example: true
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -11,14 +11,16 @@ import (
 
 // Cache stores placement groups loaded within a short-lived scope (eg: a DB transaction) to avoid reloading the same placement group multiple times.
 type Cache struct {
-	mu    sync.Mutex
-	items map[string]*api.PlacementGroup
+	mu       sync.Mutex
+	items    map[string]*api.PlacementGroup
+	projects map[string][]*api.PlacementGroup
 }
 
 // NewCache returns an initialized placement cache.
 func NewCache() *Cache {
 	return &Cache{
-		items: make(map[string]*api.PlacementGroup),
+		items:    make(map[string]*api.PlacementGroup),
+		projects: make(map[string][]*api.PlacementGroup),
 	}
 }
 
@@ -54,3 +56,78 @@ func (c *Cache) Get(ctx context.Context, tx *db.ClusterTx, name string, projectN
 
 	return apiPg, nil
 }
+
+// GroupName returns the name of the placement group applying to an instance of the given project with the given expanded configuration.
+// The "placement.group" key takes precedence. Otherwise, the first placement group of the project (by name) whose selector matches the
+// configuration is used. An empty string is returned if no placement group applies.
+func (c *Cache) GroupName(ctx context.Context, tx *db.ClusterTx, projectName string, config map[string]string) (string, error) {
+	name := config["placement.group"]
+	if name != "" {
+		return name, nil
+	}
+
+	groups, err := c.projectGroups(ctx, tx, projectName)
+	if err != nil {
+		return "", err
+	}
+
+	for _, group := range groups {
+		selector, err := ParseSelector(group.Config["selector"])
+		if err != nil {
+			return "", err
+		}
+
+		if SelectorMatches(selector, config) {
+			return group.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// projectGroups returns the placement groups of the given project ordered by name, loading them if not present in the cache.
+func (c *Cache) projectGroups(ctx context.Context, tx *db.ClusterTx, projectName string) ([]*api.PlacementGroup, error) {
+	c.mu.Lock()
+	groups, ok := c.projects[projectName]
+	c.mu.Unlock()
+
+	if ok {
+		return groups, nil
+	}
+
+	dbGroups, _, err := cluster.GetPlacementGroupsAndURLs(ctx, tx.Tx(), &projectName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(dbGroups))
+	for _, dbGroup := range dbGroups {
+		ids = append(ids, dbGroup.Row.ID)
+	}
+
+	configs, err := cluster.PlacementGroupsConfigStore().GetByEntityIDs(ctx, tx.Tx(), ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	groups = make([]*api.PlacementGroup, 0, len(dbGroups))
+	for _, dbGroup := range dbGroups {
+		groups = append(groups, dbGroup.ToAPI(configs))
+	}
+
+	c.mu.Lock()
+	c.projects[projectName] = groups
+	for _, group := range groups {
+		c.items[projectName+"/"+group.Name] = group
+	}
+
+	c.mu.Unlock()
+
+	return groups, nil
+}
+
+// GroupName returns the name of the placement group applying to an instance of the given project with the given expanded configuration.
+// See [Cache.GroupName].
+func GroupName(ctx context.Context, tx *db.ClusterTx, projectName string, config map[string]string) (string, error) {
+	return NewCache().GroupName(ctx, tx, projectName, config)
+}
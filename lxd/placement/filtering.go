@@ -3,15 +3,23 @@ package placement
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 )
 
 // Filter filters the provided slice of candidate cluster members using the provided [api.PlacementGroup].
+//
+// Candidates are first restricted by the maximum number of instances per cluster member, then by the anti-affinity and
+// affinity rules of the placement group, and finally by its policy and rigor. When the placement group scope is
+// "failure-domain", the rules and policy apply to the failure domains of the cluster members rather than to the members.
 func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, apiPlacementGroup api.PlacementGroup, evacuation bool) ([]db.NodeInfo, error) {
 	// Get policy and rigor from config.
 	policy := apiPlacementGroup.Config["policy"]
@@ -25,30 +33,312 @@ func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, api
 		memberID = &sourceMemberID
 	}
 
-	memberToInst, err := cluster.GetInstancesInPlacementGroup(ctx, tx.Tx(), apiPlacementGroup.Name, apiPlacementGroup.Project, memberID)
+	pgCache := NewCache()
+	memberToInst, err := getInstancesInGroup(ctx, tx, pgCache, apiPlacementGroup.Name, apiPlacementGroup.Project, apiPlacementGroup.Config, memberID)
 	if err != nil {
 		return nil, err
 	}
 
+	keyOf, err := getMemberKeyFunc(ctx, tx, apiPlacementGroup.Config["scope"])
+	if err != nil {
+		return nil, err
+	}
+
+	filterErr := func(err error) error {
+		return api.StatusErrorf(http.StatusConflict, "Failed filtering candidate cluster members using placement group %q with %q policy and %q rigor: %w", apiPlacementGroup.Name, policy, rigor, err)
+	}
+
+	// The maximum number of instances per cluster member is always strictly enforced.
+	maxPerMember := apiPlacementGroup.Config["limits.instances_per_member"]
+	if maxPerMember != "" {
+		limit, err := strconv.Atoi(maxPerMember)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value %q for %q: %w", maxPerMember, "limits.instances_per_member", err)
+		}
+
+		candidates = getMembersBelowLimit(candidates, memberToInst, limit)
+		if len(candidates) == 0 {
+			return nil, filterErr(fmt.Errorf("All cluster members have reached the maximum of %d instances", limit))
+		}
+	}
+
+	// Avoid cluster members (or failure domains) hosting instances of placement groups listed in anti_affinity.
+	antiAffinity := shared.SplitNTrimSpace(apiPlacementGroup.Config["anti_affinity"], ",", -1, true)
+	if len(antiAffinity) > 0 {
+		keys, err := getGroupsKeys(ctx, tx, pgCache, antiAffinity, apiPlacementGroup.Project, memberID, keyOf)
+		if err != nil {
+			return nil, err
+		}
+
+		filteredCandidates := getMembersByKeys(candidates, keys, keyOf, false)
+		if len(filteredCandidates) > 0 {
+			candidates = filteredCandidates
+		} else if rigor == api.PlacementRigorStrict {
+			return nil, filterErr(errors.New("No eligible cluster members available after applying anti-affinity"))
+		}
+	}
+
+	// Prefer cluster members (or failure domains) hosting instances of placement groups listed in affinity.
+	affinity := shared.SplitNTrimSpace(apiPlacementGroup.Config["affinity"], ",", -1, true)
+	if len(affinity) > 0 {
+		keys, err := getGroupsKeys(ctx, tx, pgCache, affinity, apiPlacementGroup.Project, memberID, keyOf)
+		if err != nil {
+			return nil, err
+		}
+
+		// Affinity only applies once the related placement groups have instances.
+		if len(keys) > 0 {
+			filteredCandidates := getMembersByKeys(candidates, keys, keyOf, true)
+			if len(filteredCandidates) > 0 {
+				candidates = filteredCandidates
+			} else if rigor == api.PlacementRigorStrict {
+				return nil, filterErr(errors.New("No eligible cluster members available after applying affinity"))
+			}
+		}
+	}
+
 	// Get compliant cluster members using the placement group.
-	filteredCandidates, err := getCompliantMembers(policy, rigor, candidates, memberToInst)
+	filteredCandidates, err := getCompliantMembersByKey(policy, rigor, candidates, aggregateByKey(memberToInst, keyOf), keyOf)
 	if err != nil {
-		return nil, api.StatusErrorf(http.StatusConflict, "Failed filtering candidate cluster members using placement group %q with %q policy and %q rigor: %w", apiPlacementGroup.Name, policy, rigor, err)
+		return nil, filterErr(err)
 	}
 
 	return filteredCandidates, nil
 }
 
+// ParseSelector parses the selector of a placement group.
+// A selector is a comma separated list of "user.<key>=<value>" pairs that must all be set in the configuration of an instance.
+func ParseSelector(selector string) (map[string]string, error) {
+	pairs := shared.SplitNTrimSpace(selector, ",", -1, true)
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("Invalid selector %q, expected <key>=<value>", pair)
+		}
+
+		if !strings.HasPrefix(key, "user.") || key == "user." {
+			return nil, fmt.Errorf("Invalid selector key %q, only user keys can be selected", key)
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// SelectorMatches returns whether the given instance configuration matches the selector of a placement group.
+// An empty selector never matches.
+func SelectorMatches(selector map[string]string, config map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+
+	for key, value := range selector {
+		if config[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getInstancesInGroup returns a map of member ID to instance IDs for the instances of a placement group.
+// This includes instances referencing the placement group with "placement.group" and instances matching its selector, if any.
+// The same precedence as [Cache.GroupName] applies so that each instance belongs to a single placement group: instances matching
+// the selector are excluded if they set "placement.group" or if the selector of a placement group sorted before this one matches them.
+func getInstancesInGroup(ctx context.Context, tx *db.ClusterTx, pgCache *Cache, name string, projectName string, config map[string]string, memberID *int64) (map[int64][]int64, error) {
+	memberToInst, err := cluster.GetInstancesInPlacementGroup(ctx, tx.Tx(), name, projectName, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := ParseSelector(config["selector"])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(selector) == 0 {
+		return memberToInst, nil
+	}
+
+	selected, err := cluster.GetInstancesWithConfig(ctx, tx.Tx(), projectName, selector, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Instances with an explicit placement group belong to that group.
+	excluded, err := cluster.GetInstancesWithConfigKey(ctx, tx.Tx(), projectName, "placement.group", memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	excludedIDs := make(map[int64]bool)
+	for _, instIDs := range excluded {
+		for _, instID := range instIDs {
+			excludedIDs[instID] = true
+		}
+	}
+
+	// Instances matching the selector of an earlier placement group belong to that group.
+	groups, err := pgCache.projectGroups(ctx, tx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if group.Name == name {
+			break
+		}
+
+		groupSelector, err := ParseSelector(group.Config["selector"])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(groupSelector) == 0 {
+			continue
+		}
+
+		earlier, err := cluster.GetInstancesWithConfig(ctx, tx.Tx(), projectName, groupSelector, memberID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instIDs := range earlier {
+			for _, instID := range instIDs {
+				excludedIDs[instID] = true
+			}
+		}
+	}
+
+	// Add the remaining selected instances.
+	for member, instIDs := range selected {
+		for _, instID := range instIDs {
+			if !excludedIDs[instID] {
+				memberToInst[member] = append(memberToInst[member], instID)
+			}
+		}
+	}
+
+	return memberToInst, nil
+}
+
+// getGroupsKeys returns the keys (cluster member or failure domain IDs) hosting instances of any of the given placement groups.
+// Placement groups that don't exist (anymore) are only matched by the "placement.group" of instances.
+func getGroupsKeys(ctx context.Context, tx *db.ClusterTx, pgCache *Cache, names []string, projectName string, memberID *int64, keyOf func(memberID int64) int64) (map[int64]bool, error) {
+	keys := make(map[int64]bool)
+	for _, name := range names {
+		var config map[string]string
+		group, err := cluster.GetPlacementGroup(ctx, tx.Tx(), name, projectName)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, err
+		}
+
+		if group != nil {
+			config, err = cluster.PlacementGroupsConfigStore().GetByEntityID(ctx, tx.Tx(), group.Row.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		memberToInst, err := getInstancesInGroup(ctx, tx, pgCache, name, projectName, config, memberID)
+		if err != nil {
+			return nil, err
+		}
+
+		for key := range aggregateByKey(memberToInst, keyOf) {
+			keys[key] = true
+		}
+	}
+
+	return keys, nil
+}
+
+// getMemberKeyFunc returns a function mapping a cluster member to the key the placement policy applies to for the given scope.
+// This is the cluster member ID for the "member" scope and the failure domain ID for the "failure-domain" scope.
+func getMemberKeyFunc(ctx context.Context, tx *db.ClusterTx, scope string) (func(memberID int64) int64, error) {
+	switch scope {
+	case "", api.PlacementScopeMember:
+		return func(memberID int64) int64 { return memberID }, nil
+	case api.PlacementScopeFailureDomain:
+		members, err := tx.GetNodes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		domains, err := tx.GetNodesFailureDomains(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting failure domains: %w", err)
+		}
+
+		memberToDomain := make(map[int64]int64, len(members))
+		for _, member := range members {
+			memberToDomain[member.ID] = int64(domains[member.Address])
+		}
+
+		return func(memberID int64) int64 { return memberToDomain[memberID] }, nil
+	default:
+		return nil, fmt.Errorf("Invalid placement scope %q", scope)
+	}
+}
+
+// aggregateByKey converts a map of member ID to instance IDs into a map of key to instance IDs using the given key function.
+func aggregateByKey(memberToInst map[int64][]int64, keyOf func(memberID int64) int64) map[int64][]int64 {
+	keyToInst := make(map[int64][]int64, len(memberToInst))
+	for memberID, instIDs := range memberToInst {
+		key := keyOf(memberID)
+		keyToInst[key] = append(keyToInst[key], instIDs...)
+	}
+
+	return keyToInst
+}
+
+// getMembersBelowLimit returns the candidates hosting fewer than limit instances.
+func getMembersBelowLimit(candidates []db.NodeInfo, memberToInst map[int64][]int64, limit int) []db.NodeInfo {
+	var filteredCandidates []db.NodeInfo
+	for _, c := range candidates {
+		if len(memberToInst[c.ID]) < limit {
+			filteredCandidates = append(filteredCandidates, c)
+		}
+	}
+
+	return filteredCandidates
+}
+
+// getMembersByKeys returns the candidates whose key is (include=true) or isn't (include=false) in keys.
+func getMembersByKeys(candidates []db.NodeInfo, keys map[int64]bool, keyOf func(memberID int64) int64, include bool) []db.NodeInfo {
+	var filteredCandidates []db.NodeInfo
+	for _, c := range candidates {
+		if keys[keyOf(c.ID)] == include {
+			filteredCandidates = append(filteredCandidates, c)
+		}
+	}
+
+	return filteredCandidates
+}
+
 // getCompliantMembers gets compliant cluster members from the provided candidates based on the given placement policy and rigor.
 func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo, memberToInst map[int64][]int64) ([]db.NodeInfo, error) {
+	return getCompliantMembersByKey(policy, rigor, candidates, memberToInst, func(memberID int64) int64 { return memberID })
+}
+
+// getCompliantMembersByKey gets compliant cluster members from the provided candidates based on the given placement policy and rigor.
+// The policy applies to the keys returned by keyOf for each cluster member (e.g. the cluster member or its failure domain),
+// and keyToInst maps each key to the instances of the placement group it hosts.
+func getCompliantMembersByKey(policy string, rigor string, candidates []db.NodeInfo, keyToInst map[int64][]int64, keyOf func(memberID int64) int64) ([]db.NodeInfo, error) {
 	var compliantCandidates []db.NodeInfo
 
 	switch {
 	case policy == api.PlacementPolicySpread && rigor == api.PlacementRigorStrict:
-		// Spread + Strict: Place at most one instance per cluster member.
+		// Spread + Strict: Place at most one instance per cluster member (or failure domain).
 		// Filter out candidates that already have instances.
 		for _, c := range candidates {
-			_, hasInst := memberToInst[c.ID]
+			_, hasInst := keyToInst[keyOf(c.ID)]
 			if !hasInst {
 				compliantCandidates = append(compliantCandidates, c)
 			}
@@ -67,7 +357,7 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		// Find the minimum instance count among candidates.
 		counts := make([]int, 0, len(candidates))
 		for _, c := range candidates {
-			counts = append(counts, len(memberToInst[c.ID]))
+			counts = append(counts, len(keyToInst[keyOf(c.ID)]))
 		}
 
 		minInstances := 0
@@ -78,7 +368,7 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		// Filter candidates to only those with at most minInstances instances.
 		// This ensures the number of instances per cluster member differs by at most one.
 		for _, c := range candidates {
-			instanceCount := len(memberToInst[c.ID])
+			instanceCount := len(keyToInst[keyOf(c.ID)])
 			if instanceCount <= minInstances {
 				compliantCandidates = append(compliantCandidates, c)
 			}
@@ -91,29 +381,28 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicyCompact && rigor == api.PlacementRigorStrict:
-		// Compact + Strict: Place all instances on the same cluster member.
+		// Compact + Strict: Place all instances on the same cluster member (or failure domain).
 		// The member with the most instances determines the cluster member.
-		if len(memberToInst) == 0 {
+		if len(keyToInst) == 0 {
 			// No instances yet.
 			// All candidates are valid (first instance determines the member).
 			return candidates, nil
 		}
 
 		// Find which member has the most instances from this placement group.
-		var targetMemberID int64
+		var targetKey int64
 		maxInstances := -1
-		for memberID, instances := range memberToInst {
+		for key, instances := range keyToInst {
 			if len(instances) > maxInstances {
 				maxInstances = len(instances)
-				targetMemberID = memberID
+				targetKey = key
 			}
 		}
 
 		// Filter candidates to only include the node with the most instances.
 		for _, c := range candidates {
-			if c.ID == targetMemberID {
+			if keyOf(c.ID) == targetKey {
 				compliantCandidates = append(compliantCandidates, c)
-				break
 			}
 		}
 
@@ -124,31 +413,35 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicyCompact && rigor == api.PlacementRigorPermissive:
-		// Compact + Permissive: Prefer to place all instances on the same cluster member.
-		if len(memberToInst) == 0 {
+		// Compact + Permissive: Prefer to place all instances on the same cluster member (or failure domain).
+		if len(keyToInst) == 0 {
 			// No instances yet.
 			// All candidates are valid (first instance determines preferred member).
 			return candidates, nil
 		}
 
 		// Find which member has the most instances from this placement group.
-		var preferredMemberID int64
+		var preferredKey int64
 		maxInstances := -1
-		for memberID, instances := range memberToInst {
+		for key, instances := range keyToInst {
 			if len(instances) > maxInstances {
 				maxInstances = len(instances)
-				preferredMemberID = memberID
+				preferredKey = key
 			}
 		}
 
 		// Check if preferred member is in candidates.
 		for _, c := range candidates {
-			if c.ID == preferredMemberID {
+			if keyOf(c.ID) == preferredKey {
 				// Preferred member is available.
-				return []db.NodeInfo{c}, nil
+				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
 
+		if len(compliantCandidates) > 0 {
+			return compliantCandidates, nil
+		}
+
 		// Preferred node is not available - fall back to all candidates.
 		return candidates, nil
 
@@ -631,3 +631,215 @@ func (s *filteringSuite) TestFilter() {
 		}
 	}
 }
+
+func (s *filteringSuite) TestFilterRules() {
+	testCluster, cleanup := db.NewTestCluster(s.T())
+	defer cleanup()
+
+	// Create 5 candidate cluster members across two racks, the last one in the default failure domain.
+	domains := map[string]string{"member01": "rack1", "member02": "rack1", "member03": "rack2", "member04": "rack2", "member05": ""}
+	nodeNames := []string{"member01", "member02", "member03", "member04", "member05"}
+
+	candidates := make([]db.NodeInfo, 0, len(nodeNames))
+	for i, nodeName := range nodeNames {
+		candidates = append(candidates, db.NodeInfo{Name: nodeName, Address: fmt.Sprintf("192.0.2.%d", i)})
+	}
+
+	candidatesOnly := func(members ...string) []db.NodeInfo {
+		filteredCandidates := make([]db.NodeInfo, 0, len(members))
+		for _, candidate := range candidates {
+			if slices.Contains(members, candidate.Name) {
+				filteredCandidates = append(filteredCandidates, candidate)
+			}
+		}
+
+		return filteredCandidates
+	}
+
+	err := testCluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		for i, node := range candidates {
+			id, err := tx.CreateNode(node.Name, node.Address)
+			s.Require().NoError(err)
+			candidates[i].ID = id
+
+			err = tx.UpdateNodeFailureDomain(ctx, id, domains[node.Name])
+			s.Require().NoError(err)
+		}
+
+		return nil
+	})
+	s.Require().NoError(err)
+
+	createGroup := func(name string, config map[string]string) {
+		err := testCluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			pgID, err := query.Create(ctx, tx.Tx(), cluster.PlacementGroupsRow{ProjectID: 1, Name: name})
+			if err != nil {
+				return err
+			}
+
+			return cluster.PlacementGroupsConfigStore().Set(ctx, tx.Tx(), pgID, config)
+		})
+		s.Require().NoError(err)
+	}
+
+	createInstance := func(name string, member string, config map[string]string) {
+		err := testCluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			instanceID, err := cluster.CreateInstance(ctx, tx.Tx(), cluster.Instance{
+				Name:    name,
+				Node:    member,
+				Project: "default",
+				Type:    instancetype.Container,
+			})
+			if err != nil {
+				return err
+			}
+
+			return cluster.CreateInstanceConfig(ctx, tx.Tx(), instanceID, config)
+		})
+		s.Require().NoError(err)
+	}
+
+	createGroup("db", map[string]string{"policy": api.PlacementPolicySpread, "rigor": api.PlacementRigorStrict, "scope": api.PlacementScopeFailureDomain})
+	createGroup("replica", map[string]string{"policy": api.PlacementPolicySpread, "rigor": api.PlacementRigorStrict, "anti_affinity": "db", "scope": api.PlacementScopeFailureDomain})
+	createGroup("cache", map[string]string{"policy": api.PlacementPolicyCompact, "rigor": api.PlacementRigorPermissive, "affinity": "db"})
+	createGroup("web", map[string]string{"policy": api.PlacementPolicySpread, "rigor": api.PlacementRigorPermissive, "limits.instances_per_member": "2"})
+	createGroup("app", map[string]string{"policy": api.PlacementPolicySpread, "rigor": api.PlacementRigorStrict, "selector": "user.app=foo"})
+	createGroup("tier", map[string]string{"policy": api.PlacementPolicySpread, "rigor": api.PlacementRigorStrict, "selector": "user.tier=prod"})
+
+	tests := []struct {
+		name      string
+		group     string
+		caseSetup func()
+		want      []db.NodeInfo
+		wantErr   bool
+	}{
+		{
+			name:  "failure-domain: initial placement",
+			group: "db",
+			want:  candidates,
+		},
+		{
+			name:      "failure-domain: exclude the failure domain of existing instances",
+			group:     "db",
+			caseSetup: func() { createInstance("db1", "member01", map[string]string{"placement.group": "db"}) },
+			want:      candidatesOnly("member03", "member04", "member05"),
+		},
+		{
+			name:      "failure-domain: members without failure domain share the default failure domain",
+			group:     "db",
+			caseSetup: func() { createInstance("db2", "member03", map[string]string{"placement.group": "db"}) },
+			want:      candidatesOnly("member05"),
+		},
+		{
+			name:      "failure-domain: all failure domains occupied should return error",
+			group:     "db",
+			caseSetup: func() { createInstance("db3", "member05", map[string]string{"placement.group": "db"}) },
+			wantErr:   true,
+		},
+		{
+			name:    "anti-affinity/strict: no failure domain without related instances should return error",
+			group:   "replica",
+			wantErr: true,
+		},
+		{
+			name:  "affinity: restrict to members hosting related instances",
+			group: "cache",
+			want:  candidatesOnly("member01", "member03", "member05"),
+		},
+		{
+			name:  "limits.instances_per_member: exclude full members",
+			group: "web",
+			caseSetup: func() {
+				createInstance("web1", "member02", map[string]string{"placement.group": "web"})
+				createInstance("web2", "member02", map[string]string{"placement.group": "web"})
+			},
+			want: candidatesOnly("member01", "member03", "member04", "member05"),
+		},
+		{
+			name:  "selector: instances matching the selector are part of the placement group",
+			group: "app",
+			caseSetup: func() {
+				createInstance("app1", "member04", map[string]string{"user.app": "foo"})
+				createInstance("app2", "member05", map[string]string{"user.app": "bar"})
+			},
+			want: candidatesOnly("member01", "member02", "member03", "member05"),
+		},
+		{
+			name:  "selector: instances with an explicit placement group are not part of other placement groups",
+			group: "app",
+			caseSetup: func() {
+				createInstance("app3", "member01", map[string]string{"placement.group": "web", "user.app": "foo"})
+			},
+			want: candidatesOnly("member01", "member02", "member03", "member05"),
+		},
+		{
+			name:  "selector: instances matching an earlier placement group are not part of later placement groups",
+			group: "tier",
+			caseSetup: func() {
+				createInstance("tier1", "member02", map[string]string{"user.app": "foo", "user.tier": "prod"})
+				createInstance("tier2", "member03", map[string]string{"user.tier": "prod"})
+			},
+			want: candidatesOnly("member01", "member02", "member04", "member05"),
+		},
+	}
+
+	pgCache := NewCache()
+
+	for i, tt := range tests {
+		s.T().Logf("Case %d: %s", i, tt.name)
+		if tt.caseSetup != nil {
+			tt.caseSetup()
+		}
+
+		_ = testCluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			apiPlacementGroup, err := pgCache.Get(ctx, tx, tt.group, "default")
+			s.Require().NoError(err)
+
+			got, err := Filter(ctx, tx, candidates, *apiPlacementGroup, false)
+			if tt.wantErr {
+				s.Error(err)
+				return nil
+			}
+
+			s.Require().NoError(err)
+			s.ElementsMatch(tt.want, got)
+			return nil
+		})
+	}
+
+	// Check which placement group applies to an instance.
+	_ = testCluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		name, err := pgCache.GroupName(ctx, tx, "default", map[string]string{"placement.group": "db", "user.app": "foo"})
+		s.Require().NoError(err)
+		s.Equal("db", name)
+
+		name, err = pgCache.GroupName(ctx, tx, "default", map[string]string{"user.app": "foo"})
+		s.Require().NoError(err)
+		s.Equal("app", name)
+
+		name, err = pgCache.GroupName(ctx, tx, "default", map[string]string{"user.app": "bar"})
+		s.Require().NoError(err)
+		s.Empty(name)
+
+		return nil
+	})
+}
+
+func (s *filteringSuite) TestParseSelector() {
+	selector, err := ParseSelector("user.app=foo, user.tier=prod")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"user.app": "foo", "user.tier": "prod"}, selector)
+
+	s.True(SelectorMatches(selector, map[string]string{"user.app": "foo", "user.tier": "prod", "limits.cpu": "2"}))
+	s.False(SelectorMatches(selector, map[string]string{"user.app": "foo"}))
+	s.False(SelectorMatches(nil, map[string]string{"user.app": "foo"}))
+
+	selector, err = ParseSelector("")
+	s.Require().NoError(err)
+	s.Empty(selector)
+
+	for _, value := range []string{"user.app", "user.app=", "limits.cpu=2", "user.=foo"} {
+		_, err := ParseSelector(value)
+		s.Error(err, value)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/query"
 	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/placement"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -750,6 +752,61 @@ func placementGroupValidateConfig(config map[string]string) error {
 		//  required: "yes"
 		//  shortdesc: Enforcement level of the placement policy
 		"rigor": validate.IsOneOf(api.PlacementRigorStrict, api.PlacementRigorPermissive),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=scope)
+		// Determines whether the placement policy applies to individual cluster members or to
+		// their failure domains.
+		//
+		// Possible values are `member` and `failure-domain`. Cluster members without a failure
+		// domain belong to the `default` failure domain.
+		// See {ref}`clustering-instance-placement-scope` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `member`
+		//  shortdesc: Scope of the placement policy
+		"scope": validate.Optional(validate.IsOneOf(api.PlacementScopeMember, api.PlacementScopeFailureDomain)),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=limits.instances_per_member)
+		// Maximum number of instances of the placement group on a single cluster member.
+		// This limit is always strictly enforced, regardless of the rigor.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of instances per cluster member
+		"limits.instances_per_member": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=affinity)
+		// Comma-separated list of placement groups in the same project whose instances should be
+		// co-located with the instances of this placement group.
+		// Candidates are restricted to cluster members (or failure domains, depending on `scope`)
+		// that already host instances of the listed placement groups.
+		// See {ref}`clustering-instance-placement-affinity` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Placement groups to co-locate with
+		"affinity": validate.Optional(validate.IsListOf(validate.IsDeviceName)),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=anti_affinity)
+		// Comma-separated list of placement groups in the same project whose instances should not
+		// share a cluster member (or failure domain, depending on `scope`) with the instances of
+		// this placement group.
+		// See {ref}`clustering-instance-placement-affinity` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Placement groups to keep apart from
+		"anti_affinity": validate.Optional(validate.IsListOf(validate.IsDeviceName)),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=selector)
+		// Comma-separated list of `user.<key>=<value>` pairs.
+		// Instances whose configuration contains all of the pairs are part of the placement group,
+		// even if they don't set {config:option}`instance-placement:placement.group`.
+		// See {ref}`clustering-instance-placement-selector` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Instance user keys selecting members of the placement group
+		"selector": func(value string) error {
+			_, err := placement.ParseSelector(value)
+			return err
+		},
 	}
 
 	for k, v := range config {
@@ -28,6 +28,10 @@ const (
 	// CtxOpenFGARequestCache is used to set a cache for the OpenFGA datastore to improve driver performance on a per request basis.
 	CtxOpenFGARequestCache CtxKey = "openfga_request_cache"
 
+	// CtxAuthorizationCheck indicates that permissions are checked on behalf of another identity to explain an
+	// authorization decision, rather than to authorize a request. Denials are not reported as security events.
+	CtxAuthorizationCheck CtxKey = "authorization_check"
+
 	// CtxSecurityEventBase carries the per-request OWASP audit fields used to
 	// populate security events emitted while handling a request.
 	CtxSecurityEventBase CtxKey = "security_event_base"
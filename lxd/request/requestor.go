@@ -33,6 +33,7 @@ type RequestorHookResult struct {
 	IdentityProviderGroups []string
 	EffectiveAuthGroups    []string
 	Projects               []string
+	TokenPermissions       []api.Permission
 }
 
 // RequestorArgs contains information that is gathered when the requestor is initially authenticated.
@@ -65,6 +66,7 @@ type Requestor struct {
 	authGroups               []string
 	mappedAuthGroups         []string
 	projects                 []string
+	tokenPermissions         []api.Permission
 	identityType             identity.Type
 	expiresAt                *time.Time
 	isForwarded              bool
@@ -128,6 +130,12 @@ func (r *Requestor) CallerAllowedProjectNames() []string {
 	return r.projects
 }
 
+// CallerTokenPermissions returns the permissions that the bearer token used by the caller is narrowed to.
+// It is empty if the caller did not authenticate with a narrowed bearer token.
+func (r *Requestor) CallerTokenPermissions() []api.Permission {
+	return r.tokenPermissions
+}
+
 // CallerIdentityProviderGroups returns the original caller identity provider groups.
 func (r *Requestor) CallerIdentityProviderGroups() []string {
 	return r.identityProviderGroups
@@ -304,6 +312,7 @@ func (r *Requestor) setIdentity(ctx context.Context, hook RequestorHook) error {
 	r.mappedAuthGroups = res.EffectiveAuthGroups
 	r.identityProviderGroups = res.IdentityProviderGroups
 	r.projects = res.Projects
+	r.tokenPermissions = res.TokenPermissions
 
 	return nil
 }
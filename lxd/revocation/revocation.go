@@ -0,0 +1,360 @@
+package revocation
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+const (
+	// ModeCRL checks certificates against the certificate revocation lists of their CRL distribution points.
+	ModeCRL = "crl"
+
+	// ModeOCSP checks certificates with their OCSP responders.
+	ModeOCSP = "ocsp"
+)
+
+// maxCacheDuration is the maximum duration for which a certificate revocation list or OCSP response is cached, so that
+// revocations are picked up even if the next update is far in the future.
+const maxCacheDuration = time.Hour
+
+// failureCacheDuration is the duration for which a failure to fetch a certificate revocation list or OCSP response
+// is cached, so that an unreachable CA does not delay every request.
+const failureCacheDuration = 30 * time.Second
+
+// fetchTimeout is the timeout for fetching a certificate revocation list or OCSP response.
+const fetchTimeout = 10 * time.Second
+
+// maxResponseSize is the maximum size of a certificate revocation list or OCSP response.
+const maxResponseSize = 32 * 1024 * 1024
+
+// ErrRevoked is returned when a certificate has been revoked.
+var ErrRevoked = errors.New("Certificate has been revoked")
+
+// cacheEntry is a cached certificate revocation list or OCSP response.
+type cacheEntry struct {
+	crl        *x509.RevocationList
+	revoked    bool
+	nextUpdate time.Time
+	expiresAt  time.Time
+}
+
+// usable returns true if the entry may still be used after it has expired, because its next update has not passed.
+func (e cacheEntry) usable() bool {
+	return e.nextUpdate.IsZero() || time.Now().Before(e.nextUpdate)
+}
+
+// Checker checks the revocation status of certificates online. Certificate revocation lists and OCSP responses are
+// cached until their next update, for at most an hour. If a cached entry cannot be refreshed, the stale entry is used
+// until its next update. Failures are cached briefly, and concurrent fetches of the same list or response are
+// deduplicated.
+type Checker struct {
+	httpClientFunc func() (*http.Client, error)
+
+	mu       sync.Mutex
+	cache    map[string]cacheEntry
+	failures map[string]failureEntry
+
+	group singleflight.Group
+}
+
+// failureEntry is a cached failure to fetch a certificate revocation list or OCSP response.
+type failureEntry struct {
+	err       error
+	expiresAt time.Time
+}
+
+// NewChecker returns a new Checker that uses the HTTP client returned by the given function to fetch certificate
+// revocation lists and OCSP responses.
+func NewChecker(httpClientFunc func() (*http.Client, error)) *Checker {
+	return &Checker{
+		httpClientFunc: httpClientFunc,
+		cache:          make(map[string]cacheEntry),
+		failures:       make(map[string]failureEntry),
+	}
+}
+
+// Check checks the revocation status of the given certificate, which must be signed by the given issuer, using the
+// given mode. It returns [ErrRevoked] if the certificate has been revoked, and another error if the revocation status
+// cannot be determined.
+func (c *Checker) Check(ctx context.Context, mode string, cert *x509.Certificate, issuer *x509.Certificate) error {
+	switch mode {
+	case ModeCRL:
+		return c.checkCRL(ctx, cert, issuer)
+	case ModeOCSP:
+		return c.checkOCSP(ctx, cert, issuer)
+	}
+
+	return fmt.Errorf("Unknown revocation check mode %q", mode)
+}
+
+// cached returns the cached entry for the given key, whether it was found, and whether it has not expired yet.
+func (c *Checker) cached(key string) (entry cacheEntry, found bool, fresh bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found = c.cache[key]
+	return entry, found, found && time.Now().Before(entry.expiresAt)
+}
+
+// store caches the given entry until the given next update, for at most [maxCacheDuration].
+func (c *Checker) store(key string, entry cacheEntry, nextUpdate time.Time) {
+	entry.nextUpdate = nextUpdate
+	entry.expiresAt = time.Now().Add(maxCacheDuration)
+	if !nextUpdate.IsZero() && nextUpdate.Before(entry.expiresAt) {
+		entry.expiresAt = nextUpdate
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache[key] = entry
+}
+
+// fetch calls the given function to fetch and cache the entry for the given key, unless fetching it failed recently. Concurrent
+// fetches of the same key are deduplicated. The fetch is not cancelled if the caller gives up waiting for it, so that
+// its result can still be cached for other callers.
+func (c *Checker) fetch(ctx context.Context, key string, fetchFunc func(ctx context.Context) (any, error)) (any, error) {
+	c.mu.Lock()
+	failure, found := c.failures[key]
+	c.mu.Unlock()
+
+	if found && time.Now().Before(failure.expiresAt) {
+		return nil, failure.err
+	}
+
+	ch := c.group.DoChan(key, func() (any, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+
+		value, err := fetchFunc(fetchCtx)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if err != nil {
+			c.failures[key] = failureEntry{err: err, expiresAt: time.Now().Add(failureCacheDuration)}
+		} else {
+			delete(c.failures, key)
+		}
+
+		return value, err
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// checkCRL checks the certificate against the certificate revocation lists of its CRL distribution points. The
+// certificate is revoked if any of the lists contain it.
+func (c *Checker) checkCRL(ctx context.Context, cert *x509.Certificate, issuer *x509.Certificate) error {
+	if len(cert.CRLDistributionPoints) == 0 {
+		return errors.New("Certificate has no CRL distribution point")
+	}
+
+	for _, url := range cert.CRLDistributionPoints {
+		crl, err := c.getCRL(ctx, url, issuer)
+		if err != nil {
+			return err
+		}
+
+		for _, revoked := range crl.RevokedCertificateEntries {
+			if cert.SerialNumber.Cmp(revoked.SerialNumber) == 0 {
+				return ErrRevoked
+			}
+		}
+	}
+
+	return nil
+}
+
+// getCRL returns the certificate revocation list at the given URL, which must be signed by the given issuer.
+func (c *Checker) getCRL(ctx context.Context, url string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	key := "crl:" + url
+	entry, found, fresh := c.cached(key)
+	if fresh {
+		return entry.crl, nil
+	}
+
+	value, err := c.fetch(ctx, key, func(ctx context.Context) (any, error) {
+		crl, err := c.fetchCRL(ctx, url, issuer)
+		if err != nil {
+			return nil, err
+		}
+
+		c.store(key, cacheEntry{crl: crl}, crl.NextUpdate)
+		return crl, nil
+	})
+	if err != nil {
+		// Fall back to the cached list until its next update.
+		if found && entry.usable() {
+			logger.Warn("Failed refreshing certificate revocation list, using cached list", logger.Ctx{"url": url, "err": err})
+			return entry.crl, nil
+		}
+
+		return nil, err
+	}
+
+	return value.(*x509.RevocationList), nil
+}
+
+// fetchCRL fetches and verifies the certificate revocation list at the given URL.
+func (c *Checker) fetchCRL(ctx context.Context, url string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	body, err := c.do(ctx, http.MethodGet, url, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching certificate revocation list %q: %w", url, err)
+	}
+
+	// Certificate revocation lists are usually DER encoded, but some CAs serve them PEM encoded.
+	block, _ := pem.Decode(body)
+	if block != nil {
+		body = block.Bytes
+	}
+
+	crl, err := x509.ParseRevocationList(body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing certificate revocation list %q: %w", url, err)
+	}
+
+	err = crl.CheckSignatureFrom(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("Certificate revocation list %q has not been signed by the CA: %w", url, err)
+	}
+
+	return crl, nil
+}
+
+// checkOCSP checks the certificate with its OCSP responders. The first responder that returns a response is used.
+func (c *Checker) checkOCSP(ctx context.Context, cert *x509.Certificate, issuer *x509.Certificate) error {
+	if len(cert.OCSPServer) == 0 {
+		return errors.New("Certificate has no OCSP responder")
+	}
+
+	key := "ocsp:" + shared.CertFingerprint(issuer) + ":" + cert.SerialNumber.String()
+	entry, found, fresh := c.cached(key)
+	if fresh {
+		if entry.revoked {
+			return ErrRevoked
+		}
+
+		return nil
+	}
+
+	value, err := c.fetch(ctx, key, func(ctx context.Context) (any, error) {
+		resp, err := c.queryOCSP(ctx, cert, issuer)
+		if err != nil {
+			return nil, err
+		}
+
+		c.store(key, cacheEntry{revoked: resp.Status == ocsp.Revoked}, resp.NextUpdate)
+		return resp, nil
+	})
+	if err != nil {
+		// Fall back to the cached response until its next update.
+		if found && entry.usable() {
+			logger.Warn("Failed refreshing OCSP response, using cached response", logger.Ctx{"serial": cert.SerialNumber.String(), "err": err})
+			if entry.revoked {
+				return ErrRevoked
+			}
+
+			return nil
+		}
+
+		return err
+	}
+
+	if value.(*ocsp.Response).Status == ocsp.Revoked {
+		return ErrRevoked
+	}
+
+	return nil
+}
+
+// queryOCSP queries the OCSP responders of the certificate in turn, and returns the first response that reports the
+// certificate as either good or revoked.
+func (c *Checker) queryOCSP(ctx context.Context, cert *x509.Certificate, issuer *x509.Certificate) (*ocsp.Response, error) {
+	var errs []error
+	for _, url := range cert.OCSPServer {
+		resp, err := c.fetchOCSP(ctx, url, cert, issuer)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if resp.Status == ocsp.Good || resp.Status == ocsp.Revoked {
+			return resp, nil
+		}
+
+		errs = append(errs, fmt.Errorf("OCSP responder %q does not know the certificate", url))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// fetchOCSP requests and verifies the OCSP response for the certificate from the given responder.
+func (c *Checker) fetchOCSP(ctx context.Context, url string, cert *x509.Certificate, issuer *x509.Certificate) (*ocsp.Response, error) {
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating OCSP request: %w", err)
+	}
+
+	body, err := c.do(ctx, http.MethodPost, url, "application/ocsp-request", req)
+	if err != nil {
+		return nil, fmt.Errorf("Failed querying OCSP responder %q: %w", url, err)
+	}
+
+	// The response must be signed by the issuer, or by a responder certificate that is signed by the issuer.
+	resp, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid response from OCSP responder %q: %w", url, err)
+	}
+
+	return resp, nil
+}
+
+// do performs an HTTP request and returns the response body.
+func (c *Checker) do(ctx context.Context, method string, url string, contentType string, body []byte) ([]byte, error) {
+	client, err := c.httpClientFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+}
@@ -0,0 +1,275 @@
+package revocation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// testCA is a certificate authority with a CRL distribution point and an OCSP responder.
+type testCA struct {
+	t       *testing.T
+	cert    *x509.Certificate
+	key     crypto.Signer
+	server  *httptest.Server
+	revoked map[int64]bool
+	fail    bool
+	serial  int64
+
+	// requests counts the requests served, and release blocks them until it is closed, if set.
+	requests atomic.Int64
+	release  chan struct{}
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	ca := &testCA{t: t, cert: cert, key: key, revoked: map[int64]bool{}, serial: 1}
+	ca.server = httptest.NewServer(http.HandlerFunc(ca.serve))
+	t.Cleanup(ca.server.Close)
+
+	return ca
+}
+
+func (ca *testCA) serve(w http.ResponseWriter, r *http.Request) {
+	ca.requests.Add(1)
+	if ca.release != nil {
+		<-ca.release
+	}
+
+	if ca.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/crl":
+		var entries []x509.RevocationListEntry
+		for serial := range ca.revoked {
+			entries = append(entries, x509.RevocationListEntry{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+		}
+
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:                    big.NewInt(1),
+			ThisUpdate:                time.Now().Add(-time.Minute),
+			NextUpdate:                time.Now().Add(time.Hour),
+			RevokedCertificateEntries: entries,
+		}, ca.cert, ca.key)
+		require.NoError(ca.t, err)
+
+		_, _ = w.Write(crl)
+
+	case "/ocsp":
+		body, err := io.ReadAll(r.Body)
+		require.NoError(ca.t, err)
+
+		req, err := ocsp.ParseRequest(body)
+		require.NoError(ca.t, err)
+
+		template := ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+
+		if ca.revoked[req.SerialNumber.Int64()] {
+			template.Status = ocsp.Revoked
+			template.RevokedAt = time.Now().Add(-time.Minute)
+		} else if req.SerialNumber.Int64() > ca.serial {
+			template.Status = ocsp.Unknown
+		}
+
+		resp, err := ocsp.CreateResponse(ca.cert, ca.cert, template, ca.key)
+		require.NoError(ca.t, err)
+
+		_, _ = w.Write(resp)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// issue returns a new client certificate signed by the CA.
+func (ca *testCA) issue(withEndpoints bool) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(ca.t, err)
+
+	ca.serial++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(ca.serial),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	if withEndpoints {
+		template.CRLDistributionPoints = []string{ca.server.URL + "/crl"}
+		template.OCSPServer = []string{ca.server.URL + "/ocsp"}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	require.NoError(ca.t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(ca.t, err)
+
+	return cert
+}
+
+func TestChecker(t *testing.T) {
+	for _, mode := range []string{ModeCRL, ModeOCSP} {
+		t.Run(mode, func(t *testing.T) {
+			ca := newTestCA(t)
+			good := ca.issue(true)
+			revoked := ca.issue(true)
+			ca.revoked[revoked.SerialNumber.Int64()] = true
+
+			checker := NewChecker(func() (*http.Client, error) { return ca.server.Client(), nil })
+
+			assert.NoError(t, checker.Check(context.Background(), mode, good, ca.cert))
+			assert.ErrorIs(t, checker.Check(context.Background(), mode, revoked, ca.cert), ErrRevoked)
+
+			// Certificates without a CRL distribution point or OCSP responder are rejected.
+			assert.Error(t, checker.Check(context.Background(), mode, ca.issue(false), ca.cert))
+
+			// Cached results are used if the CA cannot be reached.
+			ca.fail = true
+			assert.NoError(t, checker.Check(context.Background(), mode, good, ca.cert))
+			assert.ErrorIs(t, checker.Check(context.Background(), mode, revoked, ca.cert), ErrRevoked)
+
+			// Certificates are rejected if their revocation status cannot be determined.
+			checker = NewChecker(func() (*http.Client, error) { return ca.server.Client(), nil })
+			assert.Error(t, checker.Check(context.Background(), mode, good, ca.cert))
+		})
+	}
+}
+
+func TestCheckerUntrustedSignature(t *testing.T) {
+	ca := newTestCA(t)
+	other := newTestCA(t)
+	cert := ca.issue(true)
+
+	checker := NewChecker(func() (*http.Client, error) { return ca.server.Client(), nil })
+
+	// Revocation lists and OCSP responses that are not signed by the CA are rejected.
+	for _, mode := range []string{ModeCRL, ModeOCSP} {
+		assert.Error(t, checker.Check(context.Background(), mode, cert, other.cert), mode)
+	}
+
+	assert.Error(t, checker.Check(context.Background(), "foo", cert, ca.cert))
+}
+
+func TestCheckerFailureCache(t *testing.T) {
+	for _, mode := range []string{ModeCRL, ModeOCSP} {
+		t.Run(mode, func(t *testing.T) {
+			ca := newTestCA(t)
+			cert := ca.issue(true)
+			ca.fail = true
+
+			checker := NewChecker(func() (*http.Client, error) { return ca.server.Client(), nil })
+
+			// Failures are cached, so the CA is only queried once.
+			assert.Error(t, checker.Check(context.Background(), mode, cert, ca.cert))
+			assert.Error(t, checker.Check(context.Background(), mode, cert, ca.cert))
+			assert.Equal(t, int64(1), ca.requests.Load())
+
+			// Once the failure has expired, the CA is queried again.
+			checker.mu.Lock()
+			for key, failure := range checker.failures {
+				failure.expiresAt = time.Now()
+				checker.failures[key] = failure
+			}
+
+			checker.mu.Unlock()
+
+			ca.fail = false
+			assert.NoError(t, checker.Check(context.Background(), mode, cert, ca.cert))
+			assert.Equal(t, int64(2), ca.requests.Load())
+		})
+	}
+}
+
+func TestCheckerDeduplication(t *testing.T) {
+	for _, mode := range []string{ModeCRL, ModeOCSP} {
+		t.Run(mode, func(t *testing.T) {
+			ca := newTestCA(t)
+			cert := ca.issue(true)
+			ca.release = make(chan struct{})
+
+			checker := NewChecker(func() (*http.Client, error) { return ca.server.Client(), nil })
+
+			// Concurrent checks of the same certificate share a single request to the CA.
+			var wg sync.WaitGroup
+			for range 5 {
+				wg.Go(func() {
+					assert.NoError(t, checker.Check(context.Background(), mode, cert, ca.cert))
+				})
+			}
+
+			require.Eventually(t, func() bool { return ca.requests.Load() == 1 }, time.Second, 10*time.Millisecond)
+			time.Sleep(50 * time.Millisecond)
+			close(ca.release)
+			wg.Wait()
+
+			assert.Equal(t, int64(1), ca.requests.Load())
+		})
+	}
+}
+
+func TestCheckerCancellation(t *testing.T) {
+	ca := newTestCA(t)
+	cert := ca.issue(true)
+	ca.release = make(chan struct{})
+
+	checker := NewChecker(func() (*http.Client, error) { return ca.server.Client(), nil })
+
+	// A caller that gives up waiting does not cancel the fetch, whose result is cached for later checks.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, checker.Check(ctx, ModeCRL, cert, ca.cert), context.DeadlineExceeded)
+	close(ca.release)
+
+	require.Eventually(t, func() bool {
+		_, _, fresh := checker.cached("crl:" + cert.CRLDistributionPoints[0])
+		return fresh
+	}, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, checker.Check(context.Background(), ModeCRL, cert, ca.cert))
+	assert.Equal(t, int64(1), ca.requests.Load())
+}
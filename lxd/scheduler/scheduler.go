@@ -0,0 +1,245 @@
+// Package scheduler selects cluster members for new instances based on the resources they request
+// and the free capacity of the cluster members.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/canonical/lxd/shared/units"
+)
+
+// StrategyInstances is the default strategy which selects the cluster member with the fewest instances.
+// It doesn't take resources into account and isn't handled by this package.
+const StrategyInstances = "instances"
+
+// Request represents the resources requested by an instance.
+type Request struct {
+	// CPUs is the number of CPUs.
+	CPUs float64
+
+	// Memory is the amount of memory in bytes.
+	Memory int64
+
+	// MemoryPercent is the amount of memory as a percentage of the memory of the cluster member.
+	// It is used instead of Memory when greater than zero.
+	MemoryPercent float64
+
+	// Disk is the size of the root disk in bytes.
+	Disk int64
+
+	// GPUs is the number of GPUs.
+	GPUs int
+}
+
+// Member represents the capacity of a cluster member and the resources allocated to its instances.
+// CPU and memory capacities include the overcommit ratio of the cluster member.
+type Member struct {
+	Name      string
+	Instances int
+
+	CPUs       float64
+	UsedCPUs   float64
+	Memory     int64
+	UsedMemory int64
+	Disk       int64
+	UsedDisk   int64
+	GPUs       int
+	UsedGPUs   int
+}
+
+// memory returns the memory requested on the cluster member.
+func (r Request) memory(m Member) int64 {
+	if r.MemoryPercent > 0 {
+		return int64(float64(m.Memory) * r.MemoryPercent / 100)
+	}
+
+	return r.Memory
+}
+
+// Fits checks whether the request fits in the free capacity of the cluster member.
+// Resources with an unknown (zero) capacity are not checked.
+func (m Member) Fits(req Request) error {
+	if m.CPUs > 0 && req.CPUs > m.CPUs-m.UsedCPUs {
+		return fmt.Errorf("Not enough free CPUs (requested %g, free %g)", req.CPUs, m.CPUs-m.UsedCPUs)
+	}
+
+	memory := req.memory(m)
+	if m.Memory > 0 && memory > m.Memory-m.UsedMemory {
+		return fmt.Errorf("Not enough free memory (requested %s, free %s)", units.GetByteSizeStringIEC(memory, 2), units.GetByteSizeStringIEC(max(0, m.Memory-m.UsedMemory), 2))
+	}
+
+	if m.Disk > 0 && req.Disk > m.Disk-m.UsedDisk {
+		return fmt.Errorf("Not enough free disk space (requested %s, free %s)", units.GetByteSizeStringIEC(req.Disk, 2), units.GetByteSizeStringIEC(max(0, m.Disk-m.UsedDisk), 2))
+	}
+
+	if req.GPUs > m.GPUs-m.UsedGPUs {
+		return fmt.Errorf("Not enough free GPUs (requested %d, free %d)", req.GPUs, max(0, m.GPUs-m.UsedGPUs))
+	}
+
+	return nil
+}
+
+// FreeRatio returns the fraction of the CPU and memory capacity of the cluster member left free once the request is placed on it.
+// The smallest fraction of both resources is returned, resources with an unknown capacity are ignored.
+func (m Member) FreeRatio(req Request) float64 {
+	ratio := 1.0
+	if m.CPUs > 0 {
+		ratio = min(ratio, (m.CPUs-m.UsedCPUs-req.CPUs)/m.CPUs)
+	}
+
+	if m.Memory > 0 {
+		ratio = min(ratio, float64(m.Memory-m.UsedMemory-req.memory(m))/float64(m.Memory))
+	}
+
+	return ratio
+}
+
+// Strategy scores cluster members able to host an instance. The cluster member with the highest score is selected.
+type Strategy interface {
+	// Score returns the score of the cluster member for the request.
+	Score(member Member, req Request) float64
+
+	// Description returns a short description of the strategy used to explain decisions.
+	Description() string
+}
+
+var strategiesMu sync.Mutex
+var strategies = map[string]Strategy{}
+
+// Register registers a strategy under the given name.
+func Register(name string, strategy Strategy) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	strategies[name] = strategy
+}
+
+// Strategies returns the names of the registered strategies, including the default strategy.
+func Strategies() []string {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	names := []string{StrategyInstances}
+	for name := range strategies {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Candidate represents the evaluation of a cluster member for a request.
+type Candidate struct {
+	Member string
+	Score  float64
+	Reason string
+}
+
+// Decision represents the outcome of a scheduling decision.
+type Decision struct {
+	Strategy   string
+	Member     string
+	Reason     string
+	Candidates []Candidate
+}
+
+// Select selects a cluster member for the request using the named strategy.
+// Cluster members that don't have enough free capacity are excluded. Ties are broken by the number of instances and then by name.
+func Select(strategyName string, members []Member, req Request) (*Decision, error) {
+	strategiesMu.Lock()
+	strategy, ok := strategies[strategyName]
+	strategiesMu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("Unknown scheduler strategy %q", strategyName)
+	}
+
+	decision := &Decision{Strategy: strategyName, Candidates: make([]Candidate, 0, len(members))}
+
+	var best *Member
+	var bestScore float64
+	for i, member := range members {
+		err := member.Fits(req)
+		if err != nil {
+			decision.Candidates = append(decision.Candidates, Candidate{Member: member.Name, Reason: err.Error()})
+			continue
+		}
+
+		score := strategy.Score(member, req)
+		decision.Candidates = append(decision.Candidates, Candidate{Member: member.Name, Score: score})
+
+		if best == nil || score > bestScore || (score == bestScore && (member.Instances < best.Instances || (member.Instances == best.Instances && member.Name < best.Name))) {
+			best = &members[i]
+			bestScore = score
+		}
+	}
+
+	if best == nil {
+		reasons := make([]string, 0, len(decision.Candidates))
+		for _, candidate := range decision.Candidates {
+			reasons = append(reasons, candidate.Member+": "+candidate.Reason)
+		}
+
+		if len(reasons) == 0 {
+			return nil, errors.New("No cluster member available")
+		}
+
+		return nil, fmt.Errorf("No cluster member has enough free capacity (%s)", strings.Join(reasons, "; "))
+	}
+
+	decision.Member = best.Name
+	decision.Reason = fmt.Sprintf("Selected %q using the %q strategy (%s): %s free after placement", best.Name, strategyName, strategy.Description(), best.freeDescription(req))
+
+	return decision, nil
+}
+
+// freeDescription describes the free resources of the cluster member once the request is placed on it.
+func (m Member) freeDescription(req Request) string {
+	var parts []string
+	if m.CPUs > 0 {
+		parts = append(parts, fmt.Sprintf("%s/%s CPUs", strconv.FormatFloat(m.CPUs-m.UsedCPUs-req.CPUs, 'f', -1, 64), strconv.FormatFloat(m.CPUs, 'f', -1, 64)))
+	}
+
+	if m.Memory > 0 {
+		parts = append(parts, fmt.Sprintf("%s/%s memory", units.GetByteSizeStringIEC(m.Memory-m.UsedMemory-req.memory(m), 2), units.GetByteSizeStringIEC(m.Memory, 2)))
+	}
+
+	if m.Disk > 0 {
+		parts = append(parts, fmt.Sprintf("%s/%s disk", units.GetByteSizeStringIEC(m.Disk-m.UsedDisk-req.Disk, 2), units.GetByteSizeStringIEC(m.Disk, 2)))
+	}
+
+	if m.GPUs > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d GPUs", m.GPUs-m.UsedGPUs-req.GPUs, m.GPUs))
+	}
+
+	if len(parts) == 0 {
+		return "unknown resources"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// ParseOvercommit parses an overcommit ratio, which must be a number of at least 1.
+func ParseOvercommit(value string) (float64, error) {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid overcommit ratio %q: %w", value, err)
+	}
+
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		return 0, fmt.Errorf("Invalid overcommit ratio %q, must be a finite number", value)
+	}
+
+	if ratio < 1 {
+		return 0, fmt.Errorf("Invalid overcommit ratio %q, must be at least 1", value)
+	}
+
+	return ratio, nil
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gib = 1024 * 1024 * 1024
+
+func TestSelect(t *testing.T) {
+	members := []Member{
+		{Name: "small", Instances: 1, CPUs: 4, UsedCPUs: 1, Memory: 8 * gib, UsedMemory: 2 * gib},
+		{Name: "large", Instances: 5, CPUs: 32, UsedCPUs: 8, Memory: 128 * gib, UsedMemory: 32 * gib},
+		{Name: "gpu", Instances: 0, CPUs: 16, UsedCPUs: 14, Memory: 64 * gib, UsedMemory: 16 * gib, GPUs: 1},
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		req      Request
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "balanced picks the member with the most free capacity",
+			strategy: "balanced",
+			req:      Request{CPUs: 2, Memory: 4 * gib},
+			want:     "large",
+		},
+		{
+			name:     "binpack picks the fullest member that fits",
+			strategy: "binpack",
+			req:      Request{CPUs: 1, Memory: 1 * gib},
+			want:     "gpu",
+		},
+		{
+			name:     "binpack skips members without enough memory",
+			strategy: "binpack",
+			req:      Request{CPUs: 1, Memory: 64 * gib},
+			want:     "large",
+		},
+		{
+			name:     "GPU requests only fit members with free GPUs",
+			strategy: "balanced",
+			req:      Request{GPUs: 1},
+			want:     "gpu",
+		},
+		{
+			name:     "memory percentage is relative to each member",
+			strategy: "balanced",
+			req:      Request{MemoryPercent: 80},
+			wantErr:  true,
+		},
+		{
+			name:     "no member fits",
+			strategy: "balanced",
+			req:      Request{CPUs: 64},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown strategy",
+			strategy: "random",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := Select(tt.strategy, members, tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, decision.Member)
+			assert.Equal(t, tt.strategy, decision.Strategy)
+			assert.Contains(t, decision.Reason, tt.want)
+			assert.Len(t, decision.Candidates, len(members))
+		})
+	}
+}
+
+func TestSelectTieBreak(t *testing.T) {
+	members := []Member{
+		{Name: "b", Instances: 1},
+		{Name: "c", Instances: 0},
+		{Name: "a", Instances: 0},
+	}
+
+	decision, err := Select("balanced", members, Request{CPUs: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "a", decision.Member)
+}
+
+func TestParseOvercommit(t *testing.T) {
+	ratio, err := ParseOvercommit("1.5")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, ratio, 0)
+
+	for _, value := range []string{"", "0.5", "foo", "NaN", "Inf", "+Inf", "-Inf"} {
+		_, err := ParseOvercommit(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	assert.Equal(t, []string{"balanced", "binpack", StrategyInstances}, Strategies())
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+func init() {
+	Register("balanced", balanced{})
+	Register("binpack", binpack{})
+}
+
+// balanced selects the cluster member with the most free CPU and memory capacity once the instance is placed.
+type balanced struct{}
+
+// Score implements [Strategy].
+func (balanced) Score(member Member, req Request) float64 {
+	return member.FreeRatio(req)
+}
+
+// Description implements [Strategy].
+func (balanced) Description() string {
+	return "most free capacity"
+}
+
+// binpack selects the cluster member with the least free CPU and memory capacity that can still host the instance.
+// This keeps other cluster members free for large instances.
+type binpack struct{}
+
+// Score implements [Strategy].
+func (binpack) Score(member Member, req Request) float64 {
+	return -member.FreeRatio(req)
+}
+
+// Description implements [Strategy].
+func (binpack) Description() string {
+	return "least free capacity"
+}
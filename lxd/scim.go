@@ -0,0 +1,1108 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/request/security"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/scim"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// scimContentTypes are the content types accepted by the SCIM API.
+var scimContentTypes = []string{scim.ContentType, "application/json"}
+
+var scimServiceProviderConfigCmd = APIEndpoint{
+	Path: "scim/v2/ServiceProviderConfig",
+
+	Get: APIEndpointAction{Handler: scimServiceProviderConfigGet, AccessHandler: allowSCIM},
+}
+
+var scimUsersCmd = APIEndpoint{
+	Path: "scim/v2/Users",
+
+	Get:  APIEndpointAction{Handler: scimUsersGet, AccessHandler: allowSCIM},
+	Post: APIEndpointAction{Handler: scimUsersPost, AccessHandler: allowSCIM, ContentTypes: scimContentTypes},
+}
+
+var scimUserCmd = APIEndpoint{
+	Path: "scim/v2/Users/{id}",
+
+	Get:    APIEndpointAction{Handler: scimUserGet, AccessHandler: allowSCIM},
+	Put:    APIEndpointAction{Handler: scimUserPut, AccessHandler: allowSCIM, ContentTypes: scimContentTypes},
+	Patch:  APIEndpointAction{Handler: scimUserPatch, AccessHandler: allowSCIM, ContentTypes: scimContentTypes},
+	Delete: APIEndpointAction{Handler: scimUserDelete, AccessHandler: allowSCIM},
+}
+
+var scimGroupsCmd = APIEndpoint{
+	Path: "scim/v2/Groups",
+
+	Get:  APIEndpointAction{Handler: scimGroupsGet, AccessHandler: allowSCIM},
+	Post: APIEndpointAction{Handler: scimGroupsPost, AccessHandler: allowSCIM, ContentTypes: scimContentTypes},
+}
+
+var scimGroupCmd = APIEndpoint{
+	Path: "scim/v2/Groups/{id}",
+
+	Get:    APIEndpointAction{Handler: scimGroupGet, AccessHandler: allowSCIM},
+	Put:    APIEndpointAction{Handler: scimGroupPut, AccessHandler: allowSCIM, ContentTypes: scimContentTypes},
+	Patch:  APIEndpointAction{Handler: scimGroupPatch, AccessHandler: allowSCIM, ContentTypes: scimContentTypes},
+	Delete: APIEndpointAction{Handler: scimGroupDelete, AccessHandler: allowSCIM},
+}
+
+// allowSCIM is an access handler that only allows identities of type [api.IdentityTypeBearerTokenSCIM].
+// These identities do not have any other permissions, and other identities cannot use the SCIM API.
+func allowSCIM(d *Daemon, r *http.Request) response.Response {
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	if !requestor.IsIdentityType(api.IdentityTypeBearerTokenSCIM) {
+		return scimErrorResponse(api.NewStatusError(http.StatusForbidden, "The SCIM API may only be used by identities of type "+strconv.Quote(api.IdentityTypeBearerTokenSCIM)), "")
+	}
+
+	return response.EmptySyncResponse
+}
+
+// scimResponse returns a response that renders the given body as SCIM JSON with the given status code.
+func scimResponse(status int, body any) response.Response {
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		w.Header().Set("Content-Type", scim.ContentType)
+		w.WriteHeader(status)
+
+		if body == nil {
+			return nil
+		}
+
+		return json.NewEncoder(w).Encode(body)
+	})
+}
+
+// scimErrorResponse returns a response that renders the given error as a SCIM error. The status code is taken from
+// the error if it is an [api.StatusError], otherwise it is 500.
+func scimErrorResponse(err error, scimType string) response.Response {
+	status, ok := api.StatusErrorMatch(err)
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	return scimResponse(status, scim.NewError(status, scimType, err.Error()))
+}
+
+// scimBadRequest returns a SCIM error response with status code 400 and the given SCIM error type.
+func scimBadRequest(scimType string, err error) response.Response {
+	return scimResponse(http.StatusBadRequest, scim.NewError(http.StatusBadRequest, scimType, err.Error()))
+}
+
+// scimDecode decodes the request body into the given value.
+func scimDecode(r *http.Request, v any) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("Failed decoding request body: %w", err)
+	}
+
+	return nil
+}
+
+// scimParseID parses the ID of a SCIM resource. The IDs of SCIM resources are the database IDs of the corresponding
+// identities or identity provider groups.
+func scimParseID(r *http.Request, resourceType string) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return -1, api.StatusErrorf(http.StatusNotFound, "%s not found", resourceType)
+	}
+
+	return id, nil
+}
+
+// scimIdentity is an OIDC identity and its metadata.
+type scimIdentity struct {
+	dbCluster.IdentitiesRow
+
+	metadata *dbCluster.OIDCMetadata
+}
+
+// userID returns the SCIM user ID of the identity.
+func (i scimIdentity) userID() string {
+	return strconv.FormatInt(i.ID, 10)
+}
+
+// save writes the identity, including its metadata, to the database. The identity is then managed via SCIM.
+func (i *scimIdentity) save(ctx context.Context, tx *sql.Tx) error {
+	i.metadata.SCIM = true
+	metadata, err := json.Marshal(i.metadata)
+	if err != nil {
+		return fmt.Errorf("Failed encoding OIDC metadata: %w", err)
+	}
+
+	i.Metadata = string(metadata)
+	return query.UpdateByPrimaryKey(ctx, tx, i.IdentitiesRow)
+}
+
+// toSCIM converts the identity to a SCIM user. The given map of identity provider group names to IDs is used to
+// reference the groups of the user. Groups from the groups claim that are not defined in LXD are omitted.
+func (i scimIdentity) toSCIM(idpGroupIDs map[string]int64) scim.User {
+	groups := make([]scim.Reference, 0, len(i.metadata.IdentityProviderGroups))
+	for _, groupName := range i.metadata.IdentityProviderGroups {
+		groupID, ok := idpGroupIDs[groupName]
+		if !ok {
+			continue
+		}
+
+		groups = append(groups, scim.Reference{
+			Value:   strconv.FormatInt(groupID, 10),
+			Ref:     "/scim/v2/Groups/" + strconv.FormatInt(groupID, 10),
+			Display: groupName,
+		})
+	}
+
+	active := !i.metadata.Disabled
+	return scim.User{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          i.userID(),
+		ExternalID:  i.metadata.ExternalID,
+		UserName:    i.Identifier,
+		DisplayName: i.Name,
+		Emails:      []scim.Email{{Value: i.Identifier, Primary: true}},
+		Active:      &active,
+		Groups:      groups,
+		Meta: &scim.Meta{
+			ResourceType: "User",
+			Location:     "/scim/v2/Users/" + i.userID(),
+		},
+	}
+}
+
+// newSCIMIdentity returns a [scimIdentity] for the given identity. It returns an error if it is not an OIDC identity.
+func newSCIMIdentity(id dbCluster.IdentitiesRow) (*scimIdentity, error) {
+	if id.AuthMethod != api.AuthenticationMethodOIDC {
+		return nil, api.NewStatusError(http.StatusNotFound, "User not found")
+	}
+
+	metadata, err := id.OIDCMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	return &scimIdentity{IdentitiesRow: id, metadata: metadata}, nil
+}
+
+// scimGetIdentities returns all OIDC identities.
+func scimGetIdentities(ctx context.Context, tx *sql.Tx) ([]*scimIdentity, error) {
+	ids, err := query.Select[dbCluster.IdentitiesRow](ctx, tx, "WHERE auth_method = ? ORDER BY id", dbCluster.AuthMethod(api.AuthenticationMethodOIDC))
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting OIDC identities: %w", err)
+	}
+
+	identities := make([]*scimIdentity, 0, len(ids))
+	for _, id := range ids {
+		identity, err := newSCIMIdentity(id)
+		if err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+// scimGetIdentity returns the OIDC identity with the given ID.
+func scimGetIdentity(ctx context.Context, tx *sql.Tx, id int64) (*scimIdentity, error) {
+	dbIdentity, err := dbCluster.GetIdentityByID(ctx, tx, id)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, api.NewStatusError(http.StatusNotFound, "User not found")
+		}
+
+		return nil, err
+	}
+
+	return newSCIMIdentity(*dbIdentity)
+}
+
+// scimGetIdentityProviderGroupIDs returns a map of identity provider group names to IDs.
+func scimGetIdentityProviderGroupIDs(ctx context.Context, tx *sql.Tx) (map[string]int64, error) {
+	idpGroups, err := dbCluster.GetIdentityProviderGroups(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting identity provider groups: %w", err)
+	}
+
+	idpGroupIDs := make(map[string]int64, len(idpGroups))
+	for _, idpGroup := range idpGroups {
+		idpGroupIDs[idpGroup.Name] = idpGroup.ID
+	}
+
+	return idpGroupIDs, nil
+}
+
+// scimGroup converts an identity provider group to a SCIM group. The members of the group are the given identities
+// that have the group in their identity provider groups.
+func scimGroup(idpGroup dbCluster.IdentityProviderGroupsRow, identities []*scimIdentity) scim.Group {
+	groupID := strconv.FormatInt(idpGroup.ID, 10)
+	members := []scim.Reference{}
+	for _, identity := range identities {
+		if !slices.Contains(identity.metadata.IdentityProviderGroups, idpGroup.Name) {
+			continue
+		}
+
+		members = append(members, scim.Reference{
+			Value:   identity.userID(),
+			Ref:     "/scim/v2/Users/" + identity.userID(),
+			Display: identity.Identifier,
+		})
+	}
+
+	return scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          groupID,
+		DisplayName: idpGroup.Name,
+		Members:     members,
+		Meta: &scim.Meta{
+			ResourceType: "Group",
+			Location:     "/scim/v2/Groups/" + groupID,
+		},
+	}
+}
+
+// scimGroupMembers returns the set of SCIM user IDs of the identities that are members of the group with the given name.
+func scimGroupMembers(identities []*scimIdentity, groupName string) map[string]bool {
+	members := make(map[string]bool)
+	for _, identity := range identities {
+		if slices.Contains(identity.metadata.IdentityProviderGroups, groupName) {
+			members[identity.userID()] = true
+		}
+	}
+
+	return members
+}
+
+// scimSetGroupMembers updates the identity provider groups of the given identities, such that only the identities
+// with the given SCIM user IDs are members of the group. The group is renamed from oldName to newName in the identity
+// provider groups of its members. If newName is empty, the group is removed from all identities.
+func scimSetGroupMembers(ctx context.Context, tx *sql.Tx, identities []*scimIdentity, oldName string, newName string, members map[string]bool) error {
+	found := 0
+	for _, identity := range identities {
+		isMember := newName != "" && members[identity.userID()]
+		if isMember {
+			found++
+		}
+
+		groups := make([]string, 0, len(identity.metadata.IdentityProviderGroups)+1)
+		for _, groupName := range identity.metadata.IdentityProviderGroups {
+			if groupName != oldName && groupName != newName {
+				groups = append(groups, groupName)
+			}
+		}
+
+		if isMember {
+			groups = append(groups, newName)
+		}
+
+		if slices.Equal(groups, identity.metadata.IdentityProviderGroups) {
+			continue
+		}
+
+		identity.metadata.IdentityProviderGroups = groups
+		err := identity.save(ctx, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if newName != "" && found != len(members) {
+		return api.NewStatusError(http.StatusBadRequest, "One or more members are not users")
+	}
+
+	return nil
+}
+
+// scimApplyUserAttribute sets the user attribute with the given lower case name on the identity.
+// Attributes that are not mapped to LXD identities are ignored.
+func scimApplyUserAttribute(identity *scimIdentity, attribute string, value json.RawMessage) error {
+	var err error
+	switch attribute {
+	case "active":
+		var active bool
+		active, err = scim.ParseBool(value)
+		identity.metadata.Disabled = !active
+	case "displayname":
+		err = json.Unmarshal(value, &identity.Name)
+	case "externalid":
+		err = json.Unmarshal(value, &identity.metadata.ExternalID)
+	case "username":
+		var userName string
+		err = json.Unmarshal(value, &userName)
+		if err == nil && !strings.EqualFold(userName, identity.Identifier) {
+			return api.NewStatusError(http.StatusBadRequest, "The userName of a user cannot be changed")
+		}
+	}
+
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid value for attribute %q: %w", attribute, err)
+	}
+
+	return nil
+}
+
+// scimUpdateIdentity writes the identity to the database. If the identity was deactivated, all of its sessions are
+// deleted so that it cannot access LXD any more.
+func scimUpdateIdentity(ctx context.Context, tx *sql.Tx, identity *scimIdentity, wasDisabled bool) error {
+	err := identity.save(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if identity.metadata.Disabled && !wasDisabled {
+		return dbCluster.DeleteOIDCSessionsByIdentityID(ctx, tx, identity.ID)
+	}
+
+	return nil
+}
+
+// scimUserResponse returns a response containing the given identity as a SCIM user.
+func scimUserResponse(ctx context.Context, s *db.Cluster, status int, identity *scimIdentity) response.Response {
+	var idpGroupIDs map[string]int64
+	err := s.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		idpGroupIDs, err = scimGetIdentityProviderGroupIDs(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	return scimResponse(status, identity.toSCIM(idpGroupIDs))
+}
+
+// scimNotifyUser sends lifecycle and security events for a change to an identity made via SCIM.
+func scimNotifyUser(d *Daemon, r *http.Request, action lifecycle.IdentityAction, identity *scimIdentity) {
+	s := d.State()
+	notify := newIdentityNotificationFunc(s, r, s.Endpoints.NetworkCert(), s.ServerCert())
+
+	// OIDC identities are not cached, so there is no need to update the identity cache.
+	_, err := notify(action, api.AuthenticationMethodOIDC, identity.Identifier, false, true)
+	if err != nil {
+		logger.Warn("Failed sending identity lifecycle event", logger.Ctx{"identity": identity.Identifier, "err": err})
+	}
+}
+
+// scimServiceProviderConfigGet returns the SCIM features that are supported by LXD.
+func scimServiceProviderConfigGet(d *Daemon, r *http.Request) response.Response {
+	return scimResponse(http.StatusOK, scim.ServiceProviderConfig{
+		Schemas: []string{scim.SchemaServiceProviderConfig},
+		Patch:   scim.Supported{Supported: true},
+		Filter:  scim.FilterConfig{Supported: true, MaxResults: scim.MaxResults},
+		AuthenticationSchemes: []scim.AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with a bearer token issued by LXD for an identity of type " + strconv.Quote(api.IdentityTypeBearerTokenSCIM),
+			Primary:     true,
+		}},
+		Meta: &scim.Meta{
+			ResourceType: "ServiceProviderConfig",
+			Location:     "/scim/v2/ServiceProviderConfig",
+		},
+	})
+}
+
+// scimUsersGet lists the OIDC identities as SCIM users. The list can be filtered by the userName, externalId, or id
+// attributes.
+func scimUsersGet(d *Daemon, r *http.Request) response.Response {
+	startIndex, count, err := scim.ParsePagination(r.URL.Query())
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidValue, err)
+	}
+
+	var filter *scim.Filter
+	if r.URL.Query().Get("filter") != "" {
+		filter, err = scim.ParseFilter(r.URL.Query().Get("filter"))
+		if err != nil {
+			return scimBadRequest(scim.ErrorTypeInvalidFilter, err)
+		}
+
+		if !slices.Contains([]string{"username", "externalid", "id"}, filter.Attribute) {
+			return scimBadRequest(scim.ErrorTypeInvalidFilter, fmt.Errorf("Filtering users by %q is not supported", filter.Attribute))
+		}
+	}
+
+	var identities []*scimIdentity
+	var idpGroupIDs map[string]int64
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identities, err = scimGetIdentities(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		idpGroupIDs, err = scimGetIdentityProviderGroupIDs(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	users := make([]scim.User, 0, len(identities))
+	for _, identity := range identities {
+		user := identity.toSCIM(idpGroupIDs)
+		if filter != nil {
+			value := user.ID
+			switch filter.Attribute {
+			case "username":
+				value = user.UserName
+			case "externalid":
+				value = user.ExternalID
+			}
+
+			if !filter.Matches(value) {
+				continue
+			}
+		}
+
+		users = append(users, user)
+	}
+
+	return scimResponse(http.StatusOK, scim.NewListResponse(users, startIndex, count))
+}
+
+// scimUsersPost provisions an OIDC identity. The userName of the user must be the email address of the user as
+// sent in the email claim by the identity provider. If the identity already exists because the user has logged in
+// before, it is managed via SCIM from then on.
+func scimUsersPost(d *Daemon, r *http.Request) response.Response {
+	var user scim.User
+	err := scimDecode(r, &user)
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidSyntax, err)
+	}
+
+	_, err = mail.ParseAddress(user.UserName)
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidValue, fmt.Errorf("The userName %q is not a valid email address: %w", user.UserName, err))
+	}
+
+	var identity *scimIdentity
+	var created bool
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		existing, err := dbCluster.GetIdentityByAuthenticationMethodAndIdentifier(ctx, tx.Tx(), api.AuthenticationMethodOIDC, user.UserName)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		if existing != nil {
+			identity, err = newSCIMIdentity(*existing)
+			if err != nil {
+				return err
+			}
+
+			if identity.metadata.SCIM {
+				return api.StatusErrorf(http.StatusConflict, "User %q already exists", user.UserName)
+			}
+		} else {
+			id, err := query.Create(ctx, tx.Tx(), dbCluster.IdentitiesRow{
+				AuthMethod: api.AuthenticationMethodOIDC,
+				Type:       api.IdentityTypeOIDCClient,
+				Identifier: user.UserName,
+				Name:       user.FullName(),
+				Metadata:   "{}",
+			})
+			if err != nil {
+				return err
+			}
+
+			// The identity is bound to the issuer set in "oidc.issuer", which is recorded as an empty issuer. Its
+			// subject is set on the first login from that issuer.
+			created = true
+			identity = &scimIdentity{
+				IdentitiesRow: dbCluster.IdentitiesRow{ID: id, AuthMethod: api.AuthenticationMethodOIDC, Type: api.IdentityTypeOIDCClient, Identifier: user.UserName},
+				metadata:      &dbCluster.OIDCMetadata{},
+			}
+		}
+
+		wasDisabled := identity.metadata.Disabled
+		identity.Name = user.FullName()
+		identity.metadata.ExternalID = user.ExternalID
+		identity.metadata.Disabled = user.Active != nil && !*user.Active
+		return scimUpdateIdentity(ctx, tx.Tx(), identity, wasDisabled)
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return scimErrorResponse(err, scim.ErrorTypeUniqueness)
+		}
+
+		return scimErrorResponse(err, "")
+	}
+
+	action := lifecycle.IdentityUpdated
+	if created {
+		action = lifecycle.IdentityCreated
+	}
+
+	scimNotifyUser(d, r, action, identity)
+
+	return scimUserResponse(r.Context(), d.State().DB.Cluster, http.StatusCreated, identity)
+}
+
+// scimUserGet returns an OIDC identity as a SCIM user.
+func scimUserGet(d *Daemon, r *http.Request) response.Response {
+	id, err := scimParseID(r, "User")
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	var identity *scimIdentity
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = scimGetIdentity(ctx, tx.Tx(), id)
+		return err
+	})
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	return scimUserResponse(r.Context(), d.State().DB.Cluster, http.StatusOK, identity)
+}
+
+// scimUserPut replaces the name, external ID, and active status of an OIDC identity.
+func scimUserPut(d *Daemon, r *http.Request) response.Response {
+	id, err := scimParseID(r, "User")
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	var user scim.User
+	err = scimDecode(r, &user)
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidSyntax, err)
+	}
+
+	var identity *scimIdentity
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = scimGetIdentity(ctx, tx.Tx(), id)
+		if err != nil {
+			return err
+		}
+
+		if !strings.EqualFold(user.UserName, identity.Identifier) {
+			return api.NewStatusError(http.StatusBadRequest, "The userName of a user cannot be changed")
+		}
+
+		wasDisabled := identity.metadata.Disabled
+		identity.Name = user.FullName()
+		identity.metadata.ExternalID = user.ExternalID
+		identity.metadata.Disabled = user.Active != nil && !*user.Active
+		return scimUpdateIdentity(ctx, tx.Tx(), identity, wasDisabled)
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusBadRequest) {
+			return scimErrorResponse(err, scim.ErrorTypeMutability)
+		}
+
+		return scimErrorResponse(err, "")
+	}
+
+	scimNotifyUser(d, r, lifecycle.IdentityUpdated, identity)
+
+	return scimUserResponse(r.Context(), d.State().DB.Cluster, http.StatusOK, identity)
+}
+
+// scimUserPatch updates the name, external ID, or active status of an OIDC identity. Deactivating a user deletes all
+// of its sessions, and prevents it from logging in until it is activated again.
+func scimUserPatch(d *Daemon, r *http.Request) response.Response {
+	id, err := scimParseID(r, "User")
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	var patch scim.PatchRequest
+	err = scimDecode(r, &patch)
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidSyntax, err)
+	}
+
+	var identity *scimIdentity
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = scimGetIdentity(ctx, tx.Tx(), id)
+		if err != nil {
+			return err
+		}
+
+		wasDisabled := identity.metadata.Disabled
+		for _, op := range patch.Operations {
+			opName, err := scim.CheckPatchOperation(op)
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "%w", err)
+			}
+
+			attributes := map[string]json.RawMessage{}
+			if op.Path == "" {
+				// Without a path, the value contains the attributes to set.
+				var values map[string]json.RawMessage
+				err = json.Unmarshal(op.Value, &values)
+				if err != nil {
+					return api.StatusErrorf(http.StatusBadRequest, "Invalid value for operation without path: %w", err)
+				}
+
+				for attribute, value := range values {
+					attributes[strings.ToLower(attribute)] = value
+				}
+			} else if opName == "remove" {
+				// Removing an attribute sets it to its zero value.
+				switch strings.ToLower(op.Path) {
+				case "active":
+					return api.NewStatusError(http.StatusBadRequest, "The active attribute of a user cannot be removed")
+				case "displayname", "externalid":
+					attributes[strings.ToLower(op.Path)] = json.RawMessage(`""`)
+				}
+			} else {
+				attributes[strings.ToLower(op.Path)] = op.Value
+			}
+
+			for attribute, value := range attributes {
+				err = scimApplyUserAttribute(identity, attribute, value)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return scimUpdateIdentity(ctx, tx.Tx(), identity, wasDisabled)
+	})
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	scimNotifyUser(d, r, lifecycle.IdentityUpdated, identity)
+
+	return scimUserResponse(r.Context(), d.State().DB.Cluster, http.StatusOK, identity)
+}
+
+// scimUserDelete deletes an OIDC identity, including its sessions and group memberships.
+func scimUserDelete(d *Daemon, r *http.Request) response.Response {
+	id, err := scimParseID(r, "User")
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	var identity *scimIdentity
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = scimGetIdentity(ctx, tx.Tx(), id)
+		if err != nil {
+			return err
+		}
+
+		return query.DeleteByPrimaryKey(ctx, tx.Tx(), identity.IdentitiesRow)
+	})
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	scimNotifyUser(d, r, lifecycle.IdentityDeleted, identity)
+
+	return scimResponse(http.StatusNoContent, nil)
+}
+
+// scimGroupsGet lists the identity provider groups as SCIM groups. The list can be filtered by the displayName or id
+// attributes.
+func scimGroupsGet(d *Daemon, r *http.Request) response.Response {
+	startIndex, count, err := scim.ParsePagination(r.URL.Query())
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidValue, err)
+	}
+
+	var filter *scim.Filter
+	if r.URL.Query().Get("filter") != "" {
+		filter, err = scim.ParseFilter(r.URL.Query().Get("filter"))
+		if err != nil {
+			return scimBadRequest(scim.ErrorTypeInvalidFilter, err)
+		}
+
+		if !slices.Contains([]string{"displayname", "externalid", "id"}, filter.Attribute) {
+			return scimBadRequest(scim.ErrorTypeInvalidFilter, fmt.Errorf("Filtering groups by %q is not supported", filter.Attribute))
+		}
+	}
+
+	var identities []*scimIdentity
+	var idpGroups []dbCluster.IdentityProviderGroupsRow
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identities, err = scimGetIdentities(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		idpGroups, err = dbCluster.GetIdentityProviderGroups(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	groups := make([]scim.Group, 0, len(idpGroups))
+	for _, idpGroup := range idpGroups {
+		group := scimGroup(idpGroup, identities)
+		if filter != nil {
+			value := group.ID
+			switch filter.Attribute {
+			case "displayname":
+				value = group.DisplayName
+			case "externalid":
+				value = group.ExternalID
+			}
+
+			if !filter.Matches(value) {
+				continue
+			}
+		}
+
+		groups = append(groups, group)
+	}
+
+	return scimResponse(http.StatusOK, scim.NewListResponse(groups, startIndex, count))
+}
+
+// scimGroupsPost creates an identity provider group with the given members.
+func scimGroupsPost(d *Daemon, r *http.Request) response.Response {
+	var group scim.Group
+	err := scimDecode(r, &group)
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidSyntax, err)
+	}
+
+	if group.DisplayName == "" {
+		return scimBadRequest(scim.ErrorTypeInvalidValue, errors.New("The displayName of a group must be set"))
+	}
+
+	members := make(map[string]bool, len(group.Members))
+	for _, member := range group.Members {
+		members[member.Value] = true
+	}
+
+	var idpGroup dbCluster.IdentityProviderGroupsRow
+	var identities []*scimIdentity
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		idpGroup.Name = group.DisplayName
+		idpGroup.ID, err = dbCluster.CreateIdentityProviderGroup(ctx, tx.Tx(), idpGroup)
+		if err != nil {
+			return err
+		}
+
+		identities, err = scimGetIdentities(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		return scimSetGroupMembers(ctx, tx.Tx(), identities, group.DisplayName, group.DisplayName, members)
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return scimErrorResponse(api.StatusErrorf(http.StatusConflict, "Group %q already exists", group.DisplayName), scim.ErrorTypeUniqueness)
+		}
+
+		return scimErrorResponse(err, "")
+	}
+
+	s := d.State()
+	s.Events.SendLifecycle("", lifecycle.IdentityProviderGroupCreated.Event(idpGroup.Name, request.CreateRequestor(r.Context()), nil))
+	s.Events.SendSecurity(security.AuthzAdmin.WithSuffix("idp_group_create", idpGroup.Name).UserEvent(r.Context(), security.LevelInfo, "Identity provider group created"))
+
+	return scimResponse(http.StatusCreated, scimGroup(idpGroup, identities))
+}
+
+// scimGroupGet returns an identity provider group as a SCIM group.
+func scimGroupGet(d *Daemon, r *http.Request) response.Response {
+	id, err := scimParseID(r, "Group")
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	var idpGroup *dbCluster.IdentityProviderGroupsRow
+	var identities []*scimIdentity
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		idpGroup, err = scimGetIdentityProviderGroup(ctx, tx.Tx(), id)
+		if err != nil {
+			return err
+		}
+
+		identities, err = scimGetIdentities(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	return scimResponse(http.StatusOK, scimGroup(*idpGroup, identities))
+}
+
+// scimGetIdentityProviderGroup returns the identity provider group with the given ID.
+func scimGetIdentityProviderGroup(ctx context.Context, tx *sql.Tx, id int64) (*dbCluster.IdentityProviderGroupsRow, error) {
+	idpGroup, err := dbCluster.GetIdentityProviderGroupByID(ctx, tx, id)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, api.NewStatusError(http.StatusNotFound, "Group not found")
+		}
+
+		return nil, err
+	}
+
+	return idpGroup, nil
+}
+
+// scimUpdateGroup renames the identity provider group with the given ID (if the name has changed) and sets its members.
+func scimUpdateGroup(ctx context.Context, tx *sql.Tx, idpGroup *dbCluster.IdentityProviderGroupsRow, identities []*scimIdentity, newName string, members map[string]bool) error {
+	if newName == "" {
+		return api.NewStatusError(http.StatusBadRequest, "The displayName of a group must be set")
+	}
+
+	oldName := idpGroup.Name
+	if newName != oldName {
+		err := dbCluster.RenameIdentityProviderGroup(ctx, tx, oldName, newName)
+		if err != nil {
+			return err
+		}
+
+		idpGroup.Name = newName
+	}
+
+	return scimSetGroupMembers(ctx, tx, identities, oldName, newName, members)
+}
+
+// scimGroupPut replaces the name and members of an identity provider group.
+func scimGroupPut(d *Daemon, r *http.Request) response.Response {
+	id, err := scimParseID(r, "Group")
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	var group scim.Group
+	err = scimDecode(r, &group)
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidSyntax, err)
+	}
+
+	members := make(map[string]bool, len(group.Members))
+	for _, member := range group.Members {
+		members[member.Value] = true
+	}
+
+	var idpGroup *dbCluster.IdentityProviderGroupsRow
+	var identities []*scimIdentity
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		idpGroup, err = scimGetIdentityProviderGroup(ctx, tx.Tx(), id)
+		if err != nil {
+			return err
+		}
+
+		identities, err = scimGetIdentities(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		return scimUpdateGroup(ctx, tx.Tx(), idpGroup, identities, group.DisplayName, members)
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return scimErrorResponse(err, scim.ErrorTypeUniqueness)
+		}
+
+		return scimErrorResponse(err, "")
+	}
+
+	scimNotifyGroupUpdated(d, r, idpGroup.Name)
+
+	return scimResponse(http.StatusOK, scimGroup(*idpGroup, identities))
+}
+
+// scimPatchGroupMembers applies a PATCH operation on the members of a group to the given set of SCIM user IDs.
+func scimPatchGroupMembers(members map[string]bool, opName string, filter *scim.Filter, value json.RawMessage) error {
+	var references []scim.Reference
+	if len(value) > 0 {
+		err := json.Unmarshal(value, &references)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid members: %w", err)
+		}
+	}
+
+	switch opName {
+	case "replace":
+		clear(members)
+		fallthrough
+	case "add":
+		for _, reference := range references {
+			members[reference.Value] = true
+		}
+
+	case "remove":
+		if filter != nil {
+			if filter.Attribute != "value" {
+				return api.StatusErrorf(http.StatusBadRequest, "Filtering members by %q is not supported", filter.Attribute)
+			}
+
+			delete(members, filter.Value)
+		} else if len(references) > 0 {
+			for _, reference := range references {
+				delete(members, reference.Value)
+			}
+		} else {
+			clear(members)
+		}
+	}
+
+	return nil
+}
+
+// scimGroupPatch renames an identity provider group, or adds or removes members.
+func scimGroupPatch(d *Daemon, r *http.Request) response.Response {
+	id, err := scimParseID(r, "Group")
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	var patch scim.PatchRequest
+	err = scimDecode(r, &patch)
+	if err != nil {
+		return scimBadRequest(scim.ErrorTypeInvalidSyntax, err)
+	}
+
+	var idpGroup *dbCluster.IdentityProviderGroupsRow
+	var identities []*scimIdentity
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		idpGroup, err = scimGetIdentityProviderGroup(ctx, tx.Tx(), id)
+		if err != nil {
+			return err
+		}
+
+		identities, err = scimGetIdentities(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		name := idpGroup.Name
+		members := scimGroupMembers(identities, idpGroup.Name)
+		for _, op := range patch.Operations {
+			opName, err := scim.CheckPatchOperation(op)
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "%w", err)
+			}
+
+			if op.Path == "" {
+				// Without a path, the value contains the attributes to set.
+				var values map[string]json.RawMessage
+				err = json.Unmarshal(op.Value, &values)
+				if err != nil {
+					return api.StatusErrorf(http.StatusBadRequest, "Invalid value for operation without path: %w", err)
+				}
+
+				for attribute, value := range values {
+					switch strings.ToLower(attribute) {
+					case "displayname":
+						err = json.Unmarshal(value, &name)
+					case "members":
+						err = scimPatchGroupMembers(members, opName, nil, value)
+					}
+
+					if err != nil {
+						return api.StatusErrorf(http.StatusBadRequest, "Invalid value for attribute %q: %w", attribute, err)
+					}
+				}
+
+				continue
+			}
+
+			attribute, filter, err := scim.ParsePath(op.Path)
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "%w", err)
+			}
+
+			switch attribute {
+			case "displayname":
+				if opName == "remove" {
+					return api.NewStatusError(http.StatusBadRequest, "The displayName of a group cannot be removed")
+				}
+
+				err = json.Unmarshal(op.Value, &name)
+				if err != nil {
+					return api.StatusErrorf(http.StatusBadRequest, "Invalid value for attribute %q: %w", attribute, err)
+				}
+
+			case "members":
+				err = scimPatchGroupMembers(members, opName, filter, op.Value)
+				if err != nil {
+					return err
+				}
+
+			default:
+				return api.StatusErrorf(http.StatusBadRequest, "Unsupported path %q", op.Path)
+			}
+		}
+
+		return scimUpdateGroup(ctx, tx.Tx(), idpGroup, identities, name, members)
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return scimErrorResponse(err, scim.ErrorTypeUniqueness)
+		}
+
+		return scimErrorResponse(err, "")
+	}
+
+	scimNotifyGroupUpdated(d, r, idpGroup.Name)
+
+	return scimResponse(http.StatusOK, scimGroup(*idpGroup, identities))
+}
+
+// scimGroupDelete deletes an identity provider group, and removes it from the identity provider groups of its members.
+func scimGroupDelete(d *Daemon, r *http.Request) response.Response {
+	id, err := scimParseID(r, "Group")
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	var idpGroup *dbCluster.IdentityProviderGroupsRow
+	err = d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		idpGroup, err = scimGetIdentityProviderGroup(ctx, tx.Tx(), id)
+		if err != nil {
+			return err
+		}
+
+		identities, err := scimGetIdentities(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		err = scimSetGroupMembers(ctx, tx.Tx(), identities, idpGroup.Name, "", nil)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.DeleteIdentityProviderGroup(ctx, tx.Tx(), idpGroup.Name)
+	})
+	if err != nil {
+		return scimErrorResponse(err, "")
+	}
+
+	s := d.State()
+	s.Events.SendLifecycle("", lifecycle.IdentityProviderGroupDeleted.Event(idpGroup.Name, request.CreateRequestor(r.Context()), nil))
+	s.Events.SendSecurity(security.AuthzAdmin.WithSuffix("idp_group_delete", idpGroup.Name).UserEvent(r.Context(), security.LevelInfo, "Identity provider group deleted"))
+
+	return scimResponse(http.StatusNoContent, nil)
+}
+
+// scimNotifyGroupUpdated sends lifecycle and security events for a change to an identity provider group made via SCIM.
+func scimNotifyGroupUpdated(d *Daemon, r *http.Request, name string) {
+	s := d.State()
+	s.Events.SendLifecycle("", lifecycle.IdentityProviderGroupUpdated.Event(name, request.CreateRequestor(r.Context()), nil))
+	s.Events.SendSecurity(security.AuthzAdmin.WithSuffix("idp_group_edit", name).UserEvent(r.Context(), security.LevelInfo, "Identity provider group updated"))
+}
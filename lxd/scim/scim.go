@@ -0,0 +1,360 @@
+// Package scim contains the resource and message types of the System for Cross-domain Identity Management (SCIM) 2.0
+// protocol (RFC 7643 and RFC 7644) that are served by LXD.
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of SCIM requests and responses.
+const ContentType = "application/scim+json"
+
+const (
+	// SchemaUser is the schema URI of the core user resource.
+	SchemaUser = "urn:ietf:params:scim:schemas:core:2.0:User"
+
+	// SchemaGroup is the schema URI of the core group resource.
+	SchemaGroup = "urn:ietf:params:scim:schemas:core:2.0:Group"
+
+	// SchemaServiceProviderConfig is the schema URI of the service provider configuration resource.
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	// SchemaListResponse is the schema URI of list responses.
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+
+	// SchemaPatchOp is the schema URI of PATCH requests.
+	SchemaPatchOp = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+
+	// SchemaError is the schema URI of error responses.
+	SchemaError = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+const (
+	// ErrorTypeInvalidFilter indicates that the specified filter syntax was invalid or is not supported.
+	ErrorTypeInvalidFilter = "invalidFilter"
+
+	// ErrorTypeUniqueness indicates that one or more of the attribute values are already in use.
+	ErrorTypeUniqueness = "uniqueness"
+
+	// ErrorTypeMutability indicates that an attempted modification is not compatible with the mutability of the attribute.
+	ErrorTypeMutability = "mutability"
+
+	// ErrorTypeInvalidSyntax indicates that the request body structure was invalid.
+	ErrorTypeInvalidSyntax = "invalidSyntax"
+
+	// ErrorTypeInvalidPath indicates that the path attribute of a PATCH operation was invalid or is not supported.
+	ErrorTypeInvalidPath = "invalidPath"
+
+	// ErrorTypeInvalidValue indicates that a required value was missing, or that the value specified was not compatible.
+	ErrorTypeInvalidValue = "invalidValue"
+)
+
+// Meta contains the resource metadata.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location"`
+}
+
+// Name contains the components of the name of a user.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is an email address of a user.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Reference is a reference to another resource, such as a group of a user or a member of a group.
+type Reference struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+// User is the SCIM user resource.
+type User struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Name        *Name       `json:"name,omitempty"`
+	Emails      []Email     `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Groups      []Reference `json:"groups,omitempty"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+// FullName returns the display name of the user. If not set, the name of the user is formatted instead.
+func (u User) FullName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+
+	if u.Name == nil {
+		return ""
+	}
+
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+// Group is the SCIM group resource.
+type Group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+// Supported indicates whether an optional feature of the protocol is supported.
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+// BulkConfig is the configuration of bulk operations.
+type BulkConfig struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+// FilterConfig is the configuration of filtering.
+type FilterConfig struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+// AuthenticationScheme is an authentication scheme supported by the service provider.
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+// ServiceProviderConfig describes the protocol features that are supported by the service provider.
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	Patch                 Supported              `json:"patch"`
+	Bulk                  BulkConfig             `json:"bulk"`
+	Filter                FilterConfig           `json:"filter"`
+	ChangePassword        Supported              `json:"changePassword"`
+	Sort                  Supported              `json:"sort"`
+	ETag                  Supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  *Meta                  `json:"meta,omitempty"`
+}
+
+// ListResponse is the response to a query of resources.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// PatchRequest is the body of a PATCH request.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single operation of a PATCH request.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error is the body of an error response.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// NewError returns an [Error] with the given HTTP status code, SCIM error type and detail.
+func NewError(status int, scimType string, detail string) Error {
+	return Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// Filter is a filter expression of the form `<attribute> eq "<value>"`. Identity providers use this to find existing
+// resources before provisioning them. Other filter expressions are not supported.
+type Filter struct {
+	// Attribute is the lower case name of the attribute to compare.
+	Attribute string
+
+	// Value is the value to compare the attribute to.
+	Value string
+}
+
+// Matches returns true if the given value of the filter attribute is equal to the filter value.
+// Values are compared case insensitively, except for the values of the id and externalId attributes which are case exact.
+func (f Filter) Matches(value string) bool {
+	if f.Attribute == "id" || f.Attribute == "externalid" {
+		return value == f.Value
+	}
+
+	return strings.EqualFold(value, f.Value)
+}
+
+// ParseFilter parses a filter expression of the form `<attribute> eq "<value>"`.
+func ParseFilter(filter string) (*Filter, error) {
+	attribute, rest, ok := strings.Cut(strings.TrimSpace(filter), " ")
+	if !ok || attribute == "" {
+		return nil, fmt.Errorf("Invalid filter %q", filter)
+	}
+
+	operator, value, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok || !strings.EqualFold(operator, "eq") {
+		return nil, fmt.Errorf("Unsupported filter %q: Only the %q operator is supported", filter, "eq")
+	}
+
+	var s string
+	err := json.Unmarshal([]byte(strings.TrimSpace(value)), &s)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid filter %q: Value must be a string: %w", filter, err)
+	}
+
+	return &Filter{Attribute: strings.ToLower(attribute), Value: s}, nil
+}
+
+// ParsePath parses the path of a PATCH operation. The path is either an attribute name, or an attribute name with a value
+// filter (e.g. `members[value eq "1"]`). The returned attribute name is lower case. The returned filter is nil if the path
+// has no value filter.
+func ParsePath(path string) (attribute string, filter *Filter, err error) {
+	attribute, valueFilter, ok := strings.Cut(path, "[")
+	if !ok {
+		return strings.ToLower(strings.TrimSpace(path)), nil, nil
+	}
+
+	valueFilter, ok = strings.CutSuffix(valueFilter, "]")
+	if !ok {
+		return "", nil, fmt.Errorf("Invalid path %q", path)
+	}
+
+	filter, err = ParseFilter(valueFilter)
+	if err != nil {
+		return "", nil, fmt.Errorf("Invalid path %q: %w", path, err)
+	}
+
+	return strings.ToLower(strings.TrimSpace(attribute)), filter, nil
+}
+
+// ParseBool parses a boolean attribute value. Some identity providers send boolean values as strings (e.g. "False"),
+// so these are accepted as well.
+func ParseBool(value json.RawMessage) (bool, error) {
+	var b *bool
+	err := json.Unmarshal(value, &b)
+	if err == nil && b != nil {
+		return *b, nil
+	}
+
+	var s string
+	err = json.Unmarshal(value, &s)
+	if err != nil {
+		return false, fmt.Errorf("Invalid boolean value %s", value)
+	}
+
+	switch strings.ToLower(s) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("Invalid boolean value %q", s)
+}
+
+// MaxResults is the maximum number of resources that are returned in a single [ListResponse].
+const MaxResults = 1000
+
+// ParsePagination returns the one-based start index and the maximum number of resources to return from the startIndex
+// and count query parameters. The count is at most [MaxResults].
+func ParsePagination(query url.Values) (startIndex int, count int, err error) {
+	startIndex = 1
+	count = MaxResults
+
+	if query.Get("startIndex") != "" {
+		startIndex, err = strconv.Atoi(query.Get("startIndex"))
+		if err != nil {
+			return 0, 0, fmt.Errorf("Invalid startIndex: %w", err)
+		}
+
+		// A start index of less than one is interpreted as one.
+		startIndex = max(startIndex, 1)
+	}
+
+	if query.Get("count") != "" {
+		count, err = strconv.Atoi(query.Get("count"))
+		if err != nil {
+			return 0, 0, fmt.Errorf("Invalid count: %w", err)
+		}
+
+		// A negative count is interpreted as zero.
+		count = min(max(count, 0), MaxResults)
+	}
+
+	return startIndex, count, nil
+}
+
+// NewListResponse returns a [ListResponse] containing the page of the given resources specified by the one-based start
+// index and count (see [ParsePagination]).
+func NewListResponse[T any](resources []T, startIndex int, count int) ListResponse {
+	page := []any{}
+	for i := startIndex - 1; i < len(resources) && len(page) < count; i++ {
+		page = append(page, resources[i])
+	}
+
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// CheckPatchOperation returns the lower case operation name of the given [PatchOperation] after checking that it is
+// supported. The remove operation requires a path, and the add and replace operations require a value.
+func CheckPatchOperation(op PatchOperation) (string, error) {
+	name := strings.ToLower(op.Op)
+	switch name {
+	case "add", "replace":
+		if len(op.Value) == 0 {
+			return "", fmt.Errorf("The %q operation requires a value", op.Op)
+		}
+
+	case "remove":
+		if op.Path == "" {
+			return "", fmt.Errorf("The %q operation requires a path", op.Op)
+		}
+
+	default:
+		return "", fmt.Errorf("Unsupported operation %q", op.Op)
+	}
+
+	return name, nil
+}
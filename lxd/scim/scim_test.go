@@ -0,0 +1,132 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter(`userName eq "Jane.Doe@example.com"`)
+	require.NoError(t, err)
+	assert.Equal(t, Filter{Attribute: "username", Value: "Jane.Doe@example.com"}, *filter)
+	assert.True(t, filter.Matches("jane.doe@example.com"))
+	assert.False(t, filter.Matches("john.doe@example.com"))
+
+	filter, err = ParseFilter(`externalId EQ "00u1a2b3"`)
+	require.NoError(t, err)
+	assert.Equal(t, "externalid", filter.Attribute)
+	assert.True(t, filter.Matches("00u1a2b3"))
+	assert.False(t, filter.Matches("00U1A2B3"))
+
+	// Values may contain spaces and escaped quotes.
+	filter, err = ParseFilter(`displayName eq "LXD \"admins\" team"`)
+	require.NoError(t, err)
+	assert.Equal(t, `LXD "admins" team`, filter.Value)
+
+	for _, invalid := range []string{``, `userName`, `userName eq`, `userName co "jane"`, `userName eq jane`, `userName eq "jane" and active eq true`} {
+		_, err = ParseFilter(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	attribute, filter, err := ParsePath("displayName")
+	require.NoError(t, err)
+	assert.Equal(t, "displayname", attribute)
+	assert.Nil(t, filter)
+
+	attribute, filter, err = ParsePath(`members[value eq "42"]`)
+	require.NoError(t, err)
+	assert.Equal(t, "members", attribute)
+	assert.Equal(t, &Filter{Attribute: "value", Value: "42"}, filter)
+
+	_, _, err = ParsePath(`members[value eq "42"`)
+	assert.Error(t, err)
+
+	_, _, err = ParsePath(`members[value gt "42"]`)
+	assert.Error(t, err)
+}
+
+func TestParseBool(t *testing.T) {
+	for value, expected := range map[string]bool{`true`: true, `false`: false, `"True"`: true, `"False"`: false} {
+		b, err := ParseBool(json.RawMessage(value))
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, b, value)
+	}
+
+	for _, invalid := range []string{`1`, `"yes"`, `null`, `{}`} {
+		_, err := ParseBool(json.RawMessage(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNewListResponse(t *testing.T) {
+	resources := []string{"a", "b", "c"}
+
+	pagination := func(query string) (int, int) {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+
+		startIndex, count, err := ParsePagination(values)
+		require.NoError(t, err)
+
+		return startIndex, count
+	}
+
+	startIndex, count := pagination("")
+	assert.Equal(t, MaxResults, count)
+	list := NewListResponse(resources, startIndex, count)
+	assert.Equal(t, []string{SchemaListResponse}, list.Schemas)
+	assert.Equal(t, 3, list.TotalResults)
+	assert.Equal(t, 3, list.ItemsPerPage)
+	assert.Equal(t, []any{"a", "b", "c"}, list.Resources)
+
+	startIndex, count = pagination("startIndex=2&count=1")
+	list = NewListResponse(resources, startIndex, count)
+	assert.Equal(t, 3, list.TotalResults)
+	assert.Equal(t, 2, list.StartIndex)
+	assert.Equal(t, []any{"b"}, list.Resources)
+
+	// Out of range start indexes and counts are clamped.
+	startIndex, count = pagination("startIndex=0&count=-5")
+	list = NewListResponse(resources, startIndex, count)
+	assert.Equal(t, 1, list.StartIndex)
+	assert.Equal(t, []any{}, list.Resources)
+
+	startIndex, count = pagination("startIndex=10&count=5000")
+	assert.Equal(t, MaxResults, count)
+	list = NewListResponse(resources, startIndex, count)
+	assert.Equal(t, 3, list.TotalResults)
+	assert.Equal(t, 0, list.ItemsPerPage)
+
+	// The list of resources is always encoded as an array.
+	b, err := json.Marshal(NewListResponse([]string{}, 1, MaxResults))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schemas":["`+SchemaListResponse+`"],"totalResults":0,"startIndex":1,"itemsPerPage":0,"Resources":[]}`, string(b))
+
+	_, _, err = ParsePagination(url.Values{"count": []string{"ten"}})
+	assert.Error(t, err)
+}
+
+func TestCheckPatchOperation(t *testing.T) {
+	op, err := CheckPatchOperation(PatchOperation{Op: "Replace", Value: json.RawMessage(`{"active":false}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "replace", op)
+
+	op, err = CheckPatchOperation(PatchOperation{Op: "remove", Path: `members[value eq "1"]`})
+	require.NoError(t, err)
+	assert.Equal(t, "remove", op)
+
+	_, err = CheckPatchOperation(PatchOperation{Op: "add", Path: "members"})
+	assert.Error(t, err)
+
+	_, err = CheckPatchOperation(PatchOperation{Op: "remove"})
+	assert.Error(t, err)
+
+	_, err = CheckPatchOperation(PatchOperation{Op: "move", Path: "members", Value: json.RawMessage(`[]`)})
+	assert.Error(t, err)
+}
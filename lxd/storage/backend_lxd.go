@@ -3,6 +3,7 @@ package storage
 import (
 	"archive/tar"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -378,6 +379,73 @@ func (b *lxdBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return b.driver.GetResources()
 }
 
+// checkCapacity checks whether a new volume can be created in the pool according to the pool usage settings.
+// New volumes are rejected when the pool usage is above its enforced critical threshold, or when the volume
+// would leave less than the reserved space free once it and the existing volumes are fully allocated.
+func (b *lxdBackend) checkCapacity(vol drivers.Volume) error {
+	enforce := shared.IsTrue(b.db.Config["usage.critical.enforce"]) && b.db.Config["usage.critical"] != ""
+	if !enforce && b.db.Config["usage.reserved"] == "" {
+		return nil
+	}
+
+	res, err := b.driver.GetResources()
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotSupported) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed getting storage pool usage: %w", err)
+	}
+
+	if res.Space.Total == 0 {
+		return nil
+	}
+
+	if enforce {
+		critical, err := strconv.ParseUint(b.db.Config["usage.critical"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid critical usage threshold: %w", err)
+		}
+
+		usage := res.Space.Used * 100 / res.Space.Total
+		if usage >= critical {
+			return api.StatusErrorf(http.StatusInsufficientStorage, "Storage pool %q usage (%d%%) is above its critical threshold (%d%%)", b.name, usage, critical)
+		}
+	}
+
+	if b.db.Config["usage.reserved"] != "" {
+		reserved, err := units.ParseByteSizeString(b.db.Config["usage.reserved"])
+		if err != nil {
+			return fmt.Errorf("Invalid reserved space: %w", err)
+		}
+
+		var size int64
+		if vol.ConfigSize() != "" {
+			size, err = units.ParseByteSizeString(vol.ConfigSize())
+			if err != nil {
+				return err
+			}
+		}
+
+		// Thin-provisioned volumes can grow up to their size, so account for their provisioned size rather than
+		// for the space they currently use.
+		provisioned, err := b.driver.GetProvisionedSpace()
+		if err != nil {
+			if !errors.Is(err, drivers.ErrNotSupported) {
+				return fmt.Errorf("Failed getting storage pool provisioned space: %w", err)
+			}
+
+			provisioned = res.Space.Used
+		}
+
+		if provisioned+uint64(size)+uint64(reserved) > res.Space.Total {
+			return api.StatusErrorf(http.StatusInsufficientStorage, "Creating the volume would use space reserved on storage pool %q (%s reserved)", b.name, b.db.Config["usage.reserved"])
+		}
+	}
+
+	return nil
+}
+
 // IsUsed returns whether the storage pool is used by any volumes or profiles (excluding image volumes).
 func (b *lxdBackend) IsUsed() (bool, error) {
 	usedBy, err := UsedBy(context.TODO(), b.state, b, true, true, cluster.StoragePoolVolumeTypeNameImage)
@@ -777,6 +845,12 @@ func (b *lxdBackend) CreateInstance(inst instance.Instance, progressReporter iop
 		return err
 	}
 
+	// Check that the pool has enough capacity for the new volume.
+	err = b.checkCapacity(vol)
+	if err != nil {
+		return err
+	}
+
 	// Validate config and create database entry for new storage volume.
 	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, true, false)
 	if err != nil {
@@ -901,6 +975,12 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 	// Don't use GetNewVolume as the new volume' UUID got already set beforehand.
 	vol := b.GetVolume(volType, contentType, volStorageName, volumeConfig)
 
+	// Check that the pool has enough capacity for the new volume.
+	err = b.checkCapacity(vol)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	sourceSnapshots := make([]drivers.Volume, 0, len(rootVol.Snapshots))
 	for i, volSnap := range rootVol.Snapshots {
 		if volSnap == nil {
@@ -1222,6 +1302,12 @@ func (b *lxdBackend) CreateInstanceFromCopy(ctx context.Context, inst instance.I
 		return err
 	}
 
+	// Check that the pool has enough capacity for the new volume.
+	err = b.checkCapacity(vol)
+	if err != nil {
+		return err
+	}
+
 	volExists, err := b.driver.HasVolume(vol)
 	if err != nil {
 		return err
@@ -2189,6 +2275,12 @@ func (b *lxdBackend) CreateInstanceFromImage(ctx context.Context, inst instance.
 	volStorageName := project.Instance(inst.Project().Name, inst.Name())
 	vol := b.GetNewVolume(volType, contentType, volStorageName, volumeConfig)
 
+	// Check that the pool has enough capacity for the new volume.
+	err = b.checkCapacity(vol)
+	if err != nil {
+		return err
+	}
+
 	// Set the parent volume UUID.
 	if b.driver.Info().PopulateParentVolumeUUID {
 		parentUUID, err := b.getParentVolumeUUID(vol, inst.Project().Name)
@@ -2365,6 +2457,12 @@ func (b *lxdBackend) CreateInstanceFromMigration(ctx context.Context, inst insta
 		vol = b.GetVolume(volType, contentType, volStorageName, volumeConfig)
 	} else {
 		vol = b.GetNewVolume(volType, contentType, volStorageName, volumeConfig)
+
+		// Check that the pool has enough capacity for the new volume.
+		err = b.checkCapacity(vol)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Ensure storage volume settings are honored when doing migration.
@@ -4873,6 +4971,87 @@ func (b *lxdBackend) GetBucketURL(bucketName string) *url.URL {
 	return b.driver.GetBucketURL(bucketName)
 }
 
+// getBucketVolume returns the driver volume of an existing bucket.
+func (b *lxdBackend) getBucketVolume(projectName string, bucketName string) (drivers.Volume, error) {
+	err := b.isStatusReady()
+	if err != nil {
+		return drivers.Volume{}, err
+	}
+
+	if !b.Driver().Info().Buckets {
+		return drivers.Volume{}, errors.New("Storage pool does not support buckets")
+	}
+
+	var bucket *db.StorageBucket
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, false, bucketName)
+		return err
+	})
+	if err != nil {
+		return drivers.Volume{}, err
+	}
+
+	bucketVolName := project.StorageVolume(projectName, bucket.Name)
+
+	return b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, bucketVolName, bucket.Config), nil
+}
+
+// ListBucketObjects returns the objects of a bucket whose name starts with prefix.
+func (b *lxdBackend) ListBucketObjects(projectName string, bucketName string, prefix string) ([]api.StorageBucketObject, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "bucketName": bucketName, "prefix": prefix})
+	l.Debug("ListBucketObjects started")
+	defer l.Debug("ListBucketObjects finished")
+
+	bucketVol, err := b.getBucketVolume(projectName, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.driver.ListBucketObjects(bucketVol, prefix)
+}
+
+// GetBucketObject returns the content and size of a bucket object. The caller must close the returned reader.
+func (b *lxdBackend) GetBucketObject(projectName string, bucketName string, objectName string) (io.ReadCloser, int64, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "bucketName": bucketName, "objectName": objectName})
+	l.Debug("GetBucketObject started")
+	defer l.Debug("GetBucketObject finished")
+
+	bucketVol, err := b.getBucketVolume(projectName, bucketName)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return b.driver.GetBucketObject(bucketVol, objectName)
+}
+
+// PutBucketObject stores an object in a bucket, replacing any existing object with the same name.
+func (b *lxdBackend) PutBucketObject(projectName string, bucketName string, objectName string, data io.Reader, size int64) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "bucketName": bucketName, "objectName": objectName, "size": size})
+	l.Debug("PutBucketObject started")
+	defer l.Debug("PutBucketObject finished")
+
+	bucketVol, err := b.getBucketVolume(projectName, bucketName)
+	if err != nil {
+		return err
+	}
+
+	return b.driver.PutBucketObject(bucketVol, objectName, data, size)
+}
+
+// DeleteBucketObject deletes an object from a bucket.
+func (b *lxdBackend) DeleteBucketObject(projectName string, bucketName string, objectName string) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "bucketName": bucketName, "objectName": objectName})
+	l.Debug("DeleteBucketObject started")
+	defer l.Debug("DeleteBucketObject finished")
+
+	bucketVol, err := b.getBucketVolume(projectName, bucketName)
+	if err != nil {
+		return err
+	}
+
+	return b.driver.DeleteBucketObject(bucketVol, objectName)
+}
+
 // CreateCustomVolume creates an empty custom volume.
 func (b *lxdBackend) CreateCustomVolume(ctx context.Context, projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, progressReporter ioprogress.ProgressReporter) error {
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "desc": desc, "config": config, "contentType": contentType})
@@ -4888,6 +5067,12 @@ func (b *lxdBackend) CreateCustomVolume(ctx context.Context, projectName string,
 	volStorageName := project.StorageVolume(projectName, volName)
 	vol := b.GetNewVolume(drivers.VolumeTypeCustom, contentType, volStorageName, config)
 
+	// Check that the pool has enough capacity for the new volume.
+	err = b.checkCapacity(vol)
+	if err != nil {
+		return err
+	}
+
 	storagePoolSupported := slices.Contains(b.Driver().Info().VolumeTypes, drivers.VolumeTypeCustom)
 
 	if !storagePoolSupported {
@@ -5033,6 +5218,12 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(ctx context.Context, projectName
 		volStorageName := project.StorageVolume(projectName, volName)
 		vol := b.GetNewVolume(drivers.VolumeTypeCustom, contentType, volStorageName, config)
 
+		// Check that the pool has enough capacity for the new volume.
+		err = b.checkCapacity(vol)
+		if err != nil {
+			return err
+		}
+
 		// Validate config and create database entry for new storage volume.
 		err = VolumeDBCreate(b, projectName, volName, desc, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), false, true)
 		if err != nil {
@@ -5413,6 +5604,12 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(ctx context.Context, projec
 		vol = b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(args.ContentType), volStorageName, volumeConfig)
 	} else {
 		vol = b.GetNewVolume(drivers.VolumeTypeCustom, drivers.ContentType(args.ContentType), volStorageName, volumeConfig)
+
+		// Check that the pool has enough capacity for the new volume.
+		err = b.checkCapacity(vol)
+		if err != nil {
+			return err
+		}
 	}
 
 	volExists, err := b.driver.HasVolume(vol)
@@ -6702,6 +6899,370 @@ func (b *lxdBackend) RestoreCustomVolume(ctx context.Context, projectName string
 	return nil
 }
 
+// diffVolumeSnapshots loads the two snapshots of a volume to compare and checks that fromSnapshot is the
+// older of the two.
+func (b *lxdBackend) diffVolumeSnapshots(projectName string, volType drivers.VolumeType, volName string, fromSnapshot string, toSnapshot string) (drivers.Volume, drivers.Volume, error) {
+	if shared.IsSnapshot(volName) {
+		return drivers.Volume{}, drivers.Volume{}, errors.New("Volume cannot be snapshot")
+	}
+
+	if fromSnapshot == "" || shared.IsSnapshot(fromSnapshot) || shared.IsSnapshot(toSnapshot) {
+		return drivers.Volume{}, drivers.Volume{}, api.StatusErrorf(http.StatusBadRequest, "Invalid snapshot name")
+	}
+
+	if fromSnapshot == toSnapshot {
+		return drivers.Volume{}, drivers.Volume{}, api.StatusErrorf(http.StatusBadRequest, "Cannot compare a snapshot with itself")
+	}
+
+	// Get the volume name on storage.
+	storageName := func(name string) string {
+		if volType == drivers.VolumeTypeCustom {
+			return project.StorageVolume(projectName, name)
+		}
+
+		return project.Instance(projectName, name)
+	}
+
+	parentVol, err := VolumeDBGet(b, projectName, volName, volType)
+	if err != nil {
+		return drivers.Volume{}, drivers.Volume{}, err
+	}
+
+	var snapVols []drivers.Volume
+	var createdAt []time.Time
+
+	for _, snapshotName := range []string{fromSnapshot, toSnapshot} {
+		dbSnapVol, err := VolumeDBGet(b, projectName, drivers.GetSnapshotVolumeName(volName, snapshotName), volType)
+		if err != nil {
+			return drivers.Volume{}, drivers.Volume{}, err
+		}
+
+		snapVol := b.GetVolume(volType, drivers.ContentType(dbSnapVol.ContentType), storageName(dbSnapVol.Name), dbSnapVol.Config)
+		if b.driver.Info().PopulateParentVolumeUUID {
+			snapVol.SetParentUUID(parentVol.Config["volatile.uuid"])
+		}
+
+		snapVols = append(snapVols, snapVol)
+		createdAt = append(createdAt, dbSnapVol.CreatedAt)
+	}
+
+	if createdAt[0].After(createdAt[1]) {
+		return drivers.Volume{}, drivers.Volume{}, api.StatusErrorf(http.StatusBadRequest, "Snapshot %q is more recent than snapshot %q", fromSnapshot, toSnapshot)
+	}
+
+	return snapVols[0], snapVols[1], nil
+}
+
+// DiffVolumeSnapshots reports the changed blocks (for block volumes) or changed files (for filesystem volumes)
+// between two snapshots of a custom or instance volume.
+func (b *lxdBackend) DiffVolumeSnapshots(projectName string, volType drivers.VolumeType, volName string, fromSnapshot string, toSnapshot string, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volType": volType, "volName": volName, "fromSnapshot": fromSnapshot, "toSnapshot": toSnapshot})
+	l.Debug("DiffVolumeSnapshots started")
+	defer l.Debug("DiffVolumeSnapshots finished")
+
+	fromSnapVol, toSnapVol, err := b.diffVolumeSnapshots(projectName, volType, volName, fromSnapshot, toSnapshot)
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.DiffVolumeSnapshots(fromSnapVol, toSnapVol, changeFunc, progressReporter)
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotSupported) {
+			return api.StatusErrorf(http.StatusNotImplemented, "Storage driver %q doesn't support snapshot diffs", b.driver.Info().Name)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// ExportVolumeSnapshotDiff writes the content that changed between two snapshots of a volume.
+// For block volumes, each changed extent is written as its big endian 64bit offset and length followed by its
+// data. For filesystem volumes, a tarball of the added and modified files is written. Deleted files are only
+// reported by DiffVolumeSnapshots.
+func (b *lxdBackend) ExportVolumeSnapshotDiff(projectName string, volType drivers.VolumeType, volName string, fromSnapshot string, toSnapshot string, w io.Writer, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volType": volType, "volName": volName, "fromSnapshot": fromSnapshot, "toSnapshot": toSnapshot})
+	l.Debug("ExportVolumeSnapshotDiff started")
+	defer l.Debug("ExportVolumeSnapshotDiff finished")
+
+	fromSnapVol, toSnapVol, err := b.diffVolumeSnapshots(projectName, volType, volName, fromSnapshot, toSnapshot)
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.MountVolumeSnapshot(toSnapVol, progressReporter)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = b.driver.UnmountVolumeSnapshot(toSnapVol, progressReporter) }()
+
+	// Each change is written as soon as the driver reports it, so that large diffs aren't held in memory.
+	var writeChange func(change api.StorageVolumeSnapshotChange) error
+	var closeFunc func() error
+
+	if drivers.IsContentBlock(toSnapVol.ContentType()) {
+		diskPath, err := b.driver.GetVolumeDiskPath(toSnapVol)
+		if err != nil {
+			return err
+		}
+
+		disk, err := os.Open(diskPath)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = disk.Close() }()
+
+		header := make([]byte, 16)
+		writeChange = func(change api.StorageVolumeSnapshotChange) error {
+			binary.BigEndian.PutUint64(header[0:8], uint64(change.Offset))
+			binary.BigEndian.PutUint64(header[8:16], uint64(change.Length))
+
+			_, err := w.Write(header)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(w, io.NewSectionReader(disk, change.Offset, change.Length))
+			if err != nil {
+				return fmt.Errorf("Failed exporting extent at offset %d: %w", change.Offset, err)
+			}
+
+			return nil
+		}
+
+		closeFunc = disk.Close
+	} else {
+		tarWriter := instancewriter.NewInstanceTarWriter(w, nil)
+		mountPath := toSnapVol.MountPath()
+
+		writeChange = func(change api.StorageVolumeSnapshotChange) error {
+			if change.Type == api.StorageVolumeSnapshotChangeDeleted {
+				return nil
+			}
+
+			srcPath := filepath.Join(mountPath, change.Path)
+			fi, err := os.Lstat(srcPath)
+			if err != nil {
+				return err
+			}
+
+			err = tarWriter.WriteFile(change.Path, srcPath, fi, false)
+			if err != nil {
+				return fmt.Errorf("Failed exporting %q: %w", change.Path, err)
+			}
+
+			return nil
+		}
+
+		closeFunc = tarWriter.Close
+	}
+
+	err = b.driver.DiffVolumeSnapshots(fromSnapVol, toSnapVol, writeChange, progressReporter)
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotSupported) {
+			return api.StatusErrorf(http.StatusNotImplemented, "Storage driver %q doesn't support snapshot diffs", b.driver.Info().Name)
+		}
+
+		return err
+	}
+
+	return closeFunc()
+}
+
+// ScrubPool verifies the integrity of all data stored in the pool.
+func (b *lxdBackend) ScrubPool(progressReporter ioprogress.ProgressReporter) error {
+	b.logger.Debug("ScrubPool started")
+	defer b.logger.Debug("ScrubPool finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	return b.driver.ScrubPool(progressReporter)
+}
+
+// CheckVolume verifies the integrity of an unused instance, custom or image volume.
+// Returns drivers.ErrInUse if the volume is in use and drivers.ErrNotSupported if the driver can't check it.
+// The cached files of images are also verified against the image fingerprint.
+func (b *lxdBackend) CheckVolume(projectName string, volType drivers.VolumeType, volName string, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volType": volType, "volName": volName})
+	l.Debug("CheckVolume started")
+	defer l.Debug("CheckVolume finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if shared.IsSnapshot(volName) {
+		return errors.New("Volume cannot be snapshot")
+	}
+
+	dbVol, err := VolumeDBGet(b, projectName, volName, volType)
+	if err != nil {
+		return err
+	}
+
+	var volStorageName string
+	switch volType {
+	case drivers.VolumeTypeCustom:
+		volStorageName = project.StorageVolume(projectName, volName)
+	case drivers.VolumeTypeImage:
+		volStorageName = volName
+	default:
+		volStorageName = project.Instance(projectName, volName)
+	}
+
+	vol := b.GetVolume(volType, drivers.ContentType(dbVol.ContentType), volStorageName, dbVol.Config)
+
+	imageVerified := false
+	if volType == drivers.VolumeTypeImage {
+		imageVerified, err = verifyImageFiles(volName)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = b.driver.CheckVolume(vol, progressReporter)
+
+	// Also check the filesystem volume holding the VM config.
+	if vol.IsVMBlock() && (err == nil || errors.Is(err, drivers.ErrNotSupported)) {
+		fsErr := b.driver.CheckVolume(vol.NewVMBlockFilesystemVolume(), progressReporter)
+		if !errors.Is(fsErr, drivers.ErrNotSupported) {
+			err = fsErr
+		}
+	}
+
+	if errors.Is(err, drivers.ErrNotSupported) && imageVerified {
+		return nil
+	}
+
+	return err
+}
+
+// TrashVolumeName returns the name a trashed volume is kept under on the storage pool.
+func TrashVolumeName(trashUUID string) string {
+	return "trash-" + trashUUID
+}
+
+// trashVolumes returns the root volume described by the backup config along with the same volume using the
+// name it is kept under while in the trash.
+func (b *lxdBackend) trashVolumes(projectName string, poolVol *backupConfig.Config, trashUUID string) (drivers.Volume, drivers.Volume, error) {
+	if b.driver.Info().UUIDVolumeNames {
+		return drivers.Volume{}, drivers.Volume{}, api.StatusErrorf(http.StatusBadRequest, "Storage driver %q doesn't support deletion retention", b.driver.Info().Name)
+	}
+
+	rootVol, err := poolVol.RootVolume()
+	if err != nil {
+		return drivers.Volume{}, drivers.Volume{}, fmt.Errorf("Failed getting the root volume: %w", err)
+	}
+
+	volType := drivers.VolumeType(rootVol.Type)
+	contentType := drivers.ContentType(rootVol.ContentType)
+
+	var volStorageName, trashStorageName string
+	switch volType {
+	case drivers.VolumeTypeContainer, drivers.VolumeTypeVM:
+		volStorageName = project.Instance(projectName, rootVol.Name)
+		trashStorageName = project.Instance(projectName, TrashVolumeName(trashUUID))
+	case drivers.VolumeTypeCustom:
+		volStorageName = project.StorageVolume(projectName, rootVol.Name)
+		trashStorageName = project.StorageVolume(projectName, TrashVolumeName(trashUUID))
+	default:
+		return drivers.Volume{}, drivers.Volume{}, fmt.Errorf("Volumes of type %q cannot be trashed", volType)
+	}
+
+	vol := b.GetVolume(volType, contentType, volStorageName, rootVol.Config)
+	trashVol := b.GetVolume(volType, contentType, trashStorageName, rootVol.Config)
+
+	return vol, trashVol, nil
+}
+
+// TrashVolume moves the volume described by the backup config and its snapshots into the trash by renaming them
+// on the storage device. The database records are left untouched and must be removed by the caller.
+func (b *lxdBackend) TrashVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "trash": trashUUID})
+	l.Debug("TrashVolume started")
+	defer l.Debug("TrashVolume finished")
+
+	vol, trashVol, err := b.trashVolumes(projectName, poolVol, trashUUID)
+	if err != nil {
+		return err
+	}
+
+	return b.driver.RenameVolume(vol, trashVol.Name(), progressReporter)
+}
+
+// RestoreTrashedVolume moves a trashed volume and its snapshots back under the name of the root volume found in
+// the backup config. The database records must be recreated by the caller.
+func (b *lxdBackend) RestoreTrashedVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "trash": trashUUID})
+	l.Debug("RestoreTrashedVolume started")
+	defer l.Debug("RestoreTrashedVolume finished")
+
+	vol, trashVol, err := b.trashVolumes(projectName, poolVol, trashUUID)
+	if err != nil {
+		return err
+	}
+
+	volExists, err := b.driver.HasVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if volExists {
+		return api.StatusErrorf(http.StatusConflict, "Volume %q already exists on the storage pool", vol.Name())
+	}
+
+	return b.driver.RenameVolume(trashVol, vol.Name(), progressReporter)
+}
+
+// PurgeTrashedVolume permanently deletes a trashed volume and its snapshots from the storage device.
+func (b *lxdBackend) PurgeTrashedVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "trash": trashUUID})
+	l.Debug("PurgeTrashedVolume started")
+	defer l.Debug("PurgeTrashedVolume finished")
+
+	_, trashVol, err := b.trashVolumes(projectName, poolVol, trashUUID)
+	if err != nil {
+		return err
+	}
+
+	volExists, err := b.driver.HasVolume(trashVol)
+	if err != nil {
+		return err
+	}
+
+	if !volExists {
+		return nil
+	}
+
+	// Snapshots must be removed before their parent volume.
+	snapshots, err := b.driver.VolumeSnapshots(trashVol)
+	if err != nil {
+		return err
+	}
+
+	for _, snapName := range snapshots {
+		snapVol := b.GetVolume(trashVol.Type(), trashVol.ContentType(), drivers.GetSnapshotVolumeName(trashVol.Name(), snapName), trashVol.Config())
+
+		if b.driver.Info().PopulateParentVolumeUUID {
+			snapVol.SetParentUUID(trashVol.Config()["volatile.uuid"])
+		}
+
+		err = b.driver.DeleteVolumeSnapshot(snapVol, progressReporter)
+		if err != nil {
+			return fmt.Errorf("Failed deleting trashed volume snapshot %q: %w", snapName, err)
+		}
+	}
+
+	return b.driver.DeleteVolume(trashVol, progressReporter)
+}
+
 func (b *lxdBackend) createStorageStructure(path string) error {
 	for _, volType := range b.driver.Info().VolumeTypes {
 		for _, name := range drivers.BaseDirectories[volType].Paths {
@@ -7164,6 +7725,32 @@ func (b *lxdBackend) ListUnknownVolumes(progressReporter ioprogress.ProgressRepo
 		return nil, fmt.Errorf("Failed getting pool volumes: %w", err)
 	}
 
+	// Skip the volumes kept in the trash as they are not lost.
+	var trashEntries []cluster.TrashEntry
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID := b.ID()
+		trashEntries, err = cluster.GetTrashEntries(ctx, tx.Tx(), cluster.TrashEntryFilter{StoragePoolID: &poolID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(trashEntries) > 0 {
+		trashVolNames := make(map[string]bool, len(trashEntries))
+		for _, entry := range trashEntries {
+			if entry.Row.Type == api.TrashEntryTypeCustom {
+				trashVolNames[project.StorageVolume(entry.ProjectName, TrashVolumeName(entry.Row.UUID))] = true
+			} else {
+				trashVolNames[project.Instance(entry.ProjectName, TrashVolumeName(entry.Row.UUID))] = true
+			}
+		}
+
+		poolVols = slices.DeleteFunc(poolVols, func(poolVol drivers.Volume) bool {
+			return trashVolNames[poolVol.Name()]
+		})
+	}
+
 	poolVols, err = b.normalizeUnknownVolumes(context.TODO(), poolVols)
 	if err != nil {
 		return nil, fmt.Errorf("Failed resolving pool volumes: %w", err)
@@ -7968,6 +8555,12 @@ func (b *lxdBackend) CreateCustomVolumeFromISO(ctx context.Context, projectName
 
 	vol := b.GetNewVolume(drivers.VolumeTypeCustom, drivers.ContentTypeISO, volStorageName, req.Config)
 
+	// Check that the pool has enough capacity for the new volume.
+	err = b.checkCapacity(vol)
+	if err != nil {
+		return err
+	}
+
 	volExists, err := b.driver.HasVolume(vol)
 	if err != nil {
 		return err
@@ -8046,6 +8639,12 @@ func (b *lxdBackend) CreateCustomVolumeFromTarball(ctx context.Context, projectN
 
 	vol := b.GetNewVolume(drivers.VolumeTypeCustom, drivers.ContentTypeFS, volStorageName, req.Config)
 
+	// Check that the pool has enough capacity for the new volume.
+	err = b.checkCapacity(vol)
+	if err != nil {
+		return err
+	}
+
 	volExists, err := b.driver.HasVolume(vol)
 	if err != nil {
 		return err
@@ -8180,6 +8779,12 @@ func (b *lxdBackend) CreateCustomVolumeFromBackup(ctx context.Context, srcBackup
 
 	vol := b.GetNewVolume(drivers.VolumeTypeCustom, drivers.ContentType(customVol.ContentType), volStorageName, customVol.Config)
 
+	// Check that the pool has enough capacity for the new volume.
+	err = b.checkCapacity(vol)
+	if err != nil {
+		return err
+	}
+
 	volExists, err := b.driver.HasVolume(vol)
 	if err != nil {
 		return err
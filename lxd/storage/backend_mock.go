@@ -345,6 +345,26 @@ func (b *mockBackend) GetBucketURL(bucketName string) *url.URL {
 	return nil
 }
 
+// ListBucketObjects ...
+func (b *mockBackend) ListBucketObjects(projectName string, bucketName string, prefix string) ([]api.StorageBucketObject, error) {
+	return nil, nil
+}
+
+// GetBucketObject ...
+func (b *mockBackend) GetBucketObject(projectName string, bucketName string, objectName string) (io.ReadCloser, int64, error) {
+	return nil, -1, nil
+}
+
+// PutBucketObject ...
+func (b *mockBackend) PutBucketObject(projectName string, bucketName string, objectName string, data io.Reader, size int64) error {
+	return nil
+}
+
+// DeleteBucketObject ...
+func (b *mockBackend) DeleteBucketObject(projectName string, bucketName string, objectName string) error {
+	return nil
+}
+
 // CreateCustomVolume ...
 func (b *mockBackend) CreateCustomVolume(ctx context.Context, projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -425,6 +445,31 @@ func (b *mockBackend) RestoreCustomVolume(ctx context.Context, projectName strin
 	return nil
 }
 
+// DiffVolumeSnapshots ...
+func (b *mockBackend) DiffVolumeSnapshots(projectName string, volType drivers.VolumeType, volName string, fromSnapshot string, toSnapshot string, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
+// ExportVolumeSnapshotDiff ...
+func (b *mockBackend) ExportVolumeSnapshotDiff(projectName string, volType drivers.VolumeType, volName string, fromSnapshot string, toSnapshot string, w io.Writer, progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
+// TrashVolume ...
+func (b *mockBackend) TrashVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
+// RestoreTrashedVolume ...
+func (b *mockBackend) RestoreTrashedVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
+// PurgeTrashedVolume ...
+func (b *mockBackend) PurgeTrashedVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
 // BackupCustomVolume ...
 func (b *mockBackend) BackupCustomVolume(projectName string, volName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -444,3 +489,13 @@ func (b *mockBackend) CreateCustomVolumeFromISO(ctx context.Context, projectName
 func (b *mockBackend) CreateCustomVolumeFromTarball(ctx context.Context, projectName string, volName string, srcData *os.File, progressReporter ioprogress.ProgressReporter) error {
 	return nil
 }
+
+// ScrubPool ...
+func (b *mockBackend) ScrubPool(progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
+// CheckVolume ...
+func (b *mockBackend) CheckVolume(projectName string, volType drivers.VolumeType, volName string, progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
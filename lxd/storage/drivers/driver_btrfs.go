@@ -521,6 +521,17 @@ func (d *btrfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
 }
 
+// ScrubPool verifies the checksums of all data stored in the pool.
+// Volumes don't need to be checked individually as their data is covered by the scrub.
+func (d *btrfs) ScrubPool(progressReporter ioprogress.ProgressReporter) error {
+	_, err := shared.RunCommand(context.TODO(), "btrfs", "scrub", "start", "-B", GetPoolMountPath(d.name))
+	if err != nil {
+		return fmt.Errorf("Failed scrubbing BTRFS filesystem: %w", err)
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
 	var rsyncFeatures []string
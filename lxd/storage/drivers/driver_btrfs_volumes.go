@@ -1821,6 +1821,29 @@ func (d *btrfs) VolumeSnapshots(vol Volume) ([]string, error) {
 	return genericVFSVolumeSnapshots(d, vol)
 }
 
+// DiffVolumeSnapshots reports the changes between two snapshots of the same volume.
+// This relies on a metadata-only incremental send stream between the two snapshot subvolumes.
+func (d *btrfs) DiffVolumeSnapshots(fromSnapVol Volume, toSnapVol Volume, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error {
+	stream := bytes.Buffer{}
+	err := shared.RunCommandWithFds(d.state.ShutdownCtx, nil, &stream, "btrfs", "send", "--no-data", "-q", "-p", fromSnapVol.MountPath(), toSnapVol.MountPath())
+	if err != nil {
+		return fmt.Errorf("Failed generating send stream: %w", err)
+	}
+
+	dump := bytes.Buffer{}
+	err = shared.RunCommandWithFds(d.state.ShutdownCtx, &stream, &dump, "btrfs", "receive", "--dump")
+	if err != nil {
+		return fmt.Errorf("Failed parsing send stream: %w", err)
+	}
+
+	blockFile := ""
+	if IsContentBlock(toSnapVol.contentType) {
+		blockFile = genericVolumeDiskFile
+	}
+
+	return parseBtrfsReceiveDump(&dump, blockFile, changeFunc)
+}
+
 // volumeSnapshotsSorted returns a list of snapshots for the volume (ordered by subvolume ID).
 // Since the subvolume ID is incremental, this also represents the order of creation.
 func (d *btrfs) volumeSnapshotsSorted(vol Volume, progressReporter ioprogress.ProgressReporter) ([]string, error) {
@@ -397,6 +397,7 @@ func (d *ceph) Validate(config map[string]string) error {
 		//  shortdesc: Whether the pool was empty on creation time
 		//  scope: global
 		"volatile.pool.pristine": validate.IsAny,
+		"usage.reserved":         validate.Optional(validate.IsSize),
 	}
 
 	for configOption, configOptionValue := range config {
@@ -531,6 +532,36 @@ func (d *ceph) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetProvisionedSpace returns the total provisioned size of the RBD images in the pool, including snapshots and
+// cached images. This can exceed the size of the pool, as RBD images only allocate space when it is written to.
+func (d *ceph) GetProvisionedSpace() (uint64, error) {
+	var stdout bytes.Buffer
+
+	err := shared.RunCommandWithFds(d.state.ShutdownCtx, nil, &stdout,
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"du",
+		"--format", "json")
+	if err != nil {
+		return 0, err
+	}
+
+	// Temporary struct for parsing.
+	type rbdDu struct {
+		TotalProvisionedSize uint64 `json:"total_provisioned_size"`
+	}
+
+	du := rbdDu{}
+	err = json.NewDecoder(&stdout).Decode(&du)
+	if err != nil {
+		return 0, err
+	}
+
+	return du.TotalProvisionedSize, nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *ceph) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
 	var rsyncFeatures []string
@@ -2253,6 +2253,98 @@ func (d *ceph) restoreVolume(vol Volume, snapVol Volume, progressReporter ioprog
 	return nil
 }
 
+// DiffVolumeSnapshots reports the changes between two snapshots of the same volume.
+// For block volumes the changed extents are obtained from RBD directly.
+func (d *ceph) DiffVolumeSnapshots(fromSnapVol Volume, toSnapVol Volume, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error {
+	if !IsContentBlock(toSnapVol.contentType) {
+		return genericVFSDiffVolumeSnapshots(d, fromSnapVol, toSnapVol, changeFunc, progressReporter)
+	}
+
+	_, fromSnapName := CephGetRBDImageName(fromSnapVol, false)
+
+	output, err := shared.RunCommand(
+		context.TODO(),
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"diff",
+		"--from-snap", fromSnapName,
+		"--format", "json",
+		d.getRBDVolumeName(toSnapVol, "", false, true))
+	if err != nil {
+		return err
+	}
+
+	return parseRBDDiff([]byte(output), changeFunc)
+}
+
+// CheckVolume verifies the integrity of an unmounted volume.
+// The RBD object map is checked when the image has one, as well as the filesystem of filesystem volumes.
+func (d *ceph) CheckVolume(vol Volume, progressReporter ioprogress.ProgressReporter) error {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	if vol.contentType == ContentTypeFS && filesystem.IsMountPoint(vol.MountPath()) {
+		return ErrInUse
+	}
+
+	rbdVolumeName := d.getRBDVolumeName(vol, "", false, true)
+
+	output, err := shared.RunCommand(
+		context.TODO(),
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"info",
+		"--format", "json",
+		rbdVolumeName)
+	if err != nil {
+		return err
+	}
+
+	info := struct {
+		Features []string `json:"features"`
+	}{}
+
+	err = json.Unmarshal([]byte(output), &info)
+	if err != nil {
+		return fmt.Errorf("Failed parsing RBD image info: %w", err)
+	}
+
+	if slices.Contains(info.Features, "object-map") {
+		_, err = shared.RunCommand(
+			context.TODO(),
+			"rbd",
+			"--id", d.config["ceph.user.name"],
+			"--cluster", d.config["ceph.cluster_name"],
+			"object-map",
+			"check",
+			rbdVolumeName)
+		if err != nil {
+			return fmt.Errorf("RBD object map check of %q failed: %w", rbdVolumeName, err)
+		}
+	}
+
+	if vol.contentType != ContentTypeFS {
+		return nil
+	}
+
+	activated, volDevPath, err := d.getRBDMappedDevPath(vol, true)
+	if err != nil {
+		return err
+	}
+
+	if activated {
+		defer func() { _ = d.rbdUnmapVolume(vol, true) }()
+	}
+
+	return checkFileSystem(vol.ConfigBlockFilesystem(), volDevPath)
+}
+
 // RestoreVolume restores a volume from a snapshot.
 // Use restoreVolume if a VM's filesystem volume should not get restored.
 func (d *ceph) RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprogress.ProgressReporter) error {
@@ -3,19 +3,70 @@ package drivers
 import (
 	"context"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 
 	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/units"
+	"github.com/canonical/lxd/shared/validate"
 )
 
+// bucketRules returns the validation rules of the bucket policy config keys.
+func (d *cephobject) bucketRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-cephobject; group=bucket-conf; key=quota.objects)
+		// Once the bucket holds this number of objects, uploading new objects fails until some are deleted.
+		// The quota is enforced together with the `size` quota. Set it to `0` or leave it empty for no limit.
+		// ---
+		//  type: integer
+		//  defaultdesc: `0` (no limit)
+		//  shortdesc: Maximum number of objects in the storage bucket
+		//  scope: local
+		"quota.objects": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=storage-cephobject; group=bucket-conf; key=versioning)
+		// When enabled, overwritten and deleted objects are kept as noncurrent versions.
+		// Disabling versioning suspends it: existing versions are kept, but no new ones are created.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to keep previous versions of objects
+		//  scope: local
+		"versioning": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=storage-cephobject; group=bucket-conf; key=lifecycle.expiry)
+		// Specify a comma-separated list of `[<prefix>:]<days>` rules, for example `logs/:7,tmp/:1`.
+		// Objects whose name starts with the prefix are deleted the given number of days after their creation.
+		// A rule without prefix applies to all objects.
+		// Noncurrent object versions expire after the same number of days.
+		// ---
+		//  type: string
+		//  shortdesc: Expiration rules for objects
+		//  scope: local
+		"lifecycle.expiry": func(value string) error {
+			_, err := parseBucketExpiryRules(value)
+			return err
+		},
+		// lxdmeta:generate(entities=storage-cephobject; group=bucket-conf; key=policy.anonymous_read)
+		// When enabled, anyone can download the objects of the bucket through its S3 URL without credentials.
+		// Objects are not listed anonymously.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to allow anonymous read access to objects
+		//  scope: local
+		"policy.anonymous_read": validate.Optional(validate.IsBool),
+	}
+}
+
 // ValidateVolume validates the supplied volume config.
 func (d *cephobject) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
-	return d.validateVolume(vol, nil, removeUnknownKeys)
+	return d.validateVolume(vol, d.bucketRules(), removeUnknownKeys)
 }
 
 // CreateBucket creates a new bucket.
@@ -65,28 +116,54 @@ func (d *cephobject) CreateBucket(bucket Volume) error {
 	}
 
 	// Set initial quota if specified.
-	if bucket.config["size"] != "" && bucket.config["size"] != "0" {
-		err = d.setBucketQuota(bucket, bucket.config["size"])
+	if (bucket.config["size"] != "" && bucket.config["size"] != "0") || (bucket.config["quota.objects"] != "" && bucket.config["quota.objects"] != "0") {
+		err = d.setBucketQuota(bucket, bucket.config)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Apply initial bucket policies. Disabled policies don't need to be applied to a new bucket.
+	policies := map[string]string{}
+	for _, key := range []string{"versioning", "lifecycle.expiry", "policy.anonymous_read"} {
+		if bucket.config[key] != "" && bucket.config[key] != "false" {
+			policies[key] = bucket.config[key]
+		}
+	}
+
+	err = d.setBucketPolicies(bucket, policies)
+	if err != nil {
+		return err
+	}
+
 	revert.Success()
 	return nil
 }
 
-// setBucketQuota sets the bucket quota.
-func (d *cephobject) setBucketQuota(bucket Volume, quotaSize string) error {
+// setBucketQuota sets the bucket size and object count quotas from the bucket config.
+func (d *cephobject) setBucketQuota(bucket Volume, config map[string]string) error {
 	_, bucketName := project.StorageVolumeParts(bucket.name)
 	storageBucketName := d.radosgwBucketName(bucketName)
 
-	sizeBytes, err := units.ParseByteSizeString(quotaSize)
-	if err != nil {
-		return fmt.Errorf("Failed parsing bucket quota size: %w", err)
+	var sizeBytes int64
+	if config["size"] != "" {
+		var err error
+		sizeBytes, err = units.ParseByteSizeString(config["size"])
+		if err != nil {
+			return fmt.Errorf("Failed parsing bucket quota size: %w", err)
+		}
 	}
 
-	err = d.radosgwadminBucketSetQuota(context.TODO(), storageBucketName, sizeBytes)
+	var objects int64
+	if config["quota.objects"] != "" {
+		var err error
+		objects, err = strconv.ParseInt(config["quota.objects"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("Failed parsing bucket object quota: %w", err)
+		}
+	}
+
+	err := d.radosgwadminBucketSetQuota(context.TODO(), storageBucketName, sizeBytes, objects)
 	if err != nil {
 		return fmt.Errorf("Failed setting bucket quota: %w", err)
 	}
@@ -94,6 +171,71 @@ func (d *cephobject) setBucketQuota(bucket Volume, quotaSize string) error {
 	return nil
 }
 
+// setBucketPolicies applies the given versioning, lifecycle and access policy config keys to the bucket.
+// Keys that are not present in policies are left unchanged.
+func (d *cephobject) setBucketPolicies(bucket Volume, policies map[string]string) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	storageBucketName, creds, err := d.bucketUserCredentials(bucket)
+	if err != nil {
+		return err
+	}
+
+	value, ok := policies["versioning"]
+	if ok {
+		doc, err := s3VersioningDocument(shared.IsTrue(value))
+		if err != nil {
+			return err
+		}
+
+		err = d.s3SetBucketSubresource(context.TODO(), *creds, storageBucketName, "versioning", doc)
+		if err != nil {
+			return fmt.Errorf("Failed setting bucket versioning: %w", err)
+		}
+	}
+
+	value, ok = policies["lifecycle.expiry"]
+	if ok {
+		rules, err := parseBucketExpiryRules(value)
+		if err != nil {
+			return err
+		}
+
+		var doc []byte
+		if len(rules) > 0 {
+			doc, err = s3LifecycleDocument(rules)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = d.s3SetBucketSubresource(context.TODO(), *creds, storageBucketName, "lifecycle", doc)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("Failed setting bucket lifecycle expiration: %w", err)
+		}
+	}
+
+	value, ok = policies["policy.anonymous_read"]
+	if ok {
+		var doc []byte
+		if shared.IsTrue(value) {
+			doc, err = s3AnonymousReadPolicyDocument(storageBucketName)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = d.s3SetBucketSubresource(context.TODO(), *creds, storageBucketName, "policy", doc)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("Failed setting bucket access policy: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // DeleteBucket deletes an existing bucket.
 func (d *cephobject) DeleteBucket(bucket Volume) error {
 	_, bucketName := project.StorageVolumeParts(bucket.name)
@@ -114,15 +256,27 @@ func (d *cephobject) DeleteBucket(bucket Volume) error {
 
 // UpdateBucket updates an existing bucket.
 func (d *cephobject) UpdateBucket(bucket Volume, changedConfig map[string]string) error {
-	newSize, sizeChanged := changedConfig["size"]
-	if sizeChanged {
-		err := d.setBucketQuota(bucket, newSize)
+	newConfig := maps.Clone(bucket.config)
+	maps.Copy(newConfig, changedConfig)
+
+	_, sizeChanged := changedConfig["size"]
+	_, objectsChanged := changedConfig["quota.objects"]
+	if sizeChanged || objectsChanged {
+		err := d.setBucketQuota(bucket, newConfig)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	policies := map[string]string{}
+	for _, key := range []string{"versioning", "lifecycle.expiry", "policy.anonymous_read"} {
+		value, changed := changedConfig[key]
+		if changed {
+			policies[key] = value
+		}
+	}
+
+	return d.setBucketPolicies(bucket, policies)
 }
 
 // bucketKeyRadosgwAccessRole returns the radosgw access setting for the specified role name.
@@ -226,3 +380,112 @@ func (d *cephobject) GetBucketURL(bucketName string) *url.URL {
 
 	return u
 }
+
+// bucketUserCredentials returns the credentials of the radosgw user owning the bucket.
+func (d *cephobject) bucketUserCredentials(bucket Volume) (string, *S3Credentials, error) {
+	_, bucketName := project.StorageVolumeParts(bucket.name)
+	storageBucketName := d.radosgwBucketName(bucketName)
+
+	creds, _, err := d.radosgwadminGetUser(context.TODO(), storageBucketName)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed getting bucket user: %w", err)
+	}
+
+	return storageBucketName, creds, nil
+}
+
+// ListBucketObjects returns the objects of the bucket whose name starts with prefix.
+func (d *cephobject) ListBucketObjects(bucket Volume, prefix string) ([]api.StorageBucketObject, error) {
+	storageBucketName, creds, err := d.bucketUserCredentials(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := d.s3ListObjects(context.TODO(), *creds, storageBucketName, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing bucket objects: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetBucketObject returns the content and size of a bucket object.
+func (d *cephobject) GetBucketObject(bucket Volume, objectName string) (io.ReadCloser, int64, error) {
+	storageBucketName, creds, err := d.bucketUserCredentials(bucket)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	resp, err := d.s3Do(context.TODO(), *creds, http.MethodGet, storageBucketName, objectName, nil, nil, 0)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, -1, api.StatusErrorf(http.StatusNotFound, "Bucket object not found")
+		}
+
+		return nil, -1, fmt.Errorf("Failed getting bucket object: %w", s3ResponseError(resp))
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// PutBucketObject stores an object in the bucket, replacing any existing object with the same name.
+func (d *cephobject) PutBucketObject(bucket Volume, objectName string, data io.Reader, size int64) error {
+	storageBucketName, creds, err := d.bucketUserCredentials(bucket)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.s3Do(context.TODO(), *creds, http.MethodPut, storageBucketName, objectName, nil, data, size)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Failed storing bucket object: %w", s3ResponseError(resp))
+	}
+
+	return nil
+}
+
+// DeleteBucketObject deletes an object from the bucket.
+func (d *cephobject) DeleteBucketObject(bucket Volume, objectName string) error {
+	storageBucketName, creds, err := d.bucketUserCredentials(bucket)
+	if err != nil {
+		return err
+	}
+
+	// S3 doesn't report missing objects on deletion, so check that the object exists first.
+	resp, err := d.s3Do(context.TODO(), *creds, http.MethodHead, storageBucketName, objectName, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return api.StatusErrorf(http.StatusNotFound, "Bucket object not found")
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Failed getting bucket object: %w", s3ResponseError(resp))
+	}
+
+	resp, err = d.s3Do(context.TODO(), *creds, http.MethodDelete, storageBucketName, objectName, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Failed deleting bucket object: %w", s3ResponseError(resp))
+	}
+
+	return nil
+}
@@ -1,15 +1,20 @@
 package drivers
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
+	"crypto/md5"
 	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -287,12 +292,12 @@ func s3SignRequest(req *http.Request, creds S3Credentials, payloadHash string) {
 	req.Header.Set("Host", req.URL.Host)
 
 	// Build canonical request.
-	canonicalURI := req.URL.Path
+	canonicalURI := req.URL.EscapedPath()
 	if canonicalURI == "" {
 		canonicalURI = "/"
 	}
 
-	canonicalQueryString := req.URL.Query().Encode()
+	canonicalQueryString := s3EncodeQuery(req.URL.Query())
 	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
 	canonicalHeaders := "host:" + req.URL.Host + "\n" + "x-amz-content-sha256:" + payloadHash + "\n" + "x-amz-date:" + amzDate + "\n"
 
@@ -310,6 +315,326 @@ func s3SignRequest(req *http.Request, creds S3Credentials, payloadHash string) {
 	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.AccessKey+"/"+credentialScope+", SignedHeaders="+signedHeaders+", Signature="+signature)
 }
 
+// s3EncodePath escapes a path as expected by AWS Signature V4, leaving only unreserved characters and
+// the path separators unescaped.
+func s3EncodePath(p string) string {
+	var b strings.Builder
+	for i := range len(p) {
+		c := p[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+// s3EncodeQuery encodes query parameters as expected by AWS Signature V4, sorted by key with spaces
+// escaped as "%20".
+func s3EncodeQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// s3Error represents an error returned by the S3 API.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// s3ResponseError returns an error describing a failed S3 response.
+// Missing buckets and objects are reported with the http.StatusNotFound status code.
+func s3ResponseError(resp *http.Response) error {
+	s3Err := s3Error{}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&s3Err)
+
+	if s3Err.Code == "" {
+		s3Err.Code = http.StatusText(resp.StatusCode)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return api.StatusErrorf(http.StatusNotFound, "S3 request failed: %s", s3Err.Code)
+	}
+
+	if s3Err.Message != "" {
+		return fmt.Errorf("S3 request failed (HTTP %d): %s: %s", resp.StatusCode, s3Err.Code, s3Err.Message)
+	}
+
+	return fmt.Errorf("S3 request failed (HTTP %d): %s", resp.StatusCode, s3Err.Code)
+}
+
+// s3Do sends a request to the radosgw endpoint for the given bucket and object, signed with the given credentials.
+// The object name can be empty to send a request to the bucket itself. The payload isn't signed so that it can be
+// streamed. The caller is responsible for closing the response body.
+func (d *cephobject) s3Do(ctx context.Context, creds S3Credentials, method string, bucket string, objectName string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	req, err := d.s3NewRequest(ctx, method, bucket, objectName, query, body, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.s3Send(req, creds)
+}
+
+// s3NewRequest returns an unsigned request to the radosgw endpoint for the given bucket and object.
+func (d *cephobject) s3NewRequest(ctx context.Context, method string, bucket string, objectName string, query url.Values, body io.Reader, size int64) (*http.Request, error) {
+	u, err := url.ParseRequestURI(d.config["cephobject.radosgw.endpoint"])
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing cephobject.radosgw.endpoint: %w", err)
+	}
+
+	// Object names can contain any character, so the path is built without cleaning it.
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket
+	if objectName != "" {
+		u.Path += "/" + objectName
+	}
+
+	u.RawPath = s3EncodePath(u.Path)
+	u.RawQuery = s3EncodeQuery(query)
+
+	if body == nil || size == 0 {
+		body = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > 0 {
+		req.ContentLength = size
+	}
+
+	return req, nil
+}
+
+// s3Send signs the request with the given credentials and sends it to the radosgw endpoint.
+func (d *cephobject) s3Send(req *http.Request, creds S3Credentials) (*http.Response, error) {
+	s3SignRequest(req, creds, "UNSIGNED-PAYLOAD")
+
+	transport, err := d.s3Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Failed sending S3 request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// s3SetBucketSubresource replaces a bucket subresource (such as "versioning", "lifecycle" or "policy") with the
+// given document. An empty document deletes the subresource instead.
+func (d *cephobject) s3SetBucketSubresource(ctx context.Context, creds S3Credentials, bucket string, subresource string, document []byte) error {
+	query := url.Values{}
+	query.Set(subresource, "")
+
+	method := http.MethodPut
+	if len(document) == 0 {
+		method = http.MethodDelete
+	}
+
+	req, err := d.s3NewRequest(ctx, method, bucket, "", query, bytes.NewReader(document), int64(len(document)))
+	if err != nil {
+		return err
+	}
+
+	if len(document) > 0 {
+		// Bucket configuration requests require the checksum of their payload.
+		md5sum := md5.Sum(document)
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5sum[:]))
+	}
+
+	resp, err := d.s3Send(req, creds)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return s3ResponseError(resp)
+	}
+
+	return nil
+}
+
+// s3ListObjects returns the objects of a bucket whose name starts with prefix.
+func (d *cephobject) s3ListObjects(ctx context.Context, creds S3Credentials, bucket string, prefix string) ([]api.StorageBucketObject, error) {
+	objects := []api.StorageBucketObject{}
+
+	continuationToken := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		resp, err := d.s3Do(ctx, creds, http.MethodGet, bucket, "", query, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err = s3ResponseError(resp)
+			_ = resp.Body.Close()
+			return nil, err
+		}
+
+		result := struct {
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+			Contents              []struct {
+				Key          string    `xml:"Key"`
+				LastModified time.Time `xml:"LastModified"`
+				ETag         string    `xml:"ETag"`
+				Size         int64     `xml:"Size"`
+			} `xml:"Contents"`
+		}{}
+
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing S3 object list: %w", err)
+		}
+
+		for _, content := range result.Contents {
+			objects = append(objects, api.StorageBucketObject{
+				Name:         content.Key,
+				Size:         content.Size,
+				ETag:         content.ETag,
+				LastModified: content.LastModified,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+
+		continuationToken = result.NextContinuationToken
+	}
+
+	return objects, nil
+}
+
+// bucketExpiryRule represents a lifecycle expiration rule of a bucket.
+type bucketExpiryRule struct {
+	prefix string
+	days   int
+}
+
+// parseBucketExpiryRules parses a comma-separated list of "[<prefix>:]<days>" expiration rules.
+func parseBucketExpiryRules(value string) ([]bucketExpiryRule, error) {
+	rules := []bucketExpiryRule{}
+	if value == "" {
+		return rules, nil
+	}
+
+	prefixes := map[string]bool{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+
+		// Object names can contain colons, so only the last one separates the prefix from the age.
+		prefix := ""
+		age := entry
+		idx := strings.LastIndex(entry, ":")
+		if idx >= 0 {
+			prefix = entry[:idx]
+			age = entry[idx+1:]
+		}
+
+		days, err := strconv.Atoi(age)
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("Invalid expiration age %q in rule %q, must be a positive number of days", age, entry)
+		}
+
+		if prefixes[prefix] {
+			return nil, fmt.Errorf("Duplicate expiration rule for prefix %q", prefix)
+		}
+
+		prefixes[prefix] = true
+		rules = append(rules, bucketExpiryRule{prefix: prefix, days: days})
+	}
+
+	return rules, nil
+}
+
+// s3LifecycleDocument returns the S3 lifecycle configuration enforcing the expiration rules.
+// Noncurrent object versions expire after the same age as current ones.
+func s3LifecycleDocument(rules []bucketExpiryRule) ([]byte, error) {
+	type lifecycleRule struct {
+		ID     string `xml:"ID"`
+		Filter struct {
+			Prefix string `xml:"Prefix"`
+		} `xml:"Filter"`
+		Status     string `xml:"Status"`
+		Expiration struct {
+			Days int `xml:"Days"`
+		} `xml:"Expiration"`
+		NoncurrentVersionExpiration struct {
+			NoncurrentDays int `xml:"NoncurrentDays"`
+		} `xml:"NoncurrentVersionExpiration"`
+	}
+
+	doc := struct {
+		XMLName xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LifecycleConfiguration"`
+		Rules   []lifecycleRule `xml:"Rule"`
+	}{}
+
+	for i, rule := range rules {
+		r := lifecycleRule{
+			ID:     "lxd-expiry-" + strconv.Itoa(i),
+			Status: "Enabled",
+		}
+
+		r.Filter.Prefix = rule.prefix
+		r.Expiration.Days = rule.days
+		r.NoncurrentVersionExpiration.NoncurrentDays = rule.days
+		doc.Rules = append(doc.Rules, r)
+	}
+
+	return xml.Marshal(doc)
+}
+
+// s3VersioningDocument returns the S3 versioning configuration enabling or suspending object versioning.
+func s3VersioningDocument(enabled bool) ([]byte, error) {
+	doc := struct {
+		XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration"`
+		Status  string   `xml:"Status"`
+	}{Status: "Suspended"}
+
+	if enabled {
+		doc.Status = "Enabled"
+	}
+
+	return xml.Marshal(doc)
+}
+
+// s3AnonymousReadPolicyDocument returns the S3 bucket policy allowing anyone to read the objects of the bucket.
+func s3AnonymousReadPolicyDocument(bucket string) ([]byte, error) {
+	policy := map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{
+			{
+				"Sid":       "lxd-anonymous-read",
+				"Effect":    "Allow",
+				"Principal": "*",
+				"Action":    []string{"s3:GetObject", "s3:GetObjectVersion"},
+				"Resource":  []string{"arn:aws:s3:::" + bucket + "/*"},
+			},
+		},
+	}
+
+	return json.Marshal(policy)
+}
+
 // hmacSHA256 returns the HMAC-SHA256 of the data using the given key.
 func hmacSHA256(key []byte, data string) []byte {
 	h := hmac.New(sha256.New, key)
@@ -342,14 +667,20 @@ func (d *cephobject) radosgwadminBucketLink(ctx context.Context, bucket string,
 }
 
 // radosgwadminBucketSetQuota sets bucket quota.
-func (d *cephobject) radosgwadminBucketSetQuota(ctx context.Context, user string, size int64) error {
+// A size or object count lower or equal to zero removes the corresponding limit.
+func (d *cephobject) radosgwadminBucketSetQuota(ctx context.Context, user string, size int64, objects int64) error {
+	maxSize := "-1"
 	if size > 0 {
-		_, err := d.radosgwadmin(ctx, "quota", "enable", "--quota-scope=bucket", "--uid", user)
-		if err != nil {
-			return err
-		}
+		maxSize = strconv.FormatInt(size, 10)
+	}
 
-		_, err = d.radosgwadmin(ctx, "quota", "set", "--quota-scope=bucket", "--uid", user, "--max-size", strconv.FormatInt(size, 10))
+	maxObjects := "-1"
+	if objects > 0 {
+		maxObjects = strconv.FormatInt(objects, 10)
+	}
+
+	if size > 0 || objects > 0 {
+		_, err := d.radosgwadmin(ctx, "quota", "enable", "--quota-scope=bucket", "--uid", user)
 		if err != nil {
 			return err
 		}
@@ -358,11 +689,11 @@ func (d *cephobject) radosgwadminBucketSetQuota(ctx context.Context, user string
 		if err != nil {
 			return err
 		}
+	}
 
-		_, err = d.radosgwadmin(ctx, "quota", "set", "--quota-scope=bucket", "--uid", user, "--max-size", "-1")
-		if err != nil {
-			return err
-		}
+	_, err := d.radosgwadmin(ctx, "quota", "set", "--quota-scope=bucket", "--uid", user, "--max-size", maxSize, "--max-objects", maxObjects)
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -0,0 +1,90 @@
+package drivers
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func Test_s3EncodePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/bucket", "/bucket"},
+		{"/bucket/dir/file.txt", "/bucket/dir/file.txt"},
+		{"/bucket/my file", "/bucket/my%20file"},
+		{"/bucket/a+b=c&d", "/bucket/a%2Bb%3Dc%26d"},
+		{"/bucket/~user/..", "/bucket/~user/.."},
+		{"/bucket/é", "/bucket/%C3%A9"},
+	}
+
+	for _, tt := range tests {
+		got := s3EncodePath(tt.path)
+		if got != tt.want {
+			t.Errorf("s3EncodePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func Test_s3EncodeQuery(t *testing.T) {
+	query := url.Values{}
+	query.Set("prefix", "my dir/a+b")
+	query.Set("list-type", "2")
+
+	want := "list-type=2&prefix=my%20dir%2Fa%2Bb"
+	got := s3EncodeQuery(query)
+	if got != want {
+		t.Errorf("s3EncodeQuery() = %q, want %q", got, want)
+	}
+}
+
+func Test_parseBucketExpiryRules(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []bucketExpiryRule
+		wantErr bool
+	}{
+		{value: "", want: []bucketExpiryRule{}},
+		{value: "30", want: []bucketExpiryRule{{prefix: "", days: 30}}},
+		{value: "logs/:7, tmp/:1", want: []bucketExpiryRule{{prefix: "logs/", days: 7}, {prefix: "tmp/", days: 1}}},
+		{value: "a:b:2", want: []bucketExpiryRule{{prefix: "a:b", days: 2}}},
+		{value: ":5", want: []bucketExpiryRule{{prefix: "", days: 5}}},
+		{value: "logs/:0", wantErr: true},
+		{value: "logs/:7d", wantErr: true},
+		{value: "logs/", wantErr: true},
+		{value: "logs/:1,logs/:2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseBucketExpiryRules(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseBucketExpiryRules(%q) expected an error", tt.value)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseBucketExpiryRules(%q) unexpected error: %v", tt.value, err)
+			continue
+		}
+
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parseBucketExpiryRules(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func Test_s3LifecycleDocument(t *testing.T) {
+	doc, err := s3LifecycleDocument([]bucketExpiryRule{{prefix: "logs/", days: 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Rule><ID>lxd-expiry-0</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>7</Days></Expiration><NoncurrentVersionExpiration><NoncurrentDays>7</NoncurrentDays></NoncurrentVersionExpiration></Rule></LifecycleConfiguration>`
+	if string(doc) != want {
+		t.Errorf("s3LifecycleDocument() = %s, want %s", doc, want)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/canonical/lxd/lxd/backup"
@@ -24,6 +25,7 @@ import (
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/units"
 )
 
 type common struct {
@@ -101,6 +103,18 @@ func (d *common) validatePool(config map[string]string, driverRules map[string]f
 		return fmt.Errorf("Invalid option %q", k)
 	}
 
+	if config["usage.warning"] != "" && config["usage.critical"] != "" {
+		warning, _ := strconv.Atoi(config["usage.warning"])
+		critical, _ := strconv.Atoi(config["usage.critical"])
+		if warning >= critical {
+			return errors.New(`"usage.warning" must be lower than "usage.critical"`)
+		}
+	}
+
+	if shared.IsTrue(config["usage.critical.enforce"]) && config["usage.critical"] == "" {
+		return errors.New(`"usage.critical.enforce" requires "usage.critical" to be set`)
+	}
+
 	return nil
 }
 
@@ -136,6 +150,11 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// I/O limits are only relevant for custom volumes.
+		if vol.Type() != VolumeTypeCustom && strings.HasPrefix(volKey, "limits.") {
+			continue
+		}
+
 		if vol.config[volKey] == "" {
 			vol.config[volKey] = d.config[k]
 		}
@@ -195,6 +214,32 @@ func (d *common) validateVolume(vol Volume, driverRules map[string]func(value st
 		return fmt.Errorf("Volume %q property is not valid for volume type", "size")
 	}
 
+	// Check that burst limits are only set together with a lower matching limit.
+	for _, key := range []string{"limits.iops", "limits.bandwidth"} {
+		burstKey := key + ".burst"
+		if vol.config[burstKey] == "" {
+			continue
+		}
+
+		if vol.config[key] == "" {
+			return fmt.Errorf("%s requires %s to be set", burstKey, key)
+		}
+
+		limit, err := units.ParseByteSizeString(vol.config[key])
+		if err != nil {
+			return fmt.Errorf("Invalid value for volume %q option %q: %w", vol.name, key, err)
+		}
+
+		burst, err := units.ParseByteSizeString(vol.config[burstKey])
+		if err != nil {
+			return fmt.Errorf("Invalid value for volume %q option %q: %w", vol.name, burstKey, err)
+		}
+
+		if burst < limit {
+			return fmt.Errorf("%s cannot be lower than %s", burstKey, key)
+		}
+	}
+
 	// Check that security.unmapped and security.shifted are not set together.
 	if shared.IsTrue(vol.config["security.unmapped"]) && shared.IsTrue(vol.config["security.shifted"]) {
 		return errors.New("security.unmapped and security.shifted are mutually exclusive")
@@ -539,6 +584,31 @@ func (d *common) RestoreVolume(vol Volume, snapVol Volume, progressReporter iopr
 	return ErrNotSupported
 }
 
+// DiffVolumeSnapshots reports the changes between two snapshots of the same volume.
+// By default both snapshots are mounted and their content compared. Drivers able to track changes natively
+// override this.
+func (d *common) DiffVolumeSnapshots(fromSnapVol Volume, toSnapVol Volume, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error {
+	// Use the volume's driver reference to pick the actual mount functions as implemented by the driver.
+	return genericVFSDiffVolumeSnapshots(toSnapVol.driver, fromSnapVol, toSnapVol, changeFunc, progressReporter)
+}
+
+// GetProvisionedSpace returns the total size of the volumes in the pool once they are fully allocated.
+// The default implementation is not supported, as volumes of drivers without thin provisioning cannot use more
+// space than what the pool reports as used.
+func (d *common) GetProvisionedSpace() (uint64, error) {
+	return 0, ErrNotSupported
+}
+
+// ScrubPool verifies the integrity of all data stored in the pool.
+func (d *common) ScrubPool(progressReporter ioprogress.ProgressReporter) error {
+	return ErrNotSupported
+}
+
+// CheckVolume verifies the integrity of an unmounted volume.
+func (d *common) CheckVolume(vol Volume, progressReporter ioprogress.ProgressReporter) error {
+	return ErrNotSupported
+}
+
 // RenameVolumeSnapshot renames a snapshot.
 func (d *common) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, progressReporter ioprogress.ProgressReporter) error {
 	return ErrNotSupported
@@ -612,6 +682,26 @@ func (d *common) DeleteBucketKey(bucket Volume, keyName string) error {
 	return nil
 }
 
+// ListBucketObjects returns the objects of the bucket whose name starts with prefix.
+func (d *common) ListBucketObjects(bucket Volume, prefix string) ([]api.StorageBucketObject, error) {
+	return nil, ErrNotSupported
+}
+
+// GetBucketObject returns the content and size of a bucket object.
+func (d *common) GetBucketObject(bucket Volume, objectName string) (io.ReadCloser, int64, error) {
+	return nil, -1, ErrNotSupported
+}
+
+// PutBucketObject stores an object in the bucket, replacing any existing object with the same name.
+func (d *common) PutBucketObject(bucket Volume, objectName string, data io.Reader, size int64) error {
+	return ErrNotSupported
+}
+
+// DeleteBucketObject deletes an object from the bucket.
+func (d *common) DeleteBucketObject(bucket Volume, objectName string) error {
+	return ErrNotSupported
+}
+
 // roundVolumeBlockSizeBytes returns sizeBytes rounded up to the next multiple
 // of MinBlockBoundary.
 func (d *common) roundVolumeBlockSizeBytes(_ Volume, sizeBytes int64) int64 {
@@ -588,6 +588,17 @@ func (d *lvm) Validate(config map[string]string) error {
 		//  shortdesc: Force using an existing non-empty volume group
 		//  scope: global
 		"lvm.vg.force_reuse": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=storage-lvm,storage-ceph; group=pool-conf; key=usage.reserved)
+		// New volumes cannot be created if, once all volumes are fully allocated, they would leave less than
+		// this amount of free space in the storage pool. On thin-provisioned pools, the provisioned size of
+		// all volumes (including snapshots and cached images) is taken into account, which protects the pool
+		// against overcommit.
+		// ---
+		//  type: string
+		//  defaultdesc: `0` (no reservation)
+		//  shortdesc: Space kept free in the storage pool
+		//  scope: global
+		"usage.reserved": validate.Optional(validate.IsSize),
 	}
 
 	// Append common local pool rules.
@@ -832,6 +843,30 @@ func (d *lvm) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetProvisionedSpace returns the total size of the thin volumes in the thin pool, including snapshots and cached
+// images. This can exceed the size of the thin pool, as thin volumes only allocate space when it is written to.
+func (d *lvm) GetProvisionedSpace() (uint64, error) {
+	if !d.usesThinpool() {
+		return 0, ErrNotSupported
+	}
+
+	args := []string{
+		d.config["lvm.vg_name"],
+		"--noheadings",
+		"--units", "b",
+		"--nosuffix",
+		"--separator", ",",
+		"-o", "lv_size,pool_lv",
+	}
+
+	out, err := shared.RunCommand(d.state.ShutdownCtx, "lvs", args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseThinPoolProvisionedSpace(out, d.thinpoolName())
+}
+
 // roundVolumeBlockSizeBytes returns sizeBytes rounded up to the next multiple
 // of the volume group extent size.
 func (d *lvm) roundVolumeBlockSizeBytes(vol Volume, sizeBytes int64) int64 {
@@ -717,6 +717,27 @@ func (d *lvm) thinPoolVolumeUsage(volDevPath string) (totalSize uint64, usedSize
 	return totalSize, usedSize, nil
 }
 
+// parseThinPoolProvisionedSpace sums the sizes of the logical volumes in the given thin pool from the output of the
+// lvs command with the "lv_size,pool_lv" fields.
+func parseThinPoolProvisionedSpace(out string, thinPoolName string) (uint64, error) {
+	var total uint64
+	for line := range strings.SplitSeq(out, "\n") {
+		parts := shared.SplitNTrimSpace(line, ",", -1, true)
+		if len(parts) < 2 || parts[1] != thinPoolName {
+			continue
+		}
+
+		size, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("Failed parsing logical volume size (%q): %w", parts[0], err)
+		}
+
+		total += size
+	}
+
+	return total, nil
+}
+
 // parseLogicalVolumeSnapshot parses a raw logical volume name (from lvs command) and checks whether it is a
 // snapshot of the supplied parent volume. Returns unescaped parsed snapshot name if snapshot volume recognised,
 // empty string if not. The parent is required due to limitations in the naming scheme that LXD has historically
@@ -2,6 +2,10 @@ package drivers
 
 import (
 	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Example_lvm_parseLogicalVolumeName() {
@@ -66,3 +70,20 @@ func Example_lvm_parseLogicalVolumeName() {
 	// custom_proj_testvol--with--hyphens.block: Unrecognised
 	// custom_proj_testvol--with--hyphens.block-snap1--with--hyphens.block: snap1-with-hyphens.block
 }
+
+func Test_parseThinPoolProvisionedSpace(t *testing.T) {
+	// The first line is the 10GiB thin pool itself, whose thin volumes are overcommitted to 18GiB.
+	out := `  10737418240,
+  10737418240,LXDThinPool
+  8589934592,LXDThinPool
+  4294967296,OtherThinPool
+  1073741824,
+`
+
+	total, err := parseThinPoolProvisionedSpace(out, "LXDThinPool")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(19327352832), total)
+
+	_, err = parseThinPoolProvisionedSpace("  invalid,LXDThinPool\n", "LXDThinPool")
+	assert.Error(t, err)
+}
@@ -1189,6 +1189,35 @@ func (d *lvm) VolumeSnapshots(vol Volume) ([]string, error) {
 	return snapshots, nil
 }
 
+// CheckVolume verifies the integrity of an unmounted volume by checking its filesystem.
+func (d *lvm) CheckVolume(vol Volume, progressReporter ioprogress.ProgressReporter) error {
+	if vol.contentType != ContentTypeFS {
+		return ErrNotSupported
+	}
+
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	if filesystem.IsMountPoint(vol.MountPath()) {
+		return ErrInUse
+	}
+
+	_, err = d.activateVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = d.deactivateVolume(vol) }()
+
+	volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
+
+	return checkFileSystem(vol.ConfigBlockFilesystem(), volDevPath)
+}
+
 // RestoreVolume restores a volume from a snapshot.
 func (d *lvm) RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprogress.ProgressReporter) error {
 	_, snapshotName, _ := api.GetParentAndSnapshotName(snapVol.name)
@@ -721,6 +721,28 @@ func (d *zfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// ScrubPool verifies the checksums of all data stored in the zpool backing the storage pool.
+func (d *zfs) ScrubPool(progressReporter ioprogress.ProgressReporter) error {
+	zpoolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+
+	// Wait for the scrub to complete.
+	_, err := shared.RunCommand(context.TODO(), "zpool", "scrub", "-w", zpoolName)
+	if err != nil {
+		return fmt.Errorf("Failed scrubbing zpool %q: %w", zpoolName, err)
+	}
+
+	status, err := shared.RunCommand(context.TODO(), "zpool", "status", "-x", zpoolName)
+	if err != nil {
+		return fmt.Errorf("Failed getting status of zpool %q: %w", zpoolName, err)
+	}
+
+	if !strings.Contains(status, "is healthy") {
+		return fmt.Errorf("Zpool %q reported errors: %s", zpoolName, strings.TrimSpace(status))
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing
 // migrations between pools in preference order.
 func (d *zfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
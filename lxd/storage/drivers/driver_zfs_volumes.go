@@ -3798,6 +3798,115 @@ func (d *zfs) VolumeSnapshots(vol Volume) ([]string, error) {
 	return snapshots, nil
 }
 
+// DiffVolumeSnapshots reports the changes between two snapshots of the same volume.
+// For filesystem datasets this uses `zfs diff`, which requires the parent dataset to be mounted. For block volumes
+// the changed blocks are taken from the records of an incremental `zfs send` stream.
+func (d *zfs) DiffVolumeSnapshots(fromSnapVol Volume, toSnapVol Volume, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error {
+	if IsContentBlock(toSnapVol.contentType) {
+		return d.diffBlockVolumeSnapshots(fromSnapVol, toSnapVol, changeFunc)
+	}
+
+	if d.isBlockBacked(toSnapVol) {
+		return genericVFSDiffVolumeSnapshots(d, fromSnapVol, toSnapVol, changeFunc, progressReporter)
+	}
+
+	parentName, _, _ := api.GetParentAndSnapshotName(toSnapVol.name)
+	parentVol := NewVolume(d, d.name, toSnapVol.volType, toSnapVol.contentType, parentName, toSnapVol.config, toSnapVol.poolConfig)
+
+	err := d.MountVolume(parentVol, progressReporter)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = d.UnmountVolume(parentVol, false, progressReporter) }()
+
+	output, err := shared.RunCommand(context.TODO(), "zfs", "diff", "-H", "-F", d.dataset(fromSnapVol, false), d.dataset(toSnapVol, false))
+	if err != nil {
+		return err
+	}
+
+	return parseZFSDiff(strings.NewReader(output), parentVol.MountPath(), changeFunc)
+}
+
+// diffBlockVolumeSnapshots reports the changed byte ranges between two snapshots of a ZFS volume.
+func (d *zfs) diffBlockVolumeSnapshots(fromSnapVol Volume, toSnapVol Volume, changeFunc func(change api.StorageVolumeSnapshotChange) error) error {
+	dataset := d.dataset(toSnapVol, false)
+
+	volSizeStr, err := d.getDatasetProperty(dataset, "volsize")
+	if err != nil {
+		return err
+	}
+
+	volSize, err := strconv.ParseInt(volSizeStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Failed parsing volume size %q: %w", volSizeStr, err)
+	}
+
+	// Stop sending the stream if parsing fails part way through.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "zfs", "send", "-i", d.dataset(fromSnapVol, false), dataset)
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	err = parseZFSSendStream(stdout, volSize, changeFunc)
+	if err != nil {
+		cancel()
+		_ = cmd.Wait()
+		return err
+	}
+
+	_, _ = io.Copy(io.Discard, stdout)
+
+	err = cmd.Wait()
+	if err != nil {
+		return fmt.Errorf("zfs send failed: %w (%s)", err, stderr.String())
+	}
+
+	return nil
+}
+
+// CheckVolume verifies the integrity of an unmounted volume.
+// Datasets are covered by ScrubPool, so only the filesystem of block backed volumes is checked.
+func (d *zfs) CheckVolume(vol Volume, progressReporter ioprogress.ProgressReporter) error {
+	if vol.contentType != ContentTypeFS || !d.isBlockBacked(vol) {
+		return ErrNotSupported
+	}
+
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	if filesystem.IsMountPoint(vol.MountPath()) {
+		return ErrInUse
+	}
+
+	activated, volPath, err := d.activateVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if activated {
+		defer func() { _, _ = d.deactivateVolume(vol) }()
+	}
+
+	return checkFileSystem(vol.ConfigBlockFilesystem(), volPath)
+}
+
 // RestoreVolume restores a volume from a snapshot.
 func (d *zfs) RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprogress.ProgressReporter) error {
 	return d.restoreVolume(vol, snapVol, false, progressReporter)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"slices"
@@ -486,6 +487,26 @@ func genericVFSGetVolumeDiskPath(vol Volume) (string, error) {
 	return filepath.Join(vol.MountPath(), genericVolumeDiskFile), nil
 }
 
+// genericVFSDiffVolumeSnapshots is a generic DiffVolumeSnapshots implementation for drivers without native
+// change tracking. Both snapshots are mounted and their files compared. Block volumes are rejected, as comparing
+// them would require reading both snapshots in full.
+func genericVFSDiffVolumeSnapshots(d Driver, fromSnapVol Volume, toSnapVol Volume, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error {
+	if IsContentBlock(toSnapVol.contentType) {
+		return api.StatusErrorf(http.StatusNotImplemented, "Storage driver %q can't track the changed blocks of block volumes", d.Info().Name)
+	}
+
+	for _, snapVol := range []Volume{fromSnapVol, toSnapVol} {
+		err := d.MountVolumeSnapshot(snapVol, progressReporter)
+		if err != nil {
+			return err
+		}
+
+		defer func(snapVol Volume) { _, _ = d.UnmountVolumeSnapshot(snapVol, progressReporter) }(snapVol)
+	}
+
+	return diffFilesystems(fromSnapVol.MountPath(), toSnapVol.MountPath(), changeFunc)
+}
+
 // genericVFSBackupVolume is a generic BackupVolume implementation for VFS-only drivers.
 func genericVFSBackupVolume(d Driver, vol VolumeCopy, tarWriter *instancewriter.InstanceTarWriter, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
 	if len(snapshots) > 0 {
@@ -49,6 +49,12 @@ type Driver interface {
 	// Unmount unmounts a storage pool if needed, returns true if unmounted, false if was not mounted.
 	Unmount() (bool, error)
 	GetResources() (*api.ResourcesStoragePool, error)
+
+	// GetProvisionedSpace returns the total size of the volumes in the pool once they are fully allocated.
+	GetProvisionedSpace() (uint64, error)
+
+	// ScrubPool verifies the integrity of all data stored in the pool.
+	ScrubPool(progressReporter ioprogress.ProgressReporter) error
 	Validate(config map[string]string) error
 	ValidateSource() error
 	Update(changedConfig map[string]string) error
@@ -65,6 +71,10 @@ type Driver interface {
 	CreateBucketKey(bucket Volume, keyName string, creds S3Credentials, roleName string) (*S3Credentials, error)
 	UpdateBucketKey(bucket Volume, keyName string, creds S3Credentials, roleName string) (*S3Credentials, error)
 	DeleteBucketKey(bucket Volume, keyName string) error
+	ListBucketObjects(bucket Volume, prefix string) ([]api.StorageBucketObject, error)
+	GetBucketObject(bucket Volume, objectName string) (io.ReadCloser, int64, error)
+	PutBucketObject(bucket Volume, objectName string, data io.Reader, size int64) error
+	DeleteBucketObject(bucket Volume, objectName string) error
 
 	// Volumes.
 	FillVolumeConfig(vol Volume) error
@@ -106,6 +116,12 @@ type Driver interface {
 	RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, progressReporter ioprogress.ProgressReporter) error
 	VolumeSnapshots(vol Volume) ([]string, error)
 	CheckVolumeSnapshots(vol Volume, snapVols []Volume) error
+
+	// DiffVolumeSnapshots reports the changes between two snapshots of the same volume.
+	DiffVolumeSnapshots(fromSnapVol Volume, toSnapVol Volume, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error
+
+	// CheckVolume verifies the integrity of an unmounted volume.
+	CheckVolume(vol Volume, progressReporter ioprogress.ProgressReporter) error
 	RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprogress.ProgressReporter) error
 
 	// Migration.
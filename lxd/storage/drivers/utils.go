@@ -532,6 +532,31 @@ func growFileSystem(fsType string, devPath string, vol Volume) error {
 	}, nil)
 }
 
+// checkFileSystem runs a read-only consistency check of an unmounted filesystem.
+func checkFileSystem(fsType string, devPath string) error {
+	if fsType == "" {
+		fsType = DefaultFilesystem
+	}
+
+	var err error
+	switch fsType {
+	case "ext4":
+		_, err = shared.RunCommand(context.TODO(), "e2fsck", "-f", "-n", devPath)
+	case "xfs":
+		_, err = shared.RunCommand(context.TODO(), "xfs_repair", "-n", devPath)
+	case "btrfs":
+		_, err = shared.RunCommand(context.TODO(), "btrfs", "check", "--readonly", devPath)
+	default:
+		return fmt.Errorf("Unrecognised filesystem type %q", fsType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Filesystem check of %q failed: %w", devPath, err)
+	}
+
+	return nil
+}
+
 // regenerateFilesystemUUIDNeeded returns true if fsType requires UUID regeneration, false if not.
 func regenerateFilesystemUUIDNeeded(fsType string) bool {
 	switch fsType {
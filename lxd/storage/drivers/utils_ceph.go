@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/canonical/lxd/shared"
@@ -83,6 +84,88 @@ func callCephJSON(ctx context.Context, out any, args ...string) error {
 	return json.Unmarshal([]byte(jsonOut), out)
 }
 
+// cephRBDLock represents a lock held on an RBD image.
+type cephRBDLock struct {
+	ID      string `json:"id"`
+	Locker  string `json:"locker"`
+	Address string `json:"address"`
+}
+
+// cephRBDFenceTargets returns the addresses of the Ceph clients using an RBD image and the locks they hold,
+// based on the JSON output of "rbd status" and "rbd lock ls".
+func cephRBDFenceTargets(statusJSON []byte, locksJSON []byte) ([]string, []cephRBDLock, error) {
+	status := struct {
+		Watchers []struct {
+			Address string `json:"address"`
+		} `json:"watchers"`
+	}{}
+
+	err := json.Unmarshal(statusJSON, &status)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed parsing RBD image status: %w", err)
+	}
+
+	locks := []cephRBDLock{}
+	err = json.Unmarshal(locksJSON, &locks)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed parsing RBD image locks: %w", err)
+	}
+
+	addresses := []string{}
+	for _, watcher := range status.Watchers {
+		if !slices.Contains(addresses, watcher.Address) {
+			addresses = append(addresses, watcher.Address)
+		}
+	}
+
+	for _, lock := range locks {
+		if lock.Address != "" && !slices.Contains(addresses, lock.Address) {
+			addresses = append(addresses, lock.Address)
+		}
+	}
+
+	return addresses, locks, nil
+}
+
+// CephRBDFenceImage fences the given RBD image: the Ceph clients watching or locking the image are added to the
+// OSD blocklist so that they can no longer write to the cluster, then their locks on the image are released.
+// This must only be used once the cluster member that used the image is known to be offline.
+// Returns the addresses of the blocklisted clients.
+func CephRBDFenceImage(ctx context.Context, cluster string, user string, pool string, image string) ([]string, error) {
+	rbdArgs := []string{"--id", user, "--cluster", cluster, "--pool", pool}
+
+	statusJSON, err := shared.RunCommand(ctx, "rbd", append(rbdArgs, "status", "--format", "json", image)...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting status of RBD image %q: %w", image, err)
+	}
+
+	locksJSON, err := shared.RunCommand(ctx, "rbd", append(rbdArgs, "lock", "ls", "--format", "json", image)...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing locks of RBD image %q: %w", image, err)
+	}
+
+	addresses, locks, err := cephRBDFenceTargets([]byte(statusJSON), []byte(locksJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range addresses {
+		_, err := callCeph(ctx, "--name", "client."+user, "--cluster", cluster, "osd", "blocklist", "add", address)
+		if err != nil {
+			return nil, fmt.Errorf("Failed blocklisting Ceph client %q: %w", address, err)
+		}
+	}
+
+	for _, lock := range locks {
+		_, err := shared.RunCommand(ctx, "rbd", append(rbdArgs, "lock", "rm", image, lock.ID, lock.Locker)...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed removing lock %q of RBD image %q: %w", lock.ID, image, err)
+		}
+	}
+
+	return addresses, nil
+}
+
 // Monitors holds a list of Ceph monitor addresses based on which protocol they expect.
 type Monitors struct {
 	V1 []string
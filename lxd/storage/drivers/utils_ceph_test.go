@@ -401,3 +401,26 @@ func TestGetCephKeyFromFile_nonexistent(t *testing.T) {
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, os.ErrNotExist)
 }
+
+func TestCephRBDFenceTargets(t *testing.T) {
+	statusJSON := []byte(`{"watchers":[{"address":"10.0.0.1:0/1234","client":4123,"cookie":18446462598732840961},{"address":"10.0.0.1:0/1234","client":4123,"cookie":18446462598732840962}]}`)
+	locksJSON := []byte(`[{"id":"auto 18446462598732840961","locker":"client.4123","address":"10.0.0.1:0/1234"},{"id":"auto 1","locker":"client.4200","address":"10.0.0.2:0/5678"}]`)
+
+	addresses, locks, err := cephRBDFenceTargets(statusJSON, locksJSON)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:0/1234", "10.0.0.2:0/5678"}, addresses)
+	assert.Equal(t, []cephRBDLock{
+		{ID: "auto 18446462598732840961", Locker: "client.4123", Address: "10.0.0.1:0/1234"},
+		{ID: "auto 1", Locker: "client.4200", Address: "10.0.0.2:0/5678"},
+	}, locks)
+
+	// Unused image.
+	addresses, locks, err = cephRBDFenceTargets([]byte(`{"watchers":[]}`), []byte(`[]`))
+	require.NoError(t, err)
+	assert.Empty(t, addresses)
+	assert.Empty(t, locks)
+
+	// Invalid output.
+	_, _, err = cephRBDFenceTargets([]byte(`invalid`), []byte(`[]`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,564 @@
+package drivers
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// diffAppendExtent adds a changed byte range to a list of ranges sorted by offset, merging it with the last
+// range if they overlap or are adjacent.
+func diffAppendExtent(extents []api.StorageVolumeSnapshotChange, offset int64, length int64) []api.StorageVolumeSnapshotChange {
+	if len(extents) > 0 {
+		last := &extents[len(extents)-1]
+		if offset <= last.Offset+last.Length {
+			last.Length = max(last.Length, offset+length-last.Offset)
+			return extents
+		}
+	}
+
+	return append(extents, api.StorageVolumeSnapshotChange{
+		Type:   api.StorageVolumeSnapshotChangeModified,
+		Offset: offset,
+		Length: length,
+	})
+}
+
+// diffMergeExtents sorts and merges a list of changed byte ranges.
+func diffMergeExtents(extents []api.StorageVolumeSnapshotChange) []api.StorageVolumeSnapshotChange {
+	slices.SortFunc(extents, func(a api.StorageVolumeSnapshotChange, b api.StorageVolumeSnapshotChange) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+
+	merged := make([]api.StorageVolumeSnapshotChange, 0, len(extents))
+	for _, extent := range extents {
+		merged = diffAppendExtent(merged, extent.Offset, extent.Length)
+	}
+
+	return merged
+}
+
+// diffFileUnchanged returns whether a file has the same type, permissions, ownership, size, modification time
+// and (for symlinks) target in both snapshots. Directory modification times are ignored as they change whenever
+// their content does, which is already reported through the changed entries.
+func diffFileUnchanged(fromPath string, fromInfo fs.FileInfo, toPath string, toInfo fs.FileInfo) bool {
+	if fromInfo.Mode() != toInfo.Mode() {
+		return false
+	}
+
+	fromStat, fromOK := fromInfo.Sys().(*unix.Stat_t)
+	toStat, toOK := toInfo.Sys().(*unix.Stat_t)
+	if fromOK && toOK && (fromStat.Uid != toStat.Uid || fromStat.Gid != toStat.Gid) {
+		return false
+	}
+
+	if toInfo.IsDir() {
+		return true
+	}
+
+	if fromInfo.Size() != toInfo.Size() || !fromInfo.ModTime().Equal(toInfo.ModTime()) {
+		return false
+	}
+
+	if toInfo.Mode()&fs.ModeSymlink != 0 {
+		fromTarget, _ := os.Readlink(fromPath)
+		toTarget, _ := os.Readlink(toPath)
+		return fromTarget == toTarget
+	}
+
+	return true
+}
+
+// diffFilesystems compares two mounted filesystem snapshots and reports the added, modified and deleted files.
+// Paths are relative to the root of the volume. When a directory is deleted, its content isn't reported.
+func diffFilesystems(fromPath string, toPath string, changeFunc func(change api.StorageVolumeSnapshotChange) error) error {
+	// Report added and modified entries by walking the newer snapshot.
+	err := filepath.WalkDir(toPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(toPath, path)
+		if err != nil {
+			return err
+		}
+
+		if relPath == "." {
+			return nil
+		}
+
+		toInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		oldPath := filepath.Join(fromPath, relPath)
+		fromInfo, err := os.Lstat(oldPath)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+
+			return changeFunc(api.StorageVolumeSnapshotChange{Type: api.StorageVolumeSnapshotChangeAdded, Path: relPath})
+		}
+
+		if !diffFileUnchanged(oldPath, fromInfo, path, toInfo) {
+			return changeFunc(api.StorageVolumeSnapshotChange{Type: api.StorageVolumeSnapshotChangeModified, Path: relPath})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Report deleted entries by walking the older snapshot.
+	return filepath.WalkDir(fromPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(fromPath, path)
+		if err != nil {
+			return err
+		}
+
+		if relPath == "." {
+			return nil
+		}
+
+		_, err = os.Lstat(filepath.Join(toPath, relPath))
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		err = changeFunc(api.StorageVolumeSnapshotChange{Type: api.StorageVolumeSnapshotChangeDeleted, Path: relPath})
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+}
+
+// diffUnescapeOctal replaces the backslash escaped characters used by the ZFS and Btrfs tools in their output.
+// Both octal sequences (for example `\040`) and escaped single characters (for example `\ `) are supported.
+func diffUnescapeOctal(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 >= len(value) {
+			sb.WriteByte(value[i])
+			continue
+		}
+
+		// Count the octal digits following the backslash (ZFS uses 4 digits, Btrfs 3).
+		j := i + 1
+		for j < len(value) && j < i+5 && value[j] >= '0' && value[j] <= '7' {
+			j++
+		}
+
+		if j-i-1 >= 3 {
+			char, err := strconv.ParseUint(value[i+1:j], 8, 8)
+			if err == nil {
+				sb.WriteByte(byte(char))
+				i = j - 1
+				continue
+			}
+		}
+
+		switch value[i+1] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		default:
+			sb.WriteByte(value[i+1])
+		}
+
+		i++
+	}
+
+	return sb.String()
+}
+
+// parseZFSDiff parses the output of `zfs diff -H -F` and reports the changes relative to mountPath.
+// Renamed files are reported as the deletion of the old path and the addition of the new one.
+// Modifications of directories are ignored as they only reflect changes of their content.
+func parseZFSDiff(output io.Reader, mountPath string, changeFunc func(change api.StorageVolumeSnapshotChange) error) error {
+	relPath := func(path string) string {
+		path = strings.TrimPrefix(diffUnescapeOctal(path), mountPath)
+		return strings.TrimPrefix(path, "/")
+	}
+
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 3 {
+			continue
+		}
+
+		changeType, fileType, path := fields[0], fields[1], relPath(fields[2])
+		if path == "" {
+			continue
+		}
+
+		var changes []api.StorageVolumeSnapshotChange
+
+		switch changeType {
+		case "+":
+			changes = append(changes, api.StorageVolumeSnapshotChange{Type: api.StorageVolumeSnapshotChangeAdded, Path: path})
+		case "-":
+			changes = append(changes, api.StorageVolumeSnapshotChange{Type: api.StorageVolumeSnapshotChangeDeleted, Path: path})
+		case "M":
+			if fileType == "/" {
+				continue
+			}
+
+			changes = append(changes, api.StorageVolumeSnapshotChange{Type: api.StorageVolumeSnapshotChangeModified, Path: path})
+		case "R":
+			if len(fields) < 4 {
+				return fmt.Errorf("Invalid rename entry in ZFS diff output: %q", scanner.Text())
+			}
+
+			changes = append(changes,
+				api.StorageVolumeSnapshotChange{Type: api.StorageVolumeSnapshotChangeDeleted, Path: path},
+				api.StorageVolumeSnapshotChange{Type: api.StorageVolumeSnapshotChangeAdded, Path: relPath(fields[3])},
+			)
+
+		default:
+			return fmt.Errorf("Unknown change type %q in ZFS diff output", changeType)
+		}
+
+		for _, change := range changes {
+			err := changeFunc(change)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// btrfsDumpFields splits a `btrfs receive --dump` line on unescaped spaces.
+func btrfsDumpFields(line string) []string {
+	var fields []string
+	var field strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			field.WriteByte(line[i])
+			field.WriteByte(line[i+1])
+			i++
+		case line[i] == ' ':
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+
+		default:
+			field.WriteByte(line[i])
+		}
+	}
+
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+
+	return fields
+}
+
+// btrfsDumpSplit splits a `btrfs receive --dump` line into its command, path and key=value arguments.
+func btrfsDumpSplit(line string) (command string, path string, args map[string]string) {
+	args = map[string]string{}
+
+	fields := btrfsDumpFields(line)
+	if len(fields) < 2 {
+		return "", "", args
+	}
+
+	for _, field := range fields[2:] {
+		key, value, found := strings.Cut(field, "=")
+		if found {
+			args[key] = diffUnescapeOctal(value)
+		}
+	}
+
+	return fields[0], diffUnescapeOctal(fields[1]), args
+}
+
+// parseBtrfsReceiveDump parses the output of `btrfs send --no-data -p <from> <to> | btrfs receive --dump`.
+// If blockFile is empty, the changed files are reported. Otherwise, the changed byte ranges of blockFile are
+// reported instead, which is used for block volumes stored as disk image files inside the subvolume.
+func parseBtrfsReceiveDump(output io.Reader, blockFile string, changeFunc func(change api.StorageVolumeSnapshotChange) error) error {
+	// Paths are prefixed with the name of the snapshot being received.
+	relPath := func(path string) string {
+		path = strings.TrimPrefix(path, "./")
+		_, path, _ = strings.Cut(path, "/")
+		return path
+	}
+
+	var paths []string
+	var extents []api.StorageVolumeSnapshotChange
+	changes := map[string]string{}
+
+	setChange := func(path string, changeType string) {
+		_, found := changes[path]
+		if !found {
+			paths = append(paths, path)
+		}
+
+		changes[path] = changeType
+	}
+
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		command, path, args := btrfsDumpSplit(scanner.Text())
+		path = relPath(path)
+		if path == "" {
+			continue
+		}
+
+		switch command {
+		case "mkfile", "mkdir", "mknod", "mkfifo", "mksock", "symlink", "link":
+			setChange(path, api.StorageVolumeSnapshotChangeAdded)
+		case "rename":
+			dest := relPath(args["dest"])
+
+			// Move any entries created under the renamed path (orphan directories are renamed last).
+			for _, p := range slices.Clone(paths) {
+				subPath, found := strings.CutPrefix(p, path+"/")
+				if !found || changes[p] == "" {
+					continue
+				}
+
+				setChange(dest+"/"+subPath, changes[p])
+				changes[p] = ""
+			}
+
+			if changes[path] == api.StorageVolumeSnapshotChangeAdded {
+				changes[path] = ""
+			} else {
+				setChange(path, api.StorageVolumeSnapshotChangeDeleted)
+			}
+
+			setChange(dest, api.StorageVolumeSnapshotChangeAdded)
+		case "unlink", "rmdir":
+			if changes[path] == api.StorageVolumeSnapshotChangeAdded {
+				changes[path] = ""
+			} else {
+				setChange(path, api.StorageVolumeSnapshotChangeDeleted)
+			}
+
+		case "write", "update_extent", "clone", "encoded_write", "fallocate":
+			if blockFile != "" && path == blockFile {
+				offset, err := strconv.ParseInt(args["offset"], 10, 64)
+				if err != nil {
+					return fmt.Errorf("Invalid offset in btrfs dump output %q: %w", scanner.Text(), err)
+				}
+
+				length, err := strconv.ParseInt(args["len"], 10, 64)
+				if err != nil {
+					return fmt.Errorf("Invalid length in btrfs dump output %q: %w", scanner.Text(), err)
+				}
+
+				extents = append(extents, api.StorageVolumeSnapshotChange{Offset: offset, Length: length})
+			}
+
+			if changes[path] == "" {
+				setChange(path, api.StorageVolumeSnapshotChangeModified)
+			}
+
+		case "truncate", "chmod", "chown", "set_xattr", "remove_xattr", "fileattr", "enable_verity":
+			if changes[path] == "" {
+				setChange(path, api.StorageVolumeSnapshotChangeModified)
+			}
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return err
+	}
+
+	if blockFile != "" {
+		for _, extent := range diffMergeExtents(extents) {
+			err := changeFunc(extent)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, path := range paths {
+		if changes[path] == "" {
+			continue
+		}
+
+		err := changeFunc(api.StorageVolumeSnapshotChange{Type: changes[path], Path: path})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseRBDDiff parses the JSON output of `rbd diff` and reports the changed byte ranges.
+// Discarded ranges (no longer existing in the newer snapshot) are reported as changes too.
+func parseRBDDiff(output []byte, changeFunc func(change api.StorageVolumeSnapshotChange) error) error {
+	var entries []struct {
+		Offset int64 `json:"offset"`
+		Length int64 `json:"length"`
+	}
+
+	err := json.Unmarshal(output, &entries)
+	if err != nil {
+		return fmt.Errorf("Failed parsing RBD diff output: %w", err)
+	}
+
+	extents := make([]api.StorageVolumeSnapshotChange, 0, len(entries))
+	for _, entry := range entries {
+		extents = append(extents, api.StorageVolumeSnapshotChange{Offset: entry.Offset, Length: entry.Length})
+	}
+
+	for _, extent := range diffMergeExtents(extents) {
+		err := changeFunc(extent)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Record types and constants of the ZFS send stream format (see dmu_replay_record_t in OpenZFS).
+const (
+	zfsSendRecordSize     = 312
+	zfsSendMagic          = 0x2F5BACBAC
+	zfsSendZvolObject     = 1
+	zfsSendBegin          = 0
+	zfsSendObject         = 1
+	zfsSendWrite          = 3
+	zfsSendFree           = 4
+	zfsSendEnd            = 5
+	zfsSendSpill          = 7
+	zfsSendWriteEmbedded  = 8
+	zfsSendFreeToEndOfObj = ^uint64(0)
+)
+
+// parseZFSSendStream parses an incremental `zfs send` stream of a ZFS volume and reports the changed byte ranges.
+// Only the records describing the data object of the volume are used, the data of the writes is skipped.
+// Freed ranges are reported as changes too, ranges freed up to the end of the volume are capped at volSize.
+func parseZFSSendStream(stream io.Reader, volSize int64, changeFunc func(change api.StorageVolumeSnapshotChange) error) error {
+	var order binary.ByteOrder
+	var extents []api.StorageVolumeSnapshotChange
+	record := make([]byte, zfsSendRecordSize)
+
+	addExtent := func(object uint64, offset uint64, length uint64) {
+		if object != zfsSendZvolObject || offset >= uint64(volSize) {
+			return
+		}
+
+		if length == zfsSendFreeToEndOfObj || offset+length > uint64(volSize) {
+			length = uint64(volSize) - offset
+		}
+
+		extents = append(extents, api.StorageVolumeSnapshotChange{Offset: int64(offset), Length: int64(length)})
+	}
+
+	for {
+		_, err := io.ReadFull(stream, record)
+		if err != nil {
+			return fmt.Errorf("Failed reading ZFS send stream: %w", err)
+		}
+
+		// Detect the byte order of the stream from the magic of the begin record.
+		if order == nil {
+			switch {
+			case binary.LittleEndian.Uint64(record[8:16]) == zfsSendMagic:
+				order = binary.LittleEndian
+			case binary.BigEndian.Uint64(record[8:16]) == zfsSendMagic:
+				order = binary.BigEndian
+			default:
+				return errors.New("Invalid ZFS send stream")
+			}
+		}
+
+		u := record[8:]
+		var payload uint64
+
+		switch order.Uint32(record[0:4]) {
+		case zfsSendBegin:
+			payload = uint64(order.Uint32(record[4:8]))
+		case zfsSendObject:
+			payload = uint64(order.Uint32(u[28:32]))
+			if payload == 0 {
+				payload = (uint64(order.Uint32(u[20:24])) + 7) &^ 7
+			}
+
+		case zfsSendWrite:
+			addExtent(order.Uint64(u[0:8]), order.Uint64(u[16:24]), order.Uint64(u[24:32]))
+
+			payload = order.Uint64(u[24:32])
+			if u[42] != 0 {
+				payload = order.Uint64(u[88:96])
+			}
+
+		case zfsSendFree:
+			addExtent(order.Uint64(u[0:8]), order.Uint64(u[8:16]), order.Uint64(u[16:24]))
+		case zfsSendSpill:
+			payload = order.Uint64(u[32:40])
+			if payload == 0 {
+				payload = order.Uint64(u[8:16])
+			}
+
+		case zfsSendWriteEmbedded:
+			addExtent(order.Uint64(u[0:8]), order.Uint64(u[8:16]), order.Uint64(u[16:24]))
+			payload = (uint64(order.Uint32(u[44:48])) + 7) &^ 7
+		case zfsSendEnd:
+			for _, extent := range diffMergeExtents(extents) {
+				err := changeFunc(extent)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		_, err = io.CopyN(io.Discard, stream, int64(payload))
+		if err != nil {
+			return fmt.Errorf("Failed reading ZFS send stream: %w", err)
+		}
+	}
+}
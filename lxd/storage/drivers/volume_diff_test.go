@@ -0,0 +1,185 @@
+package drivers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// collectChanges returns a change function appending to the provided list.
+func collectChanges(changes *[]api.StorageVolumeSnapshotChange) func(change api.StorageVolumeSnapshotChange) error {
+	return func(change api.StorageVolumeSnapshotChange) error {
+		*changes = append(*changes, change)
+		return nil
+	}
+}
+
+func TestDiffMergeExtents(t *testing.T) {
+	extents := diffMergeExtents([]api.StorageVolumeSnapshotChange{
+		{Offset: 100, Length: 50},
+		{Offset: 0, Length: 10},
+		{Offset: 10, Length: 20},
+		{Offset: 120, Length: 10},
+	})
+
+	assert.Equal(t, []api.StorageVolumeSnapshotChange{
+		{Type: api.StorageVolumeSnapshotChangeModified, Offset: 0, Length: 30},
+		{Type: api.StorageVolumeSnapshotChangeModified, Offset: 100, Length: 50},
+	}, extents)
+}
+
+func TestDiffFilesystems(t *testing.T) {
+	fromPath := t.TempDir()
+	toPath := t.TempDir()
+	mtime := time.Now().Add(-time.Hour)
+
+	writeFile := func(root string, name string, content string) {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	writeFile(fromPath, "unchanged", "a")
+	writeFile(toPath, "unchanged", "a")
+	writeFile(fromPath, "modified", "a")
+	writeFile(toPath, "modified", "ab")
+	writeFile(fromPath, "deleted/file", "a")
+	writeFile(toPath, "added/file", "a")
+
+	var changes []api.StorageVolumeSnapshotChange
+	require.NoError(t, diffFilesystems(fromPath, toPath, collectChanges(&changes)))
+
+	assert.Equal(t, []api.StorageVolumeSnapshotChange{
+		{Type: api.StorageVolumeSnapshotChangeAdded, Path: "added"},
+		{Type: api.StorageVolumeSnapshotChangeAdded, Path: "added/file"},
+		{Type: api.StorageVolumeSnapshotChangeModified, Path: "modified"},
+		{Type: api.StorageVolumeSnapshotChangeDeleted, Path: "deleted"},
+	}, changes)
+}
+
+func TestParseZFSDiff(t *testing.T) {
+	output := strings.Join([]string{
+		"M\t/\t/mnt/vol/",
+		"+\tF\t/mnt/vol/new\\0040file",
+		"M\tF\t/mnt/vol/changed",
+		"-\tF\t/mnt/vol/removed",
+		"R\tF\t/mnt/vol/old\t/mnt/vol/renamed",
+		"M\t/\t/mnt/vol/dir",
+	}, "\n")
+
+	var changes []api.StorageVolumeSnapshotChange
+	require.NoError(t, parseZFSDiff(strings.NewReader(output), "/mnt/vol", collectChanges(&changes)))
+
+	assert.Equal(t, []api.StorageVolumeSnapshotChange{
+		{Type: api.StorageVolumeSnapshotChangeAdded, Path: "new file"},
+		{Type: api.StorageVolumeSnapshotChangeModified, Path: "changed"},
+		{Type: api.StorageVolumeSnapshotChangeDeleted, Path: "removed"},
+		{Type: api.StorageVolumeSnapshotChangeDeleted, Path: "old"},
+		{Type: api.StorageVolumeSnapshotChangeAdded, Path: "renamed"},
+	}, changes)
+
+	err := parseZFSDiff(strings.NewReader("X\tF\t/mnt/vol/foo"), "/mnt/vol", collectChanges(&changes))
+	assert.Error(t, err)
+}
+
+func TestParseBtrfsReceiveDump(t *testing.T) {
+	output := strings.Join([]string{
+		"snapshot        ./snap1                         uuid=abc transid=10 parent_uuid=def parent_transid=8",
+		"utimes          ./snap1/                        atime=2024-01-01T00:00:00+0000",
+		"mkfile          ./snap1/o257-10-0",
+		"rename          ./snap1/o257-10-0               dest=./snap1/new\\ file",
+		"update_extent   ./snap1/new\\ file               offset=0 len=4096",
+		"update_extent   ./snap1/changed                 offset=0 len=4096",
+		"chmod           ./snap1/perms                   mode=600",
+		"unlink          ./snap1/removed",
+		"mkfile          ./snap1/temp",
+		"unlink          ./snap1/temp",
+	}, "\n")
+
+	var changes []api.StorageVolumeSnapshotChange
+	require.NoError(t, parseBtrfsReceiveDump(strings.NewReader(output), "", collectChanges(&changes)))
+
+	assert.Equal(t, []api.StorageVolumeSnapshotChange{
+		{Type: api.StorageVolumeSnapshotChangeAdded, Path: "new file"},
+		{Type: api.StorageVolumeSnapshotChangeModified, Path: "changed"},
+		{Type: api.StorageVolumeSnapshotChangeModified, Path: "perms"},
+		{Type: api.StorageVolumeSnapshotChangeDeleted, Path: "removed"},
+	}, changes)
+}
+
+func TestParseBtrfsReceiveDumpBlock(t *testing.T) {
+	output := strings.Join([]string{
+		"snapshot        ./snap1                         uuid=abc transid=10 parent_uuid=def parent_transid=8",
+		"update_extent   ./snap1/root.img                offset=8192 len=4096",
+		"update_extent   ./snap1/root.img                offset=0 len=4096",
+		"update_extent   ./snap1/root.img                offset=4096 len=4096",
+		"update_extent   ./snap1/other                   offset=0 len=4096",
+	}, "\n")
+
+	var changes []api.StorageVolumeSnapshotChange
+	require.NoError(t, parseBtrfsReceiveDump(strings.NewReader(output), genericVolumeDiskFile, collectChanges(&changes)))
+
+	assert.Equal(t, []api.StorageVolumeSnapshotChange{
+		{Type: api.StorageVolumeSnapshotChangeModified, Offset: 0, Length: 12288},
+	}, changes)
+}
+
+func TestParseRBDDiff(t *testing.T) {
+	output := `[{"offset":0,"length":4194304,"exists":"true"},{"offset":4194304,"length":4194304,"exists":"false"},{"offset":16777216,"length":4194304,"exists":"true"}]`
+
+	var changes []api.StorageVolumeSnapshotChange
+	require.NoError(t, parseRBDDiff([]byte(output), collectChanges(&changes)))
+
+	assert.Equal(t, []api.StorageVolumeSnapshotChange{
+		{Type: api.StorageVolumeSnapshotChangeModified, Offset: 0, Length: 8388608},
+		{Type: api.StorageVolumeSnapshotChangeModified, Offset: 16777216, Length: 4194304},
+	}, changes)
+}
+
+// zfsSendRecord builds a little-endian ZFS send stream record from offsets of the record union and their values.
+func zfsSendRecord(recordType uint32, payloadLen uint32, fields map[int]uint64) []byte {
+	record := make([]byte, zfsSendRecordSize)
+	binary.LittleEndian.PutUint32(record[0:4], recordType)
+	binary.LittleEndian.PutUint32(record[4:8], payloadLen)
+	for offset, value := range fields {
+		binary.LittleEndian.PutUint64(record[8+offset:], value)
+	}
+
+	return record
+}
+
+func TestParseZFSSendStream(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(zfsSendRecord(zfsSendBegin, 8, map[int]uint64{0: zfsSendMagic}))
+	stream.Write(make([]byte, 8))
+	stream.Write(zfsSendRecord(zfsSendObject, 0, map[int]uint64{0: 1, 20: 0}))
+	stream.Write(zfsSendRecord(zfsSendWrite, 0, map[int]uint64{0: 1, 16: 8192, 24: 4096}))
+	stream.Write(make([]byte, 4096))
+	stream.Write(zfsSendRecord(zfsSendWrite, 0, map[int]uint64{0: 1, 16: 0, 24: 4096}))
+	stream.Write(make([]byte, 4096))
+	stream.Write(zfsSendRecord(zfsSendWrite, 0, map[int]uint64{0: 2, 16: 65536, 24: 512}))
+	stream.Write(make([]byte, 512))
+	stream.Write(zfsSendRecord(zfsSendFree, 0, map[int]uint64{0: 1, 8: 4096, 16: 4096}))
+	stream.Write(zfsSendRecord(zfsSendFree, 0, map[int]uint64{0: 1, 8: 1048576, 16: zfsSendFreeToEndOfObj}))
+	stream.Write(zfsSendRecord(zfsSendEnd, 0, nil))
+
+	var changes []api.StorageVolumeSnapshotChange
+	require.NoError(t, parseZFSSendStream(&stream, 2097152, collectChanges(&changes)))
+
+	assert.Equal(t, []api.StorageVolumeSnapshotChange{
+		{Type: api.StorageVolumeSnapshotChangeModified, Offset: 0, Length: 12288},
+		{Type: api.StorageVolumeSnapshotChangeModified, Offset: 1048576, Length: 1048576},
+	}, changes)
+
+	require.Error(t, parseZFSSendStream(bytes.NewReader(make([]byte, zfsSendRecordSize)), 4096, collectChanges(&changes)))
+}
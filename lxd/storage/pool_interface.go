@@ -115,6 +115,10 @@ type Pool interface {
 	UpdateBucketKey(projectName string, bucketName string, keyName string, key api.StorageBucketKeyPut) error
 	DeleteBucketKey(projectName string, bucketName string, keyName string) error
 	GetBucketURL(bucketName string) *url.URL
+	ListBucketObjects(projectName string, bucketName string, prefix string) ([]api.StorageBucketObject, error)
+	GetBucketObject(projectName string, bucketName string, objectName string) (io.ReadCloser, int64, error)
+	PutBucketObject(projectName string, bucketName string, objectName string, data io.Reader, size int64) error
+	DeleteBucketObject(projectName string, bucketName string, objectName string) error
 
 	// Custom volumes.
 	CreateCustomVolume(ctx context.Context, projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, progressReporter ioprogress.ProgressReporter) error
@@ -139,6 +143,19 @@ type Pool interface {
 	UpdateCustomVolumeSnapshot(ctx context.Context, projectName string, volName string, newDesc string, newConfig map[string]string, newExpiryDate time.Time, progressReporter ioprogress.ProgressReporter) error
 	RestoreCustomVolume(ctx context.Context, projectName string, volName string, snapshotName string, progressReporter ioprogress.ProgressReporter) error
 
+	// Volume snapshot diffs.
+	DiffVolumeSnapshots(projectName string, volType drivers.VolumeType, volName string, fromSnapshot string, toSnapshot string, changeFunc func(change api.StorageVolumeSnapshotChange) error, progressReporter ioprogress.ProgressReporter) error
+	ExportVolumeSnapshotDiff(projectName string, volType drivers.VolumeType, volName string, fromSnapshot string, toSnapshot string, w io.Writer, progressReporter ioprogress.ProgressReporter) error
+
+	// Integrity checks.
+	ScrubPool(progressReporter ioprogress.ProgressReporter) error
+	CheckVolume(projectName string, volType drivers.VolumeType, volName string, progressReporter ioprogress.ProgressReporter) error
+
+	// Trash.
+	TrashVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error
+	RestoreTrashedVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error
+	PurgeTrashedVolume(projectName string, poolVol *backupConfig.Config, trashUUID string, progressReporter ioprogress.ProgressReporter) error
+
 	// Custom volume migration.
 	MigrationTypes(contentType drivers.ContentType, refresh bool, copySnapshots bool) []migration.Type
 	CreateCustomVolumeFromMigration(ctx context.Context, projectName string, conn io.ReadWriteCloser, args migration.VolumeTargetArgs, progressReporter ioprogress.ProgressReporter) error
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -37,6 +40,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
 )
 
@@ -508,6 +512,57 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		rules["security.shared"] = validate.Optional(validate.IsBool)
 	}
 
+	// I/O limits follow custom volumes to every instance they are attached to.
+	if vol == nil || vol.Type() == drivers.VolumeTypeCustom {
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.iops)
+		// For virtual machines, the limit applies to the combined read and write operations of all disk devices attaching the volume to the same virtual machine.
+		// Each virtual machine has its own limit, and the I/O limits of its disk devices attaching the volume are ignored.
+		// Filesystem volumes with I/O limits cannot be attached to virtual machines.
+		// For containers, it is applied separately to reads and writes on the block device backing the volume.
+		// Changes take effect the next time the volume is attached or the instance is started.
+		// ---
+		//  type: integer
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.limits.iops`
+		//  shortdesc: I/O operations per second limit
+		//  scope: global
+		rules["limits.iops"] = validate.Optional(validate.IsUint32)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.iops.burst)
+		// This option requires `limits.iops` to be set to a value lower than this one.
+		// Burst limits are only supported for virtual machines.
+		// ---
+		//  type: integer
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.limits.iops.burst`
+		//  shortdesc: I/O operations per second allowed during bursts
+		//  scope: global
+		rules["limits.iops.burst"] = validate.Optional(validate.IsUint32)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.bandwidth)
+		// Specify the limit in bytes per second ({ref}`suffixes <instances-limit-units>` are supported), for example `100MB`.
+		// For virtual machines, the limit applies to the combined reads and writes of all disk devices attaching the volume to the same virtual machine.
+		// Each virtual machine has its own limit, and the I/O limits of its disk devices attaching the volume are ignored.
+		// Filesystem volumes with I/O limits cannot be attached to virtual machines.
+		// For containers, it is applied separately to reads and writes on the block device backing the volume.
+		// Changes take effect the next time the volume is attached or the instance is started.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.limits.bandwidth`
+		//  shortdesc: I/O bandwidth limit
+		//  scope: global
+		rules["limits.bandwidth"] = validate.Optional(validate.IsSize)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.bandwidth.burst)
+		// This option requires `limits.bandwidth` to be set to a value lower than this one.
+		// Burst limits are only supported for virtual machines.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.limits.bandwidth.burst`
+		//  shortdesc: I/O bandwidth allowed during bursts
+		//  scope: global
+		rules["limits.bandwidth.burst"] = validate.Optional(validate.IsSize)
+	}
+
 	// Those keys are only valid for volumes.
 	if vol != nil {
 		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.uuid)
@@ -570,6 +625,42 @@ func validatePoolCommonRules() map[string]func(string) error {
 		//  shortdesc: Whether to use compression while migrating storage pools
 		//  scope: global
 		"rsync.compression": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=storage-btrfs,storage-ceph,storage-cephfs,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=pool-conf; key=scrub.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of
+		// schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty
+		// to disable scheduled integrity checks (the default).
+		//
+		// On each run, the storage pool is scrubbed (ZFS and Btrfs) and all unused volumes are checked.
+		// ---
+		//  type: string
+		//  shortdesc: Schedule for storage pool integrity checks
+		//  scope: global
+		"scrub.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+		// lxdmeta:generate(entities=storage-btrfs,storage-ceph,storage-cephfs,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=pool-conf; key=usage.warning)
+		// When the percentage of used space in the storage pool reaches this value, a warning is raised
+		// and a `storage-pool-usage-warning` lifecycle event is emitted.
+		// ---
+		//  type: integer
+		//  shortdesc: Usage percentage at which a warning is raised
+		//  scope: global
+		"usage.warning": validate.Optional(validate.IsInRange(1, 100)),
+		// lxdmeta:generate(entities=storage-btrfs,storage-ceph,storage-cephfs,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=pool-conf; key=usage.critical)
+		// When the percentage of used space in the storage pool reaches this value, a high severity warning
+		// is raised and a `storage-pool-usage-critical` lifecycle event is emitted.
+		// ---
+		//  type: integer
+		//  shortdesc: Usage percentage at which a critical warning is raised
+		//  scope: global
+		"usage.critical": validate.Optional(validate.IsInRange(1, 100)),
+		// lxdmeta:generate(entities=storage-btrfs,storage-ceph,storage-cephfs,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=pool-conf; key=usage.critical.enforce)
+		// When enabled, new volumes cannot be created in the storage pool while its usage is at or above
+		// `usage.critical`.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to reject new volumes above the critical usage threshold
+		//  scope: global
+		"usage.critical.enforce": validate.Optional(validate.IsBool),
 	}
 
 	// Add to pool config rules (prefixed with volume.*) which are common for pool and volume.
@@ -657,6 +748,69 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 	return rules
 }
 
+// VolumeDiskLimits returns the I/O limits configured on a custom volume through its limits.* config keys.
+// All attachments of the volume share a throttle group named after the volume ID.
+// Returns nil if the volume has no limits configured.
+func VolumeDiskLimits(volID int64, volConfig map[string]string) (*deviceConfig.DiskLimits, error) {
+	if volConfig["limits.iops"] == "" && volConfig["limits.bandwidth"] == "" {
+		return nil, nil
+	}
+
+	limits := deviceConfig.DiskLimits{
+		Group: "lxd_volume_" + strconv.FormatInt(volID, 10),
+	}
+
+	parseIOps := func(key string) (int64, error) {
+		if volConfig[key] == "" {
+			return 0, nil
+		}
+
+		value, err := strconv.ParseInt(volConfig[key], 10, 64)
+		if err != nil {
+			return -1, fmt.Errorf("Failed parsing %q: %w", key, err)
+		}
+
+		return value, nil
+	}
+
+	parseBytes := func(key string) (int64, error) {
+		if volConfig[key] == "" {
+			return 0, nil
+		}
+
+		value, err := units.ParseByteSizeString(volConfig[key])
+		if err != nil {
+			return -1, fmt.Errorf("Failed parsing %q: %w", key, err)
+		}
+
+		return value, nil
+	}
+
+	var err error
+
+	limits.IOps, err = parseIOps("limits.iops")
+	if err != nil {
+		return nil, err
+	}
+
+	limits.IOpsBurst, err = parseIOps("limits.iops.burst")
+	if err != nil {
+		return nil, err
+	}
+
+	limits.Bytes, err = parseBytes("limits.bandwidth")
+	if err != nil {
+		return nil, err
+	}
+
+	limits.BytesBurst, err = parseBytes("limits.bandwidth.burst")
+	if err != nil {
+		return nil, err
+	}
+
+	return &limits, nil
+}
+
 // ImageUnpack unpacks a filesystem image into the destination path.
 // There are several formats that images can come in:
 // Container Format A: Separate metadata tarball and root squashfs file.
@@ -1419,3 +1573,38 @@ func VolumeDetermineNextSnapshotName(ctx context.Context, s *state.State, pool s
 
 	return pattern, nil
 }
+
+// verifyImageFiles checks that the cached files of an image still match its fingerprint.
+// Returns false if the image files aren't available on this server.
+func verifyImageFiles(fingerprint string) (bool, error) {
+	imagePath := shared.VarPath("images", fingerprint)
+	if !shared.PathExists(imagePath) {
+		return false, nil
+	}
+
+	// The fingerprint of split images covers the metadata file followed by the rootfs file.
+	hash := sha256.New()
+	for _, path := range []string{imagePath, imagePath + ".rootfs"} {
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return false, err
+		}
+
+		_, err = io.Copy(hash, f)
+		_ = f.Close()
+		if err != nil {
+			return false, fmt.Errorf("Failed reading image file %q: %w", path, err)
+		}
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if sum != fingerprint {
+		return true, fmt.Errorf("Image files of %q don't match their fingerprint (got %q)", fingerprint, sum)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var storagePoolBucketObjectsCmd = APIEndpoint{
+	Path:            "storage-pools/{poolName}/buckets/{bucketName}/objects",
+	MetricsType:     entity.TypeStoragePool,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: storagePoolBucketObjectsGet, AccessHandler: storageBucketAccessHandler(auth.EntitlementCanView)},
+}
+
+var storagePoolBucketObjectCmd = APIEndpoint{
+	Path:            "storage-pools/{poolName}/buckets/{bucketName}/objects/{objectName}",
+	MetricsType:     entity.TypeStoragePool,
+	ProjectSpecific: true,
+
+	Delete: APIEndpointAction{Handler: storagePoolBucketObjectDelete, AccessHandler: storageBucketAccessHandler(auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: storagePoolBucketObjectGet, AccessHandler: storageBucketAccessHandler(auth.EntitlementCanView)},
+	Put:    APIEndpointAction{Handler: storagePoolBucketObjectPut, AccessHandler: storageBucketAccessHandler(auth.EntitlementCanEdit)},
+}
+
+// storageBucketObjectName returns the object name from the request path.
+func storageBucketObjectName(r *http.Request) (string, error) {
+	objectName := r.PathValue("objectName")
+	if objectName == "" {
+		return "", api.StatusErrorf(http.StatusBadRequest, "Object name is required")
+	}
+
+	if len(objectName) > 1024 {
+		return "", api.StatusErrorf(http.StatusBadRequest, "Object name must not be longer than 1024 bytes")
+	}
+
+	return objectName, nil
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/buckets/{bucketName}/objects storage storage_pool_bucket_objects_get
+//
+//	Get the storage pool bucket objects
+//
+//	Returns a list of storage pool bucket objects (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: prefix
+//	    description: Only return the objects whose name starts with this prefix
+//	    type: string
+//	    example: backups/
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/storage-pools/default/buckets/foo/objects/backups%2Fdb.tar.gz"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/buckets/{bucketName}/objects?recursion=1 storage storage_pool_bucket_objects_get_recursion1
+//
+//	Get the storage pool bucket objects
+//
+//	Returns a list of storage pool bucket objects.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: prefix
+//	    description: Only return the objects whose name starts with this prefix
+//	    type: string
+//	    example: backups/
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of storage pool bucket objects
+//	          items:
+//	            $ref: "#/definitions/StorageBucketObject"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolBucketObjectsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	details, err := request.GetContextValue[storageBucketDetails](r.Context(), ctxStorageBucketDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	driverInfo := details.pool.Driver().Info()
+	if !driverInfo.Buckets {
+		return response.BadRequest(fmt.Errorf("Storage pool driver %q does not support buckets", driverInfo.Name))
+	}
+
+	objects, err := details.pool.ListBucketObjects(effectiveProjectName, details.bucketName, request.QueryParam(r, "prefix"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	recursion, _ := util.IsRecursionRequest(r)
+	if recursion > 0 {
+		return response.SyncResponse(true, objects)
+	}
+
+	objectURLs := make([]string, 0, len(objects))
+	for _, object := range objects {
+		objectURLs = append(objectURLs, object.URL(version.APIVersion, details.pool.Name(), effectiveProjectName, details.bucketName).String())
+	}
+
+	return response.SyncResponse(true, objectURLs)
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/buckets/{bucketName}/objects/{objectName} storage storage_pool_bucket_object_get
+//
+//	Get the storage pool bucket object
+//
+//	Downloads the content of the storage pool bucket object.
+//	The object name must be URL encoded, including any slash.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Raw object content
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolBucketObjectGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	details, err := request.GetContextValue[storageBucketDetails](r.Context(), ctxStorageBucketDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	objectName, err := storageBucketObjectName(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	data, size, err := details.pool.GetBucketObject(effectiveProjectName, details.bucketName, objectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		defer data.Close()
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if size >= 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		_, err := io.Copy(w, data)
+		return err
+	})
+}
+
+// swagger:operation PUT /1.0/storage-pools/{poolName}/buckets/{bucketName}/objects/{objectName} storage storage_pool_bucket_object_put
+//
+//	Upload the storage pool bucket object
+//
+//	Stores the request body in the storage pool bucket object, replacing any existing content.
+//	The object name must be URL encoded, including any slash, and the Content-Length header must be set.
+//
+//	---
+//	consumes:
+//	  - application/octet-stream
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: raw_object
+//	    description: Raw object content
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolBucketObjectPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	details, err := request.GetContextValue[storageBucketDetails](r.Context(), ctxStorageBucketDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	objectName, err := storageBucketObjectName(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if r.ContentLength < 0 {
+		return response.BadRequest(errors.New("The object size must be provided through the Content-Length header"))
+	}
+
+	err = details.pool.PutBucketObject(effectiveProjectName, details.bucketName, objectName, r.Body, r.ContentLength)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(effectiveProjectName, lifecycle.StorageBucketObjectCreated.Event(details.pool, effectiveProjectName, details.bucketName, objectName, request.CreateRequestor(r.Context()), map[string]any{"size": r.ContentLength}))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/storage-pools/{poolName}/buckets/{bucketName}/objects/{objectName} storage storage_pool_bucket_object_delete
+//
+//	Delete the storage pool bucket object
+//
+//	Removes the storage pool bucket object.
+//	The object name must be URL encoded, including any slash.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolBucketObjectDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	details, err := request.GetContextValue[storageBucketDetails](r.Context(), ctxStorageBucketDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	objectName, err := storageBucketObjectName(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = details.pool.DeleteBucketObject(effectiveProjectName, details.bucketName, objectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(effectiveProjectName, lifecycle.StorageBucketObjectDeleted.Event(details.pool, effectiveProjectName, details.bucketName, objectName, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
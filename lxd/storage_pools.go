@@ -1090,6 +1090,21 @@ func storagePoolDelete(d *Daemon, r *http.Request) response.Response {
 			return response.BadRequest(errors.New("The storage pool is currently in use"))
 		}
 
+		// Trashed volumes are not tracked as pool users, so they must be purged explicitly.
+		var trashEntries []dbCluster.TrashEntry
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			poolID := pool.ID()
+			trashEntries, err = dbCluster.GetTrashEntries(ctx, tx.Tx(), dbCluster.TrashEntryFilter{StoragePoolID: &poolID})
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if len(trashEntries) > 0 {
+			return response.BadRequest(fmt.Errorf("The storage pool still holds %d trashed volumes", len(trashEntries)))
+		}
+
 		// Get the cluster notifier
 		notifier, err = cluster.NewOperationNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
 		if err != nil {
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// storagePoolUsageLevel represents how full a storage pool is compared to its configured thresholds.
+type storagePoolUsageLevel int
+
+const (
+	storagePoolUsageNormal storagePoolUsageLevel = iota
+	storagePoolUsageWarning
+	storagePoolUsageCritical
+)
+
+// storagePoolUsageCheckTask returns a task that checks the usage of the storage pools against their configured
+// thresholds, raising warnings and lifecycle events when they are exceeded.
+func storagePoolUsageCheckTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	// Keep track of the last known level of each pool so lifecycle events are only sent on changes.
+	levels := map[string]storagePoolUsageLevel{}
+
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		err := storagePoolUsageCheck(ctx, s, levels)
+		if err != nil {
+			logger.Error("Failed checking storage pool usage", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
+
+// storagePoolUsageCheck checks the usage of all storage pools available on this member.
+func storagePoolUsageCheck(ctx context.Context, s *state.State, levels map[string]storagePoolUsageLevel) error {
+	var poolNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+
+		return err
+	})
+	if err != nil {
+		if response.IsNotFoundError(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed loading storage pools: %w", err)
+	}
+
+	leaderInfo, err := s.LeaderInfo()
+	if err != nil {
+		return err
+	}
+
+	for _, poolName := range poolNames {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		// Remote pools are shared by all members, so only check them from the leader.
+		// Other members clear any warning they raised while they were the leader.
+		if pool.Driver().Info().Remote && !leaderInfo.Leader {
+			delete(levels, poolName)
+
+			for _, warningType := range []warningtype.Type{warningtype.StoragePoolUsageWarning, warningtype.StoragePoolUsageCritical} {
+				err := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningType, entity.TypeStoragePool, int(pool.ID()))
+				if err != nil {
+					logger.Warn("Failed resolving storage pool usage warning", logger.Ctx{"pool": poolName, "err": err})
+				}
+			}
+
+			continue
+		}
+
+		level, err := storagePoolUsageCheckPool(ctx, s, pool, levels[poolName])
+		if err != nil {
+			logger.Warn("Failed checking storage pool usage", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		levels[poolName] = level
+	}
+
+	return nil
+}
+
+// storagePoolUsageCheckPool compares the usage of a storage pool to its thresholds, updates the related
+// warnings and sends a lifecycle event if the pool reached a higher level than previously.
+func storagePoolUsageCheckPool(ctx context.Context, s *state.State, pool storagePools.Pool, lastLevel storagePoolUsageLevel) (storagePoolUsageLevel, error) {
+	config := pool.Driver().Config()
+	poolID := int(pool.ID())
+
+	thresholds := map[storagePoolUsageLevel]uint64{}
+	for level, key := range map[storagePoolUsageLevel]string{storagePoolUsageWarning: "usage.warning", storagePoolUsageCritical: "usage.critical"} {
+		if config[key] == "" {
+			continue
+		}
+
+		threshold, err := strconv.ParseUint(config[key], 10, 64)
+		if err != nil {
+			return lastLevel, fmt.Errorf("Invalid value for %q: %w", key, err)
+		}
+
+		thresholds[level] = threshold
+	}
+
+	level := storagePoolUsageNormal
+	var usage uint64
+
+	if len(thresholds) > 0 {
+		res, err := pool.GetResources()
+		if err != nil {
+			return lastLevel, err
+		}
+
+		if res.Space.Total > 0 {
+			usage = res.Space.Used * 100 / res.Space.Total
+		}
+
+		for _, l := range []storagePoolUsageLevel{storagePoolUsageWarning, storagePoolUsageCritical} {
+			threshold, ok := thresholds[l]
+			if ok && usage >= threshold {
+				level = l
+			}
+		}
+	}
+
+	// Update the warnings, only keeping the one matching the current level.
+	warningTypes := map[storagePoolUsageLevel]warningtype.Type{
+		storagePoolUsageWarning:  warningtype.StoragePoolUsageWarning,
+		storagePoolUsageCritical: warningtype.StoragePoolUsageCritical,
+	}
+
+	for l, warningType := range warningTypes {
+		if l != level {
+			err := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningType, entity.TypeStoragePool, poolID)
+			if err != nil {
+				return lastLevel, err
+			}
+
+			continue
+		}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, "", entity.TypeStoragePool, poolID, warningType, fmt.Sprintf("Storage pool %q is %d%% full (threshold %d%%)", pool.Name(), usage, thresholds[l]))
+		})
+		if err != nil {
+			return lastLevel, err
+		}
+	}
+
+	if level > lastLevel {
+		action := lifecycle.StoragePoolUsageWarning
+		if level == storagePoolUsageCritical {
+			action = lifecycle.StoragePoolUsageCritical
+		}
+
+		eventCtx := map[string]any{"usage": usage, "threshold": thresholds[level]}
+		if !pool.Driver().Info().Remote {
+			eventCtx["location"] = s.ServerName
+		}
+
+		logger.Warn("Storage pool usage above threshold", logger.Ctx{"pool": pool.Name(), "usage": usage, "threshold": thresholds[level]})
+		s.Events.SendLifecycle("", action.Event(pool.Name(), nil, eventCtx))
+	}
+
+	return level, nil
+}
@@ -51,7 +51,7 @@ var storageVolumesCmd = APIEndpoint{
 	MetricsType:     entity.TypeStoragePool,
 	ProjectSpecific: true,
 
-	Get: APIEndpointAction{Handler: storagePoolVolumesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+	Get: APIEndpointAction{Handler: storagePoolVolumesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients, ClusterLinkViews: true},
 }
 
 var storageVolumesTypeCmd = APIEndpoint{
@@ -59,7 +59,7 @@ var storageVolumesTypeCmd = APIEndpoint{
 	MetricsType:     entity.TypeStoragePool,
 	ProjectSpecific: true,
 
-	Get: APIEndpointAction{Handler: storagePoolVolumesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+	Get: APIEndpointAction{Handler: storagePoolVolumesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients, ClusterLinkViews: true},
 }
 
 var storagePoolVolumesCmd = APIEndpoint{
@@ -67,7 +67,7 @@ var storagePoolVolumesCmd = APIEndpoint{
 	MetricsType:     entity.TypeStoragePool,
 	ProjectSpecific: true,
 
-	Get:  APIEndpointAction{Handler: storagePoolVolumesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+	Get:  APIEndpointAction{Handler: storagePoolVolumesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients, ClusterLinkViews: true},
 	Post: APIEndpointAction{Handler: storagePoolVolumesPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateStorageVolumes), ContentTypes: []string{"application/json", "application/octet-stream"}},
 }
 
@@ -76,7 +76,7 @@ var storagePoolVolumesTypeCmd = APIEndpoint{
 	MetricsType:     entity.TypeStoragePool,
 	ProjectSpecific: true,
 
-	Get:  APIEndpointAction{Handler: storagePoolVolumesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+	Get:  APIEndpointAction{Handler: storagePoolVolumesGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients, ClusterLinkViews: true},
 	Post: APIEndpointAction{Handler: storagePoolVolumesPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateStorageVolumes), ContentTypes: []string{"application/json", "application/octet-stream"}},
 }
 
@@ -167,6 +167,16 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entitleme
 //      description: Collection filter
 //      type: string
 //      example: default
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -233,6 +243,16 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entitleme
 //      description: Collection filter
 //      type: string
 //      example: default
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -287,6 +307,16 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entitleme
 //      description: Cluster member name
 //      type: string
 //      example: lxd01
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -346,6 +376,16 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entitleme
 //	    description: Cluster member name
 //	    type: string
 //	    example: lxd01
+//	  - in: query
+//	    name: cluster-link
+//	    description: Retrieve entries from a linked cluster
+//	    type: string
+//	    example: lxd02
+//	  - in: query
+//	    name: all-links
+//	    description: Retrieve entries from the local cluster and all linked clusters
+//	    type: boolean
+//	    example: true
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -405,6 +445,16 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entitleme
 //      description: Collection filter
 //      type: string
 //      example: default
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -471,6 +521,16 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entitleme
 //      description: Collection filter
 //      type: string
 //      example: default
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -525,6 +585,16 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entitleme
 //      description: Cluster member name
 //      type: string
 //      example: lxd01
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -584,6 +654,16 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entitleme
 //	    description: Cluster member name
 //	    type: string
 //	    example: lxd01
+//	  - in: query
+//	    name: cluster-link
+//	    description: Retrieve entries from a linked cluster
+//	    type: string
+//	    example: lxd02
+//	  - in: query
+//	    name: all-links
+//	    description: Retrieve entries from the local cluster and all linked clusters
+//	    type: boolean
+//	    example: true
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -1876,6 +1956,8 @@ func storagePoolVolumeTypePostMigration(state *state.State, r *http.Request, req
 		return response.InternalError(err)
 	}
 
+	ws.transferRate = req.TransferRate
+
 	var entityURL *api.URL
 	var opType operationtype.Type
 	srcVolParentName, srcVolSnapName, srcIsSnapshot := api.GetParentAndSnapshotName(details.fullName)
@@ -2547,7 +2629,7 @@ func storagePoolVolumeDelete(d *Daemon, r *http.Request) response.Response {
 		return operations.ScheduleUserOperationFromRequest(s, r, args)
 	}
 
-	op, err := doStoragePoolVolumeDelete(r.Context(), opScheduler, s, details.volumeName, details.volumeType, details.pool, requestProjectName, effectiveProjectName)
+	op, err := doStoragePoolVolumeDelete(r.Context(), opScheduler, s, details.volumeName, details.volumeType, details.pool, requestProjectName, effectiveProjectName, true)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -2556,7 +2638,8 @@ func storagePoolVolumeDelete(d *Daemon, r *http.Request) response.Response {
 }
 
 // doStoragePoolVolumeDelete returns an [operations.Operation] that, when run, will delete the given storage volume in the given project and pool.
-func doStoragePoolVolumeDelete(ctx context.Context, opScheduler operations.OperationScheduler, s *state.State, name string, volType cluster.StoragePoolVolumeType, pool storagePools.Pool, requestProjectName string, effectiveProjectName string) (*operations.Operation, error) {
+// If trash is true and the project has a deletion retention configured, custom volumes are moved into the project trash instead.
+func doStoragePoolVolumeDelete(ctx context.Context, opScheduler operations.OperationScheduler, s *state.State, name string, volType cluster.StoragePoolVolumeType, pool storagePools.Pool, requestProjectName string, effectiveProjectName string, trash bool) (*operations.Operation, error) {
 	if volType != cluster.StoragePoolVolumeTypeCustom && volType != cluster.StoragePoolVolumeTypeImage {
 		return nil, api.StatusErrorf(http.StatusBadRequest, "Storage volumes of type %q cannot be deleted directly", volType.String())
 	}
@@ -2597,6 +2680,17 @@ func doStoragePoolVolumeDelete(ctx context.Context, opScheduler operations.Opera
 	run := func(ctx context.Context, op *operations.Operation) error {
 		switch volType {
 		case cluster.StoragePoolVolumeTypeCustom:
+			if trash {
+				retention, err := projectDeletionRetention(ctx, s, effectiveProjectName)
+				if err != nil {
+					return err
+				}
+
+				if retention != "" {
+					return customVolumeTrash(ctx, s, pool, effectiveProjectName, name, retention, op)
+				}
+			}
+
 			return pool.DeleteCustomVolume(ctx, effectiveProjectName, name, op)
 		case cluster.StoragePoolVolumeTypeImage:
 			return pool.DeleteImage(ctx, name, op)
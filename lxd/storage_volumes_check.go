@@ -0,0 +1,409 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var storagePoolVolumeTypeCheckCmd = APIEndpoint{
+	Path:            "storage-pools/{poolName}/volumes/{type}/{volumeName}/check",
+	MetricsType:     entity.TypeStoragePool,
+	ProjectSpecific: true,
+
+	Post: APIEndpointAction{Handler: storagePoolVolumeTypeCheckPost, AccessHandler: storagePoolVolumeTypeAccessHandler(auth.EntitlementCanEdit)},
+}
+
+var storagePoolScrubCmd = APIEndpoint{
+	Path:        "storage-pools/{poolName}/scrub",
+	MetricsType: entity.TypeStoragePool,
+
+	Post: APIEndpointAction{Handler: storagePoolScrubPost, AccessHandler: allowPermission(entity.TypeStoragePool, auth.EntitlementCanEdit, "poolName")},
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/check storage storage_pool_volume_type_check_post
+//
+//	Check the storage volume
+//
+//	Runs the integrity checks supported by the storage driver on the volume.
+//	Image volumes are also verified against their fingerprint.
+//	The result is recorded in the volume state.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeCheckPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	details, err := request.GetContextValue[storageVolumeDetails](r.Context(), ctxStorageVolumeDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if details.snapshotName != "" {
+		return response.BadRequest(errors.New("Storage volume snapshots cannot be checked"))
+	}
+
+	// Forward if needed.
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
+	projectName := effectiveProjectName
+	if details.volumeType == dbCluster.StoragePoolVolumeTypeImage {
+		projectName = api.ProjectDefaultName
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		status, err := storageVolumeCheck(ctx, s, details.pool, projectName, details.volumeType, details.volumeName, op)
+		if err != nil {
+			return err
+		}
+
+		return op.UpdateMetadata(map[string]any{"status": status})
+	}
+
+	volumeURL := api.NewURL().Path(version.APIVersion, "storage-pools", details.pool.Name(), "volumes", details.volumeTypeName, details.volumeName).Project(effectiveProjectName)
+	if s.ServerClustered && !details.pool.Driver().Info().Remote {
+		volumeURL = volumeURL.Target(s.ServerName)
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: effectiveProjectName,
+		Type:        operationtype.VolumeCheck,
+		Class:       operationtype.OperationClassTask,
+		EntityURL:   volumeURL,
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/scrub storage storage_pool_scrub_post
+//
+//	Scrub the storage pool
+//
+//	Verifies the integrity of the storage pool through the storage driver and checks
+//	all the volumes of the pool which aren't in use.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolScrubPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	poolName := r.PathValue("poolName")
+
+	// Forward if needed.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		return storagePoolScrub(ctx, s, pool, op)
+	}
+
+	args := operations.OperationArgs{
+		Type:      operationtype.StoragePoolScrub,
+		Class:     operationtype.OperationClassTask,
+		EntityURL: entity.StoragePoolURL(pool.Name()),
+		RunHook:   run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// storageVolumeCheck checks the integrity of a volume and records the result in the volume state.
+// A warning is raised for the volume when the check fails and resolved otherwise.
+func storageVolumeCheck(ctx context.Context, s *state.State, pool storagePools.Pool, projectName string, volType dbCluster.StoragePoolVolumeType, volName string, op *operations.Operation) (string, error) {
+	status := api.StorageVolumeCheckStatusOK
+	message := ""
+
+	checkErr := pool.CheckVolume(projectName, storagePools.VolumeDBTypeToType(volType), volName, op)
+	if errors.Is(checkErr, storageDrivers.ErrInUse) {
+		status = api.StorageVolumeCheckStatusSkipped
+		message = "Volume is in use"
+	} else if errors.Is(checkErr, storageDrivers.ErrNotSupported) {
+		status = api.StorageVolumeCheckStatusSkipped
+		message = "Not supported by the storage driver"
+	} else if checkErr != nil {
+		status = api.StorageVolumeCheckStatusFailed
+		message = checkErr.Error()
+	}
+
+	var volID int
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVol, err := tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, volType, volName, true)
+		if err != nil {
+			return err
+		}
+
+		volID = int(dbVol.ID)
+
+		err = dbCluster.UpsertStorageVolumeCheck(ctx, tx.Tx(), dbCluster.StorageVolumeChecksRow{
+			StorageVolumeID: dbVol.ID,
+			Status:          status,
+			Message:         message,
+			Date:            time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if status == api.StorageVolumeCheckStatusFailed {
+			return tx.UpsertWarningLocalNode(ctx, projectName, entity.TypeStorageVolume, volID, warningtype.StorageVolumeCheckFailed, fmt.Sprintf("Volume %q on pool %q: %s", volName, pool.Name(), message))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if status == api.StorageVolumeCheckStatusFailed {
+		return status, fmt.Errorf("Integrity check of volume %q failed: %w", volName, checkErr)
+	}
+
+	err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, projectName, warningtype.StorageVolumeCheckFailed, entity.TypeStorageVolume, volID)
+	if err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+// storagePoolScrub scrubs a storage pool and checks all the volumes of the pool located on this member.
+func storagePoolScrub(ctx context.Context, s *state.State, pool storagePools.Pool, op *operations.Operation) error {
+	poolID := int(pool.ID())
+
+	var errs []error
+
+	scrubErr := pool.ScrubPool(op)
+	if scrubErr != nil && !errors.Is(scrubErr, storageDrivers.ErrNotSupported) {
+		errs = append(errs, fmt.Errorf("Failed scrubbing storage pool %q: %w", pool.Name(), scrubErr))
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, "", entity.TypeStoragePool, poolID, warningtype.StoragePoolScrubFailed, fmt.Sprintf("Storage pool %q: %v", pool.Name(), scrubErr))
+		})
+		if err != nil {
+			return err
+		}
+	} else if scrubErr == nil {
+		err := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningtype.StoragePoolScrubFailed, entity.TypeStoragePool, poolID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var volumes []*db.StorageVolume
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolID := pool.ID()
+		volumes, err = tx.GetStorageVolumes(ctx, true, db.StorageVolumeFilter{PoolID: &poolID})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading storage volumes: %w", err)
+	}
+
+	for _, vol := range volumes {
+		if shared.IsSnapshot(vol.Name) {
+			continue
+		}
+
+		volType, err := dbCluster.StoragePoolVolumeTypeFromName(vol.Type)
+		if err != nil {
+			continue
+		}
+
+		_, err = storageVolumeCheck(ctx, s, pool, vol.Project, volType, vol.Name, op)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// storagePoolScrubTask returns a task that scrubs the storage pools whose "scrub.schedule" matches
+// the current time.
+func storagePoolScrubTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		err := storagePoolScrubScheduled(ctx, s)
+		if err != nil {
+			logger.Error("Failed running scheduled storage pool scrubs", logger.Ctx{"err": err})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		// Skip the first run to avoid scrubbing pools at daemon startup.
+		if first {
+			first = false
+			return time.Minute, task.ErrSkip
+		}
+
+		return time.Minute, nil
+	}
+
+	return f, schedule
+}
+
+// storagePoolScrubScheduled scrubs the storage pools available on this member which are scheduled now.
+// Remote pools are scrubbed by the leader.
+func storagePoolScrubScheduled(ctx context.Context, s *state.State) error {
+	var poolNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+
+		return err
+	})
+	if err != nil {
+		if response.IsNotFoundError(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed loading storage pools: %w", err)
+	}
+
+	leaderInfo, err := s.LeaderInfo()
+	if err != nil {
+		return err
+	}
+
+	for _, poolName := range poolNames {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		schedule := pool.Driver().Config()["scrub.schedule"]
+		if schedule == "" || !snapshotIsScheduledNow(schedule, pool.ID()) {
+			continue
+		}
+
+		if pool.Driver().Info().Remote && !leaderInfo.Leader {
+			continue
+		}
+
+		opRun := func(ctx context.Context, op *operations.Operation) error {
+			return storagePoolScrub(ctx, s, pool, op)
+		}
+
+		args := operations.OperationArgs{
+			Type:      operationtype.StoragePoolScrub,
+			Class:     operationtype.OperationClassTask,
+			EntityURL: entity.StoragePoolURL(pool.Name()),
+			RunHook:   opRun,
+		}
+
+		op, err := operations.ScheduleServerOperation(s, args)
+		if err != nil {
+			logger.Error("Failed creating storage pool scrub operation", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		logger.Info("Scrubbing storage pool", logger.Ctx{"pool": poolName})
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Warn("Storage pool scrub failed", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+	}
+
+	return nil
+}
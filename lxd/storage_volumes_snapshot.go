@@ -52,6 +52,22 @@ var storagePoolVolumeSnapshotTypeCmd = APIEndpoint{
 	Put:    APIEndpointAction{Handler: storagePoolVolumeSnapshotTypePut, AccessHandler: storagePoolVolumeTypeAccessHandler(auth.EntitlementCanEdit)},
 }
 
+var storagePoolVolumeSnapshotDiffCmd = APIEndpoint{
+	Path:            "storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}/diff",
+	MetricsType:     entity.TypeStoragePool,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: storagePoolVolumeSnapshotDiffGet, AccessHandler: storagePoolVolumeTypeAccessHandler(auth.EntitlementCanView)},
+}
+
+var storagePoolVolumeSnapshotDiffExportCmd = APIEndpoint{
+	Path:            "storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}/diff/export",
+	MetricsType:     entity.TypeStoragePool,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: storagePoolVolumeSnapshotDiffExportGet, AccessHandler: storagePoolVolumeTypeAccessHandler(auth.EntitlementCanManageBackups)},
+}
+
 // swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots storage storage_pool_volumes_type_snapshots_post
 //
 //	Create a storage volume snapshot
@@ -672,6 +688,195 @@ func storagePoolVolumeSnapshotTypeGet(d *Daemon, r *http.Request) response.Respo
 	return response.SyncResponseETag(true, snapshot, etag)
 }
 
+// swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}/diff storage storage_pool_volumes_type_snapshot_diff_get
+//
+//	Get the changes between two storage volume snapshots
+//
+//	Lists the changed blocks (for block volumes) or changed files (for filesystem volumes)
+//	between an older snapshot and this snapshot.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: query
+//	    name: from
+//	    description: Name of the older snapshot to compare against
+//	    type: string
+//	    example: snap0
+//	responses:
+//	  "200":
+//	    description: Storage volume snapshot diff
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StorageVolumeSnapshotDiff"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeSnapshotDiffGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	details, err := request.GetContextValue[storageVolumeDetails](r.Context(), ctxStorageVolumeDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	fromSnapshot := request.QueryParam(r, "from")
+	if fromSnapshot == "" {
+		return response.BadRequest(errors.New("The older snapshot must be specified with the \"from\" parameter"))
+	}
+
+	// Forward if needed.
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
+	diff := api.StorageVolumeSnapshotDiff{
+		From:    fromSnapshot,
+		To:      details.snapshotName,
+		Changes: []api.StorageVolumeSnapshotChange{},
+	}
+
+	volType := storagePools.VolumeDBTypeToType(details.volumeType)
+	err = details.pool.DiffVolumeSnapshots(effectiveProjectName, volType, details.volumeName, fromSnapshot, details.snapshotName, func(change api.StorageVolumeSnapshotChange) error {
+		diff.Changes = append(diff.Changes, change)
+		return nil
+	}, nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var dbVolume *db.StorageVolume
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, details.pool.ID(), effectiveProjectName, details.volumeType, details.fullName, true)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	diff.ContentType = dbVolume.ContentType
+
+	return response.SyncResponse(true, diff)
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}/diff/export storage storage_pool_volumes_type_snapshot_diff_export_get
+//
+//	Export the changes between two storage volume snapshots
+//
+//	Downloads the data that changed between an older snapshot and this snapshot.
+//	For block volumes, each changed extent is sent as its offset and length (big endian 64bit
+//	integers) followed by its data. For filesystem volumes, a tarball of the added and modified
+//	files is sent.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: query
+//	    name: from
+//	    description: Name of the older snapshot to compare against
+//	    type: string
+//	    example: snap0
+//	responses:
+//	  "200":
+//	    description: Raw changed data
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeSnapshotDiffExportGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	details, err := request.GetContextValue[storageVolumeDetails](r.Context(), ctxStorageVolumeDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	fromSnapshot := request.QueryParam(r, "from")
+	if fromSnapshot == "" {
+		return response.BadRequest(errors.New("The older snapshot must be specified with the \"from\" parameter"))
+	}
+
+	// Forward if needed.
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
+	volType := storagePools.VolumeDBTypeToType(details.volumeType)
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s_%s-%s.diff", details.volumeName, fromSnapshot, details.snapshotName))
+
+		return details.pool.ExportVolumeSnapshotDiff(effectiveProjectName, volType, details.volumeName, fromSnapshot, details.snapshotName, w, nil)
+	})
+}
+
 // swagger:operation PUT /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName} storage storage_pool_volumes_type_snapshot_put
 //
 //	Update the storage volume snapshot
@@ -0,0 +1,897 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/lxd/auth"
+	backupConfig "github.com/canonical/lxd/lxd/backup/config"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var trashCmd = APIEndpoint{
+	Path:            "trash",
+	MetricsType:     entity.TypeProject,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: trashGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+}
+
+var trashEntryCmd = APIEndpoint{
+	Path:            "trash/{uuid}",
+	MetricsType:     entity.TypeProject,
+	ProjectSpecific: true,
+
+	Get:    APIEndpointAction{Handler: trashEntryGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanView)},
+	Post:   APIEndpointAction{Handler: trashEntryPost, AccessHandler: allowAuthenticated},
+	Delete: APIEndpointAction{Handler: trashEntryDelete, AccessHandler: allowAuthenticated},
+}
+
+// trashEntryURL returns the API URL of a trash entry.
+func trashEntryURL(projectName string, trashUUID string) *api.URL {
+	return api.NewURL().Path(version.APIVersion, "trash", trashUUID).Project(projectName)
+}
+
+// swagger:operation GET /1.0/trash trash trash_get
+//
+//	Get the trash entries
+//
+//	Returns a list of trash entries (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve trash entries from all projects
+//	    type: boolean
+//	    example: true
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/trash/5f0dbc3b-4e4f-4cdc-9f0f-17e1a9e3a5b1?project=default",
+//	              "/1.0/trash/0b5e3b0c-31e5-4d3c-9d8e-0a3f3cbe1a7e?project=default"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/trash?recursion=1 trash trash_get_recursion1
+//
+//	Get the trash entries
+//
+//	Returns a list of trash entries (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve trash entries from all projects
+//	    type: boolean
+//	    example: true
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of trash entries
+//	          items:
+//	            $ref: "#/definitions/TrashEntry"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func trashGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, allProjects, err := request.ProjectParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	recursion, _ := util.IsRecursionRequest(r)
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeProject)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	filter := dbCluster.TrashEntryFilter{}
+	customVolumeProjectName := projectName
+	if !allProjects {
+		filter.Project = &projectName
+
+		// Custom volumes of projects without their own storage volumes are trashed in the default project.
+		customVolumeProjectName, err = project.StorageVolumeProject(s.DB.Cluster, projectName, dbCluster.StoragePoolVolumeTypeCustom)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	var entries []dbCluster.TrashEntry
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		entries, err = dbCluster.GetTrashEntries(ctx, tx.Tx(), filter)
+		if err != nil {
+			return err
+		}
+
+		if customVolumeProjectName == projectName {
+			return nil
+		}
+
+		customEntries, err := dbCluster.GetTrashEntries(ctx, tx.Tx(), dbCluster.TrashEntryFilter{Project: &customVolumeProjectName})
+		if err != nil {
+			return err
+		}
+
+		entries = slices.DeleteFunc(entries, func(entry dbCluster.TrashEntry) bool {
+			return entry.Row.Type == api.TrashEntryTypeCustom
+		})
+
+		for _, entry := range customEntries {
+			if entry.Row.Type == api.TrashEntryTypeCustom {
+				entries = append(entries, entry)
+			}
+		}
+
+		slices.SortFunc(entries, func(a dbCluster.TrashEntry, b dbCluster.TrashEntry) int {
+			return a.Row.DeletionDate.Compare(b.Row.DeletionDate)
+		})
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	urls := make([]string, 0, len(entries))
+	apiEntries := make([]*api.TrashEntry, 0, len(entries))
+	for _, entry := range entries {
+		entryProjectName := entry.ProjectName
+		if !allProjects {
+			entryProjectName = projectName
+		}
+
+		if !userHasPermission(entity.ProjectURL(entryProjectName)) {
+			continue
+		}
+
+		urls = append(urls, trashEntryURL(entryProjectName, entry.Row.UUID).String())
+		apiEntries = append(apiEntries, entry.ToAPI())
+	}
+
+	if recursion == 0 {
+		return response.SyncResponse(true, urls)
+	}
+
+	return response.SyncResponse(true, apiEntries)
+}
+
+// trashEntryLoad loads the trash entry with the given UUID from the effective project of the requested project.
+// Custom volumes of projects without the "features.storage.volumes" feature are trashed in the default project.
+func trashEntryLoad(ctx context.Context, s *state.State, projectName string, trashUUID string) (*dbCluster.TrashEntry, error) {
+	var entry *dbCluster.TrashEntry
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		entry, err = dbCluster.GetTrashEntry(ctx, tx.Tx(), trashUUID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Row.Type == api.TrashEntryTypeCustom {
+		projectName, err = project.StorageVolumeProject(s.DB.Cluster, projectName, dbCluster.StoragePoolVolumeTypeCustom)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if entry.ProjectName != projectName {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Trash entry not found")
+	}
+
+	return entry, nil
+}
+
+// trashEntryCheckPermission checks that the caller is allowed to restore or purge the given trash entry.
+// This requires the permission to create the trashed entity in the requested project.
+func trashEntryCheckPermission(ctx context.Context, s *state.State, projectName string, entry *dbCluster.TrashEntry) error {
+	entitlement := auth.EntitlementCanCreateInstances
+	if entry.Row.Type == api.TrashEntryTypeCustom {
+		entitlement = auth.EntitlementCanCreateStorageVolumes
+	}
+
+	return s.Authorizer.CheckPermission(ctx, entity.ProjectURL(projectName), entitlement)
+}
+
+// swagger:operation GET /1.0/trash/{uuid} trash trash_entry_get
+//
+//	Get the trash entry
+//
+//	Gets a specific trash entry.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Trash entry
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/TrashEntry"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func trashEntryGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	entry, err := trashEntryLoad(r.Context(), s, request.ProjectParam(r), r.PathValue("uuid"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, entry.ToAPI())
+}
+
+// swagger:operation POST /1.0/trash/{uuid} trash trash_entry_post
+//
+//	Restore the trash entry
+//
+//	Restores the trashed instance or custom storage volume, optionally under a new name.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: entry
+//	    description: Restore request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/TrashEntryPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func trashEntryPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	entry, err := trashEntryLoad(r.Context(), s, projectName, r.PathValue("uuid"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = trashEntryCheckPermission(r.Context(), s, projectName, entry)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Volumes on local storage pools must be restored by the member holding them.
+	resp := forwardedResponseToNode(r.Context(), s, entry.NodeName)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.TrashEntryPost{}
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	name := req.Name
+	if name == "" {
+		name = entry.Row.Name
+	}
+
+	if entry.Row.Type == api.TrashEntryTypeCustom {
+		err = storageDrivers.ValidVolumeName(name)
+	} else {
+		err = instancetype.ValidName(name, false)
+	}
+
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		return trashEntryRestore(ctx, s, entry, name, op)
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   entity.ProjectURL(projectName),
+		Type:        operationtype.TrashRestore,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// swagger:operation DELETE /1.0/trash/{uuid} trash trash_entry_delete
+//
+//	Purge the trash entry
+//
+//	Permanently deletes the trashed instance or custom storage volume.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func trashEntryDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	entry, err := trashEntryLoad(r.Context(), s, projectName, r.PathValue("uuid"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = trashEntryCheckPermission(r.Context(), s, projectName, entry)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Volumes on local storage pools must be purged by the member holding them.
+	resp := forwardedResponseToNode(r.Context(), s, entry.NodeName)
+	if resp != nil {
+		return resp
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		return trashEntryPurge(ctx, s, entry, op)
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   entity.ProjectURL(projectName),
+		Type:        operationtype.TrashPurge,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// projectDeletionRetention returns the deletion retention configured on the project.
+// An empty string means that deleted instances and volumes are removed immediately.
+func projectDeletionRetention(ctx context.Context, s *state.State, projectName string) (string, error) {
+	var config map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		config, err = dbCluster.GetProjectConfig(ctx, tx.Tx(), projectName)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed loading project config: %w", err)
+	}
+
+	return config["deletion.retention"], nil
+}
+
+// trashVolume moves the volume described by the backup config into the trash of the project and records it so it
+// can be restored until the retention period expires. Returns a revert function moving the volume back.
+func trashVolume(ctx context.Context, s *state.State, pool storagePools.Pool, projectName string, entryType string, poolVol *backupConfig.Config, retention string, op *operations.Operation) (revert.Hook, error) {
+	revert := revert.New()
+	defer revert.Fail()
+
+	rootVol, err := poolVol.RootVolume()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting the root volume: %w", err)
+	}
+
+	deletionDate := time.Now().UTC()
+	expiryDate, err := shared.GetExpiry(deletionDate, retention)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid deletion retention %q: %w", retention, err)
+	}
+
+	data, err := yaml.Marshal(poolVol)
+	if err != nil {
+		return nil, fmt.Errorf("Failed encoding backup config: %w", err)
+	}
+
+	trashUUID := uuid.New().String()
+
+	err = pool.TrashVolume(projectName, poolVol, trashUUID, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed moving volume to the trash: %w", err)
+	}
+
+	revert.Add(func() { _ = pool.RestoreTrashedVolume(projectName, poolVol, trashUUID, nil) })
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectID, err := dbCluster.GetProjectID(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		row := dbCluster.TrashEntriesRow{
+			UUID:          trashUUID,
+			ProjectID:     projectID,
+			StoragePoolID: pool.ID(),
+			Type:          entryType,
+			Name:          rootVol.Name,
+			BackupConfig:  string(data),
+			DeletionDate:  deletionDate,
+			ExpiryDate:    expiryDate,
+		}
+
+		// Volumes on local storage pools can only be restored from the member holding them.
+		if !pool.Driver().Info().Remote {
+			nodeID := tx.GetNodeID()
+			row.NodeID = &nodeID
+		}
+
+		_, err = dbCluster.CreateTrashEntry(ctx, tx.Tx(), row)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed recording trash entry: %w", err)
+	}
+
+	revert.Add(func() {
+		_ = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteTrashEntry(ctx, tx.Tx(), trashUUID)
+		})
+	})
+
+	cleanup := revert.Clone().Fail
+	revert.Success()
+	return cleanup, nil
+}
+
+// instanceTrash moves a stopped instance into the trash of its project instead of deleting its volume.
+// The instance itself is then deleted as usual, which only removes its database records and local state as the
+// storage volume is no longer found under its name.
+func instanceTrash(ctx context.Context, s *state.State, inst instance.Instance, retention string, op *operations.Operation) error {
+	if shared.IsTrue(inst.ExpandedConfig()["security.protection.delete"]) {
+		return api.StatusErrorf(http.StatusBadRequest, "Instance is protected from being deleted")
+	}
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return err
+	}
+
+	// Wait for any pending file operations to complete so the volume can be renamed.
+	ct, ok := inst.(instance.Container)
+	if ok {
+		ct.StopForkFile(false)
+	}
+
+	poolVol, err := pool.GenerateInstanceBackupConfig(inst, true, nil, op)
+	if err != nil {
+		return fmt.Errorf("Failed generating instance backup config: %w", err)
+	}
+
+	cleanup, err := trashVolume(ctx, s, pool, inst.Project().Name, inst.Type().String(), poolVol, retention, op)
+	if err != nil {
+		return err
+	}
+
+	err = inst.Delete(ctx, false, "", op)
+	if err != nil {
+		cleanup()
+		return err
+	}
+
+	logger.Info("Moved instance to the trash", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "retention": retention})
+
+	return nil
+}
+
+// customVolumeTrash moves an unused custom volume into the trash of its project instead of deleting it.
+func customVolumeTrash(ctx context.Context, s *state.State, pool storagePools.Pool, projectName string, volName string, retention string, op *operations.Operation) error {
+	_, err := pool.UnmountCustomVolume(projectName, volName, op)
+	if err != nil {
+		return fmt.Errorf("Failed unmounting custom volume: %w", err)
+	}
+
+	poolVol, err := pool.GenerateCustomVolumeBackupConfig(projectName, volName, true, op)
+	if err != nil {
+		return fmt.Errorf("Failed generating custom volume backup config: %w", err)
+	}
+
+	cleanup, err := trashVolume(ctx, s, pool, projectName, api.TrashEntryTypeCustom, poolVol, retention, op)
+	if err != nil {
+		return err
+	}
+
+	err = pool.DeleteCustomVolume(ctx, projectName, volName, op)
+	if err != nil {
+		cleanup()
+		return err
+	}
+
+	logger.Info("Moved custom volume to the trash", logger.Ctx{"project": projectName, "pool": pool.Name(), "volume": volName, "retention": retention})
+
+	return nil
+}
+
+// trashEntryBackupConfig decodes the backup config stored in a trash entry.
+func trashEntryBackupConfig(entry *dbCluster.TrashEntry) (*backupConfig.Config, error) {
+	poolVol := &backupConfig.Config{}
+	err := yaml.Unmarshal([]byte(entry.Row.BackupConfig), poolVol)
+	if err != nil {
+		return nil, fmt.Errorf("Failed decoding backup config of trash entry %q: %w", entry.Row.UUID, err)
+	}
+
+	return poolVol, nil
+}
+
+// trashEntryRestore moves a trashed volume back under the given name and recreates its database records.
+func trashEntryRestore(ctx context.Context, s *state.State, entry *dbCluster.TrashEntry, name string, op *operations.Operation) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	poolVol, err := trashEntryBackupConfig(entry)
+	if err != nil {
+		return err
+	}
+
+	rootVol, err := poolVol.RootVolume()
+	if err != nil {
+		return fmt.Errorf("Failed getting the root volume: %w", err)
+	}
+
+	pool, err := storagePools.LoadByName(s, entry.StoragePoolName)
+	if err != nil {
+		return err
+	}
+
+	projectName := entry.ProjectName
+	rootVol.Name = name
+
+	var profiles []*api.Profile
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		if entry.Row.Type == api.TrashEntryTypeCustom {
+			_, err := tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, dbCluster.StoragePoolVolumeTypeCustom, name, true)
+			if err == nil {
+				return api.StatusErrorf(http.StatusConflict, "Storage volume %q already exists in project %q", name, projectName)
+			} else if !response.IsNotFoundError(err) {
+				return err
+			}
+
+			return nil
+		}
+
+		_, err := dbCluster.GetInstanceID(ctx, tx.Tx(), projectName, name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Instance %q already exists in project %q", name, projectName)
+		} else if !response.IsNotFoundError(err) {
+			return err
+		}
+
+		// Check that all profiles used by the instance and its snapshots still exist.
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		apiProject, err := dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		profileProjectName := project.ProfileProjectFromRecord(apiProject)
+
+		profileNames := slices.Clone(poolVol.Instance.Profiles)
+		for _, snap := range poolVol.Snapshots {
+			profileNames = append(profileNames, snap.Profiles...)
+		}
+
+		for _, profileName := range profileNames {
+			if slices.ContainsFunc(profiles, func(p *api.Profile) bool { return p.Name == profileName }) {
+				continue
+			}
+
+			dbProfile, err := dbCluster.GetProfile(ctx, tx.Tx(), profileProjectName, profileName)
+			if err != nil {
+				if response.IsNotFoundError(err) {
+					return api.StatusErrorf(http.StatusBadRequest, "Profile %q used by the instance no longer exists", profileName)
+				}
+
+				return err
+			}
+
+			profile, err := dbProfile.ToAPI(ctx, tx.Tx(), nil, nil)
+			if err != nil {
+				return err
+			}
+
+			profiles = append(profiles, profile)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if poolVol.Instance != nil {
+		poolVol.Instance.Name = name
+	}
+
+	err = pool.RestoreTrashedVolume(projectName, poolVol, entry.Row.UUID, op)
+	if err != nil {
+		return fmt.Errorf("Failed restoring volume from the trash: %w", err)
+	}
+
+	revert.Add(func() { _ = pool.TrashVolume(projectName, poolVol, entry.Row.UUID, nil) })
+
+	var cleanup func()
+	if entry.Row.Type == api.TrashEntryTypeCustom {
+		cleanup, err = pool.ImportCustomVolume(projectName, poolVol, op)
+	} else {
+		cleanup, err = internalRecoverInstance(ctx, s, pool, projectName, poolVol, profiles)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	revert.Add(cleanup)
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteTrashEntry(ctx, tx.Tx(), entry.Row.UUID)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed removing trash entry: %w", err)
+	}
+
+	revert.Success()
+
+	logger.Info("Restored from the trash", logger.Ctx{"project": projectName, "type": entry.Row.Type, "name": name})
+
+	return nil
+}
+
+// trashEntryPurge permanently deletes a trashed volume and removes its trash entry.
+func trashEntryPurge(ctx context.Context, s *state.State, entry *dbCluster.TrashEntry, op *operations.Operation) error {
+	poolVol, err := trashEntryBackupConfig(entry)
+	if err != nil {
+		return err
+	}
+
+	pool, err := storagePools.LoadByName(s, entry.StoragePoolName)
+	if err != nil {
+		return err
+	}
+
+	err = pool.PurgeTrashedVolume(entry.ProjectName, poolVol, entry.Row.UUID, op)
+	if err != nil {
+		return fmt.Errorf("Failed purging volume from the trash: %w", err)
+	}
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteTrashEntry(ctx, tx.Tx(), entry.Row.UUID)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed removing trash entry: %w", err)
+	}
+
+	logger.Info("Purged from the trash", logger.Ctx{"project": entry.ProjectName, "type": entry.Row.Type, "name": entry.Row.Name})
+
+	return nil
+}
+
+// pruneExpiredTrashTask returns a task that purges the trash entries whose retention period has expired.
+func pruneExpiredTrashTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		opRun := func(ctx context.Context, op *operations.Operation) error {
+			return pruneExpiredTrash(ctx, s, op)
+		}
+
+		args := operations.OperationArgs{
+			Type:    operationtype.TrashExpire,
+			Class:   operationtype.OperationClassTask,
+			RunHook: opRun,
+		}
+
+		op, err := operations.ScheduleServerOperation(s, args)
+		if err != nil {
+			logger.Error("Failed creating expired trash operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed purging expired trash", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	return f, task.Hourly()
+}
+
+// pruneExpiredTrash purges the expired trash entries held by this member.
+// Entries on remote storage pools are purged by the leader.
+func pruneExpiredTrash(ctx context.Context, s *state.State, op *operations.Operation) error {
+	leaderInfo, err := s.LeaderInfo()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	var entries []dbCluster.TrashEntry
+	var localNodeID int64
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		localNodeID = tx.GetNodeID()
+
+		entries, err = dbCluster.GetTrashEntries(ctx, tx.Tx(), dbCluster.TrashEntryFilter{ExpiredBefore: &now})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading expired trash entries: %w", err)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if entry.Row.NodeID == nil && leaderInfo.Clustered && !leaderInfo.Leader {
+			continue
+		}
+
+		if entry.Row.NodeID != nil && *entry.Row.NodeID != localNodeID {
+			continue
+		}
+
+		err := trashEntryPurge(ctx, s, &entry, op)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed purging %q from project %q: %w", entry.Row.Name, entry.ProjectName, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,163 @@
+package util
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// rateLimitMaxChunk is the largest amount of data passed through a rate limited reader or writer at once.
+const rateLimitMaxChunk = 32 * 1024
+
+// rateLimiter paces a transfer so that its average rate stays under a limit.
+type rateLimiter struct {
+	rate  int64 // bytes per second
+	start time.Time
+	count int64
+}
+
+// chunk returns how much data to transfer before pacing, so that transfers stay smooth with low limits.
+func (l *rateLimiter) chunk() int {
+	return int(min(max(l.rate/10, 1), rateLimitMaxChunk))
+}
+
+// wait records that n bytes were transferred and sleeps until the average rate is back under the limit.
+func (l *rateLimiter) wait(n int) {
+	time.Sleep(l.delay(n))
+}
+
+// delay records that n bytes were transferred and returns how long to wait for the average rate to be back under the
+// limit.
+func (l *rateLimiter) delay(n int) time.Duration {
+	now := time.Now()
+	if l.start.IsZero() {
+		l.start = now
+	}
+
+	l.count += int64(n)
+	expected := time.Duration(float64(l.count) / float64(l.rate) * float64(time.Second))
+	elapsed := now.Sub(l.start)
+	if expected > elapsed {
+		return expected - elapsed
+	}
+
+	// Don't let an idle transfer build up credit for a burst above the limit.
+	if elapsed-expected > time.Second {
+		l.start = now
+		l.count = 0
+	}
+
+	return 0
+}
+
+// pacer is implemented by the limiters pacing a rate limited reader or writer.
+type pacer interface {
+	chunk() int
+	wait(n int)
+}
+
+// RateLimiter paces several concurrent transfers so that their combined average rate stays under a limit.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limiter rateLimiter
+}
+
+// NewRateLimiter returns a RateLimiter sharing rate bytes per second between the transfers using it.
+// A rate of zero or less disables the limit, in which case nil is returned.
+func NewRateLimiter(rate int64) *RateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+
+	return &RateLimiter{limiter: rateLimiter{rate: rate}}
+}
+
+func (l *RateLimiter) chunk() int {
+	return l.limiter.chunk()
+}
+
+func (l *RateLimiter) wait(n int) {
+	l.mu.Lock()
+	delay := l.limiter.delay(n)
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+}
+
+type rateLimitedReader struct {
+	r       io.Reader
+	limiter pacer
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if len(p) > r.limiter.chunk() {
+		p = p[:r.limiter.chunk()]
+	}
+
+	n, err := r.r.Read(p)
+	r.limiter.wait(n)
+
+	return n, err
+}
+
+// RateLimitReader returns an io.Reader reading from r at no more than rate bytes per second.
+// A rate of zero or less disables the limit.
+func RateLimitReader(r io.Reader, rate int64) io.Reader {
+	if rate <= 0 {
+		return r
+	}
+
+	return &rateLimitedReader{r: r, limiter: &rateLimiter{rate: rate}}
+}
+
+// SharedRateLimitReader returns an io.Reader reading from r at the rate allowed by the given shared limiter.
+// A nil limiter disables the limit.
+func SharedRateLimitReader(r io.Reader, limiter *RateLimiter) io.Reader {
+	if limiter == nil {
+		return r
+	}
+
+	return &rateLimitedReader{r: r, limiter: limiter}
+}
+
+type rateLimitedReadWriteCloser struct {
+	io.ReadWriteCloser
+	limiter pacer
+}
+
+func (rwc *rateLimitedReadWriteCloser) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		end := min(written+rwc.limiter.chunk(), len(p))
+
+		n, err := rwc.ReadWriteCloser.Write(p[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		rwc.limiter.wait(n)
+	}
+
+	return written, nil
+}
+
+// RateLimitReadWriteCloser returns an io.ReadWriteCloser writing to rwc at no more than rate bytes per second.
+// Reads are not limited. A rate of zero or less disables the limit.
+func RateLimitReadWriteCloser(rwc io.ReadWriteCloser, rate int64) io.ReadWriteCloser {
+	if rate <= 0 {
+		return rwc
+	}
+
+	return &rateLimitedReadWriteCloser{ReadWriteCloser: rwc, limiter: &rateLimiter{rate: rate}}
+}
+
+// SharedRateLimitReadWriteCloser returns an io.ReadWriteCloser writing to rwc at the rate allowed by the given shared
+// limiter. Reads are not limited. A nil limiter disables the limit.
+func SharedRateLimitReadWriteCloser(rwc io.ReadWriteCloser, limiter *RateLimiter) io.ReadWriteCloser {
+	if limiter == nil {
+		return rwc
+	}
+
+	return &rateLimitedReadWriteCloser{ReadWriteCloser: rwc, limiter: limiter}
+}
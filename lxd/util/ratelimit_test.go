@@ -0,0 +1,103 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type nopReadWriteCloser struct {
+	bytes.Buffer
+}
+
+func (nopReadWriteCloser) Close() error {
+	return nil
+}
+
+func TestRateLimitReader(t *testing.T) {
+	data := strings.Repeat("x", 5000)
+
+	start := time.Now()
+	content, err := io.ReadAll(RateLimitReader(strings.NewReader(data), 10000))
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if string(content) != data {
+		t.Fatal("Rate limited reader altered the data")
+	}
+
+	// 5000 bytes at 10000 bytes per second take at least 500ms.
+	elapsed := time.Since(start)
+	if elapsed < 400*time.Millisecond {
+		t.Fatalf("Expected reading to take at least 400ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimitReadWriteCloser(t *testing.T) {
+	data := []byte(strings.Repeat("x", 5000))
+	buf := &nopReadWriteCloser{}
+
+	start := time.Now()
+	n, err := RateLimitReadWriteCloser(buf, 10000).Write(data)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if n != len(data) || !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("Rate limited writer altered the data")
+	}
+
+	elapsed := time.Since(start)
+	if elapsed < 400*time.Millisecond {
+		t.Fatalf("Expected writing to take at least 400ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	reader := strings.NewReader("hello")
+	if RateLimitReader(reader, 0) != io.Reader(reader) {
+		t.Fatal("Expected a zero rate to return the reader unchanged")
+	}
+
+	rwc := &nopReadWriteCloser{}
+	if RateLimitReadWriteCloser(rwc, -1) != io.ReadWriteCloser(rwc) {
+		t.Fatal("Expected a negative rate to return the connection unchanged")
+	}
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	data := strings.Repeat("x", 2500)
+	limiter := NewRateLimiter(10000)
+
+	// Two transfers of 2500 bytes sharing 10000 bytes per second take at least 500ms together.
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			content, err := io.ReadAll(SharedRateLimitReader(strings.NewReader(data), limiter))
+			if err != nil || string(content) != data {
+				t.Errorf("Shared rate limited reader failed: %v", err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	if elapsed < 400*time.Millisecond {
+		t.Fatalf("Expected reading to take at least 400ms, took %s", elapsed)
+	}
+
+	if NewRateLimiter(0) != nil {
+		t.Fatal("Expected a zero rate to disable the limiter")
+	}
+
+	reader := strings.NewReader("hello")
+	if SharedRateLimitReader(reader, nil) != io.Reader(reader) {
+		t.Fatal("Expected a nil limiter to return the reader unchanged")
+	}
+}
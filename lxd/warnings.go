@@ -32,7 +32,7 @@ var warningsCmd = APIEndpoint{
 	Path:        "warnings",
 	MetricsType: entity.TypeWarning,
 
-	Get: APIEndpointAction{Handler: warningsGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewWarnings)},
+	Get: APIEndpointAction{Handler: warningsGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewWarnings), ClusterLinkViews: true},
 }
 
 var warningCmd = APIEndpoint{
@@ -79,6 +79,16 @@ func filterWarnings(warnings []api.Warning, clauses *filter.ClauseSet) ([]api.Wa
 //      description: Project name
 //      type: string
 //      example: default
+//    - in: query
+//      name: cluster-link
+//      description: Retrieve entries from a linked cluster
+//      type: string
+//      example: lxd02
+//    - in: query
+//      name: all-links
+//      description: Retrieve entries from the local cluster and all linked clusters
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: Sync response
@@ -126,6 +136,16 @@ func filterWarnings(warnings []api.Warning, clauses *filter.ClauseSet) ([]api.Wa
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: cluster-link
+//	    description: Retrieve entries from a linked cluster
+//	    type: string
+//	    example: lxd02
+//	  - in: query
+//	    name: all-links
+//	    description: Retrieve entries from the local cluster and all linked clusters
+//	    type: boolean
+//	    example: true
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"time"
+)
+
+// AuditRecord is an entry of the audit log of a LXD server. Each mutating API call is recorded in the audit log of
+// the server that received it.
+//
+// swagger:model
+//
+// API extension: audit_log.
+type AuditRecord struct {
+	// Sequence number of the record in the audit log of the server
+	// Example: 42
+	Sequence int64 `json:"sequence" yaml:"sequence"`
+
+	// Time at which the API call was completed
+	// Example: 2026-10-18T18:07:36.123456789Z
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// Username of the caller (empty if the caller was not authenticated)
+	// Example: jane.doe@example.com
+	Username string `json:"username" yaml:"username"`
+
+	// Protocol used by the caller to authenticate (empty if the caller was not authenticated)
+	// Example: oidc
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// Address of the caller
+	// Example: 10.0.0.1:51234
+	SourceAddress string `json:"source_address" yaml:"source_address"`
+
+	// HTTP method of the API call
+	// Example: PATCH
+	Method string `json:"method" yaml:"method"`
+
+	// URL of the API call
+	// Example: /1.0/instances/c1/state?project=default
+	URL string `json:"url" yaml:"url"`
+
+	// URL of the entity targeted by the API call (empty if the API call does not target an entity)
+	// Example: /1.0/instances/c1?project=default
+	Entity string `json:"entity" yaml:"entity"`
+
+	// Hex encoded SHA-256 digest of the request body (empty if there is no request body)
+	// Example: 5d41402abc4b2a76b9719d911017c592ae2e1d7c6bc8f0b2f9a3bd7e6e1c9b3a
+	BodySHA256 string `json:"body_sha256" yaml:"body_sha256"`
+
+	// HTTP status code of the response
+	// Example: 202
+	StatusCode int `json:"status_code" yaml:"status_code"`
+
+	// URL of the operation created by the API call (empty if no operation was created)
+	// Example: /1.0/operations/b8d84888-1dc2-44fd-b386-7f679e171ba5
+	Operation string `json:"operation" yaml:"operation"`
+
+	// Sequence number of the last record removed by deleting rotated files (zero unless the record marks their removal)
+	// Example: 0
+	RemovedThrough int64 `json:"removed_through" yaml:"removed_through"`
+
+	// Hash of the previous record in the audit log (empty for the first record)
+	// Example: 0f0e7a0c3f4d2b1e8f0c9c7c6e2d1b0a9f8e7d6c5b4a39281706f5e4d3c2b1a0
+	PreviousHash string `json:"previous_hash" yaml:"previous_hash"`
+
+	// Keyed hash of the record, including the hash of the previous record
+	// Example: 8c7a6d5e4f3b2a1908f7e6d5c4b3a2918070f6e5d4c3b2a19080706f5e4d3c2b
+	Hash string `json:"hash" yaml:"hash"`
+}
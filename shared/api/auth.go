@@ -59,6 +59,9 @@ const (
 
 	// IdentityTypeCertificateClusterLinkPending represents cluster links for which a token has been issued but who have not yet authenticated with a linked LXD cluster.
 	IdentityTypeCertificateClusterLinkPending = "Cluster link certificate (pending)"
+
+	// IdentityTypeBearerTokenSCIM represents an identity that bears a LXD token that can be used to provision identities and groups via the SCIM API.
+	IdentityTypeBearerTokenSCIM = "SCIM token bearer"
 )
 
 // WithEntitlements is meant to be an embedded struct to API types eligible for entitlement enrichment,
@@ -175,6 +178,38 @@ type IdentityPut struct {
 	TLSCertificate string `json:"tls_certificate" yaml:"tls_certificate"`
 }
 
+// IdentityCertificatePost contains the new certificate of an identity that renews its own TLS client certificate.
+//
+// swagger:model
+//
+// API extension: certificate_lifecycle.
+type IdentityCertificatePost struct {
+	// The PEM encoded x509 certificate replacing the current certificate of the identity
+	Certificate string `json:"certificate" yaml:"certificate"`
+
+	// The nonce returned by GET /1.0/auth/identities/current/certificate
+	Nonce string `json:"nonce" yaml:"nonce"`
+
+	// Base64 encoded signature of the nonce, made with the private key of the new certificate using SHA-256
+	Signature string `json:"signature" yaml:"signature"`
+}
+
+// IdentityCertificateNonce contains a nonce that an identity must sign with the private key of its new certificate to
+// renew its own TLS client certificate.
+//
+// swagger:model
+//
+// API extension: certificate_lifecycle.
+type IdentityCertificateNonce struct {
+	// Nonce to sign with the private key of the new certificate
+	// Example: eyJhbGciOiJIUzUxMiIsInR5cCI6IkpXVCJ9...
+	Nonce string `json:"nonce" yaml:"nonce"`
+
+	// When the nonce expires
+	// Example: 2021-03-23T20:05:00-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
 // IdentitiesTLSPost contains required information for the creation of a TLS identity.
 //
 // swagger:model
@@ -237,6 +272,13 @@ type IdentityBearerToken struct {
 // API extension: auth_bearer_devlxd.
 type IdentityBearerTokenPost struct {
 	Expiry string `json:"expiry" yaml:"expiry"`
+
+	// Permissions narrow the token to the given permissions. A request made with the token is only allowed if
+	// it is allowed both by these permissions and by the groups of the identity. If empty, the token grants
+	// all the permissions of the identity.
+	//
+	// API extension: auth_bearer_scoped_tokens.
+	Permissions []Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
 }
 
 // AuthGroup is the type for a LXD group.
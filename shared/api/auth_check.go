@@ -0,0 +1,51 @@
+package api
+
+// AuthCheck is the result of checking whether an identity has an entitlement on an entity.
+//
+// swagger:model
+//
+// API extension: auth_check.
+type AuthCheck struct {
+	// Identity is the URL of the identity that was checked.
+	// Example: /1.0/auth/identities/oidc/jane.doe@example.com
+	Identity string `json:"identity" yaml:"identity"`
+
+	// EntityReference is the URL of the entity that was checked.
+	// Example: /1.0/instances/c1?project=default
+	EntityReference string `json:"url" yaml:"url"`
+
+	// Entitlement is the entitlement that was checked.
+	// Example: can_exec
+	Entitlement string `json:"entitlement" yaml:"entitlement"`
+
+	// Allowed is true if the identity has the entitlement on the entity.
+	// Example: true
+	Allowed bool `json:"allowed" yaml:"allowed"`
+
+	// Reason explains why access is allowed or denied.
+	// Example: Granted by group "ci-runners"
+	Reason string `json:"reason" yaml:"reason"`
+
+	// Grants are the groups of the identity that grant the entitlement on the entity.
+	Grants []AuthCheckGrant `json:"grants" yaml:"grants"`
+}
+
+// AuthCheckGrant describes how a group of an identity grants an entitlement on an entity.
+//
+// swagger:model
+//
+// API extension: auth_check.
+type AuthCheckGrant struct {
+	// Group is the name of the group.
+	// Example: ci-runners
+	Group string `json:"group" yaml:"group"`
+
+	// IdentityProviderGroups are the identity provider groups mapped to the group, via which the identity is a
+	// member of the group. It is empty if the identity is a direct member of the group.
+	// Example: ["ci"]
+	IdentityProviderGroups []string `json:"identity_provider_groups" yaml:"identity_provider_groups"`
+
+	// Permissions are the permissions of the group that grant the entitlement. These may apply to a parent of the
+	// entity, for example the "operator" entitlement on the project of an instance.
+	Permissions []Permission `json:"permissions" yaml:"permissions"`
+}
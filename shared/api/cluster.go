@@ -370,6 +370,12 @@ type ClusterGroup struct {
 	//
 	// API extension: clustering_groups_used_by
 	UsedBy []string `json:"used_by" yaml:"used_by"`
+
+	// Cluster group configuration map (refer to doc/reference/cluster_group_config.md)
+	// Example: {"scheduler.overcommit.memory": "1.5"}
+	//
+	// API extension: instance_placement_scheduler
+	Config map[string]string `json:"config" yaml:"config"`
 }
 
 // ClusterGroupPost represents the fields required to rename a cluster group.
@@ -396,6 +402,12 @@ type ClusterGroupPut struct {
 	// List of members in this group
 	// Example: ["node1", "node3"]
 	Members []string `json:"members" yaml:"members"`
+
+	// Cluster group configuration map (refer to doc/reference/cluster_group_config.md)
+	// Example: {"scheduler.overcommit.memory": "1.5"}
+	//
+	// API extension: instance_placement_scheduler
+	Config map[string]string `json:"config" yaml:"config"`
 }
 
 // Writable converts a full ClusterGroup struct into a ClusterGroupPut struct (filters read-only fields).
@@ -403,6 +415,7 @@ func (c *ClusterGroup) Writable() ClusterGroupPut {
 	return ClusterGroupPut{
 		Description: c.Description,
 		Members:     c.Members,
+		Config:      c.Config,
 	}
 }
 
@@ -410,6 +423,7 @@ func (c *ClusterGroup) Writable() ClusterGroupPut {
 func (c *ClusterGroup) SetWritable(put ClusterGroupPut) {
 	c.Description = put.Description
 	c.Members = put.Members
+	c.Config = put.Config
 }
 
 // ClusterLink represents high-level information about a cluster link.
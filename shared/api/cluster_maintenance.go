@@ -0,0 +1,128 @@
+package api
+
+const (
+	// ClusterMaintenanceActionPause pauses a cluster maintenance before the next step.
+	ClusterMaintenanceActionPause = "pause"
+
+	// ClusterMaintenanceActionResume resumes a paused cluster maintenance.
+	ClusterMaintenanceActionResume = "resume"
+
+	// ClusterMaintenanceActionAbort aborts a cluster maintenance.
+	ClusterMaintenanceActionAbort = "abort"
+
+	// ClusterMaintenanceActionReady signals that an evacuated cluster member is ready to be restored.
+	ClusterMaintenanceActionReady = "ready"
+)
+
+const (
+	// ClusterMaintenanceStatusRunning indicates that the cluster maintenance is in progress.
+	ClusterMaintenanceStatusRunning = "running"
+
+	// ClusterMaintenanceStatusPaused indicates that the cluster maintenance is paused, either on request or after a failure.
+	ClusterMaintenanceStatusPaused = "paused"
+
+	// ClusterMaintenanceStatusAborted indicates that the cluster maintenance was aborted.
+	ClusterMaintenanceStatusAborted = "aborted"
+
+	// ClusterMaintenanceStatusCompleted indicates that all cluster members went through maintenance.
+	ClusterMaintenanceStatusCompleted = "completed"
+
+	// ClusterMaintenanceStatusHandedOver indicates that the cluster maintenance continues on another cluster member.
+	ClusterMaintenanceStatusHandedOver = "handed-over"
+)
+
+const (
+	// ClusterMaintenanceMemberPending indicates that the cluster member hasn't gone through maintenance yet.
+	ClusterMaintenanceMemberPending = "pending"
+
+	// ClusterMaintenanceMemberEvacuating indicates that the cluster member is being evacuated.
+	ClusterMaintenanceMemberEvacuating = "evacuating"
+
+	// ClusterMaintenanceMemberWaiting indicates that the cluster member is evacuated and waiting to be ready.
+	ClusterMaintenanceMemberWaiting = "waiting"
+
+	// ClusterMaintenanceMemberRestoring indicates that the cluster member is being restored.
+	ClusterMaintenanceMemberRestoring = "restoring"
+
+	// ClusterMaintenanceMemberCompleted indicates that the cluster member was restored and is healthy.
+	ClusterMaintenanceMemberCompleted = "completed"
+)
+
+// ClusterMaintenancePost represents the fields required to start a rolling maintenance of cluster members.
+//
+// swagger:model
+//
+// API extension: cluster_maintenance.
+type ClusterMaintenancePost struct {
+	// Cluster members to go through maintenance, in order (all cluster members if empty)
+	// Example: ["server01", "server02"]
+	Members []string `json:"members" yaml:"members"`
+
+	// Override the configured evacuation mode of the instances
+	// Example: live-migrate
+	Mode string `json:"mode" yaml:"mode"`
+
+	// How long to wait for an evacuated cluster member to be reported ready before restoring it (in seconds, 0 to restore immediately)
+	// Example: 1800
+	ReadyTimeout int64 `json:"ready_timeout" yaml:"ready_timeout"`
+}
+
+// ClusterMaintenanceStatePost represents an action on a running cluster maintenance.
+//
+// swagger:model
+//
+// API extension: cluster_maintenance.
+type ClusterMaintenanceStatePost struct {
+	// The action to perform, one of "pause", "resume", "abort" or "ready"
+	// Example: ready
+	Action string `json:"action" yaml:"action"`
+
+	// Name of the cluster member that is ready to be restored (for the "ready" action)
+	// Example: server01
+	Member string `json:"member" yaml:"member"`
+}
+
+// ClusterMaintenance represents the progress of a rolling maintenance of cluster members.
+//
+// swagger:model
+//
+// API extension: cluster_maintenance.
+type ClusterMaintenance struct {
+	// Status of the cluster maintenance
+	// Example: running
+	Status string `json:"status" yaml:"status"`
+
+	// Reason why the cluster maintenance was paused after a failure
+	// Example: Failed evacuating cluster member "server01": Insufficient online voters to maintain quorum
+	Error string `json:"error" yaml:"error"`
+
+	// Cluster member coordinating the cluster maintenance (the new one once handed over)
+	// Example: server03
+	Location string `json:"location" yaml:"location"`
+
+	// Evacuation mode override
+	// Example: live-migrate
+	Mode string `json:"mode" yaml:"mode"`
+
+	// How long to wait for an evacuated cluster member to be reported ready (in seconds)
+	// Example: 1800
+	ReadyTimeout int64 `json:"ready_timeout" yaml:"ready_timeout"`
+
+	// Progress of the cluster members, in maintenance order
+	Members []ClusterMaintenanceMember `json:"members" yaml:"members"`
+}
+
+// ClusterMaintenanceMember represents the maintenance progress of a cluster member.
+//
+// swagger:model
+//
+// API extension: cluster_maintenance.
+type ClusterMaintenanceMember struct {
+	// Name of the cluster member
+	// Example: server01
+	Name string `json:"name" yaml:"name"`
+
+	// Maintenance status of the cluster member
+	// Example: waiting
+	Status string `json:"status" yaml:"status"`
+}
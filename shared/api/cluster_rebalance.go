@@ -0,0 +1,72 @@
+package api
+
+// ClusterRebalancePlan represents the instance moves needed to even out the load of cluster members.
+//
+// swagger:model
+//
+// API extension: cluster_instance_rebalance.
+type ClusterRebalancePlan struct {
+	// Load of the cluster members before the moves
+	Members []ClusterRebalanceMember `json:"members" yaml:"members"`
+
+	// Planned instance moves
+	Moves []ClusterRebalanceMove `json:"moves" yaml:"moves"`
+}
+
+// ClusterRebalanceMember represents the load of a cluster member.
+//
+// swagger:model
+//
+// API extension: cluster_instance_rebalance.
+type ClusterRebalanceMember struct {
+	// Name of the cluster member
+	// Example: server01
+	Name string `json:"name" yaml:"name"`
+
+	// Overall load score (percentage), the average of the CPU, memory and storage usage
+	// Example: 42.5
+	Score float64 `json:"score" yaml:"score"`
+
+	// CPU usage (percentage), based on the 5 minutes load average
+	// Example: 35.2
+	CPU float64 `json:"cpu" yaml:"cpu"`
+
+	// Memory usage (percentage)
+	// Example: 61.8
+	Memory float64 `json:"memory" yaml:"memory"`
+
+	// Usage of the fullest local storage pool (percentage)
+	// Example: 30.5
+	Storage float64 `json:"storage" yaml:"storage"`
+
+	// Number of instances on the cluster member
+	// Example: 12
+	Instances int `json:"instances" yaml:"instances"`
+}
+
+// ClusterRebalanceMove represents an instance move planned by the rebalancer.
+//
+// swagger:model
+//
+// API extension: cluster_instance_rebalance.
+type ClusterRebalanceMove struct {
+	// Name of the instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Cluster member the instance is moved from
+	// Example: server01
+	Source string `json:"source" yaml:"source"`
+
+	// Cluster member the instance is moved to
+	// Example: server02
+	Target string `json:"target" yaml:"target"`
+
+	// Whether the instance is live-migrated
+	// Example: true
+	Live bool `json:"live" yaml:"live"`
+}
@@ -12,8 +12,14 @@ const (
 	EventLifecycleClusterGroupDeleted               = "cluster-group-deleted"
 	EventLifecycleClusterGroupRenamed               = "cluster-group-renamed"
 	EventLifecycleClusterGroupUpdated               = "cluster-group-updated"
+	EventLifecycleClusterMaintenanceAborted         = "cluster-maintenance-aborted"
+	EventLifecycleClusterMaintenanceCompleted       = "cluster-maintenance-completed"
+	EventLifecycleClusterMaintenancePaused          = "cluster-maintenance-paused"
+	EventLifecycleClusterMaintenanceResumed         = "cluster-maintenance-resumed"
+	EventLifecycleClusterMaintenanceStarted         = "cluster-maintenance-started"
 	EventLifecycleClusterMemberAdded                = "cluster-member-added"
 	EventLifecycleClusterMemberEvacuated            = "cluster-member-evacuated"
+	EventLifecycleClusterMemberFenced               = "cluster-member-fenced"
 	EventLifecycleClusterMemberHealed               = "cluster-member-healed"
 	EventLifecycleClusterMemberRemoved              = "cluster-member-removed"
 	EventLifecycleClusterMemberRenamed              = "cluster-member-renamed"
@@ -25,6 +31,7 @@ const (
 	EventLifecycleClusterLinkUpdated                = "cluster-link-updated"
 	EventLifecycleReplicatorCreated                 = "replicator-created"
 	EventLifecycleReplicatorDeleted                 = "replicator-deleted"
+	EventLifecycleReplicatorFailover                = "replicator-failover"
 	EventLifecycleReplicatorRenamed                 = "replicator-renamed"
 	EventLifecycleReplicatorRun                     = "replicator-run"
 	EventLifecycleReplicatorUpdated                 = "replicator-updated"
@@ -110,12 +117,16 @@ const (
 	EventLifecycleStoragePoolCreated                = "storage-pool-created"
 	EventLifecycleStoragePoolDeleted                = "storage-pool-deleted"
 	EventLifecycleStoragePoolUpdated                = "storage-pool-updated"
+	EventLifecycleStoragePoolUsageWarning           = "storage-pool-usage-warning"
+	EventLifecycleStoragePoolUsageCritical          = "storage-pool-usage-critical"
 	EventLifecycleStorageBucketCreated              = "storage-bucket-created"
 	EventLifecycleStorageBucketUpdated              = "storage-bucket-updated"
 	EventLifecycleStorageBucketDeleted              = "storage-bucket-deleted"
 	EventLifecycleStorageBucketKeyCreated           = "storage-bucket-key-created"
 	EventLifecycleStorageBucketKeyUpdated           = "storage-bucket-key-updated"
 	EventLifecycleStorageBucketKeyDeleted           = "storage-bucket-key-deleted"
+	EventLifecycleStorageBucketObjectCreated        = "storage-bucket-object-created"
+	EventLifecycleStorageBucketObjectDeleted        = "storage-bucket-object-deleted"
 	EventLifecycleStorageVolumeCreated              = "storage-volume-created"
 	EventLifecycleStorageVolumeBackupCreated        = "storage-volume-backup-created"
 	EventLifecycleStorageVolumeBackupDeleted        = "storage-volume-backup-deleted"
@@ -154,6 +154,18 @@ type InstancePost struct {
 	//
 	// API extension: override_snapshot_profiles_on_copy
 	OverrideSnapshotProfiles bool `json:"override_snapshot_profiles" yaml:"override_snapshot_profiles"`
+
+	// Maximum rate in bytes per second at which the instance data is sent, 0 for no limit (migration only)
+	// Example: 12500000
+	//
+	// API extension: replicator_scope_and_limits
+	TransferRate int64 `json:"transfer_rate,omitempty" yaml:"transfer_rate,omitempty"`
+
+	// Name of the cluster link to move the instance to (migration only)
+	// Example: lxd02
+	//
+	// API extension: instance_move_cluster_link
+	ClusterLink string `json:"cluster_link,omitempty" yaml:"cluster_link,omitempty"`
 }
 
 // InstancePostTarget represents the migration target host and operation.
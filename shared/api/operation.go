@@ -29,6 +29,12 @@ const (
 	// MetadataOriginalEntityURL is set in operation metadata when renaming a resource.
 	// Callers are expected to set both MetadataOriginalEntityURL and MetadataEntityURL in operation metadata.
 	MetadataOriginalEntityURL = "original_entity_url"
+
+	// MetadataScheduler is set in operation metadata when creating an instance placed by a resource-aware scheduling strategy.
+	// It contains the reason for the placement of the instance.
+	//
+	// API extension: instance_placement_scheduler.
+	MetadataScheduler = "scheduler"
 )
 
 // Operation represents a LXD background operation
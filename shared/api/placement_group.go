@@ -18,6 +18,15 @@ const (
 	PlacementRigorPermissive string = "permissive"
 )
 
+const (
+	// PlacementScopeMember applies the placement policy to individual cluster members.
+	PlacementScopeMember string = "member"
+
+	// PlacementScopeFailureDomain applies the placement policy to cluster member failure domains.
+	// Cluster members without a failure domain belong to the default failure domain.
+	PlacementScopeFailureDomain string = "failure-domain"
+)
+
 // PlacementGroup represents a group of instances that should be scheduled.
 //
 // API extension: instance_placement_groups.
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 const (
 	// ReplicatorStatusPending represents a replicator that has never been run.
 	ReplicatorStatusPending = "Pending"
@@ -23,6 +27,38 @@ type ReplicatorState struct {
 	// Status of the replicator job.
 	// Example: Pending
 	Status string `json:"status" yaml:"status"`
+
+	// Replication lag (in seconds), the highest lag of all instances
+	// Example: 120
+	//
+	// API extension: replicator_continuous
+	Lag int64 `json:"lag" yaml:"lag"`
+
+	// Whether the lag exceeds the configured recovery point objective
+	// Example: false
+	//
+	// API extension: replicator_continuous
+	RPOBreached bool `json:"rpo_breached" yaml:"rpo_breached"`
+
+	// Replication state of each instance in the project
+	//
+	// API extension: replicator_continuous
+	Instances map[string]ReplicatorInstanceState `json:"instances" yaml:"instances"`
+}
+
+// ReplicatorInstanceState represents the replication state of an instance.
+//
+// swagger:model
+//
+// API extension: replicator_continuous.
+type ReplicatorInstanceState struct {
+	// Time at which the last successful replication of the instance started (zero if never replicated)
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastReplicatedAt time.Time `json:"last_replicated_at" yaml:"last_replicated_at"`
+
+	// Replication lag (in seconds), zero if the replica is up to date
+	// Example: 120
+	Lag int64 `json:"lag" yaml:"lag"`
 }
 
 // ReplicatorStatePut represents the fields available to change the state of a replicator.
@@ -31,7 +67,7 @@ type ReplicatorState struct {
 //
 // API extension: replicators.
 type ReplicatorStatePut struct {
-	// Action to perform on the replicator (start, restore).
+	// Action to perform on the replicator (start, restore, failover, failback, test-failover or test-failover-cleanup).
 	// Example: start
 	Action string `json:"action" yaml:"action"`
 }
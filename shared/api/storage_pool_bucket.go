@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // StorageBucketsPost represents the fields of a new LXD storage pool bucket
 //
 // swagger:model
@@ -213,3 +217,31 @@ func (b *StorageBucketKey) SetWritable(put StorageBucketKeyPut) {
 	b.AccessKey = put.AccessKey
 	b.SecretKey = put.SecretKey
 }
+
+// StorageBucketObject represents an object stored in a LXD storage pool bucket
+//
+// swagger:model
+//
+// API extension: storage_bucket_objects.
+type StorageBucketObject struct {
+	// Object name
+	// Example: backups/db.tar.gz
+	Name string `json:"name" yaml:"name"`
+
+	// Object size in bytes
+	// Example: 1048576
+	Size int64 `json:"size" yaml:"size"`
+
+	// Entity tag of the object
+	// Example: "9b2cf535f27731c974343645a3985328"
+	ETag string `json:"etag" yaml:"etag"`
+
+	// When the object was last modified
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastModified time.Time `json:"last_modified" yaml:"last_modified"`
+}
+
+// URL returns the URL for the bucket object.
+func (o *StorageBucketObject) URL(apiVersion string, poolName string, projectName string, bucketName string) *URL {
+	return NewURL().Path(apiVersion, "storage-pools", poolName, "buckets", bucketName, "objects", o.Name).Project(projectName)
+}
@@ -75,6 +75,12 @@ type StorageVolumePost struct {
 	//
 	// API extension: cluster_internal_custom_volume_copy
 	Source StorageVolumeSource `json:"source" yaml:"source"`
+
+	// Maximum rate in bytes per second at which the volume data is sent, 0 for no limit (migration only)
+	// Example: 12500000
+	//
+	// API extension: replicator_scope_and_limits
+	TransferRate int64 `json:"transfer_rate,omitempty" yaml:"transfer_rate,omitempty"`
 }
 
 // StorageVolumePostTarget represents the migration target host and operation
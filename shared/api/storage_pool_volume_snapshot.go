@@ -113,3 +113,58 @@ func (storageVolumeSnapshot *StorageVolumeSnapshot) SetWritable(put StorageVolum
 	storageVolumeSnapshot.Description = put.Description
 	storageVolumeSnapshot.ExpiresAt = put.ExpiresAt
 }
+
+// StorageVolumeSnapshotChangeAdded indicates a file that didn't exist in the older snapshot.
+const StorageVolumeSnapshotChangeAdded = "added"
+
+// StorageVolumeSnapshotChangeModified indicates a file or block range that differs between snapshots.
+const StorageVolumeSnapshotChangeModified = "modified"
+
+// StorageVolumeSnapshotChangeDeleted indicates a file that doesn't exist in the newer snapshot.
+const StorageVolumeSnapshotChangeDeleted = "deleted"
+
+// StorageVolumeSnapshotChange represents a single change between two snapshots of a storage volume.
+// Changes of filesystem volumes are reported per file and changes of block volumes per byte range.
+//
+// swagger:model
+//
+// API extension: storage_volume_snapshot_diff.
+type StorageVolumeSnapshotChange struct {
+	// Type of change (added, modified or deleted)
+	// Example: modified
+	Type string `json:"type" yaml:"type"`
+
+	// Path of the changed file relative to the volume root (filesystem volumes only)
+	// Example: etc/hostname
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Offset of the changed range in bytes (block volumes only)
+	// Example: 1048576
+	Offset int64 `json:"offset" yaml:"offset"`
+
+	// Length of the changed range in bytes (block volumes only)
+	// Example: 65536
+	Length int64 `json:"length" yaml:"length"`
+}
+
+// StorageVolumeSnapshotDiff represents the changes between two snapshots of a storage volume.
+//
+// swagger:model
+//
+// API extension: storage_volume_snapshot_diff.
+type StorageVolumeSnapshotDiff struct {
+	// Name of the older snapshot
+	// Example: snap0
+	From string `json:"from" yaml:"from"`
+
+	// Name of the newer snapshot
+	// Example: snap1
+	To string `json:"to" yaml:"to"`
+
+	// The content type (filesystem or block)
+	// Example: block
+	ContentType string `json:"content_type" yaml:"content_type"`
+
+	// List of changes
+	Changes []StorageVolumeSnapshotChange `json:"changes" yaml:"changes"`
+}
@@ -1,5 +1,20 @@
 package api
 
+import (
+	"time"
+)
+
+const (
+	// StorageVolumeCheckStatusOK indicates that no integrity issue was found on the volume.
+	StorageVolumeCheckStatusOK = "ok"
+
+	// StorageVolumeCheckStatusFailed indicates that the integrity check of the volume found issues.
+	StorageVolumeCheckStatusFailed = "failed"
+
+	// StorageVolumeCheckStatusSkipped indicates that the volume couldn't be checked (in use or not supported).
+	StorageVolumeCheckStatusSkipped = "skipped"
+)
+
 // StorageVolumeState represents the live state of the volume
 //
 // swagger:model
@@ -8,6 +23,11 @@ package api
 type StorageVolumeState struct {
 	// Volume usage
 	Usage *StorageVolumeStateUsage `json:"usage" yaml:"usage"`
+
+	// Result of the last integrity check of the volume
+	//
+	// API extension: storage_volume_check
+	Check *StorageVolumeStateCheck `json:"check" yaml:"check"`
 }
 
 // StorageVolumeStateUsage represents the disk usage of a volume
@@ -26,3 +46,22 @@ type StorageVolumeStateUsage struct {
 	// API extension: storage_volume_state_total
 	Total int64 `json:"total" yaml:"total"`
 }
+
+// StorageVolumeStateCheck represents the result of the last integrity check of a volume
+//
+// swagger:model
+//
+// API extension: storage_volume_check.
+type StorageVolumeStateCheck struct {
+	// Check result (ok, failed or skipped)
+	// Example: ok
+	Status string `json:"status" yaml:"status"`
+
+	// Details about the issues found or why the check was skipped
+	// Example: Filesystem check of "/dev/vg0/custom_default_vol1" failed
+	Message string `json:"message" yaml:"message"`
+
+	// When the volume was checked
+	// Example: 2025-03-23T17:38:37.753398689-04:00
+	CheckedAt time.Time `json:"checked_at" yaml:"checked_at"`
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"time"
+)
+
+const (
+	// TrashEntryTypeContainer indicates a trashed container.
+	TrashEntryTypeContainer = "container"
+
+	// TrashEntryTypeVirtualMachine indicates a trashed virtual machine.
+	TrashEntryTypeVirtualMachine = "virtual-machine"
+
+	// TrashEntryTypeCustom indicates a trashed custom storage volume.
+	TrashEntryTypeCustom = "custom"
+)
+
+// TrashEntry represents an instance or custom storage volume that was deleted from a project
+// with a deletion retention period and can still be restored.
+//
+// swagger:model
+//
+// API extension: trash.
+type TrashEntry struct {
+	// Unique identifier of the trash entry.
+	// Example: 5f0dbc3b-4e4f-4cdc-9f0f-17e1a9e3a5b1
+	UUID string `json:"uuid" yaml:"uuid"`
+
+	// Original name of the deleted instance or storage volume.
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Type of the deleted entity (container, virtual-machine or custom).
+	// Example: container
+	Type string `json:"type" yaml:"type"`
+
+	// Project the entity was deleted from.
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Storage pool holding the trashed volume.
+	// Example: local
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Cluster member holding the trashed volume (empty for remote storage pools).
+	// Example: lxd01
+	Location string `json:"location" yaml:"location"`
+
+	// When the entity was deleted.
+	// Example: 2025-03-23T17:38:37.753398689-04:00
+	DeletedAt time.Time `json:"deleted_at" yaml:"deleted_at"`
+
+	// When the trashed volume will be purged.
+	// Example: 2025-03-30T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// TrashEntryPost represents the fields required to restore a trash entry.
+//
+// swagger:model
+//
+// API extension: trash.
+type TrashEntryPost struct {
+	// Name to restore the entity as (defaults to its original name).
+	// Example: c1-restored
+	Name string `json:"name" yaml:"name"`
+}
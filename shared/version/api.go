@@ -500,6 +500,32 @@ var APIExtensions = []string{
 	"operation_child_count",
 	"storage_driver_powerstore_nvme",
 	"access_management_expiry",
+	"storage_volume_limits",
+	"storage_volume_snapshot_diff",
+	"storage_pool_usage_thresholds",
+	"trash",
+	"storage_volume_check",
+	"storage_bucket_objects",
+	"storage_bucket_policies",
+	"cluster_instance_rebalance",
+	"replicator_continuous",
+	"replicator_failover",
+	"replicator_scope_and_limits",
+	"cluster_link_views",
+	"instance_move_cluster_link",
+	"placement_group_affinity",
+	"instance_placement_scheduler",
+	"cluster_maintenance",
+	"cluster_database_backup",
+	"cluster_fencing",
+	"auth_bearer_scoped_tokens",
+	"auth_check",
+	"auth_scim",
+	"audit_log",
+	"oidc_additional_issuers",
+	"oidc_groups_mappings",
+	"certificate_lifecycle",
+	"acme_dns_challenge",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
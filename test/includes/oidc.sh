@@ -26,7 +26,10 @@ kill_oidc() {
   rm -f "${TEST_DIR}/oidc.user"
 }
 
+# set_oidc sets the username, email address and, optionally, the email verification status ("verified") of the user
+# that mini-oidc authenticates.
 set_oidc() {
   echo "${1}
-${2:-}" > "${TEST_DIR}/oidc.user"
+${2:-}
+${3:-}" > "${TEST_DIR}/oidc.user"
 }
@@ -24,6 +24,8 @@ readonly test_group_cluster=(
     "clustering_dns"
     "clustering_fan"
     "clustering_recover"
+    "clustering_fencing"
+    "clustering_database_backup"
     "clustering_ha"
     "clustering_handover"
     "clustering_rebalance"
@@ -34,6 +36,7 @@ readonly test_group_cluster=(
     "clustering_evacuation"
     "clustering_evacuation_quorum_force"
     "clustering_evacuation_restore_operations"
+    "clustering_maintenance"
     "clustering_move"
     "clustering_remove_members"
     "clustering_autotarget"
@@ -51,6 +54,8 @@ readonly test_group_cluster=(
     "clustering_project_limits"
     "clustering_link_auth"
     "clustering_link_info"
+    "clustering_link_views"
+    "clustering_link_instance_move"
     "clustering_image_proxy_bypass"
     "clustering_link_unidirectional"
     "clustering_acme"
@@ -69,8 +74,11 @@ readonly test_group_cluster_storage=(
 readonly test_group_replicator_storage=(
     "clustering_replicator_basic"
     "clustering_replicator_scheduled"
+    "clustering_replicator_continuous"
     "clustering_replicator_dr"
+    "clustering_replicator_failover"
     "clustering_replicator_snapshot"
+    "clustering_replicator_scope"
     "clustering_replicator_multi_member"
     "clustering_replicator_evacuated_member"
     "clustering_replicator_vm"
@@ -161,6 +169,7 @@ readonly test_group_standalone=(
     "basic_usage"
     "basic_version"
     "certificate_edit"
+    "certificate_renew"
     "certificate_project_restrictions"
     "completions"
     "config_edit"
@@ -202,6 +211,7 @@ readonly test_group_standalone=(
     "resources"
     "resources_bcache"
     "security"
+    "security_audit_log"
     "security_events"
     "security_events_bearer_authn"
     "security_authz_events"
@@ -288,6 +298,11 @@ readonly test_group_standalone_storage=(
     "storage_profiles"
     "storage_volume_attach"
     "storage_volume_attach_vm"
+    "storage_volume_limits"
+    "storage_volume_snapshot_diff"
+    "storage_pool_usage"
+    "trash"
+    "storage_volume_check"
     "storage_driver_btrfs"
     "storage_driver_ceph"
     "storage_driver_cephfs"
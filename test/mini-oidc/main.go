@@ -74,7 +74,7 @@ func main() {
 }
 
 func userCodeHandler(storage *storage.Storage, r *http.Request) {
-	name, _ := usernameAndEmail()
+	name, _, _ := usernameAndEmail()
 
 	err := r.ParseForm()
 	if err != nil {
@@ -99,22 +99,25 @@ func userCodeHandler(storage *storage.Storage, r *http.Request) {
 	fmt.Printf("%s => %s\n", userCode, name)
 }
 
-func usernameAndEmail() (username string, email string) {
+func usernameAndEmail() (username string, email string, emailVerified bool) {
 	f, err := os.Open(os.Args[2])
 	if err != nil {
-		return "", ""
+		return "", "", false
 	}
 
 	scanner := bufio.NewScanner(f)
-	for i := 0; i < 2 && scanner.Scan(); i++ {
-		if i == 0 {
+	for i := 0; i < 3 && scanner.Scan(); i++ {
+		switch i {
+		case 0:
 			username = scanner.Text()
-		} else {
+		case 1:
 			email = scanner.Text()
+		default:
+			emailVerified = scanner.Text() == "verified"
 		}
 	}
 
-	return username, email
+	return username, email, emailVerified
 }
 
 type userStore struct{}
@@ -126,22 +129,24 @@ func (u userStore) ExampleClientID() string {
 
 // GetUserByID returns a user by ID.
 func (u userStore) GetUserByID(string) *storage.User {
-	name, email := usernameAndEmail()
+	name, email, emailVerified := usernameAndEmail()
 
 	return &storage.User{
-		ID:       name,
-		Username: name,
-		Email:    email,
+		ID:            name,
+		Username:      name,
+		Email:         email,
+		EmailVerified: emailVerified,
 	}
 }
 
 // GetUserByUsername returns a user by username.
 func (u userStore) GetUserByUsername(string) *storage.User {
-	name, email := usernameAndEmail()
+	name, email, emailVerified := usernameAndEmail()
 
 	return &storage.User{
-		ID:       name,
-		Username: name,
-		Email:    email,
+		ID:            name,
+		Username:      name,
+		Email:         email,
+		EmailVerified: emailVerified,
 	}
 }
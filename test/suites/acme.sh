@@ -24,6 +24,16 @@ test_acme() {
   sub_test "Clear ACME configuration"
   lxc config set acme.agree_tos= acme.ca_url="" acme.domain="" acme.email=""
 
+  sub_test "Verify ACME challenge configuration"
+  ! lxc config set acme.challenge=TLS-ALPN-01 || false
+  lxc network zone create "acme$$.example.net"
+  lxc config set acme.challenge=DNS-01 acme.dns.zone="acme$$.example.net"
+  [ "$(lxc config get acme.challenge)" = "DNS-01" ]
+  lxc config unset acme.challenge
+  lxc config unset acme.dns.zone
+  [ "$(lxc config get acme.challenge)" = "" ]
+  lxc network zone delete "acme$$.example.net"
+
   # Cleanup.
   kill_acme
 
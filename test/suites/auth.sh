@@ -311,6 +311,107 @@ fine_grained: true"
 
   lxc auth identity delete bearer/tmp
 
+  # Ensure bearer tokens can be narrowed to a subset of the permissions of the identity.
+  lxc project create scoped-a
+  lxc project create scoped-b
+  lxc auth group create scoped-group
+  lxc auth group permission add scoped-group project scoped-a can_view
+  lxc auth group permission add scoped-group project scoped-b can_view
+  lxc auth identity create bearer/scoped --group scoped-group
+
+  # Invalid permissions are rejected.
+  ! lxc auth identity token issue bearer/scoped --permission "project not-found can_view" || false # Entity not found
+  ! lxc auth identity token issue bearer/scoped --permission "project scoped-a can_exec" || false # Invalid entitlement
+  ! lxc auth identity token issue bearer/scoped --permission "project" || false # Missing entitlement
+
+  # The token may only view project "scoped-a". It may not edit project "scoped-b" either, as the identity cannot.
+  scoped_bearer_identity_token="$(lxc auth identity token issue bearer/scoped --quiet --permission "project scoped-a can_view" --permission "project scoped-b can_edit")"
+  curl -s -k -H "Authorization: Bearer ${scoped_bearer_identity_token}" "https://${LXD_ADDR}/1.0/projects/scoped-a" | jq --exit-status '.metadata.name == "scoped-a"'
+  curl -s -k -H "Authorization: Bearer ${scoped_bearer_identity_token}" "https://${LXD_ADDR}/1.0/projects/scoped-b" | jq --exit-status '.error_code == 404'
+  curl -s -k -H "Authorization: Bearer ${scoped_bearer_identity_token}" "https://${LXD_ADDR}/1.0/projects?recursion=1" | jq --exit-status '[.metadata[].name] == ["scoped-a"]'
+  curl -s -k -X PATCH -d '{"description": "scoped"}' -H "Authorization: Bearer ${scoped_bearer_identity_token}" "https://${LXD_ADDR}/1.0/projects/scoped-a" | jq --exit-status '.error_code == 403'
+
+  # Issuing a token without permissions grants all the permissions of the identity again.
+  scoped_bearer_identity_token="$(lxc auth identity token issue bearer/scoped --quiet)"
+  curl -s -k -H "Authorization: Bearer ${scoped_bearer_identity_token}" "https://${LXD_ADDR}/1.0/projects/scoped-b" | jq --exit-status '.metadata.name == "scoped-b"'
+
+  lxc auth identity delete bearer/scoped
+  lxc auth group delete scoped-group
+  lxc project delete scoped-a
+  lxc project delete scoped-b
+
+  # Ensure authorization decisions can be explained.
+  lxc project create check-a
+  lxc auth group create check-group
+  lxc auth group permission add check-group project check-a operator
+  lxc auth identity create bearer/check --group check-group
+
+  # Invalid arguments are rejected.
+  ! lxc auth check bearer/not-found project check-a can_view || false # Identity not found
+  ! lxc auth check bearer/check project check-a not_an_entitlement || false # Invalid entitlement
+  ! lxc query "/1.0/auth/check?identity=/1.0/auth/identities/bearer/check&entity=/1.0/projects/check-a" || false # Missing entitlement
+
+  # The operator entitlement on the project grants viewing the project.
+  lxc auth check bearer/check project check-a can_view | grep -xF "allowed: true"
+  lxc query "/1.0/auth/check?identity=/1.0/auth/identities/bearer/check&entity=/1.0/projects/check-a&entitlement=can_view" | jq --exit-status '.grants[0].group == "check-group" and .grants[0].permissions[0].entitlement == "operator" and .grants[0].identity_provider_groups == []'
+
+  # Nothing grants editing project "default".
+  lxc auth check bearer/check project default can_edit | grep -xF "allowed: false"
+  lxc query "/1.0/auth/check?identity=/1.0/auth/identities/bearer/check&entity=/1.0/projects/default&entitlement=can_edit" | jq --exit-status '.grants == []'
+
+  # Identities can always view themselves.
+  lxc query "/1.0/auth/check?identity=/1.0/auth/identities/bearer/check&entity=/1.0/auth/identities/bearer/check&entitlement=can_view" | jq --exit-status '.allowed == true and .grants == []'
+
+  lxc auth identity delete bearer/check
+  lxc auth group delete check-group
+  lxc project delete check-a
+
+  # Ensure identities and identity provider groups can be provisioned via SCIM.
+  lxc auth identity create scim/idp
+  ! lxc auth identity create scim/grouped --group not-found || false # SCIM identities cannot be added to groups
+  scim_token="$(lxc auth identity token issue scim/idp --quiet)"
+  scim_curl() {
+    curl -s -k -H "Authorization: Bearer ${scim_token}" -H "Content-Type: application/scim+json" "$@"
+  }
+
+  # The SCIM token cannot be used for the main API, and other identities cannot use the SCIM API.
+  curl -s -k -H "Authorization: Bearer ${scim_token}" "https://${LXD_ADDR}/1.0/projects" | jq --exit-status '.error_code == 403'
+  [ "$(curl -s -k -o /dev/null -w "%{http_code}" "https://${LXD_ADDR}/scim/v2/Users")" = "403" ]
+
+  scim_curl "https://${LXD_ADDR}/scim/v2/ServiceProviderConfig" | jq --exit-status '.patch.supported == true'
+
+  # Provision a user and a group.
+  scim_user_id="$(scim_curl -X POST "https://${LXD_ADDR}/scim/v2/Users" --data '{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"scim-user@example.com","externalId":"00u1","name":{"givenName":"Scim","familyName":"User"},"active":true}' | jq --exit-status --raw-output '.id')"
+  scim_curl -X POST "https://${LXD_ADDR}/scim/v2/Users" --data '{"userName":"scim-user@example.com"}' | jq --exit-status '.status == "409" and .scimType == "uniqueness"'
+  scim_curl -X POST "https://${LXD_ADDR}/scim/v2/Users" --data '{"userName":"not-an-email"}' | jq --exit-status '.status == "400"'
+  lxc auth identity show oidc/scim-user@example.com | grep -xF "name: Scim User"
+
+  scim_group_id="$(scim_curl -X POST "https://${LXD_ADDR}/scim/v2/Groups" --data '{"displayName":"scim-group","members":[{"value":"'"${scim_user_id}"'"}]}' | jq --exit-status --raw-output '.id')"
+  lxc auth identity-provider-group show scim-group
+  scim_curl "https://${LXD_ADDR}/scim/v2/Users/${scim_user_id}" | jq --exit-status '.groups[0].display == "scim-group" and .active == true'
+
+  # Users and groups can be found by filter.
+  scim_curl "https://${LXD_ADDR}/scim/v2/Users?filter=userName%20eq%20%22SCIM-USER@example.com%22" | jq --exit-status '.totalResults == 1 and .Resources[0].externalId == "00u1"'
+  scim_curl "https://${LXD_ADDR}/scim/v2/Groups?filter=displayName%20eq%20%22not-found%22" | jq --exit-status '.totalResults == 0'
+  scim_curl "https://${LXD_ADDR}/scim/v2/Users?filter=active%20eq%20true" | jq --exit-status '.scimType == "invalidFilter"'
+
+  # Rename the group and remove its member.
+  scim_curl -X PATCH "https://${LXD_ADDR}/scim/v2/Groups/${scim_group_id}" --data '{"Operations":[{"op":"replace","path":"displayName","value":"scim-group-renamed"},{"op":"remove","path":"members[value eq \"'"${scim_user_id}"'\"]"}]}' | jq --exit-status '.displayName == "scim-group-renamed" and .members == []'
+  lxc auth identity-provider-group show scim-group-renamed
+  scim_curl "https://${LXD_ADDR}/scim/v2/Users/${scim_user_id}" | jq --exit-status '.groups == null'
+
+  # Deactivate the user.
+  scim_curl -X PATCH "https://${LXD_ADDR}/scim/v2/Users/${scim_user_id}" --data '{"Operations":[{"op":"replace","value":{"active":"False"}}]}' | jq --exit-status '.active == false'
+
+  # Delete the user and the group.
+  [ "$(scim_curl -o /dev/null -w "%{http_code}" -X DELETE "https://${LXD_ADDR}/scim/v2/Users/${scim_user_id}")" = "204" ]
+  ! lxc auth identity show oidc/scim-user@example.com || false
+  [ "$(scim_curl -o /dev/null -w "%{http_code}" -X DELETE "https://${LXD_ADDR}/scim/v2/Groups/${scim_group_id}")" = "204" ]
+  ! lxc auth identity-provider-group show scim-group-renamed || false
+  scim_curl "https://${LXD_ADDR}/scim/v2/Groups/${scim_group_id}" | jq --exit-status '.status == "404"'
+
+  lxc auth identity delete scim/idp
+
   # Ensure DevLXD token cannot be to authenticate with main LXD API.
   lxc auth identity create devlxd/tmp
   devlxd_identity_token="$(lxc auth identity token issue devlxd/tmp --quiet)"
@@ -1472,6 +1472,84 @@ test_clustering_heal_networks_stop() {
   kill_lxd "${LXD_THREE_DIR}"
 }
 
+test_clustering_fencing() {
+  spawn_lxd_and_bootstrap_cluster
+
+  local cert
+  cert="$(cert_to_yaml "${LXD_ONE_DIR}/cluster.crt")"
+
+  # Spawn a second node
+  spawn_lxd_and_join_cluster "${cert}" 2 1 "${LXD_ONE_DIR}"
+
+  # Spawn a third node
+  spawn_lxd_and_join_cluster "${cert}" 3 1 "${LXD_ONE_DIR}"
+
+  echo "Invalid fencing configuration"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.fencing.command=relative/path || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.fencing.timeout=0 || false
+
+  echo "Configure a fence command failing until allowed and a power command stand-in"
+  local fenceDir="${TEST_DIR}/fencing"
+  mkdir -p "${fenceDir}"
+
+  cat > "${fenceDir}/fence" << EOF
+#!/bin/sh
+[ -e "${fenceDir}/allow" ] || exit 1
+echo "\$@" >> "${fenceDir}/fenced"
+EOF
+
+  cat > "${fenceDir}/power" << EOF
+#!/bin/sh
+case "\$1" in
+  off) echo "\$2" >> "${fenceDir}/powered-off" ;;
+  status) grep -qxF "\$2" "${fenceDir}/powered-off" 2>/dev/null && echo off || echo on ;;
+esac
+EOF
+
+  chmod +x "${fenceDir}/fence" "${fenceDir}/power"
+  LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.fencing.command="${fenceDir}/fence" cluster.fencing.power_command="${fenceDir}/power" cluster.fencing.timeout=30
+
+  echo "Set offline threshold and enable cluster healing"
+  LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.offline_threshold 11
+  LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.healing_threshold 11
+
+  echo "Kill node2 (a non-leader member)"
+  # XXX: intentionally not using `kill_go_proc` helper as we want abrupt termination (sacrificing some coverage data)
+  kill -9 "$(< "${LXD_TWO_DIR}/lxd.pid")"
+
+  echo "Wait for node2 to be marked offline"
+  sleep 11
+
+  echo "Healing fails as long as fencing isn't confirmed"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST --raw --wait /internal/testing/cluster/heal || false
+  [ "$(< "${fenceDir}/powered-off")" = "node2" ]
+  [ ! -e "${fenceDir}/fenced" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/members/node2 | jq --exit-status '.status == "Offline"'
+
+  echo "Healing succeeds once fencing is confirmed"
+  touch "${fenceDir}/allow"
+  LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST --raw --wait /internal/testing/cluster/heal
+  [ "$(< "${fenceDir}/fenced")" = "node2 100.64.1.102:8443" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/members/node2 | jq --exit-status '.status == "Evacuated"'
+
+  echo "Clean up"
+  rm -rf "${fenceDir}"
+
+  LXD_DIR="${LXD_THREE_DIR}" lxd shutdown
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+
+  rm -f "${LXD_THREE_DIR}/unix.socket"
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_THREE_DIR}"
+}
+
 # Perform an upgrade of a 2-member cluster, then a join a third member and
 # perform one more upgrade
 test_clustering_upgrade() {
@@ -2727,6 +2805,81 @@ test_clustering_recover() {
   kill_lxd "${LXD_THREE_DIR}"
 }
 
+test_clustering_database_backup() {
+  spawn_lxd_and_bootstrap_cluster
+
+  local cert
+  cert="$(cert_to_yaml "${LXD_ONE_DIR}/cluster.crt")"
+
+  # Spawn a second node
+  spawn_lxd_and_join_cluster "${cert}" 2 1 "${LXD_ONE_DIR}"
+
+  # Spawn a third node
+  spawn_lxd_and_join_cluster "${cert}" 3 1 "${LXD_ONE_DIR}"
+
+  # Invalid backup configuration
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.database_backup.schedule=invalid || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.database_backup.retention=0 || false
+  LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.database_backup.schedule=@daily cluster.database_backup.retention=3
+
+  # Take a backup after creating a project.
+  LXD_DIR="${LXD_ONE_DIR}" lxc project create p1
+
+  local backup
+  backup="$(LXD_DIR="${LXD_ONE_DIR}" lxd cluster backup-db)"
+  [ "$(dirname "${backup}")" = "${LXD_ONE_DIR}/database-backups" ]
+  [ -f "${backup}" ]
+
+  # Changes made after the backup are lost when restoring it.
+  LXD_DIR="${LXD_ONE_DIR}" lxc project create p2
+
+  # Restoring a backup requires the daemon to be stopped.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxd cluster restore-db -q "${backup}" || false
+
+  # Shutdown all nodes.
+  LXD_DIR="${LXD_THREE_DIR}" lxd shutdown
+  LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+
+  # Invalid backups are rejected.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxd cluster restore-db -q "${LXD_ONE_DIR}/database-backups/missing.tar.gz" || false
+  echo "not a backup" > "${TEST_DIR}/invalid.tar.gz"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxd cluster restore-db -q "${TEST_DIR}/invalid.tar.gz" || false
+  rm "${TEST_DIR}/invalid.tar.gz"
+
+  # Restore the backup on the first node and restart it.
+  LXD_DIR="${LXD_ONE_DIR}" lxd cluster restore-db -q "${backup}"
+  [ -f "${LXD_ONE_DIR}/database/restore.global.sql" ]
+  respawn_lxd_cluster_member "${ns1}" "${LXD_ONE_DIR}"
+
+  # The restored database doesn't contain the changes made after the backup.
+  [ ! -f "${LXD_ONE_DIR}/database/restore.global.sql" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc project list | grep -wF p1
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc project list | grep -wF p2 || false
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc config get cluster.database_backup.retention)" = "3" ]
+
+  # The database nodes have been updated
+  LXD_DIR="${LXD_ONE_DIR}" lxd cluster list-database | grep -F "100.64.1.101:8443"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxd cluster list-database | grep -F "100.64.1.102:8443" || false
+
+  # Cleanup the dead nodes.
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster remove node2 --force --yes
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster remove node3 --force --yes
+
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+
+  rm -f "${LXD_THREE_DIR}/unix.socket"
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_THREE_DIR}"
+}
+
 # Putting HAproxy in front of a cluster allows to use a single address to access
 # the cluster, filter out some bogus/spam/malicious requests without terminating
 # TLS and while preserving the original client IP addresses.
@@ -4486,6 +4639,35 @@ EOF
   # Clean up.
   LXD_DIR="${LXD_ONE_DIR}" lxc delete c1 c2 --project foo
   LXD_DIR="${LXD_ONE_DIR}" lxc project delete foo
+
+  sub_test "Resource-aware instance scheduler"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.scheduler.strategy foo || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query -X PATCH -d '{"config": {"scheduler.overcommit.cpu": "0"}}' /1.0/cluster/groups/default || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query -X PATCH -d '{"config": {"scheduler.foo": "1"}}' /1.0/cluster/groups/default || false
+  LXD_DIR="${LXD_ONE_DIR}" lxc query -X PATCH -d '{"config": {"scheduler.overcommit.cpu": "4", "scheduler.overcommit.memory": "1.5"}}' /1.0/cluster/groups/default
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/groups/default | jq --exit-status '.config["scheduler.overcommit.memory"] == "1.5"'
+
+  for strategy in balanced binpack; do
+    LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.scheduler.strategy "${strategy}"
+    op="$(LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST -d '{"name": "c1", "source": {"type": "none"}, "config": {"limits.cpu": "1", "limits.memory": "16MiB"}}' /1.0/instances)"
+    echo "${op}" | jq --exit-status --arg strategy "\"${strategy}\" strategy" '.metadata.scheduler | contains($strategy)'
+    LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/operations/$(echo "${op}" | jq --raw-output '.id')/wait"
+
+    # The reason is not persisted in the instance configuration.
+    ! LXD_DIR="${LXD_ONE_DIR}" lxc config show c1 | grep -F volatile.scheduler || false
+
+    # Instances that don't fit on any cluster member cannot be scheduled.
+    ! LXD_DIR="${LXD_ONE_DIR}" lxc init --empty c2 -c limits.memory=1PiB || false
+    LXD_DIR="${LXD_ONE_DIR}" lxc delete c1
+  done
+
+  # The instances strategy doesn't report a placement reason.
+  LXD_DIR="${LXD_ONE_DIR}" lxc config unset cluster.scheduler.strategy
+  op="$(LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST -d '{"name": "c1", "source": {"type": "none"}}' /1.0/instances)"
+  echo "${op}" | jq --exit-status '.metadata.scheduler == null'
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/operations/$(echo "${op}" | jq --raw-output '.id')/wait"
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete c1
+  LXD_DIR="${LXD_ONE_DIR}" lxc query -X PUT -d "$(LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/groups/default | jq '.config = {}')" /1.0/cluster/groups/default
   LXD_DIR="${LXD_ONE_DIR}" lxc remote rm cluster
 
   LXD_DIR="${LXD_THREE_DIR}" lxd shutdown
@@ -5317,6 +5499,57 @@ test_clustering_placement_groups() {
   # Clean up
   LXD_DIR="${LXD_ONE_DIR}" lxc placement-group delete pg-invalid-test
 
+  echo "==> Test placement rules: invalid values"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-invalid policy=spread rigor=strict scope=rack || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-invalid policy=spread rigor=strict limits.instances_per_member=0 || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-invalid policy=spread rigor=strict selector=limits.cpu=1 || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-invalid policy=spread rigor=strict selector=user.app || false
+
+  echo "==> Test placement rules: spread across failure domains"
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster failure-domain set node1 az1
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster failure-domain set node2 az1
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster failure-domain set node3 az2
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster failure-domain set node4 az2
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster failure-domain set node5 az3
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-fd policy=spread rigor=strict scope=failure-domain
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty d1 -c placement.group=pg-fd
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty d2 -c placement.group=pg-fd
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty d3 -c placement.group=pg-fd
+  domains="$(for inst in d1 d2 d3; do LXD_DIR="${LXD_ONE_DIR}" lxc cluster failure-domain get "$(LXD_DIR="${LXD_ONE_DIR}" lxc list "${inst}" -f csv -c L)"; done | sort -u | wc -l)"
+  [ "${domains}" = "3" ]
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc init --empty d4 -c placement.group=pg-fd || false
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete d3
+
+  echo "==> Test placement rules: anti-affinity"
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-anti policy=spread rigor=strict anti_affinity=pg-fd
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty r1 -c placement.group=pg-anti
+  node_r1="$(LXD_DIR="${LXD_ONE_DIR}" lxc list r1 -f csv -c L)"
+  [ "${node_r1}" != "$(LXD_DIR="${LXD_ONE_DIR}" lxc list d1 -f csv -c L)" ]
+  [ "${node_r1}" != "$(LXD_DIR="${LXD_ONE_DIR}" lxc list d2 -f csv -c L)" ]
+
+  echo "==> Test placement rules: affinity"
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-aff policy=compact rigor=strict affinity=pg-anti
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty a1 -c placement.group=pg-aff
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list a1 -f csv -c L)" = "${node_r1}" ]
+
+  echo "==> Test placement rules: maximum instances per member"
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-max policy=compact rigor=permissive limits.instances_per_member=1
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty m1 -c placement.group=pg-max
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty m2 -c placement.group=pg-max
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list m1 -f csv -c L)" != "$(LXD_DIR="${LXD_ONE_DIR}" lxc list m2 -f csv -c L)" ]
+
+  echo "==> Test placement rules: selector"
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-sel policy=spread rigor=strict selector=user.app=foo
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty s1 -c user.app=foo
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty s2 -c user.app=foo
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list s1 -f csv -c L)" != "$(LXD_DIR="${LXD_ONE_DIR}" lxc list s2 -f csv -c L)" ]
+
+  # Clean up
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete d1 d2 r1 a1 m1 m2 s1 s2
+  for pg in pg-fd pg-anti pg-aff pg-max pg-sel; do
+    LXD_DIR="${LXD_ONE_DIR}" lxc placement-group delete "${pg}"
+  done
+
   echo "==> Test placement group rename"
   LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-old policy=spread rigor=strict
   LXD_DIR="${LXD_ONE_DIR}" lxc placement-group rename pg-old pg-new
@@ -5796,6 +6029,157 @@ test_clustering_link_info() {
   kill_lxd "${LXD_ONE_DIR}"
 }
 
+test_clustering_link_views() {
+  # Create two standalone clustered LXD daemons to simulate two separate clusters.
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_ONE_DIR}" true
+
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_TWO_DIR}" true
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster enable node1
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster enable node2
+
+  # Only allow the cluster link identity of ONE to view the default project of TWO.
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group create link-viewers
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add link-viewers project default viewer
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add link-viewers server can_view_warnings
+  LXD_TWO_TRUST_TOKEN="$(LXD_DIR="${LXD_TWO_DIR}" lxc cluster link create lxd_one --quiet --auth-group link-viewers)"
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster link create lxd_two --token "${LXD_TWO_TRUST_TOKEN}"
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty c1
+  LXD_DIR="${LXD_TWO_DIR}" lxc init --empty c2
+  LXD_DIR="${LXD_TWO_DIR}" lxc project create other
+  LXD_DIR="${LXD_TWO_DIR}" lxc profile device add default root disk path=/ pool="lxdtest-$(basename "${LXD_TWO_DIR}")" --project other
+  LXD_DIR="${LXD_TWO_DIR}" lxc init --empty c3 --project other
+
+  sub_test "Query a single linked cluster"
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances?cluster-link=lxd_two" | jq --exit-status '. == ["/1.0/instances/c2?cluster-link=lxd_two"]'
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances?recursion=1&cluster-link=lxd_two" | jq --exit-status 'length == 1 and .[0].name == "c2" and .[0].location == "lxd_two:node2"'
+  LXD_DIR="${LXD_ONE_DIR}" lxc list --cluster-link lxd_two -f csv -c nL | grep -xF 'c2,lxd_two:node2'
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc list --cluster-link lxd_two -f csv -c n | grep -F c1 || false
+
+  # The linked cluster only returns what the cluster link identity is allowed to view.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances?cluster-link=lxd_two&project=other" || false
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/networks?recursion=1&cluster-link=lxd_two" | jq --exit-status 'all(.[]; all(.locations[]; startswith("lxd_two")))'
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/storage-volumes?cluster-link=lxd_two"
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/warnings?cluster-link=lxd_two"
+
+  sub_test "Query all linked clusters"
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances?recursion=1&all-links=true" | jq --exit-status '[.[] | .name + "@" + .location] == ["c1@node1", "c2@lxd_two:node2"]'
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list --all-links -f csv -c nL)" = "c1,node1
+c2,lxd_two:node2" ]
+
+  sub_test "Check cluster link query permissions"
+
+  # A fine-grained identity needs can_query on the cluster link, and must be allowed to run the query locally.
+  LXD_ONE_ADDR="$(< "${LXD_ONE_DIR}/lxd.addr")"
+  LXD_CONF="${TEST_DIR}" gen_cert_and_key "link-user"
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group create link-users
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add link-users project default viewer
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add link-users cluster_link lxd_two can_view
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth identity create tls/link-user "${TEST_DIR}/link-user.crt" --group link-users
+  LXD_CONF="${TEST_DIR}" CERTNAME="link-user" my_curl "https://${LXD_ONE_ADDR}/1.0/instances?cluster-link=lxd_two" | jq --exit-status '.error_code == 403'
+  LXD_CONF="${TEST_DIR}" CERTNAME="link-user" my_curl "https://${LXD_ONE_ADDR}/1.0/instances?all-links=true" | jq --exit-status '.metadata == ["/1.0/instances/c1"]'
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add link-users cluster_link lxd_two can_query
+  LXD_CONF="${TEST_DIR}" CERTNAME="link-user" my_curl "https://${LXD_ONE_ADDR}/1.0/instances?cluster-link=lxd_two" | jq --exit-status '.metadata == ["/1.0/instances/c2?cluster-link=lxd_two"]'
+
+  # The cluster link identity may view warnings on TWO, but the caller cannot view warnings on ONE.
+  LXD_CONF="${TEST_DIR}" CERTNAME="link-user" my_curl "https://${LXD_ONE_ADDR}/1.0/warnings?cluster-link=lxd_two" | jq --exit-status '.error_code == 403'
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth identity delete tls/link-user
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group delete link-users
+
+  sub_test "Check invalid cluster link queries"
+
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances?cluster-link=unknown" || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances?cluster-link=lxd_two&all-links=true" || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/profiles?cluster-link=lxd_two" || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc list --cluster-link lxd_two --all-links || false
+
+  # Linked clusters that are down are skipped when querying all links.
+  LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances?cluster-link=lxd_two" || false
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances?all-links=true" | jq --exit-status '. == ["/1.0/instances/c1"]'
+
+  # Cleanup.
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete c1
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster link delete lxd_two
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_ONE_DIR}"
+}
+
+test_clustering_link_instance_move() {
+  # Create two standalone clustered LXD daemons to simulate two separate clusters.
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_ONE_DIR}" true
+
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_TWO_DIR}" true
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster enable node1
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster enable node2
+
+  # Allow the cluster link identity of ONE to create instances in the "target" project of TWO.
+  LXD_DIR="${LXD_TWO_DIR}" lxc project create target -c features.profiles=false -c features.images=false
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group create link-operators
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add link-operators project target operator
+  LXD_TWO_TRUST_TOKEN="$(LXD_DIR="${LXD_TWO_DIR}" lxc cluster link create lxd_one --quiet --auth-group link-operators)"
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster link create lxd_two --token "${LXD_TWO_TRUST_TOKEN}"
+
+  LXD_DIR="${LXD_ONE_DIR}" ensure_import_testimage
+  LXD_DIR="${LXD_ONE_DIR}" lxc init testimage c1 -d "${SMALL_ROOT_DISK}" -c user.foo=bar
+  LXD_DIR="${LXD_ONE_DIR}" lxc snapshot c1
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch testimage c2 -d "${SMALL_ROOT_DISK}"
+
+  sub_test "Check invalid cluster link moves"
+
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc move c1 --cluster-link unknown --target-project target || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc move c1 --cluster-link lxd_two --target node1 --target-project target || false
+
+  # The cluster link identity is not allowed to create instances in the default project of TWO.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc move c1 --cluster-link lxd_two --storage "lxdtest-$(basename "${LXD_TWO_DIR}")" || false
+  LXD_DIR="${LXD_ONE_DIR}" lxc info c1
+
+  # A fine-grained identity must be able to edit the cluster link, delete the instance, and create instances in a local
+  # project with the same name as the target project.
+  LXD_ONE_ADDR="$(< "${LXD_ONE_DIR}/lxd.addr")"
+  LXD_CONF="${TEST_DIR}" gen_cert_and_key "link-user"
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group create link-users
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add link-users project default operator
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add link-users cluster_link lxd_two can_view
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth identity create tls/link-user "${TEST_DIR}/link-user.crt" --group link-users
+  LXD_CONF="${TEST_DIR}" CERTNAME="link-user" my_curl "https://${LXD_ONE_ADDR}/1.0/instances/c1" -X POST -H 'Content-Type: application/json' --data '{"migration": true, "cluster_link": "lxd_two", "project": "target"}' | jq --exit-status '.error_code == 403'
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add link-users cluster_link lxd_two can_edit
+  LXD_CONF="${TEST_DIR}" CERTNAME="link-user" my_curl "https://${LXD_ONE_ADDR}/1.0/instances/c1" -X POST -H 'Content-Type: application/json' --data '{"migration": true, "cluster_link": "lxd_two", "project": "target"}' | jq --exit-status '.error_code == 404'
+  LXD_DIR="${LXD_ONE_DIR}" lxc info c1
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth identity delete tls/link-user
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group delete link-users
+
+  # Running containers cannot be moved between clusters.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc move c2 --cluster-link lxd_two --target-project target || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST -d '{"migration": true, "live": false, "cluster_link": "lxd_two", "project": "target"}' /1.0/instances/c2 || false
+  LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c ns c2 | grep -xF 'c2,RUNNING'
+
+  sub_test "Move an instance through a cluster link"
+
+  # The root disk is moved to the storage pool of TWO.
+  LXD_DIR="${LXD_ONE_DIR}" lxc move c1 c1-moved --cluster-link lxd_two --target-project target --storage "lxdtest-$(basename "${LXD_TWO_DIR}")" -c user.moved=true
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc info c1 || false
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project target -f csv -c nsS | grep -xF 'c1-moved,STOPPED,1'
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc config get c1-moved user.foo --project target)" = "bar" ]
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc config get c1-moved user.moved --project target)" = "true" ]
+  LXD_DIR="${LXD_TWO_DIR}" lxc start c1-moved --project target
+
+  # Cleanup.
+  LXD_DIR="${LXD_TWO_DIR}" lxc delete -f c1-moved --project target
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete -f c2
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster link delete lxd_two
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_ONE_DIR}"
+}
+
 test_clustering_replicator_basic() {
   # Create two standalone clustered LXD daemons to simulate two separate clusters.
   LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
@@ -6082,6 +6466,104 @@ test_clustering_replicator_scheduled() {
   kill_lxd "${LXD_ONE_DIR}"
 }
 
+test_clustering_replicator_continuous() {
+  # Create two standalone clustered LXD daemons to simulate two separate clusters.
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_ONE_DIR}" true
+
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_TWO_DIR}" true
+
+  # Enable clustering on both.
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster enable node1
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster enable node2
+
+  # Create projects on both clusters.
+  LXD_DIR="${LXD_ONE_DIR}" lxc project create replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc project create replicator-project
+
+  # Setup auth groups and cluster links.
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group create replicator-group
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add replicator-group project replicator-project operator
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add replicator-group project replicator-project can_edit
+  LXD_ONE_TRUST_TOKEN="$(LXD_DIR="${LXD_ONE_DIR}" lxc cluster link create lxd_two --quiet --auth-group replicator-group)"
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group create replicator-group
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add replicator-group project replicator-project operator
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add replicator-group project replicator-project can_edit
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster link create lxd_one --token "${LXD_ONE_TRUST_TOKEN}" --auth-group replicator-group
+
+  # Configure replica project settings: standby sets replica.cluster, leader creates replicator.
+  LXD_DIR="${LXD_TWO_DIR}" lxc project set replicator-project replica.cluster=lxd_one
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator create my-replicator cluster=lxd_two --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc project demote-replica replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc project promote-replica replicator-project
+
+  # Setup storage on both clusters.
+  local pool_one pool_two
+  pool_one="lxdtest-$(basename "${LXD_ONE_DIR}")"
+  pool_two="lxdtest-$(basename "${LXD_TWO_DIR}")"
+  LXD_DIR="${LXD_ONE_DIR}" lxc profile device add default root disk path="/" pool="${pool_one}" --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc profile device add default root disk path="/" pool="${pool_two}" --project replicator-project
+
+  # One running and one stopped instance.
+  LXD_DIR="${LXD_ONE_DIR}" ensure_import_testimage replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch testimage c1 --project replicator-project -d "${SMALL_ROOT_DISK}"
+  LXD_DIR="${LXD_ONE_DIR}" lxc init testimage c2 --project replicator-project -d "${SMALL_ROOT_DISK}"
+
+  sub_test "Check configuration validation"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator continuous=foo --project replicator-project || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator rpo=foo --project replicator-project || false
+
+  # Nothing was replicated yet, so the lag of both instances is the time since they were created.
+  LXD_DIR="${LXD_ONE_DIR}" lxc query '/1.0/replicators/my-replicator/state?project=replicator-project' | jq --exit-status '.instances | length == 2 and all(.[]; .last_replicated_at == "0001-01-01T00:00:00Z")'
+
+  # Instances that were never replicated have no replication lag metric.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/metrics | grep -F 'lxd_replicator_lag_seconds{' || false
+
+  sub_test "Continuous runs replicate all instances"
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator continuous=true rpo=1H --project replicator-project
+
+  local i
+  for i in $(seq 60); do
+    LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c n | grep -xF c2 && break
+    sleep 1
+  done
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc list c1 --project replicator-project -f csv -c n | grep -xF c1
+  LXD_DIR="${LXD_TWO_DIR}" lxc list c2 --project replicator-project -f csv -c n | grep -xF c2
+
+  sub_test "Stopped instances stop being replicated once up to date"
+  LXD_DIR="${LXD_ONE_DIR}" lxc stop c1 --force --project replicator-project
+
+  # The next run replicates the now stopped c1, after which both replicas are up to date.
+  for i in $(seq 60); do
+    LXD_DIR="${LXD_ONE_DIR}" lxc query '/1.0/replicators/my-replicator/state?project=replicator-project' | jq --exit-status '.lag == 0' && break
+    sleep 1
+  done
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc query '/1.0/replicators/my-replicator/state?project=replicator-project' | jq --exit-status '.lag == 0 and .rpo_breached == false and all(.instances[]; .lag == 0 and .last_replicated_at != "0001-01-01T00:00:00Z")'
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator info my-replicator --project replicator-project | grep -xF 'RPO: 1H (met)'
+
+  # No new run is started while nothing changes.
+  sleep 2
+  local ops_before
+  ops_before="$(LXD_DIR="${LXD_ONE_DIR}" lxc query -X GET '/1.0/operations?project=replicator-project&recursion=2' | jq --exit-status '[.. | objects | select(.description == "Running replicator")] | length')"
+  sleep 12
+  LXD_DIR="${LXD_ONE_DIR}" lxc query -X GET '/1.0/operations?project=replicator-project&recursion=2' \
+    | jq --exit-status --argjson before "${ops_before}" '[.. | objects | select(.description == "Running replicator")] | length == $before'
+
+  sub_test "Replication lag metrics"
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/metrics | grep -F 'lxd_replicator_lag_seconds{name="c1",project="replicator-project",replicator="my-replicator"} 0'
+
+  # Cleanup
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator unset my-replicator continuous --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc profile device remove default root --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc profile device remove default root --project replicator-project
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_ONE_DIR}"
+}
+
 test_clustering_replicator_dr() {
   # Create two standalone clustered LXD daemons to simulate two separate clusters.
   LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
@@ -6236,6 +6718,125 @@ test_clustering_replicator_dr() {
   kill_lxd "${LXD_ONE_DIR}"
 }
 
+test_clustering_replicator_failover() {
+  # Create two standalone clustered LXD daemons to simulate two separate clusters.
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_ONE_DIR}" true
+
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_TWO_DIR}" true
+
+  # Enable clustering on both.
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster enable node1
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster enable node2
+
+  # Create projects on both clusters.
+  LXD_DIR="${LXD_ONE_DIR}" lxc project create replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc project create replicator-project
+
+  # Setup auth groups and cluster links.
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group create replicator-group
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add replicator-group project replicator-project operator
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add replicator-group project replicator-project can_edit
+  LXD_ONE_TRUST_TOKEN="$(LXD_DIR="${LXD_ONE_DIR}" lxc cluster link create lxd_two --quiet --auth-group replicator-group)"
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group create replicator-group
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add replicator-group project replicator-project operator
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add replicator-group project replicator-project can_edit
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster link create lxd_one --token "${LXD_ONE_TRUST_TOKEN}" --auth-group replicator-group
+
+  # Both sides get a replicator pointing at the other cluster so replication can run in either direction.
+  LXD_DIR="${LXD_TWO_DIR}" lxc project set replicator-project replica.cluster=lxd_one
+  LXD_DIR="${LXD_ONE_DIR}" lxc project set replicator-project replica.cluster=lxd_two
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator create my-replicator cluster=lxd_two --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc replicator create my-replicator cluster=lxd_one --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc project demote-replica replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc project promote-replica replicator-project
+
+  # Setup storage on both clusters.
+  local pool_one pool_two
+  pool_one="lxdtest-$(basename "${LXD_ONE_DIR}")"
+  pool_two="lxdtest-$(basename "${LXD_TWO_DIR}")"
+  LXD_DIR="${LXD_ONE_DIR}" lxc profile device add default root disk path="/" pool="${pool_one}" --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc profile device add default root disk path="/" pool="${pool_two}" --project replicator-project
+
+  LXD_DIR="${LXD_ONE_DIR}" ensure_import_testimage replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch testimage c1 --project replicator-project -d "${SMALL_ROOT_DISK}" -c boot.autostart.priority=10
+  LXD_DIR="${LXD_ONE_DIR}" lxc init testimage c2 --project replicator-project -d "${SMALL_ROOT_DISK}" -c boot.autostart=false
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator run my-replicator --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c1,STOPPED'
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c2,STOPPED'
+
+  sub_test "Verify test failover is rejected from the leader side"
+
+  [ "$(CLIENT_DEBUG="" SHELL_TRACING="" LXD_DIR="${LXD_ONE_DIR}" lxc replicator test-failover my-replicator --project replicator-project 2>&1)" = 'Error: Project "replicator-project" must be in standby mode to test a failover' ]
+
+  sub_test "Test failover: clones are started in an isolated project"
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc replicator test-failover my-replicator --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc project get replicator-project-test-failover volatile.test_failover.source | grep -xF '/1.0/replicators/my-replicator?project=replicator-project'
+
+  # Users can't set or change the key recording which replicator created a project.
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc project set replicator-project-test-failover volatile.test_failover.source=foo || false
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc project create foo -c volatile.test_failover.source=/1.0/replicators/my-replicator?project=replicator-project || false
+
+  # The cleanup only deletes projects created by a test failover of the replicator.
+  LXD_DIR="${LXD_TWO_DIR}" lxc project create replicator-project-other
+  LXD_DIR="${LXD_TWO_DIR}" lxc replicator set my-replicator test_failover.project=replicator-project-other --project replicator-project
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc replicator test-failover my-replicator --cleanup --project replicator-project || false
+  LXD_DIR="${LXD_TWO_DIR}" lxc project show replicator-project-other
+  LXD_DIR="${LXD_TWO_DIR}" lxc replicator unset my-replicator test_failover.project --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc project delete replicator-project-other
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project-test-failover -f csv -c ns | grep -xF 'c1,RUNNING'
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project-test-failover -f csv -c ns | grep -xF 'c2,STOPPED'
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc config get c1 boot.autostart.priority --project replicator-project-test-failover)" = "10" ]
+
+  # The replicas and the leader are left untouched.
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c1,STOPPED'
+  LXD_DIR="${LXD_ONE_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c1,RUNNING'
+
+  # A second test failover requires the previous one to be cleaned up.
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc replicator test-failover my-replicator --project replicator-project || false
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc replicator test-failover my-replicator --cleanup --project replicator-project
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc project show replicator-project-test-failover || false
+
+  sub_test "Failover: LXD_TWO becomes the leader and starts its instances"
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc replicator failover my-replicator --project replicator-project
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/projects/replicator-project | jq -r '.replica_mode')" = "standby" ]
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc query /1.0/projects/replicator-project | jq -r '.replica_mode')" = "leader" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c1,STOPPED'
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c1,RUNNING'
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c2,STOPPED'
+
+  # Replication now flows from LXD_TWO to LXD_ONE.
+  LXD_DIR="${LXD_TWO_DIR}" lxc init --empty c3 --project replicator-project -d "${SMALL_ROOT_DISK}" -c boot.autostart=false
+  LXD_DIR="${LXD_TWO_DIR}" lxc replicator run my-replicator --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c3,STOPPED'
+
+  sub_test "Failback: LXD_ONE becomes the leader again"
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc replicator failback my-replicator --project replicator-project
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/projects/replicator-project | jq -r '.replica_mode')" = "leader" ]
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc query /1.0/projects/replicator-project | jq -r '.replica_mode')" = "standby" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c1,RUNNING'
+  LXD_DIR="${LXD_ONE_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c2,STOPPED'
+  LXD_DIR="${LXD_ONE_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c3,STOPPED'
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c1,STOPPED'
+
+  # Failback is only possible from the leader side.
+  [ "$(CLIENT_DEBUG="" SHELL_TRACING="" LXD_DIR="${LXD_TWO_DIR}" lxc replicator failback my-replicator --project replicator-project 2>&1)" = 'Error: Project "replicator-project" must be in leader mode to fail back' ]
+
+  # Cleanup
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete -f c1 c2 c3 --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc delete -f c1 c2 c3 --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc profile device remove default root --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc profile device remove default root --project replicator-project
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_ONE_DIR}"
+}
+
 test_clustering_replicator_snapshot() {
   # Create two standalone clustered LXD daemons to simulate two separate clusters.
   LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
@@ -6307,6 +6908,104 @@ test_clustering_replicator_snapshot() {
   kill_lxd "${LXD_ONE_DIR}"
 }
 
+test_clustering_replicator_scope() {
+  # Create two standalone clustered LXD daemons to simulate two separate clusters.
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_ONE_DIR}" true
+
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_TWO_DIR}" true
+
+  # Enable clustering on both.
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster enable node1
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster enable node2
+
+  # Create projects on both clusters.
+  LXD_DIR="${LXD_ONE_DIR}" lxc project create replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc project create replicator-project
+
+  # Setup auth groups and cluster links.
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group create replicator-group
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add replicator-group project replicator-project operator
+  LXD_DIR="${LXD_ONE_DIR}" lxc auth group permission add replicator-group project replicator-project can_edit
+  LXD_ONE_TRUST_TOKEN="$(LXD_DIR="${LXD_ONE_DIR}" lxc cluster link create lxd_two --quiet --auth-group replicator-group)"
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group create replicator-group
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add replicator-group project replicator-project operator
+  LXD_DIR="${LXD_TWO_DIR}" lxc auth group permission add replicator-group project replicator-project can_edit
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster link create lxd_one --token "${LXD_ONE_TRUST_TOKEN}" --auth-group replicator-group
+
+  # Configure replica project settings: standby sets replica.cluster, leader creates replicator.
+  LXD_DIR="${LXD_TWO_DIR}" lxc project set replicator-project replica.cluster=lxd_one
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator create my-replicator cluster=lxd_two --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc project demote-replica replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc project promote-replica replicator-project
+
+  # Setup storage on both clusters. Custom volumes are replicated to the pool with the same name.
+  local pool_one pool_two
+  pool_one="lxdtest-$(basename "${LXD_ONE_DIR}")"
+  pool_two="lxdtest-$(basename "${LXD_TWO_DIR}")"
+  LXD_DIR="${LXD_ONE_DIR}" lxc profile device add default root disk path="/" pool="${pool_one}" --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc profile device add default root disk path="/" pool="${pool_two}" --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage create replicator-pool dir --target node1
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage create replicator-pool dir
+  LXD_DIR="${LXD_TWO_DIR}" lxc storage create replicator-pool dir --target node2
+  LXD_DIR="${LXD_TWO_DIR}" lxc storage create replicator-pool dir
+
+  LXD_DIR="${LXD_ONE_DIR}" ensure_import_testimage replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume create replicator-pool vol1 user.dr=true --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume create replicator-pool vol2 --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch testimage c1 --project replicator-project -d "${SMALL_ROOT_DISK}" -c user.dr=true
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage volume attach replicator-pool vol1 c1 /mnt --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty c2 --project replicator-project -d "${SMALL_ROOT_DISK}"
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty web-1 --project replicator-project -d "${SMALL_ROOT_DISK}" -c user.dr=true
+
+  sub_test "Verify invalid filters and limits are rejected"
+
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator filter.labels=tier=gold --project replicator-project || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator filter.names='[' --project replicator-project || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator limits.bandwidth=fast --project replicator-project || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator limits.concurrency=0 --project replicator-project || false
+
+  sub_test "Replicate the labelled instances and volumes without snapshots"
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator filter.labels=user.dr=true snapshots.exclude=true limits.concurrency=1 limits.bandwidth=1Gbit --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator run my-replicator --project replicator-project
+  bulk_op="$(LXD_DIR="${LXD_ONE_DIR}" lxc query -X GET '/1.0/operations?project=replicator-project&recursion=2' | jq --exit-status '[.. | objects | select(.description == "Running replicator")] | max_by(.created_at)')"
+  jq --exit-status '.status == "Success" and ((.children // []) | length) == 3 and (all(.children[]; .status == "Success"))' <<< "${bulk_op}"
+  jq --exit-status '[.children[] | select(.description == "Replicating storage volume")] | length == 1' <<< "${bulk_op}"
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c nsS | grep -xF 'c1,STOPPED,0'
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'web-1,STOPPED'
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc info c2 --project replicator-project || false
+  LXD_DIR="${LXD_TWO_DIR}" lxc storage volume show replicator-pool vol1 --project replicator-project | grep -xF '  user.dr: "true"'
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc storage volume show replicator-pool vol2 --project replicator-project || false
+
+  # No snapshot is taken on the source when snapshots are excluded.
+  LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/instances/c1/snapshots?project=replicator-project" | jq --exit-status 'length == 0'
+
+  sub_test "Replicate by name pattern"
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator unset my-replicator filter.labels --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator set my-replicator filter.names='c*,vol2' --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc replicator run my-replicator --project replicator-project
+  LXD_DIR="${LXD_TWO_DIR}" lxc list --project replicator-project -f csv -c ns | grep -xF 'c2,STOPPED'
+  LXD_DIR="${LXD_TWO_DIR}" lxc storage volume show replicator-pool vol2 --project replicator-project
+
+  # Cleanup
+  for dir in "${LXD_ONE_DIR}" "${LXD_TWO_DIR}"; do
+    LXD_DIR="${dir}" lxc delete -f c1 c2 web-1 --project replicator-project
+    LXD_DIR="${dir}" lxc storage volume delete replicator-pool vol1 --project replicator-project
+    LXD_DIR="${dir}" lxc storage volume delete replicator-pool vol2 --project replicator-project
+    LXD_DIR="${dir}" lxc storage delete replicator-pool
+  done
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc profile device remove default root --project replicator-project
+  LXD_DIR="${LXD_ONE_DIR}" lxc profile device remove default root --project replicator-project
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_ONE_DIR}"
+}
+
 test_clustering_replicator_multi_member() {
   local poolDriver
   poolDriver=$(storage_backend "${LXD_INITIAL_DIR}")
@@ -7027,4 +7726,118 @@ test_clustering_acme() {
   kill_lxd "${LXD_ONE_DIR}"
 
   kill_acme
-}
\ No newline at end of file
+}
+
+test_clustering_maintenance() {
+  echo "Create cluster with 3 nodes"
+  spawn_lxd_and_bootstrap_cluster
+
+  local cert
+  cert="$(cert_to_yaml "${LXD_ONE_DIR}/cluster.crt")"
+
+  spawn_lxd_and_join_cluster "${cert}" 2 1 "${LXD_ONE_DIR}"
+  spawn_lxd_and_join_cluster "${cert}" 3 1 "${LXD_ONE_DIR}"
+
+  # Wait for the cluster members to get their database roles.
+  sleep 5
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty c1 --target node2
+
+  echo "==> Invalid requests"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start foo || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start node2 node2 || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start node2 --action foo || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start node2 --all || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance show || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance pause || false
+
+  echo "==> Maintenance without waiting for members to be ready"
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start node2 node3
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L c1)" = "node2" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/members/node2 | jq --exit-status '.status == "Online"'
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/members/node3 | jq --exit-status '.status == "Online"'
+
+  echo "==> Maintenance waiting for a member to be reported ready"
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start node2 --ready-timeout 600 &
+  local maintenance_pid="$!"
+
+  # The members are evacuated in turn, so only one is evacuated at a time.
+  wait_for_maintenance_status() {
+    local lxd_dir="$1" member="$2" status="$3"
+    local i
+    for i in $(seq 50); do
+      if LXD_DIR="${lxd_dir}" lxc query /1.0/cluster/maintenance 2>/dev/null | jq --exit-status --arg member "${member}" --arg status "${status}" '.members[] | select(.name == $member) | .status == $status' >/dev/null; then
+        return 0
+      fi
+
+      sleep 0.5
+    done
+
+    echo "Cluster member ${member} didn't reach maintenance status ${status}"
+    return 1
+  }
+
+  wait_for_maintenance_status "${LXD_ONE_DIR}" node2 waiting
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/members/node2 | jq --exit-status '.status == "Evacuated"'
+
+  # Another maintenance can't be started and the progress is available from any member.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start node3 || false
+  LXD_DIR="${LXD_THREE_DIR}" lxc cluster maintenance show | grep -xF "status: running"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance ready node3 || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance resume || false
+
+  LXD_DIR="${LXD_THREE_DIR}" lxc cluster maintenance ready node2
+  wait "${maintenance_pid}"
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/members/node2 | jq --exit-status '.status == "Online"'
+
+  echo "==> Pause, resume and abort"
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start node2 node3 --ready-timeout 600 &
+  maintenance_pid="$!"
+  wait_for_maintenance_status "${LXD_ONE_DIR}" node2 waiting
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance pause
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance show | grep -xF "status: paused"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance pause || false
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance ready node2
+  wait_for_maintenance_status "${LXD_ONE_DIR}" node2 restoring
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance resume
+  wait_for_maintenance_status "${LXD_ONE_DIR}" node3 waiting
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance abort
+  ! wait "${maintenance_pid}" || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance show || false
+
+  # Aborting leaves the member evacuated.
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/members/node2 | jq --exit-status '.status == "Online"'
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/cluster/members/node3 | jq --exit-status '.status == "Evacuated"'
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster restore node3 --force
+
+  echo "==> Maintenance of all members hands the coordination over"
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster maintenance start --all | grep -F 'handed over to "node2"'
+  for i in $(seq 50); do
+    LXD_DIR="${LXD_TWO_DIR}" lxc cluster maintenance show >/dev/null 2>&1 || break
+    sleep 0.5
+  done
+
+  for member in node1 node2 node3; do
+    LXD_DIR="${LXD_ONE_DIR}" lxc query "/1.0/cluster/members/${member}" | jq --exit-status '.status == "Online"'
+  done
+
+  # The maintenance of node1 was coordinated by another member.
+  LXD_DIR="${LXD_ONE_DIR}" lxc operation list --format csv | grep -F "Running cluster maintenance" | grep -F "node2"
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete c1
+
+  LXD_DIR="${LXD_THREE_DIR}" lxd shutdown
+  LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+
+  rm -f "${LXD_THREE_DIR}/unix.socket"
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_THREE_DIR}"
+}
@@ -102,6 +102,52 @@ test_oidc() {
   # The user should now be logged in.
   lxc_remote query oidc:/1.0 | jq --exit-status '.auth == "trusted"'
 
+  # Check OIDC additional issuers and claim mappings validation.
+  ! lxc config set oidc.additional_issuers "[{issuer: 'https://idp.example.com'}]" || false # Missing client ID
+  ! lxc config set oidc.additional_issuers "[{issuer: 'http://127.0.0.1:${oidc_port}/', client_id: device}]" || false # Already trusted via oidc.issuer
+  ! lxc config set oidc.additional_issuers "[{issuer: 'http://127.0.0.1:22/', client_id: device}]" || false # Wrong port
+  ! lxc config set oidc.additional_issuers "[{issuer: 'https://idp.example.com', client_id: device, groups_claim: roles}]" || false # Missing group prefix
+  [ -z "$(lxc config get oidc.additional_issuers || echo fail)" ]
+  ! lxc config set oidc.groups.mappings "[{claim: email, regex: '(', groups: [foo]}]" || false # Invalid regex
+  ! lxc config set oidc.groups.mappings "[{claim: email, value: foo}]" || false # Missing groups
+
+  # Map the email domain of the user to an identity provider group, and map that to a LXD group.
+  lxc config set oidc.groups.mappings "[{claim: email, regex: '[^@]+@(example[.]com)', groups: ['domain-\$1']}]"
+  lxc auth group create example-users
+  lxc auth identity-provider-group create domain-example.com
+  lxc auth identity-provider-group group add domain-example.com example-users
+
+  # The identity provider groups are updated on the next login.
+  lxc auth identity delete oidc/test-user@example.com
+  lxc remote remove oidc
+  BROWSER=curl lxc remote add --accept-certificate oidc "${LXD_ADDR}" --auth-type oidc
+  lxc query oidc:/1.0/auth/identities/current | jq --exit-status '.effective_groups == ["example-users"]'
+
+  lxc auth identity-provider-group delete domain-example.com
+  lxc auth group delete example-users
+  lxc config unset oidc.groups.mappings
+
+  # Identities that are provisioned via SCIM are bound to the subject of their first login, which requires a verified
+  # email address.
+  lxc auth identity create scim/idp
+  scim_token="$(lxc auth identity token issue scim/idp --quiet)"
+  curl -s -k -H "Authorization: Bearer ${scim_token}" -H "Content-Type: application/scim+json" -X POST "https://${LXD_ADDR}/scim/v2/Users" --data '{"userName":"scim-user@example.com"}' | jq --exit-status '.userName == "scim-user@example.com"'
+  lxc remote remove oidc
+  set_oidc scim-user scim-user@example.com
+  ! BROWSER=curl lxc remote add --accept-certificate oidc "${LXD_ADDR}" --auth-type oidc || false
+  set_oidc scim-user scim-user@example.com verified
+  BROWSER=curl lxc remote add --accept-certificate oidc "${LXD_ADDR}" --auth-type oidc
+  lxc query oidc:/1.0 | jq --exit-status '.auth_user_name == "scim-user@example.com"'
+
+  # Once bound, the identity cannot be used by another subject with the same email address.
+  lxc remote remove oidc
+  set_oidc other-user scim-user@example.com verified
+  ! BROWSER=curl lxc remote add --accept-certificate oidc "${LXD_ADDR}" --auth-type oidc || false
+  set_oidc test-user test-user@example.com
+  BROWSER=curl lxc remote add --accept-certificate oidc "${LXD_ADDR}" --auth-type oidc
+  lxc auth identity delete oidc/scim-user@example.com
+  lxc auth identity delete scim/idp
+
   # Cleanup OIDC
   lxc auth identity delete oidc/test-user@example.com
   lxc remote remove oidc
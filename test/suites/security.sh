@@ -997,3 +997,44 @@ test_security_authz_events() {
   kill_go_proc "${mon_pid}" || true
   rm -f "${monfile}"
 }
+
+test_security_audit_log() {
+  sub_test "Verify mutating API calls are recorded in the audit log"
+  lxc project create audit-test
+  lxc project set audit-test user.foo=bar
+  lxc project show audit-test > /dev/null
+
+  # Creating an entity targets its collection, so the record has no entity.
+  lxc query /1.0/audit | jq --exit-status 'map(select(.method == "POST" and .url == "/1.0/projects")) | .[-1] | .status_code == 200 and .entity == "" and (.body_sha256 | length == 64)'
+  lxc query "/1.0/audit?entity=/1.0/projects/audit-test" | jq --exit-status 'length == 1'
+  lxc query "/1.0/audit?entity=/1.0/projects/audit-test" | jq --exit-status '.[0].method == "PUT" and .[0].status_code == 200 and .[0].entity == "/1.0/projects/audit-test" and .[0].previous_hash != ""'
+  lxc query "/1.0/audit?since=2000-01-01T00:00:00Z&until=2000-01-02T00:00:00Z" | jq --exit-status 'length == 0'
+  ! lxc query "/1.0/audit?since=yesterday" || false
+  ! lxc query "/1.0/audit?entity=/1.0/instances" || false
+
+  sub_test "Verify denied API calls are recorded in the audit log"
+  ! curl --silent --fail --insecure -X DELETE "https://${LXD_ADDR}/1.0/projects/audit-test" || false
+  lxc query "/1.0/audit?entity=/1.0/projects/audit-test" | jq --exit-status '.[-1].method == "DELETE" and .[-1].status_code >= 400 and .[-1].username == ""'
+
+  sub_test "Verify the audit log"
+  local last_hash
+  last_hash="$(lxc query "/1.0/audit?entity=/1.0/projects/audit-test" | jq --exit-status --raw-output '.[-1].hash')"
+  lxd audit verify --dir "${LXD_DIR}/audit" --last-hash "${last_hash}" | grep -F "Last hash:"
+  ! lxd audit verify --dir "${LXD_DIR}/audit" --last-hash "invalid" || false
+
+  # The key can be passed explicitly, and records are not valid with another key.
+  local audit_key
+  audit_key="$(lxd sql global --format csv "SELECT value FROM secrets WHERE type = 3")"
+  lxd audit verify --dir "${LXD_DIR}/audit" --key "${audit_key}" | grep -F "Last hash:"
+  ! lxd audit verify --dir "${LXD_DIR}/audit" --key "$(head -c 64 /dev/urandom | base64 -w0)" || false
+
+  sub_test "Verify tampering with the audit log is detected"
+  local audit_dir="${TEST_DIR}/audit-copy"
+  cp -r "${LXD_DIR}/audit" "${audit_dir}"
+  chmod -R u+w "${audit_dir}"
+  sed -i 's/audit-test/audit-tset/' "${audit_dir}/audit.log"
+  ! lxd audit verify --dir "${audit_dir}" || false
+  rm -rf "${audit_dir}"
+
+  lxc project delete audit-test
+}
@@ -135,10 +135,43 @@ EOF
   s3cmdrun "${adAccessKey}" "${adSecretKey}" delpolicy "s3://${bucketPrefix}.foo"
   [ "$(curl -sI --insecure -o /dev/null -w "%{http_code}" "${bucketURL}/${lxdTestFile}")" = "403" ]
 
+  # Test bucket policies.
+  lxc storage bucket set "${poolName}" "${bucketPrefix}.foo" policy.anonymous_read=true
+  [ "$(curl -sI --insecure -o /dev/null -w "%{http_code}" "${bucketURL}/${lxdTestFile}")" = "200" ]
+  lxc storage bucket unset "${poolName}" "${bucketPrefix}.foo" policy.anonymous_read
+  [ "$(curl -sI --insecure -o /dev/null -w "%{http_code}" "${bucketURL}/${lxdTestFile}")" = "403" ]
+  ! lxc storage bucket set "${poolName}" "${bucketPrefix}.foo" lifecycle.expiry=logs/:0 || false
+  ! lxc storage bucket set "${poolName}" "${bucketPrefix}.foo" quota.objects=-1 || false
+  lxc storage bucket set "${poolName}" "${bucketPrefix}.foo" lifecycle.expiry=logs/:7,30
+  s3cmdrun "${adAccessKey}" "${adSecretKey}" getlifecycle "s3://${bucketPrefix}.foo" | grep -F "<Prefix>logs/</Prefix>"
+  lxc storage bucket unset "${poolName}" "${bucketPrefix}.foo" lifecycle.expiry
+  ! s3cmdrun "${adAccessKey}" "${adSecretKey}" getlifecycle "s3://${bucketPrefix}.foo" || false
+  lxc storage bucket set "${poolName}" "${bucketPrefix}.foo" versioning=true
+  lxc storage bucket set "${poolName}" "${bucketPrefix}.foo" versioning=false
+  lxc storage bucket set "${poolName}" "${bucketPrefix}.foo" quota.objects=1
+  lxc storage bucket unset "${poolName}" "${bucketPrefix}.foo" quota.objects
+
   # Test deleting a file from a bucket.
   ! s3cmdrun "${roAccessKey}" "${roSecretKey}" del "s3://${bucketPrefix}.foo/${lxdTestFile}" || false
   s3cmdrun "${adAccessKey}" "${adSecretKey}" del "s3://${bucketPrefix}.foo/${lxdTestFile}"
 
+  # Test managing bucket objects through LXD.
+  lxc storage bucket object put "${poolName}" "${bucketPrefix}.foo" "${lxdTestFile}" "dir/${lxdTestFile}"
+  s3cmdrun "${roAccessKey}" "${roSecretKey}" ls "s3://${bucketPrefix}.foo/dir/" | grep -F "${lxdTestFile}"
+  [ "$(lxc storage bucket object list "${poolName}" "${bucketPrefix}.foo" dir/ --format csv -c n)" = "dir/${lxdTestFile}" ]
+  [ "$(lxc storage bucket object list "${poolName}" "${bucketPrefix}.foo" other/ --format csv -c n)" = "" ]
+  lxc storage bucket object get "${poolName}" "${bucketPrefix}.foo" "dir/${lxdTestFile}" "${lxdTestFile}.get"
+  [ "${ORIG_MD5SUM}" = "$(md5sum < "${lxdTestFile}.get")" ]
+  [ "${ORIG_MD5SUM}" = "$(lxc storage bucket object get "${poolName}" "${bucketPrefix}.foo" "dir/${lxdTestFile}" - | md5sum)" ]
+  rm "${lxdTestFile}.get"
+  echo foo | lxc storage bucket object put "${poolName}" "${bucketPrefix}.foo" - stdin.txt
+  [ "$(lxc storage bucket object get "${poolName}" "${bucketPrefix}.foo" stdin.txt -)" = "foo" ]
+  lxc storage bucket object delete "${poolName}" "${bucketPrefix}.foo" stdin.txt
+  lxc storage bucket object delete "${poolName}" "${bucketPrefix}.foo" "dir/${lxdTestFile}"
+  ! lxc storage bucket object delete "${poolName}" "${bucketPrefix}.foo" "dir/${lxdTestFile}" || false
+  ! lxc storage bucket object get "${poolName}" "${bucketPrefix}.foo" "dir/${lxdTestFile}" - || false
+  [ "$(lxc storage bucket object list "${poolName}" "${bucketPrefix}.foo" --format csv)" = "" ]
+
   # Test bucket quota.
   initCreds=$(lxc storage bucket create "${poolName}" "${bucketPrefix}.foo2" size=1MiB)
   initAccessKey=$(echo "${initCreds}" | awk '{ if ($2 == "access" && $3 == "key:") {print $4}}')
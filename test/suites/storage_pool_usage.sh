@@ -0,0 +1,53 @@
+test_storage_pool_usage() {
+  local pool lxd_backend
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+  lxd_backend=$(storage_backend "$LXD_DIR")
+
+  # Validate the thresholds.
+  ! lxc storage set "${pool}" usage.warning=0 || false
+  ! lxc storage set "${pool}" usage.warning=101 || false
+  ! lxc storage set "${pool}" usage.warning=foo || false
+  ! lxc storage set "${pool}" usage.warning=90 usage.critical=80 || false
+  ! lxc storage set "${pool}" usage.critical.enforce=true || false
+  lxc storage set "${pool}" usage.warning=80 usage.critical=90 usage.critical.enforce=true
+  [ "$(lxc storage get "${pool}" usage.critical)" = "90" ]
+
+  # The reserved space is only supported on thin-provisioned drivers.
+  if [ "${lxd_backend}" = "lvm" ] || [ "${lxd_backend}" = "ceph" ]; then
+    ! lxc storage set "${pool}" usage.reserved=foo || false
+    lxc storage set "${pool}" usage.reserved=1PiB
+    ! lxc storage volume create "${pool}" vol1 size=1GiB || false
+    lxc storage unset "${pool}" usage.reserved
+
+    # Thin volumes count with their provisioned size, even though they don't use any space yet.
+    if [ "${lxd_backend}" = "ceph" ] || [ "$(lxc storage get "${pool}" lvm.use_thinpool)" != "false" ]; then
+      local total_mib
+      total_mib="$(lxc query "/1.0/storage-pools/${pool}/resources" | jq '.space.total / 1048576 | floor')"
+      lxc storage volume create "${pool}" vol1 size="$((total_mib / 2))MiB"
+      lxc storage set "${pool}" usage.reserved="$((total_mib / 4))MiB"
+      ! lxc storage volume create "${pool}" vol2 size="$((total_mib / 2))MiB" || false
+      lxc storage unset "${pool}" usage.reserved
+      lxc storage volume delete "${pool}" vol1
+    fi
+  else
+    ! lxc storage set "${pool}" usage.reserved=1GiB || false
+  fi
+
+  # New volumes are rejected above the enforced critical threshold.
+  if [ "${lxd_backend}" = "dir" ]; then
+    lxc storage set "${pool}" usage.warning=1 usage.critical=2
+
+    local used
+    used="$(lxc query "/1.0/storage-pools/${pool}/resources" | jq '.space.used * 100 / .space.total | floor')"
+    if [ "${used}" -ge 2 ]; then
+      ! lxc storage volume create "${pool}" vol1 || false
+      lxc storage set "${pool}" usage.critical.enforce=false
+      lxc storage volume create "${pool}" vol1
+      lxc storage volume delete "${pool}" vol1
+    fi
+  fi
+
+  lxc storage unset "${pool}" usage.critical.enforce
+  lxc storage unset "${pool}" usage.critical
+  lxc storage unset "${pool}" usage.warning
+}
@@ -0,0 +1,44 @@
+test_storage_volume_check() {
+  local pool lxd_backend
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+  lxd_backend=$(storage_backend "$LXD_DIR")
+
+  # Validate the schedule.
+  ! lxc storage set "${pool}" scrub.schedule=foo || false
+  lxc storage set "${pool}" scrub.schedule=@weekly
+  [ "$(lxc storage get "${pool}" scrub.schedule)" = "@weekly" ]
+  lxc storage unset "${pool}" scrub.schedule
+
+  lxc storage volume create "${pool}" vol1
+  lxc storage volume snapshot "${pool}" vol1 snap0
+
+  # Snapshots can't be checked.
+  ! lxc storage volume check "${pool}" vol1/snap0 || false
+
+  # The result of the check is reported in the volume state.
+  [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq -r '.check')" = "null" ]
+  lxc storage volume check "${pool}" vol1
+  if [ "${lxd_backend}" = "lvm" ] || [ "${lxd_backend}" = "ceph" ]; then
+    [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq -r '.check.status')" = "ok" ]
+  else
+    [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq -r '.check.status')" != "failed" ]
+  fi
+
+  lxc storage volume info "${pool}" vol1 | grep -F "Last check: "
+
+  # Volumes in use are skipped.
+  if [ "${lxd_backend}" = "lvm" ]; then
+    ensure_import_testimage
+    lxc launch testimage c1 -s "${pool}"
+    lxc storage volume check "${pool}" container/c1
+    [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/container/c1/state" | jq -r '.check.status')" = "skipped" ]
+    lxc delete -f c1
+  fi
+
+  # Scrub the whole pool.
+  lxc storage scrub "${pool}"
+  [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq -r '.check.status')" != "failed" ]
+  ! lxc warning list --format csv | grep -F "integrity" || false
+
+  lxc storage volume delete "${pool}" vol1
+}
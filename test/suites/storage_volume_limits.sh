@@ -0,0 +1,65 @@
+test_storage_volume_limits() {
+  local pool
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+
+  lxc storage volume create "${pool}" vol1
+
+  # Validate the limits.
+  ! lxc storage volume set "${pool}" vol1 limits.iops=foo || false
+  ! lxc storage volume set "${pool}" vol1 limits.bandwidth=foo || false
+  ! lxc storage volume set "${pool}" vol1 limits.iops.burst=200 || false
+  ! lxc storage volume set "${pool}" vol1 limits.bandwidth.burst=20MB || false
+  lxc storage volume set "${pool}" vol1 limits.iops=100 limits.bandwidth=10MB
+  ! lxc storage volume set "${pool}" vol1 limits.iops.burst=50 || false
+  ! lxc storage volume set "${pool}" vol1 limits.bandwidth.burst=1MB || false
+  lxc storage volume set "${pool}" vol1 limits.iops.burst=200 limits.bandwidth.burst=20MB
+  [ "$(lxc storage volume get "${pool}" vol1 limits.iops)" = "100" ]
+  [ "$(lxc storage volume get "${pool}" vol1 limits.bandwidth.burst)" = "20MB" ]
+
+  # Limits are only valid on custom volumes.
+  lxc init --empty c1 -s "${pool}"
+  ! lxc storage volume set "${pool}" container/c1 limits.iops=100 || false
+
+  # Pool defaults only apply to new custom volumes.
+  lxc storage set "${pool}" volume.limits.iops=500
+  lxc storage volume create "${pool}" vol2
+  [ "$(lxc storage volume get "${pool}" vol2 limits.iops)" = "500" ]
+  [ "$(lxc storage volume get "${pool}" vol1 limits.iops)" = "100" ]
+  lxc init --empty c2 -s "${pool}"
+  [ "$(lxc storage volume get "${pool}" container/c2 limits.iops)" = "" ]
+  lxc storage unset "${pool}" volume.limits.iops
+
+  # The limits follow the volume when attached.
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+  lxc storage volume attach "${pool}" vol1 c2 /mnt
+
+  # For VMs, the volume limits are applied through a throttle group and replace the device limits.
+  if [ "${LXD_VM_TESTS}" = "1" ]; then
+    lxc storage volume create "${pool}" vol3 --type=block size=1MiB limits.iops=100
+    lxc init --vm --empty v1 -s "${pool}" -c limits.memory=128MiB -d "${SMALL_ROOT_DISK}"
+
+    # Filesystem volumes with limits cannot be shared with VMs.
+    lxc storage volume attach "${pool}" vol1 v1 /mnt
+    ! lxc start v1 || false
+    lxc storage volume detach "${pool}" vol1 v1
+
+    # Expose a second QMP socket to inspect the throttling of the disk.
+    local qmp_socket="${LXD_DIR}/logs/v1/test.qmp"
+    lxc config set v1 raw.qemu="-qmp unix:${qmp_socket},server=on,wait=off"
+    lxc storage volume attach "${pool}" vol3 v1
+    lxc config device set v1 vol3 limits.read=10MB
+    lxc start v1
+
+    local block_info
+    block_info="$(printf '{"execute":"qmp_capabilities"}\n{"execute":"query-block"}\n' | socat -t 2 - "UNIX-CONNECT:${qmp_socket}" | tail -n1)"
+    echo "${block_info}" | jq --exit-status '[.return[] | select(.inserted.group // "" | startswith("lxd_volume_"))] | length == 1'
+    echo "${block_info}" | jq --exit-status '.return[] | select(.inserted.group // "" | startswith("lxd_volume_")) | .inserted.iops == 100 and .inserted.bps_rd == 0'
+
+    lxc delete -f v1
+    lxc storage volume delete "${pool}" vol3
+  fi
+
+  lxc delete c1 c2
+  lxc storage volume delete "${pool}" vol1
+  lxc storage volume delete "${pool}" vol2
+}
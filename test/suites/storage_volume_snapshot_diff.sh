@@ -0,0 +1,62 @@
+test_storage_volume_snapshot_diff() {
+  ensure_import_testimage
+
+  local pool lxd_backend
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+  lxd_backend=$(storage_backend "$LXD_DIR")
+
+  lxc storage volume create "${pool}" vol1
+  lxc launch testimage c1 -s "${pool}"
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+
+  echo foo | lxc file push --quiet - c1/mnt/unchanged
+  echo foo | lxc file push --quiet - c1/mnt/modified
+  echo foo | lxc file push --quiet - c1/mnt/deleted
+  lxc storage volume snapshot "${pool}" vol1 snap0
+
+  echo bar | lxc file push --quiet - c1/mnt/modified
+  echo bar | lxc file push --quiet - c1/mnt/added
+  lxc exec c1 -- rm /mnt/deleted
+  lxc storage volume snapshot "${pool}" vol1 snap1
+
+  # Check the reported changes.
+  diff="$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/snapshots/snap1/diff?from=snap0")"
+  echo "${diff}" | jq --exit-status '.from == "snap0" and .to == "snap1" and .content_type == "filesystem"'
+  echo "${diff}" | jq --exit-status '[.changes[] | select(.path == "added")][0].type == "added"'
+  echo "${diff}" | jq --exit-status '[.changes[] | select(.path == "modified")][0].type == "modified"'
+  echo "${diff}" | jq --exit-status '[.changes[] | select(.path == "deleted")][0].type == "deleted"'
+  echo "${diff}" | jq --exit-status '[.changes[] | select(.path == "unchanged")] == []'
+
+  # The export contains the added and modified files.
+  lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/snapshots/snap1/diff/export?from=snap0" > "${TEST_DIR}/vol1.diff"
+  tar -tf "${TEST_DIR}/vol1.diff" | grep -xF added
+  tar -tf "${TEST_DIR}/vol1.diff" | grep -xF modified
+  ! tar -tf "${TEST_DIR}/vol1.diff" | grep -xF unchanged || false
+  rm "${TEST_DIR}/vol1.diff"
+
+  # The older snapshot must be specified and be older.
+  ! lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/snapshots/snap1/diff" || false
+  ! lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/snapshots/snap0/diff?from=snap1" || false
+  ! lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/snapshots/snap1/diff?from=snap1" || false
+  ! lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/snapshots/snap1/diff?from=missing" || false
+
+  # Instance volumes are supported too.
+  lxc snapshot c1 snap0
+  lxc exec c1 -- touch /root/newfile
+  lxc snapshot c1 snap1
+  lxc query "/1.0/storage-pools/${pool}/volumes/container/c1/snapshots/snap1/diff?from=snap0" | jq --exit-status '[.changes[] | select(.path == "rootfs/root/newfile")][0].type == "added"'
+
+  # Block volumes are only supported by drivers tracking their changed blocks.
+  lxc storage volume create "${pool}" vol2 --type=block size=1MiB
+  lxc storage volume snapshot "${pool}" vol2 snap0
+  lxc storage volume snapshot "${pool}" vol2 snap1
+  if [ "${lxd_backend}" = "zfs" ] || [ "${lxd_backend}" = "btrfs" ] || [ "${lxd_backend}" = "ceph" ]; then
+    lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol2/snapshots/snap1/diff?from=snap0" | jq --exit-status '.content_type == "block" and .changes == []'
+  else
+    ! lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol2/snapshots/snap1/diff?from=snap0" || false
+  fi
+
+  lxc delete -f c1
+  lxc storage volume delete "${pool}" vol1
+  lxc storage volume delete "${pool}" vol2
+}
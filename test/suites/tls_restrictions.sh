@@ -593,6 +593,74 @@ test_certificate_edit() {
   lxc project delete blah
 }
 
+test_certificate_renew() {
+  ensure_has_localhost_remote "${LXD_ADDR}"
+
+  sub_test "Validate certificate lifecycle configuration"
+  ! lxc config set core.trust_ca_revocation foo || false
+  lxc config set core.trust_ca_revocation ocsp
+  lxc config unset core.trust_ca_revocation
+  ! lxc config set core.certificate_expiry_warning foo || false
+
+  # Certificates generated by gen_cert_and_key are valid for a day, so they expire within the default warning period.
+  gen_cert_and_key "renew-old"
+  gen_cert_and_key "renew-new"
+  old_fingerprint="$(cert_fingerprint "${LXD_CONF}/renew-old.crt")"
+  new_fingerprint="$(cert_fingerprint "${LXD_CONF}/renew-new.crt")"
+
+  lxc auth group create renew-group
+  lxc auth identity create tls/renew-test "${LXD_CONF}/renew-old.crt" --group renew-group
+
+  sub_test "Check that a warning is raised for the expiring client certificate"
+  # The certificate expiry check runs when the daemon starts.
+  shutdown_lxd "${LXD_DIR}"
+  respawn_lxd "${LXD_DIR}" true
+
+  warning_filter='[.[] | select(.type == "Client certificate expiring" and (.last_message | contains("\"renew-test\"")))]'
+  for _ in $(seq 10); do
+    lxc query "/1.0/warnings?recursion=1" | jq --exit-status "${warning_filter} | length == 1" && break
+    sleep 0.5
+  done
+
+  lxc query "/1.0/warnings?recursion=1" | jq --exit-status "${warning_filter} | .[0].status == \"new\""
+
+  sub_test "Check that renewal requires a trusted and different certificate"
+  [ "$(CERTNAME=renew-new my_curl -X POST -H 'Content-Type: application/json' -d "{\"certificate\": $(cert_to_json "${LXD_CONF}/renew-new.crt")}" "https://${LXD_ADDR}/1.0/auth/identities/current/certificate" | jq --raw-output '.error_code')" = "403" ]
+  [ "$(CERTNAME=renew-old my_curl -X POST -H 'Content-Type: application/json' -d "{\"certificate\": $(cert_to_json "${LXD_CONF}/renew-old.crt")}" "https://${LXD_ADDR}/1.0/auth/identities/current/certificate" | jq --raw-output '.error_code')" = "400" ]
+
+  sub_test "Check that renewal requires a valid nonce signed with the new private key"
+  nonce="$(CERTNAME=renew-old my_curl "https://${LXD_ADDR}/1.0/auth/identities/current/certificate" | jq --exit-status --raw-output '.metadata.nonce')"
+  renew_body() {
+    jq --null-input --arg certificate "$(< "${LXD_CONF}/renew-new.crt")" --arg nonce "${1}" --arg signature "$(printf %s "${1}" | openssl dgst -sha256 -sign "${LXD_CONF}/${2}.key" | base64 -w0)" '{certificate: $certificate, nonce: $nonce, signature: $signature}'
+  }
+
+  [ "$(CERTNAME=renew-old my_curl -X POST -H 'Content-Type: application/json' -d "{\"certificate\": $(cert_to_json "${LXD_CONF}/renew-new.crt")}" "https://${LXD_ADDR}/1.0/auth/identities/current/certificate" | jq --raw-output '.error_code')" = "403" ]
+  [ "$(CERTNAME=renew-old my_curl -X POST -H 'Content-Type: application/json' -d "$(renew_body "${nonce}" renew-old)" "https://${LXD_ADDR}/1.0/auth/identities/current/certificate" | jq --raw-output '.error_code')" = "403" ]
+  [ "$(CERTNAME=renew-old my_curl -X POST -H 'Content-Type: application/json' -d "$(renew_body "${nonce}x" renew-new)" "https://${LXD_ADDR}/1.0/auth/identities/current/certificate" | jq --raw-output '.error_code')" = "403" ]
+
+  # A nonce issued to another identity cannot be used.
+  other_nonce="$(lxc query localhost:/1.0/auth/identities/current/certificate | jq --exit-status --raw-output '.nonce')"
+  [ "$(CERTNAME=renew-old my_curl -X POST -H 'Content-Type: application/json' -d "$(renew_body "${other_nonce}" renew-new)" "https://${LXD_ADDR}/1.0/auth/identities/current/certificate" | jq --raw-output '.error_code')" = "403" ]
+
+  sub_test "Renew the certificate"
+  CERTNAME=renew-old my_curl --fail-with-body -X POST -H 'Content-Type: application/json' -d "$(renew_body "${nonce}" renew-new)" "https://${LXD_ADDR}/1.0/auth/identities/current/certificate"
+
+  # The old certificate is no longer trusted.
+  CERTNAME=renew-old my_curl "https://${LXD_ADDR}/1.0" | jq --exit-status '.metadata.auth == "untrusted"'
+
+  # The identity keeps its name and groups with the new certificate.
+  CERTNAME=renew-new my_curl "https://${LXD_ADDR}/1.0/auth/identities/current" | jq --exit-status ".metadata.name == \"renew-test\" and .metadata.id == \"${new_fingerprint}\" and .metadata.groups == [\"renew-group\"]"
+  ! lxc auth identity show "tls/${old_fingerprint}" || false
+
+  # The expiry warning of the replaced certificate is resolved.
+  lxc query "/1.0/warnings?recursion=1" | jq --exit-status "${warning_filter} | .[0].status == \"resolved\""
+
+  # Clean up.
+  lxc auth identity delete tls/renew-test
+  lxc auth group delete renew-group
+  rm "${LXD_CONF}/renew-old.crt" "${LXD_CONF}/renew-old.key" "${LXD_CONF}/renew-new.crt" "${LXD_CONF}/renew-new.key"
+}
+
 test_tls_version() {
   ensure_has_localhost_remote "${LXD_ADDR}"
 
@@ -0,0 +1,76 @@
+test_trash() {
+  ensure_import_testimage
+
+  local pool uuid
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+
+  # Validate the retention.
+  ! lxc project set default deletion.retention=foo || false
+  lxc project set default deletion.retention=1d
+
+  # Deleted instances are moved to the trash and keep their snapshots.
+  lxc init testimage c1 -s "${pool}" -d "${SMALL_ROOT_DISK}"
+  lxc snapshot c1 snap0
+  lxc delete c1
+  ! lxc info c1 || false
+  [ "$(lxc trash list -f csv -c nt)" = "c1,container" ]
+
+  # Protected instances can't be trashed either.
+  lxc init testimage c2 -s "${pool}" -d "${SMALL_ROOT_DISK}" -c security.protection.delete=true
+  ! lxc delete c2 || false
+  lxc config unset c2 security.protection.delete
+
+  # Restoring fails while the name is taken.
+  uuid="$(lxc trash list -f csv -c u)"
+  lxc rename c2 c1
+  ! lxc trash restore "${uuid}" || false
+  lxc trash restore "${uuid}" --name c3
+  [ "$(lxc trash list -f csv | wc -l)" = "0" ]
+  lxc info c3 | grep -wF snap0
+  lxc start c3
+  lxc stop c3 --force
+
+  # Purge the instance immediately.
+  lxc delete c3
+  uuid="$(lxc trash list -f csv -c u)"
+  ! lxc storage delete "${pool}" || false
+  lxc trash delete "${uuid}"
+  [ "$(lxc trash list -f csv | wc -l)" = "0" ]
+
+  # Deleted custom volumes are moved to the trash.
+  lxc storage volume create "${pool}" vol1 size=32MiB
+  lxc storage volume delete "${pool}" vol1
+  ! lxc storage volume show "${pool}" vol1 || false
+  [ "$(lxc trash list -f csv -c nt)" = "vol1,custom" ]
+  uuid="$(lxc trash list -f csv -c u)"
+  lxc trash restore "${uuid}"
+  lxc storage volume show "${pool}" vol1
+
+  # Projects can't be deleted while their trash isn't empty.
+  lxc project create foo -c features.images=false -c features.storage.volumes=true -c deletion.retention=1d
+  lxc storage volume create "${pool}" vol2 --project foo
+  lxc storage volume delete "${pool}" vol2 --project foo
+  [ "$(lxc trash list --all-projects -f csv -c pn)" = "foo,vol2" ]
+  ! lxc project delete foo || false
+  lxc trash delete "$(lxc trash list --project foo -f csv -c u)" --project foo
+  lxc project delete foo
+
+  # Custom volumes of projects without their own storage volumes are trashed in the default project.
+  lxc project create bar -c features.images=false -c features.storage.volumes=false
+  lxc storage volume create "${pool}" vol3 --project bar
+  lxc storage volume delete "${pool}" vol3 --project bar
+  [ "$(lxc trash list --project bar -f csv -c nt)" = "vol3,custom" ]
+  uuid="$(lxc trash list --project bar -f csv -c u)"
+  lxc query "/1.0/trash/${uuid}?project=bar" | jq --exit-status '.name == "vol3"'
+  lxc trash restore "${uuid}" --project bar
+  lxc storage volume delete "${pool}" vol3 --project bar
+  lxc trash delete "$(lxc trash list --project bar -f csv -c u)" --project bar
+  [ "$(lxc trash list --all-projects -f csv | wc -l)" = "0" ]
+  lxc project delete bar
+
+  # Without retention, deletion is immediate.
+  lxc project unset default deletion.retention
+  lxc storage volume delete "${pool}" vol1
+  lxc delete c1
+  [ "$(lxc trash list -f csv | wc -l)" = "0" ]
+}